@@ -0,0 +1,298 @@
+'use strict';
+
+/**
+ * @fileoverview Go internal state aliasing module.
+ * Finds the methods returning a slice or map field of their receiver
+ * rather than a copy of it (`return c.items`): the caller then shares the
+ * backing array or the map of the value, and can change its state from
+ * outside, a common encapsulation bug.  Each such return is reported with
+ * the field returned and how it aliases it:
+ * - field: the field itself (`return c.items`)
+ * - subslice: a slice of the field (`return c.items[1:]`), sharing its
+ *   backing array
+ * - local: a local variable holding the field (`items := c.items`)
+ * Returns of a copy are told apart and listed as such: `slices.Clone`,
+ * `maps.Clone`, `append([]T(nil), c.items...)`, a local filled with
+ * `copy(out, c.items)`, or by ranging over the field.  Returning an
+ * element (`return c.items[i]`) shares nothing and is not reported.
+ *
+ * Only unexported slice and map fields are checked, of struct types of
+ * the package: exported fields are public state already.  The methods
+ * checked are the exported ones unless asked otherwise.  Test files are
+ * left out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/aliasing
+ */
+
+import {
+  mask_source,
+  classify_type,
+  get_base_type,
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { find_return_statements } from './errors.mjs';
+
+/**
+ * Ways a returned value aliases a field of the receiver.
+ */
+const ALIAS_KINDS = ['field', 'subslice', 'local'];
+
+/**
+ * Ways a returned value copies a field of the receiver.
+ */
+const COPY_KINDS = ['clone', 'append', 'copy', 'loop'];
+
+// ============================================================================
+// FIELDS
+// ============================================================================
+
+/**
+ * Classify a type as a slice or a map, following the named types of the
+ * package (`type Items []Item`).
+ * @param {string} type_text - Type expression
+ * @param {Map<string, Object>} types - Types of the package by name
+ * @returns {string|null} slice, map or null
+ */
+const get_collection_kind = (type_text, types) => {
+  let text = type_text;
+  const seen = new Set();
+  while (text) {
+    const kind = classify_type(text);
+    if (kind === 'slice' || kind === 'map') return kind;
+    if (kind !== 'named' || seen.has(text)) return null;
+    seen.add(text);
+    const named = types.get(get_base_type(text));
+    if (!named || ['alias', 'struct'].includes(named.kind)) return null;
+    text = named.underlying;
+  }
+  return null;
+};
+
+/**
+ * List the unexported slice and map fields of the struct types of a
+ * package.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Map<string, Map<string, Object>>} Fields with type and kind by name, by struct name
+ */
+const find_collection_fields = (pkg) => {
+  const types = new Map(pkg.types.map((t) => [t.name, t]));
+  const structs = new Map();
+  for (const type of pkg.types) {
+    if (type.kind !== 'struct') continue;
+    const fields = new Map();
+    for (const field of type.fields) {
+      if (field.embedded) continue;
+      const kind = get_collection_kind(field.type, types);
+      if (!kind) continue;
+      for (const name of field.names) {
+        if (/^[a-z_]/.test(name)) fields.set(name, { type: field.type, kind });
+      }
+    }
+    if (fields.size > 0) structs.set(type.name, fields);
+  }
+  return structs;
+};
+
+// ============================================================================
+// RETURNED VALUES
+// ============================================================================
+
+/**
+ * Classify a returned expression against the fields of the receiver.
+ * @param {string} value - Returned expression
+ * @param {string} receiver - Receiver name
+ * @param {Map<string, Object>} fields - Collection fields of the receiver type
+ * @param {string} masked - Masked method body
+ * @returns {Object|null} Field, kind and whether it is a copy, or null
+ */
+const classify_returned_value = (value, receiver, fields, masked) => {
+  const field_ref = `${receiver}\\s*\\.\\s*(\\w+)`;
+  const slicing = '\\[[^\\]]*:[^\\]]*\\]';
+  const text = value.trim();
+  const known = (name) => (fields.has(name) ? name : null);
+
+  let match = text.match(new RegExp(`^${field_ref}(${slicing})?$`));
+  if (match && known(match[1])) {
+    const kind = match[2] ? 'subslice' : 'field';
+    return { field: match[1], kind, copy: false };
+  }
+
+  const clone = `^(?:slices|maps)\\.Clone\\(\\s*${field_ref}\\s*\\)$`;
+  match = text.match(new RegExp(clone));
+  if (match && known(match[1])) {
+    return { field: match[1], kind: 'clone', copy: true };
+  }
+
+  // append([]T(nil), c.items...) or append([]T{}, c.items...)
+  const empty = '(?:nil|\\[\\][^,]*?(?:\\{\\s*\\}|\\(\\s*nil\\s*\\)))';
+  match = text.match(
+    new RegExp(
+      `^append\\(\\s*${empty}\\s*,\\s*${field_ref}(?:${slicing})?\\s*\\.\\.\\.\\s*\\)$`
+    )
+  );
+  if (match && known(match[1])) {
+    return { field: match[1], kind: 'append', copy: true };
+  }
+
+  if (!/^[A-Za-z_]\w*$/.test(text) || text === receiver) return null;
+  const local = text;
+
+  // A local holding the field, or filled with a copy of it
+  match = masked.match(
+    new RegExp(
+      `(?:^|[\\n;{])\\s*(?:var\\s+)?${local}\\s*:?=\\s*${field_ref}(${slicing})?\\s*(?=[\\n;}])`
+    )
+  );
+  if (match && known(match[1])) {
+    return { field: match[1], kind: 'local', copy: false };
+  }
+  match = masked.match(
+    new RegExp(`\\bcopy\\(\\s*${local}\\s*,\\s*${field_ref}`)
+  );
+  if (match && known(match[1])) {
+    return { field: match[1], kind: 'copy', copy: true };
+  }
+  match = masked.match(new RegExp(`\\brange\\s+${field_ref}\\s*\\{`));
+  const filled = new RegExp(
+    `\\b${local}\\s*(?:\\[[^\\]]*\\]\\s*=|=\\s*append\\(\\s*${local}\\b)`
+  );
+  if (match && known(match[1]) && filled.test(masked)) {
+    return { field: match[1], kind: 'loop', copy: true };
+  }
+  return null;
+};
+
+// ============================================================================
+// ALIASING RETURNS
+// ============================================================================
+
+/**
+ * Find the methods of a set of packages returning a slice or map field of
+ * their receiver, or a copy of it (see the module doc).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.include_unexported=false] - Also check unexported methods
+ * @returns {Object} Aliasing returns and copies by file and line
+ */
+const find_state_aliasing = (packages, options = {}) => {
+  const aliases = [];
+  const copies = [];
+
+  for (const pkg of packages) {
+    const structs = find_collection_fields(pkg);
+    for (const fn of pkg.functions) {
+      if (!fn.receiver || !fn.receiver.name || !fn.body) continue;
+      if (fn.filename.endsWith('_test.go')) continue;
+      if (!fn.exported && !options.include_unexported) continue;
+      const fields = structs.get(fn.receiver.type);
+      if (!fields) continue;
+
+      const masked = mask_source(fn.body);
+      for (const statement of find_return_statements(fn.body, fn.body_line)) {
+        statement.values.forEach((value, position) => {
+          const found = classify_returned_value(
+            value,
+            fn.receiver.name,
+            fields,
+            masked
+          );
+          if (!found) return;
+          const field = fields.get(found.field);
+          const entry = {
+            type: fn.receiver.type,
+            method: fn.name,
+            receiver: fn.receiver.name,
+            field: found.field,
+            field_type: field.type,
+            collection: field.kind,
+            kind: found.kind,
+            position,
+            expression: value.trim(),
+            package: pkg.name,
+            directory: pkg.directory,
+            filename: fn.filename,
+            line: statement.line
+          };
+          if (found.copy) {
+            copies.push(entry);
+            return;
+          }
+          const how = found.kind === 'field' ? '' : ` (${found.kind})`;
+          aliases.push({
+            ...entry,
+            message: `${fn.receiver.type}.${fn.name} returns the internal ${field.kind} ${fn.receiver.name}.${found.field}${how}; callers can modify it, return a copy instead`
+          });
+        });
+      }
+    }
+  }
+
+  function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  }
+  return {
+    aliases: aliases.sort(sort_by_location),
+    copies: copies.sort(sort_by_location)
+  };
+};
+
+/**
+ * Summarize an aliasing report.
+ * @param {Object} report - Report (from find_state_aliasing)
+ * @returns {Object} Numbers of aliasing returns by kind, copies by kind, and types concerned
+ */
+const summarize_state_aliasing = (report) => {
+  const count = (entries, kinds) => {
+    const counts = Object.fromEntries(kinds.map((k) => [k, 0]));
+    for (const entry of entries) counts[entry.kind]++;
+    return counts;
+  };
+  return {
+    aliases: report.aliases.length,
+    copies: report.copies.length,
+    types: new Set(report.aliases.map((a) => `${a.directory}.${a.type}`)).size,
+    by_kind: count(report.aliases, ALIAS_KINDS),
+    copies_by_kind: count(report.copies, COPY_KINDS)
+  };
+};
+
+/**
+ * Report the aliasing returns of a set of Go sources, such as the files
+ * of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_state_aliasing)
+ * @returns {Object} Aliasing returns and copies with a summary
+ */
+const find_source_state_aliasing = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const report = find_state_aliasing(group_go_packages(files), options);
+  return { ...report, summary: summarize_state_aliasing(report) };
+};
+
+/**
+ * Report the aliasing returns of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_state_aliasing)
+ * @returns {Promise<Object>} Aliasing returns and copies with a summary
+ */
+const analyze_project_state_aliasing = async (project_id, options = {}) => {
+  const report = find_state_aliasing(
+    await load_go_packages(project_id),
+    options
+  );
+  return { ...report, summary: summarize_state_aliasing(report) };
+};
+
+export {
+  analyze_project_state_aliasing,
+  find_source_state_aliasing,
+  find_state_aliasing,
+  summarize_state_aliasing,
+  ALIAS_KINDS,
+  COPY_KINDS
+};
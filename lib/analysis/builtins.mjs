@@ -0,0 +1,315 @@
+'use strict';
+
+/**
+ * @fileoverview Go builtin shadowing module.
+ * Finds the declarations named after a predeclared identifier of Go
+ * (`len`, `cap`, `new`, `make`, `error`, `string`, `nil`), which hide the
+ * builtin wherever they are in scope: a parameter named `len` makes every
+ * `len(s)` of the function a compile error, or worse, a call of something
+ * else.  The shadowing declaration is reported with the builtin it hides.
+ *
+ * Shadowing is problematic when:
+ * - package: a package level function, type, constant or variable hides
+ *   the builtin in every file of the package
+ * - used: the scope of the declaration also uses the builtin, by a call
+ *   (`len(s)`), a conversion (`string(b)`) or in a type (`[]string`,
+ *   `map[string]int`), which then refers to the declaration instead
+ * - constant: `true`, `false`, `nil` and `iota` are shadowed, which
+ *   changes the meaning of any later comparison
+ * Other shadowing is harmless: a local `len` or `max` never calling the
+ * builtin.  Parameters, named results and the receiver are in scope in
+ * the whole body; a local variable from the end of its statement, so
+ * that `len := len(s)` reads the builtin.  Block scopes are not tracked:
+ * a local is in scope until the end of the function.  Methods and struct
+ * fields are selected (`s.len`) and never shadow anything.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/builtins
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  parse_parameters,
+  parse_go_file,
+  group_go_packages,
+  GO_BUILTINS,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Reasons a shadowing is problematic, or harmless (unused).
+ */
+const SHADOW_REASONS = ['package', 'used', 'constant', 'unused'];
+
+/**
+ * Kind of each builtin identifier.
+ */
+const BUILTIN_KINDS = new Map([
+  ...GO_BUILTINS.types.map((name) => [name, 'type']),
+  ...GO_BUILTINS.constants.map((name) => [name, 'constant']),
+  ...GO_BUILTINS.zero.map((name) => [name, 'constant']),
+  ...GO_BUILTINS.functions.map((name) => [name, 'function'])
+]);
+
+// ============================================================================
+// DECLARATIONS
+// ============================================================================
+
+/**
+ * Find the variables declared in a masked function body and the
+ * parameters of its function literals, with the offset from which they
+ * are in scope.
+ * @param {string} masked - Masked function body
+ * @returns {Object[]} Declarations with name, kind (local or parameter), type (parameters only), offset and scope start
+ */
+const find_body_declarations = (masked) => {
+  const declarations = [];
+  const statement_end = (index) => {
+    const end = masked.substring(index).search(/[\n;]/);
+    return end === -1 ? masked.length : index + end;
+  };
+  const add = (names, index, end, kind) => {
+    for (const name of names.split(',').map((n) => n.trim())) {
+      if (!/^[A-Za-z_]\w*$/.test(name)) continue;
+      declarations.push({
+        name,
+        kind,
+        type: null,
+        offset: index,
+        scope_start: statement_end(end)
+      });
+    }
+  };
+
+  const define =
+    /(?:^|[;{(\n]|\bif|\bswitch|\bfor)\s*(\w+(?:\s*,\s*\w+)*)\s*:=/g;
+  const declare = /\bvar\s+(\w+(?:\s*,\s*\w+)*)/g;
+  for (const match of [
+    ...masked.matchAll(define),
+    ...masked.matchAll(declare)
+  ]) {
+    const offset = match.index + match[0].indexOf(match[1]);
+    add(match[1], offset, match.index + match[0].length, 'local');
+  }
+
+  for (const match of masked.matchAll(/\bfunc\s*\(/g)) {
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    if (close === -1) continue;
+    for (const param of parse_parameters(masked.substring(open + 1, close))) {
+      if (!param.name) continue;
+      declarations.push({
+        name: param.name,
+        kind: 'parameter',
+        type: param.type,
+        offset: match.index,
+        scope_start: close + 1
+      });
+    }
+  }
+
+  return declarations;
+};
+
+/**
+ * Check whether code uses a builtin as such: calls a builtin function,
+ * converts to or refers to a builtin type.
+ * @param {string} masked - Masked code in the scope of the shadowing
+ * @param {string} name - Builtin name
+ * @param {boolean} [callable=false] - Whether the shadowing declaration is a function value, whose calls are its own
+ * @returns {boolean} True if the builtin is used
+ */
+const uses_builtin = (masked, name, callable = false) => {
+  const kind = BUILTIN_KINDS.get(name);
+  const call = new RegExp(`(?<![\\w.])${name}\\s*\\(`);
+  if (kind === 'function') return !callable && call.test(masked);
+  if (kind !== 'type') return false;
+  if (!callable && call.test(masked)) return true;
+  return new RegExp(
+    `(?:\\[|\\]|\\*|\\.\\(|\\bchan\\s+|\\bvar\\s+\\w+(?:\\s*,\\s*\\w+)*\\s+)\\s*${name}(?!\\w)`
+  ).test(masked);
+};
+
+// ============================================================================
+// BUILTIN SHADOWING
+// ============================================================================
+
+/**
+ * Build the message describing a shadowing declaration.
+ * @param {Object} shadow - Shadowing (without message)
+ * @returns {string} Message
+ */
+const get_shadow_message = (shadow) => {
+  const where = shadow.function ? ` in ${shadow.function}` : '';
+  const hides = `${shadow.declaration} ${shadow.name}${where} shadows the builtin ${shadow.builtin_kind} ${shadow.name}`;
+  if (shadow.reason === 'package') return `${hides} in the whole package`;
+  if (shadow.reason === 'used') {
+    return `${hides}, which is used in its scope and now refers to the ${shadow.declaration}`;
+  }
+  if (shadow.reason === 'constant') {
+    return `${hides}, changing the meaning of later uses`;
+  }
+  return `${hides}; harmless while the builtin is not needed there`;
+};
+
+/**
+ * Find the declarations of a set of packages shadowing a builtin
+ * identifier, each with whether it is problematic and why.  Test files
+ * are included: shadowing in tests is as confusing.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.problematic_only=false] - Only list problematic shadowing
+ * @returns {Object[]} Shadowing declarations by file and line
+ */
+const find_builtin_shadowing = (packages, options = {}) => {
+  const shadows = [];
+  const add = (pkg, fields) => {
+    const builtin_kind = BUILTIN_KINDS.get(fields.name);
+    const reason =
+      builtin_kind === 'constant' && fields.reason === 'unused'
+        ? 'constant'
+        : fields.reason;
+    const shadow = {
+      name: fields.name,
+      builtin_kind,
+      declaration: fields.declaration,
+      function: fields.function || null,
+      package: pkg.name,
+      directory: pkg.directory,
+      filename: fields.filename,
+      line: fields.line,
+      problematic: reason !== 'unused',
+      reason
+    };
+    if (options.problematic_only && !shadow.problematic) return;
+    shadows.push({ ...shadow, message: get_shadow_message(shadow) });
+  };
+
+  for (const pkg of packages) {
+    // Package level declarations
+    const top_level = [
+      ...pkg.functions
+        .filter((fn) => !fn.receiver)
+        .map((fn) => ['function', fn.name, fn.filename, fn.line]),
+      ...pkg.types.map((t) => ['type', t.name, t.filename, t.line]),
+      ...pkg.consts.flatMap((c) =>
+        c.names.map((n) => ['const', n, c.filename, c.line])
+      ),
+      ...pkg.vars.flatMap((v) =>
+        v.names.map((n) => ['var', n, v.filename, v.line])
+      )
+    ];
+    for (const [declaration, name, filename, line] of top_level) {
+      if (!BUILTIN_KINDS.has(name)) continue;
+      add(pkg, { name, declaration, filename, line, reason: 'package' });
+    }
+
+    // Receivers, parameters, results and locals of functions
+    for (const fn of pkg.functions) {
+      const symbol = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      const masked = mask_source(fn.body || '');
+      const line_index = build_line_index(fn.body || '');
+      const signature = [
+        ...(fn.receiver ? [{ ...fn.receiver, kind: 'receiver' }] : []),
+        ...fn.params.map((p) => ({ ...p, kind: 'parameter' })),
+        ...fn.results.map((r) => ({ ...r, kind: 'result' }))
+      ];
+
+      for (const entry of signature) {
+        if (!entry.name || !BUILTIN_KINDS.has(entry.name)) continue;
+        const callable = /^func\b/.test(entry.type || '');
+        add(pkg, {
+          name: entry.name,
+          declaration: entry.kind,
+          function: symbol,
+          filename: fn.filename,
+          line: fn.line,
+          reason: uses_builtin(masked, entry.name, callable) ? 'used' : 'unused'
+        });
+      }
+
+      if (!fn.body) continue;
+      for (const local of find_body_declarations(masked)) {
+        if (!BUILTIN_KINDS.has(local.name)) continue;
+        const callable = /^func\b/.test(local.type || '');
+        const scope = masked.substring(local.scope_start);
+        add(pkg, {
+          name: local.name,
+          declaration: local.kind === 'parameter' ? 'parameter' : 'variable',
+          function: symbol,
+          filename: fn.filename,
+          line: fn.body_line + line_at(line_index, local.offset) - 1,
+          reason: uses_builtin(scope, local.name, callable) ? 'used' : 'unused'
+        });
+      }
+    }
+  }
+
+  return shadows.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Summarize the builtin shadowing of a code base.
+ * @param {Object[]} shadows - Shadowing declarations (from find_builtin_shadowing)
+ * @returns {Object} Numbers of declarations, problematic and harmless ones, by reason and by builtin
+ */
+const summarize_builtin_shadowing = (shadows) => {
+  const by_reason = Object.fromEntries(SHADOW_REASONS.map((r) => [r, 0]));
+  const by_builtin = {};
+  for (const shadow of shadows) {
+    by_reason[shadow.reason]++;
+    by_builtin[shadow.name] = (by_builtin[shadow.name] || 0) + 1;
+  }
+  const problematic = shadows.filter((s) => s.problematic).length;
+  return {
+    total: shadows.length,
+    problematic,
+    harmless: shadows.length - problematic,
+    by_reason,
+    by_builtin
+  };
+};
+
+/**
+ * Report the builtin shadowing of a set of Go sources, such as the files
+ * of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_builtin_shadowing)
+ * @returns {Object} Shadowing declarations with a summary
+ */
+const find_source_builtin_shadowing = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const shadows = find_builtin_shadowing(group_go_packages(files), options);
+  return { shadows, summary: summarize_builtin_shadowing(shadows) };
+};
+
+/**
+ * Report the builtin shadowing of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_builtin_shadowing)
+ * @returns {Promise<Object>} Shadowing declarations with a summary
+ */
+const analyze_project_builtin_shadowing = async (project_id, options = {}) => {
+  const shadows = find_builtin_shadowing(
+    await load_go_packages(project_id),
+    options
+  );
+  return { shadows, summary: summarize_builtin_shadowing(shadows) };
+};
+
+export {
+  analyze_project_builtin_shadowing,
+  find_source_builtin_shadowing,
+  find_builtin_shadowing,
+  summarize_builtin_shadowing,
+  uses_builtin,
+  BUILTIN_KINDS,
+  SHADOW_REASONS
+};
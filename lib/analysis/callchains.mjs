@@ -0,0 +1,215 @@
+'use strict';
+
+/**
+ * @fileoverview Go call chain module.
+ * Finds the longest call chain from an entry point: the deepest path of
+ * calls from `main` (or a given root) through the call graph, in order.
+ * Deep chains make code hard to follow and can indicate over-layering,
+ * each layer only forwarding to the next.
+ *
+ * Recursion makes the longest path unbounded; calls back to a function
+ * already on the path are not followed but reported as cycles, and the
+ * functions making them are marked as recursive.  Chains are bounded by a
+ * maximum depth, and marked as truncated when they reach it.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/callchains
+ */
+
+import { load_go_packages } from './golang.mjs';
+import { find_call_edges, get_node_id } from './graph.mjs';
+
+/**
+ * Default maximum number of calls in a chain.
+ */
+const DEFAULT_CHAIN_DEPTH = 50;
+
+// ============================================================================
+// CALL GRAPH
+// ============================================================================
+
+/**
+ * Build the call graph of a set of packages: the functions and methods
+ * with the distinct functions they call, each with the line of its first
+ * call.  Functions of test files are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Map<string, Object>} Functions with their calls by node ID
+ */
+const build_call_graph = (packages) => {
+  const functions = new Map();
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.filename.endsWith('_test.go')) continue;
+      const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      const calls = new Map();
+      for (const edge of find_call_edges(fn, pkg, packages)) {
+        if (!calls.has(edge.target)) calls.set(edge.target, edge.line);
+      }
+      functions.set(get_node_id(pkg.directory, name), {
+        id: get_node_id(pkg.directory, name),
+        name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        calls
+      });
+    }
+  }
+
+  return functions;
+};
+
+/**
+ * Find the functions a root names: a node ID (`cmd/app:main`), an
+ * unqualified or package qualified name (`Serve`, `server.Serve`) or a
+ * method (`Server.Start`).  Without a root, the main functions of main
+ * packages are the roots.
+ * @param {Map<string, Object>} functions - Call graph (from build_call_graph)
+ * @param {string} [root] - Root function
+ * @returns {Object[]} Root functions
+ * @throws {Error} If no function matches the root, or several do
+ */
+const resolve_chain_roots = (functions, root) => {
+  const all = [...functions.values()];
+  if (!root) {
+    const mains = all.filter((f) => f.package === 'main' && f.name === 'main');
+    if (mains.length === 0) {
+      throw new Error('No main function found; give the root function');
+    }
+    return mains;
+  }
+
+  const matches = all.filter(
+    (f) =>
+      f.id === root || f.name === root || `${f.package}.${f.name}` === root
+  );
+  if (matches.length === 0) throw new Error(`Function '${root}' not found`);
+  if (matches.length > 1) {
+    throw new Error(
+      `Function '${root}' is ambiguous: ${matches.map((f) => f.id).join(', ')}`
+    );
+  }
+  return matches;
+};
+
+// ============================================================================
+// LONGEST CHAINS
+// ============================================================================
+
+/**
+ * Find the longest call chain from a root function.  The search is depth
+ * first with the longest chain of each function memoized, so each
+ * function and call is visited once; calls to a function on the current
+ * path are cycles and are not followed.
+ * @param {Map<string, Object>} functions - Call graph (from build_call_graph)
+ * @param {string} root - Node ID of the root function
+ * @param {Object} [options] - Options
+ * @param {number} [options.max_depth=50] - Maximum number of calls in the chain
+ * @returns {Object} Chain of functions, its length, cycles and whether it was truncated
+ */
+const find_longest_chain = (functions, root, options = {}) => {
+  const max_depth = options.max_depth || DEFAULT_CHAIN_DEPTH;
+  const longest = new Map();
+  const on_path = new Set();
+  const cycles = [];
+
+  const visit = (id) => {
+    if (longest.has(id)) return longest.get(id);
+    on_path.add(id);
+    let best = { length: 0, next: null, line: null };
+
+    for (const [target, line] of functions.get(id).calls) {
+      if (on_path.has(target)) {
+        cycles.push({ from: id, to: target, line });
+        continue;
+      }
+      const length = visit(target).length + 1;
+      if (length > best.length) best = { length, next: target, line };
+    }
+
+    on_path.delete(id);
+    longest.set(id, best);
+    return best;
+  };
+  visit(root);
+
+  const recursive = new Set(cycles.map((c) => c.from));
+  const chain = [];
+  let call_line = null;
+  for (let id = root; id !== null && chain.length <= max_depth; ) {
+    const fn = functions.get(id);
+    const step = longest.get(id);
+    chain.push({
+      id,
+      name: fn.name,
+      package: fn.package,
+      directory: fn.directory,
+      filename: fn.filename,
+      line: fn.line,
+      call_line,
+      recursive: recursive.has(id)
+    });
+    call_line = step.line;
+    id = step.next;
+  }
+
+  return {
+    root,
+    chain,
+    length: chain.length - 1,
+    truncated: longest.get(root).length > max_depth,
+    cycles: cycles.sort(function sort_by_source(a, b) {
+      return a.from.localeCompare(b.from) || a.to.localeCompare(b.to);
+    })
+  };
+};
+
+/**
+ * Find the longest call chains of a set of packages, one per root.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options (see find_longest_chain)
+ * @param {string} [options.root] - Root function (default: the main functions)
+ * @returns {Object[]} Chains, longest first
+ */
+const find_longest_call_chains = (packages, options = {}) => {
+  const functions = build_call_graph(packages);
+  return resolve_chain_roots(functions, options.root)
+    .map((fn) => find_longest_chain(functions, fn.id, options))
+    .sort(function sort_by_length(a, b) {
+      return b.length - a.length || a.root.localeCompare(b.root);
+    });
+};
+
+/**
+ * Find the longest call chain from the entry points of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_longest_call_chains)
+ * @returns {Promise<Object>} Chains and a summary
+ */
+const analyze_project_call_chains = async (project_id, options = {}) => {
+  const chains = find_longest_call_chains(
+    await load_go_packages(project_id),
+    options
+  );
+
+  return {
+    chains,
+    summary: {
+      roots: chains.length,
+      longest: chains.length > 0 ? chains[0].length : 0,
+      longest_root: chains.length > 0 ? chains[0].root : null,
+      truncated: chains.filter((c) => c.truncated).length,
+      cycles: chains.reduce((sum, c) => sum + c.cycles.length, 0),
+      max_depth: options.max_depth || DEFAULT_CHAIN_DEPTH
+    }
+  };
+};
+
+export {
+  analyze_project_call_chains,
+  find_longest_call_chains,
+  find_longest_chain,
+  build_call_graph,
+  DEFAULT_CHAIN_DEPTH
+};
@@ -0,0 +1,340 @@
+'use strict';
+
+/**
+ * @fileoverview Go API catalog module.
+ * Builds a single JSON document listing the public API of every package
+ * of a code base: types with their fields and methods, functions,
+ * constants and variables, each with its signature, doc comment and
+ * position.  This is the machine readable "everything public in this
+ * repository" artifact for documentation portals and compatibility
+ * tooling.
+ *
+ * Entries have the same ids as search index documents
+ * (`<directory>:<qualifiedName>`) and are cross-linked by them: `links`
+ * lists the catalog types an entry refers to in its declaration, and
+ * types list the entries referring to them in `referenced_by`.
+ *
+ * Fields keep the grouping of their struct: fields separated by blank
+ * lines share a `group` number and the comment heading the group, if
+ * any, as `group_header`, so that documentation can render the sections
+ * of large structs.
+ *
+ * The catalog is deterministic: packages are sorted by directory and
+ * entries by qualified name, so that two catalogs of the same code only
+ * differ by their generation timestamp.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/catalog
+ */
+
+import {
+  parse_go_file,
+  group_go_packages,
+  get_comment_text,
+  load_go_sources
+} from './golang.mjs';
+import { get_imported_packages } from './graph.mjs';
+import { list_search_documents } from './searchindex.mjs';
+
+/**
+ * Version of the catalog document schema, incremented when a field is
+ * removed or changes meaning.
+ */
+const CATALOG_SCHEMA_VERSION = 1;
+
+/**
+ * Directories the go tool does not build as part of the module.
+ */
+const SKIPPED_DIRECTORY = /(^|\/)(vendor|testdata)\//;
+
+/**
+ * A type name, optionally qualified by a package name (`geo.Point`).
+ */
+const TYPE_NAME_PATTERN = /(?<![\w.])([A-Za-z_]\w*)(?:\s*\.\s*([A-Za-z_]\w*))?/g;
+
+// ============================================================================
+// LINKS
+// ============================================================================
+
+/**
+ * Get the type expressions a declaration refers to: parameter, result and
+ * receiver types of functions and methods, field types, embedded and
+ * underlying types of type declarations, and declared types of constants
+ * and variables.
+ * @param {Object} document - Search index document
+ * @param {Object} declaration - Declaration the document was built from
+ * @returns {string[]} Type expressions
+ */
+const get_referenced_types = (document, declaration) => {
+  const signature_types = (fn) =>
+    [...fn.params, ...fn.results].map((p) => p.type);
+
+  if (document.kind === 'function') return signature_types(declaration);
+  if (document.kind === 'method') {
+    if (declaration.receiver) {
+      return [declaration.receiver.type, ...signature_types(declaration)];
+    }
+    const method = declaration.methods.find((m) => m.name === document.name);
+    return method ? signature_types(method) : [];
+  }
+  if (document.kind === 'field') {
+    const field = declaration.fields.find((f) =>
+      f.names.includes(document.name)
+    );
+    return field ? [field.type] : [];
+  }
+  if (document.kind === 'struct' || document.kind === 'interface') {
+    return [
+      ...declaration.fields.filter((f) => f.embedded).map((f) => f.type),
+      ...declaration.embeds.map((e) => e.type)
+    ];
+  }
+  if (document.kind === 'type') return [declaration.underlying];
+  return declaration.type ? [declaration.type] : [];
+};
+
+/**
+ * Resolve the type names of type expressions to the ids of the types they
+ * name: types of the package, and types of the project packages imported
+ * by the file of the declaration.  Other names are left out.
+ * @param {string[]} types - Type expressions
+ * @param {Object} pkg - Package of the declaration
+ * @param {string} filename - File of the declaration
+ * @param {Object[]} packages - All project packages
+ * @returns {string[]} Ids of the referenced types
+ */
+const resolve_type_links = (types, pkg, filename, packages) => {
+  const imported = get_imported_packages(pkg, filename, packages);
+  const has_type = (p, name) => p.types.some((t) => t.name === name);
+  const links = new Set();
+
+  for (const type of types) {
+    let match;
+    TYPE_NAME_PATTERN.lastIndex = 0;
+    while ((match = TYPE_NAME_PATTERN.exec(type || '')) !== null) {
+      const target = match[2] ? imported.get(match[1]) : pkg;
+      const name = match[2] || match[1];
+      if (target && has_type(target, name)) {
+        links.add(`${target.directory}:${target.name}.${name}`);
+      }
+    }
+  }
+
+  return [...links];
+};
+
+/**
+ * Get the group of a field document and the text of the comment heading
+ * it (see parse_struct_fields).
+ * @param {Object} document - Search index document
+ * @param {Object} declaration - Declaration the document was built from
+ * @returns {Object} Group and group header for fields, nothing for other kinds
+ */
+const get_field_group = (document, declaration) => {
+  if (document.kind !== 'field') return {};
+  const field = declaration.fields.find((f) =>
+    f.names.includes(document.name)
+  );
+  if (!field) return {};
+  return {
+    group: field.group,
+    group_header: get_comment_text(field.group_header) || null
+  };
+};
+
+// ============================================================================
+// CATALOG
+// ============================================================================
+
+/**
+ * Compare entries by qualified name.
+ * @param {Object} a - Entry
+ * @param {Object} b - Entry
+ * @returns {number} Sort order
+ */
+const sort_by_qualified_name = (a, b) => {
+  if (a.qualifiedName === b.qualifiedName) return 0;
+  return a.qualifiedName < b.qualifiedName ? -1 : 1;
+};
+
+/**
+ * Build a catalog entry from a search index document.  Package and
+ * directory are left to the enclosing package, and links are limited to
+ * the types of the catalog.
+ * @param {Object} document - Search index document, with links
+ * @param {Set<string>} type_ids - Ids of the catalog types
+ * @returns {Object} Entry
+ */
+const to_catalog_entry = (document, type_ids) => {
+  return {
+    id: document.id,
+    qualifiedName: document.qualifiedName,
+    name: document.name,
+    kind: document.kind,
+    signature: document.signature,
+    doc: document.doc,
+    file: document.file,
+    line: document.line,
+    fingerprint: document.fingerprint,
+    links: document.links
+      .filter((id) => id !== document.id && type_ids.has(id))
+      .sort(),
+    ...(document.kind === 'field'
+      ? { group: document.group, group_header: document.group_header }
+      : {})
+  };
+};
+
+/**
+ * Build the API catalog of a set of packages: the exported declarations
+ * of every package, cross-linked by id.  Methods of unexported types and
+ * unexported fields are not part of the API and are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {Map<string, string[]>} [options.lines] - Source lines by filename, for fingerprints
+ * @param {string} [options.module] - Module path, to give packages their import path
+ * @param {string} [options.generated_at] - Generation timestamp (default: now)
+ * @returns {Object} Catalog with schema version, timestamp, summary and packages
+ */
+const build_api_catalog = (packages, options = {}) => {
+  const documents = list_search_documents(packages, {
+    lines: options.lines,
+    exported_only: true,
+    fields: function add_links(document, { declaration, package: pkg }) {
+      return {
+        links: resolve_type_links(
+          get_referenced_types(document, declaration),
+          pkg,
+          declaration.filename,
+          packages
+        ),
+        ...get_field_group(document, declaration)
+      };
+    }
+  });
+
+  const type_kinds = new Set(['struct', 'interface', 'type']);
+  const type_ids = new Set(
+    documents.filter((d) => type_kinds.has(d.kind)).map((d) => d.id)
+  );
+  const entries = new Map(
+    documents.map((d) => [d.id, to_catalog_entry(d, type_ids)])
+  );
+
+  // Types list the entries referring to them
+  const referenced_by = new Map([...type_ids].map((id) => [id, []]));
+  for (const entry of entries.values()) {
+    for (const id of entry.links) referenced_by.get(id).push(entry.id);
+  }
+
+  const catalog_packages = [...packages]
+    .filter((pkg) => pkg.name)
+    .sort(function sort_by_directory(a, b) {
+      if (a.directory !== b.directory) {
+        return a.directory < b.directory ? -1 : 1;
+      }
+      return a.name < b.name ? -1 : a.name > b.name ? 1 : 0;
+    })
+    .map(function build_package(pkg) {
+      const members = documents
+        .filter((d) => d.directory === pkg.directory && d.package === pkg.name)
+        .map((d) => entries.get(d.id))
+        .sort(sort_by_qualified_name);
+      const of_kind = (...kinds) =>
+        members.filter((e) => kinds.includes(e.kind));
+      const children = (type, kind) =>
+        of_kind(kind).filter((e) =>
+          e.qualifiedName.startsWith(`${type.qualifiedName}.`)
+        );
+
+      return {
+        name: pkg.name,
+        directory: pkg.directory,
+        import_path: options.module
+          ? [options.module, pkg.directory]
+              .filter((p) => p && p !== '.')
+              .join('/')
+          : null,
+        doc: get_comment_text(pkg.doc),
+        command: pkg.name === 'main',
+        internal: /(^|\/)internal(\/|$)/.test(pkg.directory),
+        files: pkg.files.map((f) => f.filename).sort(),
+        types: of_kind('struct', 'interface', 'type').map((type) => ({
+          ...type,
+          fields: children(type, 'field'),
+          methods: children(type, 'method'),
+          referenced_by: referenced_by.get(type.id).sort()
+        })),
+        functions: of_kind('function'),
+        constants: of_kind('const'),
+        variables: of_kind('var')
+      };
+    });
+
+  const count = (kind) => documents.filter((d) => d.kind === kind).length;
+  return {
+    schema_version: CATALOG_SCHEMA_VERSION,
+    generated_at: options.generated_at || new Date().toISOString(),
+    module: options.module || null,
+    summary: {
+      packages: catalog_packages.length,
+      types: type_ids.size,
+      functions: count('function'),
+      methods: count('method'),
+      fields: count('field'),
+      constants: count('const'),
+      variables: count('var')
+    },
+    packages: catalog_packages
+  };
+};
+
+/**
+ * Build the API catalog of a set of Go sources.  Test files, build ignored
+ * files and files under vendor and testdata directories are not part of
+ * the API.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see build_api_catalog)
+ * @returns {Object} Catalog
+ */
+const build_source_catalog = (sources, options = {}) => {
+  const lines = new Map();
+  const files = sources
+    .filter(
+      (file) =>
+        !file.filename.endsWith('_test.go') &&
+        !SKIPPED_DIRECTORY.test(file.filename)
+    )
+    .map(function parse_source(file) {
+      lines.set(file.filename, file.source.split('\n'));
+      return parse_go_file(file.source, file.filename);
+    });
+  return build_api_catalog(group_go_packages(files), { ...options, lines });
+};
+
+/**
+ * Format a catalog as JSON: two space indented, newline terminated.
+ * @param {Object} catalog - Catalog
+ * @returns {string} JSON text
+ */
+const format_api_catalog = (catalog) => {
+  return `${JSON.stringify(catalog, null, 2)}\n`;
+};
+
+/**
+ * Build the API catalog of a project.
+ * @param {number} project_id - The project ID
+ * @param {Object} [options] - Options (see build_api_catalog)
+ * @returns {Promise<Object>} Catalog
+ */
+const export_api_catalog = async (project_id, options = {}) => {
+  return build_source_catalog(await load_go_sources(project_id), options);
+};
+
+export {
+  export_api_catalog,
+  build_source_catalog,
+  build_api_catalog,
+  format_api_catalog,
+  resolve_type_links,
+  CATALOG_SCHEMA_VERSION
+};
@@ -0,0 +1,381 @@
+'use strict';
+
+/**
+ * @fileoverview Go API changelog module.
+ * Compares two API catalogs (from `cb catalog`) and writes the changes of
+ * the public API between them as a Markdown changelog, grouped by
+ * package: the symbols added, changed, removed and newly deprecated.  This
+ * automates the API part of release notes.
+ *
+ * Changes say whether they break the API.  Function and method signatures
+ * are compared with the rules of `cb compare`: receiver, type parameter,
+ * parameter and result type changes break callers, renamed parameters do
+ * not.  Removed symbols, changed types of fields, constants and variables,
+ * and methods added to interfaces (which their implementations lack) are
+ * breaking; other additions and changed values are not.  Doc and body
+ * changes are not part of the changelog, except for deprecation notices,
+ * the paragraphs starting with `Deprecated:`.
+ *
+ * The changelog is stable for review: packages are sorted by directory,
+ * symbols by qualified name, and no timestamp is written.  Its lines are
+ * formatted by a template, whose formats can be replaced one by one.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/changelog
+ */
+
+import { parse_go_file } from './golang.mjs';
+import {
+  describe_go_declaration,
+  compare_go_declarations
+} from './refactoring.mjs';
+import { CATALOG_SCHEMA_VERSION } from './catalog.mjs';
+
+/**
+ * Changelog sections, in display order.
+ */
+const CHANGELOG_SECTIONS = ['added', 'changed', 'removed', 'deprecated'];
+
+/**
+ * Default changelog template.  Each format is a line with `{name}`
+ * placeholders:
+ * - title: {title}
+ * - summary: {added}, {changed}, {removed}, {deprecated}, {breaking}
+ * - package: {package}, {directory}, {import_path} (or the directory)
+ * - section: {section} (Added, Changed, Removed or Deprecated)
+ * - added, removed: {breaking}, {name}, {kind}, {signature}
+ * - changed: {breaking}, {name}, {kind}, {before}, {after}
+ * - deprecated: {name}, {kind}, {signature}, {notice}
+ * - breaking: the marker {breaking} is replaced with for breaking changes
+ */
+const DEFAULT_CHANGELOG_TEMPLATE = {
+  title: '# {title}',
+  summary:
+    '{added} added, {changed} changed, {removed} removed, {deprecated} deprecated ({breaking} breaking)',
+  package: '## {import_path}',
+  section: '### {section}',
+  added: '- {breaking}`{name}` ({kind}): `{signature}`',
+  changed: '- {breaking}`{name}` ({kind}): `{before}` → `{after}`',
+  removed: '- {breaking}`{name}` ({kind}): `{signature}`',
+  deprecated: '- `{name}` ({kind}): {notice}',
+  breaking: '**Breaking:** '
+};
+
+// ============================================================================
+// CATALOG ENTRIES
+// ============================================================================
+
+/**
+ * List the entries of an API catalog by id, with their package and, for
+ * fields and methods, the kind of their type.
+ * @param {Object} catalog - API catalog (from build_api_catalog)
+ * @returns {Map<string, Object>} Entries with package and parent_kind, by id
+ * @throws {Error} If the catalog has another schema version
+ */
+const list_catalog_entries = (catalog) => {
+  if (!catalog || catalog.schema_version !== CATALOG_SCHEMA_VERSION) {
+    throw new Error(
+      `Unsupported catalog schema version ${catalog ? catalog.schema_version : 'undefined'} (expected ${CATALOG_SCHEMA_VERSION})`
+    );
+  }
+
+  const entries = new Map();
+  for (const pkg of catalog.packages) {
+    const info = {
+      name: pkg.name,
+      directory: pkg.directory,
+      import_path: pkg.import_path
+    };
+    const add = (entry, parent_kind = null) =>
+      entries.set(entry.id, { ...entry, package: info, parent_kind });
+
+    for (const type of pkg.types) {
+      add(type);
+      for (const field of type.fields) add(field, type.kind);
+      for (const method of type.methods) add(method, type.kind);
+    }
+    for (const entry of [
+      ...pkg.functions,
+      ...pkg.constants,
+      ...pkg.variables
+    ]) {
+      add(entry);
+    }
+  }
+
+  return entries;
+};
+
+/**
+ * Get the deprecation notice of a doc comment: its paragraph starting
+ * with `Deprecated:`, on one line.
+ * @param {string} doc - Doc comment text
+ * @returns {string|null} Notice, or null if not deprecated
+ */
+const get_deprecation_notice = (doc) => {
+  const paragraph = (doc || '')
+    .split(/\n\s*\n/)
+    .map((p) => p.trim())
+    .find((p) => /^Deprecated:/.test(p));
+  return paragraph ? paragraph.replace(/\s+/g, ' ') : null;
+};
+
+// ============================================================================
+// CHANGES
+// ============================================================================
+
+/**
+ * Describe the declaration of a catalog entry from its signature, for
+ * compare_go_declarations.  Only functions and methods are described;
+ * interface methods have no func keyword and are not.
+ * @param {Object} entry - Catalog entry
+ * @returns {Object|null} Description, or null
+ */
+const describe_signature = (entry) => {
+  if (!/^func\b/.test(entry.signature)) return null;
+  const file = parse_go_file(`package p\n\n${entry.signature} {}\n`);
+  if (file.functions.length !== 1) return null;
+  return describe_go_declaration(
+    { kind: entry.kind, decl: file.functions[0] },
+    entry.name
+  );
+};
+
+/**
+ * Parse the type and value of a constant or variable signature.
+ * @param {Object} entry - Catalog entry
+ * @returns {Object|null} Type and value, or null
+ */
+const parse_value_signature = (entry) => {
+  const file = parse_go_file(`package p\n\n${entry.signature}\n`);
+  const decl = (entry.kind === 'const' ? file.consts : file.vars)[0];
+  return decl
+    ? { type: decl.type, value: (decl.values || []).join(', ') }
+    : null;
+};
+
+/**
+ * Compare the signatures of two versions of a catalog entry.
+ * @param {Object} before - Entry of the old catalog
+ * @param {Object} after - Entry of the new catalog
+ * @returns {Object|null} Change with aspects and a breaking flag, or null if the signature did not change
+ */
+const compare_catalog_entries = (before, after) => {
+  const normalize = (text) => (text || '').replace(/\s+/g, ' ').trim();
+  if (
+    before.kind === after.kind &&
+    normalize(before.signature) === normalize(after.signature)
+  ) {
+    return null;
+  }
+
+  let aspects = [{ aspect: 'signature', breaking: true }];
+  if (before.kind === after.kind) {
+    const a = describe_signature(before);
+    const b = describe_signature(after);
+    if (a && b) {
+      aspects = compare_go_declarations(a, b)
+        .filter((c) => c.aspect !== 'doc' && c.aspect !== 'body')
+        .map((c) => ({ aspect: c.aspect, breaking: c.breaking }));
+    } else if (before.kind === 'const' || before.kind === 'var') {
+      const x = parse_value_signature(before);
+      const y = parse_value_signature(after);
+      if (x && y) {
+        aspects = [];
+        if (normalize(x.type) !== normalize(y.type)) {
+          aspects.push({ aspect: 'type', breaking: true });
+        }
+        if (normalize(x.value) !== normalize(y.value)) {
+          aspects.push({ aspect: 'value', breaking: false });
+        }
+      }
+    }
+  } else {
+    aspects = [{ aspect: 'kind', breaking: true }];
+  }
+
+  return {
+    aspects:
+      aspects.length > 0
+        ? aspects
+        : [{ aspect: 'signature', breaking: false }],
+    breaking: aspects.some((a) => a.breaking)
+  };
+};
+
+/**
+ * Compare two API catalogs.  Entries are matched by id; a symbol renamed
+ * or moved to another package is removed and added.
+ * @param {Object} before - Old API catalog
+ * @param {Object} after - New API catalog
+ * @returns {Object} Packages with their added, changed, removed and deprecated symbols, and a summary
+ * @throws {Error} If a catalog has another schema version
+ */
+const diff_api_catalogs = (before, after) => {
+  const old_entries = list_catalog_entries(before);
+  const new_entries = list_catalog_entries(after);
+  const packages = new Map();
+
+  const add = (section, entry, change) => {
+    const key = `${entry.package.directory}\0${entry.package.name}`;
+    if (!packages.has(key)) {
+      packages.set(key, {
+        ...entry.package,
+        ...Object.fromEntries(CHANGELOG_SECTIONS.map((s) => [s, []]))
+      });
+    }
+    packages.get(key)[section].push({
+      id: entry.id,
+      name: entry.qualifiedName,
+      kind: entry.kind,
+      signature: entry.signature,
+      file: entry.file,
+      line: entry.line,
+      ...change
+    });
+  };
+
+  for (const [id, entry] of new_entries) {
+    const old = old_entries.get(id);
+    if (!old) {
+      add('added', entry, {
+        breaking: entry.kind === 'method' && entry.parent_kind === 'interface'
+      });
+    } else {
+      const change = compare_catalog_entries(old, entry);
+      if (change) add('changed', entry, { before: old.signature, ...change });
+    }
+
+    const notice = get_deprecation_notice(entry.doc);
+    if (notice && !(old && get_deprecation_notice(old.doc))) {
+      add('deprecated', entry, { notice });
+    }
+  }
+  for (const [id, entry] of old_entries) {
+    if (!new_entries.has(id)) add('removed', entry, { breaking: true });
+  }
+
+  const sorted = [...packages.values()]
+    .sort(function sort_by_directory(a, b) {
+      if (a.directory !== b.directory) {
+        return a.directory < b.directory ? -1 : 1;
+      }
+      return a.name < b.name ? -1 : a.name > b.name ? 1 : 0;
+    })
+    .map(function sort_sections(pkg) {
+      for (const section of CHANGELOG_SECTIONS) {
+        pkg[section].sort(function sort_by_name(a, b) {
+          if (a.name !== b.name) return a.name < b.name ? -1 : 1;
+          return a.kind < b.kind ? -1 : a.kind > b.kind ? 1 : 0;
+        });
+      }
+      return pkg;
+    });
+
+  const count = (section) =>
+    sorted.reduce((sum, pkg) => sum + pkg[section].length, 0);
+  return {
+    packages: sorted,
+    summary: {
+      packages: sorted.length,
+      ...Object.fromEntries(CHANGELOG_SECTIONS.map((s) => [s, count(s)])),
+      breaking: sorted.reduce(
+        (sum, pkg) =>
+          sum +
+          ['added', 'changed', 'removed'].reduce(
+            (n, s) => n + pkg[s].filter((c) => c.breaking).length,
+            0
+          ),
+        0
+      )
+    }
+  };
+};
+
+// ============================================================================
+// MARKDOWN
+// ============================================================================
+
+/**
+ * Fill the `{name}` placeholders of a template format.  Unknown names are
+ * kept as they are.
+ * @param {string} format - Template format
+ * @param {Object} values - Values by placeholder name
+ * @returns {string} Line
+ */
+const fill_template = (format, values) => {
+  return format.replace(/\{(\w+)\}/g, function fill_placeholder(match, name) {
+    return values[name] !== undefined && values[name] !== null
+      ? String(values[name])
+      : match;
+  });
+};
+
+/**
+ * Format a catalog diff as a Markdown changelog.
+ * @param {Object} diff - Catalog diff (from diff_api_catalogs)
+ * @param {Object} [options] - Options
+ * @param {string} [options.title='API changes'] - Changelog title
+ * @param {Object} [options.template] - Formats replacing those of DEFAULT_CHANGELOG_TEMPLATE
+ * @returns {string} Markdown text, newline terminated
+ */
+const format_changelog = (diff, options = {}) => {
+  const template = { ...DEFAULT_CHANGELOG_TEMPLATE, ...options.template };
+  const unknown = Object.keys(options.template || {}).filter(
+    (key) => !(key in DEFAULT_CHANGELOG_TEMPLATE)
+  );
+  if (unknown.length > 0) {
+    throw new Error(
+      `Unknown changelog template format: ${unknown.join(', ')} (expected ${Object.keys(DEFAULT_CHANGELOG_TEMPLATE).join(', ')})`
+    );
+  }
+
+  const lines = [
+    fill_template(template.title, { title: options.title || 'API changes' }),
+    '',
+    fill_template(template.summary, diff.summary)
+  ];
+  if (diff.packages.length === 0) lines.push('', 'No API changes.');
+
+  for (const pkg of diff.packages) {
+    lines.push(
+      '',
+      fill_template(template.package, {
+        package: pkg.name,
+        directory: pkg.directory,
+        import_path: pkg.import_path || pkg.directory
+      })
+    );
+    for (const section of CHANGELOG_SECTIONS) {
+      if (pkg[section].length === 0) continue;
+      lines.push(
+        '',
+        fill_template(template.section, {
+          section: section[0].toUpperCase() + section.substring(1)
+        }),
+        ''
+      );
+      for (const change of pkg[section]) {
+        lines.push(
+          fill_template(template[section], {
+            ...change,
+            after: change.signature,
+            breaking: change.breaking ? template.breaking : ''
+          })
+        );
+      }
+    }
+  }
+
+  return `${lines.join('\n')}\n`;
+};
+
+export {
+  diff_api_catalogs,
+  format_changelog,
+  compare_catalog_entries,
+  list_catalog_entries,
+  get_deprecation_notice,
+  fill_template,
+  CHANGELOG_SECTIONS,
+  DEFAULT_CHANGELOG_TEMPLATE
+};
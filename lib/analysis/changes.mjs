@@ -0,0 +1,505 @@
+'use strict';
+
+/**
+ * @fileoverview Symbol level change module.
+ * Compares two revisions of a Go code base and lists the packages, files
+ * and symbols that changed between them: what actually changed,
+ * semantically, rather than which lines.  Declarations are compared with
+ * the same rules as `cb compare`, so every change says whether it breaks
+ * the API, and removed symbols found again under another name or in
+ * another package are reported as renamed or moved.
+ *
+ * Revisions are read through a revision source, an object with two
+ * methods, so that git is only one of the possible backends:
+ * - list_files(revision): Promise of the filenames of the revision
+ * - read_file(revision, filename): Promise of the source of a file, or null
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/changes
+ */
+
+import { parse_go_file, group_go_packages, is_exported } from './golang.mjs';
+import {
+  describe_go_declaration,
+  compare_go_declarations
+} from './refactoring.mjs';
+
+/**
+ * Symbol change kinds, in display order.
+ */
+const SYMBOL_CHANGE_KINDS = [
+  'added',
+  'removed',
+  'renamed',
+  'moved',
+  'modified'
+];
+
+/**
+ * Directories the go tool does not build as part of the module.
+ */
+const SKIPPED_DIRECTORY = /(^|\/)(vendor|testdata)\//;
+
+// ============================================================================
+// REVISION SOURCES
+// ============================================================================
+
+/**
+ * Create a revision source over revisions held in memory, such as
+ * snapshots of a project taken at different times.
+ * @param {Object} revisions - Files with filename and source, by revision name
+ * @returns {Object} Revision source
+ */
+const create_memory_source = (revisions) => {
+  const get_files = (revision) => {
+    const files = revisions[revision];
+    if (!files) throw new Error(`Unknown revision '${revision}'`);
+    return files;
+  };
+
+  return {
+    list_files: async (revision) => get_files(revision).map((f) => f.filename),
+    read_file: async (revision, filename) => {
+      const file = get_files(revision).find((f) => f.filename === filename);
+      return file ? file.source : null;
+    }
+  };
+};
+
+/**
+ * Read the Go files of a revision.  Files under vendor and testdata
+ * directories are skipped, as are test files unless asked for.
+ * @param {Object} source - Revision source
+ * @param {string} revision - Revision name
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.include_tests=false] - Include _test.go files
+ * @returns {Promise<Object[]>} Files with filename and source
+ */
+const read_revision_sources = async (source, revision, options = {}) => {
+  const filenames = (await source.list_files(revision))
+    .filter(
+      (f) =>
+        f.endsWith('.go') &&
+        !SKIPPED_DIRECTORY.test(f) &&
+        (options.include_tests || !f.endsWith('_test.go'))
+    )
+    .sort();
+
+  const files = [];
+  for (const filename of filenames) {
+    const text = await source.read_file(revision, filename);
+    if (text === null || text === undefined) continue;
+    files.push({ filename, source: text });
+  }
+  return files;
+};
+
+// ============================================================================
+// SYMBOLS
+// ============================================================================
+
+/**
+ * Check whether a symbol is part of the API of its package: exported, and
+ * for methods, declared on an exported type.
+ * @param {Object} symbol - Symbol (from collect_revision_symbols)
+ * @returns {boolean} True if the symbol is part of the API
+ */
+const is_api_symbol = (symbol) => {
+  return symbol.name.split('.').every(is_exported);
+};
+
+/**
+ * Collect the top level symbols of a set of packages, keyed by package
+ * directory and name (`geo:Circle.Area`).  Methods are named Type.Method.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Map<string, Object>} Symbols with their declaration description
+ */
+const collect_revision_symbols = (packages) => {
+  const symbols = new Map();
+
+  for (const pkg of packages) {
+    const add = (kind, name, decl, lookup = name) => {
+      const key = `${pkg.directory}:${name}`;
+      symbols.set(key, {
+        key,
+        name,
+        kind,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: decl.filename,
+        line: decl.line,
+        description: describe_go_declaration({ kind, decl }, lookup)
+      });
+    };
+
+    for (const fn of pkg.functions) {
+      if (!fn.name || fn.name === '_' || fn.name === 'init') continue;
+      if (fn.receiver) {
+        const receiver = fn.receiver.type.replace(/\[.*$/, '');
+        add('method', `${receiver}.${fn.name}`, fn, fn.name);
+      } else {
+        add('function', fn.name, fn);
+      }
+    }
+    for (const type of pkg.types) add('type', type.name, type);
+    for (const [decls, kind] of [
+      [pkg.consts, 'const'],
+      [pkg.vars, 'var']
+    ]) {
+      for (const decl of decls) {
+        for (const name of decl.names.filter((n) => n !== '_')) {
+          add(kind, name, decl);
+        }
+      }
+    }
+  }
+
+  return symbols;
+};
+
+/**
+ * Get the key matching a removed symbol with the symbol it was renamed or
+ * moved to: its declaration without name, position and doc comment.
+ * Methods of a renamed type keep their name and use the new name of
+ * their type; other methods may change name but not type.
+ * @param {Object} symbol - Symbol (from collect_revision_symbols)
+ * @param {Map<string, string>} [type_renames] - New names of renamed types, by the symbol key of the type in the symbol's revision
+ * @returns {string} Rename key
+ */
+const get_rename_key = (symbol, type_renames = new Map()) => {
+  const {
+    line,
+    end_line,
+    doc,
+    name,
+    signature,
+    receiver,
+    receiver_name,
+    ...declaration
+  } = symbol.description;
+
+  let owner = null;
+  if (symbol.kind === 'method') {
+    const [type, method] = symbol.name.split('.');
+    const renamed = type_renames.get(`${symbol.directory}:${type}`);
+    owner = {
+      pointer: receiver.startsWith('*'),
+      type: renamed || type,
+      method: renamed ? method : null
+    };
+  }
+  return JSON.stringify({ kind: symbol.kind, owner, declaration });
+};
+
+/**
+ * Pair removed symbols with added symbols of identical declaration.
+ * Only unambiguous pairs are kept: a key shared by several removed or
+ * several added symbols is left as removals and additions.
+ * @param {Object[]} removed - Symbols only in the old revision
+ * @param {Object[]} added - Symbols only in the new revision
+ * @param {Object} [type_renames] - New type names by old (before) and new (after) type key
+ * @returns {Object[]} Pairs of old and new symbols
+ */
+const match_renamed_symbols = (removed, added, type_renames = {}) => {
+  const group = (symbols, renames) => {
+    const groups = new Map();
+    for (const symbol of symbols) {
+      const key = get_rename_key(symbol, renames);
+      if (!groups.has(key)) groups.set(key, []);
+      groups.get(key).push(symbol);
+    }
+    return groups;
+  };
+
+  const before = group(removed, type_renames.before);
+  const after = group(added, type_renames.after);
+  const pairs = [];
+  for (const [key, old_symbols] of before) {
+    const new_symbols = after.get(key);
+    if (old_symbols.length !== 1 || !new_symbols || new_symbols.length !== 1) {
+      continue;
+    }
+    pairs.push({ before: old_symbols[0], after: new_symbols[0] });
+  }
+  return pairs;
+};
+
+// ============================================================================
+// CHANGES
+// ============================================================================
+
+/**
+ * Compare the files of two revisions.  A removed file whose source is
+ * found unchanged under another name is reported as renamed.
+ * @param {Object[]} before - Files of the old revision
+ * @param {Object[]} after - Files of the new revision
+ * @returns {Object[]} Changed files with filename, status and old filename
+ */
+const diff_revision_files = (before, after) => {
+  const old_files = new Map(before.map((f) => [f.filename, f.source]));
+  const new_files = new Map(after.map((f) => [f.filename, f.source]));
+  const files = [];
+
+  for (const [filename, source] of new_files) {
+    if (!old_files.has(filename)) {
+      files.push({ filename, status: 'added', old_filename: null });
+    } else if (old_files.get(filename) !== source) {
+      files.push({ filename, status: 'modified', old_filename: null });
+    }
+  }
+  for (const filename of old_files.keys()) {
+    if (new_files.has(filename)) continue;
+    const source = old_files.get(filename);
+    const renamed = files.find(
+      (f) => f.status === 'added' && new_files.get(f.filename) === source
+    );
+    if (renamed) {
+      renamed.status = 'renamed';
+      renamed.old_filename = filename;
+    } else {
+      files.push({ filename, status: 'deleted', old_filename: null });
+    }
+  }
+
+  return files.sort(function sort_by_filename(a, b) {
+    return a.filename < b.filename ? -1 : 1;
+  });
+};
+
+/**
+ * Compare the packages of two revisions by directory.  Removing a package
+ * with exported symbols, or changing a package's name, breaks importers.
+ * @param {Object[]} before - Packages of the old revision
+ * @param {Object[]} after - Packages of the new revision
+ * @returns {Object[]} Changed packages with directory, status and names
+ */
+const diff_revision_packages = (before, after) => {
+  const old_packages = new Map(before.map((p) => [p.directory, p]));
+  const new_packages = new Map(after.map((p) => [p.directory, p]));
+  const has_api = (pkg) =>
+    pkg.name !== 'main' &&
+    [...pkg.functions, ...pkg.types].some((d) => d.exported);
+  const packages = [];
+
+  for (const [directory, pkg] of new_packages) {
+    const old = old_packages.get(directory);
+    if (!old) {
+      packages.push({
+        directory,
+        status: 'added',
+        name: pkg.name,
+        old_name: null,
+        breaking: false
+      });
+    } else if (old.name !== pkg.name) {
+      packages.push({
+        directory,
+        status: 'renamed',
+        name: pkg.name,
+        old_name: old.name,
+        breaking: has_api(old)
+      });
+    }
+  }
+  for (const [directory, pkg] of old_packages) {
+    if (new_packages.has(directory)) continue;
+    packages.push({
+      directory,
+      status: 'removed',
+      name: null,
+      old_name: pkg.name,
+      breaking: has_api(pkg)
+    });
+  }
+
+  return packages.sort(function sort_by_directory(a, b) {
+    return a.directory < b.directory ? -1 : 1;
+  });
+};
+
+/**
+ * Build a symbol change record.
+ * @param {string} change - Change kind (see SYMBOL_CHANGE_KINDS)
+ * @param {Object|null} before - Symbol in the old revision
+ * @param {Object|null} after - Symbol in the new revision
+ * @param {Object[]} [changes] - Declaration changes (from compare_go_declarations)
+ * @returns {Object} Symbol change
+ */
+const build_symbol_change = (change, before, after, changes = []) => {
+  const symbol = after || before;
+  const api = before ? is_api_symbol(before) : false;
+  const moved = before && after && before.directory !== after.directory;
+
+  let breaking = false;
+  if (change === 'removed' || change === 'renamed' || change === 'moved') {
+    breaking = api;
+  } else if (change === 'modified') {
+    breaking = api && changes.some((c) => c.breaking);
+  }
+
+  return {
+    key: symbol.key,
+    name: symbol.name,
+    kind: symbol.kind,
+    package: symbol.package,
+    directory: symbol.directory,
+    change,
+    filename: symbol.filename,
+    line: symbol.line,
+    old_name: before && after && before.name !== after.name ? before.name : null,
+    old_directory: moved ? before.directory : null,
+    old_filename:
+      before && after && before.filename !== after.filename
+        ? before.filename
+        : null,
+    breaking,
+    changes: changes.map((c) => ({ ...c, breaking: api && c.breaking }))
+  };
+};
+
+/**
+ * Compare the symbols of two revisions.
+ * Symbols with the same package directory and name are compared
+ * declaration by declaration; the others were added or removed, unless a
+ * removed symbol is found unchanged under another name (renamed) or in
+ * another directory (moved).  Types are paired first, so that the
+ * methods of a renamed type follow it.
+ * @param {Map<string, Object>} before - Symbols of the old revision (from collect_revision_symbols)
+ * @param {Map<string, Object>} after - Symbols of the new revision
+ * @returns {Object[]} Symbol changes
+ */
+const diff_revision_symbols = (before, after) => {
+  const symbols = [];
+  const removed = [...before.values()].filter((s) => !after.has(s.key));
+  const added = [...after.values()].filter((s) => !before.has(s.key));
+  const paired = new Set();
+  const type_renames = { before: new Map(), after: new Map() };
+
+  const pair = (candidates_removed, candidates_added, renames) => {
+    for (const { before: old, after: symbol } of match_renamed_symbols(
+      candidates_removed,
+      candidates_added,
+      renames
+    )) {
+      paired.add(old.key);
+      paired.add(symbol.key);
+      if (old.kind === 'type') {
+        type_renames.before.set(old.key, symbol.name);
+        type_renames.after.set(symbol.key, symbol.name);
+      }
+      const change = old.name === symbol.name ? 'moved' : 'renamed';
+      symbols.push(build_symbol_change(change, old, symbol));
+    }
+  };
+  pair(
+    removed.filter((s) => s.kind !== 'method'),
+    added.filter((s) => s.kind !== 'method')
+  );
+  pair(
+    removed.filter((s) => s.kind === 'method'),
+    added.filter((s) => s.kind === 'method'),
+    type_renames
+  );
+
+  for (const symbol of added) {
+    if (!paired.has(symbol.key)) {
+      symbols.push(build_symbol_change('added', null, symbol));
+    }
+  }
+  for (const symbol of removed) {
+    if (!paired.has(symbol.key)) {
+      symbols.push(build_symbol_change('removed', symbol, null));
+    }
+  }
+  for (const [key, symbol] of after) {
+    const old = before.get(key);
+    if (!old) continue;
+    const changes = compare_go_declarations(
+      old.description,
+      symbol.description
+    );
+    if (changes.length > 0) {
+      symbols.push(build_symbol_change('modified', old, symbol, changes));
+    }
+  }
+
+  return symbols.sort(function sort_by_change(a, b) {
+    const a_kind = SYMBOL_CHANGE_KINDS.indexOf(a.change);
+    const b_kind = SYMBOL_CHANGE_KINDS.indexOf(b.change);
+    if (a_kind !== b_kind) return a_kind - b_kind;
+    return a.key < b.key ? -1 : 1;
+  });
+};
+
+/**
+ * Compare the Go files of two revisions at the symbol level.
+ * @param {Object[]} before - Files of the old revision, with filename and source
+ * @param {Object[]} after - Files of the new revision, with filename and source
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.breaking_only=false] - Only list breaking package and symbol changes
+ * @returns {Object} Changed files, packages and symbols, with a summary of all changes
+ */
+const diff_revision_sources = (before, after, options = {}) => {
+  const parse = (files) =>
+    group_go_packages(files.map((f) => parse_go_file(f.source, f.filename)));
+  const old_packages = parse(before);
+  const new_packages = parse(after);
+
+  const files = diff_revision_files(before, after);
+  let packages = diff_revision_packages(old_packages, new_packages);
+  let symbols = diff_revision_symbols(
+    collect_revision_symbols(old_packages),
+    collect_revision_symbols(new_packages)
+  );
+
+  const by_change = {};
+  for (const change of SYMBOL_CHANGE_KINDS) {
+    by_change[change] = symbols.filter((s) => s.change === change).length;
+  }
+  const summary = {
+    files_changed: files.length,
+    packages_changed: packages.length,
+    symbols_changed: symbols.length,
+    breaking_changes:
+      symbols.filter((s) => s.breaking).length +
+      packages.filter((p) => p.breaking).length,
+    by_change
+  };
+
+  if (options.breaking_only) {
+    packages = packages.filter((p) => p.breaking);
+    symbols = symbols
+      .filter((s) => s.breaking)
+      .map((s) => ({ ...s, changes: s.changes.filter((c) => c.breaking) }));
+  }
+
+  return { files, packages, symbols, summary };
+};
+
+/**
+ * Compare two revisions of a source at the symbol level.
+ * @param {Object} source - Revision source (see create_memory_source)
+ * @param {string} from - Old revision
+ * @param {string} to - New revision
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.breaking_only=false] - Only list breaking changes
+ * @param {boolean} [options.include_tests=false] - Compare _test.go files too
+ * @returns {Promise<Object>} Revisions with their changes (see diff_revision_sources)
+ */
+const diff_revisions = async (source, from, to, options = {}) => {
+  const before = await read_revision_sources(source, from, options);
+  const after = await read_revision_sources(source, to, options);
+  return { from, to, ...diff_revision_sources(before, after, options) };
+};
+
+export {
+  diff_revisions,
+  diff_revision_sources,
+  diff_revision_symbols,
+  diff_revision_packages,
+  diff_revision_files,
+  collect_revision_symbols,
+  match_renamed_symbols,
+  read_revision_sources,
+  create_memory_source,
+  SYMBOL_CHANGE_KINDS
+};
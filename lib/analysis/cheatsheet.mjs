@@ -0,0 +1,386 @@
+'use strict';
+
+/**
+ * @fileoverview Go API cheat sheet module.
+ * Builds a terse reference card of the public API of each package, the
+ * fast-lookup artifact to keep open while using a package: the
+ * constructors of each exported type, its methods grouped by what they
+ * do, then the free functions, each with a one-line signature and the
+ * first sentence of its doc comment.
+ *
+ * Methods are grouped as:
+ * - construction: package functions returning the type (`NewServer`)
+ * - mutation: setters, and methods writing the fields of their receiver
+ *   or named like a change (`Add`, `Set`, `Close`); methods without
+ *   results are assumed to act on their receiver
+ * - query: getters, String and Error, and other methods with results
+ *
+ * To keep a card within a screenful, entries are prioritized and the
+ * least important left out, with a count of the omitted ones: the
+ * constructors first, then the entries called most often across the
+ * project (tests included, as they show typical use), then documented
+ * ones.  Test files are not part of the API.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/cheatsheet
+ */
+
+import {
+  get_comment_text,
+  get_base_type,
+  summarize_go_body,
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { get_node_id, find_call_edges } from './graph.mjs';
+import { format_method_signature } from './interfaces.mjs';
+import { classify_method_role } from './structs.mjs';
+import { get_doc_synopsis } from './godoc.mjs';
+
+/**
+ * Default number of lines of a cheat sheet, about a screenful.
+ */
+const DEFAULT_MAX_LINES = 50;
+
+/**
+ * Groups of the methods of a type, in display order.
+ */
+const CHEATSHEET_GROUPS = ['construction', 'mutation', 'query'];
+
+/**
+ * Method names describing a change of the receiver.
+ */
+const MUTATION_NAME_PATTERN =
+  /^(Set|Add|Append|Put|Push|Pop|Insert|Remove|Delete|Clear|Reset|Update|Close|Open|Start|Stop|Register|Unregister|Enable|Disable|Apply|Store|Write|Flush|Lock|Unlock|Init)(?![a-z])/;
+
+// ============================================================================
+// ENTRIES
+// ============================================================================
+
+/**
+ * Classify a method of a type as a mutation or a query (see the module
+ * doc).  Interface methods are classified by their name and results.
+ * @param {Object} method - Method (from the Go parser)
+ * @returns {string} mutation or query
+ */
+const classify_cheatsheet_method = (method) => {
+  if (method.body !== undefined && method.receiver) {
+    const role = classify_method_role(method);
+    if (role === 'setter') return 'mutation';
+    if (role === 'getter' || role === 'formatting') return 'query';
+    const receiver = method.receiver.name;
+    const { field_writes } = summarize_go_body(method.body, method.body_line);
+    if (receiver && field_writes.some((w) => w.base === receiver)) {
+      return 'mutation';
+    }
+  }
+  if (MUTATION_NAME_PATTERN.test(method.name)) return 'mutation';
+  const results = method.results.filter((r) => r.type !== 'error');
+  return results.length > 0 ? 'query' : 'mutation';
+};
+
+/**
+ * Count the calls of each function and method of a set of packages.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Map<string, number>} Calls by node ID
+ */
+const count_calls = (packages) => {
+  const calls = new Map();
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      for (const edge of find_call_edges(fn, pkg, packages)) {
+        calls.set(edge.target, (calls.get(edge.target) || 0) + 1);
+      }
+    }
+  }
+  return calls;
+};
+
+/**
+ * Build a cheat sheet entry for a function or method.
+ * @param {Object} fn - Function or method (from the Go parser)
+ * @param {string} id - Node ID
+ * @param {string} group - Group of the entry
+ * @param {Map<string, number>} calls - Calls by node ID
+ * @returns {Object} Entry with name, signature, synopsis, group, calls and position
+ */
+const to_cheatsheet_entry = (fn, id, group, calls) => {
+  const doc = get_comment_text(fn.doc);
+  return {
+    name: fn.name,
+    signature: format_method_signature(fn),
+    synopsis: doc ? get_doc_synopsis(doc) : null,
+    group,
+    calls: calls.get(id) || 0,
+    filename: fn.filename || null,
+    line: fn.line || null
+  };
+};
+
+/**
+ * Collect the constructors, typed methods and free functions of the
+ * exported API of a package.  A constructor is an exported function whose
+ * first result is an exported type of the package, or a pointer to one.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Map<string, number>} calls - Calls by node ID
+ * @returns {Object} Package with synopsis, types (with entries) and functions
+ */
+const collect_package_api = (pkg, calls) => {
+  const is_api = (decl) => decl.exported && !decl.filename.endsWith('_test.go');
+  const types = pkg.types.filter(is_api).map((type) => {
+    const doc = get_comment_text(type.doc);
+    return {
+      name: type.name,
+      kind: type.kind,
+      synopsis: doc ? get_doc_synopsis(doc) : null,
+      line: type.line,
+      entries: []
+    };
+  });
+  const by_name = new Map(types.map((t) => [t.name, t]));
+  const functions = [];
+
+  for (const fn of pkg.functions.filter(is_api)) {
+    if (fn.receiver) {
+      const type = by_name.get(fn.receiver.type);
+      if (!type) continue;
+      const id = get_node_id(pkg.directory, `${type.name}.${fn.name}`);
+      const group = classify_cheatsheet_method(fn);
+      type.entries.push(to_cheatsheet_entry(fn, id, group, calls));
+      continue;
+    }
+    const id = get_node_id(pkg.directory, fn.name);
+    const [first] = fn.results;
+    const type =
+      first && !/^\[/.test(first.type)
+        ? by_name.get(get_base_type(first.type))
+        : null;
+    if (type) {
+      type.entries.push(to_cheatsheet_entry(fn, id, 'construction', calls));
+    } else {
+      functions.push(to_cheatsheet_entry(fn, id, 'function', calls));
+    }
+  }
+
+  for (const type of pkg.types.filter(is_api)) {
+    if (type.kind !== 'interface') continue;
+    for (const method of type.methods) {
+      const id = get_node_id(pkg.directory, `${type.name}.${method.name}`);
+      const group = classify_cheatsheet_method(method);
+      by_name
+        .get(type.name)
+        .entries.push(
+          to_cheatsheet_entry(
+            { ...method, filename: type.filename },
+            id,
+            group,
+            calls
+          )
+        );
+    }
+  }
+
+  const doc = get_comment_text(pkg.doc);
+  return {
+    name: pkg.name,
+    directory: pkg.directory,
+    synopsis: doc ? get_doc_synopsis(doc) : null,
+    types,
+    functions
+  };
+};
+
+// ============================================================================
+// PRIORITIZATION
+// ============================================================================
+
+/**
+ * Order entries by importance: constructors, then by calls, then
+ * documented entries, then by name.
+ * @param {Object} a - Entry
+ * @param {Object} b - Entry
+ * @returns {number} Sort order
+ */
+const sort_by_priority = (a, b) => {
+  const a_rank = a.group === 'construction' ? 0 : 1;
+  const b_rank = b.group === 'construction' ? 0 : 1;
+  return (
+    a_rank - b_rank ||
+    b.calls - a.calls ||
+    Number(Boolean(b.synopsis)) - Number(Boolean(a.synopsis)) ||
+    a.name.localeCompare(b.name)
+  );
+};
+
+/**
+ * Arrange the kept entries of a package into the groups of a card, with
+ * the number of entries left out of each.
+ * @param {Object} api - Package API (from collect_package_api)
+ * @param {Set<Object>} kept - Entries kept
+ * @returns {Object} Card package with types (with groups) and functions
+ */
+const arrange_card = (api, kept) => {
+  const arrange = (entries) => {
+    const omitted = entries.filter((e) => !kept.has(e)).length;
+    const shown = entries
+      .filter((e) => kept.has(e))
+      .sort(function sort_by_name(a, b) {
+        return a.name.localeCompare(b.name);
+      });
+    return { entries: shown, omitted };
+  };
+  return {
+    name: api.name,
+    directory: api.directory,
+    synopsis: api.synopsis,
+    types: api.types.map((type) => ({
+      name: type.name,
+      kind: type.kind,
+      synopsis: type.synopsis,
+      groups: Object.fromEntries(
+        CHEATSHEET_GROUPS.map((group) => [
+          group,
+          arrange(type.entries.filter((e) => e.group === group))
+        ])
+      )
+    })),
+    functions: arrange(api.functions)
+  };
+};
+
+/**
+ * Format the cheat sheet of a package as lines of text.
+ * @param {Object} card - Card package (from arrange_card)
+ * @returns {string[]} Lines
+ */
+const format_card_lines = (card) => {
+  const lines = [];
+  const describe = (text, synopsis) =>
+    synopsis ? `${text}  // ${synopsis}` : text;
+  const add_section = (indent, section) => {
+    for (const entry of section.entries) {
+      lines.push(`${indent}${describe(entry.signature, entry.synopsis)}`);
+    }
+    if (section.omitted > 0) lines.push(`${indent}... ${section.omitted} more`);
+  };
+  // Sections without any entry kept are left out, counted at the end
+  let hidden = 0;
+  const is_shown = (section) => {
+    if (section.entries.length > 0) return true;
+    hidden += section.omitted;
+    return false;
+  };
+
+  lines.push(describe(`package ${card.name} (${card.directory})`, card.synopsis));
+  for (const type of card.types) {
+    const shown = CHEATSHEET_GROUPS.filter((g) => is_shown(type.groups[g]));
+    if (shown.length === 0) continue;
+    lines.push('');
+    lines.push(describe(`${type.name} ${type.kind}`, type.synopsis));
+    for (const group of shown) {
+      lines.push(`  ${group}:`);
+      add_section('    ', type.groups[group]);
+    }
+  }
+  if (is_shown(card.functions)) {
+    lines.push('');
+    lines.push('functions:');
+    add_section('  ', card.functions);
+  }
+  if (hidden > 0) {
+    lines.push('');
+    lines.push(`... ${hidden} more in other types and groups`);
+  }
+  return lines;
+};
+
+// ============================================================================
+// CHEAT SHEET
+// ============================================================================
+
+/**
+ * Build the cheat sheets of a set of packages, each kept within a number
+ * of lines by leaving out the least important entries.
+ * @param {Object[]} packages - Packages (from group_go_packages), test files included
+ * @param {Object} [options] - Options
+ * @param {number} [options.max_lines=50] - Lines of each sheet, 0 for no limit
+ * @param {string} [options.directory] - Only build the sheet of this directory
+ * @returns {Object[]} Sheets with package, groups, omitted count and text lines
+ * @throws {Error} If max_lines is negative
+ */
+const build_cheatsheets = (packages, options = {}) => {
+  const max_lines =
+    options.max_lines === undefined ? DEFAULT_MAX_LINES : options.max_lines;
+  if (!Number.isInteger(max_lines) || max_lines < 0) {
+    throw new Error(`Invalid max lines '${options.max_lines}'`);
+  }
+  const calls = count_calls(packages);
+
+  return packages
+    .filter((pkg) => pkg.name && pkg.name !== 'main' && !/_test$/.test(pkg.name))
+    .filter(
+      (pkg) => options.directory === undefined || pkg.directory === options.directory
+    )
+    .sort(function sort_by_directory(a, b) {
+      return a.directory.localeCompare(b.directory);
+    })
+    .map(function build_sheet(pkg) {
+      const api = collect_package_api(pkg, calls);
+      const entries = [
+        ...api.types.flatMap((t) => t.entries),
+        ...api.functions
+      ].sort(sort_by_priority);
+
+      let kept = new Set(entries);
+      if (max_lines > 0) {
+        kept = new Set();
+        for (const entry of entries) {
+          kept.add(entry);
+          const lines = format_card_lines(arrange_card(api, kept));
+          if (lines.length > max_lines) kept.delete(entry);
+        }
+      }
+
+      const card = arrange_card(api, kept);
+      return {
+        ...card,
+        entries: entries.length,
+        omitted: entries.length - kept.size,
+        lines: format_card_lines(card)
+      };
+    });
+};
+
+/**
+ * Build the cheat sheets of a set of Go sources, such as the files of a
+ * directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see build_cheatsheets)
+ * @returns {Object} Sheets
+ */
+const build_source_cheatsheets = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  return { sheets: build_cheatsheets(group_go_packages(files), options) };
+};
+
+/**
+ * Build the cheat sheets of a project.
+ * @param {number} project_id - The project ID
+ * @param {Object} [options] - Options (see build_cheatsheets)
+ * @returns {Promise<Object>} Sheets
+ */
+const analyze_project_cheatsheets = async (project_id, options = {}) => {
+  const packages = await load_go_packages(project_id);
+  return { sheets: build_cheatsheets(packages, options) };
+};
+
+export {
+  analyze_project_cheatsheets,
+  build_source_cheatsheets,
+  build_cheatsheets,
+  classify_cheatsheet_method,
+  CHEATSHEET_GROUPS,
+  DEFAULT_MAX_LINES
+};
@@ -2,12 +2,22 @@
 
 /**
  * @fileoverview Concurrency analysis module.
- * Detects async/await patterns, threads, locks, and potential race conditions.
+ * Detects async/await patterns, threads, locks, and potential race conditions,
+ * and models Go channel and goroutine topology.
  * Computed on-demand from source code - no database changes required.
  * @module lib/concurrency
  */
 
 import { query } from '../db.mjs';
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  split_top_level,
+  classify_type,
+  load_go_packages
+} from './golang.mjs';
 
 /**
  * Language-specific concurrency patterns to detect.
@@ -995,8 +1005,582 @@ const analyze_project_concurrency = async (project_id) => {
   return results;
 };
 
+// ============================================================================
+// GO CHANNEL TOPOLOGY
+// ============================================================================
+
+/**
+ * Identifiers that can precede or follow `<-` without being a channel.
+ */
+const CHANNEL_KEYWORDS = new Set(['chan', 'case', 'return', 'go', 'defer']);
+
+/**
+ * Get the direction a channel type allows.
+ * @param {string} type_text - Channel type (e.g. `chan<- int`)
+ * @returns {string} 'send', 'receive' or 'both'
+ */
+const get_channel_direction = (type_text) => {
+  const text = (type_text || '').trim();
+  if (text.startsWith('<-')) return 'receive';
+  if (/^chan\s*<-/.test(text)) return 'send';
+  return 'both';
+};
+
+/**
+ * Get the element type of a channel type.
+ * @param {string} type_text - Channel type
+ * @returns {string} Element type
+ */
+const get_channel_element_type = (type_text) => {
+  return (type_text || '')
+    .trim()
+    .replace(/^(<-\s*)?chan\s*(<-)?/, '')
+    .trim();
+};
+
+/**
+ * Get the name used for a function in the concurrency model.
+ * @param {Object} fn - Parsed function (from the Go parser)
+ * @returns {string} Function name, qualified with its receiver for methods
+ */
+const get_function_key = (fn) => {
+  return fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+};
+
+/**
+ * Find the function literals launched with `go func(...) {...}(...)`.
+ * @param {string} masked - Masked function body
+ * @returns {Object[]} Ranges with start (the `go` keyword) and end offsets
+ */
+const find_goroutine_closures = (masked) => {
+  const closures = [];
+  const pattern = /\bgo\s+func\s*\(/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    const params_close = find_matching(masked, pattern.lastIndex - 1);
+    if (params_close === -1) continue;
+    const body_open = masked.indexOf('{', params_close);
+    const body_close = body_open === -1 ? -1 : find_matching(masked, body_open);
+    if (body_close === -1) continue;
+    closures.push({ start: match.index, end: body_close + 1 });
+  }
+
+  return closures;
+};
+
+/**
+ * Build a best-effort model of the channels, channel operations and
+ * goroutine launch sites in a Go package.
+ * Channels are identified by where they are declared: a local variable or
+ * parameter of a function, a struct field or a package level variable.
+ * When a channel is passed to another function of the package, the
+ * callee's parameter is treated as the same channel, which connects the
+ * stages of a pipeline.  The usage direction of each channel is derived
+ * from the operations performed on it.
+ * @param {Object} pkg - Package (from load_go_packages)
+ * @returns {Object} Concurrency model with channels, operations, goroutines and functions
+ */
+const build_concurrency_model = (pkg) => {
+  const channels = new Map();
+  const parent = new Map();
+  const operations = [];
+  const goroutines = [];
+  const bindings = [];
+
+  // Union-find so that aliased channels share one identity
+  const find = (id) => {
+    while (parent.get(id) !== id) id = parent.get(id);
+    return id;
+  };
+
+  const add_channel = (id, channel) => {
+    if (channels.has(id)) return id;
+    channels.set(id, { id, ...channel });
+    parent.set(id, id);
+    return id;
+  };
+
+  // Package level channel variables and struct fields
+  const package_channels = new Map();
+  for (const v of pkg.vars || []) {
+    const value = v.values[0] || '';
+    const make_match = value.match(/^make\(\s*([\s\S]*)\)$/);
+    const type = v.type || (make_match ? split_top_level(make_match[1])[0] : '');
+    if (classify_type(type) !== 'chan') continue;
+    for (const name of v.names) {
+      package_channels.set(
+        name,
+        add_channel(name, {
+          name,
+          kind: 'package',
+          scope: null,
+          type,
+          buffer: make_match ? split_top_level(make_match[1])[1] || null : null,
+          filename: v.filename,
+          line: v.line
+        })
+      );
+    }
+  }
+
+  const field_channels = new Map();
+  for (const type of pkg.types || []) {
+    if (type.kind !== 'struct') continue;
+    for (const field of type.fields) {
+      if (classify_type(field.type) !== 'chan') continue;
+      for (const name of field.names) {
+        const id = `${type.name}.${name}`;
+        add_channel(id, {
+          name,
+          kind: 'field',
+          scope: type.name,
+          type: field.type,
+          buffer: null,
+          filename: type.filename,
+          line: field.line
+        });
+        if (!field_channels.has(name)) field_channels.set(name, []);
+        field_channels.get(name).push(id);
+      }
+    }
+  }
+
+  const functions_by_name = new Map();
+  const methods_by_name = new Map();
+  for (const fn of pkg.functions || []) {
+    if (fn.receiver) {
+      if (!methods_by_name.has(fn.name)) methods_by_name.set(fn.name, []);
+      methods_by_name.get(fn.name).push(fn);
+    } else {
+      functions_by_name.set(fn.name, fn);
+    }
+  }
+
+  const function_summaries = [];
+
+  for (const fn of pkg.functions || []) {
+    if (!fn.body) continue;
+    const key = get_function_key(fn);
+    const body = fn.body;
+    const masked = mask_source(body);
+    const body_lines = build_line_index(body);
+    const line_of = (offset) => fn.body_line + line_at(body_lines, offset) - 1;
+    const local = new Map();
+
+    // Channel parameters (and receivers are never channels)
+    for (const param of fn.params) {
+      if (!param.name || classify_type(param.type) !== 'chan') continue;
+      local.set(
+        param.name,
+        add_channel(`${key}:${param.name}`, {
+          name: param.name,
+          kind: 'parameter',
+          scope: key,
+          type: param.type,
+          buffer: null,
+          filename: fn.filename,
+          line: fn.line
+        })
+      );
+    }
+
+    // Local channels created with make or declared with a channel type
+    const make_pattern =
+      /([A-Za-z_]\w*)\s*(?::=|=)\s*make\(\s*((?:<-\s*)?chan\b)/g;
+    let match;
+    while ((match = make_pattern.exec(masked)) !== null) {
+      const open = masked.indexOf('(', match.index + match[1].length);
+      const close = find_matching(masked, open);
+      if (close === -1) continue;
+      const args = split_top_level(body.substring(open + 1, close));
+      const name = match[1];
+      if (package_channels.has(name) && !/:=/.test(match[0])) continue;
+      local.set(
+        name,
+        add_channel(`${key}:${name}`, {
+          name,
+          kind: 'local',
+          scope: key,
+          type: args[0],
+          buffer: args[1] || null,
+          filename: fn.filename,
+          line: line_of(match.index)
+        })
+      );
+    }
+
+    const var_pattern = /\bvar\s+([A-Za-z_]\w*)\s+((?:<-\s*)?chan\b[^\n;=]*)/g;
+    while ((match = var_pattern.exec(masked)) !== null) {
+      if (local.has(match[1])) continue;
+      local.set(
+        match[1],
+        add_channel(`${key}:${match[1]}`, {
+          name: match[1],
+          kind: 'local',
+          scope: key,
+          type: match[2].trim(),
+          buffer: null,
+          filename: fn.filename,
+          line: line_of(match.index)
+        })
+      );
+    }
+
+    // Resolve an expression used as a channel to a channel id
+    const resolve = (expr, create) => {
+      if (local.has(expr)) return local.get(expr);
+      if (package_channels.has(expr)) return package_channels.get(expr);
+      const dot = expr.lastIndexOf('.');
+      if (dot !== -1) {
+        const field = expr.substring(dot + 1);
+        const candidates = field_channels.get(field) || [];
+        // Prefer a field of the receiver's own type
+        const own = fn.receiver
+          ? candidates.find((id) => id === `${fn.receiver.type}.${field}`)
+          : null;
+        if (own) return own;
+        if (candidates.length > 0) return candidates[0];
+      }
+      if (!create) return null;
+      // Channels of unknown origin (e.g. returned by another function)
+      const id = add_channel(`${key}:${expr}`, {
+        name: expr,
+        kind: 'unknown',
+        scope: key,
+        type: null,
+        buffer: null,
+        filename: fn.filename,
+        line: null
+      });
+      local.set(expr, id);
+      return id;
+    };
+
+    const closures = find_goroutine_closures(masked);
+    const goroutine_at = (offset) => {
+      const closure = closures.find((c) => offset > c.start && offset < c.end);
+      return closure ? line_of(closure.start) : null;
+    };
+
+    const select_ranges = [];
+    const select_pattern = /\bselect\s*\{/g;
+    while ((match = select_pattern.exec(masked)) !== null) {
+      const open = masked.indexOf('{', match.index);
+      select_ranges.push({ start: open, end: find_matching(masked, open) });
+    }
+
+    const record = (channel, op, offset) => {
+      operations.push({
+        channel,
+        function: key,
+        op,
+        filename: fn.filename,
+        line: line_of(offset),
+        goroutine: goroutine_at(offset),
+        in_select: select_ranges.some(
+          (r) => offset > r.start && (r.end === -1 || offset < r.end)
+        )
+      });
+    };
+
+    // Sends: `ch <- value` (the operand is not itself preceded by `<-`)
+    const send_pattern = /([A-Za-z_][\w.]*)\s*<-(?!-)/g;
+    while ((match = send_pattern.exec(masked)) !== null) {
+      if (CHANNEL_KEYWORDS.has(match[1])) continue;
+      const before = masked.substring(0, match.index).trimEnd();
+      if (/(<-|\bchan)$/.test(before)) continue;
+      // `in <-chan T` is a parameter or variable declaration
+      const after = masked.substring(match.index + match[0].length);
+      if (/^\s*chan\b/.test(after)) continue;
+      record(resolve(match[1], true), 'send', match.index);
+    }
+
+    // Receives: `<-ch`, `v := <-ch`, `case <-ch:`
+    const receive_pattern = /<-\s*([A-Za-z_][\w.]*)/g;
+    while ((match = receive_pattern.exec(masked)) !== null) {
+      if (CHANNEL_KEYWORDS.has(match[1])) continue;
+      // An operand before `<-` on the same statement makes it a send
+      const statement_start =
+        Math.max(
+          masked.lastIndexOf('\n', match.index),
+          masked.lastIndexOf(';', match.index)
+        ) + 1;
+      const before = masked.substring(statement_start, match.index).trimEnd();
+      if (/[\w.)\]]$/.test(before) && !/\b(case|return)$/.test(before)) {
+        continue;
+      }
+      // `<-ch` followed by `(` is a receive from a call's result
+      const after = masked.substring(match.index + match[0].length);
+      if (/^\s*\(/.test(after)) continue;
+      record(resolve(match[1], true), 'receive', match.index);
+    }
+
+    const range_pattern = /\brange\s+([A-Za-z_][\w.]*)\s*\{/g;
+    while ((match = range_pattern.exec(masked)) !== null) {
+      const channel = resolve(match[1], false);
+      if (channel) record(channel, 'range', match.index);
+    }
+
+    const close_pattern = /\bclose\(\s*([A-Za-z_][\w.]*)\s*\)/g;
+    while ((match = close_pattern.exec(masked)) !== null) {
+      record(resolve(match[1], true), 'close', match.index);
+    }
+
+    // Calls that pass channels to other functions of the package
+    const launches = [];
+    const call_pattern = /(\bgo\s+)?\b([A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?)\s*\(/g;
+    while ((match = call_pattern.exec(masked)) !== null) {
+      const open = match.index + match[0].length - 1;
+      const close = find_matching(masked, open);
+      if (close === -1) continue;
+      const target = match[2];
+      if (target === 'func' || target === 'make' || target === 'close') {
+        continue;
+      }
+
+      const args = split_top_level(body.substring(open + 1, close));
+      const arg_channels = args
+        .map((arg) => resolve(arg, false))
+        .filter(Boolean);
+
+      const name = target.includes('.') ? target.split('.').pop() : target;
+      const callee = target.includes('.')
+        ? (methods_by_name.get(name) || [])[0]
+        : functions_by_name.get(name);
+      if (callee) {
+        callee.params.forEach(function bind_parameter(param, index) {
+          if (!param.name || classify_type(param.type) !== 'chan') return;
+          const channel = args[index] ? resolve(args[index], false) : null;
+          if (channel) {
+            bindings.push({
+              channel,
+              alias: `${get_function_key(callee)}:${param.name}`
+            });
+          }
+        });
+      }
+
+      if (match[1]) {
+        const launch = {
+          function: key,
+          target,
+          filename: fn.filename,
+          line: line_of(match.index),
+          channels: arg_channels
+        };
+        goroutines.push(launch);
+        launches.push(launch);
+      }
+    }
+
+    for (const closure of closures) {
+      const launch = {
+        function: key,
+        target: 'func literal',
+        filename: fn.filename,
+        line: line_of(closure.start),
+        channels: []
+      };
+      goroutines.push(launch);
+      launches.push(launch);
+    }
+
+    function_summaries.push({
+      name: key,
+      filename: fn.filename,
+      line: fn.line
+    });
+  }
+
+  // Merge channels passed as arguments with the callee's parameters
+  for (const binding of bindings) {
+    if (!channels.has(binding.alias)) continue;
+    const a = find(binding.channel);
+    const b = find(binding.alias);
+    if (a !== b) parent.set(b, a);
+  }
+
+  for (const op of operations) op.channel = find(op.channel);
+  operations.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+  for (const launch of goroutines) {
+    launch.channels = [...new Set(launch.channels.map(find))];
+  }
+
+  // Function literals use the channels operated on inside their body
+  for (const launch of goroutines) {
+    if (launch.target !== 'func literal') continue;
+    const inner = operations.filter(
+      (op) => op.function === launch.function && op.goroutine === launch.line
+    );
+    launch.channels = [...new Set(inner.map((op) => op.channel))];
+  }
+
+  const result_channels = [];
+  for (const [id, channel] of channels) {
+    if (find(id) !== id) continue;
+    const aliases = [...channels.keys()].filter(
+      (other) => other !== id && find(other) === id
+    );
+    const ops = operations.filter((op) => op.channel === id);
+    const senders = [
+      ...new Set(ops.filter((op) => op.op === 'send').map((op) => op.function))
+    ];
+    const receivers = [
+      ...new Set(
+        ops
+          .filter((op) => op.op === 'receive' || op.op === 'range')
+          .map((op) => op.function)
+      )
+    ];
+    const closers = [
+      ...new Set(ops.filter((op) => op.op === 'close').map((op) => op.function))
+    ];
+
+    let direction = 'unused';
+    if (senders.length > 0 && receivers.length > 0) direction = 'both';
+    else if (senders.length > 0) direction = 'send';
+    else if (receivers.length > 0) direction = 'receive';
+
+    result_channels.push({
+      ...channel,
+      element_type: channel.type
+        ? get_channel_element_type(channel.type)
+        : null,
+      declared_direction: channel.type
+        ? get_channel_direction(channel.type)
+        : null,
+      direction,
+      aliases,
+      senders,
+      receivers,
+      closers
+    });
+  }
+
+  // Channels that are only sent to or only received from may block forever
+  const warnings = [];
+  for (const channel of result_channels) {
+    // Other sides of package, field and unbound parameter channels may
+    // live outside of the package
+    if (channel.kind !== 'local') continue;
+    if (channel.senders.length > 0 && channel.receivers.length === 0) {
+      warnings.push({
+        type: 'no_receiver',
+        channel: channel.id,
+        filename: channel.filename,
+        line: channel.line,
+        message: `Channel '${channel.name}' is sent to but never received from; senders may block forever`
+      });
+    } else if (
+      channel.receivers.length > 0 &&
+      channel.senders.length === 0 &&
+      channel.closers.length === 0
+    ) {
+      warnings.push({
+        type: 'no_sender',
+        channel: channel.id,
+        filename: channel.filename,
+        line: channel.line,
+        message: `Channel '${channel.name}' is received from but never sent to or closed; receivers may block forever`
+      });
+    }
+  }
+
+  const functions = function_summaries
+    .map(function summarize_function(fn) {
+      const ops = operations.filter((op) => op.function === fn.name);
+      const ids = (kinds) => [
+        ...new Set(
+          ops.filter((op) => kinds.includes(op.op)).map((op) => op.channel)
+        )
+      ];
+      return {
+        ...fn,
+        sends: ids(['send']),
+        receives: ids(['receive', 'range']),
+        closes: ids(['close']),
+        launches: goroutines.filter((g) => g.function === fn.name).length
+      };
+    })
+    .filter(
+      (fn) =>
+        fn.sends.length > 0 ||
+        fn.receives.length > 0 ||
+        fn.closes.length > 0 ||
+        fn.launches > 0
+    );
+
+  return {
+    package: pkg.name,
+    directory: pkg.directory,
+    channels: result_channels,
+    operations,
+    goroutines,
+    functions,
+    warnings
+  };
+};
+
+/**
+ * Build the channel and goroutine model of a project's Go packages.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {string} [options.package] - Only model this package (name or directory)
+ * @param {string} [options.function] - Only include operations and launches of this function
+ * @returns {Promise<Object>} Concurrency models per package with a summary
+ */
+const analyze_project_channels = async (project_id, options = {}) => {
+  let packages = await load_go_packages(project_id);
+  if (options.package) {
+    packages = packages.filter(
+      (pkg) =>
+        pkg.name === options.package || pkg.directory === options.package
+    );
+  }
+
+  const models = packages.map(build_concurrency_model).map((model) => {
+    if (!options.function) return model;
+    const in_function = (item) =>
+      item.function === options.function || item.name === options.function;
+    const operations = model.operations.filter(in_function);
+    const used = new Set(operations.map((op) => op.channel));
+    return {
+      ...model,
+      channels: model.channels.filter((c) => used.has(c.id)),
+      operations,
+      goroutines: model.goroutines.filter(in_function),
+      functions: model.functions.filter(in_function),
+      warnings: model.warnings.filter((w) => used.has(w.channel))
+    };
+  });
+
+  const populated = models.filter(
+    (m) => m.channels.length > 0 || m.goroutines.length > 0
+  );
+
+  return {
+    packages: populated,
+    summary: {
+      packages_analyzed: packages.length,
+      packages_with_concurrency: populated.length,
+      total_channels: populated.reduce((n, m) => n + m.channels.length, 0),
+      total_operations: populated.reduce((n, m) => n + m.operations.length, 0),
+      total_goroutines: populated.reduce((n, m) => n + m.goroutines.length, 0),
+      total_warnings: populated.reduce((n, m) => n + m.warnings.length, 0)
+    }
+  };
+};
+
 export {
   analyze_project_concurrency,
   analyze_function_concurrency,
+  analyze_project_channels,
+  build_concurrency_model,
+  get_channel_direction,
+  get_channel_element_type,
   CONCURRENCY_PATTERNS
 };
@@ -0,0 +1,120 @@
+'use strict';
+
+/**
+ * @fileoverview Go build constraint module.
+ * Lists the effective build constraint of each Go file of a project: its
+ * `//go:build` (or `// +build`) expression ANDed with the GOOS and GOARCH
+ * implied by a `_linux`, `_amd64` or `_linux_amd64` filename suffix.
+ * Given a target platform, reports which files are built for it, so that
+ * platform specific analyses only look at the code the compiler would.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/constraints
+ */
+
+import {
+  evaluate_build_constraint,
+  get_target_tags,
+  load_go_files
+} from './golang.mjs';
+
+/**
+ * Count files by a key, skipping files without one.
+ * @param {Object[]} files - File constraints
+ * @param {string} key - Key to count by
+ * @returns {Object} Counts by value
+ */
+const count_by = (files, key) => {
+  const counts = {};
+  for (const file of files) {
+    if (!file[key]) continue;
+    counts[file[key]] = (counts[file[key]] || 0) + 1;
+  }
+  return counts;
+};
+
+/**
+ * Summarize the effective build constraints of parsed Go files.
+ * Without a target, only constrained files (by build line, filename
+ * suffix or both) and test files are listed.  With a target, every file
+ * is listed with whether it is built for the target.
+ * @param {Object[]} files - Parsed files (from parse_go_file)
+ * @param {Object} [options] - Options
+ * @param {string} [options.goos] - Target operating system
+ * @param {string} [options.goarch] - Target architecture
+ * @param {string[]} [options.tags] - Extra build tags satisfied by the target
+ * @returns {Object} Files with their effective constraint, and a summary
+ */
+const summarize_build_constraints = (files, options = {}) => {
+  const has_target = Boolean(
+    options.goos || options.goarch || (options.tags && options.tags.length)
+  );
+  const tags = get_target_tags(options);
+
+  const results = files
+    .map(function describe_file(file) {
+      const constraint = file.effective_constraint;
+      const result = {
+        filename: file.filename,
+        package: file.package,
+        expression: constraint.expression,
+        build: constraint.build,
+        goos: constraint.goos,
+        goarch: constraint.goarch,
+        test: constraint.test,
+        ignored: file.build_ignored
+      };
+      if (has_target) {
+        try {
+          result.included =
+            !file.build_ignored &&
+            evaluate_build_constraint(constraint.expression, tags);
+        } catch (error) {
+          result.included = false;
+          result.error = error.message;
+        }
+      }
+      return result;
+    })
+    .filter((f) => has_target || f.expression || f.test)
+    .sort(function sort_by_filename(a, b) {
+      return a.filename < b.filename ? -1 : 1;
+    });
+
+  const summary = {
+    total_files: files.length,
+    constrained_files: results.filter((f) => f.expression).length,
+    build_constrained: results.filter((f) => f.build).length,
+    filename_constrained: results.filter((f) => f.goos || f.goarch).length,
+    test_files: results.filter((f) => f.test).length,
+    by_goos: count_by(results, 'goos'),
+    by_goarch: count_by(results, 'goarch')
+  };
+  if (has_target) {
+    summary.included = results.filter((f) => f.included).length;
+    summary.excluded = results.length - summary.included;
+  }
+
+  return {
+    target: has_target
+      ? {
+          goos: options.goos || null,
+          goarch: options.goarch || null,
+          tags: [...tags].sort()
+        }
+      : null,
+    files: results,
+    summary
+  };
+};
+
+/**
+ * Summarize the effective build constraints of the Go files of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Target options (see summarize_build_constraints)
+ * @returns {Promise<Object>} Files with their effective constraint, and a summary
+ */
+const analyze_build_constraints = async (project_id, options = {}) => {
+  return summarize_build_constraints(await load_go_files(project_id), options);
+};
+
+export { analyze_build_constraints, summarize_build_constraints };
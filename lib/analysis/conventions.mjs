@@ -0,0 +1,670 @@
+'use strict';
+
+/**
+ * @fileoverview Go API convention analysis module.
+ * Checks that functions taking a `context.Context` take it as their first
+ * parameter, as Go style and the context package documentation require.
+ * Parameter types are resolved through each file's imports, so renamed
+ * and dot imports of "context" and aliases of its Context type are
+ * recognised.
+ *
+ * Functions returning an error must return it as their last result, as
+ * Go convention requires (`(float64, error)`, not `(error, float64)`).
+ * Signatures returning several errors are flagged too: callers cannot
+ * tell which one to check.
+ *
+ * The "accept interfaces, return structs" guideline is checked on exported
+ * functions and methods, as an opt-in heuristic:
+ * - accept_interfaces: a parameter of a project struct type (`s *Store`)
+ *   whose only uses in the body are method calls could be an interface,
+ *   decoupling callers from the implementation.  The interfaces of the
+ *   project satisfied by the type and declaring the methods called are
+ *   suggested.  Parameters whose fields are read, or which are passed on
+ *   or compared, need the concrete type and are not reported.
+ * - return_structs: a result of a project interface type (other than
+ *   error) could be the concrete type when every return statement returns
+ *   a composite literal of the same exported struct (`return &Store{}`),
+ *   giving callers its full method set.  Unexported concrete types are
+ *   hidden deliberately and are not reported.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/conventions
+ */
+
+import {
+  mask_source,
+  find_matching,
+  parse_parameters,
+  parse_results,
+  split_top_level,
+  load_go_packages
+} from './golang.mjs';
+import {
+  get_imported_packages,
+  get_interface_methods,
+  get_type_methods
+} from './graph.mjs';
+import { find_method_sets } from './interfaces.mjs';
+import { strip_function_literals } from './inlining.mjs';
+
+/**
+ * Guidelines of the "accept interfaces, return structs" check.
+ */
+const INTERFACE_GUIDELINES = ['accept_interfaces', 'return_structs'];
+
+// ============================================================================
+// TYPE RESOLUTION
+// ============================================================================
+
+/**
+ * Get the names the "context" package is imported as, by file.
+ * A dot import is recorded as the empty qualifier.
+ * @param {Object[]} imports - Package imports with filename
+ * @returns {Map<string, Set<string>>} Qualifiers by filename
+ */
+const get_context_qualifiers = (imports) => {
+  const qualifiers = new Map();
+  for (const imp of imports) {
+    if (imp.path !== 'context' || imp.name === '_') continue;
+    if (!qualifiers.has(imp.filename)) {
+      qualifiers.set(imp.filename, new Set());
+    }
+    const name = imp.name === '.' ? '' : imp.name || 'context';
+    qualifiers.get(imp.filename).add(name);
+  }
+  return qualifiers;
+};
+
+/**
+ * Check whether a type expression is `context.Context`.
+ * @param {string} type_text - Type expression
+ * @param {Set<string>} qualifiers - Names "context" is imported as
+ * @param {Set<string>} [aliases] - Package types aliasing context.Context
+ * @returns {boolean} True for context.Context
+ */
+const is_context_type = (type_text, qualifiers, aliases = new Set()) => {
+  const text = (type_text || '').replace(/\s+/g, '');
+  if (aliases.has(text)) return true;
+  const match = text.match(/^(?:([A-Za-z_]\w*)\.)?Context$/);
+  return Boolean(match) && qualifiers.has(match[1] || '');
+};
+
+/**
+ * Find the types of a package that alias context.Context
+ * (`type Context = context.Context`).
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Map<string, Set<string>>} qualifiers - Qualifiers by filename
+ * @returns {Set<string>} Alias names
+ */
+const get_context_aliases = (pkg, qualifiers) => {
+  const aliases = new Set();
+  for (const type of pkg.types) {
+    if (type.kind !== 'alias') continue;
+    const names = qualifiers.get(type.filename) || new Set();
+    if (is_context_type(type.underlying, names)) aliases.add(type.name);
+  }
+  return aliases;
+};
+
+/**
+ * Parse the parameters of a function type (`func(a int, ctx context.Context)`).
+ * @param {string} type_text - Function type expression
+ * @returns {Object[]} Parameters (see parse_parameters)
+ */
+const parse_func_type_params = (type_text) => {
+  const masked = mask_source(type_text);
+  const open = masked.indexOf('(');
+  const close = open === -1 ? -1 : find_matching(masked, open);
+  if (close === -1) return [];
+  return parse_parameters(type_text.substring(open + 1, close));
+};
+
+/**
+ * Parse the results of a function type (`func(a int) (int, error)`).
+ * @param {string} type_text - Function type expression
+ * @returns {Object[]} Results (see parse_results)
+ */
+const parse_func_type_results = (type_text) => {
+  const masked = mask_source(type_text);
+  const open = masked.indexOf('(');
+  const close = open === -1 ? -1 : find_matching(masked, open);
+  if (close === -1) return [];
+  return parse_results(type_text.substring(close + 1));
+};
+
+// ============================================================================
+// CONTEXT PARAMETER CHECK
+// ============================================================================
+
+/**
+ * List the signatures of a package that can take parameters: functions,
+ * methods, interface methods and function types.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object[]} Signatures with symbol, kind, filename, line, params and results
+ */
+const list_package_signatures = (pkg) => {
+  const signatures = [];
+
+  for (const fn of pkg.functions) {
+    signatures.push({
+      symbol: fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name,
+      kind: fn.receiver ? 'method' : 'function',
+      filename: fn.filename,
+      line: fn.line,
+      exported: fn.exported,
+      params: fn.params,
+      results: fn.results
+    });
+  }
+
+  for (const type of pkg.types) {
+    for (const method of type.methods) {
+      signatures.push({
+        symbol: `${type.name}.${method.name}`,
+        kind: 'interface_method',
+        filename: type.filename,
+        line: method.line,
+        exported: type.exported && /^[A-Z]/.test(method.name),
+        params: method.params,
+        results: method.results
+      });
+    }
+    if (type.kind === 'func') {
+      signatures.push({
+        symbol: type.name,
+        kind: 'func_type',
+        filename: type.filename,
+        line: type.line,
+        exported: type.exported,
+        params: parse_func_type_params(type.underlying),
+        results: parse_func_type_results(type.underlying)
+      });
+    }
+  }
+
+  return signatures;
+};
+
+/**
+ * Check the position of the context parameter of each function of a
+ * package.  Functions without a context are not reported.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.allow_types_before=[]] - Parameter types allowed before the context (e.g. `*testing.T`)
+ * @returns {Object} Functions taking a context and the misplaced ones
+ */
+const check_context_params = (pkg, options = {}) => {
+  const allowed = new Set(
+    (options.allow_types_before || []).map((t) => t.replace(/\s+/g, ''))
+  );
+  const qualifiers = get_context_qualifiers(pkg.imports);
+  const aliases = get_context_aliases(pkg, qualifiers);
+  const with_context = [];
+  const misplaced = [];
+
+  for (const signature of list_package_signatures(pkg)) {
+    const names = qualifiers.get(signature.filename) || new Set();
+    const position = signature.params.findIndex(
+      (p) => !p.variadic && is_context_type(p.type, names, aliases)
+    );
+    if (position === -1) continue;
+    with_context.push(signature.symbol);
+
+    const before = signature.params.slice(0, position);
+    const allowed_before = before.every((p) =>
+      allowed.has(p.type.replace(/\s+/g, ''))
+    );
+    if (allowed_before) continue;
+
+    const param = signature.params[position];
+    misplaced.push({
+      symbol: signature.symbol,
+      kind: signature.kind,
+      filename: signature.filename,
+      line: signature.line,
+      exported: signature.exported,
+      parameter: param.name,
+      // 1-based, as in "the third parameter"
+      position: position + 1,
+      parameter_count: signature.params.length,
+      message: `${signature.symbol} takes context.Context as parameter ${position + 1} of ${signature.params.length}; it should be the first`
+    });
+  }
+
+  return {
+    package: pkg.name,
+    directory: pkg.directory,
+    with_context,
+    misplaced
+  };
+};
+
+/**
+ * Check the context parameter convention across a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see check_context_params)
+ * @returns {Promise<Object>} Per-package results with a summary
+ */
+const analyze_project_context_params = async (project_id, options = {}) => {
+  const packages = (await load_go_packages(project_id))
+    .map((pkg) => check_context_params(pkg, options))
+    .filter((pkg) => pkg.with_context.length > 0);
+  const misplaced = packages.flatMap((p) => p.misplaced);
+
+  return {
+    packages,
+    summary: {
+      packages_analyzed: packages.length,
+      functions_with_context: packages.reduce(
+        (sum, p) => sum + p.with_context.length,
+        0
+      ),
+      misplaced: misplaced.length,
+      exported_misplaced: misplaced.filter((m) => m.exported).length
+    }
+  };
+};
+
+// ============================================================================
+// ERROR RESULT CHECK
+// ============================================================================
+
+/**
+ * Check the position of the error result of each function of a package:
+ * it must be the last result, and the only error.  Functions without an
+ * error result are not reported.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object} Functions returning an error and the misplaced ones
+ */
+const check_error_results = (pkg) => {
+  const with_error = [];
+  const misplaced = [];
+
+  for (const signature of list_package_signatures(pkg)) {
+    const results = signature.results;
+    const positions = [];
+    results.forEach((r, i) => {
+      if (r.type === 'error') positions.push(i + 1);
+    });
+    if (positions.length === 0) continue;
+    with_error.push(signature.symbol);
+
+    const last = positions[positions.length - 1] === results.length;
+    if (positions.length === 1 && last) continue;
+
+    const issue = positions.length > 1 ? 'multiple' : 'misplaced';
+    const message =
+      issue === 'multiple'
+        ? `${signature.symbol} returns ${positions.length} errors (results ${positions.join(', ')} of ${results.length}); it should return one, last`
+        : `${signature.symbol} returns error as result ${positions[0]} of ${results.length}; it should be the last`;
+    misplaced.push({
+      symbol: signature.symbol,
+      kind: signature.kind,
+      filename: signature.filename,
+      line: signature.line,
+      exported: signature.exported,
+      issue,
+      // 1-based, as in "the first result"
+      position: positions[0],
+      positions,
+      result_count: results.length,
+      message
+    });
+  }
+
+  return {
+    package: pkg.name,
+    directory: pkg.directory,
+    with_error,
+    misplaced
+  };
+};
+
+/**
+ * Check the error result convention across a project.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Per-package results with a summary
+ */
+const analyze_project_error_results = async (project_id) => {
+  const packages = (await load_go_packages(project_id))
+    .map((pkg) => check_error_results(pkg))
+    .filter((pkg) => pkg.with_error.length > 0);
+  const misplaced = packages.flatMap((p) => p.misplaced);
+
+  return {
+    packages,
+    summary: {
+      packages_analyzed: packages.length,
+      functions_with_error: packages.reduce(
+        (sum, p) => sum + p.with_error.length,
+        0
+      ),
+      misplaced: misplaced.filter((m) => m.issue === 'misplaced').length,
+      multiple: misplaced.filter((m) => m.issue === 'multiple').length,
+      exported_misplaced: misplaced.filter((m) => m.exported).length
+    }
+  };
+};
+
+// ============================================================================
+// ACCEPT INTERFACES, RETURN STRUCTS
+// ============================================================================
+
+/**
+ * Resolve a named type expression (`Store`, `*Store`, `store.Store[K]`) to
+ * the project type it names, in the package or the project packages
+ * imported by the file.
+ * @param {string} type_text - Type expression
+ * @param {Object} pkg - Package of the expression
+ * @param {string} filename - File of the expression
+ * @param {Object[]} packages - All project packages
+ * @returns {Object|null} Type, its package and pointer flag, or null
+ */
+const resolve_project_type = (type_text, pkg, filename, packages) => {
+  const match = (type_text || '')
+    .replace(/\s+/g, '')
+    .match(/^(\*)?(?:([A-Za-z_]\w*)\.)?([A-Za-z_]\w*)(?:\[.*\])?$/);
+  if (!match) return null;
+  const type_pkg = match[2]
+    ? get_imported_packages(pkg, filename, packages).get(match[2])
+    : pkg;
+  const type = type_pkg && type_pkg.types.find((t) => t.name === match[3]);
+  return type ? { type, pkg: type_pkg, pointer: Boolean(match[1]) } : null;
+};
+
+/**
+ * Find the methods called on a parameter in a function body.  Any other
+ * use of the parameter (a field read, passing it on, comparing it) needs
+ * its concrete type.
+ * @param {string} masked - Masked function body
+ * @param {string} name - Parameter name
+ * @param {Map<string, Object>} methods - Methods of the parameter type
+ * @returns {string[]|null} Sorted names of the methods called, or null if the parameter has other uses
+ */
+const find_param_method_uses = (masked, name, methods) => {
+  const used = new Set();
+  const pattern = new RegExp(`(?<![\\w.])${name}(?!\\w)`, 'g');
+  let match;
+  while ((match = pattern.exec(masked)) !== null) {
+    const rest = masked.substring(pattern.lastIndex);
+    const selector = rest.match(/^\s*\.\s*([A-Za-z_]\w*)/);
+    if (!selector || !methods.has(selector[1])) return null;
+    used.add(selector[1]);
+  }
+  return [...used].sort();
+};
+
+/**
+ * Find the interfaces of the project a type satisfies that declare a set
+ * of methods, smallest first.
+ * @param {Object} resolved - Resolved type (from resolve_project_type)
+ * @param {string[]} methods - Method names the interfaces must declare
+ * @param {Object[]} packages - All project packages
+ * @returns {string[]} Interfaces, qualified by package name
+ */
+const find_covering_interfaces = (resolved, methods, packages) => {
+  let sets;
+  try {
+    sets = find_method_sets(
+      packages,
+      `${resolved.pkg.directory}.${resolved.type.name}`
+    );
+  } catch {
+    return [];
+  }
+
+  const covering = [];
+  for (const satisfied of sets.interfaces) {
+    if (!resolved.pointer && !satisfied.value) continue;
+    const iface_pkg = packages.find((p) => p.directory === satisfied.directory);
+    const iface = iface_pkg.types.find((t) => t.name === satisfied.interface);
+    const declared = get_interface_methods(iface, iface_pkg);
+    if (!declared || !methods.every((m) => declared.has(m))) continue;
+    covering.push({
+      name: `${satisfied.package}.${satisfied.interface}`,
+      size: declared.size
+    });
+  }
+  return covering
+    .sort(function sort_by_size(a, b) {
+      return a.size - b.size || a.name.localeCompare(b.name);
+    })
+    .map((i) => i.name);
+};
+
+/**
+ * Check the parameters of a function against "accept interfaces": the
+ * parameters of a project struct type only used for method calls.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {Object} pkg - Package of the function
+ * @param {Object[]} packages - All project packages
+ * @returns {Object[]} Parameters with their type, methods used and suggested interfaces
+ */
+const check_accept_interfaces = (fn, pkg, packages) => {
+  if (!fn.body) return [];
+  const masked = strip_function_literals(mask_source(fn.body));
+  const issues = [];
+
+  fn.params.forEach(function check_param(param, index) {
+    if (!param.name || param.name === '_' || param.variadic) return;
+    const resolved = resolve_project_type(
+      param.type,
+      pkg,
+      fn.filename,
+      packages
+    );
+    if (!resolved || resolved.type.kind !== 'struct') return;
+    const methods = get_type_methods(
+      resolved.type.name,
+      resolved.pkg,
+      new Set(),
+      resolved.pointer
+    );
+    const used = find_param_method_uses(masked, param.name, methods);
+    if (!used || used.length === 0) return;
+
+    issues.push({
+      parameter: param.name,
+      position: index + 1,
+      type: param.type,
+      methods_used: used,
+      interfaces: find_covering_interfaces(resolved, used, packages)
+    });
+  });
+
+  return issues;
+};
+
+/**
+ * Get the expressions of the return statements of a function body,
+ * leaving out those of function literals.
+ * @param {string} body - Function body
+ * @returns {string[][]|null} Returned expressions by statement, or null if a statement returns named results
+ */
+const get_return_expressions = (body) => {
+  const masked = strip_function_literals(mask_source(body));
+  const statements = [];
+  const pattern = /\breturn\b/g;
+  let match;
+  while ((match = pattern.exec(masked)) !== null) {
+    // The statement ends at a newline, a semicolon or the end of its block,
+    // outside of its brackets
+    let end = pattern.lastIndex;
+    while (end < masked.length && !'\n;}'.includes(masked[end])) {
+      if ('([{'.includes(masked[end])) {
+        const close = find_matching(masked, end);
+        if (close === -1) break;
+        end = close;
+      }
+      end++;
+    }
+    const text = body.substring(pattern.lastIndex, end);
+    if (!text.trim()) return null;
+    statements.push(split_top_level(text));
+  }
+  return statements;
+};
+
+/**
+ * Check the results of a function against "return structs": the results
+ * of a project interface type always returned as the same exported
+ * struct.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {Object} pkg - Package of the function
+ * @param {Object[]} packages - All project packages
+ * @returns {Object[]} Results with their interface and concrete type
+ */
+const check_return_structs = (fn, pkg, packages) => {
+  if (!fn.body) return [];
+  const statements = get_return_expressions(fn.body);
+  if (!statements) return [];
+  const issues = [];
+
+  fn.results.forEach(function check_result(result, index) {
+    if (result.type === 'error') return;
+    const iface = resolve_project_type(
+      result.type,
+      pkg,
+      fn.filename,
+      packages
+    );
+    if (!iface || iface.pointer || iface.type.kind !== 'interface') return;
+
+    const concrete = new Set();
+    for (const expressions of statements) {
+      if (expressions.length !== fn.results.length) return;
+      const expression = expressions[index].trim();
+      if (expression === 'nil') continue;
+      const literal = expression.match(/^(&?)\s*([\w.]+)\s*(?:\[[^\]]*\])?\s*\{/);
+      if (!literal) return;
+      concrete.add(`${literal[1] ? '*' : ''}${literal[2]}`);
+    }
+    if (concrete.size !== 1) return;
+
+    const type = [...concrete][0];
+    const resolved = resolve_project_type(type, pkg, fn.filename, packages);
+    if (!resolved || resolved.type.kind !== 'struct') return;
+    if (!resolved.type.exported) return;
+
+    issues.push({
+      result: result.name,
+      position: index + 1,
+      type: result.type,
+      concrete: type
+    });
+  });
+
+  return issues;
+};
+
+/**
+ * Check the exported functions and methods of a set of packages against
+ * the "accept interfaces, return structs" guideline.  Functions of test
+ * files are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string} [options.guideline] - Only check one guideline (accept_interfaces or return_structs)
+ * @returns {Object[]} Issues by file and line
+ * @throws {Error} If the guideline is unknown
+ */
+const find_interface_guideline_issues = (packages, options = {}) => {
+  if (options.guideline && !INTERFACE_GUIDELINES.includes(options.guideline)) {
+    throw new Error(
+      `Unknown guideline '${options.guideline}' (expected one of: ${INTERFACE_GUIDELINES.join(', ')})`
+    );
+  }
+  const checks = (guideline) =>
+    !options.guideline || options.guideline === guideline;
+  const issues = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (!fn.exported || fn.filename.endsWith('_test.go')) continue;
+      if (fn.receiver && !/^[A-Z]/.test(fn.receiver.type)) continue;
+      const symbol = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      const location = {
+        symbol,
+        kind: fn.receiver ? 'method' : 'function',
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line
+      };
+
+      if (checks('accept_interfaces')) {
+        for (const issue of check_accept_interfaces(fn, pkg, packages)) {
+          const suggestion =
+            issue.interfaces.length > 0
+              ? `accept ${issue.interfaces[0]}`
+              : `accept an interface with ${issue.methods_used.join(', ')}`;
+          issues.push({
+            ...location,
+            guideline: 'accept_interfaces',
+            ...issue,
+            message: `${symbol} takes ${issue.parameter} ${issue.type} but only calls its methods; ${suggestion}`
+          });
+        }
+      }
+
+      if (checks('return_structs')) {
+        for (const issue of check_return_structs(fn, pkg, packages)) {
+          issues.push({
+            ...location,
+            guideline: 'return_structs',
+            ...issue,
+            message: `${symbol} returns ${issue.type} but always returns ${issue.concrete}; return the concrete type`
+          });
+        }
+      }
+    }
+  }
+
+  return issues.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line || a.position - b.position;
+  });
+};
+
+/**
+ * Check the "accept interfaces, return structs" guideline across a
+ * project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_interface_guideline_issues)
+ * @returns {Promise<Object>} Issues with a summary
+ * @throws {Error} If the guideline is unknown
+ */
+const analyze_project_interface_guideline = async (
+  project_id,
+  options = {}
+) => {
+  const issues = find_interface_guideline_issues(
+    await load_go_packages(project_id),
+    options
+  );
+  const count = (guideline) =>
+    issues.filter((i) => i.guideline === guideline).length;
+
+  return {
+    issues,
+    summary: {
+      total_issues: issues.length,
+      accept_interfaces: count('accept_interfaces'),
+      return_structs: count('return_structs')
+    }
+  };
+};
+
+export {
+  analyze_project_interface_guideline,
+  find_interface_guideline_issues,
+  INTERFACE_GUIDELINES,
+  resolve_project_type,
+  find_param_method_uses,
+  find_covering_interfaces,
+  analyze_project_error_results,
+  check_error_results,
+  analyze_project_context_params,
+  check_context_params,
+  list_package_signatures,
+  is_context_type,
+  get_context_qualifiers
+};
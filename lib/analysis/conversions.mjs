@@ -0,0 +1,568 @@
+'use strict';
+
+/**
+ * @fileoverview Go conversion and type assertion module.
+ * Lists the type conversions (`Fahrenheit(c*9/5 + 32)`) and type
+ * assertions (`x.(T)`) of each function.  Single-value assertions panic
+ * when the dynamic type does not match, so they are reported on their
+ * own with the safer comma-ok form (`v, ok := x.(T)`) to use instead.
+ *
+ * Conversions between two given types, in both directions, are listed
+ * for auditing unit confusion (a `Celsius` reinterpreted as `Fahrenheit`
+ * without the formula).  The type converted from is inferred, best
+ * effort, from the declared types of variables, fields, constants and
+ * function results.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/conversions
+ */
+
+import {
+  find_go_assertions,
+  find_go_conversions,
+  mask_source,
+  find_matching,
+  get_base_type,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Get the named types a package can convert to: its own types and the
+ * exported types of the other packages, qualified by package name.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} pkg - Package containing the code
+ * @returns {Set<string>} Type names
+ */
+const get_conversion_types = (packages, pkg) => {
+  const names = new Set(pkg.types.map((t) => t.name));
+  for (const other of packages) {
+    if (other === pkg || !other.name) continue;
+    for (const type of other.types.filter((t) => t.exported)) {
+      names.add(`${other.name}.${type.name}`);
+    }
+  }
+  return names;
+};
+
+/**
+ * Find the conversions and type assertions of the functions of a set of
+ * packages.  Functions using neither are not listed.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Functions with their conversions, assertions and unchecked assertion count
+ */
+const find_type_conversions = (packages) => {
+  const functions = [];
+
+  for (const pkg of packages) {
+    const type_names = get_conversion_types(packages, pkg);
+
+    for (const fn of pkg.functions) {
+      if (!fn.body) continue;
+      const conversions = find_go_conversions(
+        fn.body,
+        fn.body_line,
+        type_names,
+        fn.body_column
+      );
+      const assertions = find_go_assertions(fn.body, fn.body_line);
+      if (conversions.length === 0 && assertions.length === 0) continue;
+
+      const receiver = fn.receiver ? get_base_type(fn.receiver.type) : null;
+      functions.push({
+        name: receiver ? `${receiver}.${fn.name}` : fn.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        conversions,
+        assertions,
+        unchecked_assertions: assertions.filter((a) => !a.checked).length
+      });
+    }
+  }
+
+  return functions;
+};
+
+/**
+ * List the single-value type assertions of functions, which panic when
+ * the dynamic type does not match.
+ * @param {Object[]} functions - Functions (from find_type_conversions)
+ * @returns {Object[]} Unchecked assertions with their function and a suggestion
+ */
+const list_unchecked_assertions = (functions) => {
+  return functions.flatMap((fn) =>
+    fn.assertions
+      .filter((a) => !a.checked)
+      .map((assertion) => ({
+        function: fn.name,
+        package: fn.package,
+        directory: fn.directory,
+        filename: fn.filename,
+        line: assertion.line,
+        expression: assertion.expression,
+        type: assertion.type,
+        message: `${assertion.expression}.(${assertion.type}) panics if ${assertion.expression} is not a ${assertion.type}`,
+        suggestion: `v, ok := ${assertion.expression}.(${assertion.type})`
+      }))
+  );
+};
+
+/**
+ * Report the conversions and type assertions of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.unchecked_only=false] - Only list functions with unchecked assertions
+ * @returns {Promise<Object>} Functions, unchecked assertions and a summary
+ */
+const analyze_project_conversions = async (project_id, options = {}) => {
+  let functions = find_type_conversions(await load_go_packages(project_id));
+  const unchecked = list_unchecked_assertions(functions);
+
+  const by_type = {};
+  const assertions = functions.flatMap((fn) => fn.assertions);
+  for (const conversion of functions.flatMap((fn) => fn.conversions)) {
+    by_type[conversion.type] = (by_type[conversion.type] || 0) + 1;
+  }
+  const summary = {
+    functions: functions.length,
+    conversions: functions.reduce((sum, fn) => sum + fn.conversions.length, 0),
+    assertions: assertions.filter((a) => a.form !== 'switch').length,
+    type_switches: assertions.filter((a) => a.form === 'switch').length,
+    unchecked_assertions: unchecked.length,
+    conversions_by_type: by_type
+  };
+
+  if (options.unchecked_only) {
+    functions = functions.filter((fn) => fn.unchecked_assertions > 0);
+  }
+  return { functions, unchecked_assertions: unchecked, summary };
+};
+
+// ============================================================================
+// CONVERSIONS BETWEEN TYPES
+// ============================================================================
+
+/**
+ * Binary operators; their operands have the type of the expression.
+ */
+const BINARY_OPERATORS = new Set(['+', '-', '*', '/', '%', '&', '|', '^']);
+
+/**
+ * Remove the parentheses enclosing a whole expression (`(c + 1)`).
+ * @param {string} expression - Expression
+ * @returns {string} Expression without enclosing parentheses
+ */
+const strip_parentheses = (expression) => {
+  let text = expression.trim();
+  while (
+    text.startsWith('(') &&
+    find_matching(mask_source(text), 0) === text.length - 1
+  ) {
+    text = text.substring(1, text.length - 1).trim();
+  }
+  return text;
+};
+
+/**
+ * Split an expression into the operands of its top level binary
+ * arithmetic operators (`(f - 32) * 5 / 9` has three operands).
+ * @param {string} expression - Expression
+ * @returns {string[]} Operands, or the expression itself
+ */
+const split_operands = (expression) => {
+  const masked = mask_source(expression);
+  const operands = [];
+  let depth = 0;
+  let start = 0;
+  let previous = '';
+
+  for (let i = 0; i < masked.length; i++) {
+    const ch = masked[i];
+    if ('([{'.includes(ch)) depth++;
+    else if (')]}'.includes(ch)) depth--;
+
+    // An operator after an operand is binary, otherwise unary
+    const shift = (ch === '<' || ch === '>') && masked[i + 1] === ch;
+    if (
+      depth === 0 &&
+      (BINARY_OPERATORS.has(ch) || shift) &&
+      /[\w)\]]/.test(previous)
+    ) {
+      operands.push(expression.substring(start, i));
+      if (shift || (ch === '&' && masked[i + 1] === '^')) i++;
+      start = i + 1;
+      previous = '';
+      continue;
+    }
+    if (!/\s/.test(ch)) previous = ch;
+  }
+  operands.push(expression.substring(start));
+
+  return operands.map((o) => o.trim()).filter(Boolean);
+};
+
+/**
+ * Build the scope in which the expressions of a package are typed: its
+ * named types, the types of its constants, variables and struct fields,
+ * and the results of its functions and methods.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Set<string>} type_names - Convertible type names (from get_conversion_types)
+ * @returns {Object} Scope with types, values, fields and results
+ */
+const build_type_scope = (pkg, type_names) => {
+  const values = new Map();
+  for (const decl of [...pkg.consts, ...pkg.vars]) {
+    for (const name of decl.names) {
+      if (decl.type) values.set(name, decl.type);
+    }
+  }
+
+  const fields = new Map();
+  for (const type of pkg.types) {
+    const by_name = new Map();
+    for (const field of type.fields) {
+      for (const name of field.names) by_name.set(name, field.type);
+    }
+    fields.set(type.name, by_name);
+  }
+
+  const results = new Map([
+    ['len', 'int'],
+    ['cap', 'int']
+  ]);
+  for (const fn of pkg.functions) {
+    if (fn.results.length !== 1) continue;
+    const receiver = fn.receiver ? `${get_base_type(fn.receiver.type)}.` : '';
+    results.set(`${receiver}${fn.name}`, fn.results[0].type);
+  }
+
+  return { types: type_names, values, fields, results };
+};
+
+/**
+ * Get the element types of ranging over a collection type: index and
+ * element of slices and arrays, key and value of maps.
+ * @param {string} type - Collection type
+ * @returns {string[]|null} Key and value types, or null if unknown
+ */
+const get_range_types = (type) => {
+  const slice = (type || '').match(/^\[[^\]]*\]\s*(.+)$/);
+  if (slice) return ['int', slice[1].trim()];
+  const map = (type || '').match(/^map\[([^\]]+)\]\s*(.+)$/);
+  return map ? [map[1].trim(), map[2].trim()] : null;
+};
+
+/**
+ * Get the element type of indexing a collection type (`[]T`, `map[K]T`).
+ * @param {string} type - Collection type
+ * @returns {string|null} Element type, or null if unknown
+ */
+const get_element_type = (type) => {
+  const types = get_range_types(type);
+  return types ? types[1] : null;
+};
+
+/**
+ * Split a masked operand into its chain of names, each possibly called or
+ * indexed (`r.Value`, `Celsius(c).ToFahrenheit()`, `values[0]`).
+ * @param {string} masked - Masked operand
+ * @returns {Object[]|null} Names with call and index flags, or null for other operands
+ */
+const split_selector_chain = (masked) => {
+  const chain = [];
+  const name_pattern = /\s*([A-Za-z_]\w*)\s*/y;
+  let pos = 0;
+
+  while (pos < masked.length) {
+    name_pattern.lastIndex = pos;
+    const match = name_pattern.exec(masked);
+    if (!match) return null;
+    const part = { name: match[1], call: false, index: false };
+    pos = name_pattern.lastIndex;
+
+    while (masked[pos] === '(' || masked[pos] === '[') {
+      const close = find_matching(masked, pos);
+      if (close === -1) return null;
+      if (masked[pos] === '(') part.call = true;
+      else part.index = true;
+      pos = close + 1;
+      while (/\s/.test(masked[pos] || '')) pos++;
+    }
+    chain.push(part);
+
+    if (pos >= masked.length) break;
+    if (masked[pos] !== '.') return null;
+    pos++;
+  }
+
+  return chain.length > 0 ? chain : null;
+};
+
+/**
+ * Infer the type of an expression, best effort: conversions, calls of
+ * functions and methods with one result, indexing, and typed variables,
+ * constants and fields.  Arithmetic has the type of its first
+ * typed operand, untyped constants taking the type of the others.
+ * @param {string} expression - Expression
+ * @param {Object} scope - Scope (from build_type_scope)
+ * @param {Map<string, string>} variables - Types of the function variables
+ * @returns {string|null} Type, or null if unknown
+ */
+const infer_expression_type = (expression, scope, variables) => {
+  const text = strip_parentheses(expression);
+  if (!text) return null;
+  const masked = mask_source(text);
+  const infer = (e) => infer_expression_type(e, scope, variables);
+
+  const operands = split_operands(text);
+  if (operands.length > 1) {
+    for (const operand of operands) {
+      const type = infer(operand);
+      if (type) return type;
+    }
+    return null;
+  }
+
+  if (/^[-+^]/.test(masked)) return infer(text.substring(1));
+  if (masked.startsWith('*')) {
+    const type = infer(text.substring(1));
+    return type && type.startsWith('*') ? type.substring(1) : null;
+  }
+
+  const chain = split_selector_chain(masked);
+  if (!chain) return null;
+
+  // The first name is a variable, a constant, or with the next one a
+  // qualified type or function (`time.Duration(n)`)
+  let [{ name, call, index }, ...rest] = chain;
+  let type = null;
+  if (!variables.has(name) && !scope.values.has(name) && rest.length > 0) {
+    const qualified = `${name}.${rest[0].name}`;
+    if (scope.types.has(qualified) || scope.results.has(qualified)) {
+      ({ call, index } = rest.shift());
+      name = qualified;
+    }
+  }
+  if (call) {
+    type = scope.types.has(name) ? name : scope.results.get(name) || null;
+  } else {
+    type = variables.get(name) || scope.values.get(name) || null;
+  }
+  if (index) type = get_element_type(type);
+
+  for (const part of rest) {
+    if (!type) return null;
+    const base = get_base_type(type);
+    if (part.call) {
+      type = scope.results.get(`${base}.${part.name}`) || null;
+    } else {
+      const fields = scope.fields.get(base);
+      type = fields ? fields.get(part.name) || null : null;
+    }
+    if (part.index) type = get_element_type(type);
+  }
+  return type;
+};
+
+/**
+ * Find the types of the variables of a function: receiver, parameters and
+ * variables declared in its body, in the order they are declared.
+ * Variables declared without a type take the inferred type of their
+ * value.  Shadowing is not modeled.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @param {Object} scope - Scope (from build_type_scope)
+ * @returns {Map<string, string>} Types by variable name
+ */
+const find_variable_types = (fn, scope) => {
+  const variables = new Map();
+  if (fn.receiver && fn.receiver.name) {
+    const pointer = fn.receiver.pointer ? '*' : '';
+    variables.set(fn.receiver.name, `${pointer}${fn.receiver.type}`);
+  }
+  for (const param of fn.params) {
+    if (!param.name) continue;
+    variables.set(param.name, param.variadic ? `[]${param.type}` : param.type);
+  }
+  if (!fn.body) return variables;
+
+  // var x T, var x = value, x := value and k, v := range collection, in
+  // the order they appear
+  const masked = mask_source(fn.body);
+  const declare =
+    /\bvar\s+(\w+(?:\s*,\s*\w+)*)[ \t]*([^=;\n]*?)[ \t]*(?:=[ \t]*([^;\n]+))?(?=[;\n])/dg;
+  const define = /(?<![\w.])(\w+(?:\s*,\s*\w+)?)\s*:=\s*(range\s+)?([^;\n]+)/dg;
+  const of_kind = (pattern, declared) =>
+    [...masked.matchAll(pattern)].map((match) => ({ match, declared }));
+  const matches = [...of_kind(declare, true), ...of_kind(define, false)].sort(
+    (a, b) => a.match.index - b.match.index
+  );
+
+  for (const { match, declared } of matches) {
+    const text_of = (group) => {
+      const [start, end] = match.indices[group];
+      return fn.body.substring(start, end).trim();
+    };
+    const names = match[1].split(',').map((n) => n.trim());
+
+    if (declared && match[2].trim()) {
+      for (const name of names) variables.set(name, match[2].trim());
+    } else if (match[2] && !declared) {
+      const collection = text_of(3).replace(/\s*\{[^{]*$/, '');
+      const types = get_range_types(
+        infer_expression_type(collection, scope, variables)
+      );
+      names.forEach(function set_range_type(name, index) {
+        if (types && name !== '_') variables.set(name, types[index]);
+      });
+    } else if (names.length === 1 && match[3]) {
+      const type = infer_expression_type(text_of(3), scope, variables);
+      if (type) variables.set(names[0], type);
+    }
+  }
+
+  return variables;
+};
+
+/**
+ * Qualify a type with the name of the package declaring it, when it is a
+ * type of the package (`Celsius` is `temperature.Celsius`).
+ * @param {string} type - Type
+ * @param {Object} pkg - Package where the type appears
+ * @returns {string} Qualified type
+ */
+const qualify_type = (type, pkg) => {
+  if (type.includes('.') || !pkg.types.some((t) => t.name === type)) {
+    return type;
+  }
+  return `${pkg.name}.${type}`;
+};
+
+/**
+ * Check whether a qualified type is the type asked for, given qualified
+ * (`temperature.Celsius`) or not (`Celsius`).
+ * @param {string} qualified - Qualified type
+ * @param {string} requested - Requested type
+ * @returns {boolean} True if the types match
+ */
+const is_requested_type = (qualified, requested) => {
+  return qualified === requested || qualified.endsWith(`.${requested}`);
+};
+
+/**
+ * Find the conversions between two types across a set of packages, in
+ * both directions: `To(x)` where x is a `From`, and `From(x)` where x is
+ * a `To`.  Types are named alone (`Celsius`) or qualified
+ * (`temperature.Celsius`); predeclared types (`float64`) are accepted.
+ * Conversions to either type whose operand type cannot be inferred are
+ * counted as unresolved.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} from - Type converted from
+ * @param {string} to - Type converted to
+ * @returns {Object} Conversion spans, by file and line, and the unresolved count
+ */
+const find_conversions_between = (packages, from, to) => {
+  const conversions = [];
+  let unresolved = 0;
+
+  for (const pkg of packages) {
+    const type_names = get_conversion_types(packages, pkg);
+    const scope = build_type_scope(pkg, type_names);
+
+    for (const fn of pkg.functions) {
+      if (!fn.body) continue;
+      let variables = null;
+      const receiver = fn.receiver ? get_base_type(fn.receiver.type) : null;
+
+      for (const conversion of find_go_conversions(
+        fn.body,
+        fn.body_line,
+        type_names,
+        fn.body_column
+      )) {
+        const target = qualify_type(conversion.type, pkg);
+        const forward = is_requested_type(target, to);
+        const reverse = is_requested_type(target, from);
+        if (!forward && !reverse) continue;
+
+        if (!variables) variables = find_variable_types(fn, scope);
+        const type = infer_expression_type(
+          conversion.expression,
+          scope,
+          variables
+        );
+        if (!type) {
+          unresolved++;
+          continue;
+        }
+        const source = qualify_type(type, pkg);
+        const direction = forward && is_requested_type(source, from)
+          ? 'forward'
+          : reverse && is_requested_type(source, to)
+            ? 'reverse'
+            : null;
+        if (!direction) continue;
+
+        conversions.push({
+          function: receiver ? `${receiver}.${fn.name}` : fn.name,
+          package: pkg.name,
+          directory: pkg.directory,
+          filename: fn.filename,
+          line: conversion.line,
+          column: conversion.column,
+          end_line: conversion.end_line,
+          end_column: conversion.end_column,
+          from: source,
+          to: target,
+          direction,
+          expression: conversion.expression,
+          text: `${conversion.type}(${conversion.expression})`
+        });
+      }
+    }
+  }
+
+  conversions.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line || a.column - b.column;
+  });
+  return { conversions, unresolved };
+};
+
+/**
+ * Report the conversions between two types in a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {string} from - Type converted from
+ * @param {string} to - Type converted to
+ * @returns {Promise<Object>} Conversion spans and a summary by direction
+ */
+const analyze_project_conversions_between = async (project_id, from, to) => {
+  const { conversions, unresolved } = find_conversions_between(
+    await load_go_packages(project_id),
+    from,
+    to
+  );
+
+  return {
+    from,
+    to,
+    conversions,
+    summary: {
+      total_conversions: conversions.length,
+      forward: conversions.filter((c) => c.direction === 'forward').length,
+      reverse: conversions.filter((c) => c.direction === 'reverse').length,
+      functions: new Set(conversions.map((c) => `${c.directory}.${c.function}`))
+        .size,
+      unresolved
+    }
+  };
+};
+
+export {
+  analyze_project_conversions,
+  analyze_project_conversions_between,
+  find_conversions_between,
+  infer_expression_type,
+  find_type_conversions,
+  list_unchecked_assertions,
+  get_conversion_types
+};
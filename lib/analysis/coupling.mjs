@@ -0,0 +1,185 @@
+'use strict';
+
+/**
+ * @fileoverview Go function coupling module.
+ * Computes the classic coupling metrics of each function from the call
+ * graph of the whole code base:
+ * - fan-out: the number of distinct functions it calls
+ * - fan-in: the number of distinct functions calling it
+ * An entry point such as main has a high fan-out, and a widely used
+ * helper a high fan-in.  Functions with both are coupling hotspots, where
+ * a change spreads in both directions; they are ranked by their combined
+ * fan-in and fan-out.  This complements complexity, which measures a
+ * function on its own, with how it is tied to the rest of the code.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/coupling
+ */
+
+import { load_go_packages } from './golang.mjs';
+import { find_call_edges, get_node_id } from './graph.mjs';
+
+/**
+ * Number of functions listed as most coupled by default.
+ */
+const DEFAULT_COUPLING_LIMIT = 20;
+
+// ============================================================================
+// FAN-IN AND FAN-OUT
+// ============================================================================
+
+/**
+ * Compare functions by combined fan-in and fan-out, then by their product
+ * (a function both calling and called is more coupled than one doing only
+ * one of them), then by id.
+ * @param {Object} a - Function metrics
+ * @param {Object} b - Function metrics
+ * @returns {number} Sort order
+ */
+const sort_by_coupling = (a, b) => {
+  return (
+    b.coupling - a.coupling ||
+    b.fan_in * b.fan_out - a.fan_in * a.fan_out ||
+    a.id.localeCompare(b.id)
+  );
+};
+
+/**
+ * Compute the fan-in and fan-out of every function and method of a set of
+ * packages.  Only calls resolved to a declaration of the packages count
+ * (see find_call_edges), each called or calling function once however
+ * many times it is called.  Functions of test files are left out, so that
+ * tests do not count as callers.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.include_tests=false] - Include functions of test files
+ * @returns {Object[]} Functions with fan-in, fan-out, callers and callees, by file and line
+ */
+const compute_fan_metrics = (packages, options = {}) => {
+  const functions = new Map();
+  const calls = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (!options.include_tests && fn.filename.endsWith('_test.go')) continue;
+      const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      const id = get_node_id(pkg.directory, name);
+      functions.set(id, {
+        id,
+        name,
+        kind: fn.receiver ? 'method' : 'function',
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        callers: new Set(),
+        callees: new Set()
+      });
+      calls.push(...find_call_edges(fn, pkg, packages));
+    }
+  }
+
+  for (const edge of calls) {
+    const source = functions.get(edge.source);
+    const target = functions.get(edge.target);
+    if (!source || !target) continue;
+    source.callees.add(edge.target);
+    target.callers.add(edge.source);
+  }
+
+  return [...functions.values()]
+    .map(function format_metrics(fn) {
+      return {
+        ...fn,
+        fan_in: fn.callers.size,
+        fan_out: fn.callees.size,
+        coupling: fn.callers.size + fn.callees.size,
+        callers: [...fn.callers].sort(),
+        callees: [...fn.callees].sort()
+      };
+    })
+    .sort(function sort_by_location(a, b) {
+      if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+      return a.line - b.line;
+    });
+};
+
+/**
+ * Rank functions by coupling, leaving out those neither calling nor called.
+ * @param {Object[]} functions - Function metrics (from compute_fan_metrics)
+ * @param {number} [limit=20] - Number of functions to keep
+ * @returns {Object[]} The most coupled functions, most coupled first
+ */
+const rank_by_coupling = (functions, limit = DEFAULT_COUPLING_LIMIT) => {
+  return functions
+    .filter((fn) => fn.coupling > 0)
+    .sort(sort_by_coupling)
+    .slice(0, limit);
+};
+
+/**
+ * Find the most coupled functions of a set of packages.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options (see compute_fan_metrics)
+ * @param {number} [options.limit=20] - Number of functions to list
+ * @returns {Object[]} The most coupled functions, most coupled first
+ */
+const find_top_coupled = (packages, options = {}) => {
+  return rank_by_coupling(
+    compute_fan_metrics(packages, options),
+    options.limit || DEFAULT_COUPLING_LIMIT
+  );
+};
+
+/**
+ * Report the fan-in and fan-out of the functions of a project, with the
+ * most coupled ones.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_top_coupled)
+ * @returns {Promise<Object>} Functions, most coupled functions and a summary
+ */
+const analyze_project_coupling = async (project_id, options = {}) => {
+  const functions = compute_fan_metrics(
+    await load_go_packages(project_id),
+    options
+  );
+  const top_coupled = rank_by_coupling(
+    functions,
+    options.limit || DEFAULT_COUPLING_LIMIT
+  );
+
+  const average = (key) =>
+    functions.length > 0
+      ? Math.round(
+          (functions.reduce((sum, fn) => sum + fn[key], 0) /
+            functions.length) *
+            10
+        ) / 10
+      : 0;
+  const highest = (key) => {
+    const top = functions.reduce(
+      (best, fn) => (!best || fn[key] > best[key] ? fn : best),
+      null
+    );
+    return top && top[key] > 0 ? { id: top.id, [key]: top[key] } : null;
+  };
+
+  return {
+    functions,
+    top_coupled,
+    summary: {
+      total_functions: functions.length,
+      average_fan_in: average('fan_in'),
+      average_fan_out: average('fan_out'),
+      highest_fan_in: highest('fan_in'),
+      highest_fan_out: highest('fan_out'),
+      isolated: functions.filter((fn) => fn.coupling === 0).length
+    }
+  };
+};
+
+export {
+  analyze_project_coupling,
+  find_top_coupled,
+  compute_fan_metrics,
+  DEFAULT_COUPLING_LIMIT
+};
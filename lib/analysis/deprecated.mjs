@@ -0,0 +1,186 @@
+'use strict';
+
+/**
+ * @fileoverview Go deprecated standard library module.
+ * Flags the calls to deprecated functions of the standard library
+ * (`ioutil.ReadFile`, `rand.Seed`, `strings.Title`) with the Go version
+ * deprecating them and their recommended replacement, to keep a code base
+ * current.  Calls are resolved through the imports of each file, so a
+ * renamed import (`import iu "io/ioutil"`) is followed and a local
+ * package or variable of the same name is not mistaken for the standard
+ * library one.
+ *
+ * The list of deprecated functions is kept here as data, by import path:
+ * update DEPRECATED_FUNCTIONS as the standard library deprecates more.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/deprecated
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  parse_go_file,
+  load_go_sources
+} from './golang.mjs';
+import { get_import_name, get_package_names } from './imports.mjs';
+import { find_enclosing_declaration } from './literals.mjs';
+
+/**
+ * Deprecated functions of the standard library by import path, with the
+ * Go version deprecating them and their replacement (null when the
+ * functionality has no replacement).
+ */
+const DEPRECATED_FUNCTIONS = {
+  'io/ioutil': {
+    ReadAll: { since: '1.16', replacement: 'io.ReadAll' },
+    ReadFile: { since: '1.16', replacement: 'os.ReadFile' },
+    WriteFile: { since: '1.16', replacement: 'os.WriteFile' },
+    ReadDir: { since: '1.16', replacement: 'os.ReadDir' },
+    NopCloser: { since: '1.16', replacement: 'io.NopCloser' },
+    TempFile: { since: '1.17', replacement: 'os.CreateTemp' },
+    TempDir: { since: '1.17', replacement: 'os.MkdirTemp' }
+  },
+  'math/rand': {
+    Seed: {
+      since: '1.20',
+      replacement: 'rand.New(rand.NewSource(seed)) for a seeded generator'
+    },
+    Read: { since: '1.20', replacement: 'crypto/rand.Read' }
+  },
+  strings: {
+    Title: { since: '1.18', replacement: 'golang.org/x/text/cases.Title' }
+  },
+  bytes: {
+    Title: { since: '1.18', replacement: 'golang.org/x/text/cases.Title' }
+  },
+  reflect: {
+    PtrTo: { since: '1.22', replacement: 'reflect.PointerTo' }
+  },
+  'crypto/elliptic': {
+    Marshal: { since: '1.21', replacement: 'crypto/ecdh' },
+    Unmarshal: { since: '1.21', replacement: 'crypto/ecdh' },
+    GenerateKey: { since: '1.21', replacement: 'crypto/ecdh' }
+  },
+  'crypto/x509': {
+    ParseCRL: { since: '1.19', replacement: 'x509.ParseRevocationList' },
+    ParseDERCRL: { since: '1.19', replacement: 'x509.ParseRevocationList' },
+    DecryptPEMBlock: { since: '1.16', replacement: null },
+    EncryptPEMBlock: { since: '1.16', replacement: null },
+    IsEncryptedPEMBlock: { since: '1.16', replacement: null }
+  },
+  'go/importer': {
+    For: { since: '1.12', replacement: 'importer.ForCompiler' }
+  },
+  'net/http/httputil': {
+    NewClientConn: { since: '1.0', replacement: 'net/http.Client' },
+    NewServerConn: { since: '1.0', replacement: 'net/http.Server' }
+  }
+};
+
+// ============================================================================
+// DEPRECATED CALLS
+// ============================================================================
+
+/**
+ * Format the message of a deprecated call.
+ * @param {string} call - Call as written (`ioutil.ReadFile`)
+ * @param {Object} deprecation - Deprecation (from DEPRECATED_FUNCTIONS)
+ * @returns {string} Message
+ */
+const format_deprecation = (call, deprecation) => {
+  const replacement = deprecation.replacement
+    ? `use ${deprecation.replacement}`
+    : 'it has no replacement';
+  return `${call} is deprecated since Go ${deprecation.since}: ${replacement}`;
+};
+
+/**
+ * Find the calls to deprecated standard library functions of a Go source.
+ * Blank and dot imports are left out.
+ * @param {string} source - Go source
+ * @param {string} [filename=''] - Filename of the source
+ * @param {Map<string, string>} [package_names] - Package names by directory (see get_package_names)
+ * @returns {Object[]} Deprecated calls with their replacement, in order
+ */
+const find_deprecated_calls = (source, filename = '', package_names) => {
+  const text = source || '';
+  const masked = mask_source(text);
+  const line_index = build_line_index(text);
+  const file = parse_go_file(text, filename);
+  const calls = [];
+
+  for (const imp of file.imports) {
+    const functions = DEPRECATED_FUNCTIONS[imp.path];
+    if (!functions || imp.name === '_' || imp.name === '.') continue;
+    const { name } = get_import_name(imp, package_names);
+    const pattern = new RegExp(
+      `(?<![\\w.])${name}\\s*\\.\\s*(${Object.keys(functions).join('|')})\\s*\\(`,
+      'g'
+    );
+
+    let match;
+    while ((match = pattern.exec(masked)) !== null) {
+      const deprecation = functions[match[1]];
+      const call = `${name}.${match[1]}`;
+      const line = line_at(line_index, match.index);
+      calls.push({
+        function: `${imp.path}.${match[1]}`,
+        call,
+        filename,
+        line,
+        column: match.index - line_index[line - 1] + 1,
+        enclosing: find_enclosing_declaration(file, line),
+        since: deprecation.since,
+        replacement: deprecation.replacement,
+        message: format_deprecation(call, deprecation),
+        start: match.index
+      });
+    }
+  }
+
+  return calls
+    .sort(function sort_by_offset(a, b) {
+      return a.start - b.start;
+    })
+    .map(function strip_offset({ start, ...call }) {
+      return call;
+    });
+};
+
+/**
+ * Find the calls to deprecated standard library functions of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Deprecated calls with a summary
+ */
+const analyze_project_deprecated_calls = async (project_id) => {
+  const sources = await load_go_sources(project_id);
+  const package_names = get_package_names(sources);
+  const calls = [];
+  for (const row of sources) {
+    calls.push(
+      ...find_deprecated_calls(row.source, row.filename, package_names)
+    );
+  }
+
+  const by_function = {};
+  for (const call of calls) {
+    by_function[call.function] = (by_function[call.function] || 0) + 1;
+  }
+
+  return {
+    calls,
+    summary: {
+      total_calls: calls.length,
+      files: new Set(calls.map((call) => call.filename)).size,
+      by_function
+    }
+  };
+};
+
+export {
+  analyze_project_deprecated_calls,
+  find_deprecated_calls,
+  format_deprecation,
+  DEPRECATED_FUNCTIONS
+};
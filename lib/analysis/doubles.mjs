@@ -0,0 +1,221 @@
+'use strict';
+
+/**
+ * @fileoverview Go test double module.
+ * Verifies that every exported interface of a code base has at least one
+ * test double: a mock, fake or stub type implementing it, declared in a
+ * `_test.go` file or in a package of doubles (`mocks`, `fakes`, `stubs`,
+ * `storemock`).  An interface without a double is harder to test
+ * against, which nudges towards testable designs.
+ *
+ * Doubles are recognized by implementation, not by name: any type of a
+ * test file or doubles package whose pointer method set satisfies the
+ * interface, as in `go vet`'s view of implements.  Interfaces are
+ * compared with their types qualified when the double lives in another
+ * package (`store.Entry` in an external `store_test` package).  Empty
+ * interfaces and interfaces embedding one of another package are left
+ * out, as their method set is not known.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/doubles
+ */
+
+import {
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import {
+  get_method_key,
+  get_interface_methods,
+  get_interface_method_set,
+  get_pointer_method_set,
+  get_value_method_set
+} from './graph.mjs';
+import { qualify_method_types } from './interfaces.mjs';
+
+/**
+ * Names of packages holding test doubles (`mocks`, `fake`, `storemock`).
+ */
+const DOUBLE_PACKAGE_PATTERN = /^(mocks?|fakes?|stubs?)$|mocks?$/;
+
+// ============================================================================
+// TEST DOUBLES
+// ============================================================================
+
+/**
+ * Check whether a type of a package is a candidate test double: declared
+ * in a test file, or in a package of doubles.
+ * @param {Object} type - Type (from the Go parser)
+ * @param {Object} pkg - Package of the type
+ * @returns {boolean} True for a candidate double
+ */
+const is_double_candidate = (type, pkg) => {
+  if (type.kind === 'interface' || type.kind === 'alias') return false;
+  return (
+    type.filename.endsWith('_test.go') ||
+    DOUBLE_PACKAGE_PATTERN.test(pkg.name || '')
+  );
+};
+
+/**
+ * Get the method set of an interface, as compared with the types of its
+ * package and with those of other packages (qualified).
+ * @param {Object} iface - Interface type
+ * @param {Object} pkg - Package of the interface
+ * @returns {Object|null} Local and qualified method keys, or null if unknown
+ */
+const get_double_method_keys = (iface, pkg) => {
+  const local = get_interface_method_set(iface, pkg);
+  const methods = get_interface_methods(iface, pkg);
+  if (!local || !methods || local.size === 0) return null;
+  return {
+    local,
+    qualified: new Set(
+      [...methods.values()].map((m) =>
+        get_method_key(qualify_method_types(m, pkg))
+      )
+    )
+  };
+};
+
+/**
+ * Find the test doubles of the exported interfaces of a set of packages.
+ * The packages must include the test files.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object} Interfaces with their doubles, and the interfaces skipped (empty, or with an unknown method set)
+ */
+const find_test_doubles = (packages) => {
+  const candidates = [];
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (!is_double_candidate(type, pkg)) continue;
+      candidates.push({
+        type,
+        pkg,
+        pointer_keys: get_pointer_method_set(type.name, pkg),
+        value_keys: get_value_method_set(type.name, pkg)
+      });
+    }
+  }
+
+  const interfaces = [];
+  const skipped = [];
+  for (const pkg of packages) {
+    if (DOUBLE_PACKAGE_PATTERN.test(pkg.name || '')) continue;
+    for (const iface of pkg.types) {
+      if (iface.kind !== 'interface' || !iface.exported) continue;
+      if (iface.filename.endsWith('_test.go')) continue;
+      const keys = get_double_method_keys(iface, pkg);
+      if (!keys) {
+        skipped.push(`${pkg.directory}.${iface.name}`);
+        continue;
+      }
+
+      const doubles = [];
+      for (const candidate of candidates) {
+        const same_package = candidate.pkg === pkg;
+        const expected = same_package ? keys.local : keys.qualified;
+        const satisfied_by = (set) => [...expected].every((k) => set.has(k));
+        if (!satisfied_by(candidate.pointer_keys)) continue;
+        doubles.push({
+          type: candidate.type.name,
+          package: candidate.pkg.name,
+          directory: candidate.pkg.directory,
+          filename: candidate.type.filename,
+          line: candidate.type.line,
+          pointer_only: !satisfied_by(candidate.value_keys)
+        });
+      }
+      interfaces.push({
+        interface: iface.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: iface.filename,
+        line: iface.line,
+        methods: keys.local.size,
+        has_double: doubles.length > 0,
+        doubles
+      });
+    }
+  }
+
+  return {
+    interfaces: interfaces.sort(function sort_by_interface(a, b) {
+      return (
+        a.directory.localeCompare(b.directory) ||
+        a.interface.localeCompare(b.interface)
+      );
+    }),
+    skipped: skipped.sort()
+  };
+};
+
+/**
+ * Summarize the test doubles of a set of interfaces.
+ * @param {Object[]} interfaces - Interfaces (from find_test_doubles)
+ * @returns {Object} Numbers of interfaces with and without doubles, and the coverage in percent
+ */
+const summarize_test_doubles = (interfaces) => {
+  const with_double = interfaces.filter((i) => i.has_double).length;
+  return {
+    interfaces: interfaces.length,
+    with_double,
+    without_double: interfaces.length - with_double,
+    coverage:
+      interfaces.length === 0
+        ? 100
+        : Math.round((with_double / interfaces.length) * 1000) / 10
+  };
+};
+
+/**
+ * Build a test double report of a set of packages.  The summary covers
+ * every interface, whatever the options.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.missing_only=false] - Only list interfaces without a double
+ * @returns {Object} Interfaces with their doubles, skipped interfaces and a summary
+ */
+const build_test_double_report = (packages, options = {}) => {
+  const { interfaces, skipped } = find_test_doubles(packages);
+  return {
+    interfaces: options.missing_only
+      ? interfaces.filter((i) => !i.has_double)
+      : interfaces,
+    skipped,
+    summary: summarize_test_doubles(interfaces)
+  };
+};
+
+/**
+ * Report the test doubles of the exported interfaces of a set of Go
+ * sources, such as the files of a directory, test files included.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see build_test_double_report)
+ * @returns {Object} Interfaces with their doubles and a summary
+ */
+const find_source_test_doubles = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  return build_test_double_report(group_go_packages(files), options);
+};
+
+/**
+ * Report the test doubles of the exported interfaces of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see build_test_double_report)
+ * @returns {Promise<Object>} Interfaces with their doubles and a summary
+ */
+const analyze_project_test_doubles = async (project_id, options = {}) => {
+  const packages = await load_go_packages(project_id);
+  return build_test_double_report(packages, options);
+};
+
+export {
+  analyze_project_test_doubles,
+  find_source_test_doubles,
+  find_test_doubles,
+  summarize_test_doubles,
+  DOUBLE_PACKAGE_PATTERN
+};
@@ -0,0 +1,353 @@
+'use strict';
+
+/**
+ * @fileoverview Go error handling analysis module.
+ * Finds the error values returned by Go functions and classifies how each
+ * one is produced (wrapped with %w, returned as-is, annotated without
+ * wrapping, newly created or a sentinel), then checks that each package
+ * follows a consistent wrapping convention.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/errors
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  split_top_level,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Supported error wrapping conventions.
+ * - auto: the majority pattern of each package is expected
+ * - wrap: propagated errors are wrapped with %w
+ * - no-wrap: propagated errors are returned as-is
+ */
+const WRAPPING_CONVENTIONS = ['auto', 'wrap', 'no-wrap'];
+
+/**
+ * Return site categories that deviate from each convention.
+ */
+const DEVIATING_CATEGORIES = {
+  wrap: ['raw', 'annotated'],
+  'no-wrap': ['wrapped']
+};
+
+/**
+ * Calls that wrap an error, keeping it available to errors.Is/As.
+ */
+const WRAPPING_CALLS =
+  /^(errors\.(Wrap|Wrapf|WithMessage|WithMessagef|WithStack)|xerrors\.Errorf)$/;
+
+// ============================================================================
+// RETURN SITES
+// ============================================================================
+
+/**
+ * Find the offset ranges of function literals in masked source.
+ * @param {string} masked - Masked source
+ * @returns {Object[]} Ranges with the offsets of the literal's braces
+ */
+const find_func_literal_ranges = (masked) => {
+  const ranges = [];
+  const pattern = /\bfunc\s*\(/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    let pos = find_matching(masked, match.index + match[0].length - 1);
+    if (pos === -1) break;
+
+    // Skip the results, which may be parenthesized or generic
+    for (pos++; pos < masked.length && masked[pos] !== '{'; pos++) {
+      if (masked[pos] === '(' || masked[pos] === '[') {
+        pos = find_matching(masked, pos);
+        if (pos === -1) return ranges;
+      } else if (masked[pos] === '\n' || masked[pos] === ')') {
+        break;
+      }
+    }
+    if (masked[pos] !== '{') continue;
+
+    const close = find_matching(masked, pos);
+    if (close === -1) break;
+    ranges.push({ open: pos, close });
+  }
+
+  return ranges;
+};
+
+/**
+ * Find the return statements of a function body, excluding those of
+ * nested function literals.
+ * @param {string} body - Function body including its braces
+ * @param {number} [body_line=1] - Line of the opening brace
+ * @returns {Object[]} Return statements with line and values
+ */
+const find_return_statements = (body, body_line = 1) => {
+  const masked = mask_source(body);
+  const line_index = build_line_index(body);
+  const literals = find_func_literal_ranges(masked);
+  const returns = [];
+  const pattern = /(^|[;{}\s])return\b/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    const start = match.index + match[1].length;
+    if (literals.some((r) => start > r.open && start < r.close)) continue;
+
+    // The statement ends at a newline, ';' or '}' outside of brackets
+    let end = start + 'return'.length;
+    for (; end < masked.length; end++) {
+      const ch = masked[end];
+      if (ch === '(' || ch === '[' || ch === '{') {
+        const close = find_matching(masked, end);
+        if (close === -1) break;
+        end = close;
+      } else if (ch === '\n' || ch === ';' || ch === '}') {
+        // A trailing operator or comma continues the expression
+        const before = masked.substring(start, end).trimEnd();
+        if (ch === '\n' && /[,+\-*/&|.]$/.test(before)) continue;
+        break;
+      }
+    }
+
+    const expression = body.substring(start + 'return'.length, end).trim();
+    returns.push({
+      line: body_line + line_at(line_index, start) - 1,
+      expression,
+      values: expression ? split_top_level(expression) : []
+    });
+    pattern.lastIndex = end;
+  }
+
+  return returns;
+};
+
+/**
+ * Classify how a returned error value is produced.
+ * - nil: no error
+ * - wrapped: fmt.Errorf with %w (or a wrapping helper such as errors.Wrap)
+ * - annotated: fmt.Errorf that formats an error without %w, losing it
+ * - raw: an error variable returned as-is
+ * - new: a newly created error (errors.New, fmt.Errorf without an error)
+ * - sentinel: a package level or qualified error variable (ErrNotFound, io.EOF)
+ * - call: the result of another call, returned as-is
+ * - other: anything else
+ * @param {string} value - Returned expression
+ * @param {Object} [options] - Options
+ * @param {Set<string>} [options.package_vars] - Names of package level variables
+ * @param {Set<string>} [options.error_names] - Local names known to hold errors
+ * @returns {string} Category
+ */
+const classify_error_value = (value, options = {}) => {
+  const package_vars = options.package_vars || new Set();
+  const error_names = options.error_names || new Set(['err']);
+  const text = value.trim();
+
+  if (text === 'nil') return 'nil';
+
+  const call = text.match(/^([\w.]+)\s*\(([\s\S]*)\)$/);
+  if (call) {
+    const [, callee, args_text] = call;
+    if (WRAPPING_CALLS.test(callee)) return 'wrapped';
+    if (callee === 'errors.New') return 'new';
+    if (callee === 'fmt.Errorf') {
+      const [format, ...args] = split_top_level(args_text);
+      if (/%w/.test(format || '')) return 'wrapped';
+      const formats_error = args.some((a) =>
+        error_names.has(a.replace(/\.Error\(\)$/, ''))
+      );
+      return formats_error ? 'annotated' : 'new';
+    }
+    return 'call';
+  }
+
+  if (/^\w+$/.test(text)) {
+    if (package_vars.has(text) || /^Err[A-Z]/.test(text)) return 'sentinel';
+    return 'raw';
+  }
+  if (/^\w+\.\w+$/.test(text)) {
+    return /^\w+\.(Err|EOF)/.test(text) ? 'sentinel' : 'other';
+  }
+  if (/^&?\w+(\.\w+)?\s*\{/.test(text)) return 'new';
+
+  return 'other';
+};
+
+/**
+ * Check whether a function returns an error as its last result.
+ * @param {Object} fn - Function (from the Go parser)
+ * @returns {boolean} True if the last result is of type error
+ */
+const returns_error = (fn) => {
+  const last = fn.results[fn.results.length - 1];
+  return Boolean(last && last.type === 'error');
+};
+
+/**
+ * Find the error return sites of a function.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {Object} [options] - Options
+ * @param {Set<string>} [options.package_vars] - Names of package level variables
+ * @returns {Object[]} Return sites with line, expression and category
+ */
+const find_error_returns = (fn, options = {}) => {
+  if (!fn.body || !returns_error(fn)) return [];
+
+  const error_names = new Set(['err']);
+  const last = fn.results[fn.results.length - 1];
+  if (last.name) error_names.add(last.name);
+  for (const match of fn.body.matchAll(/\b(\w*[eE]rr\w*)\s*:?=/g)) {
+    error_names.add(match[1]);
+  }
+
+  return find_return_statements(fn.body, fn.body_line)
+    .filter((r) => r.values.length === fn.results.length)
+    .map(function classify_return(r) {
+      const value = r.values[r.values.length - 1];
+      return {
+        line: r.line,
+        expression: value,
+        category: classify_error_value(value, {
+          package_vars: options.package_vars,
+          error_names
+        })
+      };
+    });
+};
+
+// ============================================================================
+// WRAPPING CONVENTIONS
+// ============================================================================
+
+/**
+ * Build the message for a return site that deviates from a convention.
+ * @param {Object} site - Error return site
+ * @param {string} convention - Expected convention (wrap or no-wrap)
+ * @returns {string} Message
+ */
+const get_deviation_message = (site, convention) => {
+  if (convention === 'no-wrap') {
+    return `Error is wrapped with ${site.expression.split('(')[0]}, but this package returns errors unwrapped`;
+  }
+  if (site.category === 'annotated') {
+    return 'Error is formatted without %w, so callers cannot unwrap it; use %w';
+  }
+  return `Error '${site.expression}' is returned without wrapping; wrap it with fmt.Errorf("...: %w", ${site.expression})`;
+};
+
+/**
+ * Check a package's error return sites against a wrapping convention.
+ * Only sites that propagate an existing error (wrapped, annotated and
+ * raw) take part; nil, new, sentinel and call returns are ignored.
+ * @param {Object[]} sites - Error return sites (with function and filename)
+ * @param {string} [expected='auto'] - Expected convention (auto, wrap or no-wrap)
+ * @returns {Object} Convention check with counts, detected convention and deviations
+ */
+const check_wrapping_convention = (sites, expected = 'auto') => {
+  const counts = {};
+  for (const site of sites) {
+    counts[site.category] = (counts[site.category] || 0) + 1;
+  }
+
+  const wrapped = counts.wrapped || 0;
+  const unwrapped = (counts.raw || 0) + (counts.annotated || 0);
+  let detected = 'none';
+  if (wrapped > 0 || unwrapped > 0) {
+    if (wrapped > unwrapped) detected = 'wrap';
+    else if (unwrapped > wrapped) detected = 'no-wrap';
+    else detected = 'mixed';
+  }
+
+  const convention = expected === 'auto' ? detected : expected;
+  const deviating = DEVIATING_CATEGORIES[convention] || [];
+
+  const deviations = sites
+    .filter((site) => deviating.includes(site.category))
+    .map(function format_deviation(site) {
+      return { ...site, message: get_deviation_message(site, convention) };
+    });
+
+  return {
+    counts,
+    propagated: wrapped + unwrapped,
+    detected,
+    convention,
+    consistent: deviations.length === 0 && detected !== 'mixed',
+    deviations
+  };
+};
+
+/**
+ * Analyze the error wrapping convention of a package.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string} [options.expected='auto'] - Expected convention (auto, wrap or no-wrap)
+ * @returns {Object} Package convention check
+ */
+const analyze_package_error_wrapping = (pkg, options = {}) => {
+  const package_vars = new Set(pkg.vars.flatMap((v) => v.names));
+  const sites = [];
+
+  for (const fn of pkg.functions) {
+    const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+    for (const site of find_error_returns(fn, { package_vars })) {
+      sites.push({ function: name, filename: fn.filename, ...site });
+    }
+  }
+
+  return {
+    package: pkg.name,
+    directory: pkg.directory,
+    return_sites: sites.length,
+    ...check_wrapping_convention(sites, options.expected)
+  };
+};
+
+/**
+ * Analyze the error wrapping conventions of a project's Go packages.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {string} [options.expected='auto'] - Expected convention (auto, wrap or no-wrap)
+ * @returns {Promise<Object>} Per-package results with a summary
+ */
+const analyze_project_error_wrapping = async (project_id, options = {}) => {
+  const expected = options.expected || 'auto';
+  if (!WRAPPING_CONVENTIONS.includes(expected)) {
+    throw new Error(
+      `Unknown convention '${expected}' (expected one of: ${WRAPPING_CONVENTIONS.join(', ')})`
+    );
+  }
+
+  const packages = (await load_go_packages(project_id))
+    .map((pkg) => analyze_package_error_wrapping(pkg, { expected }))
+    .filter((pkg) => pkg.return_sites > 0);
+
+  return {
+    expected,
+    packages,
+    summary: {
+      packages_analyzed: packages.length,
+      consistent_packages: packages.filter((p) => p.consistent).length,
+      mixed_packages: packages.filter((p) => p.detected === 'mixed').length,
+      total_return_sites: packages.reduce((sum, p) => sum + p.return_sites, 0),
+      total_deviations: packages.reduce(
+        (sum, p) => sum + p.deviations.length,
+        0
+      )
+    }
+  };
+};
+
+export {
+  analyze_project_error_wrapping,
+  analyze_package_error_wrapping,
+  check_wrapping_convention,
+  find_error_returns,
+  find_return_statements,
+  find_func_literal_ranges,
+  classify_error_value,
+  WRAPPING_CONVENTIONS
+};
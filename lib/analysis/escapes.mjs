@@ -0,0 +1,226 @@
+'use strict';
+
+/**
+ * @fileoverview Go escaping pointer module.
+ * Finds the return statements of Go functions that return the address of
+ * a variable of the function (`return &x`).  This is legal Go: escape
+ * analysis moves the variable to the heap.  It is reported as
+ * informational, for those learning the language and for allocation
+ * sensitive code, with the kind of variable that escapes:
+ * - local: a variable declared in the function
+ * - param: a parameter or value receiver, a copy of the caller's value
+ * - range: a range variable, a copy of the element rather than the
+ *   element itself
+ * - composite: a composite literal (`&Config{...}`), the idiomatic way to
+ *   allocate a value on the heap
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/escapes
+ */
+
+import { mask_source, get_base_type, load_go_packages } from './golang.mjs';
+import { find_return_statements } from './errors.mjs';
+
+/**
+ * Kinds of escaping addresses.
+ */
+const ESCAPE_KINDS = ['local', 'param', 'range', 'composite'];
+
+/**
+ * The address of a variable, optionally of one of its fields (`&c.cfg`).
+ */
+const ADDRESS_PATTERN = /^&\s*([A-Za-z_]\w*)((?:\s*\.\s*[A-Za-z_]\w*)*)$/;
+
+/**
+ * The address of a composite literal (`&Config{`, `&pkg.Config{`).
+ */
+const COMPOSITE_PATTERN = /^&\s*([A-Za-z_][\w.]*(?:\[[^\]]*\])?)\s*\{/;
+
+// ============================================================================
+// LOCAL VARIABLES
+// ============================================================================
+
+/**
+ * Find the variables declared in a function body, with how they are
+ * declared: range variables (`for _, v := range`), short variable
+ * declarations and `var` declarations.  Function literals are included;
+ * their variables can only shadow those of the function.
+ * @param {string} body - Function body
+ * @returns {Map<string, string>} Declaration kind (range or local) by name
+ */
+const find_local_variables = (body) => {
+  const masked = mask_source(body || '');
+  const locals = new Map();
+  const add = (names, kind) => {
+    for (const name of names.split(',').map((n) => n.trim())) {
+      if (/^[A-Za-z_]\w*$/.test(name) && name !== '_' && !locals.has(name)) {
+        locals.set(name, kind);
+      }
+    }
+  };
+
+  let match;
+  const range = /\bfor\s+(\w+(?:\s*,\s*\w+)?)\s*:=\s*range\b/g;
+  while ((match = range.exec(masked)) !== null) add(match[1], 'range');
+
+  const define =
+    /(?:^|[;{(\n]|\bif|\bswitch|\bfor)\s*(\w+(?:\s*,\s*\w+)*)\s*:=/g;
+  while ((match = define.exec(masked)) !== null) add(match[1], 'local');
+
+  const declare = /\bvar\s+(\w+(?:\s*,\s*\w+)*)/g;
+  while ((match = declare.exec(masked)) !== null) add(match[1], 'local');
+
+  return locals;
+};
+
+// ============================================================================
+// ESCAPING ADDRESSES
+// ============================================================================
+
+/**
+ * Build the message describing an escaping address.
+ * @param {string} kind - Escape kind
+ * @param {string} name - Variable name, or composite literal type
+ * @param {boolean} [receiver=false] - Whether the variable is the receiver
+ * @returns {string} Message
+ */
+const get_escape_message = (kind, name, receiver = false) => {
+  if (kind === 'param') {
+    const variable = receiver ? 'value receiver' : 'parameter';
+    return `returns a pointer to ${variable} ${name}, a copy of the caller's value, which escapes to the heap`;
+  }
+  if (kind === 'range') {
+    return `returns a pointer to range variable ${name}, a copy of the element rather than the element itself`;
+  }
+  if (kind === 'composite') {
+    return `returns a pointer to a ${name} composite literal, allocated on the heap`;
+  }
+  return `returns a pointer to local variable ${name}, which escapes to the heap`;
+};
+
+/**
+ * Find the returned addresses of a function's variables and composite
+ * literals.  Addresses of package variables and of variables reached
+ * through a pointer receiver are not reported.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @returns {Object[]} Escapes with line, expression, variable, kind and message
+ */
+const find_function_escapes = (fn) => {
+  if (!fn.body) return [];
+  const locals = find_local_variables(fn.body);
+  const params = new Set(fn.params.map((p) => p.name).filter(Boolean));
+  if (fn.receiver && fn.receiver.name && !fn.receiver.pointer) {
+    params.add(fn.receiver.name);
+  }
+  const escapes = [];
+
+  for (const statement of find_return_statements(fn.body, fn.body_line)) {
+    for (const value of statement.values) {
+      const text = value.trim();
+      const composite = text.match(COMPOSITE_PATTERN);
+      if (composite) {
+        escapes.push({
+          line: statement.line,
+          expression: text.replace(/\{[\s\S]*$/, '{...}'),
+          variable: null,
+          field: null,
+          kind: 'composite',
+          message: get_escape_message('composite', composite[1])
+        });
+        continue;
+      }
+
+      const address = text.match(ADDRESS_PATTERN);
+      if (!address) continue;
+      const name = address[1];
+      const kind = locals.get(name) || (params.has(name) ? 'param' : null);
+      if (!kind) continue;
+      const field = address[2].replace(/\s+/g, '').replace(/^\./, '') || null;
+      const receiver =
+        kind === 'param' && fn.receiver && fn.receiver.name === name;
+      escapes.push({
+        line: statement.line,
+        expression: text,
+        variable: name,
+        field,
+        kind,
+        message: get_escape_message(
+          kind,
+          field ? `${name} (field ${field})` : name,
+          receiver
+        )
+      });
+    }
+  }
+
+  return escapes;
+};
+
+/**
+ * Find the functions of a set of packages returning the address of one
+ * of their variables or of a composite literal.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.include_composites=true] - Include composite literals
+ * @returns {Object[]} Escapes with their function, by file and line
+ */
+const find_escaping_pointers = (packages, options = {}) => {
+  const include_composites = options.include_composites !== false;
+  const escapes = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      const receiver = fn.receiver ? get_base_type(fn.receiver.type) : null;
+      for (const escape of find_function_escapes(fn)) {
+        if (escape.kind === 'composite' && !include_composites) continue;
+        escapes.push({
+          function: receiver ? `${receiver}.${fn.name}` : fn.name,
+          package: pkg.name,
+          directory: pkg.directory,
+          filename: fn.filename,
+          severity: 'info',
+          ...escape
+        });
+      }
+    }
+  }
+
+  return escapes.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Report the functions of a project returning pointers to their
+ * variables or to composite literals.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_escaping_pointers)
+ * @returns {Promise<Object>} Escapes and a summary by kind
+ */
+const analyze_project_escapes = async (project_id, options = {}) => {
+  const escapes = find_escaping_pointers(
+    await load_go_packages(project_id),
+    options
+  );
+
+  const by_kind = Object.fromEntries(ESCAPE_KINDS.map((k) => [k, 0]));
+  for (const escape of escapes) by_kind[escape.kind]++;
+  return {
+    escapes,
+    summary: {
+      total_escapes: escapes.length,
+      variables: escapes.length - by_kind.composite,
+      functions: new Set(escapes.map((e) => `${e.directory}.${e.function}`))
+        .size,
+      by_kind
+    }
+  };
+};
+
+export {
+  analyze_project_escapes,
+  find_escaping_pointers,
+  find_function_escapes,
+  find_local_variables,
+  ESCAPE_KINDS
+};
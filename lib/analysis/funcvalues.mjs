@@ -0,0 +1,389 @@
+'use strict';
+
+/**
+ * @fileoverview Go function value module.
+ * Finds where the functions and methods of a project are used as values
+ * rather than called: passed as arguments (`sort.Slice(xs, less)`),
+ * assigned to variables or fields, stored in composite literals (a
+ * `map[string]func()` of handlers, a struct field) or returned.  A
+ * function used as a value may be called indirectly, from anywhere the
+ * value flows, so the call graph alone under-approximates what it
+ * reaches; callback-heavy code should treat these functions as roots.
+ *
+ * References are resolved like calls in the symbol graph: package
+ * functions (`handle`), functions of imported project packages
+ * (`api.Handle`), method values through the receiver and parameters
+ * (`s.handle`) and method expressions (`Server.Handle`).  Names shadowed
+ * by a parameter or a local variable are left out, as are instantiations
+ * of generic functions immediately called (`Map[int](xs)`).
+ *
+ * Sites are classified by their context:
+ * - argument: an argument of a call or conversion
+ * - assignment: the value of a variable assignment or declaration
+ * - field: the value assigned to a field (`s.handler = handle`)
+ * - composite: an element of a composite literal (struct, map or slice)
+ * - return: a returned value
+ * - other: any other expression, such as a comparison
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/funcvalues
+ */
+
+import {
+  mask_source,
+  find_matching,
+  build_line_index,
+  line_at,
+  get_base_type,
+  load_go_packages
+} from './golang.mjs';
+import {
+  get_node_id,
+  get_imported_packages,
+  find_call_edges
+} from './graph.mjs';
+
+/**
+ * Kinds of function value sites.
+ */
+const VALUE_SITE_KINDS = [
+  'argument',
+  'assignment',
+  'field',
+  'composite',
+  'return',
+  'other'
+];
+
+// ============================================================================
+// VALUE SITES
+// ============================================================================
+
+/**
+ * Find the innermost bracket left open before an offset.
+ * @param {string} masked - Masked source
+ * @param {number} index - Offset
+ * @returns {number} Offset of the bracket, or -1
+ */
+const find_open_bracket = (masked, index) => {
+  let depth = 0;
+  for (let i = index; i >= 0; i--) {
+    const ch = masked[i];
+    if (ch === ')' || ch === ']' || ch === '}') depth++;
+    else if (ch === '(' || ch === '[' || ch === '{') {
+      if (depth === 0) return i;
+      depth--;
+    }
+  }
+  return -1;
+};
+
+/**
+ * Classify the site of a function value by the code before it.
+ * @param {string} masked - Masked source
+ * @param {number} index - Offset of the value
+ * @returns {string} Site kind (see VALUE_SITE_KINDS)
+ */
+const classify_value_site = (masked, index) => {
+  let i = index - 1;
+  while (i >= 0 && /\s/.test(masked[i])) i--;
+  const previous = masked[i];
+
+  if (/(?<![\w.])return$/.test(masked.substring(Math.max(0, i - 6), i + 1))) {
+    return 'return';
+  }
+  if (previous === '=') {
+    const operator = masked[i - 1];
+    if ('=!<>'.includes(operator)) return 'other';
+    const line_start = masked.lastIndexOf('\n', i) + 1;
+    const target = masked
+      .substring(line_start, operator === ':' ? i - 1 : i)
+      .trim();
+    return /\.\s*\w+$/.test(target) ? 'field' : 'assignment';
+  }
+  if (previous === ':') return 'composite';
+  if (previous === '{') return 'composite';
+  if (previous === '(' || previous === ',') {
+    const open = find_open_bracket(masked, i);
+    if (masked[open] === '{') return 'composite';
+    if (masked[open] === '(') {
+      let j = open - 1;
+      while (j >= 0 && /\s/.test(masked[j])) j--;
+      if (/[\w)\]]/.test(masked[j] || '')) return 'argument';
+    }
+  }
+  return 'other';
+};
+
+/**
+ * Check whether the reference at an offset is called: followed by a call,
+ * or by type arguments and a call.
+ * @param {string} masked - Masked source
+ * @param {number} end - Offset after the reference
+ * @returns {boolean} True if the reference is called
+ */
+const is_called_reference = (masked, end) => {
+  let i = end;
+  while (i < masked.length && /\s/.test(masked[i])) i++;
+  if (masked[i] === '[') {
+    const close = find_matching(masked, i);
+    if (close === -1) return false;
+    i = close + 1;
+    while (i < masked.length && /\s/.test(masked[i])) i++;
+  }
+  return masked[i] === '(';
+};
+
+/**
+ * Get the names declared in a function body or signature: parameters,
+ * receiver, and variables declared with `:=` or `var`.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {string} masked - Masked function body
+ * @returns {Set<string>} Declared names
+ */
+const get_local_names = (fn, masked) => {
+  const names = new Set(
+    [...fn.params, ...fn.results].map((p) => p.name).filter(Boolean)
+  );
+  if (fn.receiver && fn.receiver.name) names.add(fn.receiver.name);
+  for (const match of masked.matchAll(
+    /(?<![\w.])(\w+(?:\s*,\s*\w+)*)\s*:=/g
+  )) {
+    for (const name of match[1].split(',')) names.add(name.trim());
+  }
+  for (const match of masked.matchAll(/\bvar\s+(\w+)/g)) names.add(match[1]);
+  return names;
+};
+
+/**
+ * Find the function values of a piece of code: the references to project
+ * functions and methods that are not called.
+ * @param {string} code - Code, such as a function body
+ * @param {Object} context - Resolution context
+ * @param {Object} context.pkg - Package of the code
+ * @param {Map<string, Object>} context.imported - Imported project packages (from get_imported_packages)
+ * @param {Map<string, string>} [context.variables] - Receiver and parameter types by name
+ * @param {Set<string>} [context.locals] - Names shadowing package functions
+ * @returns {Object[]} Values with target node ID, kind and offset
+ */
+const find_code_function_values = (code, context) => {
+  const { pkg, imported } = context;
+  const variables = context.variables || new Map();
+  const locals = context.locals || new Set();
+  const masked = mask_source(code);
+  const functions = new Set(
+    pkg.functions.filter((f) => !f.receiver).map((f) => f.name)
+  );
+  const has_method = (p, type, name) =>
+    (p.methods[type] || []).some((m) => m.name === name);
+
+  const values = [];
+  const pattern = /(?<![\w.])([A-Za-z_]\w*)(?:\s*\.\s*([A-Za-z_]\w*))?/g;
+  let match;
+  while ((match = pattern.exec(masked)) !== null) {
+    const [, first, second] = match;
+    let target = null;
+
+    if (!second) {
+      if (functions.has(first) && !locals.has(first)) {
+        target = get_node_id(pkg.directory, first);
+      }
+    } else if (imported.has(first) && !locals.has(first)) {
+      const other = imported.get(first);
+      if (other.functions.some((f) => !f.receiver && f.name === second)) {
+        target = get_node_id(other.directory, second);
+      }
+    } else if (variables.has(first)) {
+      const type = variables.get(first);
+      if (has_method(pkg, type, second)) {
+        target = get_node_id(pkg.directory, `${type}.${second}`);
+      }
+    } else if (!locals.has(first) && has_method(pkg, first, second)) {
+      // Method expression
+      target = get_node_id(pkg.directory, `${first}.${second}`);
+    }
+
+    if (!target) continue;
+    const end = match.index + match[0].length;
+    if (is_called_reference(masked, end)) continue;
+    // Keys of composite literals and labels are not values
+    if (!second && /^\s*:(?!=)/.test(masked.substring(end)) &&
+      classify_value_site(masked, match.index) === 'composite') {
+      continue;
+    }
+    values.push({
+      target,
+      kind: classify_value_site(masked, match.index),
+      offset: match.index
+    });
+  }
+
+  return values;
+};
+
+/**
+ * Find the function value sites of a set of packages: the function and
+ * method bodies and the package variable initializers using a project
+ * function as a value.  Sites of package variables are at the line of
+ * their declaration.  Test files are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Sites with target, kind, enclosing function, filename and line
+ */
+const find_function_value_sites = (packages) => {
+  const sites = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (!fn.body || fn.filename.endsWith('_test.go')) continue;
+      const masked = mask_source(fn.body);
+      const variables = new Map();
+      if (fn.receiver && fn.receiver.name) {
+        variables.set(fn.receiver.name, fn.receiver.type);
+      }
+      for (const param of fn.params) {
+        if (param.name) variables.set(param.name, get_base_type(param.type));
+      }
+      const line_index = build_line_index(fn.body);
+      const values = find_code_function_values(fn.body, {
+        pkg,
+        imported: get_imported_packages(pkg, fn.filename, packages),
+        variables,
+        locals: get_local_names(fn, masked)
+      });
+      const enclosing = get_node_id(
+        pkg.directory,
+        fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name
+      );
+      for (const value of values) {
+        sites.push({
+          target: value.target,
+          kind: value.kind,
+          enclosing,
+          filename: fn.filename,
+          line: fn.body_line + line_at(line_index, value.offset) - 1
+        });
+      }
+    }
+
+    for (const decl of pkg.vars) {
+      if (decl.filename.endsWith('_test.go')) continue;
+      const imported = get_imported_packages(pkg, decl.filename, packages);
+      for (const value of decl.values || []) {
+        // Prefixed so that the value classifies as an assignment
+        for (const found of find_code_function_values(`= ${value}`, {
+          pkg,
+          imported
+        })) {
+          sites.push({
+            target: found.target,
+            kind: found.kind,
+            enclosing: null,
+            filename: decl.filename,
+            line: decl.line
+          });
+        }
+      }
+    }
+  }
+
+  return sites;
+};
+
+// ============================================================================
+// FUNCTION VALUES
+// ============================================================================
+
+/**
+ * Report, for each function and method of a set of packages, whether it
+ * is used as a value, with its value sites and its number of direct
+ * calls.  Functions are used as values, only called directly, or not
+ * referenced at all.  Functions of test files are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.values_only=false] - Only list functions used as values
+ * @returns {Object[]} Functions with used_as_value, calls and value_sites, by file and line
+ */
+const find_function_values = (packages, options = {}) => {
+  const calls = new Map();
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.filename.endsWith('_test.go')) continue;
+      for (const edge of find_call_edges(fn, pkg, packages)) {
+        calls.set(edge.target, (calls.get(edge.target) || 0) + 1);
+      }
+    }
+  }
+
+  const sites = new Map();
+  for (const site of find_function_value_sites(packages)) {
+    if (!sites.has(site.target)) sites.set(site.target, []);
+    const { target, ...rest } = site;
+    sites.get(target).push(rest);
+  }
+
+  const functions = [];
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.filename.endsWith('_test.go')) continue;
+      const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      const id = get_node_id(pkg.directory, name);
+      const value_sites = sites.get(id) || [];
+      if (options.values_only && value_sites.length === 0) continue;
+      functions.push({
+        id,
+        name,
+        kind: fn.receiver ? 'method' : 'function',
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        used_as_value: value_sites.length > 0,
+        calls: calls.get(id) || 0,
+        value_sites
+      });
+    }
+  }
+
+  return functions.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Report the functions of a project used as values.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_function_values)
+ * @returns {Promise<Object>} Functions with their value sites and a summary
+ */
+const analyze_project_function_values = async (project_id, options = {}) => {
+  const all = find_function_values(await load_go_packages(project_id));
+  const functions = options.values_only
+    ? all.filter((fn) => fn.used_as_value)
+    : all;
+
+  const by_kind = Object.fromEntries(VALUE_SITE_KINDS.map((k) => [k, 0]));
+  for (const fn of all) {
+    for (const site of fn.value_sites) by_kind[site.kind]++;
+  }
+
+  return {
+    functions,
+    summary: {
+      total_functions: all.length,
+      used_as_value: all.filter((fn) => fn.used_as_value).length,
+      called_only: all.filter((fn) => !fn.used_as_value && fn.calls > 0)
+        .length,
+      unreferenced: all.filter((fn) => !fn.used_as_value && fn.calls === 0)
+        .length,
+      by_kind
+    }
+  };
+};
+
+export {
+  analyze_project_function_values,
+  find_function_values,
+  find_function_value_sites,
+  classify_value_site,
+  VALUE_SITE_KINDS
+};
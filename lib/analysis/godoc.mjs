@@ -0,0 +1,927 @@
+'use strict';
+
+/**
+ * @fileoverview Go documentation analysis module.
+ * Extracts package doc comments and merges them across the files of each
+ * package, distinguishing the `// Package name ...` doc from file headers
+ * such as license banners.  Useful for documentation generation and for
+ * giving an overview of each package.
+ *
+ * Symbol docs are linted in two ways: exported symbols without a doc
+ * comment, and doc comments that do not begin with the declared name
+ * (`// Add adds ...`), the godoc convention that makes comments read as
+ * sentences in the generated documentation.
+ *
+ * Doc coverage, the ratio of documented exported symbols, is compared
+ * against a stored baseline to report its change over time and the newly
+ * added symbols without docs, so that changes reducing it can be gated.
+ *
+ * Doc links (`[Calculator]`, `[Calculator.Add]`, `[store.Entry]`) are
+ * extracted from the doc comments and resolved against the project's
+ * symbols, for cross-linked documentation and link-rot detection: links
+ * to symbols missing from a project package are flagged.  Links to other
+ * packages, such as the standard library, are external.  URLs, bare or
+ * from link definitions (`[Go]: https://go.dev`), are listed apart.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/godoc
+ */
+
+import {
+  get_comment_text,
+  is_exported,
+  parse_go_file,
+  group_go_packages,
+  load_go_sources,
+  load_go_packages
+} from './golang.mjs';
+import { get_imported_packages } from './graph.mjs';
+import { get_import_name } from './imports.mjs';
+
+/**
+ * Kinds of symbols that can be required to have doc comments.
+ */
+const DOC_KINDS = ['function', 'method', 'type', 'field', 'const', 'var'];
+
+/**
+ * Kinds required to have doc comments by default, as in golint.
+ */
+const DEFAULT_DOC_KINDS = ['function', 'method', 'type', 'field'];
+
+/**
+ * Kinds whose doc comments are checked to begin with the declared name.
+ * Field docs describe the field and rarely repeat its name.
+ */
+const DOC_NAME_KINDS = ['function', 'method', 'type', 'const', 'var'];
+
+/**
+ * Articles allowed before the declared name (`// A Reader reads ...`).
+ */
+const DOC_ARTICLES = ['A', 'An', 'The'];
+
+/**
+ * Version of the doc coverage baseline format.
+ */
+const DOC_COVERAGE_VERSION = 1;
+
+/**
+ * Statuses of doc links.
+ * - resolved: the symbol is declared in the project
+ * - external: the link targets a package outside the project
+ * - unresolved: the symbol is missing from a project package
+ */
+const DOC_LINK_STATUSES = ['resolved', 'external', 'unresolved'];
+
+/**
+ * Bracketed doc link candidates (`[Name]`, `[*pkg.Name.Method]`,
+ * `[example.com/app/store.Entry]`), not part of an index expression or a
+ * link definition.
+ */
+const DOC_LINK_PATTERN =
+  /(?<![\w\]])\[(\*?(?:[\w.-]+\/)*[A-Za-z_][\w-]*(?:\.[A-Za-z_]\w*){0,2})\](?![\w(:[])/g;
+
+/**
+ * Link definitions of a doc comment (`[Text]: https://example.com`).
+ */
+const DOC_LINK_DEFINITION_PATTERN = /^\[([^\]]+)\]:\s*(\S+)\s*$/;
+
+/**
+ * Bare URLs, which godoc links as they are.
+ */
+const DOC_URL_PATTERN = /\bhttps?:\/\/[^\s<>"\]]*[^\s<>"\].,;:!?)']/g;
+
+// ============================================================================
+// PACKAGE DOCS
+// ============================================================================
+
+/**
+ * Get the first sentence of a doc comment's text.
+ * @param {string} text - Comment text (without markers)
+ * @returns {string} First sentence, or the whole text if it has none
+ */
+const get_doc_synopsis = (text) => {
+  const flat = (text || '').replace(/\s+/g, ' ').trim();
+  const match = flat.match(/^(.*?[.!?])(\s|$)/);
+  return match ? match[1] : flat;
+};
+
+/**
+ * Summarize the documentation of a package.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object} Package doc with text, synopsis, source file, conflicts and file headers
+ */
+const summarize_package_doc = (pkg) => {
+  const text = pkg.doc ? get_comment_text(pkg.doc) : null;
+  const subject = text ? text.match(/^(Package|Command)\s+(\S+)/) : null;
+
+  return {
+    package: pkg.name,
+    directory: pkg.directory,
+    files: pkg.files.length,
+    documented: Boolean(pkg.doc),
+    doc: text,
+    synopsis: text ? get_doc_synopsis(text) : null,
+    doc_file: pkg.doc_file,
+    doc_line: pkg.doc_line,
+    name_mismatch:
+      subject && subject[1] === 'Package' && subject[2] !== pkg.name
+        ? subject[2]
+        : null,
+    conflicts: pkg.doc_conflicts.map(function format_conflict(conflict) {
+      return {
+        filename: conflict.filename,
+        line: conflict.line,
+        same_text: conflict.same_text,
+        synopsis: get_doc_synopsis(get_comment_text(conflict.doc))
+      };
+    }),
+    file_headers: pkg.files
+      .filter((f) => f.file_header)
+      .map(function format_header(file) {
+        return {
+          filename: file.filename,
+          header: get_comment_text(file.file_header)
+        };
+      })
+  };
+};
+
+/**
+ * Analyze the package doc comments of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Package docs with a summary
+ */
+const analyze_package_docs = async (project_id) => {
+  const packages = (await load_go_packages(project_id)).map(
+    summarize_package_doc
+  );
+
+  return {
+    packages,
+    summary: {
+      total_packages: packages.length,
+      documented_packages: packages.filter((p) => p.documented).length,
+      undocumented_packages: packages
+        .filter((p) => !p.documented)
+        .map((p) => p.directory),
+      packages_with_conflicts: packages.filter((p) => p.conflicts.length > 0)
+        .length,
+      name_mismatches: packages.filter((p) => p.name_mismatch).length,
+      files_with_headers: packages.reduce(
+        (sum, p) => sum + p.file_headers.length,
+        0
+      )
+    }
+  };
+};
+
+// ============================================================================
+// SYMBOL DOCS
+// ============================================================================
+
+/**
+ * Convert a glob to a regular expression.  `**` matches any number of
+ * directories, `*` and `?` any characters but `/`.  A glob without a
+ * slash matches file names in any directory, as in .gitignore.
+ * @param {string} glob - Glob pattern (e.g. `*_gen.go` or `internal/**`)
+ * @returns {RegExp} Regular expression matching relative filenames
+ */
+const glob_to_regexp = (glob) => {
+  let pattern = '';
+  for (let i = 0; i < glob.length; i++) {
+    const ch = glob[i];
+    if (ch === '*' && glob[i + 1] === '*') {
+      pattern += glob[i + 2] === '/' ? '(?:.*/)?' : '.*';
+      i += glob[i + 2] === '/' ? 2 : 1;
+    } else if (ch === '*') {
+      pattern += '[^/]*';
+    } else if (ch === '?') {
+      pattern += '[^/]';
+    } else {
+      pattern += ch.replace(/[.+^${}()|[\]\\]/g, '\\$&');
+    }
+  }
+  const anchor = glob.includes('/') ? '^' : '(?:^|/)';
+  return new RegExp(`${anchor}${pattern.replace(/^\//, '')}$`);
+};
+
+/**
+ * Check a list of kinds against the kinds that can be documented.
+ * @param {string[]} kinds - Kinds
+ * @param {string[]} allowed - Allowed kinds
+ * @throws {Error} If a kind is unknown
+ */
+const check_doc_kinds = (kinds, allowed) => {
+  const unknown = kinds.filter((k) => !allowed.includes(k));
+  if (unknown.length > 0) {
+    throw new Error(
+      `Unknown kind '${unknown[0]}' (expected one of: ${allowed.join(', ')})`
+    );
+  }
+};
+
+/**
+ * List the exported symbols of a set of packages with their doc comments.
+ * Methods are listed when their receiver type is exported, and fields
+ * when their struct is; a trailing comment documents a field.  Embedded
+ * fields, documented by their type, and test files are skipped.
+ *
+ * A group doc comment (`// Colors ...` before `const (`) is inherited by
+ * the constants and variables of the group without a doc comment of
+ * their own; those are marked group_doc.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Symbols with kind, name, declared names, position and doc
+ */
+const list_exported_symbols = (packages) => {
+  const symbols = [];
+  const add = (pkg, kind, name, names, filename, line, doc, group_doc) => {
+    if (filename.endsWith('_test.go')) return;
+    symbols.push({
+      pkg,
+      kind,
+      name,
+      names,
+      filename,
+      line,
+      doc: doc || null,
+      group_doc: Boolean(group_doc)
+    });
+  };
+
+  for (const pkg of packages) {
+    const exported_types = new Set(
+      pkg.types.filter((t) => t.exported).map((t) => t.name)
+    );
+
+    for (const fn of pkg.functions) {
+      if (!fn.exported) continue;
+      if (!fn.receiver) {
+        add(pkg, 'function', fn.name, [fn.name], fn.filename, fn.line, fn.doc);
+      } else if (exported_types.has(fn.receiver.type)) {
+        const name = `${fn.receiver.type}.${fn.name}`;
+        add(pkg, 'method', name, [fn.name, name], fn.filename, fn.line, fn.doc);
+      }
+    }
+
+    for (const type of pkg.types.filter((t) => t.exported)) {
+      add(pkg, 'type', type.name, [type.name], type.filename, type.line, type.doc);
+      for (const field of type.fields) {
+        if (field.embedded) continue;
+        for (const name of field.names.filter((n) => /^[A-Z]/.test(n))) {
+          const doc = field.doc || field.comment;
+          const full_name = `${type.name}.${name}`;
+          add(pkg, 'field', full_name, [name], type.filename, field.line, doc);
+        }
+      }
+    }
+
+    for (const [decls, kind] of [
+      [pkg.consts, 'const'],
+      [pkg.vars, 'var']
+    ]) {
+      // Specs of a group sharing one doc comment inherit the group's
+      const groups = new Map();
+      for (const decl of decls.filter((d) => d.grouped)) {
+        const key = `${decl.filename}:${decl.group_line}`;
+        if (!groups.has(key)) groups.set(key, []);
+        groups.get(key).push(decl);
+      }
+
+      for (const decl of decls) {
+        const group = decl.grouped
+          ? groups.get(`${decl.filename}:${decl.group_line}`)
+          : [];
+        const group_doc =
+          decl.doc && group.length > 0 && group.every((d) => d.doc === decl.doc);
+        for (const name of decl.names.filter((n) => /^[A-Z]/.test(n))) {
+          add(
+            pkg,
+            kind,
+            name,
+            decl.names,
+            decl.filename,
+            decl.line,
+            decl.doc,
+            group_doc
+          );
+        }
+      }
+    }
+  }
+
+  return symbols.sort(function sort_by_position(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Find the exported symbols of a set of packages that lack a doc comment
+ * (see list_exported_symbols).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.kinds] - Kinds to require docs for (default function, method, type and field)
+ * @returns {Object} Undocumented symbols and the counts per kind
+ */
+const find_undocumented_symbols = (packages, options = {}) => {
+  const kinds = options.kinds || DEFAULT_DOC_KINDS;
+  check_doc_kinds(kinds, DOC_KINDS);
+
+  const counts = Object.fromEntries(
+    kinds.map((k) => [k, { checked: 0, undocumented: 0 }])
+  );
+  const symbols = [];
+  for (const symbol of list_exported_symbols(packages)) {
+    if (!counts[symbol.kind]) continue;
+    counts[symbol.kind].checked++;
+    if (symbol.doc) continue;
+    counts[symbol.kind].undocumented++;
+    symbols.push({
+      name: symbol.name,
+      kind: symbol.kind,
+      package: symbol.pkg.name,
+      directory: symbol.pkg.directory,
+      filename: symbol.filename,
+      line: symbol.line
+    });
+  }
+
+  const checked = kinds.reduce((sum, k) => sum + counts[k].checked, 0);
+  return {
+    kinds,
+    symbols,
+    by_kind: counts,
+    summary: {
+      checked,
+      undocumented: symbols.length,
+      coverage:
+        checked > 0
+          ? Math.round(((checked - symbols.length) / checked) * 1000) / 10
+          : 100
+    }
+  };
+};
+
+/**
+ * Find the undocumented exported symbols of a set of Go sources, such as
+ * the files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.kinds] - Kinds to require docs for
+ * @param {string[]} [options.exclude=[]] - Globs of files to skip, such as generated code
+ * @returns {Object} Undocumented symbols (see find_undocumented_symbols)
+ */
+const find_source_undocumented = (sources, options = {}) => {
+  const exclude = (options.exclude || []).map(glob_to_regexp);
+  const files = sources
+    .filter((file) => !exclude.some((re) => re.test(file.filename)))
+    .map((file) => parse_go_file(file.source, file.filename));
+  return find_undocumented_symbols(group_go_packages(files), options);
+};
+
+/**
+ * Find the undocumented exported symbols of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_source_undocumented)
+ * @returns {Promise<Object>} Undocumented symbols with a summary
+ */
+const analyze_project_undocumented = async (project_id, options = {}) => {
+  return find_source_undocumented(await load_go_sources(project_id), options);
+};
+
+// ============================================================================
+// DOC NAMES
+// ============================================================================
+
+/**
+ * Get the first word of a doc comment, the one that should be the
+ * declared name.  A leading `Deprecated:` paragraph is skipped, as is an
+ * article (`A`, `An`, `The`) when the next word is the name.  Trailing
+ * punctuation and possessives (`Reader's`) are not part of the word.
+ * @param {string} text - Comment text (without markers)
+ * @param {string[]} names - Declared names
+ * @returns {Object|null} First word and whether the comment is deprecated, or null if only a deprecation notice
+ */
+const get_doc_first_word = (text, names) => {
+  const paragraphs = (text || '')
+    .split(/\n\s*\n/)
+    .map((p) => p.trim())
+    .filter(Boolean);
+  const deprecated = paragraphs.length > 0 && /^Deprecated:/.test(paragraphs[0]);
+  const paragraph = deprecated ? paragraphs[1] : paragraphs[0];
+  if (!paragraph) return null;
+
+  const clean = (word) =>
+    (word || '').replace(/['’]s$/, '').replace(/[.,:;!?)"'`’]+$/, '');
+  const words = paragraph.split(/\s+/);
+  let word = clean(words[0]);
+  if (DOC_ARTICLES.includes(word) && names.includes(clean(words[1]))) {
+    word = clean(words[1]);
+  }
+  return { word, deprecated };
+};
+
+/**
+ * Check that a doc comment begins with a declared name.  A first word
+ * differing from the name only by case (`Url` for `URL`, `JsonEncoder`
+ * for `JSONEncoder`) names the symbol with the wrong capitalization of
+ * an acronym rather than not naming it, and is reported as such.
+ * @param {string} doc - Doc comment (with markers)
+ * @param {string[]} names - Declared names, any of which may begin the comment
+ * @returns {Object|null} First word and reason (name or case), or null if the comment follows the convention
+ */
+const check_doc_name = (doc, names) => {
+  const first = get_doc_first_word(get_comment_text(doc), names);
+  if (!first || names.includes(first.word)) return null;
+
+  const lower = first.word.toLowerCase();
+  const same_word = names.some((n) => n.toLowerCase() === lower);
+  return {
+    first_word: first.word,
+    reason: same_word ? 'case' : 'name',
+    deprecated: first.deprecated
+  };
+};
+
+/**
+ * Find the doc comments of the exported symbols of a set of packages that
+ * do not begin with the declared name (see list_exported_symbols).
+ * Methods may be named alone (`Area`) or with their type (`Square.Area`),
+ * and declarations of several names by any of them.  Undocumented
+ * symbols and group doc comments, which describe the group, are not
+ * checked.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.kinds] - Kinds to check (default function, method, type, const and var)
+ * @returns {Object} Misnamed doc comments and the counts per kind
+ */
+const find_doc_name_mismatches = (packages, options = {}) => {
+  const kinds = options.kinds || DOC_NAME_KINDS;
+  check_doc_kinds(kinds, DOC_KINDS);
+
+  const counts = Object.fromEntries(
+    kinds.map((k) => [k, { checked: 0, mismatched: 0 }])
+  );
+  const symbols = [];
+  for (const symbol of list_exported_symbols(packages)) {
+    if (!counts[symbol.kind] || !symbol.doc || symbol.group_doc) continue;
+    counts[symbol.kind].checked++;
+    const mismatch = check_doc_name(symbol.doc, symbol.names);
+    if (!mismatch) continue;
+
+    counts[symbol.kind].mismatched++;
+    const expected = symbol.kind === 'method' ? symbol.names[0] : symbol.name;
+    symbols.push({
+      name: symbol.name,
+      kind: symbol.kind,
+      package: symbol.pkg.name,
+      directory: symbol.pkg.directory,
+      filename: symbol.filename,
+      line: symbol.line,
+      expected,
+      ...mismatch,
+      message:
+        mismatch.reason === 'case'
+          ? `comment on exported ${symbol.kind} ${symbol.name} begins with ${mismatch.first_word}; write the name as declared, ${expected}`
+          : `comment on exported ${symbol.kind} ${symbol.name} should be of the form "${expected} ..." (begins with ${mismatch.first_word})`
+    });
+  }
+
+  const checked = kinds.reduce((sum, k) => sum + counts[k].checked, 0);
+  return {
+    kinds,
+    symbols,
+    by_kind: counts,
+    summary: {
+      checked,
+      mismatched: symbols.length,
+      by_case: symbols.filter((s) => s.reason === 'case').length
+    }
+  };
+};
+
+/**
+ * Find the misnamed doc comments of a set of Go sources, such as the files
+ * of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.kinds] - Kinds to check
+ * @param {string[]} [options.exclude=[]] - Globs of files to skip, such as generated code
+ * @returns {Object} Misnamed doc comments (see find_doc_name_mismatches)
+ */
+const find_source_doc_names = (sources, options = {}) => {
+  const exclude = (options.exclude || []).map(glob_to_regexp);
+  const files = sources
+    .filter((file) => !exclude.some((re) => re.test(file.filename)))
+    .map((file) => parse_go_file(file.source, file.filename));
+  return find_doc_name_mismatches(group_go_packages(files), options);
+};
+
+/**
+ * Find the misnamed doc comments of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_source_doc_names)
+ * @returns {Promise<Object>} Misnamed doc comments with a summary
+ */
+const analyze_project_doc_names = async (project_id, options = {}) => {
+  return find_source_doc_names(await load_go_sources(project_id), options);
+};
+
+// ============================================================================
+// DOC COVERAGE
+// ============================================================================
+
+/**
+ * Compute the doc coverage of a set of packages: whether each exported
+ * symbol of the checked kinds is documented (see list_exported_symbols),
+ * by `<directory>:<name>` id.  The result is the baseline stored to
+ * compare later coverage with.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.kinds] - Kinds to check (default function, method, type and field)
+ * @returns {Object} Coverage with version, kinds, summary and documented flags by symbol id
+ */
+const compute_doc_coverage = (packages, options = {}) => {
+  const kinds = options.kinds || DEFAULT_DOC_KINDS;
+  check_doc_kinds(kinds, DOC_KINDS);
+
+  const symbols = {};
+  for (const symbol of list_exported_symbols(packages)) {
+    if (!kinds.includes(symbol.kind)) continue;
+    symbols[`${symbol.pkg.directory}:${symbol.name}`] = Boolean(symbol.doc);
+  }
+
+  const checked = Object.keys(symbols).length;
+  const documented = Object.values(symbols).filter(Boolean).length;
+  return {
+    version: DOC_COVERAGE_VERSION,
+    kinds,
+    summary: {
+      checked,
+      documented,
+      coverage:
+        checked > 0 ? Math.round((documented / checked) * 1000) / 10 : 100
+    },
+    symbols
+  };
+};
+
+/**
+ * Compare doc coverage with a baseline: the change of coverage, the
+ * symbols added since without docs, and the symbols whose docs were
+ * added or removed.  Coverage regresses when fewer of the symbols are
+ * documented, comparing the exact ratios rather than rounded percents.
+ * @param {Object} current - Current coverage (from compute_doc_coverage)
+ * @param {Object} baseline - Baseline coverage (from compute_doc_coverage)
+ * @returns {Object} Delta, regression flag and changed symbols
+ * @throws {Error} If the baseline has another format version
+ */
+const compare_doc_coverage = (current, baseline) => {
+  if (!baseline || baseline.version !== DOC_COVERAGE_VERSION) {
+    throw new Error(
+      `Unsupported doc coverage baseline version: ${baseline && baseline.version}`
+    );
+  }
+
+  const before = baseline.symbols || {};
+  const after = current.symbols;
+  const ids = (symbols, test) => Object.keys(symbols).filter(test).sort();
+  const ratio = ({ checked, documented }) =>
+    checked > 0 ? documented / checked : 1;
+
+  return {
+    coverage: current.summary.coverage,
+    baseline_coverage: baseline.summary.coverage,
+    delta:
+      Math.round((current.summary.coverage - baseline.summary.coverage) * 10) /
+      10,
+    regression: ratio(current.summary) < ratio(baseline.summary),
+    new_undocumented: ids(after, (id) => !(id in before) && !after[id]),
+    newly_documented: ids(after, (id) => after[id] && before[id] === false),
+    lost_docs: ids(after, (id) => !after[id] && before[id] === true),
+    added: ids(after, (id) => !(id in before)).length,
+    removed: ids(before, (id) => !(id in after)).length
+  };
+};
+
+/**
+ * Compute the doc coverage of a set of Go sources, such as the files of
+ * a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.kinds] - Kinds to check
+ * @param {string[]} [options.exclude=[]] - Globs of files to skip, such as generated code
+ * @returns {Object} Coverage (see compute_doc_coverage)
+ */
+const find_source_doc_coverage = (sources, options = {}) => {
+  const exclude = (options.exclude || []).map(glob_to_regexp);
+  const files = sources
+    .filter((file) => !exclude.some((re) => re.test(file.filename)))
+    .map((file) => parse_go_file(file.source, file.filename));
+  return compute_doc_coverage(group_go_packages(files), options);
+};
+
+/**
+ * Compute the doc coverage of a project, compared with a baseline when
+ * one is given.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_source_doc_coverage)
+ * @param {Object} [options.baseline] - Baseline coverage to compare with
+ * @returns {Promise<Object>} Coverage, with the comparison to the baseline
+ */
+const analyze_project_doc_coverage = async (project_id, options = {}) => {
+  const coverage = find_source_doc_coverage(
+    await load_go_sources(project_id),
+    options
+  );
+  if (!options.baseline) return { ...coverage, comparison: null };
+  return {
+    ...coverage,
+    comparison: compare_doc_coverage(coverage, options.baseline)
+  };
+};
+
+// ============================================================================
+// DOC LINKS
+// ============================================================================
+
+/**
+ * Extract the links of a doc comment.  Code blocks, indented past the
+ * comment marker (`//\tx := 1`), are skipped.  A bracketed text names a
+ * symbol when its last element is an exported identifier, and a URL when
+ * a link definition gives it one.
+ * @param {string} doc - Doc comment (with markers)
+ * @returns {Object} Symbol links (text and target) and URL links (text or null, and url)
+ */
+const parse_doc_links = (doc) => {
+  const text = /^\s*\/\*/.test(doc || '')
+    ? get_comment_text(doc)
+    : (doc || '')
+        .split('\n')
+        .filter((l) => !/^\s*\/\/go:/.test(l))
+        .map((l) => l.replace(/^\s*\/\/ ?/, ''))
+        .join('\n');
+  const definitions = new Map();
+  const lines = [];
+  for (const line of text.split('\n')) {
+    if (/^\s/.test(line)) continue;
+    const definition = line.match(DOC_LINK_DEFINITION_PATTERN);
+    if (definition) definitions.set(definition[1], definition[2]);
+    else lines.push(line);
+  }
+  const prose = lines.join('\n');
+
+  const symbols = [];
+  const urls = [];
+  const seen = new Set();
+  for (const match of prose.matchAll(/\[([^\]\n]+)\]/g)) {
+    if (!definitions.has(match[1]) || seen.has(match[1])) continue;
+    seen.add(match[1]);
+    urls.push({ text: match[1], url: definitions.get(match[1]) });
+  }
+  for (const [text, url] of definitions) {
+    if (!seen.has(text)) urls.push({ text, url });
+  }
+  for (const match of prose.matchAll(DOC_URL_PATTERN)) {
+    urls.push({ text: null, url: match[0] });
+  }
+  for (const match of prose.matchAll(DOC_LINK_PATTERN)) {
+    const target = match[1];
+    const name = target.split('/').pop().split('.').pop();
+    if (definitions.has(target) || !is_exported(name)) continue;
+    symbols.push({ text: `[${target}]`, target });
+  }
+  return { symbols, urls };
+};
+
+/**
+ * Find a declaration of a package by name: a type, function, constant or
+ * variable, or with a type name, a method or field of the type.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {string} name - Name
+ * @param {string} [member] - Method or field name
+ * @returns {string|null} Kind of the declaration, or null if missing
+ */
+const find_package_declaration = (pkg, name, member) => {
+  const type = pkg.types.find((t) => t.name === name);
+  if (member) {
+    if (!type) return null;
+    if ((pkg.methods[name] || []).some((fn) => fn.name === member)) {
+      return 'method';
+    }
+    if ((type.methods || []).some((m) => m.name === member)) return 'method';
+    const fields = type.fields || [];
+    return fields.some((f) => f.names.includes(member)) ? 'field' : null;
+  }
+  if (type) return 'type';
+  if (pkg.functions.some((fn) => !fn.receiver && fn.name === name)) {
+    return 'function';
+  }
+  if (pkg.consts.some((c) => c.names.includes(name))) return 'const';
+  if (pkg.vars.some((v) => v.names.includes(name))) return 'var';
+  return null;
+};
+
+/**
+ * Resolve a doc link target (`Name`, `Type.Method`, `pkg.Name`,
+ * `pkg.Type.Method` or `import/path.Name`) in the file of a doc comment,
+ * as godoc does: a first element naming a type of the package is the
+ * type, else an import of the file.  A lower case first element that is
+ * not imported names a standard library package, and is external.
+ * @param {string} target - Link target, without brackets
+ * @param {Object} pkg - Package of the doc comment
+ * @param {string} filename - File of the doc comment
+ * @param {Object[]} packages - All project packages
+ * @returns {Object} Status, resolved declaration (package, directory, name and kind) or null, and import path of external links
+ */
+const resolve_doc_link = (target, pkg, filename, packages) => {
+  const text = target.replace(/^\*/, '');
+  let qualifier = null;
+  let rest = text;
+  const slash = text.lastIndexOf('/');
+  if (slash !== -1) {
+    const dot = text.indexOf('.', slash);
+    qualifier = text.substring(0, dot);
+    rest = text.substring(dot + 1);
+  } else {
+    const [first, ...others] = text.split('.');
+    const local_type = pkg.types.some((t) => t.name === first);
+    if (others.length > 0 && (others.length === 2 || !local_type)) {
+      qualifier = first;
+      rest = others.join('.');
+    }
+  }
+  const [name, member] = rest.split('.');
+
+  let target_pkg = pkg;
+  let import_path = null;
+  if (qualifier !== null && slash !== -1) {
+    import_path = qualifier;
+    target_pkg = packages.find(
+      (p) =>
+        p.directory !== '.' &&
+        (qualifier === p.directory || qualifier.endsWith(`/${p.directory}`))
+    );
+  } else if (qualifier !== null) {
+    if (is_exported(qualifier)) {
+      // A type name missing from the package
+      return { status: 'unresolved', resolved: null, import_path: null };
+    }
+    target_pkg = get_imported_packages(pkg, filename, packages).get(qualifier);
+    if (!target_pkg) {
+      const imp = pkg.imports.find(
+        (i) => i.filename === filename && get_import_name(i).name === qualifier
+      );
+      import_path = imp ? imp.path : qualifier;
+    }
+  }
+  if (!target_pkg) {
+    return { status: 'external', resolved: null, import_path };
+  }
+
+  const kind = find_package_declaration(target_pkg, name, member);
+  return {
+    status: kind ? 'resolved' : 'unresolved',
+    resolved: kind
+      ? {
+          package: target_pkg.name,
+          directory: target_pkg.directory,
+          name: member ? `${name}.${member}` : name,
+          kind
+        }
+      : null,
+    import_path: null
+  };
+};
+
+/**
+ * Extract and resolve the doc links of the package docs and exported
+ * symbols of a set of packages (see list_exported_symbols).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.unresolved_only=false] - Only list symbols with unresolved links
+ * @returns {Object} Symbols with their doc_links and urls, the unresolved links and a summary
+ */
+const find_doc_links = (packages, options = {}) => {
+  const documented = list_exported_symbols(packages).filter((s) => s.doc);
+  for (const pkg of packages) {
+    if (!pkg.doc) continue;
+    documented.push({
+      pkg,
+      kind: 'package',
+      name: pkg.name,
+      filename: pkg.doc_file,
+      line: pkg.doc_line,
+      doc: pkg.doc
+    });
+  }
+
+  const symbols = [];
+  const unresolved = [];
+  const counts = Object.fromEntries(DOC_LINK_STATUSES.map((s) => [s, 0]));
+  let url_count = 0;
+  for (const symbol of documented) {
+    const links = parse_doc_links(symbol.doc);
+    if (links.symbols.length === 0 && links.urls.length === 0) continue;
+
+    const doc_links = links.symbols.map((link) => ({
+      ...link,
+      ...resolve_doc_link(link.target, symbol.pkg, symbol.filename, packages)
+    }));
+    const entry = {
+      name: symbol.name,
+      kind: symbol.kind,
+      package: symbol.pkg.name,
+      directory: symbol.pkg.directory,
+      filename: symbol.filename,
+      line: symbol.line,
+      doc_links,
+      urls: links.urls
+    };
+    for (const link of doc_links) {
+      counts[link.status]++;
+      if (link.status !== 'unresolved') continue;
+      unresolved.push({
+        symbol: entry.name,
+        kind: entry.kind,
+        filename: entry.filename,
+        line: entry.line,
+        link: link.text,
+        message: `doc comment of ${entry.kind} ${entry.name} links to ${link.text}, which does not exist`
+      });
+    }
+    url_count += links.urls.length;
+
+    const has_unresolved = doc_links.some((l) => l.status === 'unresolved');
+    if (options.unresolved_only && !has_unresolved) continue;
+    symbols.push(entry);
+  }
+
+  const by_position = function sort_by_position(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  };
+  return {
+    symbols: symbols.sort(by_position),
+    unresolved: unresolved.sort(by_position),
+    summary: {
+      symbols: symbols.length,
+      links: counts.resolved + counts.external + counts.unresolved,
+      ...counts,
+      urls: url_count
+    }
+  };
+};
+
+/**
+ * Find the doc links of a set of Go sources, such as the files of a
+ * directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_doc_links)
+ * @param {string[]} [options.exclude=[]] - Globs of files to skip, such as generated code
+ * @returns {Object} Doc links (see find_doc_links)
+ */
+const find_source_doc_links = (sources, options = {}) => {
+  const exclude = (options.exclude || []).map(glob_to_regexp);
+  const files = sources
+    .filter((file) => !exclude.some((re) => re.test(file.filename)))
+    .map((file) => parse_go_file(file.source, file.filename));
+  return find_doc_links(group_go_packages(files), options);
+};
+
+/**
+ * Find the doc links of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_source_doc_links)
+ * @returns {Promise<Object>} Doc links with a summary
+ */
+const analyze_project_doc_links = async (project_id, options = {}) => {
+  return find_source_doc_links(await load_go_sources(project_id), options);
+};
+
+export {
+  analyze_package_docs,
+  summarize_package_doc,
+  get_doc_synopsis,
+  analyze_project_undocumented,
+  find_source_undocumented,
+  find_undocumented_symbols,
+  analyze_project_doc_names,
+  find_source_doc_names,
+  find_doc_name_mismatches,
+  check_doc_name,
+  analyze_project_doc_coverage,
+  find_source_doc_coverage,
+  compute_doc_coverage,
+  compare_doc_coverage,
+  analyze_project_doc_links,
+  find_source_doc_links,
+  find_doc_links,
+  parse_doc_links,
+  resolve_doc_link,
+  glob_to_regexp,
+  DOC_KINDS,
+  DEFAULT_DOC_KINDS,
+  DOC_NAME_KINDS,
+  DOC_COVERAGE_VERSION,
+  DOC_LINK_STATUSES
+};
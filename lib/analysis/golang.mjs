@@ -0,0 +1,2563 @@
+'use strict';
+
+/**
+ * @fileoverview Lightweight Go declaration parser.
+ * Extracts package clauses, build constraints, imports, constants,
+ * variables, types (struct fields and interface methods) and functions
+ * (including methods and their receivers) from Go source text.  The
+ * entity table only stores a function's text, so Go specific analyses
+ * use this module to work with declarations read from the sourcecode table.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/golang
+ */
+
+import { posix } from 'path';
+import { query } from '../db.mjs';
+
+/**
+ * Go keywords that can begin a type expression.  Used to tell an unnamed
+ * parameter such as `chan int` apart from a named one such as `c int`.
+ */
+const TYPE_KEYWORDS = new Set(['chan', 'func', 'map', 'struct', 'interface']);
+
+/**
+ * Go predeclared (builtin) identifiers.
+ */
+const GO_BUILTINS = {
+  types: [
+    'any',
+    'bool',
+    'byte',
+    'comparable',
+    'complex64',
+    'complex128',
+    'error',
+    'float32',
+    'float64',
+    'int',
+    'int8',
+    'int16',
+    'int32',
+    'int64',
+    'rune',
+    'string',
+    'uint',
+    'uint8',
+    'uint16',
+    'uint32',
+    'uint64',
+    'uintptr'
+  ],
+  constants: ['true', 'false', 'iota'],
+  zero: ['nil'],
+  functions: [
+    'append',
+    'cap',
+    'clear',
+    'close',
+    'complex',
+    'copy',
+    'delete',
+    'imag',
+    'len',
+    'make',
+    'max',
+    'min',
+    'new',
+    'panic',
+    'print',
+    'println',
+    'real',
+    'recover'
+  ]
+};
+
+// ============================================================================
+// SOURCE SCANNING HELPERS
+// ============================================================================
+
+/**
+ * Find the comments, string literals and rune literals in Go source.
+ * @param {string} source - Go source code
+ * @returns {Object[]} Ranges with type ('comment' or 'string'), start and end (exclusive)
+ */
+const find_literal_ranges = (source) => {
+  const ranges = [];
+  const length = source.length;
+  let i = 0;
+
+  while (i < length) {
+    const ch = source[i];
+    const next = source[i + 1];
+
+    if (ch === '/' && next === '/') {
+      const end = source.indexOf('\n', i);
+      const stop = end === -1 ? length : end;
+      ranges.push({ type: 'comment', start: i, end: stop });
+      i = stop;
+    } else if (ch === '/' && next === '*') {
+      const end = source.indexOf('*/', i + 2);
+      const stop = end === -1 ? length : end + 2;
+      ranges.push({ type: 'comment', start: i, end: stop });
+      i = stop;
+    } else if (ch === '`') {
+      const end = source.indexOf('`', i + 1);
+      const stop = end === -1 ? length : end + 1;
+      ranges.push({ type: 'string', start: i, end: stop });
+      i = stop;
+    } else if (ch === '"' || ch === "'") {
+      let j = i + 1;
+      while (j < length && source[j] !== ch && source[j] !== '\n') {
+        if (source[j] === '\\') j++;
+        j++;
+      }
+      const stop = Math.min(j + 1, length);
+      ranges.push({ type: 'string', start: i, end: stop });
+      i = stop;
+    } else {
+      i++;
+    }
+  }
+
+  return ranges;
+};
+
+/**
+ * Replace the contents of comments, string literals and rune literals with
+ * spaces, preserving offsets and newlines.  String delimiters are kept so
+ * that literal positions can still be found; comments are blanked entirely.
+ * @param {string} source - Go source code
+ * @returns {string} Masked source of the same length
+ */
+const mask_source = (source) => {
+  const out = source.split('');
+
+  for (const range of find_literal_ranges(source)) {
+    const from = range.type === 'string' ? range.start + 1 : range.start;
+    const to = range.type === 'string' ? range.end - 1 : range.end;
+    for (let j = from; j < to; j++) {
+      if (out[j] !== '\n') out[j] = ' ';
+    }
+  }
+
+  return out.join('');
+};
+
+/**
+ * Build a line index for converting offsets to line numbers.
+ * @param {string} source - Source text
+ * @returns {number[]} Offsets at which each line starts
+ */
+const build_line_index = (source) => {
+  const starts = [0];
+  for (let i = 0; i < source.length; i++) {
+    if (source[i] === '\n') starts.push(i + 1);
+  }
+  return starts;
+};
+
+/**
+ * Convert an offset to a 1-based line number.
+ * @param {number[]} line_index - Line index from build_line_index
+ * @param {number} offset - Offset into the source
+ * @returns {number} Line number
+ */
+const line_at = (line_index, offset) => {
+  let low = 0;
+  let high = line_index.length - 1;
+  while (low < high) {
+    const mid = (low + high + 1) >> 1;
+    if (line_index[mid] <= offset) low = mid;
+    else high = mid - 1;
+  }
+  return low + 1;
+};
+
+/**
+ * Find the bracket matching the one at an offset in masked source.
+ * @param {string} masked - Masked source
+ * @param {number} open_index - Offset of '(', '[' or '{'
+ * @returns {number} Offset of the matching bracket, or -1
+ */
+const find_matching = (masked, open_index) => {
+  const pairs = { '(': ')', '[': ']', '{': '}' };
+  const open = masked[open_index];
+  const close = pairs[open];
+  if (!close) return -1;
+
+  let depth = 0;
+  for (let i = open_index; i < masked.length; i++) {
+    const ch = masked[i];
+    if (ch === '(' || ch === '[' || ch === '{') depth++;
+    else if (ch === ')' || ch === ']' || ch === '}') {
+      depth--;
+      if (depth === 0) return ch === close ? i : -1;
+    }
+  }
+  return -1;
+};
+
+/**
+ * Split text on a separator that is not nested inside brackets.
+ * @param {string} text - Text to split
+ * @param {string} [separator=','] - Single character separator
+ * @returns {string[]} Trimmed, non-empty parts
+ */
+const split_top_level = (text, separator = ',') => {
+  const masked = mask_source(text);
+  const parts = [];
+  let depth = 0;
+  let start = 0;
+
+  for (let i = 0; i < masked.length; i++) {
+    const ch = masked[i];
+    if (ch === '(' || ch === '[' || ch === '{') depth++;
+    else if (ch === ')' || ch === ']' || ch === '}') depth--;
+    else if (ch === separator && depth === 0) {
+      parts.push(text.substring(start, i));
+      start = i + 1;
+    }
+  }
+  parts.push(text.substring(start));
+
+  return parts.map((p) => p.trim()).filter((p) => p.length > 0);
+};
+
+/**
+ * Split a block body into its entries (one per line or ';'), keeping
+ * multi-line entries such as nested struct types together.
+ * @param {string} body - Block text without the enclosing braces/parens
+ * @param {number} body_offset - Offset of the body in the full source
+ * @returns {Object[]} Entries with text, offset and a blank_before flag
+ */
+const split_block_entries = (body, body_offset) => {
+  const masked = mask_source(body);
+  const entries = [];
+  let depth = 0;
+  let start = 0;
+  let pending_blank = false;
+
+  const push = (end) => {
+    const raw = body.substring(start, end);
+    const masked_raw = masked.substring(start, end);
+    if (masked_raw.trim().length > 0) {
+      const lead = masked_raw.length - masked_raw.trimStart().length;
+      entries.push({
+        text: raw.substring(lead).trim(),
+        offset: body_offset + start + lead,
+        blank_before: pending_blank
+      });
+      pending_blank = false;
+    } else if (raw.trim().length === 0 && entries.length > 0) {
+      // A blank line is only a group separator when it follows an entry
+      pending_blank = true;
+    }
+  };
+
+  for (let i = 0; i < masked.length; i++) {
+    const ch = masked[i];
+    if (ch === '(' || ch === '[' || ch === '{') depth++;
+    else if (ch === ')' || ch === ']' || ch === '}') depth--;
+    else if ((ch === '\n' || ch === ';') && depth === 0) {
+      push(i);
+      start = i + 1;
+    }
+  }
+  push(masked.length);
+
+  return entries;
+};
+
+/**
+ * Strip comments from a piece of Go source, leaving strings intact.
+ * @param {string} text - Go source text
+ * @returns {string} Text with comments removed
+ */
+const strip_comments = (text) => {
+  let out = '';
+  let position = 0;
+  for (const range of find_literal_ranges(text)) {
+    if (range.type !== 'comment') continue;
+    out += text.substring(position, range.start);
+    position = range.end;
+  }
+  return out + text.substring(position);
+};
+
+/**
+ * Get the text of a trailing line comment.
+ * @param {string} text - Go source text (a single entry or line)
+ * @returns {string|null} Comment text without the marker, or null
+ */
+const get_trailing_comment = (text) => {
+  const comments = find_literal_ranges(text).filter(
+    (r) => r.type === 'comment'
+  );
+  if (comments.length === 0) return null;
+  const last = comments[comments.length - 1];
+  return get_comment_text(text.substring(last.start, last.end)) || null;
+};
+
+/**
+ * Collect the doc comment immediately preceding a line.
+ * @param {string[]} lines - Source lines
+ * @param {number} line - 1-based line of the declaration
+ * @returns {string|null} Comment text (with markers) or null
+ */
+const get_doc_comment = (lines, line) => {
+  let index = line - 2;
+  if (index < 0) return null;
+
+  const trimmed = lines[index].trim();
+  if (trimmed.endsWith('*/')) {
+    const block = [];
+    while (index >= 0) {
+      block.unshift(lines[index].trim());
+      if (lines[index].includes('/*')) break;
+      index--;
+    }
+    return block.join('\n');
+  }
+
+  const comment_lines = [];
+  while (index >= 0 && lines[index].trim().startsWith('//')) {
+    comment_lines.unshift(lines[index].trim());
+    index--;
+  }
+
+  return comment_lines.length > 0 ? comment_lines.join('\n') : null;
+};
+
+/**
+ * Remove comment markers from a doc comment.
+ * @param {string} comment - Comment text with // or /* markers
+ * @returns {string} Plain comment text
+ */
+const get_comment_text = (comment) => {
+  if (!comment) return '';
+  return comment
+    .replace(/^\/\*+/, '')
+    .replace(/\*+\/$/, '')
+    .split('\n')
+    .filter((l) => !/^\s*\/\/go:/.test(l))
+    .map((l) => l.replace(/^\s*\/\/\s?/, '').replace(/^\s*\*\s?/, ''))
+    .join('\n')
+    .trim();
+};
+
+/**
+ * Check whether a Go identifier is exported.
+ * @param {string} name - Identifier
+ * @returns {boolean} True if the name starts with an upper case letter
+ */
+const is_exported = (name) => {
+  return /^[A-Z]/.test(name || '');
+};
+
+// ============================================================================
+// DECLARATION PARSING
+// ============================================================================
+
+/**
+ * Parse a parameter or result list.
+ * Handles grouped names (`a, b int`), unnamed parameters and variadics.
+ * @param {string} text - List text without the enclosing parentheses
+ * @returns {Object[]} Parameters with name (or null), type and variadic flag
+ */
+const parse_parameters = (text) => {
+  const parts = split_top_level(strip_comments(text || ''));
+  const parsed = parts.map((part) => {
+    const match = part.match(/^([A-Za-z_]\w*)\s+(\S[\s\S]*)$/);
+    if (match && !TYPE_KEYWORDS.has(match[1])) {
+      return { name: match[1], type: match[2].trim() };
+    }
+    return { name: null, type: part };
+  });
+
+  // Go lists are either all named or all unnamed; in a named list an entry
+  // without a type shares the type of the next typed entry
+  const named = parsed.some((p) => p.name !== null);
+  if (named) {
+    let pending_type = null;
+    for (let i = parsed.length - 1; i >= 0; i--) {
+      if (parsed[i].name !== null) {
+        pending_type = parsed[i].type;
+      } else if (/^[A-Za-z_]\w*$/.test(parsed[i].type)) {
+        parsed[i] = { name: parsed[i].type, type: pending_type };
+      }
+    }
+  }
+
+  return parsed.map(function format_parameter(p) {
+    const variadic = (p.type || '').startsWith('...');
+    return {
+      name: p.name,
+      type: variadic ? p.type.substring(3).trim() : p.type,
+      variadic
+    };
+  });
+};
+
+/**
+ * Parse a method receiver.
+ * @param {string} text - Receiver text without parentheses (e.g. `c *Counter`)
+ * @returns {Object|null} Receiver with name, type, pointer flag and type_args
+ */
+const parse_receiver = (text) => {
+  const params = parse_parameters(text);
+  if (params.length === 0) return null;
+
+  let type = params[0].type.trim();
+  const pointer = type.startsWith('*');
+  if (pointer) type = type.substring(1).trim();
+
+  let type_args = null;
+  const bracket = type.indexOf('[');
+  if (bracket !== -1) {
+    type_args = type.substring(bracket + 1, type.lastIndexOf(']')).trim();
+    type = type.substring(0, bracket).trim();
+  }
+
+  return { name: params[0].name, type, pointer, type_args };
+};
+
+/**
+ * Split a leading identifier list from the rest of the text.
+ * @param {string} text - Text such as `a, b int = 1, 2`
+ * @returns {Object} { names, rest }
+ */
+const split_identifier_list = (text) => {
+  const match = text.match(
+    /^([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)([\s\S]*)$/
+  );
+  if (!match) return { names: [], rest: text };
+  return {
+    names: match[1].split(',').map((n) => n.trim()),
+    rest: match[2].trim()
+  };
+};
+
+/**
+ * Parse a struct field entry.
+ * @param {string} text - Field text such as `Name string \`json:"name"\``
+ * @returns {Object} Field with names, type, tag and embedded flag
+ */
+const parse_field = (text) => {
+  const code = strip_comments(text).trim();
+  let tag = null;
+  let rest = code;
+
+  const tag_match = code.match(/\s*(`[^`]*`|"(?:[^"\\]|\\.)*")$/);
+  if (tag_match) {
+    tag = tag_match[1].substring(1, tag_match[1].length - 1);
+    rest = code.substring(0, code.length - tag_match[0].length).trim();
+  }
+
+  const named = rest.match(/^([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)\s+(\S[\s\S]*)$/);
+  if (named) {
+    return {
+      names: named[1].split(',').map((n) => n.trim()),
+      type: named[2].trim(),
+      tag,
+      embedded: false
+    };
+  }
+
+  // Embedded field: the field name is the unqualified type name
+  const base = rest.replace(/^\*/, '').replace(/\[[\s\S]*\]$/, '');
+  const name = base.includes('.') ? base.split('.').pop() : base;
+  return { names: [name], type: rest, tag, embedded: true };
+};
+
+/**
+ * Find the comment heading a group of struct fields, the title of a
+ * logical section of the struct: the last comment standing alone (followed
+ * by a blank line) between the previous field and the group, or else the
+ * doc comment of the group's first field when the group has several
+ * fields and the comment does not begin with the field's name, which
+ * would make it the doc of the field alone.
+ * @param {string[]} lines - Source lines
+ * @param {number} from - Line after which the comment may start
+ * @param {Object[]} group - Fields of the group
+ * @returns {string|null} Comment (with markers), or null if the group has none
+ */
+const get_field_group_header = (lines, from, group) => {
+  const [first] = group;
+  let header = null;
+  let block = [];
+  for (let line = from + 1; line < first.line; line++) {
+    const text = (lines[line - 1] || '').trim();
+    if (text.startsWith('//')) {
+      block.push(text);
+      continue;
+    }
+    if (text === '' && block.length > 0) header = block.join('\n');
+    block = [];
+  }
+  if (header) return header;
+
+  const doc_name = get_comment_text(first.doc).split(/\s+/)[0];
+  if (first.doc && group.length > 1 && !first.names.includes(doc_name)) {
+    return first.doc;
+  }
+  return null;
+};
+
+/**
+ * Parse the fields of a struct body.
+ * Fields separated by blank lines are placed in different groups, each
+ * with the comment heading it, if any (see get_field_group_header).
+ * @param {string} source - Full source text
+ * @param {number} open_index - Offset of the struct's '{'
+ * @param {number} close_index - Offset of the matching '}'
+ * @param {number[]} line_index - Line index of the source
+ * @param {string[]} lines - Source lines
+ * @returns {Object[]} Parsed fields
+ */
+const parse_struct_fields = (
+  source,
+  open_index,
+  close_index,
+  line_index,
+  lines
+) => {
+  const body = source.substring(open_index + 1, close_index);
+  const entries = split_block_entries(body, open_index + 1);
+  const fields = [];
+  const end_lines = [];
+  let group = 0;
+
+  for (const entry of entries) {
+    if (entry.blank_before) group++;
+    const field = parse_field(entry.text);
+    const line = line_at(line_index, entry.offset);
+
+    fields.push({
+      ...field,
+      line,
+      group,
+      group_header: null,
+      doc: get_doc_comment(lines, line),
+      comment: get_trailing_comment(entry.text)
+    });
+    end_lines.push(line_at(line_index, entry.offset + entry.text.length - 1));
+  }
+
+  let from = line_at(line_index, open_index);
+  for (let i = 0; i < fields.length; ) {
+    let end = i;
+    while (end < fields.length && fields[end].group === fields[i].group) end++;
+    const members = fields.slice(i, end);
+    const header = get_field_group_header(lines, from, members);
+    for (const field of members) field.group_header = header;
+    from = end_lines[end - 1];
+    i = end;
+  }
+
+  return fields;
+};
+
+/**
+ * Parse the entries of an interface body.
+ * @param {string} source - Full source text
+ * @param {number} open_index - Offset of the interface's '{'
+ * @param {number} close_index - Offset of the matching '}'
+ * @param {number[]} line_index - Line index of the source
+ * @param {string[]} lines - Source lines
+ * @returns {Object} { methods, embeds }
+ */
+const parse_interface_body = (
+  source,
+  open_index,
+  close_index,
+  line_index,
+  lines
+) => {
+  const body = source.substring(open_index + 1, close_index);
+  const entries = split_block_entries(body, open_index + 1);
+  const methods = [];
+  const embeds = [];
+
+  for (const entry of entries) {
+    const text = strip_comments(entry.text).trim();
+    const line = line_at(line_index, entry.offset);
+    const match = text.match(/^([A-Za-z_]\w*)\s*\(/);
+
+    if (match) {
+      const masked = mask_source(text);
+      const open = text.indexOf('(');
+      const close = find_matching(masked, open);
+      const params_text = text.substring(open + 1, close);
+      const results_text = text.substring(close + 1).trim();
+      methods.push({
+        name: match[1],
+        params: parse_parameters(params_text),
+        results: parse_results(results_text),
+        params_text,
+        results_text,
+        signature: text,
+        line,
+        doc: get_doc_comment(lines, line)
+      });
+    } else {
+      embeds.push({ type: text, line });
+    }
+  }
+
+  return { methods, embeds };
+};
+
+/**
+ * Type kinds (from classify_type) whose values can be nil.
+ */
+const NILABLE_KINDS = new Set([
+  'pointer',
+  'interface',
+  'map',
+  'slice',
+  'chan',
+  'func'
+]);
+
+/**
+ * Named interface types known without resolving them: the predeclared
+ * interfaces and common standard library ones.
+ */
+const KNOWN_INTERFACES = new Set([
+  'any',
+  'error',
+  'context.Context',
+  'fmt.Stringer',
+  'io.Reader',
+  'io.Writer',
+  'io.Closer',
+  'io.ReadCloser',
+  'io.WriteCloser',
+  'io.ReadWriter',
+  'io.ReadWriteCloser',
+  'net.Conn',
+  'net.Listener',
+  'http.Handler',
+  'sort.Interface'
+]);
+
+/**
+ * Check whether values of a type can be nil, from the kind of the type
+ * expression: pointers, interfaces, maps, slices, channels and functions.
+ * Of the named types, only well known interfaces (error, io.Reader...)
+ * are nilable here; package types are resolved by
+ * resolve_nilable_results.
+ * @param {string} type_text - Type expression
+ * @returns {boolean} Whether the type can be nil
+ */
+const is_nilable_type = (type_text) => {
+  const text = (type_text || '').trim();
+  if (KNOWN_INTERFACES.has(text)) return true;
+  return NILABLE_KINDS.has(classify_type(text));
+};
+
+/**
+ * Parse a result list, which may be a single unparenthesized type.
+ * Each result is marked nilable when its type can be nil, so callers know
+ * when a nil check is needed: `(float64, error)` returns a non-nilable
+ * float64 and a nilable error.
+ * @param {string} text - Result text (e.g. `(int, error)` or `error`)
+ * @returns {Object[]} Results in the same shape as parse_parameters, with nilable
+ */
+const parse_results = (text) => {
+  const trimmed = (text || '').trim();
+  if (!trimmed) return [];
+  let results = [{ name: null, type: trimmed, variadic: false }];
+  if (trimmed.startsWith('(')) {
+    const close = find_matching(mask_source(trimmed), 0);
+    if (close === trimmed.length - 1) {
+      results = parse_parameters(trimmed.substring(1, close));
+    }
+  }
+  return results.map((r) => ({ ...r, nilable: is_nilable_type(r.type) }));
+};
+
+/**
+ * Classify a type expression.
+ * @param {string} type_text - The type expression
+ * @returns {string} Kind: struct, interface, func, map, slice, array, chan, pointer or named
+ */
+const classify_type = (type_text) => {
+  const text = (type_text || '').trim();
+  if (/^struct\s*\{/.test(text)) return 'struct';
+  if (/^interface\s*\{/.test(text)) return 'interface';
+  if (/^func\s*\(/.test(text)) return 'func';
+  if (/^map\s*\[/.test(text)) return 'map';
+  if (/^\[\s*\]/.test(text)) return 'slice';
+  if (/^\[/.test(text)) return 'array';
+  if (/^(<-\s*)?chan\b/.test(text)) return 'chan';
+  if (text.startsWith('*')) return 'pointer';
+  return 'named';
+};
+
+/**
+ * Find the end offset of a declaration spec that starts at an offset.
+ * The spec ends at the first newline or ';' outside of brackets.
+ * @param {string} masked - Masked source
+ * @param {number} start - Offset where the spec begins
+ * @returns {number} Offset of the end of the spec (exclusive)
+ */
+const find_spec_end = (masked, start) => {
+  let depth = 0;
+  for (let i = start; i < masked.length; i++) {
+    const ch = masked[i];
+    if (ch === '(' || ch === '[' || ch === '{') depth++;
+    else if (ch === ')' || ch === ']' || ch === '}') depth--;
+    else if ((ch === '\n' || ch === ';') && depth <= 0) return i;
+  }
+  return masked.length;
+};
+
+/**
+ * Parse a type spec (`Name[T any] struct { ... }`).
+ * @param {Object} ctx - Parse context
+ * @param {number} start - Offset where the spec begins
+ * @param {number} end - Offset where the spec ends
+ * @returns {Object|null} Parsed type
+ */
+const parse_type_spec = (ctx, start, end) => {
+  const { source, masked, line_index, lines } = ctx;
+  const text = source.substring(start, end);
+  const name_match = text.match(/^\s*([A-Za-z_]\w*)/);
+  if (!name_match) return null;
+
+  const name = name_match[1];
+  let pos = start + name_match[0].length;
+
+  // Type parameters: `[` directly after the name unless it is an array type
+  let type_params = null;
+  const after_name = masked.substring(pos, end);
+  const bracket_match = after_name.match(/^\s*\[/);
+  if (bracket_match) {
+    const open = pos + bracket_match[0].length - 1;
+    const close = find_matching(masked, open);
+    const inner = source.substring(open + 1, close).trim();
+    if (/^[A-Za-z_]\w*(\s*,\s*[A-Za-z_]\w*)*\s+[~[(A-Za-z_]/.test(inner)) {
+      type_params = inner;
+      pos = close + 1;
+    }
+  }
+
+  let rest = source.substring(pos, end).trim();
+  const alias = rest.startsWith('=');
+  if (alias) rest = rest.substring(1).trim();
+
+  const rest_offset = source.indexOf(rest, pos);
+  const kind = alias ? 'alias' : classify_type(rest);
+
+  const type = {
+    name,
+    kind,
+    underlying: strip_comments(rest).trim(),
+    type_params,
+    exported: is_exported(name),
+    line: line_at(line_index, start + text.search(/\S/)),
+    end_line: line_at(line_index, end),
+    doc: null,
+    fields: [],
+    methods: [],
+    embeds: []
+  };
+
+  if (kind === 'struct' || kind === 'interface') {
+    const open = masked.indexOf('{', rest_offset);
+    const close = find_matching(masked, open);
+    if (open !== -1 && close !== -1) {
+      type.end_line = line_at(line_index, close);
+      if (kind === 'struct') {
+        type.fields = parse_struct_fields(source, open, close, line_index, lines);
+      } else {
+        const body = parse_interface_body(
+          source,
+          open,
+          close,
+          line_index,
+          lines
+        );
+        type.methods = body.methods;
+        type.embeds = body.embeds;
+      }
+    }
+  }
+
+  return type;
+};
+
+/**
+ * Parse a const or var spec (`a, b int = 1, 2`).
+ * @param {string} text - Spec text
+ * @returns {Object} { names, type, values }
+ */
+const parse_value_spec = (text) => {
+  const code = strip_comments(text).trim();
+  const { names, rest } = split_identifier_list(code);
+
+  let type = null;
+  let values = [];
+  const masked = mask_source(rest);
+  let eq = -1;
+  let depth = 0;
+  for (let i = 0; i < masked.length; i++) {
+    const ch = masked[i];
+    if (ch === '(' || ch === '[' || ch === '{') depth++;
+    else if (ch === ')' || ch === ']' || ch === '}') depth--;
+    else if (ch === '=' && depth === 0 && masked[i + 1] !== '=') {
+      eq = i;
+      break;
+    }
+  }
+
+  if (eq === -1) {
+    type = rest.trim() || null;
+  } else {
+    type = rest.substring(0, eq).trim() || null;
+    values = split_top_level(rest.substring(eq + 1));
+  }
+
+  return { names, type, values };
+};
+
+/**
+ * Parse a function or method declaration beginning at the `func` keyword.
+ * @param {Object} ctx - Parse context
+ * @param {number} start - Offset of the `func` keyword
+ * @returns {Object} Parsed function and the offset where it ends
+ */
+const parse_function = (ctx, start) => {
+  const { source, masked, line_index } = ctx;
+  let pos = start + 4;
+
+  const skip_space = () => {
+    while (pos < masked.length && /[ \t]/.test(masked[pos])) pos++;
+  };
+
+  skip_space();
+
+  let receiver = null;
+  let receiver_text = null;
+  if (masked[pos] === '(') {
+    const close = find_matching(masked, pos);
+    receiver_text = source.substring(pos + 1, close);
+    receiver = parse_receiver(receiver_text);
+    pos = close + 1;
+    skip_space();
+  }
+
+  const name_match = masked.substring(pos).match(/^[A-Za-z_]\w*/);
+  const name = name_match ? name_match[0] : null;
+  pos += name ? name.length : 0;
+  skip_space();
+
+  let type_params = null;
+  if (masked[pos] === '[') {
+    const close = find_matching(masked, pos);
+    type_params = source.substring(pos + 1, close).trim();
+    pos = close + 1;
+    skip_space();
+  }
+
+  let params_text = '';
+  if (masked[pos] === '(') {
+    const close = find_matching(masked, pos);
+    params_text = source.substring(pos + 1, close);
+    pos = close + 1;
+  }
+
+  // Results run until the body's '{' (skipping struct{}/interface{} types)
+  const results_start = pos;
+  let body_open = -1;
+  while (pos < masked.length) {
+    const ch = masked[pos];
+    if (ch === '(' || ch === '[') {
+      pos = find_matching(masked, pos) + 1;
+      if (pos === 0) break;
+      continue;
+    }
+    if (ch === '{') {
+      const before = masked.substring(results_start, pos).trimEnd();
+      if (/\b(struct|interface)$/.test(before)) {
+        pos = find_matching(masked, pos) + 1;
+        if (pos === 0) break;
+        continue;
+      }
+      body_open = pos;
+      break;
+    }
+    if (ch === '\n') break;
+    pos++;
+  }
+
+  const results_end = body_open === -1 ? pos : body_open;
+  const results_text = source.substring(results_start, results_end).trim();
+
+  let body = null;
+  let body_close = -1;
+  let end = results_end;
+  if (body_open !== -1) {
+    body_close = find_matching(masked, body_open);
+    if (body_close === -1) body_close = masked.length - 1;
+    body = source.substring(body_open, body_close + 1);
+    end = body_close + 1;
+  }
+
+  const fn = {
+    name,
+    receiver,
+    receiver_text,
+    type_params,
+    params: parse_parameters(params_text),
+    results: parse_results(results_text),
+    params_text: params_text.replace(/\s+/g, ' ').trim(),
+    results_text: results_text.replace(/\s+/g, ' ').trim(),
+    signature: source.substring(start, results_end).replace(/\s+/g, ' ').trim(),
+    body,
+    body_offset: body_open,
+    body_line: body_open === -1 ? null : line_at(line_index, body_open),
+    body_column:
+      body_open === -1
+        ? null
+        : body_open - line_index[line_at(line_index, body_open) - 1] + 1,
+    exported: is_exported(name),
+    line: line_at(line_index, start),
+    end_line: line_at(line_index, Math.max(start, end - 1)),
+    doc: null
+  };
+
+  return { fn, end };
+};
+
+/**
+ * Parse the build constraints that appear before the package clause.
+ * @param {string[]} lines - Source lines
+ * @returns {Object} { go_build, plus_build } raw constraint expressions
+ */
+const parse_build_constraints = (lines) => {
+  let go_build = null;
+  const plus_build = [];
+
+  for (const line of lines) {
+    const trimmed = line.trim();
+    if (/^package\s/.test(trimmed)) break;
+
+    const go_match = trimmed.match(/^\/\/go:build\s+(.+)$/);
+    if (go_match) {
+      go_build = go_match[1].trim();
+      continue;
+    }
+
+    const plus_match = trimmed.match(/^\/\/\s*\+build\s+(.+)$/);
+    if (plus_match) plus_build.push(plus_match[1].trim());
+  }
+
+  return { go_build, plus_build };
+};
+
+/**
+ * Check whether build constraints exclude a file from every build, as
+ * `//go:build ignore` does for scripts and code generators run with
+ * `go run`.  The ignore tag must be required, alone or in a conjunction.
+ * @param {Object} constraints - Build constraints (from parse_build_constraints)
+ * @returns {boolean} True for files that are never built
+ */
+const is_build_ignored = (constraints) => {
+  if (constraints.go_build) {
+    // `ignore && linux` can never be satisfied, `ignore || linux` can
+    const expression = constraints.go_build;
+    if (/\|\||[()]/.test(expression)) return false;
+    return expression.split('&&').some((term) => term.trim() === 'ignore');
+  }
+  // +build lines are ANDed; within a line spaces are ORs and commas ANDs
+  return constraints.plus_build.some(
+    (line) => !/\s/.test(line) && line.split(',').includes('ignore')
+  );
+};
+
+/**
+ * Operating systems recognised in filenames (`_linux.go`), from Go's
+ * go/build syslist.
+ */
+const KNOWN_GOOS = new Set([
+  'aix',
+  'android',
+  'darwin',
+  'dragonfly',
+  'freebsd',
+  'hurd',
+  'illumos',
+  'ios',
+  'js',
+  'linux',
+  'nacl',
+  'netbsd',
+  'openbsd',
+  'plan9',
+  'solaris',
+  'wasip1',
+  'windows',
+  'zos'
+]);
+
+/**
+ * Architectures recognised in filenames (`_amd64.go`), from Go's
+ * go/build syslist.
+ */
+const KNOWN_GOARCH = new Set([
+  '386',
+  'amd64',
+  'amd64p32',
+  'arm',
+  'armbe',
+  'arm64',
+  'arm64be',
+  'loong64',
+  'mips',
+  'mipsle',
+  'mips64',
+  'mips64le',
+  'mips64p32',
+  'mips64p32le',
+  'ppc',
+  'ppc64',
+  'ppc64le',
+  'riscv',
+  'riscv64',
+  's390',
+  's390x',
+  'sparc',
+  'sparc64',
+  'wasm'
+]);
+
+/**
+ * Operating systems satisfying the `unix` build tag.
+ */
+const UNIX_GOOS = new Set([
+  'aix',
+  'android',
+  'darwin',
+  'dragonfly',
+  'freebsd',
+  'hurd',
+  'illumos',
+  'ios',
+  'linux',
+  'netbsd',
+  'openbsd',
+  'solaris'
+]);
+
+/**
+ * Parse the implicit constraints of a Go filename, following go/build:
+ * after removing `.go` and a `_test` suffix, the name ends in `_GOOS`,
+ * `_GOARCH` or `_GOOS_GOARCH`.  The part before the first underscore never
+ * counts, so `linux.go` is unconstrained.
+ * @param {string} filename - Filename, with or without directory
+ * @returns {Object} { goos, goarch, test }
+ */
+const parse_filename_constraints = (filename) => {
+  let name = posix.basename((filename || '').replace(/\\/g, '/'));
+  name = name.replace(/\.go$/, '');
+  const test = name.endsWith('_test');
+  if (test) name = name.slice(0, -'_test'.length);
+
+  const underscore = name.indexOf('_');
+  const parts = underscore === -1 ? [] : name.slice(underscore + 1).split('_');
+  const last = parts[parts.length - 1];
+  const before = parts[parts.length - 2];
+  let goos = null;
+  let goarch = null;
+
+  if (parts.length >= 2 && KNOWN_GOOS.has(before) && KNOWN_GOARCH.has(last)) {
+    goos = before;
+    goarch = last;
+  } else if (KNOWN_GOOS.has(last)) {
+    goos = last;
+  } else if (KNOWN_GOARCH.has(last)) {
+    goarch = last;
+  }
+
+  return { goos, goarch, test };
+};
+
+/**
+ * Convert `// +build` lines to a `//go:build` expression, as gofmt does:
+ * lines are ANDed, space separated options ORed and comma separated terms
+ * ANDed.
+ * @param {string[]} lines - +build line expressions
+ * @returns {string|null} Expression, or null without lines
+ */
+const convert_plus_build = (lines) => {
+  const clauses = lines.map(function convert_line(line) {
+    const options = line
+      .split(/\s+/)
+      .filter(Boolean)
+      .map((option) => option.split(',').join(' && '));
+    if (options.length === 1) return options[0];
+    return options
+      .map((o) => (o.includes('&&') ? `(${o})` : o))
+      .join(' || ');
+  });
+  if (clauses.length === 0) return null;
+  if (clauses.length === 1) return clauses[0];
+  return clauses.map((c) => (c.includes('||') ? `(${c})` : c)).join(' && ');
+};
+
+/**
+ * Merge the build constraints of a file with those implied by its name.
+ * The effective constraint is their conjunction: `//go:build !purego` in
+ * `poll_linux_amd64.go` gives `!purego && linux && amd64`.  Tags of the
+ * name the expression already requires are not repeated: `linux && !cgo`
+ * in `foo_linux.go` stays `linux && !cgo`.  The `_test` suffix is not a build tag and is reported separately.
+ * @param {Object} constraints - Build constraints (from parse_build_constraints)
+ * @param {string} filename - Filename
+ * @returns {Object} { expression, build, goos, goarch, test }; expression is null for unconstrained files
+ */
+const get_effective_constraint = (constraints, filename) => {
+  const build =
+    constraints.go_build || convert_plus_build(constraints.plus_build);
+  const { goos, goarch, test } = parse_filename_constraints(filename);
+
+  let terms = [goos, goarch].filter(Boolean);
+  if (build) {
+    // Only a top level || needs parentheses to bind before &&, and the
+    // top level && terms already require the tags they name
+    let depth = 0;
+    const top_level_or = [...build].some(function check_char(ch, i) {
+      if (ch === '(') depth++;
+      if (ch === ')') depth--;
+      return depth === 0 && ch === '|' && build[i + 1] === '|';
+    });
+    if (!top_level_or) {
+      const required = new Set(
+        split_top_level(build.replace(/&&/g, '&'), '&').map((term) =>
+          term.replace(/^\((.*)\)$/, '$1').trim()
+        )
+      );
+      terms = terms.filter((term) => !required.has(term));
+    }
+    const grouped = terms.length > 0 && top_level_or;
+    terms.unshift(grouped ? `(${build})` : build);
+  }
+
+  return {
+    expression: terms.length > 0 ? terms.join(' && ') : null,
+    build,
+    goos,
+    goarch,
+    test
+  };
+};
+
+/**
+ * Evaluate a build constraint expression for a set of satisfied tags.
+ * @param {string|null} expression - Expression (`linux && (amd64 || arm64)`)
+ * @param {Set<string>} tags - Satisfied tags
+ * @returns {boolean} True if the expression holds; unconstrained files always match
+ * @throws {Error} If the expression is malformed
+ */
+const evaluate_build_constraint = (expression, tags) => {
+  if (!expression) return true;
+  const tokens = expression.match(/&&|\|\||[!()]|[\w.]+|\S/g) || [];
+  let position = 0;
+
+  const fail = () => {
+    throw new Error(`Invalid build constraint '${expression}'`);
+  };
+  const parse_or = () => {
+    let value = parse_and();
+    while (tokens[position] === '||') {
+      position++;
+      // Evaluate both sides so that malformed expressions always fail
+      value = parse_and() || value;
+    }
+    return value;
+  };
+  const parse_and = () => {
+    let value = parse_not();
+    while (tokens[position] === '&&') {
+      position++;
+      value = parse_not() && value;
+    }
+    return value;
+  };
+  const parse_not = () => {
+    const token = tokens[position++];
+    if (token === '!') return !parse_not();
+    if (token === '(') {
+      const value = parse_or();
+      if (tokens[position++] !== ')') fail();
+      return value;
+    }
+    if (!token || !/^[\w.]+$/.test(token)) fail();
+    return tags.has(token);
+  };
+
+  const value = parse_or();
+  if (position !== tokens.length) fail();
+  return value;
+};
+
+/**
+ * Get the tags satisfied when building for a target, as the go command
+ * sets them: GOOS, GOARCH, `unix` on Unix-like systems and any extra tags.
+ * @param {Object} target - Target
+ * @param {string} [target.goos] - Operating system
+ * @param {string} [target.goarch] - Architecture
+ * @param {string[]} [target.tags] - Extra build tags (such as purego or go1.22)
+ * @returns {Set<string>} Satisfied tags
+ */
+const get_target_tags = (target) => {
+  const tags = new Set(target.tags || []);
+  if (target.goos) tags.add(target.goos);
+  if (target.goarch) tags.add(target.goarch);
+  if (UNIX_GOOS.has(target.goos)) tags.add('unix');
+  // As in go/build, android implies linux and ios implies darwin
+  if (target.goos === 'android') tags.add('linux');
+  if (target.goos === 'ios') tags.add('darwin');
+  return tags;
+};
+
+/**
+ * Separate the package doc comment from other comments before the package
+ * clause.  Following the `// Package name ...` convention, only a comment
+ * directly above the clause that starts with "Package" (or "Command" for
+ * main packages) is a package doc; anything else, such as license headers
+ * or file descriptions, is a file header.  Build constraints are ignored.
+ * @param {string[]} lines - Source lines
+ * @param {number} package_line - 1-based line of the package clause
+ * @returns {Object} { package_doc, package_doc_line, file_header }
+ */
+const get_package_comments = (lines, package_line) => {
+  const blocks = [];
+  let current = null;
+  let in_block_comment = false;
+
+  for (let index = 0; index < package_line - 1; index++) {
+    const trimmed = lines[index].trim();
+    const is_comment =
+      in_block_comment || trimmed.startsWith('//') || trimmed.startsWith('/*');
+    const is_constraint = /^\/\/(go:build\s|\s*\+build\s)/.test(trimmed);
+
+    if (!is_comment || is_constraint) {
+      current = null;
+      continue;
+    }
+    if (trimmed.startsWith('/*')) in_block_comment = true;
+    if (in_block_comment && trimmed.includes('*/')) in_block_comment = false;
+
+    if (!current) {
+      current = { line: index + 1, end_line: index + 1, lines: [] };
+      blocks.push(current);
+    }
+    current.lines.push(trimmed);
+    current.end_line = index + 1;
+  }
+
+  let package_doc = null;
+  let package_doc_line = null;
+  const last = blocks[blocks.length - 1];
+  if (last && last.end_line === package_line - 1) {
+    const text = get_comment_text(last.lines.join('\n'));
+    if (/^(Package|Command)\s+\S/.test(text)) {
+      blocks.pop();
+      package_doc = last.lines.join('\n');
+      package_doc_line = last.line;
+    }
+  }
+
+  const header = blocks.map((b) => b.lines.join('\n')).join('\n\n');
+  return { package_doc, package_doc_line, file_header: header || null };
+};
+
+/**
+ * Parse a Go source file into its top level declarations.
+ * @param {string} source - Go source code
+ * @param {string} [filename=''] - Filename (recorded on the result)
+ * @returns {Object} Parsed file with package, package doc, imports, consts, vars, types and functions
+ */
+const parse_go_file = (source, filename = '') => {
+  const text = source || '';
+  const masked = mask_source(text);
+  const line_index = build_line_index(text);
+  const lines = text.split('\n');
+  const ctx = { source: text, masked, line_index, lines };
+
+  const build_constraints = parse_build_constraints(lines);
+  const file = {
+    filename,
+    package: null,
+    package_line: null,
+    package_doc: null,
+    package_doc_line: null,
+    file_header: null,
+    build_constraints,
+    build_ignored: is_build_ignored(build_constraints),
+    effective_constraint: get_effective_constraint(build_constraints, filename),
+    imports: [],
+    consts: [],
+    vars: [],
+    types: [],
+    functions: []
+  };
+
+  const add_doc = (decl, group_doc) => {
+    decl.doc = get_doc_comment(lines, decl.line) || group_doc || null;
+    return decl;
+  };
+
+  // Walk top level declarations (keywords at the start of a line, depth 0)
+  const keyword = /^(package|import|const|var|type|func)\b/;
+  let pos = 0;
+
+  while (pos < masked.length) {
+    const line_end = masked.indexOf('\n', pos);
+    const stop = line_end === -1 ? masked.length : line_end;
+    const line_text = masked.substring(pos, stop);
+    const match = line_text.match(keyword);
+
+    if (!match) {
+      pos = stop + 1;
+      continue;
+    }
+
+    const kw = match[1];
+    const kw_end = pos + kw.length;
+
+    if (kw === 'package') {
+      const name_match = line_text.match(/^package\s+(\w+)/);
+      file.package = name_match ? name_match[1] : null;
+      file.package_line = line_at(line_index, pos);
+      Object.assign(file, get_package_comments(lines, file.package_line));
+      pos = stop + 1;
+      continue;
+    }
+
+    if (kw === 'func') {
+      const { fn, end } = parse_function(ctx, pos);
+      file.functions.push(add_doc(fn));
+      const next_line = masked.indexOf('\n', end);
+      pos = next_line === -1 ? masked.length : next_line + 1;
+      continue;
+    }
+
+    // import, const, var and type may be grouped with parentheses
+    let spec_start = kw_end;
+    while (/[ \t]/.test(masked[spec_start])) spec_start++;
+
+    const specs = [];
+    let decl_end;
+    let group_doc = null;
+    let grouped = false;
+
+    if (masked[spec_start] === '(') {
+      grouped = true;
+      const close = find_matching(masked, spec_start);
+      decl_end = close === -1 ? masked.length : close + 1;
+      group_doc = get_doc_comment(lines, line_at(line_index, pos));
+      const body = text.substring(spec_start + 1, decl_end - 1);
+      for (const entry of split_block_entries(body, spec_start + 1)) {
+        specs.push({
+          start: entry.offset,
+          end: entry.offset + entry.text.length,
+          blank_before: entry.blank_before
+        });
+      }
+    } else {
+      decl_end = find_spec_end(masked, spec_start);
+      specs.push({ start: spec_start, end: decl_end, blank_before: false });
+    }
+
+    const decl_line = line_at(line_index, pos);
+    let group = 0;
+    let previous_values = [];
+    let iota_index = 0;
+
+    for (const spec of specs) {
+      const spec_text = text.substring(spec.start, spec.end);
+      const line = line_at(line_index, spec.start);
+      if (spec.blank_before) group++;
+
+      if (kw === 'import') {
+        const import_match = strip_comments(spec_text)
+          .trim()
+          .match(/^(?:([A-Za-z_]\w*|\.|_)\s+)?"([^"]*)"/);
+        if (import_match) {
+          // Span of the spec itself, without surrounding comments
+          const span = masked
+            .substring(spec.start, spec.end)
+            .match(/(?:(?:[A-Za-z_]\w*|\.|_)\s+)?"[^"]*"/);
+          const start = span ? spec.start + span.index : spec.start;
+          const end = span ? start + span[0].length : spec.end;
+          const start_line = line_at(line_index, start);
+          const end_line = line_at(line_index, end);
+          file.imports.push({
+            name: import_match[1] || null,
+            path: import_match[2],
+            line: start_line,
+            column: start - line_index[start_line - 1] + 1,
+            end_line,
+            end_column: end - line_index[end_line - 1] + 1,
+            grouped
+          });
+        }
+      } else if (kw === 'type') {
+        const type = parse_type_spec(ctx, spec.start, spec.end);
+        if (type) {
+          file.types.push(add_doc(type, grouped ? group_doc : null));
+        }
+      } else {
+        const value = parse_value_spec(spec_text);
+        if (value.names.length === 0) continue;
+
+        // Constants without values repeat the previous expression list
+        let implicit = false;
+        if (kw === 'const') {
+          if (value.values.length === 0 && !value.type) {
+            value.values = previous_values.slice();
+            implicit = true;
+          } else {
+            previous_values = value.values;
+          }
+        }
+
+        const decl = add_doc(
+          {
+            names: value.names,
+            type: value.type,
+            values: value.values,
+            implicit,
+            line,
+            end_line: line_at(line_index, spec.end),
+            grouped,
+            group_line: decl_line,
+            group,
+            exported: value.names.some(is_exported),
+            doc: null
+          },
+          grouped ? group_doc : null
+        );
+
+        if (kw === 'const') {
+          decl.iota = iota_index;
+          file.consts.push(decl);
+        } else {
+          file.vars.push(decl);
+        }
+      }
+
+      iota_index++;
+    }
+
+    const next_line = masked.indexOf('\n', decl_end);
+    pos = next_line === -1 ? masked.length : next_line + 1;
+  }
+
+  return file;
+};
+
+/**
+ * Get the base type name of a type expression, stripping pointers,
+ * slices, arrays and type arguments (e.g. `*[]pkg.Item[T]` -> `pkg.Item`).
+ * @param {string} type_text - Type expression
+ * @returns {string} Base type name
+ */
+const get_base_type = (type_text) => {
+  let text = (type_text || '').trim();
+  let previous;
+  do {
+    previous = text;
+    text = text
+      .replace(/^\.\.\./, '')
+      .replace(/^\*/, '')
+      .replace(/^\[[^\]]*\]/, '')
+      .trim();
+  } while (text !== previous);
+  const bracket = text.indexOf('[');
+  if (bracket > 0 && !text.startsWith('map')) text = text.substring(0, bracket);
+  return text;
+};
+
+/**
+ * Parse the fields of a struct type expression such as `struct { x int }`.
+ * @param {string} type_text - Struct type expression
+ * @returns {Object[]} Parsed fields (see parse_struct_fields)
+ */
+const parse_struct_type_fields = (type_text) => {
+  const masked = mask_source(type_text || '');
+  const open = masked.indexOf('{');
+  const close = open === -1 ? -1 : find_matching(masked, open);
+  if (close === -1) return [];
+
+  return parse_struct_fields(
+    type_text,
+    open,
+    close,
+    build_line_index(type_text),
+    type_text.split('\n')
+  );
+};
+
+// ============================================================================
+// ASSIGNMENTS
+// ============================================================================
+
+/**
+ * Assignment operators and the kind of assignment they make.
+ */
+const ASSIGNMENT_KINDS = {
+  ':=': 'define',
+  '=': 'assign',
+  '++': 'increment',
+  '--': 'decrement'
+};
+
+/**
+ * Assignment operators in masked source: definitions, compound
+ * assignments, increments and plain assignments (not comparisons).
+ */
+const ASSIGNMENT_PATTERN =
+  /(:=|<<=|>>=|&\^=|[-+*/%&|^]=|\+\+|--|(?<![=!<>:])=(?!=))/g;
+
+/**
+ * An assignable operand: a name with optional selectors and indexes,
+ * optionally dereferenced.
+ */
+const ASSIGNMENT_TARGET =
+  /^(\*\s*)?(\(\s*\*?\s*\w+\s*\)|\w+)((?:\s*\.\s*\w+|\[[^\]]*\])*)$/;
+
+/**
+ * Describe an assignment target such as `c.items[i]`.
+ * @param {string} text - Target expression
+ * @returns {Object|null} Target with text, base, field, path and indexed flag, or null
+ */
+const parse_assignment_target = (text) => {
+  const match = text.trim().match(ASSIGNMENT_TARGET);
+  if (!match) return null;
+
+  const base = match[2].replace(/[()*\s]/g, '');
+  const selectors = match[3].replace(/\[[^\]]*\]/g, '\0').split('.');
+  const path = selectors
+    .slice(1)
+    .map((s) => s.replace(/\0/g, '').trim())
+    .filter((s) => s.length > 0);
+  return {
+    text: text.trim(),
+    base,
+    field: path.length > 0 ? path[0] : null,
+    path,
+    indexed: /\[/.test(match[3]),
+    dereferenced: Boolean(match[1])
+  };
+};
+
+/**
+ * Find the assignments in a Go function body.
+ * Covers plain assignments (`=`), definitions (`:=` and `var x = ...`),
+ * compound assignments (`+=`, `<<=`, ...) and increment/decrement
+ * statements.  Compound assignments and increments both read and write
+ * their target.  The blank identifier is not reported as a target.
+ * @param {string} body - Function body
+ * @param {number} [body_line=1] - Line of the start of the body
+ * @returns {Object[]} Assignments with line, operator, kind, targets and reads_target
+ */
+const find_go_assignments = (body, body_line = 1) => {
+  const masked = mask_source(body || '');
+  const line_index = build_line_index(body || '');
+  const assignments = [];
+  let match;
+
+  ASSIGNMENT_PATTERN.lastIndex = 0;
+  while ((match = ASSIGNMENT_PATTERN.exec(masked)) !== null) {
+    const operator = match[1];
+    const kind = ASSIGNMENT_KINDS[operator] || 'compound';
+
+    // The left-hand side starts at the beginning of the statement
+    let start = match.index;
+    while (start > 0 && !/[\n;{}]/.test(masked[start - 1])) start--;
+    let lhs = masked
+      .substring(start, match.index)
+      .replace(/^\s*(?:else\s+)?(?:if|for|switch|var|const)\s+/, '')
+      .trim();
+    // `var x, y T = ...` declares typed variables
+    if (operator === '=' && /^\w+(\s*,\s*\w+)*\s+[\w.*[\]]+$/.test(lhs)) {
+      lhs = lhs.replace(/\s+[\w.*[\]]+$/, '');
+    }
+
+    const reads_target = kind !== 'define' && kind !== 'assign';
+    const parts = reads_target ? [lhs] : split_top_level(lhs);
+    const targets = parts.map(parse_assignment_target);
+    if (targets.length === 0 || targets.some((t) => t === null)) continue;
+
+    const is_var = /^\s*var\s/.test(masked.substring(start, match.index));
+    assignments.push({
+      line: body_line + line_at(line_index, match.index) - 1,
+      operator,
+      kind: is_var ? 'define' : kind,
+      targets: targets.filter((t) => t.base !== '_'),
+      reads_target
+    });
+  }
+
+  return assignments;
+};
+
+/**
+ * Summarize the assignment statements of a Go function body.
+ * Field writes are assignments whose target selects a field of a
+ * variable (e.g. `c.value++`), which is what receiver mutation checks
+ * need; compound assignments and increments count as both reads and
+ * writes.
+ * @param {string} body - Function body
+ * @param {number} [body_line=1] - Line of the start of the body
+ * @returns {Object} Summary with assignments, counts by kind and operator, and field writes
+ */
+const summarize_go_body = (body, body_line = 1) => {
+  const assignments = find_go_assignments(body, body_line);
+  const by_kind = {
+    define: 0,
+    assign: 0,
+    compound: 0,
+    increment: 0,
+    decrement: 0
+  };
+  const by_operator = {};
+  const field_writes = [];
+
+  for (const assignment of assignments) {
+    by_kind[assignment.kind]++;
+    by_operator[assignment.operator] =
+      (by_operator[assignment.operator] || 0) + 1;
+
+    for (const target of assignment.targets) {
+      if (!target.field) continue;
+      field_writes.push({
+        base: target.base,
+        field: target.field,
+        target: target.text,
+        line: assignment.line,
+        operator: assignment.operator,
+        read_write: assignment.reads_target,
+        indexed: target.indexed
+      });
+    }
+  }
+
+  return {
+    assignments,
+    assignment_count: assignments.length,
+    compound_count: by_kind.compound + by_kind.increment + by_kind.decrement,
+    by_kind,
+    by_operator,
+    field_writes
+  };
+};
+
+// ============================================================================
+// CONVERSIONS AND ASSERTIONS
+// ============================================================================
+
+/**
+ * A name, optionally qualified by a package, directly followed by an
+ * opening parenthesis: a call or a conversion such as `Fahrenheit(c)`.
+ */
+const CONVERSION_NAME_PATTERN =
+  /(?<![\w.\]])([A-Za-z_]\w*(?:\s*\.\s*[A-Za-z_]\w*)?)\s*(?=\()/g;
+
+/**
+ * A slice or parenthesized pointer type directly followed by an opening
+ * parenthesis: `[]byte(s)`, `(*Node)(p)`.
+ */
+const CONVERSION_TYPE_PATTERN =
+  /(?<![\w\])])(?:(\[\]\s*[\w.]+)|\(\s*(\*\s*[\w.]+)\s*\))\s*(?=\()/g;
+
+/**
+ * Find where the operand ending at an offset starts: a name with
+ * selectors, indexes and calls, such as `m[key].value` or `get()`.
+ * @param {string} masked - Masked source
+ * @param {number} end - Offset just after the operand
+ * @returns {number} Offset of the first character of the operand
+ */
+const find_operand_start = (masked, end) => {
+  let start = end;
+  while (start > 0) {
+    const ch = masked[start - 1];
+    if (/[\w.]/.test(ch)) {
+      start--;
+    } else if (ch === ')' || ch === ']') {
+      let depth = 0;
+      let i = start - 1;
+      for (; i >= 0; i--) {
+        if (masked[i] === ')' || masked[i] === ']') depth++;
+        else if (masked[i] === '(' || masked[i] === '[') depth--;
+        if (depth === 0) break;
+      }
+      if (i < 0) break;
+      start = i;
+    } else {
+      break;
+    }
+  }
+  return start;
+};
+
+/**
+ * Find the type assertions in a Go function body.
+ * - comma_ok: `v, ok := x.(T)`, which cannot panic
+ * - single: `x.(T)` used as a value, which panics when x is not a T
+ * - switch: `x.(type)` in a type switch
+ * @param {string} body - Function body
+ * @param {number} [body_line=1] - Line of the start of the body
+ * @returns {Object[]} Assertions with line, expression, type, form and checked flag
+ */
+const find_go_assertions = (body, body_line = 1) => {
+  const text = body || '';
+  const masked = mask_source(text);
+  const line_index = build_line_index(text);
+  const assertions = [];
+  const pattern = /\.\s*\(/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    if (masked[match.index - 1] === '.') continue;
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    const start = find_operand_start(masked, match.index);
+    if (close === -1 || start === match.index) continue;
+
+    const type = text.substring(open + 1, close).replace(/\s+/g, ' ').trim();
+    let form = 'single';
+    if (type === 'type') {
+      form = 'switch';
+    } else {
+      // The assertion must be the whole right-hand side of `v, ok :=`
+      let statement = start;
+      while (statement > 0 && !/[\n;{}]/.test(masked[statement - 1])) {
+        statement--;
+      }
+      const lhs = masked.substring(statement, start);
+      const after = masked.substring(close + 1).match(/^[ \t]*([\n;}]|$)/);
+      if (
+        after &&
+        /^\s*(?:(?:else\s+)?if\s+|var\s+)?\w+\s*,\s*\w+\s*:?=\s*$/.test(lhs)
+      ) {
+        form = 'comma_ok';
+      }
+    }
+
+    assertions.push({
+      line: body_line + line_at(line_index, match.index) - 1,
+      expression: text.substring(start, match.index).trim(),
+      type,
+      form,
+      checked: form !== 'single'
+    });
+  }
+
+  return assertions;
+};
+
+/**
+ * Find the type conversions in a Go function body: predeclared types
+ * (`float64(n)`), slices (`[]byte(s)`), parenthesized pointer types
+ * (`(*Node)(p)`) and the named types the caller knows of
+ * (`Fahrenheit(c*9/5 + 32)`, `time.Duration(n)`).  A name that is not a
+ * known type is a call, not a conversion.
+ * @param {string} body - Function body
+ * @param {number} [body_line=1] - Line of the start of the body
+ * @param {Set<string>} [type_names] - Named types, qualified for other packages
+ * @param {number} [body_column=1] - Column of the start of the body
+ * @returns {Object[]} Conversions with span, type, expression and kind (basic, named or composite)
+ */
+const find_go_conversions = (
+  body,
+  body_line = 1,
+  type_names = new Set(),
+  body_column = 1
+) => {
+  const text = body || '';
+  const masked = mask_source(text);
+  const line_index = build_line_index(text);
+  const basic = new Set(GO_BUILTINS.types);
+  const conversions = [];
+
+  // Columns of the first line of the body start at the opening brace
+  const get_position = (offset) => {
+    const line = line_at(line_index, offset);
+    const column = offset - line_index[line - 1] + 1;
+    return {
+      line: body_line + line - 1,
+      column: line === 1 ? body_column + column - 1 : column
+    };
+  };
+
+  const add = (type, kind, index, open) => {
+    const close = find_matching(masked, open);
+    if (close === -1) return;
+    const expression = text.substring(open + 1, close).trim();
+    if (split_top_level(expression).length !== 1) return;
+    const start = get_position(index);
+    const end = get_position(close);
+    conversions.push({
+      line: start.line,
+      column: start.column,
+      end_line: end.line,
+      end_column: end.column + 1,
+      type,
+      expression,
+      kind,
+      index
+    });
+  };
+
+  let match;
+  CONVERSION_NAME_PATTERN.lastIndex = 0;
+  while ((match = CONVERSION_NAME_PATTERN.exec(masked)) !== null) {
+    const type = match[1].replace(/\s+/g, '');
+    if (!basic.has(type) && !type_names.has(type)) continue;
+    const open = masked.indexOf('(', match.index + match[0].length);
+    add(type, basic.has(type) ? 'basic' : 'named', match.index, open);
+  }
+  CONVERSION_TYPE_PATTERN.lastIndex = 0;
+  while ((match = CONVERSION_TYPE_PATTERN.exec(masked)) !== null) {
+    const type = (match[1] || match[2]).replace(/\s+/g, '');
+    const open = masked.indexOf('(', match.index + match[0].length);
+    add(type, 'composite', match.index, open);
+  }
+
+  return conversions
+    .sort((a, b) => a.index - b.index)
+    .map(({ index, ...conversion }) => conversion);
+};
+
+// ============================================================================
+// CONSTANT EVALUATION
+// ============================================================================
+
+/**
+ * Tokenize a constant expression.
+ * @param {string} expr - Expression text
+ * @returns {string[]|null} Tokens, or null if the expression has unsupported syntax
+ */
+const tokenize_constant = (expr) => {
+  const tokens = [];
+  const pattern =
+    /\s*(0[xX][0-9a-fA-F_]+|0[bB][01_]+|0[oO]?[0-7_]+|\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?|[A-Za-z_][\w.]*|<<|>>|&\^|[-+*/%&|^()~])/y;
+  let position = 0;
+  const text = (expr || '').trim();
+
+  while (position < text.length) {
+    pattern.lastIndex = position;
+    const match = pattern.exec(text);
+    if (!match) return null;
+    tokens.push(match[1]);
+    position = pattern.lastIndex;
+    while (position < text.length && /\s/.test(text[position])) position++;
+  }
+
+  return tokens;
+};
+
+/**
+ * Parse a numeric literal token.
+ * @param {string} token - Literal token
+ * @returns {number} Numeric value
+ */
+const parse_number_literal = (token) => {
+  const clean = token.replace(/_/g, '');
+  if (/^0[xX]/.test(clean)) return parseInt(clean.substring(2), 16);
+  if (/^0[bB]/.test(clean)) return parseInt(clean.substring(2), 2);
+  if (/^0[oO]/.test(clean)) return parseInt(clean.substring(2), 8);
+  if (/^0[0-7]+$/.test(clean)) return parseInt(clean.substring(1), 8);
+  return Number(clean);
+};
+
+/**
+ * Evaluate a Go constant expression.
+ * Supports integer literals, named constants, iota, unary and binary
+ * arithmetic/bitwise operators, parentheses, type conversions such as
+ * `int64(1) << 10` and the length of arrays (`len(table)`).
+ * @param {string} expr - Expression text
+ * @param {Object} [options] - Evaluation options
+ * @param {Function} [options.resolve] - Resolves an identifier to a number (or null)
+ * @param {Function} [options.length] - Resolves an identifier to the length of its array (or null)
+ * @param {number} [options.iota] - Value of iota
+ * @returns {number|null} The value, or null if it cannot be evaluated
+ */
+const evaluate_constant = (expr, options = {}) => {
+  const tokens = tokenize_constant(expr);
+  if (!tokens || tokens.length === 0) return null;
+
+  let index = 0;
+  const peek = () => tokens[index];
+  const next = () => tokens[index++];
+
+  const BINARY = {
+    5: ['*', '/', '%', '<<', '>>', '&', '&^'],
+    4: ['+', '-', '|', '^']
+  };
+
+  const apply = (op, a, b) => {
+    switch (op) {
+      case '*':
+        return a * b;
+      case '/':
+        if (b === 0) return null;
+        if (Number.isInteger(a) && Number.isInteger(b)) {
+          return Math.trunc(a / b);
+        }
+        return a / b;
+      case '%':
+        return b === 0 ? null : a % b;
+      case '<<':
+        return a * 2 ** b;
+      case '>>':
+        return Math.floor(a / 2 ** b);
+      case '&':
+        return Number(BigInt(a) & BigInt(b));
+      case '&^':
+        return Number(BigInt(a) & ~BigInt(b));
+      case '+':
+        return a + b;
+      case '-':
+        return a - b;
+      case '|':
+        return Number(BigInt(a) | BigInt(b));
+      case '^':
+        return Number(BigInt(a) ^ BigInt(b));
+      default:
+        return null;
+    }
+  };
+
+  const parse_primary = () => {
+    const token = next();
+    if (token === undefined) return null;
+
+    if (token === '(') {
+      const value = parse_binary(4);
+      if (next() !== ')') return null;
+      return value;
+    }
+    if (token === '-' || token === '+' || token === '^') {
+      const value = parse_primary();
+      if (value === null) return null;
+      if (token === '-') return -value;
+      if (token === '^') return Number(~BigInt(value));
+      return value;
+    }
+    if (/^\d/.test(token)) return parse_number_literal(token);
+    if (token === 'iota') return options.iota ?? null;
+    if (token === 'len' && peek() === '(') {
+      next();
+      const name = next();
+      if (next() !== ')' || !/^[A-Za-z_]/.test(name || '')) return null;
+      return options.length ? options.length(name) : null;
+    }
+    if (/^[A-Za-z_]/.test(token)) {
+      // Type conversion: T(expr)
+      if (peek() === '(') {
+        next();
+        const value = parse_binary(4);
+        if (next() !== ')') return null;
+        return value;
+      }
+      return options.resolve ? options.resolve(token) : null;
+    }
+    return null;
+  };
+
+  const parse_binary = (precedence) => {
+    if (precedence > 5) return parse_primary();
+
+    let left = parse_binary(precedence + 1);
+    while (left !== null && BINARY[precedence]?.includes(peek())) {
+      const op = next();
+      const right = parse_binary(precedence + 1);
+      if (right === null) return null;
+      left = apply(op, left, right);
+    }
+    return left;
+  };
+
+  const value = parse_binary(4);
+  if (index !== tokens.length || value === null || Number.isNaN(value)) {
+    return null;
+  }
+  return value;
+};
+
+/**
+ * Build a resolver for the constants declared in a package.
+ * Values are evaluated lazily, with iota and implicit repetition applied.
+ * @param {Object[]} consts - Constant declarations (from parse_go_file)
+ * @param {Object} [options] - Options
+ * @param {Function} [options.length] - Resolves an identifier to the length of its array, for `len(table)`
+ * @returns {Function} resolve(name) returning a number or null
+ */
+const build_constant_resolver = (consts, options = {}) => {
+  const specs = new Map();
+  for (const decl of consts) {
+    decl.names.forEach((name, i) => {
+      specs.set(name, { expr: decl.values[i], iota: decl.iota });
+    });
+  }
+
+  const cache = new Map();
+  const resolving = new Set();
+
+  const resolve = (name) => {
+    if (cache.has(name)) return cache.get(name);
+    const spec = specs.get(name);
+    if (!spec || spec.expr === undefined || resolving.has(name)) return null;
+
+    resolving.add(name);
+    const value = evaluate_constant(spec.expr, {
+      resolve,
+      length: options.length,
+      iota: spec.iota
+    });
+    resolving.delete(name);
+
+    cache.set(name, value);
+    return value;
+  };
+
+  return resolve;
+};
+
+/**
+ * Count the elements of the body of an array composite literal, following
+ * keyed elements: `[...]string{2: "c", "d"}` has 4 elements.
+ * @param {string} body - Literal body, without the braces
+ * @param {Function} [resolve] - Resolves an identifier to a number (or null)
+ * @returns {number|null} Number of elements, or null if a key cannot be evaluated
+ */
+const count_array_elements = (body, resolve) => {
+  let index = 0;
+  let length = 0;
+  for (const element of split_top_level(body)) {
+    const parts = split_top_level(element, ':');
+    if (parts.length > 1) {
+      index = evaluate_constant(parts[0], { resolve });
+      if (index === null) return null;
+    }
+    index++;
+    length = Math.max(length, index);
+  }
+  return length;
+};
+
+/**
+ * Split a composite literal into its type and its body, the braces
+ * closing the literal (`[...]struct{ n int }{{1}, {2}}`).
+ * @param {string} literal - Composite literal
+ * @returns {Object|null} Type and body, or null if not a composite literal
+ */
+const split_composite_literal = (literal) => {
+  const text = literal.trim();
+  const masked = mask_source(text);
+  if (!masked.endsWith('}')) return null;
+  let depth = 0;
+  for (let i = masked.length - 1; i >= 0; i--) {
+    const ch = masked[i];
+    if (ch === ')' || ch === ']' || ch === '}') depth++;
+    else if (ch === '(' || ch === '[' || ch === '{') depth--;
+    if (depth === 0) {
+      const type = text.substring(0, i).trim();
+      if (!type) return null;
+      return { type, body: text.substring(i + 1, text.length - 1) };
+    }
+  }
+  return null;
+};
+
+/**
+ * Get the length of an array type, evaluating constant lengths (`[N]T`,
+ * `[2 * N]T`).  Implicit lengths (`[...]T`) are counted from the
+ * elements of a composite literal.
+ * @param {string} type_text - Array type expression
+ * @param {Object} [options] - Options
+ * @param {Function} [options.resolve] - Resolves an identifier to a number (or null)
+ * @param {Function} [options.length] - Resolves an identifier to the length of its array, for `len(table)`
+ * @param {string} [options.literal] - Composite literal of the array type, for implicit lengths
+ * @returns {Object|null} Length (or null if unresolved) and its expression, or null if not an array
+ */
+const get_array_length = (type_text, options = {}) => {
+  const text = (type_text || '').trim();
+  if (classify_type(text) !== 'array') return null;
+  const close = find_matching(mask_source(text), 0);
+  const expr = text.substring(1, close).trim();
+
+  if (expr === '...') {
+    const literal = split_composite_literal(options.literal || '');
+    const length = literal
+      ? count_array_elements(literal.body, options.resolve)
+      : null;
+    return { length, expr };
+  }
+
+  return {
+    length: evaluate_constant(expr, {
+      resolve: options.resolve,
+      length: options.length
+    }),
+    expr
+  };
+};
+
+/**
+ * Merge the package doc comments of a package's files.
+ * Only one file should carry the package doc; a doc.go file takes
+ * precedence, otherwise the first file (by name) with a doc is used.  Docs
+ * found in other files are reported as conflicts.
+ * @param {Object} pkg - Package being built by group_go_packages
+ * @returns {Object} The package
+ */
+const merge_package_doc = (pkg) => {
+  const documented = pkg.files
+    .filter((f) => f.package_doc)
+    .sort(function sort_doc_go_first(a, b) {
+      const a_doc = posix.basename(a.filename) === 'doc.go' ? 0 : 1;
+      const b_doc = posix.basename(b.filename) === 'doc.go' ? 0 : 1;
+      if (a_doc !== b_doc) return a_doc - b_doc;
+      return a.filename < b.filename ? -1 : 1;
+    });
+
+  const [primary, ...others] = documented;
+  pkg.doc = primary ? primary.package_doc : null;
+  pkg.doc_file = primary ? primary.filename : null;
+  pkg.doc_line = primary ? primary.package_doc_line : null;
+  pkg.doc_conflicts = others.map(function format_conflict(file) {
+    return {
+      filename: file.filename,
+      line: file.package_doc_line,
+      doc: file.package_doc,
+      same_text:
+        get_comment_text(file.package_doc) === get_comment_text(pkg.doc)
+    };
+  });
+
+  return pkg;
+};
+
+/**
+ * Create an empty package.
+ * @param {string} name - Package name
+ * @param {string} directory - Package directory
+ * @returns {Object} Package without files
+ */
+const create_go_package = (name, directory) => {
+  return {
+    name,
+    directory,
+    files: [],
+    imports: [],
+    consts: [],
+    vars: [],
+    types: [],
+    functions: [],
+    methods: {},
+    doc: null,
+    doc_file: null,
+    doc_line: null,
+    doc_conflicts: []
+  };
+};
+
+/**
+ * Add the declarations of a parsed file to a package.
+ * @param {Object} pkg - Package (from create_go_package)
+ * @param {Object} file - Parsed file (from parse_go_file)
+ * @returns {Object} The package
+ */
+const add_go_file = (pkg, file) => {
+  pkg.files.push(file);
+
+  for (const imp of file.imports) {
+    pkg.imports.push({ ...imp, filename: file.filename });
+  }
+  for (const c of file.consts) {
+    pkg.consts.push({ ...c, filename: file.filename });
+  }
+  for (const v of file.vars) {
+    pkg.vars.push({ ...v, filename: file.filename });
+  }
+  for (const type of file.types) {
+    pkg.types.push({ ...type, filename: file.filename });
+  }
+  for (const fn of file.functions) {
+    const entry = { ...fn, filename: file.filename };
+    pkg.functions.push(entry);
+    if (fn.receiver) {
+      if (!pkg.methods[fn.receiver.type]) pkg.methods[fn.receiver.type] = [];
+      pkg.methods[fn.receiver.type].push(entry);
+    }
+  }
+
+  return pkg;
+};
+
+/**
+ * Mark the results of a package's functions and interface methods whose
+ * type is a package type as nilable when the type is an interface, or is
+ * defined as a pointer, map, slice, channel or function type.  Types of
+ * other packages keep the kind of their type expression.
+ * @param {Object} pkg - Package (from create_go_package)
+ * @returns {Object} The package
+ */
+const resolve_nilable_results = (pkg) => {
+  const types = new Map(pkg.types.map((t) => [t.name, t]));
+
+  const is_nilable = (type_text, seen = new Set()) => {
+    if (is_nilable_type(type_text)) return true;
+    const name = (type_text || '').trim().replace(/\[[\s\S]*$/, '');
+    const type = types.get(name);
+    if (!type || seen.has(name)) return false;
+    seen.add(name);
+    return type.kind === 'interface' || is_nilable(type.underlying, seen);
+  };
+
+  const methods = pkg.types.flatMap((t) => t.methods);
+  for (const fn of [...pkg.functions, ...methods]) {
+    for (const result of fn.results) result.nilable = is_nilable(result.type);
+  }
+
+  return pkg;
+};
+
+/**
+ * Get the directory of a parsed file, with forward slashes.
+ * @param {Object} file - Parsed file
+ * @returns {string} Directory
+ */
+const get_file_directory = (file) => {
+  return posix.dirname(file.filename.replace(/\\/g, '/'));
+};
+
+/**
+ * Group parsed files into packages by directory.
+ * Files excluded from every build by `//go:build ignore` are not part of
+ * any package; see group_go_scripts.
+ * @param {Object[]} files - Parsed files (from parse_go_file)
+ * @returns {Object[]} Packages with name, directory, doc, files, types, functions and method sets
+ */
+const group_go_packages = (files) => {
+  const by_dir = new Map();
+
+  for (const file of files) {
+    if (file.build_ignored) continue;
+    const directory = get_file_directory(file);
+    // External test packages (package foo_test) are kept separate
+    const key = `${directory}\0${file.package || ''}`;
+    if (!by_dir.has(key)) {
+      by_dir.set(key, create_go_package(file.package, directory));
+    }
+    add_go_file(by_dir.get(key), file);
+  }
+
+  for (const pkg of by_dir.values()) {
+    merge_package_doc(pkg);
+    resolve_nilable_results(pkg);
+  }
+
+  return [...by_dir.values()].sort(function sort_by_directory(a, b) {
+    if (a.directory !== b.directory) return a.directory < b.directory ? -1 : 1;
+    return (a.name || '') < (b.name || '') ? -1 : 1;
+  });
+};
+
+/**
+ * Find the `//go:generate` directives of Go sources that run a script
+ * with `go run`, keyed by the script's filename.
+ * @param {Object[]} sources - Files with filename and source
+ * @returns {Map<string, Object[]>} Directives with filename, line and command by script filename
+ */
+const find_script_generators = (sources) => {
+  const generators = new Map();
+
+  for (const { filename, source } of sources) {
+    const directory = posix.dirname(filename.replace(/\\/g, '/'));
+    (source || '').split('\n').forEach(function check_line(text, index) {
+      const match = text.match(/^\/\/go:generate\s+(.+)$/);
+      if (!match) return;
+      const run = match[1].match(/\bgo\s+run\s+(?:-\S+\s+)*(\S+\.go)\b/);
+      if (!run) return;
+      const script = posix.join(directory, run[1]);
+      if (!generators.has(script)) generators.set(script, []);
+      generators.get(script).push({
+        filename,
+        line: index + 1,
+        command: match[1].trim()
+      });
+    });
+  }
+
+  return generators;
+};
+
+/**
+ * Group the files excluded from every build by `//go:build ignore`, such
+ * as code generators, into scripts.  Each script is its own program, so
+ * it gets its own single file package, marked with script: true.
+ * @param {Object[]} files - Parsed files (from parse_go_file)
+ * @param {Map<string, Object[]>} [generators] - go:generate directives by script filename
+ * @returns {Object[]} Script packages, with the directives that run them
+ */
+const group_go_scripts = (files, generators = new Map()) => {
+  return files
+    .filter((file) => file.build_ignored)
+    .map(function create_script(file) {
+      const pkg = add_go_file(
+        create_go_package(file.package, get_file_directory(file)),
+        file
+      );
+      merge_package_doc(pkg);
+      return {
+        ...pkg,
+        script: true,
+        filename: file.filename,
+        generated_by: generators.get(file.filename) || []
+      };
+    })
+    .sort(function sort_by_filename(a, b) {
+      return a.filename < b.filename ? -1 : 1;
+    });
+};
+
+/**
+ * Parse Go sources into buildable packages and, optionally, scripts.
+ * Files excluded by `//go:build ignore` are never mixed into packages;
+ * they are listed in ignored_files and, with include_scripts, parsed into
+ * scripts.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.include_scripts=false] - Parse ignored files into scripts
+ * @returns {Object} Parse result with packages, scripts and ignored_files
+ */
+const parse_go_sources = (sources, options = {}) => {
+  const files = sources.map(function parse_source(row) {
+    return parse_go_file(row.source, row.filename);
+  });
+  const ignored = files.filter((f) => f.build_ignored);
+
+  return {
+    packages: group_go_packages(files),
+    scripts: options.include_scripts
+      ? group_go_scripts(ignored, find_script_generators(sources))
+      : [],
+    ignored_files: ignored.map((f) => f.filename)
+  };
+};
+
+/**
+ * Sort methods by name, then by location.
+ * @param {Object} a - Method
+ * @param {Object} b - Method
+ * @returns {number} Sort order
+ */
+const sort_methods = (a, b) => {
+  if (a.name !== b.name) return a.name < b.name ? -1 : 1;
+  if (a.filename !== b.filename) {
+    return (a.filename || '') < (b.filename || '') ? -1 : 1;
+  }
+  return a.line - b.line;
+};
+
+/**
+ * List the methods of a parsed file or package grouped by receiver type.
+ * Generic receivers are keyed by their base type name (`Container[T]` ->
+ * `Container`).  Methods within each group are sorted by name.
+ *
+ * With include_promoted, methods promoted from embedded fields of structs
+ * declared in the same scope are added with promoted_from and depth.  As
+ * in Go, a type's own methods shadow promoted ones, shallower embeddings
+ * win over deeper ones and names promoted from two fields at the same
+ * depth are ambiguous and left out.
+ * @param {Object} scope - Parsed file (from parse_go_file) or package (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.include_promoted=false] - Include promoted methods
+ * @returns {Object} Methods keyed by receiver type name
+ */
+const get_methods_by_type = (scope, options = {}) => {
+  const declared = {};
+  for (const fn of scope.functions) {
+    if (!fn.receiver) continue;
+    if (!declared[fn.receiver.type]) declared[fn.receiver.type] = [];
+    declared[fn.receiver.type].push(fn);
+  }
+
+  const types = new Map(scope.types.map((t) => [t.name, t]));
+  const get_own_methods = (name) => {
+    const type = types.get(name);
+    if (type && type.kind === 'interface') return type.methods;
+    return declared[name] || [];
+  };
+
+  const result = {};
+  const names = new Set(Object.keys(declared));
+  if (options.include_promoted) {
+    for (const type of scope.types) {
+      if (type.kind === 'struct') names.add(type.name);
+    }
+  }
+
+  for (const name of [...names].sort()) {
+    const methods = [...(declared[name] || [])];
+
+    if (options.include_promoted) {
+      const taken = new Set(methods.map((m) => m.name));
+      const visited = new Set([name]);
+      let level = [name];
+
+      for (let depth = 1; level.length > 0; depth++) {
+        const candidates = new Map();
+        const next = [];
+
+        for (const outer of level) {
+          const type = types.get(outer);
+          if (!type || type.kind !== 'struct') continue;
+          for (const field of type.fields) {
+            if (!field.embedded) continue;
+            const embedded = get_base_type(field.type);
+            if (visited.has(embedded) || !types.has(embedded)) continue;
+            visited.add(embedded);
+            next.push(embedded);
+
+            for (const method of get_own_methods(embedded)) {
+              if (!candidates.has(method.name)) {
+                candidates.set(method.name, []);
+              }
+              candidates.get(method.name).push({ method, embedded });
+            }
+          }
+        }
+
+        for (const [method_name, found] of candidates) {
+          if (taken.has(method_name)) continue;
+          taken.add(method_name);
+          if (found.length > 1) continue;
+          methods.push({
+            ...found[0].method,
+            promoted_from: found[0].embedded,
+            depth
+          });
+        }
+        level = next;
+      }
+    }
+
+    if (methods.length > 0) result[name] = methods.sort(sort_methods);
+  }
+
+  return result;
+};
+
+/**
+ * Load the source of all Go files of a project from the sourcecode table.
+ * @param {number} project_id - The project ID
+ * @returns {Promise<Object[]>} Rows with filename and source
+ */
+const load_go_sources = async (project_id) => {
+  return await query`
+    SELECT filename, source
+    FROM sourcecode
+    WHERE project_id = ${project_id}
+    AND filename LIKE '%.go'
+    ORDER BY filename
+  `;
+};
+
+/**
+ * Load and parse all Go files of a project from the sourcecode table.
+ * @param {number} project_id - The project ID
+ * @returns {Promise<Object[]>} Parsed files
+ */
+const load_go_files = async (project_id) => {
+  const sources = await load_go_sources(project_id);
+
+  return sources.map(function parse_source(row) {
+    return parse_go_file(row.source, row.filename);
+  });
+};
+
+/**
+ * Load all Go packages of a project.
+ * @param {number} project_id - The project ID
+ * @returns {Promise<Object[]>} Packages (see group_go_packages)
+ */
+const load_go_packages = async (project_id) => {
+  return group_go_packages(await load_go_files(project_id));
+};
+
+/**
+ * Load and parse all Go files of a project into packages and scripts.
+ * @param {number} project_id - The project ID
+ * @param {Object} [options] - Options (see parse_go_sources)
+ * @returns {Promise<Object>} Parse result with packages, scripts and ignored_files
+ */
+const load_go_project = async (project_id, options = {}) => {
+  return parse_go_sources(await load_go_sources(project_id), options);
+};
+
+export {
+  find_literal_ranges,
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  split_top_level,
+  split_block_entries,
+  strip_comments,
+  get_trailing_comment,
+  get_doc_comment,
+  get_comment_text,
+  get_package_comments,
+  is_exported,
+  parse_parameters,
+  parse_results,
+  parse_receiver,
+  parse_field,
+  parse_value_spec,
+  parse_build_constraints,
+  is_build_ignored,
+  parse_filename_constraints,
+  convert_plus_build,
+  get_effective_constraint,
+  evaluate_build_constraint,
+  get_target_tags,
+  KNOWN_GOOS,
+  KNOWN_GOARCH,
+  classify_type,
+  is_nilable_type,
+  resolve_nilable_results,
+  get_base_type,
+  parse_struct_type_fields,
+  parse_number_literal,
+  evaluate_constant,
+  build_constant_resolver,
+  count_array_elements,
+  split_composite_literal,
+  get_array_length,
+  parse_go_file,
+  group_go_packages,
+  group_go_scripts,
+  find_script_generators,
+  parse_go_sources,
+  merge_package_doc,
+  get_methods_by_type,
+  parse_assignment_target,
+  find_go_assignments,
+  summarize_go_body,
+  find_go_assertions,
+  find_go_conversions,
+  load_go_sources,
+  load_go_files,
+  load_go_packages,
+  load_go_project,
+  GO_BUILTINS
+};
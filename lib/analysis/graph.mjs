@@ -0,0 +1,843 @@
+'use strict';
+
+/**
+ * @fileoverview Go symbol graph module.
+ * Builds a graph of a project's Go functions, methods and types connected
+ * by calls, interface implementations and embedding, and renders it as a
+ * self-contained interactive HTML page.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/graph
+ */
+
+import {
+  mask_source,
+  get_base_type,
+  is_exported,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Edge types of the symbol graph.
+ */
+const EDGE_TYPES = ['calls', 'implements', 'embeds'];
+
+/**
+ * Identifiers that look like calls but are not.
+ */
+const NON_CALLS = new Set([
+  'func',
+  'if',
+  'for',
+  'switch',
+  'select',
+  'return',
+  'go',
+  'defer',
+  'range',
+  'chan',
+  'map',
+  'struct',
+  'interface'
+]);
+
+// ============================================================================
+// NODES
+// ============================================================================
+
+/**
+ * Build the ID of a symbol node.
+ * @param {string} directory - Package directory
+ * @param {string} name - Symbol name (Type.Method for methods)
+ * @returns {string} Node ID
+ */
+const get_node_id = (directory, name) => {
+  return `${directory}:${name}`;
+};
+
+/**
+ * Build the nodes of a package.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object[]} Function, method and type nodes
+ */
+const build_package_nodes = (pkg) => {
+  const nodes = [];
+
+  for (const type of pkg.types) {
+    const is_composite = type.kind === 'struct' || type.kind === 'interface';
+    nodes.push({
+      id: get_node_id(pkg.directory, type.name),
+      name: type.name,
+      kind: is_composite ? type.kind : 'type',
+      package: pkg.name,
+      directory: pkg.directory,
+      filename: type.filename,
+      line: type.line,
+      exported: is_exported(type.name)
+    });
+  }
+
+  for (const fn of pkg.functions) {
+    const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+    nodes.push({
+      id: get_node_id(pkg.directory, name),
+      name,
+      kind: fn.receiver ? 'method' : 'function',
+      package: pkg.name,
+      directory: pkg.directory,
+      filename: fn.filename,
+      line: fn.line,
+      exported: is_exported(fn.name)
+    });
+  }
+
+  return nodes;
+};
+
+// ============================================================================
+// EDGES
+// ============================================================================
+
+/**
+ * Map the import names of a file to the project packages they refer to.
+ * An import refers to a project package when its path ends with the
+ * package's directory.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {string} filename - File whose imports to map
+ * @param {Object[]} packages - All project packages
+ * @returns {Map<string, Object>} Import name to package
+ */
+const get_imported_packages = (pkg, filename, packages) => {
+  const imported = new Map();
+
+  for (const imp of pkg.imports) {
+    if (imp.filename !== filename) continue;
+    const target = packages.find(
+      (p) =>
+        p !== pkg &&
+        p.directory !== '.' &&
+        (imp.path === p.directory || imp.path.endsWith(`/${p.directory}`))
+    );
+    if (!target) continue;
+    imported.set(imp.name || target.name, target);
+  }
+
+  return imported;
+};
+
+/**
+ * Find the call edges of a function body.
+ * Calls are resolved to package functions (`Name()`), functions of
+ * imported project packages (`pkg.Name()`) and methods, where the type is
+ * known from the receiver or a parameter, or the method name is unique
+ * within the package.
+ *
+ * Calls that cannot be resolved to a single declaration are only returned
+ * when asked for: calls through interface typed variables target the
+ * interface method (`Iface.Method`, with interface and method set), and
+ * calls of function typed parameters have a null target and indirect set.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @param {Object} pkg - Package containing the function
+ * @param {Object[]} packages - All project packages
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.interface_calls=false] - Include calls of interface methods
+ * @param {boolean} [options.indirect_calls=false] - Include calls of function values
+ * @returns {Object[]} Edges with source, target and line
+ */
+const find_call_edges = (fn, pkg, packages, options = {}) => {
+  if (!fn.body) return [];
+
+  const source = get_node_id(
+    pkg.directory,
+    fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name
+  );
+  const imported = get_imported_packages(pkg, fn.filename, packages);
+  const variables = new Map();
+  if (fn.receiver && fn.receiver.name) {
+    variables.set(fn.receiver.name, fn.receiver.type);
+  }
+  for (const param of fn.params) {
+    if (param.name) variables.set(param.name, get_base_type(param.type));
+  }
+
+  const method_names = new Map();
+  for (const [type, methods] of Object.entries(pkg.methods)) {
+    for (const method of methods) {
+      if (!method_names.has(method.name)) method_names.set(method.name, []);
+      method_names.get(method.name).push(type);
+    }
+  }
+
+  const functions = new Set(
+    pkg.functions.filter((f) => !f.receiver).map((f) => f.name)
+  );
+  const has_method = (p, type, name) =>
+    (p.methods[type] || []).some((m) => m.name === name);
+
+  const masked = mask_source(fn.body);
+  const pattern = /(?<![\w.])(\w+)(?:\.(\w+))?\s*\(/g;
+  const edges = [];
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    const [, first, second] = match;
+    const newlines = masked.substring(0, match.index).match(/\n/g) || [];
+    const line = fn.body_line + newlines.length;
+    let target = null;
+    let extra = null;
+
+    if (!second) {
+      if (!NON_CALLS.has(first) && functions.has(first)) {
+        target = get_node_id(pkg.directory, first);
+      } else if (
+        options.indirect_calls &&
+        /^func\b/.test(variables.get(first) || '')
+      ) {
+        edges.push({
+          source,
+          target: null,
+          type: 'calls',
+          line,
+          indirect: true,
+          callee: first
+        });
+      }
+    } else if (imported.has(first)) {
+      const other = imported.get(first);
+      if (other.functions.some((f) => !f.receiver && f.name === second)) {
+        target = get_node_id(other.directory, second);
+      }
+    } else if (variables.has(first)) {
+      const type = variables.get(first);
+      const declared = pkg.types.find((t) => t.name === type);
+      if (has_method(pkg, type, second)) {
+        target = get_node_id(pkg.directory, `${type}.${second}`);
+      } else if (
+        options.interface_calls &&
+        declared &&
+        declared.kind === 'interface'
+      ) {
+        target = get_node_id(pkg.directory, `${type}.${second}`);
+        extra = {
+          interface: get_node_id(pkg.directory, type),
+          method: second
+        };
+      }
+    } else if ((method_names.get(second) || []).length === 1) {
+      const [type] = method_names.get(second);
+      target = get_node_id(pkg.directory, `${type}.${second}`);
+    }
+
+    if (target && target !== source) {
+      edges.push({ source, target, type: 'calls', line, ...extra });
+    }
+  }
+
+  return edges;
+};
+
+/**
+ * Resolve a type name used in a package to its node ID.
+ * @param {string} type_text - Type expression
+ * @param {Object} pkg - Package using the type
+ * @param {Map<string, Object>} imported - Imported project packages
+ * @returns {string|null} Node ID, or null for types outside the project
+ */
+const resolve_type_node = (type_text, pkg, imported) => {
+  const base = get_base_type(type_text);
+  const dot = base.indexOf('.');
+  if (dot === -1) {
+    return pkg.types.some((t) => t.name === base)
+      ? get_node_id(pkg.directory, base)
+      : null;
+  }
+
+  const other = imported.get(base.substring(0, dot));
+  const name = base.substring(dot + 1);
+  if (!other || !other.types.some((t) => t.name === name)) return null;
+  return get_node_id(other.directory, name);
+};
+
+/**
+ * Find the embedding edges of a package's struct and interface types.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object[]} packages - All project packages
+ * @returns {Object[]} Edges with source, target and line
+ */
+const find_embed_edges = (pkg, packages) => {
+  const edges = [];
+
+  for (const type of pkg.types) {
+    const imported = get_imported_packages(pkg, type.filename, packages);
+    const source = get_node_id(pkg.directory, type.name);
+    const embedded = [...type.fields.filter((f) => f.embedded), ...type.embeds];
+
+    for (const entry of embedded) {
+      const target = resolve_type_node(entry.type, pkg, imported);
+      if (target && target !== source) {
+        edges.push({ source, target, type: 'embeds', line: entry.line });
+      }
+    }
+  }
+
+  return edges;
+};
+
+/**
+ * Build the comparison key of a method signature (name and types).
+ * @param {Object} method - Method with name, params and results
+ * @returns {string} Signature key
+ */
+const get_method_key = (method) => {
+  const params = method.params
+    .map((p) => `${p.variadic ? '...' : ''}${p.type}`)
+    .join(',');
+  const results = method.results.map((r) => r.type).join(',');
+  return `${method.name}(${params})(${results})`;
+};
+
+/**
+ * Collect the methods of an interface by name, including methods of
+ * embedded interfaces declared in the same package.
+ * @param {Object} type - Interface type
+ * @param {Object} pkg - Package containing the interface
+ * @param {Set<string>} [seen] - Interfaces already expanded
+ * @returns {Map<string, Object>|null} Methods by name, or null if an embedded interface is outside the package
+ */
+const get_interface_methods = (type, pkg, seen = new Set()) => {
+  const methods = new Map(type.methods.map((m) => [m.name, m]));
+  seen.add(type.name);
+
+  for (const embed of type.embeds) {
+    const embedded = pkg.types.find(
+      (t) => t.name === embed.type && t.kind === 'interface'
+    );
+    if (!embedded) return null;
+    if (seen.has(embedded.name)) continue;
+    const inner = get_interface_methods(embedded, pkg, seen);
+    if (!inner) return null;
+    for (const [name, method] of inner) {
+      if (!methods.has(name)) methods.set(name, method);
+    }
+  }
+
+  return methods;
+};
+
+/**
+ * Collect the methods of a concrete type by name, including methods
+ * promoted from embedded types of the same package.  Declared methods
+ * take precedence over promoted ones.
+ *
+ * The pointer method set of *T has every method of T; the value method
+ * set of T only has the methods with value receivers, and the methods
+ * promoted from embedded pointers (`*E`), all of which are in the method
+ * set of the embedded pointer.
+ * @param {string} name - Type name
+ * @param {Object} pkg - Package containing the type
+ * @param {Set<string>} [seen] - Types already expanded
+ * @param {boolean} [pointer=true] - Collect the pointer method set
+ * @returns {Map<string, Object>} Methods by name
+ */
+const get_type_methods = (name, pkg, seen = new Set(), pointer = true) => {
+  const methods = new Map(
+    (pkg.methods[name] || [])
+      .filter((m) => pointer || !m.receiver.pointer)
+      .map((m) => [m.name, m])
+  );
+  seen.add(name);
+
+  const type = pkg.types.find((t) => t.name === name);
+  for (const field of type ? type.fields : []) {
+    if (!field.embedded) continue;
+    const embedded = get_base_type(field.type);
+    if (seen.has(embedded)) continue;
+    const through_pointer = field.type.trim().startsWith('*');
+    for (const [method_name, method] of get_type_methods(
+      embedded,
+      pkg,
+      seen,
+      pointer || through_pointer
+    )) {
+      if (!methods.has(method_name)) methods.set(method_name, method);
+    }
+  }
+
+  return methods;
+};
+
+/**
+ * Collect the method set of an interface (see get_interface_methods).
+ * @param {Object} type - Interface type
+ * @param {Object} pkg - Package containing the interface
+ * @returns {Set<string>|null} Method keys, or null if an embedded interface is outside the package
+ */
+const get_interface_method_set = (type, pkg) => {
+  const methods = get_interface_methods(type, pkg);
+  return methods ? new Set([...methods.values()].map(get_method_key)) : null;
+};
+
+/**
+ * Collect the method set of a concrete type (see get_type_methods).
+ * @param {string} name - Type name
+ * @param {Object} pkg - Package containing the type
+ * @param {boolean} [pointer=true] - Collect the pointer method set
+ * @returns {Set<string>} Method keys
+ */
+const get_type_method_set = (name, pkg, pointer = true) => {
+  return new Set(
+    [...get_type_methods(name, pkg, new Set(), pointer).values()].map(
+      get_method_key
+    )
+  );
+};
+
+/**
+ * Collect the value method set of a type T: the methods callable on a T
+ * value, which a T value needs to satisfy an interface.
+ * @param {string} name - Type name
+ * @param {Object} pkg - Package containing the type
+ * @returns {Set<string>} Method keys
+ */
+const get_value_method_set = (name, pkg) => {
+  return get_type_method_set(name, pkg, false);
+};
+
+/**
+ * Collect the pointer method set of a type T: the methods of *T, with
+ * both value and pointer receivers.
+ * @param {string} name - Type name
+ * @param {Object} pkg - Package containing the type
+ * @returns {Set<string>} Method keys
+ */
+const get_pointer_method_set = (name, pkg) => {
+  return get_type_method_set(name, pkg, true);
+};
+
+/**
+ * Find the types that implement the project's interfaces.
+ * An implementation must declare all of the interface's methods with the
+ * same parameter and result types.  Empty interfaces are skipped.  Edges
+ * say whether values of the type implement the interface (receiver
+ * value), or only pointers to it (receiver pointer) because some of the
+ * methods have pointer receivers.
+ * @param {Object[]} packages - All project packages
+ * @returns {Object[]} Edges from the implementing type to the interface
+ */
+const find_implements_edges = (packages) => {
+  const interfaces = [];
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind !== 'interface') continue;
+      const keys = get_interface_method_set(type, pkg);
+      if (keys && keys.size > 0) interfaces.push({ pkg, type, keys });
+    }
+  }
+
+  const edges = [];
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind === 'interface') continue;
+      const keys = get_pointer_method_set(type.name, pkg);
+      if (keys.size === 0) continue;
+      const value_keys = get_value_method_set(type.name, pkg);
+
+      for (const iface of interfaces) {
+        if (![...iface.keys].every((key) => keys.has(key))) continue;
+        const by_value = [...iface.keys].every((key) => value_keys.has(key));
+        edges.push({
+          source: get_node_id(pkg.directory, type.name),
+          target: get_node_id(iface.pkg.directory, iface.type.name),
+          type: 'implements',
+          line: type.line,
+          receiver: by_value ? 'value' : 'pointer'
+        });
+      }
+    }
+  }
+
+  return edges;
+};
+
+// ============================================================================
+// SYMBOL GRAPH
+// ============================================================================
+
+/**
+ * Build the symbol graph of a set of packages.
+ * Repeated calls between the same pair of symbols are merged into a single
+ * edge with a count.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.edge_types] - Edge types to include (default: all)
+ * @returns {Object} Graph with nodes, edges, packages and a summary
+ */
+const build_symbol_graph = (packages, options = {}) => {
+  const edge_types = options.edge_types || EDGE_TYPES;
+  const nodes = packages.flatMap(build_package_nodes);
+
+  const found = [];
+  if (edge_types.includes('calls')) {
+    for (const pkg of packages) {
+      for (const fn of pkg.functions) {
+        found.push(...find_call_edges(fn, pkg, packages));
+      }
+    }
+  }
+  if (edge_types.includes('embeds')) {
+    for (const pkg of packages) found.push(...find_embed_edges(pkg, packages));
+  }
+  if (edge_types.includes('implements')) {
+    found.push(...find_implements_edges(packages));
+  }
+
+  const merged = new Map();
+  for (const edge of found) {
+    const key = `${edge.type}|${edge.source}|${edge.target}`;
+    if (merged.has(key)) {
+      merged.get(key).count++;
+    } else {
+      merged.set(key, { ...edge, count: 1 });
+    }
+  }
+  const edges = [...merged.values()];
+
+  const by_type = {};
+  for (const type of edge_types) by_type[type] = 0;
+  for (const edge of edges) by_type[edge.type]++;
+
+  return {
+    nodes,
+    edges,
+    packages: packages.map(function format_package(pkg) {
+      return { name: pkg.name, directory: pkg.directory };
+    }),
+    summary: {
+      total_nodes: nodes.length,
+      total_edges: edges.length,
+      total_packages: packages.length,
+      edges_by_type: by_type
+    }
+  };
+};
+
+/**
+ * Build the symbol graph of a project's Go code.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.edge_types] - Edge types to include (default: all)
+ * @param {string} [options.package] - Only include packages with this name or directory
+ * @returns {Promise<Object>} Symbol graph
+ */
+const analyze_symbol_graph = async (project_id, options = {}) => {
+  const edge_types = options.edge_types || EDGE_TYPES;
+  const unknown = edge_types.filter((t) => !EDGE_TYPES.includes(t));
+  if (unknown.length > 0) {
+    throw new Error(
+      `Unknown edge type '${unknown[0]}' (expected one of: ${EDGE_TYPES.join(', ')})`
+    );
+  }
+
+  let packages = await load_go_packages(project_id);
+  if (options.package) {
+    packages = packages.filter(
+      (p) => p.name === options.package || p.directory === options.package
+    );
+  }
+
+  return build_symbol_graph(packages, { edge_types });
+};
+
+// ============================================================================
+// HTML RENDERING
+// ============================================================================
+
+/**
+ * Escape text for use in HTML.
+ * @param {string} text - Text to escape
+ * @returns {string} Escaped text
+ */
+const escape_html = (text) => {
+  return String(text)
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;');
+};
+
+/**
+ * Styles of the HTML graph page.
+ */
+const GRAPH_STYLE = `
+body { margin: 0; font: 13px sans-serif; display: flex; height: 100vh; }
+#sidebar { width: 260px; padding: 12px; overflow-y: auto; border-right: 1px solid #ccc; background: #fafafa; }
+#sidebar h1 { font-size: 16px; margin: 0 0 8px; }
+#sidebar h2 { font-size: 13px; margin: 16px 0 4px; }
+#sidebar label { display: block; }
+#details { white-space: pre-wrap; font-family: monospace; }
+svg { flex: 1; cursor: grab; }
+.node circle { stroke: #fff; stroke-width: 1.5px; cursor: pointer; }
+.node text { pointer-events: none; fill: #333; }
+.edge { stroke-opacity: 0.6; fill: none; }
+.edge.calls { stroke: #999; }
+.edge.implements { stroke: #2a9d8f; stroke-dasharray: 4 2; }
+.edge.embeds { stroke: #e76f51; }
+.dimmed { opacity: 0.1; }
+`;
+
+/**
+ * Script of the HTML graph page.  Lays the graph out with a simple force
+ * simulation and supports pan (drag), zoom (wheel), click-to-focus, edge
+ * type filters and collapsing packages into single nodes.
+ */
+const GRAPH_SCRIPT = `
+const graph = JSON.parse(document.getElementById('graph-data').textContent);
+const svg = document.getElementById('canvas');
+const view = document.getElementById('view');
+const NS = 'http://www.w3.org/2000/svg';
+const COLORS = { function: '#264653', method: '#457b9d', struct: '#e9c46a', interface: '#2a9d8f', type: '#f4a261', package: '#6d597a' };
+const enabled = new Set(Object.keys(graph.summary.edges_by_type));
+const collapsed = new Set();
+const positions = new Map();
+let transform = { x: 0, y: 0, k: 1 };
+let focus = null;
+
+function visible_graph() {
+  const alias = new Map();
+  const nodes = new Map();
+  for (const node of graph.nodes) {
+    if (collapsed.has(node.directory)) {
+      const id = 'package:' + node.directory;
+      alias.set(node.id, id);
+      if (!nodes.has(id)) nodes.set(id, { id, name: node.package + ' (' + node.directory + ')', kind: 'package', directory: node.directory });
+    } else {
+      alias.set(node.id, node.id);
+      nodes.set(node.id, node);
+    }
+  }
+  const edges = new Map();
+  for (const edge of graph.edges) {
+    if (!enabled.has(edge.type)) continue;
+    const source = alias.get(edge.source);
+    const target = alias.get(edge.target);
+    if (!source || !target || source === target) continue;
+    const key = edge.type + '|' + source + '|' + target;
+    if (edges.has(key)) edges.get(key).count += edge.count;
+    else edges.set(key, { source, target, type: edge.type, count: edge.count });
+  }
+  return { nodes: [...nodes.values()], edges: [...edges.values()] };
+}
+
+function layout(nodes, edges) {
+  const width = svg.clientWidth || 800;
+  const height = svg.clientHeight || 600;
+  for (const node of nodes) {
+    if (!positions.has(node.id)) {
+      positions.set(node.id, { x: width / 2 + (Math.random() - 0.5) * width, y: height / 2 + (Math.random() - 0.5) * height });
+    }
+  }
+  for (let step = 0; step < 300; step++) {
+    const force = new Map(nodes.map((n) => [n.id, { x: 0, y: 0 }]));
+    for (let i = 0; i < nodes.length; i++) {
+      for (let j = i + 1; j < nodes.length; j++) {
+        const a = positions.get(nodes[i].id);
+        const b = positions.get(nodes[j].id);
+        const dx = a.x - b.x || 0.01;
+        const dy = a.y - b.y || 0.01;
+        const d2 = Math.max(dx * dx + dy * dy, 1);
+        const f = 2000 / d2;
+        force.get(nodes[i].id).x += dx * f; force.get(nodes[i].id).y += dy * f;
+        force.get(nodes[j].id).x -= dx * f; force.get(nodes[j].id).y -= dy * f;
+      }
+    }
+    for (const edge of edges) {
+      const a = positions.get(edge.source);
+      const b = positions.get(edge.target);
+      const dx = b.x - a.x;
+      const dy = b.y - a.y;
+      force.get(edge.source).x += dx * 0.02; force.get(edge.source).y += dy * 0.02;
+      force.get(edge.target).x -= dx * 0.02; force.get(edge.target).y -= dy * 0.02;
+    }
+    const cooling = 1 - step / 300;
+    for (const node of nodes) {
+      const p = positions.get(node.id);
+      const f = force.get(node.id);
+      p.x += Math.max(-10, Math.min(10, f.x + (width / 2 - p.x) * 0.005)) * cooling;
+      p.y += Math.max(-10, Math.min(10, f.y + (height / 2 - p.y) * 0.005)) * cooling;
+    }
+  }
+}
+
+function neighbours(id, edges) {
+  const result = new Set([id]);
+  for (const edge of edges) {
+    if (edge.source === id) result.add(edge.target);
+    if (edge.target === id) result.add(edge.source);
+  }
+  return result;
+}
+
+function show_details(node, edges) {
+  const details = document.getElementById('details');
+  if (!node) { details.textContent = 'Click a node to focus it.'; return; }
+  const lines = [node.name, node.kind + (node.filename ? ' - ' + node.filename + ':' + node.line : '')];
+  for (const edge of edges) {
+    if (edge.source === node.id) lines.push(edge.type + ' -> ' + edge.target);
+    if (edge.target === node.id) lines.push(edge.type + ' <- ' + edge.source);
+  }
+  details.textContent = lines.join('\\n');
+}
+
+function render() {
+  const { nodes, edges } = visible_graph();
+  layout(nodes, edges);
+  view.replaceChildren();
+  const near = focus ? neighbours(focus, edges) : null;
+  for (const edge of edges) {
+    const a = positions.get(edge.source);
+    const b = positions.get(edge.target);
+    const line = document.createElementNS(NS, 'line');
+    line.setAttribute('x1', a.x); line.setAttribute('y1', a.y);
+    line.setAttribute('x2', b.x); line.setAttribute('y2', b.y);
+    line.setAttribute('class', 'edge ' + edge.type + (near && !(near.has(edge.source) && near.has(edge.target)) ? ' dimmed' : ''));
+    line.setAttribute('stroke-width', Math.min(1 + Math.log2(edge.count), 5));
+    line.setAttribute('marker-end', 'url(#arrow)');
+    view.appendChild(line);
+  }
+  for (const node of nodes) {
+    const p = positions.get(node.id);
+    const group = document.createElementNS(NS, 'g');
+    group.setAttribute('class', 'node' + (near && !near.has(node.id) ? ' dimmed' : ''));
+    group.setAttribute('transform', 'translate(' + p.x + ',' + p.y + ')');
+    const circle = document.createElementNS(NS, 'circle');
+    circle.setAttribute('r', node.kind === 'package' ? 12 : 6);
+    circle.setAttribute('fill', COLORS[node.kind] || '#999');
+    circle.addEventListener('click', function focus_node(event) {
+      event.stopPropagation();
+      focus = focus === node.id ? null : node.id;
+      show_details(focus ? node : null, edges);
+      render();
+    });
+    const label = document.createElementNS(NS, 'text');
+    label.setAttribute('x', 9); label.setAttribute('y', 4);
+    label.textContent = node.name;
+    group.appendChild(circle); group.appendChild(label);
+    view.appendChild(group);
+  }
+  apply_transform();
+}
+
+function apply_transform() {
+  view.setAttribute('transform', 'translate(' + transform.x + ',' + transform.y + ') scale(' + transform.k + ')');
+}
+
+let drag = null;
+svg.addEventListener('mousedown', function start_pan(event) { drag = { x: event.clientX - transform.x, y: event.clientY - transform.y }; });
+window.addEventListener('mousemove', function pan(event) {
+  if (!drag) return;
+  transform.x = event.clientX - drag.x; transform.y = event.clientY - drag.y;
+  apply_transform();
+});
+window.addEventListener('mouseup', function end_pan() { drag = null; });
+svg.addEventListener('wheel', function zoom(event) {
+  event.preventDefault();
+  const factor = event.deltaY < 0 ? 1.1 : 1 / 1.1;
+  const rect = svg.getBoundingClientRect();
+  const mx = event.clientX - rect.left;
+  const my = event.clientY - rect.top;
+  transform.x = mx - (mx - transform.x) * factor;
+  transform.y = my - (my - transform.y) * factor;
+  transform.k *= factor;
+  apply_transform();
+}, { passive: false });
+svg.addEventListener('click', function clear_focus() {
+  if (!focus) return;
+  focus = null; show_details(null, []); render();
+});
+
+function add_toggle(container, label, checked, on_change) {
+  const element = document.createElement('label');
+  const input = document.createElement('input');
+  input.type = 'checkbox'; input.checked = checked;
+  input.addEventListener('change', function toggle() { on_change(input.checked); render(); });
+  element.appendChild(input);
+  element.appendChild(document.createTextNode(' ' + label));
+  container.appendChild(element);
+}
+
+const edge_filters = document.getElementById('edge-filters');
+for (const type of Object.keys(graph.summary.edges_by_type)) {
+  add_toggle(edge_filters, type + ' (' + graph.summary.edges_by_type[type] + ')', true, function set_edge_type(on) {
+    if (on) enabled.add(type); else enabled.delete(type);
+  });
+}
+const package_toggles = document.getElementById('packages');
+for (const pkg of graph.packages) {
+  add_toggle(package_toggles, 'collapse ' + pkg.name + ' (' + pkg.directory + ')', false, function set_collapsed(on) {
+    if (on) collapsed.add(pkg.directory); else collapsed.delete(pkg.directory);
+    focus = null;
+  });
+}
+show_details(null, []);
+render();
+`;
+
+/**
+ * Render a symbol graph as a self-contained interactive HTML page.
+ * The graph JSON and the script are embedded so the page works offline.
+ * @param {Object} graph - Symbol graph (from build_symbol_graph)
+ * @param {Object} [options] - Options
+ * @param {string} [options.title='Symbol Graph'] - Page title
+ * @returns {string} HTML document
+ */
+const render_graph_html = (graph, options = {}) => {
+  const title = escape_html(options.title || 'Symbol Graph');
+  // Keep the embedded JSON from closing the script element
+  const data = JSON.stringify(graph).replace(/</g, '\\u003c');
+
+  return `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>${title}</title>
+<style>${GRAPH_STYLE}</style>
+</head>
+<body>
+<div id="sidebar">
+<h1>${title}</h1>
+<div>${graph.summary.total_nodes} symbols, ${graph.summary.total_edges} edges</div>
+<h2>Edge types</h2>
+<div id="edge-filters"></div>
+<h2>Packages</h2>
+<div id="packages"></div>
+<h2>Details</h2>
+<div id="details"></div>
+</div>
+<svg id="canvas">
+<defs><marker id="arrow" viewBox="0 0 10 10" refX="16" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0L10,5L0,10z" fill="#999"/></marker></defs>
+<g id="view"></g>
+</svg>
+<script type="application/json" id="graph-data">${data}</script>
+<script>${GRAPH_SCRIPT}</script>
+</body>
+</html>
+`;
+};
+
+export {
+  analyze_symbol_graph,
+  build_symbol_graph,
+  render_graph_html,
+  find_call_edges,
+  find_embed_edges,
+  get_imported_packages,
+  get_node_id,
+  find_implements_edges,
+  get_method_key,
+  get_interface_methods,
+  get_type_methods,
+  get_interface_method_set,
+  get_type_method_set,
+  get_value_method_set,
+  get_pointer_method_set,
+  EDGE_TYPES
+};
@@ -0,0 +1,542 @@
+'use strict';
+
+/**
+ * @fileoverview Go interface method usage module.
+ * Finds the interface methods that no caller invokes through the
+ * interface: a method the code only ever calls on concrete types, or not
+ * at all, widens the abstraction for nothing, and is a candidate for
+ * removal from the interface (interface minimization).
+ *
+ * Calls are tracked through the names typed with an interface of the
+ * project:
+ * - parameters, named results, variables and package variables
+ *   (`func Lookup(st Store)`, `var st Store`)
+ * - struct fields (`s.store.Get(key)`)
+ * - type assertions and conversions (`c := v.(cache)`, `Store(m)`)
+ * - locals assigned the result of a function or method returning the
+ *   interface (`rc := Open()`)
+ * - elements of slices, maps and channels of the interface, indexed or
+ *   ranged over (`for _, st := range stores`)
+ * - type parameters constrained by the interface (`[C cache]`)
+ * A call through an interface embedding another (`ReadCloser` embedding
+ * `Reader`) counts for the interface declaring the method.  Method values
+ * (`st.Get` without a call) count as calls.  Calls in test files do not
+ * count, and are given apart.
+ *
+ * Tracking is by name and heuristic: shadowed names are not told apart,
+ * and calls on expressions (`Open().Close()`) are missed.  Each unused
+ * method carries the reasons it may be used after all, and a low
+ * confidence when there is any:
+ * - exported: the interface is exported, modules outside the project may
+ *   call its methods
+ * - struct_embedding: the interface is embedded in a struct, whose
+ *   values call its methods as their own
+ * - reflection: the project calls methods through reflect
+ * - standard_method: the method is the method of a standard library
+ *   interface (`String`, `Close`...), callers may use it through that one
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/ifacemethods
+ */
+
+import {
+  mask_source,
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { get_imported_packages } from './graph.mjs';
+import {
+  format_method_signature,
+  STANDARD_INTERFACES
+} from './interfaces.mjs';
+
+/**
+ * Reasons an unused interface method may be used after all.
+ */
+const UNCERTAINTY_REASONS = [
+  'exported',
+  'struct_embedding',
+  'reflection',
+  'standard_method'
+];
+
+/**
+ * Method names of the standard library interfaces.
+ */
+const STANDARD_METHOD_NAMES = new Set(
+  Object.values(STANDARD_INTERFACES).flat()
+);
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * Build a resolver of type expressions written in a file to the
+ * interfaces of the project.
+ * @param {Object} pkg - Package of the file
+ * @param {string} filename - File the types are written in
+ * @param {Object[]} packages - All project packages
+ * @param {Map<string, Object>} interfaces - Interfaces by directory and name
+ * @returns {Function} Resolver of a type text, with type parameters, to an interface or null
+ */
+const build_type_resolver = (pkg, filename, packages, interfaces) => {
+  let imported = null;
+  return (text, type_params = new Map()) => {
+    if (!text) return null;
+    let type = text.replace(/\s+/g, '').replace(/^\*/, '');
+    if (!type.startsWith('[')) type = type.replace(/\[.*\]$/, '');
+    if (type_params.has(type)) return type_params.get(type);
+    const dot = type.indexOf('.');
+    if (dot === -1) return interfaces.get(`${pkg.directory}:${type}`) || null;
+    imported = imported || get_imported_packages(pkg, filename, packages);
+    const target = imported.get(type.substring(0, dot));
+    if (!target) return null;
+    const name = type.substring(dot + 1);
+    return interfaces.get(`${target.directory}:${name}`) || null;
+  };
+};
+
+/**
+ * Get the element type of a slice, array, map or channel type.
+ * @param {string} text - Type expression
+ * @returns {Object|null} Element type and whether the type is a channel, or null
+ */
+const get_element_type = (text) => {
+  const type = (text || '').replace(/\s+/g, ' ').trim();
+  const match = type.match(
+    /^(?:\[[^\]]*\]|map\[[^\]]+\]|(<-\s*)?chan(?:\s*<-)?\s)\s*(.+)$/
+  );
+  if (!match) return null;
+  return { type: match[2], chan: type.includes('chan') };
+};
+
+/**
+ * Map the type parameters of a function to the interfaces constraining
+ * them (`[C cache, T any]`).
+ * @param {string|null} text - Type parameter list, without brackets
+ * @param {Function} resolve - Type resolver (from build_type_resolver)
+ * @returns {Map<string, Object>} Interfaces by type parameter name
+ */
+const resolve_type_params = (text, resolve) => {
+  const type_params = new Map();
+  if (!text) return type_params;
+  for (const [, names, constraint] of text.matchAll(
+    /(\w+(?:\s*,\s*\w+)*)\s+([\w.*]+(?:\[[^\]]*\])?)/g
+  )) {
+    const iface = resolve(constraint);
+    if (!iface) continue;
+    for (const name of names.split(',')) type_params.set(name.trim(), iface);
+  }
+  return type_params;
+};
+
+/**
+ * Collect the methods an interface has, declared or through embedded
+ * interfaces of the project, with the interface declaring each.
+ * @param {Object} iface - Interface entry
+ * @param {Set<string>} [seen] - Interfaces already expanded
+ * @returns {Map<string, Object>} Declaring interface by method name
+ */
+const get_method_origins = (iface, seen = new Set()) => {
+  if (iface.origins) return iface.origins;
+  const origins = new Map(iface.type.methods.map((m) => [m.name, iface]));
+  seen.add(iface.key);
+  for (const embed of iface.type.embeds) {
+    const inner = iface.resolve(embed.type);
+    if (!inner || seen.has(inner.key)) continue;
+    for (const [name, origin] of get_method_origins(inner, seen)) {
+      if (!origins.has(name)) origins.set(name, origin);
+    }
+  }
+  iface.origins = origins;
+  return origins;
+};
+
+// ============================================================================
+// CALLS
+// ============================================================================
+
+/**
+ * Collect the interfaces of a set of packages, and the names typed with
+ * them at package level: package variables, struct fields, and the
+ * results of functions and methods.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object} Interfaces by key and the package level bindings
+ */
+const collect_interfaces = (packages) => {
+  const interfaces = new Map();
+  const resolvers = new Map();
+  const get_resolver = (pkg, filename) => {
+    const key = `${pkg.directory}:${filename}`;
+    if (!resolvers.has(key)) {
+      resolvers.set(
+        key,
+        build_type_resolver(pkg, filename, packages, interfaces)
+      );
+    }
+    return resolvers.get(key);
+  };
+
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind !== 'interface' || type.filename.endsWith('_test.go')) {
+        continue;
+      }
+      const key = `${pkg.directory}:${type.name}`;
+      interfaces.set(key, {
+        key,
+        type,
+        pkg,
+        resolve: get_resolver(pkg, type.filename),
+        calls: new Map(),
+        test_calls: new Map(),
+        embedded_in: []
+      });
+    }
+  }
+
+  const fields = new Map();
+  const field_collections = new Map();
+  const functions = new Map();
+  const methods = new Map();
+  const variables = new Map(packages.map((pkg) => [pkg, new Map()]));
+  const add_result = (map, key, iface) => {
+    if (map.has(key) && map.get(key) !== iface) map.set(key, null);
+    else map.set(key, iface);
+  };
+
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      const resolve = get_resolver(pkg, type.filename);
+      for (const field of type.kind === 'struct' ? type.fields : []) {
+        const iface = resolve(field.type);
+        if (iface && field.embedded) iface.embedded_in.push(type.name);
+        if (iface) {
+          for (const name of field.names) fields.set(name, iface);
+          continue;
+        }
+        const element = get_element_type(field.type);
+        const inner = element && resolve(element.type);
+        if (!inner) continue;
+        for (const name of field.names) {
+          field_collections.set(name, { iface: inner, chan: element.chan });
+        }
+      }
+      for (const method of type.kind === 'interface' ? type.methods : []) {
+        const iface = method.results[0] && resolve(method.results[0].type);
+        if (iface) add_result(methods, method.name, iface);
+      }
+    }
+    for (const fn of pkg.functions) {
+      const resolve = get_resolver(pkg, fn.filename);
+      const iface = fn.results[0] && resolve(fn.results[0].type);
+      if (!iface) continue;
+      if (fn.receiver) add_result(methods, fn.name, iface);
+      else functions.set(`${pkg.directory}:${fn.name}`, iface);
+    }
+    for (const v of pkg.vars) {
+      const iface = get_resolver(pkg, v.filename)(v.type);
+      for (const name of iface ? v.names : []) {
+        variables.get(pkg).set(name, iface);
+      }
+    }
+  }
+
+  return {
+    interfaces,
+    get_resolver,
+    fields,
+    field_collections,
+    functions,
+    methods,
+    variables
+  };
+};
+
+/**
+ * Record the calls through interfaces of a function body.
+ * @param {Object} fn - Function or method (from the Go parser)
+ * @param {Object} pkg - Package of the function
+ * @param {Object} context - Interfaces and bindings (from collect_interfaces)
+ */
+const record_interface_calls = (fn, pkg, context) => {
+  const resolve = context.get_resolver(pkg, fn.filename);
+  const type_params = resolve_type_params(fn.type_params, resolve);
+  const counter = fn.filename.endsWith('_test.go') ? 'test_calls' : 'calls';
+  const masked = mask_source(fn.body);
+  const names = new Map(context.variables.get(pkg));
+  const collections = new Map();
+
+  const bind = (name, type, variadic = false) => {
+    if (!name || name === '_') return;
+    const iface = resolve(type, type_params);
+    if (iface && variadic) collections.set(name, { iface, chan: false });
+    else if (iface) names.set(name, iface);
+    if (iface || variadic) return;
+    const element = get_element_type(type);
+    const inner = element && resolve(element.type, type_params);
+    if (inner) collections.set(name, { iface: inner, chan: element.chan });
+  };
+  const call = (iface, method) => {
+    const origin = iface && get_method_origins(iface).get(method);
+    if (!origin) return;
+    origin[counter].set(method, (origin[counter].get(method) || 0) + 1);
+  };
+  const find_result = (callee) => {
+    const dot = callee.lastIndexOf('.');
+    if (dot === -1) return context.functions.get(`${pkg.directory}:${callee}`);
+    const imported = get_imported_packages(pkg, fn.filename, context.packages);
+    const target = imported.get(callee.substring(0, dot));
+    const name = callee.substring(dot + 1);
+    if (target) return context.functions.get(`${target.directory}:${name}`);
+    return context.methods.get(name);
+  };
+
+  for (const param of fn.params) bind(param.name, param.type, param.variadic);
+  for (const result of fn.results) bind(result.name, result.type);
+  for (const [, list, type] of masked.matchAll(
+    /\bvar\s+(\w+(?:\s*,\s*\w+)*)\s+([^=\n;]+)/g
+  )) {
+    for (const name of list.split(',')) bind(name.trim(), type.trim());
+  }
+  for (const [, name, type] of masked.matchAll(
+    /\b(\w+)(?:\s*,\s*\w+)?\s*:?=\s*[\w.]*\s*\.\s*\(\s*([\w.*]+)\s*\)/g
+  )) {
+    bind(name, type);
+  }
+  for (const [, name, callee] of masked.matchAll(
+    /\b(\w+)(?:\s*,\s*\w+)*\s*:?=\s*([\w.]+)\s*\(/g
+  )) {
+    const iface = resolve(callee, type_params) || find_result(callee);
+    if (iface && name !== '_') names.set(name, iface);
+  }
+  for (const [, first, second, expr] of masked.matchAll(
+    /\bfor\s+(\w+)(?:\s*,\s*(\w+))?\s*:?=\s*range\s+([\w.]+)/g
+  )) {
+    const last = expr.substring(expr.lastIndexOf('.') + 1);
+    const collection = expr.includes('.')
+      ? context.field_collections.get(last)
+      : collections.get(expr);
+    if (!collection) continue;
+    const value = second || (collection.chan ? first : null);
+    if (value && value !== '_') names.set(value, collection.iface);
+  }
+
+  const member = '\\s*\\.\\s*([A-Za-z_]\\w*)';
+  const index = '\\s*\\[[^\\]]*\\]';
+  for (const [name, iface] of names) {
+    for (const match of masked.matchAll(
+      new RegExp(`(?<![\\w.])${name}${member}`, 'g')
+    )) {
+      call(iface, match[1]);
+    }
+  }
+  for (const [name, { iface }] of collections) {
+    for (const match of masked.matchAll(
+      new RegExp(`(?<![\\w.])${name}${index}${member}`, 'g')
+    )) {
+      call(iface, match[1]);
+    }
+  }
+  for (const match of masked.matchAll(
+    new RegExp(`\\.\\s*(\\w+)(${index})?${member}`, 'g')
+  )) {
+    if (match[2]) {
+      const collection = context.field_collections.get(match[1]);
+      if (collection) call(collection.iface, match[3]);
+    } else {
+      call(context.fields.get(match[1]), match[3]);
+    }
+  }
+  for (const match of masked.matchAll(
+    new RegExp(`\\.\\s*\\(\\s*([\\w.*]+)\\s*\\)${member}`, 'g')
+  )) {
+    call(resolve(match[1], type_params), match[2]);
+  }
+};
+
+/**
+ * Tell whether a set of packages calls methods through reflect
+ * (`reflect.ValueOf(v).MethodByName(name)`).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {boolean} True if a non-test function calls methods by reflection
+ */
+const uses_method_reflection = (packages) => {
+  return packages.some((pkg) =>
+    pkg.functions.some(
+      (fn) =>
+        fn.body &&
+        !fn.filename.endsWith('_test.go') &&
+        pkg.imports.some(
+          (imp) => imp.filename === fn.filename && imp.path === 'reflect'
+        ) &&
+        /\.\s*(?:MethodByName|Method)\s*\(/.test(mask_source(fn.body))
+    )
+  );
+};
+
+// ============================================================================
+// UNUSED INTERFACE METHODS
+// ============================================================================
+
+/**
+ * Find the interface methods of a set of packages never called through
+ * their interface (see the module doc).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.unexported_only=false] - Only report the methods of unexported interfaces
+ * @param {string} [options.confidence] - Only report the methods of this confidence (high or low)
+ * @returns {Object} Unused methods by file and line, and the usage of each interface
+ */
+const find_unused_interface_methods = (packages, options = {}) => {
+  if (options.confidence && !['high', 'low'].includes(options.confidence)) {
+    throw new Error(
+      `Unknown confidence '${options.confidence}', expected high or low`
+    );
+  }
+  const context = { ...collect_interfaces(packages), packages };
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.body) record_interface_calls(fn, pkg, context);
+    }
+  }
+  const reflection = uses_method_reflection(packages);
+
+  const unused = [];
+  const interfaces = [];
+  for (const iface of context.interfaces.values()) {
+    const { type, pkg } = iface;
+    if (type.methods.length === 0) continue;
+    const reasons = [];
+    if (type.exported) reasons.push('exported');
+    if (iface.embedded_in.length > 0) reasons.push('struct_embedding');
+    if (reflection) reasons.push('reflection');
+
+    const used = type.methods.filter((m) => iface.calls.has(m.name));
+    const never = type.methods.filter((m) => !iface.calls.has(m.name));
+    interfaces.push({
+      name: type.name,
+      package: pkg.name,
+      directory: pkg.directory,
+      filename: type.filename,
+      line: type.line,
+      exported: type.exported,
+      methods: type.methods.length,
+      used: used.map((m) => m.name),
+      unused: never.map((m) => m.name),
+      embedded_in: iface.embedded_in,
+      uncertainty: reasons,
+      classification:
+        never.length === 0 ? 'used' : used.length === 0 ? 'unused' : 'partial'
+    });
+
+    if (options.unexported_only && type.exported) continue;
+    for (const method of never) {
+      const uncertainty = STANDARD_METHOD_NAMES.has(method.name)
+        ? [...reasons, 'standard_method']
+        : reasons;
+      const confidence = uncertainty.length === 0 ? 'high' : 'low';
+      if (options.confidence && options.confidence !== confidence) continue;
+      const test_calls = iface.test_calls.get(method.name) || 0;
+      const notes = [];
+      let message = `${type.name}.${method.name} is never called through the interface; consider removing it from ${type.name}`;
+      if (test_calls > 0) notes.push('only called in tests');
+      if (uncertainty.length > 0) {
+        notes.push(`uncertain: ${uncertainty.join(', ')}`);
+      }
+      if (notes.length > 0) message += ` (${notes.join('; ')})`;
+      unused.push({
+        interface: type.name,
+        method: method.name,
+        signature: format_method_signature(method),
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: type.filename,
+        line: method.line || type.line,
+        exported: type.exported,
+        test_calls,
+        interface_called: used.length > 0,
+        uncertainty,
+        confidence,
+        message
+      });
+    }
+  }
+
+  function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  }
+  return {
+    unused: unused.sort(sort_by_location),
+    interfaces: interfaces.sort(sort_by_location)
+  };
+};
+
+/**
+ * Summarize an interface method usage report.
+ * @param {Object} report - Report (from find_unused_interface_methods)
+ * @returns {Object} Numbers of interfaces by usage, methods, unused methods by confidence and only called in tests
+ */
+const summarize_interface_method_usage = (report) => {
+  const count = (classification) =>
+    report.interfaces.filter((i) => i.classification === classification)
+      .length;
+  return {
+    interfaces: report.interfaces.length,
+    used_interfaces: count('used'),
+    partial_interfaces: count('partial'),
+    unused_interfaces: count('unused'),
+    methods: report.interfaces.reduce((sum, i) => sum + i.methods, 0),
+    unused: report.unused.length,
+    by_confidence: {
+      high: report.unused.filter((m) => m.confidence === 'high').length,
+      low: report.unused.filter((m) => m.confidence === 'low').length
+    },
+    test_only: report.unused.filter((m) => m.test_calls > 0).length
+  };
+};
+
+/**
+ * Report the unused interface methods of a set of Go sources, such as
+ * the files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_unused_interface_methods)
+ * @returns {Object} Unused methods and interfaces with a summary
+ */
+const find_source_unused_interface_methods = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const report = find_unused_interface_methods(
+    group_go_packages(files),
+    options
+  );
+  return { ...report, summary: summarize_interface_method_usage(report) };
+};
+
+/**
+ * Report the unused interface methods of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_unused_interface_methods)
+ * @returns {Promise<Object>} Unused methods and interfaces with a summary
+ */
+const analyze_project_unused_interface_methods = async (
+  project_id,
+  options = {}
+) => {
+  const report = find_unused_interface_methods(
+    await load_go_packages(project_id),
+    options
+  );
+  return { ...report, summary: summarize_interface_method_usage(report) };
+};
+
+export {
+  analyze_project_unused_interface_methods,
+  find_source_unused_interface_methods,
+  find_unused_interface_methods,
+  summarize_interface_method_usage,
+  UNCERTAINTY_REASONS
+};
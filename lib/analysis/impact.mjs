@@ -0,0 +1,418 @@
+'use strict';
+
+/**
+ * @fileoverview Diff impact module.
+ * Parses unified diffs and maps their changed lines onto the Go symbols of
+ * the old and new versions of each file, turning a line based diff into a
+ * list of the functions, methods, types, constants and variables that were
+ * added, removed or modified.  Useful for summarizing pull requests.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/impact
+ */
+
+import { parse_go_file } from './golang.mjs';
+
+/**
+ * A hunk header such as `@@ -12,5 +12,7 @@ func Name() {`.
+ */
+const HUNK_HEADER = /^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@/;
+
+/**
+ * Symbol change kinds, in display order.
+ */
+const CHANGE_KINDS = ['added', 'removed', 'modified'];
+
+// ============================================================================
+// DIFF PARSING
+// ============================================================================
+
+/**
+ * Strip the a/ or b/ prefix git puts on diff paths.
+ * @param {string} text - Path from a ---/+++ line or diff header
+ * @returns {string|null} Path, or null for /dev/null
+ */
+const clean_diff_path = (text) => {
+  const path = text.trim().split('\t')[0].replace(/^"(.*)"$/, '$1');
+  if (path === '/dev/null') return null;
+  return path.replace(/^[ab]\//, '');
+};
+
+/**
+ * Parse a unified diff, as printed by `git diff` or `diff -u`.
+ * Each hunk records its lines and the old and new line numbers that were
+ * removed and added.  Added, deleted and renamed files are recognized
+ * from git's extended headers or /dev/null paths.
+ * @param {string} text - Unified diff
+ * @returns {Object[]} Files with old and new paths, status and hunks
+ */
+const parse_unified_diff = (text) => {
+  const files = [];
+  let file = null;
+  let hunk = null;
+  let old_line = 0;
+  let new_line = 0;
+
+  const start_file = () => {
+    file = {
+      old_path: null,
+      new_path: null,
+      status: 'modified',
+      binary: false,
+      hunks: []
+    };
+    files.push(file);
+    hunk = null;
+  };
+
+  for (const line of (text || '').split('\n')) {
+    if (line.startsWith('diff --git ')) {
+      start_file();
+      const match = line.match(/^diff --git a\/(.+) b\/(.+)$/);
+      if (match) {
+        file.old_path = match[1];
+        file.new_path = match[2];
+      }
+      continue;
+    }
+
+    // Inside a hunk, lines are counted until both sides are complete
+    if (hunk && (hunk.old_remaining > 0 || hunk.new_remaining > 0)) {
+      const op = line[0];
+      const content = line.substring(1);
+      if (op === '+') {
+        hunk.lines.push({ op, text: content });
+        hunk.added.push(new_line++);
+        hunk.new_remaining--;
+        continue;
+      }
+      if (op === '-') {
+        hunk.lines.push({ op, text: content });
+        hunk.removed.push(old_line++);
+        hunk.old_remaining--;
+        continue;
+      }
+      if (op === ' ' || line === '') {
+        hunk.lines.push({ op: ' ', text: content });
+        old_line++;
+        new_line++;
+        hunk.old_remaining--;
+        hunk.new_remaining--;
+        continue;
+      }
+    }
+    if (line.startsWith('\\')) continue;
+
+    if (line.startsWith('--- ')) {
+      if (!file || file.hunks.length > 0) start_file();
+      file.old_path = clean_diff_path(line.substring(4));
+      if (file.old_path === null) file.status = 'added';
+      continue;
+    }
+    if (line.startsWith('+++ ') && file) {
+      file.new_path = clean_diff_path(line.substring(4));
+      if (file.new_path === null) file.status = 'deleted';
+      continue;
+    }
+    if (!file) continue;
+
+    if (line.startsWith('new file mode')) file.status = 'added';
+    else if (line.startsWith('deleted file mode')) file.status = 'deleted';
+    else if (line.startsWith('rename from ')) {
+      file.old_path = line.substring('rename from '.length);
+      file.status = 'renamed';
+    } else if (line.startsWith('rename to ')) {
+      file.new_path = line.substring('rename to '.length);
+      file.status = 'renamed';
+    } else if (line.startsWith('Binary files ')) file.binary = true;
+
+    const header = line.match(HUNK_HEADER);
+    if (header) {
+      const old_count = header[2] === undefined ? 1 : parseInt(header[2]);
+      const new_count = header[4] === undefined ? 1 : parseInt(header[4]);
+      old_line = parseInt(header[1]);
+      new_line = parseInt(header[3]);
+      hunk = {
+        old_start: old_line,
+        old_lines: old_count,
+        new_start: new_line,
+        new_lines: new_count,
+        old_remaining: old_count,
+        new_remaining: new_count,
+        lines: [],
+        added: [],
+        removed: []
+      };
+      file.hunks.push(hunk);
+    }
+  }
+
+  for (const entry of files) {
+    if (entry.status === 'added') entry.old_path = null;
+    if (entry.status === 'deleted') entry.new_path = null;
+    for (const h of entry.hunks) {
+      delete h.old_remaining;
+      delete h.new_remaining;
+    }
+  }
+
+  return files.filter((f) => f.old_path || f.new_path);
+};
+
+/**
+ * Rebuild the old version of a file by applying its hunks in reverse to
+ * the new version.
+ * @param {string} new_source - New version of the file
+ * @param {Object[]} hunks - Hunks (from parse_unified_diff)
+ * @returns {string|null} Old version, or null if the hunks do not match
+ */
+const reverse_apply_hunks = (new_source, hunks) => {
+  const lines = new_source === '' ? [] : new_source.split('\n');
+  const old_lines = [];
+  let index = 0;
+
+  for (const hunk of hunks) {
+    // Hunks without new lines start after new_start instead of at it
+    const start = hunk.new_lines === 0 ? hunk.new_start : hunk.new_start - 1;
+    if (start < index) return null;
+    while (index < start && index < lines.length) {
+      old_lines.push(lines[index++]);
+    }
+
+    for (const { op, text } of hunk.lines) {
+      if (op === '-') {
+        old_lines.push(text);
+        continue;
+      }
+      if (lines[index] !== text) return null;
+      if (op === ' ') old_lines.push(text);
+      index++;
+    }
+  }
+  while (index < lines.length) old_lines.push(lines[index++]);
+
+  return old_lines.join('\n');
+};
+
+// ============================================================================
+// SYMBOL SPANS
+// ============================================================================
+
+/**
+ * Find the first line of the comment block directly above a line.
+ * @param {string[]} lines - Source lines
+ * @param {number} line - 1-based line of the declaration
+ * @returns {number} First line of its doc comment, or the line itself
+ */
+const get_doc_start = (lines, line) => {
+  let start = line;
+  while (start > 1 && /^\s*\/\//.test(lines[start - 2])) start--;
+  return start;
+};
+
+/**
+ * List the spans of the top level symbols of a Go file, including their
+ * doc comments.  Methods are named Type.Method.
+ * @param {string} source - Go source
+ * @param {string} [filename=''] - Filename
+ * @returns {Object[]} Symbols with key, name, kind, start and end lines
+ */
+const get_go_symbol_spans = (source, filename = '') => {
+  const file = parse_go_file(source, filename);
+  const lines = source.split('\n');
+  const spans = [];
+
+  const add = (name, kind, line, end_line, with_doc = true) => {
+    spans.push({
+      key: `${kind}:${name}`,
+      name,
+      kind,
+      line,
+      start: with_doc ? get_doc_start(lines, line) : line,
+      end_line: end_line || line
+    });
+  };
+
+  for (const fn of file.functions) {
+    if (!fn.name) continue;
+    const receiver = fn.receiver ? fn.receiver.type.replace(/\[.*$/, '') : null;
+    const name = receiver ? `${receiver}.${fn.name}` : fn.name;
+    add(name, receiver ? 'method' : 'function', fn.line, fn.end_line);
+  }
+  for (const type of file.types) {
+    add(type.name, 'type', type.line, type.end_line);
+  }
+  for (const [decls, kind] of [
+    [file.consts, 'const'],
+    [file.vars, 'var']
+  ]) {
+    for (const decl of decls) {
+      for (const name of decl.names.filter((n) => n !== '_')) {
+        add(name, kind, decl.line, decl.end_line, !decl.grouped);
+      }
+    }
+  }
+
+  return spans;
+};
+
+/**
+ * Count the lines of a list that fall within a symbol's span.
+ * @param {number[]} changed - Changed line numbers
+ * @param {Object} span - Symbol span
+ * @returns {number} Number of changed lines in the span
+ */
+const count_lines_in_span = (changed, span) => {
+  return changed.filter((line) => line >= span.start && line <= span.end_line)
+    .length;
+};
+
+// ============================================================================
+// IMPACT
+// ============================================================================
+
+/**
+ * Map the changes of one file onto its symbols.
+ * Symbols only in the new version were added and symbols only in the old
+ * version removed; symbols in both were modified when changed lines fall
+ * within their span in either version.
+ * @param {Object} file - File (from parse_unified_diff)
+ * @param {string|null} old_source - Old version (null for added files)
+ * @param {string|null} new_source - New version (null for deleted files)
+ * @returns {Object} Symbol changes and the number of changed lines outside symbols
+ */
+const get_file_impact = (file, old_source, new_source) => {
+  const removed = file.hunks.flatMap((h) => h.removed);
+  const added = file.hunks.flatMap((h) => h.added);
+  const old_spans = old_source ? get_go_symbol_spans(old_source) : [];
+  const new_spans = new_source ? get_go_symbol_spans(new_source) : [];
+  const old_by_key = new Map(old_spans.map((s) => [s.key, s]));
+  const new_by_key = new Map(new_spans.map((s) => [s.key, s]));
+  const symbols = [];
+
+  for (const span of new_spans) {
+    const before = old_by_key.get(span.key);
+    const lines_added = count_lines_in_span(added, span);
+    const lines_removed = before ? count_lines_in_span(removed, before) : 0;
+    if (before && lines_added === 0 && lines_removed === 0) continue;
+    symbols.push({
+      name: span.name,
+      kind: span.kind,
+      change: before ? 'modified' : 'added',
+      line: span.line,
+      end_line: span.end_line,
+      lines_added,
+      lines_removed
+    });
+  }
+  for (const span of old_spans) {
+    if (new_by_key.has(span.key)) continue;
+    symbols.push({
+      name: span.name,
+      kind: span.kind,
+      change: 'removed',
+      line: span.line,
+      end_line: span.end_line,
+      lines_added: 0,
+      lines_removed: count_lines_in_span(removed, span)
+    });
+  }
+
+  symbols.sort(function sort_by_change(a, b) {
+    const a_kind = CHANGE_KINDS.indexOf(a.change);
+    const b_kind = CHANGE_KINDS.indexOf(b.change);
+    if (a_kind !== b_kind) return a_kind - b_kind;
+    return a.line - b.line;
+  });
+
+  const outside = (changed, spans) =>
+    changed.filter(
+      (line) => !spans.some((s) => line >= s.start && line <= s.end_line)
+    ).length;
+
+  return {
+    symbols,
+    lines_outside_symbols:
+      outside(added, new_spans) + outside(removed, old_spans)
+  };
+};
+
+/**
+ * Summarize the symbol level impact of a unified diff.
+ * The new version of each file is read with get_source; the old version
+ * is rebuilt by applying the diff in reverse.  Only Go files are mapped
+ * onto symbols; other files are listed with their line counts.
+ * @param {string} diff - Unified diff
+ * @param {Function} get_source - Returns the new source of a path, or null if it is unavailable
+ * @returns {Promise<Object>} Changed files with their symbol changes and a summary
+ */
+const summarize_diff_impact = async (diff, get_source) => {
+  const files = [];
+
+  for (const file of parse_unified_diff(diff)) {
+    const filename = file.new_path || file.old_path;
+    const entry = {
+      filename,
+      old_filename: file.status === 'renamed' ? file.old_path : null,
+      status: file.status,
+      lines_added: file.hunks.reduce((sum, h) => sum + h.added.length, 0),
+      lines_removed: file.hunks.reduce((sum, h) => sum + h.removed.length, 0),
+      symbols: [],
+      lines_outside_symbols: 0,
+      error: null
+    };
+    files.push(entry);
+    if (file.binary || !/\.go$/.test(filename)) continue;
+
+    const new_source =
+      file.status === 'deleted' ? '' : await get_source(file.new_path);
+    if (new_source === null || new_source === undefined) {
+      entry.error = `Cannot read the new version of ${filename}`;
+      continue;
+    }
+    const old_source = reverse_apply_hunks(new_source, file.hunks);
+    if (old_source === null) {
+      entry.error = `The diff does not apply to the new version of ${filename}`;
+      continue;
+    }
+
+    const impact = get_file_impact(
+      file,
+      file.status === 'added' ? null : old_source,
+      file.status === 'deleted' ? null : new_source
+    );
+    entry.symbols = impact.symbols;
+    entry.lines_outside_symbols = impact.lines_outside_symbols;
+  }
+
+  const symbols = files.flatMap((f) => f.symbols);
+  const by_change = {};
+  const by_kind = {};
+  for (const symbol of symbols) {
+    by_change[symbol.change] = (by_change[symbol.change] || 0) + 1;
+    by_kind[symbol.kind] = (by_kind[symbol.kind] || 0) + 1;
+  }
+
+  return {
+    files,
+    summary: {
+      files_changed: files.length,
+      files_added: files.filter((f) => f.status === 'added').length,
+      files_deleted: files.filter((f) => f.status === 'deleted').length,
+      symbols_changed: symbols.length,
+      symbols_added: by_change.added || 0,
+      symbols_removed: by_change.removed || 0,
+      symbols_modified: by_change.modified || 0,
+      by_kind
+    }
+  };
+};
+
+export {
+  summarize_diff_impact,
+  get_file_impact,
+  get_go_symbol_spans,
+  get_doc_start,
+  parse_unified_diff,
+  reverse_apply_hunks,
+  CHANGE_KINDS
+};
@@ -0,0 +1,218 @@
+'use strict';
+
+/**
+ * @fileoverview Go import usage module.
+ * Cross-references the imports of each file against the qualified
+ * identifiers (`fmt.Println`) used in it and reports the imports that are
+ * never used, with their span.  Unused imports are a compile error in Go;
+ * finding them from the source catches them before compiling, in editors
+ * and in generated code.  Blank (`_ "embed"`) and dot (`. "math"`) imports
+ * are always considered used.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/imports
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  parse_go_file,
+  load_go_sources
+} from './golang.mjs';
+
+/**
+ * A qualified identifier (`pkg.Name`), not itself a selector (`a.pkg.Name`).
+ */
+const QUALIFIED_PATTERN = /(?<![\w.])([A-Za-z_]\w*)\s*\.\s*[A-Za-z_]/g;
+
+// ============================================================================
+// IMPORT NAMES
+// ============================================================================
+
+/**
+ * Guess the package name of an import path: its last element without a
+ * major version (`/v2`, `.v3`) or a `go-` prefix and `-go` suffix.
+ * The actual name is the package clause of the imported package, which is
+ * only known for project packages.
+ * @param {string} path - Import path
+ * @returns {string} Package name
+ */
+const get_default_import_name = (path) => {
+  const elements = path.split('/').filter(Boolean);
+  let last = elements.pop() || path;
+  if (/^v\d+$/.test(last) && elements.length > 0) last = elements.pop();
+  return last
+    .replace(/\.v\d+$/, '')
+    .replace(/^go-/, '')
+    .replace(/-go$/, '')
+    .replace(/[^\w]/g, '_');
+};
+
+/**
+ * Get the name a file refers to an import by: its explicit name, the name
+ * of the project package it imports or the name derived from its path,
+ * which is a guess for packages outside the standard library.
+ * @param {Object} imp - Import (from parse_go_file)
+ * @param {Map<string, string>} [package_names] - Package names by directory
+ * @returns {{name: string, guessed: boolean}} Import name
+ */
+const get_import_name = (imp, package_names = new Map()) => {
+  if (imp.name) return { name: imp.name, guessed: false };
+  for (const [directory, name] of package_names) {
+    if (
+      directory !== '.' &&
+      (imp.path === directory || imp.path.endsWith(`/${directory}`))
+    ) {
+      return { name, guessed: false };
+    }
+  }
+  // Standard library package names always match their path
+  const guessed = imp.path.split('/')[0].includes('.');
+  return { name: get_default_import_name(imp.path), guessed };
+};
+
+// ============================================================================
+// USAGE
+// ============================================================================
+
+/**
+ * Find the qualified identifiers of a Go source file, ignoring comments and
+ * string literals.
+ * @param {string} source - Go source code
+ * @returns {Map<string, Object>} Uses (count and first line) by qualifier
+ */
+const find_qualified_identifiers = (source) => {
+  const masked = mask_source(source || '');
+  const line_index = build_line_index(masked);
+  const uses = new Map();
+
+  let match;
+  QUALIFIED_PATTERN.lastIndex = 0;
+  while ((match = QUALIFIED_PATTERN.exec(masked)) !== null) {
+    const use = uses.get(match[1]);
+    if (use) {
+      use.count++;
+    } else {
+      uses.set(match[1], { count: 1, line: line_at(line_index, match.index) });
+    }
+  }
+
+  return uses;
+};
+
+/**
+ * Report the usage of every import of a Go source file.
+ * @param {string} source - Go source code
+ * @param {string} [filename=''] - Filename
+ * @param {Map<string, string>} [package_names] - Project package names by directory
+ * @returns {Object} File with its package, imports and unused imports
+ */
+const find_file_import_usage = (source, filename = '', package_names) => {
+  const file = parse_go_file(source, filename);
+  const uses = find_qualified_identifiers(source);
+
+  const imports = file.imports.map((imp) => {
+    const { name, guessed } = get_import_name(imp, package_names);
+    const always_used = imp.name === '_' || imp.name === '.';
+    const use = always_used ? null : uses.get(name);
+    return {
+      name,
+      path: imp.path,
+      alias: imp.name,
+      guessed,
+      kind: imp.name === '_' ? 'blank' : imp.name === '.' ? 'dot' : 'named',
+      used: always_used || Boolean(use),
+      uses: use ? use.count : 0,
+      line: imp.line,
+      column: imp.column,
+      end_line: imp.end_line,
+      end_column: imp.end_column
+    };
+  });
+
+  return {
+    filename,
+    package: file.package,
+    imports,
+    unused: imports
+      .filter((imp) => !imp.used)
+      .map((imp) => ({
+        ...imp,
+        message: `"${imp.path}" imported${imp.alias ? ` as ${imp.alias}` : ''} and not used`
+      }))
+  };
+};
+
+/**
+ * Map the directories of a set of Go sources to their package names.
+ * @param {Object[]} sources - Sources with filename and source
+ * @returns {Map<string, string>} Package names by directory
+ */
+const get_package_names = (sources) => {
+  const names = new Map();
+  for (const row of sources) {
+    const match = (row.source || '').match(/^package\s+(\w+)/m);
+    if (!match || match[1] === 'main' || row.filename.endsWith('_test.go')) {
+      continue;
+    }
+    const slash = row.filename.lastIndexOf('/');
+    const directory = slash === -1 ? '.' : row.filename.substring(0, slash);
+    if (!names.has(directory)) names.set(directory, match[1]);
+  }
+  return names;
+};
+
+/**
+ * Report the import usage of a set of Go sources.  Files without imports
+ * are not listed.
+ * @param {Object[]} sources - Sources with filename and source
+ * @returns {Object[]} Files with their imports and unused imports
+ */
+const find_import_usage = (sources) => {
+  const package_names = get_package_names(sources);
+  return sources
+    .map(function check_source(row) {
+      return find_file_import_usage(row.source, row.filename, package_names);
+    })
+    .filter((file) => file.imports.length > 0);
+};
+
+/**
+ * Report the import usage of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.unused_only=false] - Only list files with unused imports
+ * @returns {Promise<Object>} Files, unused imports and a summary
+ */
+const analyze_project_imports = async (project_id, options = {}) => {
+  let files = find_import_usage(await load_go_sources(project_id));
+  const unused = files.flatMap((file) =>
+    file.unused.map((imp) => ({ filename: file.filename, ...imp }))
+  );
+
+  const summary = {
+    files: files.length,
+    imports: files.reduce((sum, file) => sum + file.imports.length, 0),
+    unused_imports: unused.length,
+    files_with_unused: files.filter((file) => file.unused.length > 0).length,
+    guessed_names: files.reduce(
+      (sum, file) => sum + file.imports.filter((imp) => imp.guessed).length,
+      0
+    )
+  };
+
+  if (options.unused_only) {
+    files = files.filter((file) => file.unused.length > 0);
+  }
+  return { files, unused_imports: unused, summary };
+};
+
+export {
+  analyze_project_imports,
+  find_import_usage,
+  find_file_import_usage,
+  find_qualified_identifiers,
+  get_import_name,
+  get_default_import_name,
+  get_package_names
+};
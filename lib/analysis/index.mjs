@@ -28,12 +28,173 @@ import {
   get_inheritance_stats
 } from '../model/inheritance.mjs';
 import { get_class_entities } from '../model/entity.mjs';
-import { analyze_project_concurrency } from './concurrency.mjs';
+import {
+  analyze_project_concurrency,
+  analyze_project_channels
+} from './concurrency.mjs';
 import { analyze_project_resources } from './resources.mjs';
 import { analyze_project_naming } from './naming.mjs';
-import { analyze_project_readability } from './readability.mjs';
+import {
+  analyze_project_readability,
+  analyze_project_nesting
+} from './readability.mjs';
 import { analyze_project_patterns } from './patterns.mjs';
 import { analyze_project_tests } from './testing.mjs';
+import {
+  analyze_rename_impact,
+  analyze_project_duplicate_methods,
+  compare_symbol,
+  compare_symbol_sources
+} from './refactoring.mjs';
+import {
+  analyze_project_struct_sizes,
+  analyze_project_field_init,
+  analyze_project_struct_kinds,
+  analyze_project_method_shadowing,
+  analyze_project_embedders,
+  find_source_embedders,
+  analyze_project_struct_tags,
+  analyze_project_type_complexity,
+  analyze_project_copy_locks,
+  analyze_project_field_types,
+  analyze_project_options_structs,
+  analyze_project_recursive_structs
+} from './structs.mjs';
+import {
+  extract_literals,
+  check_format_calls,
+  analyze_project_magic_numbers
+} from './literals.mjs';
+import {
+  analyze_package_docs,
+  analyze_project_undocumented,
+  find_source_undocumented,
+  analyze_project_doc_names,
+  find_source_doc_names,
+  analyze_project_doc_coverage,
+  find_source_doc_coverage,
+  compare_doc_coverage,
+  analyze_project_doc_links,
+  find_source_doc_links
+} from './godoc.mjs';
+import { analyze_symbol_graph, render_graph_html } from './graph.mjs';
+import { analyze_project_error_wrapping } from './errors.mjs';
+import {
+  analyze_project_panics,
+  find_source_panics
+} from './panics.mjs';
+import { get_workspace_symbols } from './symbols.mjs';
+import { analyze_project_init_order } from './initorder.mjs';
+import { summarize_diff_impact } from './impact.mjs';
+import { analyze_go_scripts } from './scripts.mjs';
+import {
+  analyze_project_context_params,
+  analyze_project_interface_guideline,
+  analyze_project_error_results
+} from './conventions.mjs';
+import { analyze_project_token_costs } from './tokens.mjs';
+import { generate_stub } from './stubs.mjs';
+import {
+  analyze_project_near_misses,
+  analyze_project_method_sets,
+  analyze_project_interface_widths,
+  analyze_project_unused_interfaces,
+  analyze_project_method_implementers,
+  analyze_project_handlers_of_shape
+} from './interfaces.mjs';
+import {
+  export_search_index,
+  build_search_index,
+  format_search_index,
+  SEARCH_INDEX_FIELDS
+} from './searchindex.mjs';
+import { analyze_build_constraints } from './constraints.mjs';
+import { analyze_project_smell_scores } from './smells.mjs';
+import { diff_revisions, create_memory_source } from './changes.mjs';
+import {
+  analyze_project_conversions,
+  analyze_project_conversions_between
+} from './conversions.mjs';
+import { generate_minimal_repro } from './repro.mjs';
+import { analyze_project_concurrency_hints } from './purity.mjs';
+import { analyze_project_escapes } from './escapes.mjs';
+import { analyze_project_imports } from './imports.mjs';
+import {
+  export_api_catalog,
+  build_source_catalog,
+  format_api_catalog,
+  CATALOG_SCHEMA_VERSION
+} from './catalog.mjs';
+import { diff_api_catalogs, format_changelog } from './changelog.mjs';
+import { analyze_project_loop_captures } from './loopvars.mjs';
+import { analyze_project_coupling } from './coupling.mjs';
+import {
+  export_project_proto,
+  generate_source_proto
+} from './protobuf.mjs';
+import { analyze_project_unsafe_usage } from './unsafe.mjs';
+import { analyze_project_call_chains } from './callchains.mjs';
+import { analyze_project_inline_hints } from './inlining.mjs';
+import { analyze_project_deprecated_calls } from './deprecated.mjs';
+import {
+  analyze_project_surface_area,
+  find_source_surface_area,
+  summarize_surface_area,
+  compare_surface_area,
+  compute_surface_area,
+  DEFAULT_MAX_GROWTH
+} from './surface.mjs';
+import {
+  analyze_project_receiver_names,
+  find_source_receiver_names
+} from './receivers.mjs';
+import {
+  analyze_project_function_values,
+  find_function_values
+} from './funcvalues.mjs';
+import {
+  analyze_project_dependency_inversions,
+  find_source_dependency_inversions
+} from './inversion.mjs';
+import {
+  analyze_project_test_doubles,
+  find_source_test_doubles
+} from './doubles.mjs';
+import { analyze_project_builtin_shadowing } from './builtins.mjs';
+import {
+  analyze_project_cheatsheets,
+  build_source_cheatsheets
+} from './cheatsheet.mjs';
+import { analyze_project_state_machines } from './lifecycle.mjs';
+import { analyze_project_package_metrics } from './stability.mjs';
+import { analyze_project_param_names } from './paramnames.mjs';
+import {
+  analyze_project_symbol_ranges,
+  build_symbol_ranges
+} from './symbolranges.mjs';
+import {
+  analyze_project_state_aliasing,
+  find_state_aliasing
+} from './aliasing.mjs';
+import {
+  analyze_project_symbol_tree,
+  build_symbol_tree,
+  format_symbol_tree
+} from './symboltree.mjs';
+import {
+  analyze_project_unused_interface_methods,
+  find_unused_interface_methods
+} from './ifacemethods.mjs';
+import {
+  analyze_project_predicate_names,
+  find_predicate_names
+} from './predicates.mjs';
+import {
+  analyze_project_risk_scores,
+  find_source_risk_scores,
+  compute_risk_scores,
+  parse_git_churn
+} from './risk.mjs';
 
 // ============================================================================
 // DEAD CODE DETECTION
@@ -2433,6 +2594,10 @@ export {
   list_definitions,
   get_symbol_reference_summary,
   find_symbols_at_location,
+  // Refactoring impact
+  analyze_rename_impact,
+  compare_symbol,
+  compare_symbol_sources,
   // Class hierarchy functions
   get_class_hierarchy,
   find_implementations,
@@ -2449,6 +2614,191 @@ export {
   analyze_project_design_patterns,
   // Test analysis
   analyze_project_test_coverage,
+  // Go struct analysis
+  analyze_project_struct_sizes,
+  analyze_project_field_init,
+  // Go literal extraction
+  extract_literals,
+  // Go channel topology
+  analyze_project_channels,
+  // Go guard clauses and nesting
+  analyze_project_nesting,
+  // Go package documentation
+  analyze_package_docs,
+  // Go symbol graph
+  analyze_symbol_graph,
+  render_graph_html,
+  // Go error wrapping conventions
+  analyze_project_error_wrapping,
+  // Go panic reachability
+  analyze_project_panics,
+  find_source_panics,
+  // Go workspace symbols
+  get_workspace_symbols,
+  // Go package variable initialization order
+  analyze_project_init_order,
+  // Diff impact
+  summarize_diff_impact,
+  // Go scripts (go:build ignore)
+  analyze_go_scripts,
+  // Go methods duplicated across types
+  analyze_project_duplicate_methods,
+  // Go context parameter convention
+  analyze_project_context_params,
+  // Symbol token costs
+  analyze_project_token_costs,
+  // Go interface stubs
+  generate_stub,
+  // Go undocumented exported symbols
+  analyze_project_undocumented,
+  find_source_undocumented,
+  // Go doc comments not beginning with the declared name
+  analyze_project_doc_names,
+  find_source_doc_names,
+  // Go doc coverage compared with a baseline
+  analyze_project_doc_coverage,
+  find_source_doc_coverage,
+  compare_doc_coverage,
+  // Go struct kinds (data, service, mixed)
+  analyze_project_struct_kinds,
+  // Go interface near misses
+  analyze_project_near_misses,
+  // Search index documents (JSON lines)
+  export_search_index,
+  build_search_index,
+  format_search_index,
+  SEARCH_INDEX_FIELDS,
+  // Go effective build constraints
+  analyze_build_constraints,
+  // Go code smell scores
+  analyze_project_smell_scores,
+  // Go method shadowing through embedding
+  analyze_project_method_shadowing,
+  // Go types embedding a type
+  analyze_project_embedders,
+  find_source_embedders,
+  // Symbol level changes between revisions
+  diff_revisions,
+  create_memory_source,
+  // Go type conversions and assertions
+  analyze_project_conversions,
+  // Go conversions between two types
+  analyze_project_conversions_between,
+  // Minimal reproduction of a Go symbol
+  generate_minimal_repro,
+  // Go concurrency safety hints
+  analyze_project_concurrency_hints,
+  // Go functions returning pointers to their variables
+  analyze_project_escapes,
+  // Go import usage and unused imports
+  analyze_project_imports,
+  // Public API catalog (JSON)
+  export_api_catalog,
+  build_source_catalog,
+  format_api_catalog,
+  CATALOG_SCHEMA_VERSION,
+  // API changelog from two catalogs
+  diff_api_catalogs,
+  format_changelog,
+  // Go goroutines capturing loop variables
+  analyze_project_loop_captures,
+  // Go value and pointer method sets
+  analyze_project_method_sets,
+  // Go interface widths (narrow, regular, wide)
+  analyze_project_interface_widths,
+  // Go struct tags validated against a tag policy
+  analyze_project_struct_tags,
+  // Go function fan-in and fan-out
+  analyze_project_coupling,
+  // Proto3 schemas of serialized Go structs
+  export_project_proto,
+  generate_source_proto,
+  // Go unsafe, reflect, linkname and assembly usage
+  analyze_project_unsafe_usage,
+  // Longest Go call chain from an entry point
+  analyze_project_call_chains,
+  // Go functions likely to be inlined
+  analyze_project_inline_hints,
+  // Go interfaces never used as a type
+  analyze_project_unused_interfaces,
+  // Go types with the most complex methods
+  analyze_project_type_complexity,
+  // Go format calls checked against their arguments
+  check_format_calls,
+  // Go types that must not be copied
+  analyze_project_copy_locks,
+  // Go struct field type histogram
+  analyze_project_field_types,
+  // Types declaring a method with a given signature
+  analyze_project_method_implementers,
+  // Calls to deprecated standard library functions
+  analyze_project_deprecated_calls,
+  // Public API surface area
+  analyze_project_surface_area,
+  find_source_surface_area,
+  summarize_surface_area,
+  compare_surface_area,
+  compute_surface_area,
+  DEFAULT_MAX_GROWTH,
+  // Accept interfaces, return structs guideline
+  analyze_project_interface_guideline,
+  // Go receiver naming
+  analyze_project_receiver_names,
+  find_source_receiver_names,
+  // Go function values
+  analyze_project_function_values,
+  find_function_values,
+  // Go magic numbers
+  analyze_project_magic_numbers,
+  // Go error results returned last
+  analyze_project_error_results,
+  // Go dependency inversion
+  analyze_project_dependency_inversions,
+  find_source_dependency_inversions,
+  // Go options structs
+  analyze_project_options_structs,
+  // Go functions matching a handler shape
+  analyze_project_handlers_of_shape,
+  // Go recursive structs
+  analyze_project_recursive_structs,
+  // Go interface test doubles
+  analyze_project_test_doubles,
+  find_source_test_doubles,
+  // Go doc comment links
+  analyze_project_doc_links,
+  find_source_doc_links,
+  // Go declarations shadowing builtins
+  analyze_project_builtin_shadowing,
+  // Go package API cheat sheets
+  analyze_project_cheatsheets,
+  build_source_cheatsheets,
+  // Go lifecycle state machines
+  analyze_project_state_machines,
+  // Go package abstractness and instability
+  analyze_project_package_metrics,
+  // Go exported functions with weak parameter names
+  analyze_project_param_names,
+  // Tree-sitter compatible Go symbol ranges
+  analyze_project_symbol_ranges,
+  build_symbol_ranges,
+  // Go methods returning internal slices and maps
+  analyze_project_state_aliasing,
+  find_state_aliasing,
+  // Go directory, file and symbol trees
+  analyze_project_symbol_tree,
+  build_symbol_tree,
+  format_symbol_tree,
+  // Go interface methods never called through their interface
+  analyze_project_unused_interface_methods,
+  find_unused_interface_methods,
+  // Go bool functions without predicate names
+  analyze_project_predicate_names,
+  find_predicate_names,
+  // Go function risk scores
+  analyze_project_risk_scores,
+  find_source_risk_scores,
+  compute_risk_scores,
+  parse_git_churn,
   // Duplication helper functions (exported for testing)
   extract_tokens,
   calculate_similarity_from_tokens,
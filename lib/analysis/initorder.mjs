@@ -0,0 +1,363 @@
+'use strict';
+
+/**
+ * @fileoverview Go package initialization order module.
+ * Extracts the dependencies between package level variables, directly in
+ * their initializers or through the package functions they call, and
+ * computes the order Go initializes them in.  Initialization cycles,
+ * which are compile errors, are detected and reported.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/initorder
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_go_assignments,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Identifiers that are not selectors (`x.Name`) or keys of keyed
+ * composite literals (`Name: value`).
+ */
+const REFERENCE_PATTERN = /(?<![\w.])([A-Za-z_]\w*)(?!\s*:(?!=))/g;
+
+// ============================================================================
+// DEPENDENCIES
+// ============================================================================
+
+/**
+ * Find the references to a set of names in Go code.
+ * @param {string} text - Expression or function body
+ * @param {Set<string>} names - Names to look for
+ * @param {number} [first_line=1] - Line of the start of the text
+ * @returns {Object[]} References with name and line, first occurrence only
+ */
+const find_name_references = (text, names, first_line = 1) => {
+  const masked = mask_source(text || '');
+  const line_index = build_line_index(text || '');
+  const references = new Map();
+
+  for (const match of masked.matchAll(REFERENCE_PATTERN)) {
+    if (!names.has(match[1]) || references.has(match[1])) continue;
+    references.set(match[1], {
+      name: match[1],
+      line: first_line + line_at(line_index, match.index) - 1
+    });
+  }
+
+  return [...references.values()];
+};
+
+/**
+ * Get the names declared locally by a function: its receiver, parameters,
+ * named results and variables defined in its body.  Locals shadow package
+ * level names, wherever in the body they are declared.
+ * @param {Object} fn - Function (from the Go parser)
+ * @returns {Set<string>} Local names
+ */
+const get_local_names = (fn) => {
+  const locals = new Set();
+  if (fn.receiver && fn.receiver.name) locals.add(fn.receiver.name);
+  for (const param of [...fn.params, ...fn.results]) {
+    if (param.name) locals.add(param.name);
+  }
+  for (const assignment of find_go_assignments(fn.body)) {
+    if (assignment.kind !== 'define') continue;
+    for (const target of assignment.targets) locals.add(target.base);
+  }
+  return locals;
+};
+
+/**
+ * List the package level variables of a package in declaration order,
+ * with the initializer of each.  Files are ordered by name, as the go
+ * tool presents them to the compiler.  Specs such as `var a, b = f()`
+ * share one initializer.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object[]} Variables with name, filename, line, initializer and spec index
+ */
+const list_package_variables = (pkg) => {
+  const specs = [...pkg.vars].sort(function sort_by_position(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+
+  const variables = [];
+  specs.forEach(function add_spec(spec, index) {
+    spec.names.forEach(function add_name(name, position) {
+      if (name === '_') return;
+      let initializer = null;
+      if (spec.values.length === spec.names.length) {
+        initializer = spec.values[position];
+      } else if (spec.values.length === 1) {
+        initializer = spec.values[0];
+      }
+      variables.push({
+        name,
+        filename: spec.filename,
+        line: spec.line,
+        initializer,
+        spec: index
+      });
+    });
+  });
+
+  return variables;
+};
+
+/**
+ * Build the dependency graph of a package's variables and functions.
+ * Variables depend on the variables and functions their initializer
+ * refers to; functions on those their body refers to.  init functions
+ * cannot be referred to and methods are not followed.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object} Graph with variables, functions and edges by source name
+ */
+const build_init_graph = (pkg) => {
+  const variables = list_package_variables(pkg);
+  const functions = new Map(
+    pkg.functions
+      .filter((f) => !f.receiver && f.name !== 'init' && f.name !== '_')
+      .map((f) => [f.name, f])
+  );
+  const names = new Set([
+    ...variables.map((v) => v.name),
+    ...functions.keys()
+  ]);
+  const kind_of = (name) => (functions.has(name) ? 'func' : 'var');
+  const edges = new Map();
+
+  for (const variable of variables) {
+    const references = find_name_references(
+      variable.initializer,
+      names,
+      variable.line
+    );
+    edges.set(
+      variable.name,
+      references.map((r) => ({ ...r, kind: kind_of(r.name) }))
+    );
+  }
+
+  for (const [name, fn] of functions) {
+    const locals = get_local_names(fn);
+    const visible = new Set([...names].filter((n) => !locals.has(n)));
+    const references = find_name_references(fn.body, visible, fn.body_line);
+    edges.set(
+      name,
+      references.map((r) => ({ ...r, kind: kind_of(r.name) }))
+    );
+  }
+
+  return { variables, functions, edges };
+};
+
+/**
+ * Find the variables a variable requires to be initialized first,
+ * following references through functions.
+ * @param {Object} graph - Dependency graph (from build_init_graph)
+ * @param {string} name - Variable name
+ * @returns {string[]} Required variable names, including the variable itself when it depends on itself
+ */
+const get_required_variables = (graph, name) => {
+  const required = new Set();
+  const visited = new Set();
+  const stack = [...graph.edges.get(name)];
+
+  while (stack.length > 0) {
+    const edge = stack.pop();
+    if (edge.kind === 'var') {
+      required.add(edge.name);
+      continue;
+    }
+    if (visited.has(edge.name)) continue;
+    visited.add(edge.name);
+    stack.push(...graph.edges.get(edge.name));
+  }
+
+  return [...required];
+};
+
+// ============================================================================
+// INITIALIZATION ORDER
+// ============================================================================
+
+/**
+ * Find the initialization cycles of a package.  A cycle is a chain of
+ * references from a variable back to itself, possibly through functions;
+ * recursion among functions alone is not a cycle.
+ * @param {Object} graph - Dependency graph (from build_init_graph)
+ * @returns {Object[]} Cycles with path, location and message
+ */
+const find_init_cycles = (graph) => {
+  const cycles = [];
+  const reported = new Set();
+
+  for (const variable of graph.variables) {
+    if (reported.has(variable.name)) continue;
+
+    // Breadth first, for the shortest cycle through this variable
+    const previous = new Map();
+    const queue = [];
+    for (const edge of graph.edges.get(variable.name)) {
+      if (previous.has(edge.name)) continue;
+      previous.set(edge.name, variable.name);
+      queue.push(edge.name);
+    }
+
+    while (queue.length > 0 && !previous.has(variable.name)) {
+      const name = queue.shift();
+      for (const edge of graph.edges.get(name)) {
+        if (previous.has(edge.name)) continue;
+        previous.set(edge.name, name);
+        queue.push(edge.name);
+      }
+    }
+    if (!previous.has(variable.name)) continue;
+
+    const path = [variable.name];
+    for (let name = previous.get(variable.name); name !== variable.name; ) {
+      path.unshift(name);
+      name = previous.get(name);
+    }
+    path.unshift(variable.name);
+
+    for (const name of path) reported.add(name);
+    const steps = path
+      .slice(0, -1)
+      .map((name, i) => `${name} refers to ${path[i + 1]}`);
+    cycles.push({
+      path,
+      filename: variable.filename,
+      line: variable.line,
+      message: `Initialization cycle: ${steps.join(', ')}`
+    });
+  }
+
+  return cycles;
+};
+
+/**
+ * Compute the order Go initializes a package's variables in: repeatedly
+ * the earliest variable in declaration order whose required variables
+ * are all initialized.  Variables in or depending on a cycle are never
+ * ready and are left out.
+ * @param {Object} graph - Dependency graph (from build_init_graph)
+ * @returns {Object} Initialization order and the uninitialized variable names
+ */
+const compute_init_order = (graph) => {
+  const required = new Map(
+    graph.variables.map((v) => [v.name, get_required_variables(graph, v.name)])
+  );
+  const initialized = new Set();
+  const pending = [...graph.variables];
+  const order = [];
+
+  while (pending.length > 0) {
+    const index = pending.findIndex((v) =>
+      required.get(v.name).every((name) => initialized.has(name))
+    );
+    if (index === -1) break;
+
+    // Variables sharing an initializer are initialized together
+    const { spec } = pending[index];
+    for (const variable of pending.filter((v) => v.spec === spec)) {
+      initialized.add(variable.name);
+      order.push(variable.name);
+    }
+    for (let i = pending.length - 1; i >= 0; i--) {
+      if (pending[i].spec === spec) pending.splice(i, 1);
+    }
+  }
+
+  return { order, uninitialized: pending.map((v) => v.name), required };
+};
+
+/**
+ * Analyze the initialization order of a package's variables.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object} Variables with dependencies, edges, order and cycles
+ */
+const analyze_package_init_order = (pkg) => {
+  const graph = build_init_graph(pkg);
+  const { order, uninitialized, required } = compute_init_order(graph);
+  const declared = new Map(graph.variables.map((v, i) => [v.name, i]));
+  const position = new Map(order.map((name, i) => [name, i]));
+
+  const edges = [];
+  for (const [source, targets] of graph.edges) {
+    for (const target of targets) {
+      edges.push({
+        source,
+        source_kind: graph.functions.has(source) ? 'func' : 'var',
+        target: target.name,
+        target_kind: target.kind,
+        line: target.line
+      });
+    }
+  }
+
+  return {
+    package: pkg.name,
+    directory: pkg.directory,
+    variables: graph.variables.map(function format_variable(variable) {
+      const requires = required.get(variable.name);
+      return {
+        name: variable.name,
+        filename: variable.filename,
+        line: variable.line,
+        depends_on: graph.edges.get(variable.name),
+        requires,
+        // Depends on a variable declared after it, so Go reorders it
+        reordered: requires.some(
+          (name) => declared.get(name) > declared.get(variable.name)
+        ),
+        order: position.has(variable.name) ? position.get(variable.name) : null
+      };
+    }),
+    edges,
+    order,
+    uninitialized,
+    cycles: find_init_cycles(graph)
+  };
+};
+
+/**
+ * Analyze the package variable initialization order of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Per-package initialization order with a summary
+ */
+const analyze_project_init_order = async (project_id) => {
+  const packages = (await load_go_packages(project_id))
+    .map(analyze_package_init_order)
+    .filter((pkg) => pkg.variables.length > 0);
+  const variables = packages.flatMap((p) => p.variables);
+
+  return {
+    packages,
+    summary: {
+      packages_analyzed: packages.length,
+      total_variables: variables.length,
+      dependency_edges: packages.reduce((sum, p) => sum + p.edges.length, 0),
+      reordered_variables: variables.filter((v) => v.reordered).length,
+      uninitialized_variables: packages.reduce(
+        (sum, p) => sum + p.uninitialized.length,
+        0
+      ),
+      cycles: packages.reduce((sum, p) => sum + p.cycles.length, 0)
+    }
+  };
+};
+
+export {
+  analyze_project_init_order,
+  analyze_package_init_order,
+  build_init_graph,
+  compute_init_order,
+  find_init_cycles,
+  find_name_references,
+  list_package_variables
+};
@@ -0,0 +1,217 @@
+'use strict';
+
+/**
+ * @fileoverview Go inlining hint module.
+ * Flags the functions likely to be inlined by the Go compiler: small,
+ * non-recursive functions with a single return and none of the
+ * statements that keep the compiler from inlining, or make inlining
+ * pointless.  This loosely follows the heuristics of the gc inliner,
+ * which inlines functions whose body costs less than a budget of 80
+ * syntax tree nodes; it is a documented heuristic, not the decision of
+ * the compiler (see `go build -gcflags=-m` for that).
+ *
+ * A function is not an inlining candidate when:
+ * - cost: its estimated cost is over the budget, counting the
+ *   identifiers, literals and operators of its body
+ * - recursive: it calls itself
+ * - multiple_returns: it has more than one return statement
+ * - defer, recover, closure, loop, goroutine, select: its body has such
+ *   a statement or expression
+ * - noinline: it has a `//go:noinline` directive
+ * - no_body: it is implemented in assembly or by a linkname
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/inlining
+ */
+
+import {
+  mask_source,
+  find_matching,
+  get_base_type,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Default inlining budget, in estimated syntax tree nodes.
+ */
+const DEFAULT_INLINE_BUDGET = 80;
+
+/**
+ * Statements and expressions keeping a function from being a candidate.
+ */
+const INLINE_BLOCKERS = [
+  { reason: 'defer', pattern: /\bdefer\b/ },
+  { reason: 'recover', pattern: /\brecover\s*\(/ },
+  { reason: 'closure', pattern: /\bfunc\s*\(/ },
+  { reason: 'loop', pattern: /\bfor\b/ },
+  { reason: 'goroutine', pattern: /\bgo\s+[\w(]/ },
+  { reason: 'select', pattern: /\bselect\s*\{/ }
+];
+
+/**
+ * Tokens counted as syntax tree nodes: identifiers and numbers, masked
+ * string and rune literals, and operators.  Braces, parentheses, commas
+ * and semicolons only delimit nodes.
+ */
+const NODE_PATTERN = /[\w.]*\w|"[^"\n]*"|`[^`]*`|'[^'\n]*'|[-+*/%&|^<>=!:]+/g;
+
+// ============================================================================
+// INLINE HINTS
+// ============================================================================
+
+/**
+ * Estimate the inlining cost of a function body: the number of its
+ * identifiers, literals and operators, the nodes of its syntax tree.
+ * Selectors (`a.b.c`) count as one node.
+ * @param {string} masked - Masked function body
+ * @returns {number} Estimated cost
+ */
+const estimate_inline_cost = (masked) => {
+  return (masked.match(NODE_PATTERN) || []).length;
+};
+
+/**
+ * Blank out the bodies of the function literals of a masked body, so that
+ * their statements are not taken for those of the function.
+ * @param {string} masked - Masked function body
+ * @returns {string} Masked body without function literal bodies
+ */
+const strip_function_literals = (masked) => {
+  let result = masked;
+  const pattern = /\bfunc\s*\(/g;
+  let match;
+  while ((match = pattern.exec(result)) !== null) {
+    const params_close = find_matching(result, pattern.lastIndex - 1);
+    const open = params_close === -1 ? -1 : result.indexOf('{', params_close);
+    const close = open === -1 ? -1 : find_matching(result, open);
+    if (close === -1) continue;
+    result =
+      result.substring(0, open + 1) +
+      result.substring(open + 1, close).replace(/[^\n]/g, ' ') +
+      result.substring(close);
+  }
+  return result;
+};
+
+/**
+ * Check whether a function calls itself, directly or as a method of its
+ * receiver (`f.fact(n - 1)`).
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {string} masked - Masked function body
+ * @returns {boolean} True if the function calls itself
+ */
+const is_self_recursive = (fn, masked) => {
+  const call = fn.receiver
+    ? `(?<!\\w)${fn.receiver.name || '_'}\\s*\\.\\s*${fn.name}\\s*\\(`
+    : `(?<![\\w.])${fn.name}\\s*\\(`;
+  return new RegExp(call).test(masked);
+};
+
+/**
+ * Get the inlining hint of a function: whether it is a likely candidate
+ * for inlining, its estimated cost and the reasons it is not.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {Object} [options] - Options
+ * @param {number} [options.budget=80] - Inlining budget
+ * @returns {Object} Hint with inlinable, cost, budget and reasons
+ */
+const get_inline_hint = (fn, options = {}) => {
+  const budget = options.budget || DEFAULT_INLINE_BUDGET;
+  const reasons = [];
+
+  if (/^\/\/go:noinline\b/m.test(fn.doc || '')) reasons.push('noinline');
+  if (!fn.body) {
+    reasons.push('no_body');
+    return { inlinable: false, cost: null, budget, reasons };
+  }
+
+  // Drop the braces of the body
+  const masked = mask_source(fn.body).slice(1, -1);
+  const cost = estimate_inline_cost(masked);
+  if (cost > budget) reasons.push('cost');
+  if (is_self_recursive(fn, masked)) reasons.push('recursive');
+  const returns = strip_function_literals(masked).match(/\breturn\b/g);
+  if ((returns || []).length > 1) {
+    reasons.push('multiple_returns');
+  }
+  for (const { reason, pattern } of INLINE_BLOCKERS) {
+    if (pattern.test(masked)) reasons.push(reason);
+  }
+
+  return { inlinable: reasons.length === 0, cost, budget, reasons };
+};
+
+/**
+ * Get the inlining hints of the functions and methods of a set of
+ * packages.  Functions of test files are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options (see get_inline_hint)
+ * @param {boolean} [options.inlinable_only=false] - Only list inlining candidates
+ * @returns {Object[]} Functions with their inline hint, by file and line
+ */
+const find_inline_hints = (packages, options = {}) => {
+  const functions = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.filename.endsWith('_test.go')) continue;
+      const inline_hint = get_inline_hint(fn, options);
+      if (options.inlinable_only && !inline_hint.inlinable) continue;
+      const receiver = fn.receiver ? get_base_type(fn.receiver.type) : null;
+      functions.push({
+        function: receiver ? `${receiver}.${fn.name}` : fn.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        inline_hint
+      });
+    }
+  }
+
+  return functions.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Report the inlining hints of the functions of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_inline_hints)
+ * @returns {Promise<Object>} Functions with their hints and a summary
+ */
+const analyze_project_inline_hints = async (project_id, options = {}) => {
+  const all = find_inline_hints(await load_go_packages(project_id), {
+    ...options,
+    inlinable_only: false
+  });
+  const functions = options.inlinable_only
+    ? all.filter((fn) => fn.inline_hint.inlinable)
+    : all;
+
+  const by_reason = {};
+  for (const fn of all) {
+    for (const reason of fn.inline_hint.reasons) {
+      by_reason[reason] = (by_reason[reason] || 0) + 1;
+    }
+  }
+
+  return {
+    functions,
+    summary: {
+      total_functions: all.length,
+      inlinable: all.filter((fn) => fn.inline_hint.inlinable).length,
+      budget: options.budget || DEFAULT_INLINE_BUDGET,
+      by_reason
+    }
+  };
+};
+
+export {
+  analyze_project_inline_hints,
+  find_inline_hints,
+  get_inline_hint,
+  estimate_inline_cost,
+  strip_function_literals,
+  DEFAULT_INLINE_BUDGET
+};
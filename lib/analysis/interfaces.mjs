@@ -0,0 +1,1184 @@
+'use strict';
+
+/**
+ * @fileoverview Go interface near-miss module.
+ * Reports the types that almost implement an interface: they declare most
+ * of its methods but miss some, or declare a method with the right name
+ * and the wrong signature.  Each report lists the exact signatures still
+ * needed, the helpful "you're one method away" error the compiler only
+ * gives at the point of use.
+ *
+ * Method sets are computed separately for values and pointers: the value
+ * method set of T only has the methods with value receivers (and those
+ * promoted through embedded pointers), while the pointer method set of *T
+ * has them all.  A T with pointer receiver methods may only satisfy an
+ * interface through *T.
+ *
+ * Interfaces are also classified by width, the size of their resolved
+ * method set: single-method interfaces (io.Reader, fmt.Stringer) are the
+ * idiomatic narrow kind, and interfaces over a threshold of methods are
+ * flagged as wide, a sign of an abstraction doing too much.
+ *
+ * Unused interfaces are interface pollution: abstractions declared but
+ * never used as the type of a parameter, result, variable or field, nor
+ * embedded, used as a constraint or in an expression.  Compile-time
+ * assertions (`var _ Shape = (*Square)(nil)`) check an implementation but
+ * do not make the interface used.  Unused exported interfaces may be part
+ * of the public API of a package and are reported at a lower severity.
+ *
+ * Method implementers are found by signature rather than through an
+ * interface: the types declaring a method with a given name and
+ * signature (`String() string`), the conventions of a code base that no
+ * interface states.  Types declaring a method of that name with another
+ * signature are listed apart, as they break the convention.
+ *
+ * Handler shapes match functions and methods by signature alone, whatever
+ * their name: every `func(http.ResponseWriter, *http.Request)` or
+ * `func(context.Context) error` of a code base, to find the handlers of a
+ * middleware or pipeline framework and the routes registering them.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/interfaces
+ */
+
+import {
+  mask_source,
+  find_matching,
+  get_base_type,
+  parse_parameters,
+  parse_results,
+  load_go_packages
+} from './golang.mjs';
+import { get_import_name, get_default_import_name } from './imports.mjs';
+import {
+  get_method_key,
+  get_interface_methods,
+  get_type_methods,
+  get_interface_method_set,
+  get_value_method_set,
+  get_pointer_method_set,
+  get_imported_packages
+} from './graph.mjs';
+
+/**
+ * Largest number of missing or mismatched methods reported by default.
+ */
+const DEFAULT_MAX_MISSING = 1;
+
+/**
+ * Number of methods above which an interface is wide by default.
+ */
+const DEFAULT_MAX_INTERFACE_METHODS = 10;
+
+/**
+ * Method names of the standard library interfaces commonly embedded, by
+ * import path and name, and of the predeclared error interface.
+ */
+const STANDARD_INTERFACES = {
+  error: ['Error'],
+  'fmt.Stringer': ['String'],
+  'fmt.GoStringer': ['GoString'],
+  'io.Reader': ['Read'],
+  'io.Writer': ['Write'],
+  'io.Closer': ['Close'],
+  'io.Seeker': ['Seek'],
+  'io.ReaderAt': ['ReadAt'],
+  'io.WriterAt': ['WriteAt'],
+  'io.ReaderFrom': ['ReadFrom'],
+  'io.WriterTo': ['WriteTo'],
+  'io.ByteReader': ['ReadByte'],
+  'io.ByteWriter': ['WriteByte'],
+  'io.RuneReader': ['ReadRune'],
+  'io.StringWriter': ['WriteString'],
+  'io.ReadWriter': ['Read', 'Write'],
+  'io.ReadCloser': ['Read', 'Close'],
+  'io.WriteCloser': ['Write', 'Close'],
+  'io.ReadWriteCloser': ['Read', 'Write', 'Close'],
+  'io.ReadSeeker': ['Read', 'Seek'],
+  'io.ReadSeekCloser': ['Read', 'Seek', 'Close'],
+  'io.WriteSeeker': ['Write', 'Seek'],
+  'io.ReadWriteSeeker': ['Read', 'Write', 'Seek'],
+  'sort.Interface': ['Len', 'Less', 'Swap'],
+  'context.Context': ['Deadline', 'Done', 'Err', 'Value'],
+  'net/http.Handler': ['ServeHTTP'],
+  'encoding/json.Marshaler': ['MarshalJSON'],
+  'encoding/json.Unmarshaler': ['UnmarshalJSON'],
+  'encoding.TextMarshaler': ['MarshalText'],
+  'encoding.TextUnmarshaler': ['UnmarshalText']
+};
+
+// ============================================================================
+// SIGNATURES
+// ============================================================================
+
+/**
+ * Format a method signature as written in an interface
+ * (`Perimeter() float64`, `Read(p []byte) (n int, err error)`).
+ * @param {Object} method - Method or function (from the Go parser)
+ * @returns {string} Signature
+ */
+const format_method_signature = (method) => {
+  const format = (p) =>
+    `${p.name ? `${p.name} ` : ''}${p.variadic ? '...' : ''}${p.type}`;
+  const params = method.params.map(format).join(', ');
+
+  let results = '';
+  if (method.results.length === 1 && !method.results[0].name) {
+    results = ` ${method.results[0].type}`;
+  } else if (method.results.length > 0) {
+    results = ` (${method.results.map(format).join(', ')})`;
+  }
+  return `${method.name}(${params})${results}`;
+};
+
+/**
+ * Qualify the package types used by a method signature, so that the
+ * signature of an interface compares with that of a type from another
+ * package (`Entry` in package kv is `kv.Entry` elsewhere).
+ * @param {Object} method - Interface method
+ * @param {Object} pkg - Package of the interface
+ * @returns {Object} Method with qualified parameter and result types
+ */
+const qualify_method_types = (method, pkg) => {
+  const names = pkg.types.filter((t) => t.exported).map((t) => t.name);
+  if (names.length === 0) return method;
+  const pattern = new RegExp(`(^|[^.\\w])(${names.join('|')})\\b`, 'g');
+  const qualify = (p) => ({
+    ...p,
+    type: p.type.replace(pattern, `$1${pkg.name}.$2`)
+  });
+  return {
+    ...method,
+    params: method.params.map(qualify),
+    results: method.results.map(qualify)
+  };
+};
+
+// ============================================================================
+// NEAR MISSES
+// ============================================================================
+
+/**
+ * Compare the methods of a type with those of an interface.
+ * A method with the interface's name and different parameter or result
+ * types is reported as mismatched rather than missing.
+ * @param {Object} type - Concrete type declaration
+ * @param {Object} type_pkg - Package of the type
+ * @param {Object} iface - Interface type declaration
+ * @param {Object} iface_pkg - Package of the interface
+ * @returns {Object|null} Report with present, missing and mismatched methods, or null if the interface embeds one from another package
+ */
+const check_near_miss = (type, type_pkg, iface, iface_pkg) => {
+  const wanted = get_interface_methods(iface, iface_pkg);
+  if (!wanted) return null;
+  const methods = get_type_methods(type.name, type_pkg);
+  const value_methods = get_type_methods(type.name, type_pkg, new Set(), false);
+  const foreign = type_pkg.directory !== iface_pkg.directory;
+
+  const present = [];
+  const missing = [];
+  const mismatched = [];
+  const pointer_methods = [];
+
+  for (const [name, method] of wanted) {
+    const expected = foreign
+      ? qualify_method_types(method, iface_pkg)
+      : method;
+    const actual = methods.get(name);
+
+    if (!actual) {
+      missing.push({ name, signature: format_method_signature(expected) });
+    } else if (get_method_key(actual) !== get_method_key(expected)) {
+      mismatched.push({
+        name,
+        expected: format_method_signature(expected),
+        actual: format_method_signature(actual),
+        filename: actual.filename,
+        line: actual.line
+      });
+    } else {
+      present.push(format_method_signature(actual));
+      if (!value_methods.has(name)) pointer_methods.push(name);
+    }
+  }
+
+  const lacking = missing.length + mismatched.length;
+  const report = {
+    type: type.name,
+    type_package: type_pkg.directory,
+    interface: iface.name,
+    interface_package: iface_pkg.directory,
+    filename: type.filename,
+    line: type.line,
+    satisfies: lacking === 0,
+    // A method with a pointer receiver is only in the method set of *T,
+    // unless it is promoted through an embedded pointer
+    value_satisfies: lacking === 0 && pointer_methods.length === 0,
+    present,
+    missing,
+    mismatched,
+    pointer_methods
+  };
+
+  if (lacking > 0) {
+    const needs = [
+      ...missing.map((m) => `missing ${m.signature}`),
+      ...mismatched.map((m) => `have ${m.actual}, want ${m.expected}`)
+    ];
+    report.message = `${type.name} is ${lacking} method${lacking === 1 ? '' : 's'} away from implementing ${iface.name}: ${needs.join('; ')}`;
+  }
+  return report;
+};
+
+/**
+ * Find a type or interface of a set of packages by name, optionally
+ * qualified by package directory (`geo.Shape`) or name.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} name - Type name
+ * @param {Function} accept - Predicate on the type declaration
+ * @returns {Object[]} Matches with type and pkg
+ */
+const find_named_type = (packages, name, accept) => {
+  const dot = name.lastIndexOf('.');
+  const qualifier = dot === -1 ? null : name.substring(0, dot);
+  const base = get_base_type(name.substring(dot + 1));
+
+  return packages
+    .filter(
+      (pkg) =>
+        !qualifier || pkg.name === qualifier || pkg.directory === qualifier
+    )
+    .flatMap((pkg) =>
+      pkg.types
+        .filter((t) => t.name === base && accept(t))
+        .map((type) => ({ type, pkg }))
+    );
+};
+
+/**
+ * Find the one type or interface of a set of packages with a name.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} name - Type name, optionally qualified
+ * @param {string} label - Kind of type, for errors
+ * @param {Function} accept - Predicate on the type declaration
+ * @returns {Object} Match with type and pkg
+ * @throws {Error} If the type is not found or is ambiguous
+ */
+const find_single_type = (packages, name, label, accept) => {
+  const found = find_named_type(packages, name, accept);
+  if (found.length === 0) {
+    throw new Error(`${label} '${name}' not found in project`);
+  }
+  if (found.length > 1) {
+    throw new Error(
+      `${label} '${name}' is ambiguous (found in: ${found.map((f) => f.pkg.directory).join(', ')})`
+    );
+  }
+  return found[0];
+};
+
+/**
+ * Report how close a type is to implementing an interface.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} type_name - Type name, optionally qualified
+ * @param {string} interface_name - Interface name, optionally qualified
+ * @returns {Object} Near-miss report (see check_near_miss)
+ * @throws {Error} If the type or the interface is not found or is ambiguous
+ */
+const find_near_miss = (packages, type_name, interface_name) => {
+  const lookup = (name, label, accept) =>
+    find_single_type(packages, name, label, accept);
+
+  const type = lookup(type_name, 'Type', (t) => t.kind !== 'interface');
+  const iface = lookup(
+    interface_name,
+    'Interface',
+    (t) => t.kind === 'interface'
+  );
+  const report = check_near_miss(type.type, type.pkg, iface.type, iface.pkg);
+  if (!report) {
+    throw new Error(
+      `Interface '${interface_name}' embeds an interface from another package`
+    );
+  }
+  return report;
+};
+
+/**
+ * Find the types of a set of packages that are within a few methods of
+ * implementing an interface.  A type must already have at least as many
+ * of the interface's methods as it lacks, so that types sharing a single
+ * common method (such as String) with a large interface are not reported.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {number} [options.max_missing=1] - Largest number of missing or mismatched methods
+ * @returns {Object[]} Near-miss reports, closest first
+ */
+const find_near_misses = (packages, options = {}) => {
+  const max_missing = options.max_missing || DEFAULT_MAX_MISSING;
+  const interfaces = packages.flatMap((pkg) =>
+    pkg.types
+      .filter((t) => t.kind === 'interface')
+      .map((type) => ({ type, pkg }))
+  );
+
+  const reports = [];
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind === 'interface') continue;
+      if (!pkg.methods[type.name] && type.kind !== 'struct') continue;
+
+      for (const iface of interfaces) {
+        const report = check_near_miss(type, pkg, iface.type, iface.pkg);
+        if (!report || report.satisfies) continue;
+        const lacking = report.missing.length + report.mismatched.length;
+        if (lacking > max_missing || report.present.length < lacking) continue;
+        reports.push(report);
+      }
+    }
+  }
+
+  return reports.sort(function sort_by_closeness(a, b) {
+    const lacking = (r) => r.missing.length + r.mismatched.length;
+    return (
+      lacking(a) - lacking(b) ||
+      a.type.localeCompare(b.type) ||
+      a.interface.localeCompare(b.interface)
+    );
+  });
+};
+
+/**
+ * Summarize near-miss reports.
+ * @param {Object[]} reports - Near-miss reports
+ * @param {number} max_missing - Largest number of missing or mismatched methods
+ * @returns {Object} Reports with a summary
+ */
+const summarize_near_misses = (reports, max_missing) => {
+  return {
+    near_misses: reports,
+    summary: {
+      max_missing,
+      total_near_misses: reports.length,
+      types: new Set(reports.map((r) => `${r.type_package}.${r.type}`)).size,
+      interfaces: new Set(
+        reports.map((r) => `${r.interface_package}.${r.interface}`)
+      ).size,
+      with_mismatched_signatures: reports.filter(
+        (r) => r.mismatched.length > 0
+      ).length
+    }
+  };
+};
+
+/**
+ * Report interface near misses across a project: for one type and
+ * interface when both are given, otherwise for every pair within
+ * max_missing methods, optionally restricted to one type or interface.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {string} [options.type] - Type name, optionally qualified
+ * @param {string} [options.interface] - Interface name, optionally qualified
+ * @param {number} [options.max_missing=1] - Largest number of missing or mismatched methods
+ * @returns {Promise<Object>} Near-miss report, or reports with a summary
+ * @throws {Error} If a named type or interface is not found or is ambiguous
+ */
+const analyze_project_near_misses = async (project_id, options = {}) => {
+  const packages = await load_go_packages(project_id);
+  if (options.type && options.interface) {
+    return find_near_miss(packages, options.type, options.interface);
+  }
+
+  let reports = find_near_misses(packages, options);
+  for (const [side, label] of [
+    ['type', 'Type'],
+    ['interface', 'Interface']
+  ]) {
+    if (!options[side]) continue;
+    const found = find_named_type(packages, options[side], () => true);
+    if (found.length === 0) {
+      throw new Error(`${label} '${options[side]}' not found in project`);
+    }
+    reports = reports.filter((r) =>
+      found.some(
+        (f) =>
+          r[side] === f.type.name && r[`${side}_package`] === f.pkg.directory
+      )
+    );
+  }
+
+  return summarize_near_misses(
+    reports,
+    options.max_missing || DEFAULT_MAX_MISSING
+  );
+};
+
+// ============================================================================
+// METHOD SETS
+// ============================================================================
+
+/**
+ * Report the value and pointer method sets of a type, and the interfaces
+ * of the packages satisfied by T or only by *T.  Interfaces of other
+ * packages are compared with their types qualified, as by
+ * check_near_miss; empty interfaces and interfaces embedding one from
+ * another package are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} type_name - Type name, optionally qualified
+ * @returns {Object} Method sets, pointer only methods and satisfied interfaces
+ * @throws {Error} If the type is not found or is ambiguous
+ */
+const find_method_sets = (packages, type_name) => {
+  const { type, pkg } = find_single_type(
+    packages,
+    type_name,
+    'Type',
+    (t) => t.kind !== 'interface'
+  );
+  const value_methods = get_type_methods(type.name, pkg, new Set(), false);
+  const pointer_methods = get_type_methods(type.name, pkg);
+  const signatures = (methods) =>
+    [...methods.values()].map(format_method_signature).sort();
+  const value_keys = get_value_method_set(type.name, pkg);
+  const pointer_keys = get_pointer_method_set(type.name, pkg);
+
+  const interfaces = [];
+  for (const iface_pkg of packages) {
+    for (const iface of iface_pkg.types) {
+      if (iface.kind !== 'interface') continue;
+      let keys = null;
+      if (iface_pkg.directory === pkg.directory) {
+        keys = get_interface_method_set(iface, iface_pkg);
+      } else {
+        const methods = get_interface_methods(iface, iface_pkg);
+        keys =
+          methods &&
+          new Set(
+            [...methods.values()].map((m) =>
+              get_method_key(qualify_method_types(m, iface_pkg))
+            )
+          );
+      }
+      if (!keys || keys.size === 0) continue;
+      const satisfied_by = (set) => [...keys].every((key) => set.has(key));
+      if (!satisfied_by(pointer_keys)) continue;
+
+      interfaces.push({
+        interface: iface.name,
+        package: iface_pkg.name,
+        directory: iface_pkg.directory,
+        value: satisfied_by(value_keys),
+        pointer: true
+      });
+    }
+  }
+
+  return {
+    type: type.name,
+    package: pkg.name,
+    directory: pkg.directory,
+    filename: type.filename,
+    line: type.line,
+    value_method_set: signatures(value_methods),
+    pointer_method_set: signatures(pointer_methods),
+    pointer_only: [...pointer_methods.keys()]
+      .filter((name) => !value_methods.has(name))
+      .sort(),
+    interfaces: interfaces.sort(function sort_by_interface(a, b) {
+      return (
+        a.directory.localeCompare(b.directory) ||
+        a.interface.localeCompare(b.interface)
+      );
+    })
+  };
+};
+
+/**
+ * Report the value and pointer method sets of a project type.
+ * @param {number} project_id - The project ID to analyze
+ * @param {string} type_name - Type name, optionally qualified
+ * @returns {Promise<Object>} Method sets (see find_method_sets)
+ * @throws {Error} If the type is not found or is ambiguous
+ */
+const analyze_project_method_sets = async (project_id, type_name) => {
+  return find_method_sets(await load_go_packages(project_id), type_name);
+};
+
+// ============================================================================
+// INTERFACE WIDTH
+// ============================================================================
+
+/**
+ * Check whether an embedded element of an interface is part of a type set
+ * (`~int | ~float64`, `comparable`, `int`) rather than an interface, which
+ * makes the interface usable as a type constraint only.
+ * @param {string} element - Embedded element
+ * @returns {boolean} True for type set elements
+ */
+const is_type_set_element = (element) => {
+  return (
+    /[~|]/.test(element) ||
+    element === 'comparable' ||
+    /^(bool|string|byte|rune|u?int(8|16|32|64)?|uintptr|float(32|64)|complex(64|128))$/.test(
+      element
+    )
+  );
+};
+
+/**
+ * Resolve the method set of an interface, following embedded interfaces
+ * of the project (in its package or in packages imported by its file)
+ * and the common interfaces of the standard library.
+ * @param {Object} type - Interface type
+ * @param {Object} pkg - Package containing the interface
+ * @param {Object[]} packages - All project packages
+ * @param {Set<string>} [seen] - Interfaces already expanded
+ * @returns {Object} Method names, unresolved embeds and type set elements
+ */
+const resolve_interface_method_names = (type, pkg, packages, seen = new Set()) => {
+  const methods = new Set(type.methods.map((m) => m.name));
+  const unresolved = [];
+  const type_set = [];
+  seen.add(`${pkg.directory}:${type.name}`);
+
+  for (const embed of type.embeds) {
+    const element = embed.type.trim();
+    if (is_type_set_element(element)) {
+      type_set.push(element);
+      continue;
+    }
+
+    const dot = element.indexOf('.');
+    let target_pkg = pkg;
+    let name = element;
+    let standard = STANDARD_INTERFACES[element] ? element : null;
+    if (dot !== -1) {
+      const qualifier = element.substring(0, dot);
+      name = element.substring(dot + 1);
+      target_pkg = get_imported_packages(pkg, type.filename, packages).get(
+        qualifier
+      );
+      const imp = pkg.imports.find(
+        (i) =>
+          i.filename === type.filename && get_import_name(i).name === qualifier
+      );
+      standard = imp ? `${imp.path}.${name}` : null;
+    }
+
+    const embedded =
+      target_pkg &&
+      target_pkg.types.find((t) => t.name === name && t.kind === 'interface');
+    if (embedded) {
+      if (seen.has(`${target_pkg.directory}:${embedded.name}`)) continue;
+      const inner = resolve_interface_method_names(
+        embedded,
+        target_pkg,
+        packages,
+        seen
+      );
+      for (const method of inner.methods) methods.add(method);
+      unresolved.push(...inner.unresolved);
+      type_set.push(...inner.type_set);
+    } else if (standard && STANDARD_INTERFACES[standard]) {
+      for (const method of STANDARD_INTERFACES[standard]) methods.add(method);
+    } else {
+      unresolved.push(element);
+    }
+  }
+
+  return { methods, unresolved, type_set };
+};
+
+/**
+ * Classify the interfaces of a set of packages by width.  An interface is
+ * narrow with exactly one method, wide with more than max_methods, empty
+ * without methods (`interface{}`, `any`) and a constraint when it has a
+ * type set (`~int | ~float64`).  Method counts include embedded
+ * interfaces; when an embedded interface cannot be resolved the count is
+ * a lower bound and complete is false, and such an interface is not
+ * classified as narrow or empty.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {number} [options.max_methods=10] - Largest number of methods of an interface that is not wide
+ * @returns {Object[]} Interfaces with their method count and classification
+ */
+const find_interface_widths = (packages, options = {}) => {
+  const max_methods = options.max_methods || DEFAULT_MAX_INTERFACE_METHODS;
+  const interfaces = [];
+
+  for (const pkg of packages) {
+    for (const type of pkg.types.filter((t) => t.kind === 'interface')) {
+      if (type.filename.endsWith('_test.go')) continue;
+      const { methods, unresolved, type_set } = resolve_interface_method_names(
+        type,
+        pkg,
+        packages
+      );
+      const count = methods.size;
+      const complete = unresolved.length === 0;
+
+      let classification = 'regular';
+      if (type_set.length > 0) classification = 'constraint';
+      else if (count > max_methods) classification = 'wide';
+      else if (complete && count === 0) classification = 'empty';
+      else if (complete && count === 1) classification = 'narrow';
+
+      const info = {
+        name: type.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: type.filename,
+        line: type.line,
+        exported: type.exported,
+        method_count: count,
+        methods: [...methods].sort(),
+        declared_methods: type.methods.length,
+        embeds: type.embeds.map((e) => e.type),
+        unresolved_embeds: unresolved,
+        complete,
+        classification
+      };
+      if (classification === 'wide') {
+        info.message = `${type.name} has ${count} methods (more than ${max_methods}); consider splitting it into smaller interfaces that callers can depend on separately`;
+      }
+      interfaces.push(info);
+    }
+  }
+
+  return interfaces.sort(function sort_by_width(a, b) {
+    return (
+      b.method_count - a.method_count ||
+      a.directory.localeCompare(b.directory) ||
+      a.name.localeCompare(b.name)
+    );
+  });
+};
+
+/**
+ * Report the width of the interfaces of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_interface_widths)
+ * @returns {Promise<Object>} Interfaces with a summary per classification
+ */
+const analyze_project_interface_widths = async (project_id, options = {}) => {
+  const interfaces = find_interface_widths(
+    await load_go_packages(project_id),
+    options
+  );
+  const count = (classification) =>
+    interfaces.filter((i) => i.classification === classification).length;
+
+  return {
+    interfaces,
+    summary: {
+      total_interfaces: interfaces.length,
+      max_methods: options.max_methods || DEFAULT_MAX_INTERFACE_METHODS,
+      narrow: count('narrow'),
+      regular: count('regular'),
+      wide: count('wide'),
+      empty: count('empty'),
+      constraint: count('constraint'),
+      incomplete: interfaces.filter((i) => !i.complete).length,
+      average_methods:
+        interfaces.length > 0
+          ? Math.round(
+              (interfaces.reduce((sum, i) => sum + i.method_count, 0) /
+                interfaces.length) *
+                10
+            ) / 10
+          : 0
+    }
+  };
+};
+
+// ============================================================================
+// UNUSED INTERFACES
+// ============================================================================
+
+/**
+ * Collect the type expressions of the declarations of a package, the
+ * places an interface can be used: parameters and results, struct fields,
+ * variables, interface methods and embeds, type parameter constraints,
+ * defined types, and the masked bodies of functions.  Each use is
+ * labelled with the type declaring it, so that an interface referring to
+ * itself is not taken as used.  Declarations of test files are left out.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object[]} Type expressions with kind, text, owner and filename
+ */
+const collect_type_expressions = (pkg) => {
+  const expressions = [];
+  const add = (kind, text, owner, filename) => {
+    if (text) expressions.push({ kind, text, owner, filename });
+  };
+
+  for (const fn of pkg.functions) {
+    if (fn.filename.endsWith('_test.go')) continue;
+    for (const param of fn.params) {
+      add('parameter', param.type, null, fn.filename);
+    }
+    for (const result of fn.results) {
+      add('result', result.type, null, fn.filename);
+    }
+    add('constraint', fn.type_params, null, fn.filename);
+    if (fn.body) add('expression', mask_source(fn.body), null, fn.filename);
+  }
+
+  for (const v of pkg.vars) {
+    if (v.filename.endsWith('_test.go')) continue;
+    const blank = v.names.every((name) => name === '_');
+    add(blank ? 'assertion' : 'variable', v.type, null, v.filename);
+    for (const value of v.values) {
+      add(blank ? 'assertion' : 'expression', value, null, v.filename);
+    }
+  }
+
+  for (const type of pkg.types) {
+    if (type.filename.endsWith('_test.go')) continue;
+    add('constraint', type.type_params, type.name, type.filename);
+    if (type.kind === 'struct') {
+      for (const field of type.fields) {
+        add('field', field.type, type.name, type.filename);
+      }
+    } else if (type.kind === 'interface') {
+      for (const method of type.methods) {
+        add('method', method.params_text, type.name, type.filename);
+        add('method', method.results_text, type.name, type.filename);
+      }
+      for (const embed of type.embeds) {
+        add('embed', embed.type, type.name, type.filename);
+      }
+    } else {
+      add('type', type.underlying, type.name, type.filename);
+    }
+  }
+
+  return expressions;
+};
+
+/**
+ * Find the uses of the interfaces of a set of packages.  An interface is
+ * used by name in its own package (`Shape`) and through the name its
+ * package is imported by in other packages (`geo.Shape`).  Matching is by
+ * name, so a variable named like an unexported interface is taken for a
+ * use: interfaces may be missed, but not reported unused by mistake.
+ * Interfaces of test files are left out, and uses in test files do not
+ * count.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Interfaces with their uses by kind and assertions
+ */
+const find_interface_uses = (packages) => {
+  const expressions = new Map(
+    packages.map((pkg) => [pkg, collect_type_expressions(pkg)])
+  );
+  const interfaces = [];
+
+  for (const pkg of packages) {
+    for (const type of pkg.types.filter((t) => t.kind === 'interface')) {
+      if (type.filename.endsWith('_test.go')) continue;
+      const uses = {};
+      let assertions = 0;
+
+      for (const [other, list] of expressions) {
+        // Patterns by filename, for the names the package is imported by
+        const patterns = new Map();
+        const get_pattern = (filename) => {
+          if (patterns.has(filename)) return patterns.get(filename);
+          let pattern = null;
+          if (other === pkg) {
+            pattern = new RegExp(`(?<![\\w.])${type.name}(?!\\w)`);
+          } else {
+            const qualifiers = [
+              ...get_imported_packages(other, filename, packages)
+            ]
+              .filter(([, target]) => target === pkg)
+              .map(([qualifier]) => qualifier);
+            if (qualifiers.length > 0) {
+              pattern = new RegExp(
+                `(?<![\\w.])(?:${qualifiers.join('|')})\\s*\\.\\s*${type.name}(?!\\w)`
+              );
+            }
+          }
+          patterns.set(filename, pattern);
+          return pattern;
+        };
+
+        for (const expression of list) {
+          if (other === pkg && expression.owner === type.name) continue;
+          const pattern = get_pattern(expression.filename);
+          if (!pattern || !pattern.test(expression.text)) continue;
+          if (expression.kind === 'assertion') assertions++;
+          else uses[expression.kind] = (uses[expression.kind] || 0) + 1;
+        }
+      }
+
+      interfaces.push({
+        name: type.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: type.filename,
+        line: type.line,
+        exported: type.exported,
+        uses,
+        assertions,
+        used: Object.keys(uses).length > 0
+      });
+    }
+  }
+
+  return interfaces;
+};
+
+/**
+ * Find the unused interfaces of a set of packages (see find_interface_uses).
+ * Unused unexported interfaces are dead code and reported as warnings;
+ * unused exported interfaces may be used by other modules, as part of the
+ * public API of their package, and are reported as info.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.unexported_only=false] - Only report unexported interfaces
+ * @returns {Object[]} Unused interfaces with severity and message, by file and line
+ */
+const find_unused_interfaces = (packages, options = {}) => {
+  return find_interface_uses(packages)
+    .filter((iface) => !iface.used)
+    .filter((iface) => !(options.unexported_only && iface.exported))
+    .map((iface) => ({
+      name: iface.name,
+      package: iface.package,
+      directory: iface.directory,
+      filename: iface.filename,
+      line: iface.line,
+      exported: iface.exported,
+      assertions: iface.assertions,
+      severity: iface.exported ? 'info' : 'warning',
+      message: iface.exported
+        ? `${iface.name} is never used in the project; remove it unless it is part of the public API of ${iface.package}`
+        : `${iface.name} is never used; remove it or use it where its methods are needed`
+    }))
+    .sort(function sort_by_location(a, b) {
+      if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+      return a.line - b.line;
+    });
+};
+
+/**
+ * Report the unused interfaces of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_unused_interfaces)
+ * @returns {Promise<Object>} Unused interfaces and a summary
+ */
+const analyze_project_unused_interfaces = async (project_id, options = {}) => {
+  const packages = await load_go_packages(project_id);
+  const total = find_interface_uses(packages).length;
+  const interfaces = find_unused_interfaces(packages, options);
+
+  return {
+    interfaces,
+    summary: {
+      total_interfaces: total,
+      unused: interfaces.length,
+      unused_exported: interfaces.filter((i) => i.exported).length,
+      unused_unexported: interfaces.filter((i) => !i.exported).length,
+      assertion_only: interfaces.filter((i) => i.assertions > 0).length
+    }
+  };
+};
+
+// ============================================================================
+// METHOD IMPLEMENTERS
+// ============================================================================
+
+/**
+ * Parse a method signature given without its method name (`() string`,
+ * `(p []byte) (int, error)`); a leading method name is accepted too.
+ * @param {string} name - Method name
+ * @param {string} signature - Signature
+ * @returns {Object} Method with name, params and results
+ * @throws {Error} If the signature has no parameter list
+ */
+const parse_method_signature = (name, signature) => {
+  let text = (signature || '').trim();
+  if (text.startsWith(name)) text = text.substring(name.length).trim();
+  const close = text.startsWith('(') ? find_matching(mask_source(text), 0) : -1;
+  if (close === -1) {
+    throw new Error(`Invalid method signature '${signature}'`);
+  }
+
+  return {
+    name,
+    params: parse_parameters(text.substring(1, close)),
+    results: parse_results(text.substring(close + 1))
+  };
+};
+
+/**
+ * Find the types declaring a method with a name and signature, whatever
+ * the interfaces they implement.  Signatures match on their parameter and
+ * result types, not their names; types declaring the method with another
+ * signature are reported as mismatches.  Methods of test files are left
+ * out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} name - Method name
+ * @param {string} signature - Method signature (see parse_method_signature)
+ * @returns {Object} Method signature, implementers and mismatches
+ * @throws {Error} If the signature is invalid
+ */
+const find_method_implementers = (packages, name, signature) => {
+  const normalize = (method) => get_method_key(method).replace(/\s+/g, '');
+  const method = parse_method_signature(name, signature);
+  const key = normalize(method);
+  const implementers = [];
+  const mismatches = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (!fn.receiver || fn.name !== name) continue;
+      if (fn.filename.endsWith('_test.go')) continue;
+      const implementer = {
+        type: get_base_type(fn.receiver.type),
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        pointer_receiver: fn.receiver.pointer,
+        signature: format_method_signature(fn)
+      };
+      if (normalize(fn) === key) {
+        implementers.push(implementer);
+      } else {
+        mismatches.push(implementer);
+      }
+    }
+  }
+
+  const sort_by_location = (a, b) => {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  };
+  return {
+    method: format_method_signature(method),
+    implementers: implementers.sort(sort_by_location),
+    mismatches: mismatches.sort(sort_by_location)
+  };
+};
+
+/**
+ * Report the types of a project declaring a method with a signature.
+ * @param {number} project_id - The project ID to analyze
+ * @param {string} name - Method name
+ * @param {string} signature - Method signature (see parse_method_signature)
+ * @returns {Promise<Object>} Implementers, mismatches and a summary
+ * @throws {Error} If the signature is invalid
+ */
+const analyze_project_method_implementers = async (
+  project_id,
+  name,
+  signature
+) => {
+  const result = find_method_implementers(
+    await load_go_packages(project_id),
+    name,
+    signature
+  );
+
+  return {
+    ...result,
+    summary: {
+      implementers: result.implementers.length,
+      packages: new Set(result.implementers.map((i) => i.directory)).size,
+      pointer_receivers: result.implementers.filter(
+        (i) => i.pointer_receiver
+      ).length,
+      mismatches: result.mismatches.length
+    }
+  };
+};
+
+// ============================================================================
+// HANDLER SHAPES
+// ============================================================================
+
+/**
+ * Parse a handler shape: a function signature without a name, with or
+ * without the func keyword (`func(context.Context) error`,
+ * `(w http.ResponseWriter, r *http.Request)`).  Parameter names are
+ * ignored; a type `_` matches any type.
+ * @param {string} shape - Handler shape
+ * @returns {Object} Shape with params and results
+ * @throws {Error} If the shape has no parameter list
+ */
+const parse_handler_shape = (shape) => {
+  const text = (shape || '').trim().replace(/^func\b\s*/, '');
+  let parsed = null;
+  try {
+    parsed = parse_method_signature('', text);
+  } catch {
+    // Reported below
+  }
+  if (!parsed || [...parsed.params, ...parsed.results].some((p) => !p.type)) {
+    throw new Error(`Invalid handler shape '${shape}'`);
+  }
+  return parsed;
+};
+
+/**
+ * Normalize a type for shape matching: whitespace is removed and
+ * `interface{}` is `any`.
+ * @param {string} type_text - Type
+ * @returns {string} Normalized type
+ */
+const normalize_shape_type = (type_text) => {
+  return type_text.replace(/\s+/g, '').replace(/\binterface\{\}/g, 'any');
+};
+
+/**
+ * Rename the package qualifiers of renamed imports in the types of a
+ * function to the default names of their packages (`ctx.Context` for an
+ * `import ctx "context"` is `context.Context`), to compare with a shape.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {Object} pkg - Package of the function
+ * @returns {Object[]} Parameter and result types, normalized
+ */
+const get_shape_types = (fn, pkg) => {
+  const renames = new Map();
+  for (const imp of pkg.imports) {
+    if (imp.filename !== fn.filename || !imp.name) continue;
+    if (imp.name === '_' || imp.name === '.') continue;
+    renames.set(imp.name, get_default_import_name(imp.path));
+  }
+  const normalize = (p) => ({
+    variadic: p.variadic,
+    type: normalize_shape_type(p.type).replace(
+      /(?<![\w.])([A-Za-z_]\w*)\./g,
+      (match, name) => (renames.has(name) ? `${renames.get(name)}.` : match)
+    )
+  });
+  return {
+    params: fn.params.map(normalize),
+    results: fn.results.map(normalize)
+  };
+};
+
+/**
+ * Check whether the parameter or result types of a function match those
+ * of a shape, one by one.
+ * @param {Object[]} types - Types of the function (from get_shape_types)
+ * @param {Object[]} shape_types - Types of the shape
+ * @returns {boolean} True if they match
+ */
+const match_shape_types = (types, shape_types) => {
+  return (
+    types.length === shape_types.length &&
+    shape_types.every((expected, i) => {
+      const type = normalize_shape_type(expected.type);
+      return (
+        types[i].variadic === expected.variadic &&
+        (type === '_' || types[i].type === type)
+      );
+    })
+  );
+};
+
+/**
+ * Find the functions and methods of a set of packages matching a handler
+ * shape.  Types match as written, once renamed imports are resolved, so
+ * a package referring to its own types unqualified matches a shape
+ * qualifying them with the package name too.  Test files are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} shape - Handler shape (see parse_handler_shape)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.functions_only=false] - Leave out methods
+ * @returns {Object} Shape and the matching functions, by file and line
+ * @throws {Error} If the shape is invalid
+ */
+const find_handlers_of_shape = (packages, shape, options = {}) => {
+  const parsed = parse_handler_shape(shape);
+  const handlers = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.filename.endsWith('_test.go')) continue;
+      if (fn.receiver && options.functions_only) continue;
+      const matches = [fn, qualify_method_types(fn, pkg)].some((candidate) => {
+        const types = get_shape_types(candidate, pkg);
+        return (
+          match_shape_types(types.params, parsed.params) &&
+          match_shape_types(types.results, parsed.results)
+        );
+      });
+      if (!matches) continue;
+      handlers.push({
+        symbol: fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name,
+        kind: fn.receiver ? 'method' : 'function',
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        exported: fn.exported,
+        signature: `func ${format_method_signature(fn)}`
+      });
+    }
+  }
+
+  return {
+    shape: `func${format_method_signature(parsed)}`,
+    handlers: handlers.sort(function sort_by_location(a, b) {
+      if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+      return a.line - b.line;
+    })
+  };
+};
+
+/**
+ * Report the functions and methods of a project matching a handler shape.
+ * @param {number} project_id - The project ID to analyze
+ * @param {string} shape - Handler shape (see parse_handler_shape)
+ * @param {Object} [options] - Options (see find_handlers_of_shape)
+ * @returns {Promise<Object>} Shape, handlers and a summary
+ * @throws {Error} If the shape is invalid
+ */
+const analyze_project_handlers_of_shape = async (
+  project_id,
+  shape,
+  options = {}
+) => {
+  const result = find_handlers_of_shape(
+    await load_go_packages(project_id),
+    shape,
+    options
+  );
+
+  return {
+    ...result,
+    summary: {
+      handlers: result.handlers.length,
+      functions: result.handlers.filter((h) => h.kind === 'function').length,
+      methods: result.handlers.filter((h) => h.kind === 'method').length,
+      packages: new Set(result.handlers.map((h) => h.directory)).size
+    }
+  };
+};
+
+export {
+  analyze_project_handlers_of_shape,
+  find_handlers_of_shape,
+  parse_handler_shape,
+  analyze_project_near_misses,
+  analyze_project_method_sets,
+  find_method_sets,
+  analyze_project_interface_widths,
+  find_interface_widths,
+  analyze_project_unused_interfaces,
+  find_unused_interfaces,
+  find_interface_uses,
+  analyze_project_method_implementers,
+  find_method_implementers,
+  parse_method_signature,
+  find_near_miss,
+  find_near_misses,
+  check_near_miss,
+  format_method_signature,
+  qualify_method_types,
+  DEFAULT_MAX_MISSING,
+  DEFAULT_MAX_INTERFACE_METHODS,
+  STANDARD_INTERFACES
+};
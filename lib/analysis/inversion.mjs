@@ -0,0 +1,324 @@
+'use strict';
+
+/**
+ * @fileoverview Go dependency inversion module.
+ * Finds where higher-level packages depend directly on the concrete types
+ * of lower-level packages when an interface of the project would do: a
+ * parameter or struct field of type `*store.Store` only used through
+ * methods that an interface such as `service.Repository` declares.
+ * Depending on the interface instead inverts the dependency, so that the
+ * lower-level package can be replaced or faked in tests.  This supports
+ * architecture reviews; it is a heuristic, scoped to the project.
+ *
+ * Packages are layered by their project imports: a package importing no
+ * project package is at level 0, and any other one level above the
+ * highest package it imports.  Every cross-package reference goes from a
+ * higher level to a lower one, except within import cycles.
+ *
+ * A dependency is reported when the concrete type (a struct, or a named
+ * type with methods) satisfies an interface of the project declaring
+ * every method used:
+ * - parameters: the methods called on the parameter in the function
+ *   body; parameters whose fields are read, or which are passed on or
+ *   compared, need the concrete type and are left out
+ * - struct fields: the methods called on the field (`s.store.Get()`) in
+ *   the functions of the package; fields whose own fields are read are
+ *   left out
+ * Interfaces declared by the depending package come first, as Go idiom
+ * defines interfaces where they are used, then the smallest.  Results are
+ * left out: returning concrete types is the Go convention.  Test files
+ * are left out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/inversion
+ */
+
+import {
+  mask_source,
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { get_imported_packages, get_type_methods } from './graph.mjs';
+import {
+  resolve_project_type,
+  find_param_method_uses,
+  find_covering_interfaces
+} from './conventions.mjs';
+import { strip_function_literals } from './inlining.mjs';
+
+// ============================================================================
+// PACKAGE LEVELS
+// ============================================================================
+
+/**
+ * Get the project packages imported by a package, by any of its files.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object[]} packages - All project packages
+ * @returns {Object[]} Imported packages
+ */
+const get_package_dependencies = (pkg, packages) => {
+  const dependencies = new Set();
+  for (const filename of new Set(pkg.imports.map((imp) => imp.filename))) {
+    for (const other of get_imported_packages(pkg, filename, packages).values()) {
+      dependencies.add(other);
+    }
+  }
+  return [...dependencies];
+};
+
+/**
+ * Compute the level of each package: 0 for packages importing no project
+ * package, else one more than the highest level imported.  Imports
+ * closing a cycle are not followed.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Map<Object, number>} Levels by package
+ */
+const get_package_levels = (packages) => {
+  const levels = new Map();
+  const visiting = new Set();
+
+  const visit = (pkg) => {
+    if (levels.has(pkg)) return levels.get(pkg);
+    if (visiting.has(pkg)) return -1;
+    visiting.add(pkg);
+    let level = 0;
+    for (const dependency of get_package_dependencies(pkg, packages)) {
+      level = Math.max(level, visit(dependency) + 1);
+    }
+    visiting.delete(pkg);
+    levels.set(pkg, level);
+    return level;
+  };
+
+  for (const pkg of packages) visit(pkg);
+  return levels;
+};
+
+// ============================================================================
+// CONCRETE DEPENDENCIES
+// ============================================================================
+
+/**
+ * Resolve a type expression to a concrete type of another project
+ * package: a struct, or a named type with methods.
+ * @param {string} type_text - Type expression
+ * @param {Object} pkg - Package of the expression
+ * @param {string} filename - File of the expression
+ * @param {Object[]} packages - All project packages
+ * @returns {Object|null} Resolved type with its methods, or null
+ */
+const resolve_concrete_dependency = (type_text, pkg, filename, packages) => {
+  const resolved = resolve_project_type(type_text, pkg, filename, packages);
+  if (!resolved || resolved.pkg === pkg) return null;
+  if (resolved.type.kind === 'interface') return null;
+  const methods = get_type_methods(
+    resolved.type.name,
+    resolved.pkg,
+    new Set(),
+    resolved.pointer
+  );
+  if (resolved.type.kind !== 'struct' && methods.size === 0) return null;
+  return { ...resolved, methods };
+};
+
+/**
+ * Find the methods called on a struct field in the functions of a
+ * package (`s.store.Get()`).  Selecting anything but a method of the
+ * field type needs the concrete type.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {string} field - Field name
+ * @param {Map<string, Object>} methods - Methods of the field type
+ * @returns {string[]|null} Sorted names of the methods called, or null if a field of the field is read
+ */
+const find_field_method_uses = (pkg, field, methods) => {
+  const used = new Set();
+  const pattern = new RegExp(`\\.\\s*${field}(?!\\w)`, 'g');
+  for (const fn of pkg.functions) {
+    if (!fn.body) continue;
+    const masked = mask_source(fn.body);
+    let match;
+    while ((match = pattern.exec(masked)) !== null) {
+      const rest = masked.substring(pattern.lastIndex);
+      const selector = rest.match(/^\s*\.\s*([A-Za-z_]\w*)/);
+      if (!selector) continue;
+      if (!methods.has(selector[1])) return null;
+      used.add(selector[1]);
+    }
+  }
+  return [...used].sort();
+};
+
+/**
+ * List the parameters and struct fields of a package of a concrete type
+ * of another project package.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object[]} packages - All project packages
+ * @returns {Object[]} Sites with kind, symbol, name, type, filename, line, resolved type and methods used
+ */
+const find_concrete_dependencies = (pkg, packages) => {
+  const sites = [];
+
+  for (const fn of pkg.functions) {
+    if (!fn.body || fn.filename.endsWith('_test.go')) continue;
+    const masked = strip_function_literals(mask_source(fn.body));
+    const symbol = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+    for (const param of fn.params) {
+      if (!param.name || param.name === '_' || param.variadic) continue;
+      const resolved = resolve_concrete_dependency(
+        param.type,
+        pkg,
+        fn.filename,
+        packages
+      );
+      if (!resolved) continue;
+      const used = find_param_method_uses(masked, param.name, resolved.methods);
+      if (!used || used.length === 0) continue;
+      sites.push({
+        kind: 'parameter',
+        symbol,
+        name: param.name,
+        type: param.type,
+        filename: fn.filename,
+        line: fn.line,
+        resolved,
+        methods_used: used
+      });
+    }
+  }
+
+  for (const type of pkg.types) {
+    if (type.kind !== 'struct' || type.filename.endsWith('_test.go')) continue;
+    for (const field of type.fields) {
+      if (field.embedded) continue;
+      const resolved = resolve_concrete_dependency(
+        field.type,
+        pkg,
+        type.filename,
+        packages
+      );
+      if (!resolved) continue;
+      for (const name of field.names) {
+        const used = find_field_method_uses(pkg, name, resolved.methods);
+        if (!used || used.length === 0) continue;
+        sites.push({
+          kind: 'field',
+          symbol: `${type.name}.${name}`,
+          name,
+          type: field.type,
+          filename: type.filename,
+          line: field.line,
+          resolved,
+          methods_used: used
+        });
+      }
+    }
+  }
+
+  return sites;
+};
+
+// ============================================================================
+// DEPENDENCY INVERSION REPORT
+// ============================================================================
+
+/**
+ * Find the concrete dependencies of a set of packages which could depend
+ * on an interface of the project instead, with the interfaces suggested.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Dependencies by file and line
+ */
+const find_dependency_inversions = (packages) => {
+  const levels = get_package_levels(packages);
+  const inversions = [];
+
+  for (const pkg of packages) {
+    for (const site of find_concrete_dependencies(pkg, packages)) {
+      const { resolved, ...rest } = site;
+      const covering = find_covering_interfaces(
+        resolved,
+        site.methods_used,
+        packages
+      );
+      if (covering.length === 0) continue;
+      // Interfaces of the depending package first
+      const own = covering.filter((i) => i.startsWith(`${pkg.name}.`));
+      const interfaces = [
+        ...own,
+        ...covering.filter((i) => !own.includes(i))
+      ];
+      const concrete = `${resolved.pkg.name}.${resolved.type.name}`;
+
+      inversions.push({
+        package: pkg.name,
+        directory: pkg.directory,
+        level: levels.get(pkg),
+        depends_on: {
+          package: resolved.pkg.name,
+          directory: resolved.pkg.directory,
+          level: levels.get(resolved.pkg)
+        },
+        concrete,
+        ...rest,
+        interfaces,
+        suggestion: interfaces[0],
+        message: `${site.kind} ${site.symbol}${site.kind === 'parameter' ? ` (${site.name})` : ''} depends on the concrete ${concrete}; depend on ${interfaces[0]} instead`
+      });
+    }
+  }
+
+  return inversions.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Summarize a dependency inversion report.
+ * @param {Object[]} inversions - Dependencies (from find_dependency_inversions)
+ * @returns {Object} Numbers of dependencies, packages and concrete types
+ */
+const summarize_dependency_inversions = (inversions) => {
+  return {
+    dependencies: inversions.length,
+    parameters: inversions.filter((i) => i.kind === 'parameter').length,
+    fields: inversions.filter((i) => i.kind === 'field').length,
+    packages: new Set(inversions.map((i) => i.directory)).size,
+    concrete_types: new Set(
+      inversions.map((i) => `${i.depends_on.directory}.${i.concrete}`)
+    ).size
+  };
+};
+
+/**
+ * Report the dependency inversions of a set of Go sources, such as the
+ * files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @returns {Object} Dependencies with a summary
+ */
+const find_source_dependency_inversions = (sources) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const inversions = find_dependency_inversions(group_go_packages(files));
+  return { inversions, summary: summarize_dependency_inversions(inversions) };
+};
+
+/**
+ * Report the dependency inversions of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Dependencies with a summary
+ */
+const analyze_project_dependency_inversions = async (project_id) => {
+  const inversions = find_dependency_inversions(
+    await load_go_packages(project_id)
+  );
+  return { inversions, summary: summarize_dependency_inversions(inversions) };
+};
+
+export {
+  analyze_project_dependency_inversions,
+  find_source_dependency_inversions,
+  find_dependency_inversions,
+  summarize_dependency_inversions,
+  get_package_levels
+};
@@ -0,0 +1,494 @@
+'use strict';
+
+/**
+ * @fileoverview Go lifecycle state machine module.
+ * Infers the state machine implied by the methods of a lifecycle type: a
+ * struct whose methods (`Start`, `Pause`, `Stop`) assign the constants of
+ * a state field, as in
+ *
+ *   type State int
+ *   const (Idle State = iota; Running; Stopped)
+ *   func (s *Server) Start() error {
+ *     if s.state != Idle { return ErrStarted }
+ *     s.state = Running
+ *     ...
+ *
+ * The states and transitions are reported as a graph, for documenting
+ * the lifecycle of a type.  This is opt-in and best-effort: it reads the
+ * code, it does not execute it.
+ *
+ * A state field is a field of a named type with typed constants (an
+ * enum), or a field named state, status, phase or stage, assigned a
+ * constant by at least one method.  Each assignment is a transition from
+ * the states its guards allow:
+ * - `if s.state != A { return }` before it: from A only
+ * - `if s.state == A { return }` before it: from any state but A
+ * - `if s.state == A { ... }` around it: from A only
+ * - `case A, B:` of a `switch s.state` around it: from A or B
+ * Conditions joined with `||` or `&&` combine as expected; other guards
+ * are not understood and allow any state.  The initial state is the one
+ * set by a composite literal of the type (`&Server{state: Idle}`), else
+ * the zero value of the enum.  The machine is complete when every state
+ * is reachable from the initial state.  Test files are left out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/lifecycle
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  build_constant_resolver,
+  get_base_type,
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Names of fields holding a state, whatever their type.
+ */
+const STATE_FIELD_PATTERN = /^(state|status|phase|stage)$/i;
+
+/**
+ * Names of the methods of a lifecycle, for ordering and reporting.
+ */
+const LIFECYCLE_METHOD_PATTERN =
+  /^(Start|Stop|Pause|Resume|Run|Open|Close|Shutdown|Begin|End|Finish|Cancel|Reset|Init|Connect|Disconnect|Activate|Deactivate|Enable|Disable|Suspend|Restart|Abort|Commit|Rollback)/;
+
+// ============================================================================
+// STATES
+// ============================================================================
+
+/**
+ * Find the typed constants of the named types of a package, the
+ * candidate state types.  A constant without a type or value in a group
+ * repeats the type of the previous one (`Running` after
+ * `Idle State = iota`).
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Map<string, Object[]>} Constants with name, value and line by type name
+ */
+const find_state_types = (pkg) => {
+  const named = new Set(
+    pkg.types.filter((t) => t.kind === 'named').map((t) => t.name)
+  );
+  const resolve = build_constant_resolver(pkg.consts);
+  const types = new Map();
+  let group = null;
+  let group_type = null;
+  for (const decl of pkg.consts) {
+    const key = decl.grouped ? `${decl.filename}:${decl.group_line}` : null;
+    if (key !== group) group_type = null;
+    group = key;
+    const type = decl.type || (decl.implicit ? group_type : null);
+    group_type = type;
+    if (!named.has(type)) continue;
+    if (!types.has(type)) types.set(type, []);
+    for (const name of decl.names) {
+      if (name === '_') continue;
+      types.get(type).push({ name, value: resolve(name), line: decl.line });
+    }
+  }
+  return types;
+};
+
+/**
+ * Find the constants assigned to a field of the receiver in the methods
+ * of a type.
+ * @param {Object[]} methods - Methods of the type, with masked bodies
+ * @param {string} field - Field name
+ * @returns {Object[]} Assignments with method, value, offset and line
+ */
+const find_state_assignments = (methods, field) => {
+  const assignments = [];
+  for (const method of methods) {
+    const receiver = method.fn.receiver.name;
+    if (!receiver) continue;
+    const pattern = new RegExp(
+      `(?<![\\w.])${receiver}\\s*\\.\\s*${field}\\s*=(?!=)\\s*([A-Za-z_][\\w.]*)`,
+      'g'
+    );
+    for (const match of method.masked.matchAll(pattern)) {
+      assignments.push({
+        method: method.fn,
+        value: match[1].split('.').pop(),
+        offset: match.index,
+        line: method.fn.body_line + line_at(method.line_index, match.index) - 1
+      });
+    }
+  }
+  return assignments;
+};
+
+// ============================================================================
+// GUARDS
+// ============================================================================
+
+/**
+ * Parse the comparisons of a state field in a condition.
+ * @param {string} condition - Masked condition
+ * @param {string} subject - Pattern of the compared field (`s\.state`)
+ * @returns {Object|null} Operator (== or !=) and states, or null if not a state comparison
+ */
+const parse_state_condition = (condition, subject) => {
+  const pattern = new RegExp(
+    `${subject}\\s*(==|!=)\\s*([A-Za-z_][\\w.]*)|([A-Za-z_][\\w.]*)\\s*(==|!=)\\s*${subject}`,
+    'g'
+  );
+  const comparisons = [...condition.matchAll(pattern)].map((m) => ({
+    operator: m[1] || m[4],
+    state: (m[2] || m[3]).split('.').pop()
+  }));
+  if (comparisons.length === 0) return null;
+  const operators = new Set(comparisons.map((c) => c.operator));
+  if (operators.size > 1) return null;
+  return {
+    operator: comparisons[0].operator,
+    states: comparisons.map((c) => c.state)
+  };
+};
+
+/**
+ * Find the states from which an assignment of a state field can happen,
+ * from the `if` and `switch` guards of the method (see the module doc).
+ * @param {string} masked - Masked method body
+ * @param {number} offset - Offset of the assignment
+ * @param {string} subject - Pattern of the state field (`s\.state`)
+ * @returns {Object} Allowed states (null for any) and excluded states
+ */
+const find_guard_states = (masked, offset, subject) => {
+  let allowed = null;
+  const excluded = new Set();
+  const allow = (states) => {
+    allowed = allowed
+      ? new Set(states.filter((s) => allowed.has(s)))
+      : new Set(states);
+  };
+
+  for (const match of masked.matchAll(/\bif\s+([^{]*)\{/g)) {
+    if (match.index > offset) break;
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    if (close === -1) continue;
+    const condition = parse_state_condition(match[1], subject);
+    if (!condition) continue;
+    const inside = offset > open && offset < close;
+    const block = masked.substring(open, close);
+    if (inside) {
+      if (condition.operator === '==') allow(condition.states);
+      else condition.states.forEach((s) => excluded.add(s));
+    } else if (/\b(return|panic)\b/.test(block)) {
+      if (condition.operator === '!=') allow(condition.states);
+      else condition.states.forEach((s) => excluded.add(s));
+    }
+  }
+
+  const switch_pattern = new RegExp(`\\bswitch\\s+${subject}\\s*\\{`, 'g');
+  for (const match of masked.matchAll(switch_pattern)) {
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    if (close === -1 || offset < open || offset > close) continue;
+    const body = masked.substring(open + 1, close);
+    const clauses = [...body.matchAll(/\b(case\s+([^:]*)|default\s*):/g)];
+    const listed = clauses
+      .filter((c) => c[2])
+      .flatMap((c) => c[2].split(',').map((s) => s.trim().split('.').pop()));
+    const clause = clauses.filter((c) => open + 1 + c.index < offset).pop();
+    if (!clause) continue;
+    if (clause[2]) {
+      allow(clause[2].split(',').map((s) => s.trim().split('.').pop()));
+    } else {
+      listed.forEach((s) => excluded.add(s));
+    }
+  }
+
+  return { allowed, excluded };
+};
+
+// ============================================================================
+// STATE MACHINES
+// ============================================================================
+
+/**
+ * Find the initial state of a type: the state set by a composite literal
+ * of the type in the package, else the zero value of the state type.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {string} type_name - Type name
+ * @param {string} field - State field
+ * @param {Object[]} constants - Constants of the state type, if an enum
+ * @returns {string|null} Initial state
+ */
+const find_initial_state = (pkg, type_name, field, constants) => {
+  const literal = new RegExp(`(?<![\\w.])${type_name}\\s*\\{`, 'g');
+  for (const fn of pkg.functions) {
+    if (!fn.body || fn.filename.endsWith('_test.go')) continue;
+    const masked = mask_source(fn.body);
+    for (const match of masked.matchAll(literal)) {
+      const open = match.index + match[0].length - 1;
+      const close = find_matching(masked, open);
+      if (close === -1) continue;
+      const value = masked
+        .substring(open, close)
+        .match(new RegExp(`[{,\\s]${field}\\s*:\\s*([A-Za-z_][\\w.]*)`));
+      if (value) return value[1].split('.').pop();
+    }
+  }
+  const zero = (constants || []).find((c) => c.value === 0);
+  return zero ? zero.name : null;
+};
+
+/**
+ * Find the states reachable from a state over a set of edges.
+ * @param {string} initial - Initial state
+ * @param {Object[]} edges - Edges with from and to
+ * @returns {Set<string>} Reachable states, the initial one included
+ */
+const find_reachable_states = (initial, edges) => {
+  const reachable = new Set([initial]);
+  const queue = [initial];
+  while (queue.length > 0) {
+    const state = queue.shift();
+    for (const edge of edges) {
+      if (edge.from !== state || reachable.has(edge.to)) continue;
+      reachable.add(edge.to);
+      queue.push(edge.to);
+    }
+  }
+  return reachable;
+};
+
+/**
+ * Build the state machine of a struct and its state field.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object} type - Struct type
+ * @param {string} field - State field
+ * @param {string|null} state_type - Enum type of the field, if any
+ * @param {Object[]} constants - Constants of the enum, if any
+ * @param {Object[]} assignments - Assignments of the field (from find_state_assignments)
+ * @param {Map<Object, Object>} masked_methods - Masked methods by function
+ * @returns {Object} State machine
+ */
+const build_state_machine = (
+  pkg,
+  type,
+  field,
+  state_type,
+  constants,
+  assignments,
+  masked_methods
+) => {
+  const initial = find_initial_state(pkg, type.name, field, constants);
+  const names = [
+    ...new Set([
+      ...(constants || []).map((c) => c.name),
+      ...(initial ? [initial] : []),
+      ...assignments.map((a) => a.value)
+    ])
+  ];
+
+  const transitions = assignments.map((assignment) => {
+    const { masked } = masked_methods.get(assignment.method);
+    const subject = `${assignment.method.receiver.name}\\s*\\.\\s*${field}`;
+    const guard = find_guard_states(masked, assignment.offset, subject);
+    const from = names.filter(
+      (s) =>
+        (guard.allowed === null || guard.allowed.has(s)) &&
+        !guard.excluded.has(s)
+    );
+    return {
+      method: assignment.method.name,
+      from: guard.allowed === null && guard.excluded.size === 0 ? ['*'] : from,
+      to: assignment.value,
+      guarded: guard.allowed !== null || guard.excluded.size > 0,
+      filename: assignment.method.filename,
+      line: assignment.line
+    };
+  });
+
+  // Unguarded transitions go from every other state
+  const edges = [];
+  const seen = new Set();
+  for (const transition of transitions) {
+    const from = transition.guarded
+      ? transition.from
+      : names.filter((s) => s !== transition.to);
+    for (const state of from) {
+      const key = `${state}:${transition.to}:${transition.method}`;
+      if (seen.has(key)) continue;
+      seen.add(key);
+      edges.push({ from: state, to: transition.to, method: transition.method });
+    }
+  }
+
+  const reachable = initial ? find_reachable_states(initial, edges) : new Set();
+  const states = names.map((name) => ({
+    name,
+    initial: name === initial,
+    terminal: !edges.some((e) => e.from === name && e.to !== name),
+    reachable: reachable.has(name)
+  }));
+  const unreachable = states.filter((s) => !s.reachable).map((s) => s.name);
+  const methods = [...new Set(transitions.map((t) => t.method))];
+
+  return {
+    type: type.name,
+    package: pkg.name,
+    directory: pkg.directory,
+    filename: type.filename,
+    line: type.line,
+    field,
+    state_type,
+    initial,
+    states,
+    transitions,
+    edges,
+    lifecycle_methods: methods.filter((m) => LIFECYCLE_METHOD_PATTERN.test(m)),
+    complete: initial !== null && unreachable.length === 0,
+    unreachable
+  };
+};
+
+/**
+ * Format a state machine as a Graphviz digraph, with the methods as the
+ * labels of the transitions.
+ * @param {Object} machine - State machine (from find_state_machines)
+ * @returns {string} DOT source
+ */
+const format_state_machine_dot = (machine) => {
+  const lines = [`digraph ${machine.type} {`, '  rankdir=LR;'];
+  for (const state of machine.states) {
+    const shape = state.terminal ? 'doublecircle' : 'circle';
+    const style = state.reachable ? '' : ', style=dashed';
+    lines.push(`  "${state.name}" [shape=${shape}${style}];`);
+  }
+  if (machine.initial) {
+    lines.push('  start [shape=point];');
+    lines.push(`  start -> "${machine.initial}";`);
+  }
+  for (const edge of machine.edges) {
+    lines.push(`  "${edge.from}" -> "${edge.to}" [label="${edge.method}"];`);
+  }
+  lines.push('}');
+  return lines.join('\n');
+};
+
+/**
+ * Find the state machines implied by the lifecycle types of a set of
+ * packages (see the module doc).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string} [options.type] - Only infer the machine of this type
+ * @param {boolean} [options.dot=false] - Add the Graphviz digraph of each machine
+ * @returns {Object[]} State machines by file and line
+ */
+const find_state_machines = (packages, options = {}) => {
+  const machines = [];
+
+  for (const pkg of packages) {
+    const state_types = find_state_types(pkg);
+    for (const type of pkg.types) {
+      if (type.kind !== 'struct' || type.filename.endsWith('_test.go')) continue;
+      if (options.type && type.name !== options.type) continue;
+      const masked_methods = new Map(
+        pkg.functions
+          .filter((fn) => fn.receiver && fn.receiver.type === type.name && fn.body)
+          .filter((fn) => !fn.filename.endsWith('_test.go'))
+          .map((fn) => [
+            fn,
+            {
+              fn,
+              masked: mask_source(fn.body),
+              line_index: build_line_index(fn.body)
+            }
+          ])
+      );
+      if (masked_methods.size === 0) continue;
+
+      for (const field_decl of type.fields) {
+        if (field_decl.embedded) continue;
+        const state_type = get_base_type(field_decl.type);
+        const constants = state_types.get(state_type) || null;
+        for (const field of field_decl.names) {
+          if (!constants && !STATE_FIELD_PATTERN.test(field)) continue;
+          const assignments = find_state_assignments(
+            [...masked_methods.values()],
+            field
+          ).filter(
+            (a) => !constants || constants.some((c) => c.name === a.value)
+          );
+          if (assignments.length === 0) continue;
+          const machine = build_state_machine(
+            pkg,
+            type,
+            field,
+            constants ? state_type : null,
+            constants,
+            assignments,
+            masked_methods
+          );
+          if (machine.states.length < 2) continue;
+          if (options.dot) machine.dot = format_state_machine_dot(machine);
+          machines.push(machine);
+        }
+      }
+    }
+  }
+
+  return machines.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Summarize a set of state machines.
+ * @param {Object[]} machines - State machines (from find_state_machines)
+ * @returns {Object} Numbers of machines, complete ones, states and transitions
+ */
+const summarize_state_machines = (machines) => {
+  return {
+    machines: machines.length,
+    complete: machines.filter((m) => m.complete).length,
+    states: machines.reduce((sum, m) => sum + m.states.length, 0),
+    transitions: machines.reduce((sum, m) => sum + m.transitions.length, 0)
+  };
+};
+
+/**
+ * Infer the state machines of a set of Go sources, such as the files of
+ * a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_state_machines)
+ * @returns {Object} State machines with a summary
+ */
+const find_source_state_machines = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const machines = find_state_machines(group_go_packages(files), options);
+  return { machines, summary: summarize_state_machines(machines) };
+};
+
+/**
+ * Infer the state machines of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_state_machines)
+ * @returns {Promise<Object>} State machines with a summary
+ */
+const analyze_project_state_machines = async (project_id, options = {}) => {
+  const machines = find_state_machines(
+    await load_go_packages(project_id),
+    options
+  );
+  return { machines, summary: summarize_state_machines(machines) };
+};
+
+export {
+  analyze_project_state_machines,
+  find_source_state_machines,
+  find_state_machines,
+  summarize_state_machines,
+  format_state_machine_dot,
+  parse_state_condition
+};
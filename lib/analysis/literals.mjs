@@ -0,0 +1,752 @@
+'use strict';
+
+/**
+ * @fileoverview Go literal extraction module.
+ * Finds string, rune and numeric literals in Go source along with their
+ * values, spans and enclosing declarations.  Literals assigned to named
+ * constants are distinguished from inline ones, which makes this the
+ * building block for hardcoded string and magic number audits.
+ *
+ * The format strings of the formatting calls of the fmt and log packages
+ * (`fmt.Printf`, `fmt.Errorf`, `log.Fatalf`) are parsed for their verbs,
+ * including `%%` escapes, width and precision specifiers (`%-8.2f`, `%*d`)
+ * and explicit argument indexes (`%[1]d`), and checked against the number
+ * of arguments of the call, as `go vet` does.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/literals
+ */
+
+import {
+  find_literal_ranges,
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  parse_number_literal,
+  split_top_level,
+  parse_go_file,
+  load_go_sources
+} from './golang.mjs';
+import { get_import_name, get_package_names } from './imports.mjs';
+
+/**
+ * Supported literal kinds.
+ */
+const LITERAL_KINDS = ['string', 'rune', 'number'];
+
+/**
+ * Go numeric literal (integer, float, hex, binary, octal and imaginary).
+ */
+const NUMBER_PATTERN =
+  /(?<![\w.])(?:0[xX][0-9a-fA-F_]+(?:\.[0-9a-fA-F_]*)?(?:[pP][+-]?\d+)?|0[bB][01_]+|0[oO][0-7_]+|(?:\d[\d_]*(?:\.[\d_]*)?|\.\d[\d_]*)(?:[eE][+-]?\d[\d_]*)?)i?(?![\w.])/g;
+
+/**
+ * Characters after which a '-' is a unary minus rather than subtraction.
+ */
+const UNARY_CONTEXT = /[(,=:[{+\-*/%<>!&|^;]/;
+
+/**
+ * Escape sequences of interpreted string and rune literals.
+ */
+const SIMPLE_ESCAPES = {
+  a: '\x07',
+  b: '\b',
+  f: '\f',
+  n: '\n',
+  r: '\r',
+  t: '\t',
+  v: '\v',
+  '\\': '\\',
+  "'": "'",
+  '"': '"'
+};
+
+/**
+ * Formatting functions by import path, with the index of their format
+ * argument.
+ */
+const FORMAT_FUNCTIONS = {
+  fmt: {
+    Printf: 0,
+    Sprintf: 0,
+    Errorf: 0,
+    Fprintf: 1,
+    Appendf: 1,
+    Scanf: 0,
+    Sscanf: 1,
+    Fscanf: 1
+  },
+  log: { Printf: 0, Fatalf: 0, Panicf: 0 }
+};
+
+/**
+ * Kinds of format call issues.
+ * - missing_args: the format has more verbs than the call has arguments
+ * - extra_args: the call has more arguments than the format has verbs
+ * - no_verb: the format ends with a % without a verb
+ */
+const FORMAT_ISSUES = ['missing_args', 'extra_args', 'no_verb'];
+
+/**
+ * Numbers common enough not to be magic, left out of magic number
+ * reports by default.
+ */
+const DEFAULT_COMMON_NUMBERS = [0, 1, -1, 2];
+
+/**
+ * Default number of uses from which a number is magic.
+ */
+const DEFAULT_MIN_OCCURRENCES = 3;
+
+// ============================================================================
+// LITERAL VALUES
+// ============================================================================
+
+/**
+ * Decode a Go string or rune literal into its value.
+ * Raw (backquoted) strings are returned as-is, minus carriage returns.
+ * @param {string} text - Literal text including its quotes
+ * @returns {string} Decoded value
+ */
+const unquote_go_literal = (text) => {
+  const quote = text[0];
+  const body = text.substring(1, text.length - 1);
+  if (quote === '`') return body.replace(/\r/g, '');
+
+  return body.replace(
+    /\\(x[0-9a-fA-F]{2}|u[0-9a-fA-F]{4}|U[0-9a-fA-F]{8}|[0-7]{3}|.)/g,
+    function decode_escape(match, seq) {
+      if (SIMPLE_ESCAPES[seq] !== undefined) return SIMPLE_ESCAPES[seq];
+      if (/^[xuU]/.test(seq)) {
+        return String.fromCodePoint(parseInt(seq.substring(1), 16));
+      }
+      if (/^[0-7]{3}$/.test(seq)) {
+        return String.fromCodePoint(parseInt(seq, 8));
+      }
+      return match;
+    }
+  );
+};
+
+/**
+ * Get the numeric value of a Go number literal.
+ * @param {string} text - Literal text
+ * @returns {number|null} Value, or null for imaginary and hex float literals
+ */
+const get_number_value = (text) => {
+  if (text.endsWith('i')) return null;
+  if (/^0[xX]/.test(text) && /[.pP]/.test(text)) return null;
+  const value = parse_number_literal(text);
+  return Number.isNaN(value) ? null : value;
+};
+
+// ============================================================================
+// DECLARATION RANGES
+// ============================================================================
+
+/**
+ * Find the offset ranges of `const` and `import` declarations, including
+ * constants declared inside function bodies.
+ * @param {string} masked - Masked source (from mask_source)
+ * @returns {Object[]} Ranges with keyword, start and end (exclusive)
+ */
+const find_declaration_ranges = (masked) => {
+  const ranges = [];
+  const pattern = /(^|[;{\n])[ \t]*(const|import)\b/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    const start = match.index + match[0].length - match[2].length;
+    let pos = start + match[2].length;
+    while (/[ \t]/.test(masked[pos])) pos++;
+
+    let end;
+    if (masked[pos] === '(') {
+      const close = find_matching(masked, pos);
+      end = close === -1 ? masked.length : close + 1;
+    } else {
+      const line_end = masked.indexOf('\n', pos);
+      end = line_end === -1 ? masked.length : line_end;
+    }
+
+    ranges.push({ keyword: match[2], start, end });
+    pattern.lastIndex = end;
+  }
+
+  return ranges;
+};
+
+/**
+ * Find the innermost top level declaration containing a line.
+ * Functions take precedence over the other declarations.
+ * @param {Object} file - Parsed file (from parse_go_file)
+ * @param {number} line - Line number
+ * @returns {Object|null} Enclosing symbol with kind and name, or null
+ */
+const find_enclosing_declaration = (file, line) => {
+  const contains = (decl) => line >= decl.line && line <= decl.end_line;
+
+  const fn = file.functions.find(contains);
+  if (fn) {
+    return fn.receiver
+      ? { kind: 'method', name: `${fn.receiver.type}.${fn.name}` }
+      : { kind: 'function', name: fn.name };
+  }
+
+  const type = file.types.find(contains);
+  if (type) return { kind: 'type', name: type.name };
+
+  for (const [kind, decls] of [
+    ['const', file.consts],
+    ['var', file.vars]
+  ]) {
+    const decl = decls.find(contains);
+    if (decl) return { kind, name: decl.names.join(', ') };
+  }
+
+  return null;
+};
+
+/**
+ * Check if a string literal at a line is a struct field tag.
+ * @param {Object} file - Parsed file (from parse_go_file)
+ * @param {number} line - Line number
+ * @returns {boolean} True if a struct field on that line has a tag
+ */
+const is_struct_tag_line = (file, line) => {
+  return file.types.some(
+    (type) =>
+      type.kind === 'struct' &&
+      type.fields.some((field) => field.line === line && field.tag)
+  );
+};
+
+// ============================================================================
+// LITERAL EXTRACTION
+// ============================================================================
+
+/**
+ * Find the literals in a Go source file.
+ * Each literal is classified by context:
+ * - const: part of a constant declaration (acceptable, already named)
+ * - var: initializer of a package level variable
+ * - import: an import path
+ * - struct_tag: a struct field tag
+ * - inline: anywhere else (hardcoded values)
+ * @param {string} source - Go source code
+ * @param {string} [filename=''] - Filename (recorded on each literal)
+ * @param {Object} [options] - Options
+ * @param {string} [options.kind] - Only return literals of this kind (string, rune or number)
+ * @returns {Object[]} Literals in source order
+ */
+const find_go_literals = (source, filename = '', options = {}) => {
+  const text = source || '';
+  const kind = options.kind || null;
+  const masked = mask_source(text);
+  const line_index = build_line_index(text);
+  const file = parse_go_file(text, filename);
+  const declarations = find_declaration_ranges(masked);
+  const found = [];
+
+  const get_column = (offset) => {
+    return offset - line_index[line_at(line_index, offset) - 1] + 1;
+  };
+
+  const add_literal = (literal_kind, start, end, value, raw) => {
+    const line = line_at(line_index, start);
+    const declaration = declarations.find(
+      (d) => start >= d.start && start < d.end
+    );
+    const enclosing = find_enclosing_declaration(file, line);
+
+    let context = 'inline';
+    if (declaration) {
+      context = declaration.keyword;
+    } else if (literal_kind === 'string' && is_struct_tag_line(file, line)) {
+      context = 'struct_tag';
+    } else if (enclosing && enclosing.kind === 'var') {
+      context = 'var';
+    }
+
+    found.push({
+      kind: literal_kind,
+      text: raw,
+      value,
+      filename,
+      line,
+      column: get_column(start),
+      end_line: line_at(line_index, end - 1),
+      end_column: get_column(end - 1) + 1,
+      enclosing,
+      context,
+      is_const: context === 'const',
+      start
+    });
+  };
+
+  for (const range of find_literal_ranges(text)) {
+    if (range.type !== 'string') continue;
+    const raw = text.substring(range.start, range.end);
+    const literal_kind = raw[0] === "'" ? 'rune' : 'string';
+    if (kind && kind !== literal_kind) continue;
+    add_literal(
+      literal_kind,
+      range.start,
+      range.end,
+      unquote_go_literal(raw),
+      raw
+    );
+  }
+
+  if (!kind || kind === 'number') {
+    NUMBER_PATTERN.lastIndex = 0;
+    let match;
+    while ((match = NUMBER_PATTERN.exec(masked)) !== null) {
+      let start = match.index;
+      let raw = match[0];
+      let value = get_number_value(raw);
+
+      // Fold a unary minus into the literal (e.g. `x = -1`)
+      let before = start - 1;
+      while (before >= 0 && /[ \t]/.test(masked[before])) before--;
+      if (masked[before] === '-') {
+        let prev = before - 1;
+        while (prev >= 0 && /\s/.test(masked[prev])) prev--;
+        if (prev < 0 || UNARY_CONTEXT.test(masked[prev])) {
+          raw = masked.substring(before, start) + raw;
+          start = before;
+          if (value !== null) value = -value;
+        }
+      }
+
+      add_literal('number', start, match.index + match[0].length, value, raw);
+    }
+  }
+
+  return found
+    .sort(function sort_by_offset(a, b) {
+      return a.start - b.start;
+    })
+    .map(function strip_offset({ start, ...literal }) {
+      return literal;
+    });
+};
+
+/**
+ * Extract the literals of a project's Go files.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {string} [options.kind] - Only return literals of this kind (string, rune or number)
+ * @param {boolean} [options.inline_only=false] - Only return inline literals
+ * @returns {Promise<Object>} Literals with a summary
+ */
+const extract_literals = async (project_id, options = {}) => {
+  const kind = options.kind || null;
+  if (kind && !LITERAL_KINDS.includes(kind)) {
+    throw new Error(
+      `Unknown literal kind '${kind}' (expected one of: ${LITERAL_KINDS.join(', ')})`
+    );
+  }
+
+  const sources = await load_go_sources(project_id);
+  let literals = [];
+  for (const row of sources) {
+    literals.push(...find_go_literals(row.source, row.filename, { kind }));
+  }
+
+  if (options.inline_only) {
+    literals = literals.filter((l) => l.context === 'inline');
+  }
+
+  const by_kind = {};
+  const by_context = {};
+  for (const literal of literals) {
+    by_kind[literal.kind] = (by_kind[literal.kind] || 0) + 1;
+    by_context[literal.context] = (by_context[literal.context] || 0) + 1;
+  }
+
+  return {
+    kind,
+    literals,
+    summary: {
+      total_literals: literals.length,
+      by_kind,
+      by_context,
+      const_count: by_context.const || 0,
+      inline_count: by_context.inline || 0,
+      file_count: new Set(literals.map((l) => l.filename)).size
+    }
+  };
+};
+
+// ============================================================================
+// MAGIC NUMBERS
+// ============================================================================
+
+/**
+ * Find the constants of a Go source declared as a plain number literal
+ * (`const Timeout = 30`), by value.  Constants repeating an iota
+ * expression or computed from other values are left out.
+ * @param {string} source - Go source code
+ * @returns {Map<number, string[]>} Constant names by value
+ */
+const find_number_constants = (source) => {
+  const number = new RegExp(`^-?\\s*(?:${NUMBER_PATTERN.source})$`);
+  const constants = new Map();
+  for (const decl of parse_go_file(source || '').consts) {
+    if (decl.implicit) continue;
+    decl.values.forEach((text, i) => {
+      const value_text = text.trim();
+      if (!decl.names[i] || !number.test(value_text)) return;
+      const negative = value_text.startsWith('-');
+      const value = get_number_value(value_text.replace(/^-\s*/, ''));
+      if (value === null) return;
+      const key = negative ? -value : value;
+      if (!constants.has(key)) constants.set(key, []);
+      constants.get(key).push(decl.names[i]);
+    });
+  }
+  return constants;
+};
+
+/**
+ * Find the magic numbers of a set of Go sources: the number values used
+ * inline at least a minimum number of times instead of through a named
+ * constant.  Uses are grouped by value, so `30` and `0x1E` are the same
+ * number; the inline uses only are counted, not the constant, variable
+ * and import declarations.  Each group suggests extracting a named
+ * constant, or using the constants already declared with that value.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options
+ * @param {number} [options.min_occurrences=3] - Number of uses from which a number is magic
+ * @param {number[]} [options.ignore] - Numbers never magic (default: DEFAULT_COMMON_NUMBERS)
+ * @returns {Object[]} Magic numbers with their sites and suggestion, most used first
+ * @throws {Error} If the minimum number of occurrences is not at least 2
+ */
+const find_magic_numbers = (sources, options = {}) => {
+  const min_occurrences =
+    options.min_occurrences !== undefined
+      ? options.min_occurrences
+      : DEFAULT_MIN_OCCURRENCES;
+  if (!Number.isInteger(min_occurrences) || min_occurrences < 2) {
+    throw new Error(
+      `Invalid minimum occurrences '${min_occurrences}' (expected an integer of at least 2)`
+    );
+  }
+  const ignore = new Set(options.ignore || DEFAULT_COMMON_NUMBERS);
+
+  const groups = new Map();
+  const constants = new Map();
+  for (const row of sources) {
+    for (const [value, names] of find_number_constants(row.source)) {
+      if (!constants.has(value)) constants.set(value, new Set());
+      for (const name of names) constants.get(value).add(name);
+    }
+    const literals = find_go_literals(row.source, row.filename, {
+      kind: 'number'
+    });
+    for (const literal of literals) {
+      if (literal.context !== 'inline' || literal.value === null) continue;
+      if (ignore.has(literal.value)) continue;
+      if (!groups.has(literal.value)) groups.set(literal.value, []);
+      groups.get(literal.value).push({
+        text: literal.text,
+        filename: literal.filename,
+        line: literal.line,
+        column: literal.column,
+        enclosing: literal.enclosing
+      });
+    }
+  }
+
+  const magic = [];
+  for (const [value, sites] of groups) {
+    if (sites.length < min_occurrences) continue;
+    const names = [...(constants.get(value) || [])].sort();
+    const enclosing = new Set(
+      sites.map((site) =>
+        site.enclosing ? `${site.filename}:${site.enclosing.name}` : null
+      )
+    );
+    const suggestion =
+      names.length > 0
+        ? `use the constant ${names.join(' or ')}`
+        : 'extract a named constant';
+    magic.push({
+      value,
+      texts: [...new Set(sites.map((site) => site.text))],
+      occurrences: sites.length,
+      files: new Set(sites.map((site) => site.filename)).size,
+      declarations: enclosing.size,
+      constants: names,
+      suggestion,
+      message: `${sites[0].text} is used ${sites.length} times: ${suggestion}`,
+      sites
+    });
+  }
+
+  return magic.sort(function sort_by_occurrences(a, b) {
+    return b.occurrences - a.occurrences || a.value - b.value;
+  });
+};
+
+/**
+ * Find the magic numbers of a project's Go files.  Test files are left
+ * out: their expected values are rarely worth naming.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_magic_numbers)
+ * @returns {Promise<Object>} Magic numbers with a summary
+ */
+const analyze_project_magic_numbers = async (project_id, options = {}) => {
+  const sources = (await load_go_sources(project_id)).filter(
+    (row) => !row.filename.endsWith('_test.go')
+  );
+  const numbers = find_magic_numbers(sources, options);
+
+  return {
+    numbers,
+    summary: {
+      magic_numbers: numbers.length,
+      occurrences: numbers.reduce((sum, n) => sum + n.occurrences, 0),
+      with_constant: numbers.filter((n) => n.constants.length > 0).length,
+      file_count: new Set(
+        numbers.flatMap((n) => n.sites.map((site) => site.filename))
+      ).size
+    }
+  };
+};
+
+// ============================================================================
+// FORMAT CALLS
+// ============================================================================
+
+/**
+ * Parse the verbs of a format string.  `%%` is an escaped percent sign
+ * and consumes no argument; a `*` width or precision consumes one, and an
+ * argument index (`%[2]d`, `%[1]*d`) moves the next argument read.
+ * @param {string} format - Format string value
+ * @returns {Object} Verbs, number of arguments needed, whether arguments are indexed and whether the format ends without a verb
+ */
+const parse_format_verbs = (format) => {
+  const verbs = [];
+  let arg = 0;
+  let needed = 0;
+  let indexed = false;
+  let no_verb = false;
+
+  for (let i = 0; i < format.length; i++) {
+    if (format[i] !== '%') continue;
+    const start = i++;
+    if (format[i] === '%') continue;
+
+    const read_index = () => {
+      const match = format.substring(i).match(/^\[(\d+)\]/);
+      if (!match) return;
+      indexed = true;
+      arg = parseInt(match[1], 10) - 1;
+      i += match[0].length;
+    };
+    const read_number = () => {
+      read_index();
+      if (format[i] === '*') {
+        needed = Math.max(needed, ++arg);
+        i++;
+      } else {
+        while (/\d/.test(format[i] || '')) i++;
+      }
+    };
+
+    while (/[+\-# 0]/.test(format[i] || '')) i++;
+    read_number();
+    if (format[i] === '.') {
+      i++;
+      read_number();
+    }
+    read_index();
+
+    if (i >= format.length) {
+      no_verb = true;
+      break;
+    }
+    const verb = String.fromCodePoint(format.codePointAt(i));
+    needed = Math.max(needed, ++arg);
+    verbs.push({ verb, text: format.substring(start, i + verb.length), arg });
+    i += verb.length - 1;
+  }
+
+  return { verbs, needed, indexed, no_verb };
+};
+
+/**
+ * Check a format call: the verbs of its format string against its
+ * arguments.  Calls whose format is not a string literal, or whose last
+ * argument is spread (`args...`), are not checked; with argument indexes
+ * only missing arguments are reported.
+ * @param {string} name - Function name, as called (`fmt.Printf`)
+ * @param {string[]} args - Texts of the call arguments
+ * @param {number} format_index - Index of the format argument
+ * @returns {Object} Format value, verbs, argument counts and issues
+ */
+const check_format_call = (name, args, format_index) => {
+  const format_arg = args[format_index] || '';
+  const values = args.slice(format_index + 1);
+  const spread = values.length > 0 && values[values.length - 1].endsWith('...');
+  if (!/^(?:"(?:[^"\\\n]|\\.)*"|`[^`]*`)$/.test(format_arg) || spread) {
+    return {
+      format: null,
+      verbs: [],
+      needed: null,
+      args: values.length,
+      checked: false,
+      issues: []
+    };
+  }
+
+  const format = unquote_go_literal(format_arg);
+  const { verbs, needed, indexed, no_verb } = parse_format_verbs(format);
+  const plural = (count) => `${count} ${count === 1 ? 'arg' : 'args'}`;
+  const issues = [];
+
+  if (no_verb) {
+    issues.push({
+      kind: 'no_verb',
+      message: `${name} format ${format_arg} ends with a % without a verb`
+    });
+  }
+  if (needed > values.length) {
+    issues.push({
+      kind: 'missing_args',
+      message: `${name} format ${format_arg} needs ${plural(needed)} but the call has ${plural(values.length)}`
+    });
+  } else if (!indexed && needed < values.length) {
+    issues.push({
+      kind: 'extra_args',
+      message:
+        verbs.length === 0
+          ? `${name} call has arguments but no formatting directives`
+          : `${name} format ${format_arg} needs ${plural(needed)} but the call has ${plural(values.length)}`
+    });
+  }
+
+  return {
+    format,
+    verbs: verbs.map((v) => v.text),
+    needed,
+    args: values.length,
+    checked: true,
+    issues
+  };
+};
+
+/**
+ * Find the format calls of a Go source file (see FORMAT_FUNCTIONS) and
+ * check them.  The fmt and log packages are resolved through the imports
+ * of the file, so aliased imports are followed.
+ * @param {string} source - Go source code
+ * @param {string} [filename=''] - Filename (recorded on each call)
+ * @param {Map<string, string>} [package_names] - Project package names by directory
+ * @returns {Object[]} Format calls in source order
+ */
+const find_format_calls = (source, filename = '', package_names) => {
+  const text = source || '';
+  const masked = mask_source(text);
+  const line_index = build_line_index(text);
+  const file = parse_go_file(text, filename);
+  const calls = [];
+
+  for (const imp of file.imports) {
+    const functions = FORMAT_FUNCTIONS[imp.path];
+    if (!functions || imp.name === '_' || imp.name === '.') continue;
+    const { name } = get_import_name(imp, package_names);
+    const pattern = new RegExp(
+      `(?<![\\w.])${name}\\s*\\.\\s*(${Object.keys(functions).join('|')})\\s*\\(`,
+      'g'
+    );
+
+    let match;
+    while ((match = pattern.exec(masked)) !== null) {
+      const open = pattern.lastIndex - 1;
+      const close = find_matching(masked, open);
+      if (close === -1) continue;
+      const function_name = `${imp.path}.${match[1]}`;
+      const line = line_at(line_index, match.index);
+      calls.push({
+        function: function_name,
+        filename,
+        line,
+        column: match.index - line_index[line - 1] + 1,
+        enclosing: find_enclosing_declaration(file, line),
+        ...check_format_call(
+          function_name,
+          split_top_level(text.substring(open + 1, close)),
+          functions[match[1]]
+        ),
+        start: match.index
+      });
+    }
+  }
+
+  return calls
+    .sort(function sort_by_offset(a, b) {
+      return a.start - b.start;
+    })
+    .map(function strip_offset({ start, ...call }) {
+      return call;
+    });
+};
+
+/**
+ * Check the format calls of a project's Go files.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.mismatched_only=false] - Only return calls with issues
+ * @returns {Promise<Object>} Format calls with a summary
+ */
+const check_format_calls = async (project_id, options = {}) => {
+  const sources = await load_go_sources(project_id);
+  const package_names = get_package_names(sources);
+  const all = [];
+  for (const row of sources) {
+    all.push(...find_format_calls(row.source, row.filename, package_names));
+  }
+  const calls = options.mismatched_only
+    ? all.filter((call) => call.issues.length > 0)
+    : all;
+
+  const by_issue = Object.fromEntries(FORMAT_ISSUES.map((kind) => [kind, 0]));
+  for (const call of all) {
+    for (const issue of call.issues) by_issue[issue.kind]++;
+  }
+
+  return {
+    calls,
+    summary: {
+      total_calls: all.length,
+      checked: all.filter((call) => call.checked).length,
+      unchecked: all.filter((call) => !call.checked).length,
+      mismatched: all.filter((call) => call.issues.length > 0).length,
+      by_issue
+    }
+  };
+};
+
+export {
+  analyze_project_magic_numbers,
+  find_magic_numbers,
+  find_number_constants,
+  DEFAULT_COMMON_NUMBERS,
+  DEFAULT_MIN_OCCURRENCES,
+  check_format_calls,
+  find_format_calls,
+  check_format_call,
+  parse_format_verbs,
+  FORMAT_FUNCTIONS,
+  FORMAT_ISSUES,
+  extract_literals,
+  find_go_literals,
+  find_declaration_ranges,
+  find_enclosing_declaration,
+  unquote_go_literal,
+  get_number_value,
+  LITERAL_KINDS
+};
@@ -0,0 +1,369 @@
+'use strict';
+
+/**
+ * @fileoverview Go loop variable capture module.
+ * Finds goroutines launched in a loop with a function literal capturing a
+ * loop variable:
+ *
+ *   for _, v := range items {
+ *     go func() { use(v) }()
+ *   }
+ *
+ * Before Go 1.22 a loop declares its variables once for the whole loop,
+ * so every goroutine shares the same variable and may see the value of a
+ * later iteration.  Go 1.22 declares them once per iteration, which fixes
+ * the bug for modules declaring `go 1.22` or later and for files built
+ * with a `//go:build go1.22` constraint.  Callers give the target Go
+ * version to suppress the captures it fixes.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/loopvars
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  parse_parameters,
+  get_base_type,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * First minor Go version declaring loop variables once per iteration.
+ */
+const LOOPVAR_FIX_VERSION = 22;
+
+/**
+ * Ways a function literal is run as a goroutine: a go statement
+ * (`go func() {...}()`) or a group (`g.Go(func() error {...})`, as with
+ * errgroup).
+ */
+const LAUNCH_PATTERNS = [
+  { kind: 'go', pattern: /\bgo\s+(func)\s*\(/g },
+  { kind: 'group', pattern: /\.\s*Go\s*\(\s*(func)\s*\(/g }
+];
+
+// ============================================================================
+// GO VERSIONS
+// ============================================================================
+
+/**
+ * Parse a Go version (`1.21`, `1.21.3`, `go1.22`).
+ * @param {string} version - Go version
+ * @returns {Object|null} Major and minor version, or null if not a version
+ */
+const parse_go_version = (version) => {
+  const match = String(version || '')
+    .trim()
+    .match(/^(?:go)?(\d+)\.(\d+)(?:\.\d+)?/);
+  if (!match) return null;
+  return { major: Number(match[1]), minor: Number(match[2]) };
+};
+
+/**
+ * Check whether a Go version declares loop variables once per iteration.
+ * @param {string} version - Go version
+ * @returns {boolean} True for Go 1.22 and later
+ */
+const has_per_iteration_loopvars = (version) => {
+  const parsed = parse_go_version(version);
+  if (!parsed) return false;
+  return parsed.major > 1 || parsed.minor >= LOOPVAR_FIX_VERSION;
+};
+
+/**
+ * Get the Go version a file requires with its build constraint
+ * (`//go:build go1.22`, alone or in a conjunction).  The toolchain
+ * compiles such files with the semantics of that version.
+ * @param {Object} constraints - Build constraints (from parse_build_constraints)
+ * @returns {string|null} Go version, or null if none is required
+ */
+const get_constraint_go_version = (constraints) => {
+  const expression = constraints && constraints.go_build;
+  if (!expression || /\|\||[()!]/.test(expression)) return null;
+  const term = expression
+    .split('&&')
+    .map((t) => t.trim())
+    .find((t) => /^go1\.\d+$/.test(t));
+  return term ? term.substring(2) : null;
+};
+
+// ============================================================================
+// LOOPS AND CLOSURES
+// ============================================================================
+
+/**
+ * Find the loops of a masked function body declaring variables: range
+ * loops (`for k, v := range`) and three-clause loops (`for i := 0; ...`).
+ * Loops assigning to existing variables (`for k = range`) declare none.
+ * @param {string} masked - Masked function body
+ * @returns {Object[]} Loops with kind, variables, offset and body range
+ */
+const find_variable_loops = (masked) => {
+  const loops = [];
+  const pattern = /\bfor\b/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    // The loop body opens at the first brace outside of brackets
+    let depth = 0;
+    let body_open = -1;
+    for (let i = pattern.lastIndex; i < masked.length; i++) {
+      const ch = masked[i];
+      if (ch === '(' || ch === '[') depth++;
+      else if (ch === ')' || ch === ']') depth--;
+      else if (ch === '{' && depth === 0) {
+        body_open = i;
+        break;
+      }
+    }
+    if (body_open === -1) continue;
+    const body_close = find_matching(masked, body_open);
+    if (body_close === -1) continue;
+
+    const clause = masked.substring(pattern.lastIndex, body_open);
+    let kind = 'range';
+    let names = [];
+    const range = clause.match(/^\s*(\w+(?:\s*,\s*\w+)?)\s*:=\s*range\b/);
+    if (range) {
+      names = range[1].split(',');
+    } else {
+      const semicolon = clause.indexOf(';');
+      const init =
+        semicolon !== -1 &&
+        clause
+          .substring(0, semicolon)
+          .match(/^\s*(\w+(?:\s*,\s*\w+)*)\s*:=/);
+      if (!init) continue;
+      kind = 'for';
+      names = init[1].split(',');
+    }
+
+    names = names.map((n) => n.trim()).filter((n) => n && n !== '_');
+    if (names.length === 0) continue;
+    loops.push({
+      kind,
+      variables: names,
+      offset: match.index,
+      body_start: body_open,
+      body_end: body_close + 1
+    });
+  }
+
+  return loops;
+};
+
+/**
+ * Find the function literals launched as goroutines in a function body.
+ * @param {string} body - Function body
+ * @param {string} masked - Masked function body
+ * @returns {Object[]} Launches with kind, offset, parameter names and body range
+ */
+const find_goroutine_literals = (body, masked) => {
+  const launches = [];
+
+  for (const { kind, pattern } of LAUNCH_PATTERNS) {
+    pattern.lastIndex = 0;
+    let match;
+    while ((match = pattern.exec(masked)) !== null) {
+      const params_open = pattern.lastIndex - 1;
+      const params_close = find_matching(masked, params_open);
+      if (params_close === -1) continue;
+      const body_open = masked.indexOf('{', params_close);
+      const body_close =
+        body_open === -1 ? -1 : find_matching(masked, body_open);
+      if (body_close === -1) continue;
+
+      const params = parse_parameters(
+        body.substring(params_open + 1, params_close)
+      );
+      launches.push({
+        kind,
+        offset: match.index,
+        params: params.map((p) => p.name).filter(Boolean),
+        body_start: body_open,
+        body_end: body_close + 1
+      });
+    }
+  }
+
+  return launches.sort((a, b) => a.offset - b.offset);
+};
+
+/**
+ * Build a pattern matching a declaration of a variable with `:=`, alone or
+ * among others (`v := v`, `v, err :=`).
+ * @param {string} name - Variable name
+ * @returns {RegExp} Pattern
+ */
+const get_declaration_pattern = (name) => {
+  return new RegExp(
+    `(?<![\\w.])(?:\\w+\\s*,\\s*)*${name}(?:\\s*,\\s*\\w+)*\\s*:=`
+  );
+};
+
+/**
+ * Find the first use of a variable in a range of masked source, ignoring
+ * selectors of the same name (`x.v`) and redeclarations (`v :=`).
+ * @param {string} masked - Masked source
+ * @param {string} name - Variable name
+ * @param {number} start - Start offset
+ * @param {number} end - End offset
+ * @returns {number} Offset of the first use, or -1
+ */
+const find_first_use = (masked, name, start, end) => {
+  const pattern = new RegExp(`(?<![\\w.])${name}(?!\\w)(?!\\s*:=)`, 'g');
+  pattern.lastIndex = start;
+  const match = pattern.exec(masked);
+  return match && match.index < end ? match.index : -1;
+};
+
+// ============================================================================
+// CAPTURES
+// ============================================================================
+
+/**
+ * Find the loop variables captured by goroutine function literals in a
+ * function.  A variable is not captured when it is passed as a parameter
+ * of the literal (`go func(v T) {...}(v)`), copied in the loop body before
+ * the launch (`v := v`) or redeclared in the literal before its first use.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @returns {Object[]} Captures with their line, variable, loop and launch
+ */
+const find_function_loop_captures = (fn) => {
+  if (!fn.body) return [];
+  const masked = mask_source(fn.body);
+  const line_index = build_line_index(fn.body);
+  const get_line = (offset) => fn.body_line + line_at(line_index, offset) - 1;
+  const launches = find_goroutine_literals(fn.body, masked);
+  const captures = [];
+
+  for (const loop of find_variable_loops(masked)) {
+    for (const launch of launches) {
+      if (launch.offset < loop.body_start || launch.offset >= loop.body_end) {
+        continue;
+      }
+
+      for (const name of loop.variables) {
+        if (launch.params.includes(name)) continue;
+        const declaration = get_declaration_pattern(name);
+        const before = masked.substring(loop.body_start + 1, launch.offset);
+        if (declaration.test(before)) continue;
+
+        const use = find_first_use(
+          masked,
+          name,
+          launch.body_start,
+          launch.body_end
+        );
+        if (use === -1) continue;
+        if (declaration.test(masked.substring(launch.body_start, use))) {
+          continue;
+        }
+
+        const launcher = launch.kind === 'go' ? 'goroutine' : 'group goroutine';
+        captures.push({
+          line: get_line(use),
+          variable: name,
+          loop_kind: loop.kind,
+          loop_line: get_line(loop.offset),
+          launch: launch.kind,
+          launch_line: get_line(launch.offset),
+          message: `${launcher} captures loop variable ${name}; before Go 1.22 all iterations share ${name}, so the goroutine may see the value of a later iteration`,
+          suggestion: `pass ${name} as an argument of the function literal or copy it before launching (${name} := ${name}); fixed by Go 1.22`
+        });
+      }
+    }
+  }
+
+  return captures.sort(function sort_by_line(a, b) {
+    return a.line - b.line || a.variable.localeCompare(b.variable);
+  });
+};
+
+/**
+ * Find the loop variables captured by goroutines in a set of packages.
+ * Captures in files compiled with Go 1.22 semantics, because of the
+ * target version or of a build constraint, are marked with what fixes
+ * them and left out unless asked for.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string} [options.go_version] - Target Go version (the module's go directive)
+ * @param {boolean} [options.include_fixed=false] - Include captures fixed by the Go version
+ * @returns {Object[]} Captures with their function, by file and line
+ */
+const find_loop_captures = (packages, options = {}) => {
+  const target_fixed = has_per_iteration_loopvars(options.go_version);
+  const captures = [];
+
+  for (const pkg of packages) {
+    const constraints = new Map(
+      pkg.files.map((f) => [f.filename, f.build_constraints])
+    );
+
+    for (const fn of pkg.functions) {
+      const file_version = get_constraint_go_version(
+        constraints.get(fn.filename)
+      );
+      let fixed_by = null;
+      if (target_fixed) fixed_by = 'go_version';
+      else if (has_per_iteration_loopvars(file_version)) {
+        fixed_by = 'build_constraint';
+      }
+      if (fixed_by && !options.include_fixed) continue;
+
+      const receiver = fn.receiver ? get_base_type(fn.receiver.type) : null;
+      for (const capture of find_function_loop_captures(fn)) {
+        captures.push({
+          function: receiver ? `${receiver}.${fn.name}` : fn.name,
+          package: pkg.name,
+          directory: pkg.directory,
+          filename: fn.filename,
+          severity: fixed_by ? 'info' : 'warning',
+          fixed_by,
+          ...capture
+        });
+      }
+    }
+  }
+
+  return captures.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Report the loop variables captured by goroutines in a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_loop_captures)
+ * @returns {Promise<Object>} Captures and a summary
+ */
+const analyze_project_loop_captures = async (project_id, options = {}) => {
+  const packages = await load_go_packages(project_id);
+  const all = find_loop_captures(packages, { ...options, include_fixed: true });
+  const captures = options.include_fixed ? all : all.filter((c) => !c.fixed_by);
+
+  return {
+    captures,
+    summary: {
+      total_captures: captures.length,
+      fixed_by_go_version: all.filter((c) => c.fixed_by).length,
+      functions: new Set(captures.map((c) => `${c.directory}.${c.function}`))
+        .size,
+      go_version: options.go_version || null
+    }
+  };
+};
+
+export {
+  analyze_project_loop_captures,
+  find_loop_captures,
+  find_function_loop_captures,
+  find_variable_loops,
+  get_constraint_go_version,
+  has_per_iteration_loopvars,
+  parse_go_version,
+  LOOPVAR_FIX_VERSION
+};
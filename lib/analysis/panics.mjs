@@ -0,0 +1,368 @@
+'use strict';
+
+/**
+ * @fileoverview Go panic reachability module.
+ * Finds the panic sites of Go functions and reports which exported
+ * functions can reach one through the call graph, along with the shortest
+ * call path.  Functions that recover from panics in a deferred call stop
+ * the search.  Calls through interfaces are resolved to every project type
+ * implementing the interface; calls of function values cannot be resolved
+ * and are counted instead.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/panics
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  is_exported,
+  get_methods_by_type,
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { find_call_edges, find_implements_edges } from './graph.mjs';
+
+/**
+ * Default maximum call depth searched from each exported function.
+ */
+const DEFAULT_PANIC_DEPTH = 10;
+
+/**
+ * Calls that panic.
+ */
+const PANIC_PATTERN = /(?<![\w.])(panic|log\.Panic(?:f|ln)?)\s*\(/g;
+
+/**
+ * A call of recover().
+ */
+const RECOVER_PATTERN = /\brecover\s*\(\s*\)/;
+
+// ============================================================================
+// PANIC SITES
+// ============================================================================
+
+/**
+ * Find the panic calls in a function body.
+ * @param {string} body - Function body
+ * @param {number} [body_line=1] - Line of the start of the body
+ * @returns {Object[]} Panic sites with line and call text
+ */
+const find_panic_sites = (body, body_line = 1) => {
+  const masked = mask_source(body || '');
+  const line_index = build_line_index(body || '');
+  const sites = [];
+  let match;
+
+  PANIC_PATTERN.lastIndex = 0;
+  while ((match = PANIC_PATTERN.exec(masked)) !== null) {
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    const end = close === -1 ? masked.indexOf('\n', open) : close + 1;
+    sites.push({
+      line: body_line + line_at(line_index, match.index) - 1,
+      text: body.substring(match.index, end === -1 ? body.length : end)
+    });
+  }
+
+  return sites;
+};
+
+/**
+ * Check whether a function recovers from panics.
+ * A function recovers when it defers a function literal that calls
+ * recover(), or defers a call of a package function that does.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {Set<string>} [recovering_helpers] - Package functions that call recover()
+ * @returns {boolean} True if panics below the function are recovered
+ */
+const has_deferred_recover = (fn, recovering_helpers = new Set()) => {
+  const masked = mask_source(fn.body || '');
+  const pattern = /\bdefer\s+(?:func\s*\(\s*\)\s*(\{)|(\w+)\s*\()/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    if (match[2]) {
+      if (recovering_helpers.has(match[2])) return true;
+      continue;
+    }
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    const literal = masked.substring(open, close === -1 ? undefined : close);
+    if (RECOVER_PATTERN.test(literal)) return true;
+  }
+
+  return false;
+};
+
+/**
+ * Check whether a function is part of a package's exported API.
+ * Methods are only exported when their receiver type is too.  Test
+ * files and main packages are not part of the API.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @param {Object} pkg - Package containing the function
+ * @returns {boolean} True for exported API functions
+ */
+const is_api_function = (fn, pkg) => {
+  if (pkg.name === 'main' || /_test\.go$/.test(fn.filename)) return false;
+  if (!is_exported(fn.name)) return false;
+  return !fn.receiver || is_exported(fn.receiver.type);
+};
+
+// ============================================================================
+// REACHABILITY
+// ============================================================================
+
+/**
+ * Build the call model used for panic reachability.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Map<string, Object>} Functions by node ID with panics, recovery and calls
+ */
+const build_panic_model = (packages) => {
+  const functions = new Map();
+
+  // Interface method -> implementing methods (including promoted ones)
+  const implementations = new Map();
+  const by_directory = new Map(packages.map((p) => [p.directory, p]));
+  const method_sets = new Map();
+  for (const edge of find_implements_edges(packages)) {
+    const [directory, type] = edge.source.split(':');
+    const pkg = by_directory.get(directory);
+    if (!method_sets.has(pkg)) {
+      const methods = get_methods_by_type(pkg, { include_promoted: true });
+      method_sets.set(pkg, methods);
+    }
+    for (const method of method_sets.get(pkg)[type] || []) {
+      const owner = method.promoted_from || type;
+      const key = `${edge.target}.${method.name}`;
+      if (!implementations.has(key)) implementations.set(key, []);
+      implementations.get(key).push(`${directory}:${owner}.${method.name}`);
+    }
+  }
+
+  for (const pkg of packages) {
+    const recovering_helpers = new Set(
+      pkg.functions
+        .filter((f) => !f.receiver && RECOVER_PATTERN.test(f.body || ''))
+        .map((f) => f.name)
+    );
+
+    for (const fn of pkg.functions) {
+      const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      const id = `${pkg.directory}:${name}`;
+      const calls = [];
+      let indirect_calls = 0;
+
+      const edges = find_call_edges(fn, pkg, packages, {
+        interface_calls: true,
+        indirect_calls: true
+      });
+      for (const edge of edges) {
+        if (edge.indirect) {
+          indirect_calls++;
+        } else if (edge.interface) {
+          const targets = implementations.get(edge.target) || [];
+          for (const target of targets) {
+            calls.push({ target, line: edge.line, via: edge.interface });
+          }
+        } else {
+          calls.push({ target: edge.target, line: edge.line });
+        }
+      }
+
+      functions.set(id, {
+        id,
+        name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        api: is_api_function(fn, pkg),
+        panics: find_panic_sites(fn.body, fn.body_line),
+        recovers: has_deferred_recover(fn, recovering_helpers),
+        indirect_calls,
+        calls
+      });
+    }
+  }
+
+  return functions;
+};
+
+/**
+ * Search the call graph from an exported function for panic sites.
+ * The search is breadth first, so the reported path is a shortest one.
+ * Recovering functions are not entered.
+ * @param {Map<string, Object>} functions - Call model (from build_panic_model)
+ * @param {string} start - Node ID of the starting function
+ * @param {number} max_depth - Maximum call depth
+ * @returns {Object|null} Reachability with path, panic site and counts, or null if the start recovers
+ */
+const search_panics = (functions, start, max_depth) => {
+  const root = functions.get(start);
+  if (!root || root.recovers) return null;
+
+  const previous = new Map([[start, null]]);
+  const queue = [{ id: start, depth: 0 }];
+  let nearest = null;
+  let panic_sites = 0;
+  let indirect_calls = 0;
+
+  while (queue.length > 0) {
+    const { id, depth } = queue.shift();
+    const fn = functions.get(id);
+    indirect_calls += fn.indirect_calls;
+
+    if (fn.panics.length > 0) {
+      panic_sites += fn.panics.length;
+      if (!nearest) nearest = { id, depth };
+    }
+    if (depth >= max_depth) continue;
+
+    for (const call of fn.calls) {
+      const callee = functions.get(call.target);
+      if (!callee || callee.recovers || previous.has(call.target)) continue;
+      previous.set(call.target, { from: id, line: call.line, via: call.via });
+      queue.push({ id: call.target, depth: depth + 1 });
+    }
+  }
+
+  if (!nearest) {
+    return {
+      path: null,
+      panic: null,
+      depth: null,
+      panic_sites,
+      indirect_calls
+    };
+  }
+
+  const path = [];
+  for (let id = nearest.id; id !== null; ) {
+    const fn = functions.get(id);
+    const step = previous.get(id);
+    path.unshift({
+      function: fn.name,
+      filename: fn.filename,
+      line: fn.line,
+      call_line: step ? step.line : null,
+      via: step && step.via ? step.via.split(':')[1] : null
+    });
+    id = step ? step.from : null;
+  }
+
+  const target = functions.get(nearest.id);
+  return {
+    path,
+    panic: {
+      function: target.name,
+      filename: target.filename,
+      ...target.panics[0]
+    },
+    depth: nearest.depth,
+    panic_sites,
+    indirect_calls
+  };
+};
+
+/**
+ * Find the exported functions of a set of packages that can reach a panic.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {number} [options.max_depth=10] - Maximum call depth to search
+ * @returns {Object} Functions reaching a panic, unresolved functions and a summary
+ */
+const find_reachable_panics = (packages, options = {}) => {
+  const max_depth = options.max_depth || DEFAULT_PANIC_DEPTH;
+  const model = build_panic_model(packages);
+  const api = [...model.values()].filter((fn) => fn.api);
+  const results = [];
+  const unresolved = [];
+
+  for (const fn of api) {
+    const reach = search_panics(model, fn.id, max_depth);
+    if (!reach) continue;
+    if (!reach.panic) {
+      // Function values could call anything, including code that panics
+      if (reach.indirect_calls > 0) {
+        unresolved.push({
+          function: fn.name,
+          package: fn.package,
+          filename: fn.filename,
+          line: fn.line,
+          indirect_calls: reach.indirect_calls
+        });
+      }
+      continue;
+    }
+    results.push({
+      function: fn.name,
+      package: fn.package,
+      directory: fn.directory,
+      filename: fn.filename,
+      line: fn.line,
+      direct: reach.depth === 0,
+      ...reach
+    });
+  }
+
+  results.sort(function sort_by_depth(a, b) {
+    if (a.depth !== b.depth) return a.depth - b.depth;
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+
+  const all = [...model.values()];
+  return {
+    max_depth,
+    functions: results,
+    unresolved,
+    summary: {
+      exported_functions: api.length,
+      functions_reaching_panic: results.length,
+      direct_panics: results.filter((r) => r.direct).length,
+      panic_sites: all.reduce((sum, fn) => sum + fn.panics.length, 0),
+      recovering_functions: all.filter((fn) => fn.recovers).length,
+      with_indirect_calls: results.filter((r) => r.indirect_calls > 0).length
+    }
+  };
+};
+
+/**
+ * Find the exported functions that can reach a panic in a set of Go
+ * sources, such as the files of a directory on disk.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_reachable_panics)
+ * @returns {Object} Functions reaching a panic, unresolved functions and a summary
+ */
+const find_source_panics = (sources, options = {}) => {
+  const files = sources.map(function parse_source(file) {
+    return parse_go_file(file.source, file.filename);
+  });
+  return find_reachable_panics(group_go_packages(files), options);
+};
+
+/**
+ * Find the exported functions of a project that can reach a panic.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {number} [options.max_depth=10] - Maximum call depth to search
+ * @returns {Promise<Object>} Exported functions reaching a panic, with a summary
+ */
+const analyze_project_panics = async (project_id, options = {}) => {
+  return find_reachable_panics(await load_go_packages(project_id), options);
+};
+
+export {
+  analyze_project_panics,
+  find_reachable_panics,
+  find_source_panics,
+  build_panic_model,
+  search_panics,
+  find_panic_sites,
+  has_deferred_recover,
+  is_api_function,
+  DEFAULT_PANIC_DEPTH
+};
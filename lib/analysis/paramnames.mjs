@@ -0,0 +1,272 @@
+'use strict';
+
+/**
+ * @fileoverview Go parameter naming module.
+ * Flags the exported functions and methods whose parameters have names
+ * that documentation cannot refer to: doc tooling and doc comments name
+ * parameters ("Add adds n to the total"), which needs stable, meaningful
+ * names.  A parameter name is weak when it is:
+ * - unnamed: the parameter list has no names (`Add(int, int)`)
+ * - blank: the parameter is named `_`
+ * - numbered: a placeholder with a number (`arg0`, `p1`, `a2`)
+ * - short: a single letter (`Add(a, b int)`), a borderline case
+ * Idiomatic short names are accepted: a configurable list (i, n, err,
+ * ctx...) and single letters for their conventional types (`w
+ * http.ResponseWriter`, `r *http.Request`, `t *testing.T`, `p []byte`).
+ * Receivers are never flagged: short receiver names are Go style.
+ *
+ * Exported functions, the exported methods of exported types and the
+ * methods of exported interfaces are checked.  Test files are left out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/paramnames
+ */
+
+import {
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { format_method_signature } from './interfaces.mjs';
+
+/**
+ * Reasons a parameter name is weak.
+ */
+const PARAM_NAME_REASONS = ['unnamed', 'blank', 'numbered', 'short'];
+
+/**
+ * Short parameter names accepted by default, whatever their type.
+ */
+const DEFAULT_ACCEPTABLE_NAMES = [
+  'i',
+  'j',
+  'k',
+  'n',
+  'ok',
+  'err',
+  'ctx',
+  'id',
+  'fn',
+  'db',
+  'tx'
+];
+
+/**
+ * Single letter names accepted for their conventional types.
+ */
+const IDIOMATIC_TYPED_NAMES = new Map([
+  ['w', ['io.Writer', 'http.ResponseWriter', '*bufio.Writer']],
+  ['r', ['io.Reader', '*http.Request', '*bufio.Reader']],
+  ['t', ['*testing.T', 'testing.TB']],
+  ['b', ['*testing.B', '[]byte', 'byte', '*bytes.Buffer']],
+  ['f', ['*testing.F', '*os.File']],
+  ['m', ['*testing.M']],
+  ['p', ['[]byte']],
+  ['s', ['string']],
+  ['c', ['rune', 'byte']],
+  ['x', ['float64', 'float32']],
+  ['y', ['float64', 'float32']]
+]);
+
+/**
+ * Placeholder names with a number.
+ */
+const NUMBERED_NAME_PATTERN = /^(arg|param|p|a|v|x|in)\d+$/;
+
+// ============================================================================
+// PARAMETER NAMES
+// ============================================================================
+
+/**
+ * Classify the name of a parameter.
+ * @param {Object} param - Parameter (from parse_parameters)
+ * @param {Set<string>} acceptable - Short names accepted whatever their type
+ * @returns {string|null} Reason the name is weak, or null if it is not
+ */
+const classify_param_name = (param, acceptable) => {
+  const { name } = param;
+  if (!name) return 'unnamed';
+  if (name === '_') return 'blank';
+  if (acceptable.has(name)) return null;
+  if (NUMBERED_NAME_PATTERN.test(name)) return 'numbered';
+  if (name.length > 1) return null;
+  const types = IDIOMATIC_TYPED_NAMES.get(name);
+  if (types && types.includes(param.type)) return null;
+  return 'short';
+};
+
+/**
+ * Find the weak parameter names of a function or method.
+ * @param {Object} fn - Function or method (from the Go parser)
+ * @param {Set<string>} acceptable - Short names accepted whatever their type
+ * @returns {Object[]} Weak parameters with name, position, type and reason
+ */
+const find_weak_params = (fn, acceptable) => {
+  const weak = [];
+  fn.params.forEach((param, position) => {
+    const reason = classify_param_name(param, acceptable);
+    if (!reason) return;
+    weak.push({
+      name: param.name,
+      position,
+      type: param.variadic ? `...${param.type}` : param.type,
+      reason
+    });
+  });
+  return weak;
+};
+
+/**
+ * List the exported functions and methods of a package whose parameters
+ * document them: functions, methods of exported types and methods of
+ * exported interfaces, outside test files.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object[]} Entries with kind, symbol, filename, line and function
+ */
+const list_documented_functions = (pkg) => {
+  const exported_types = new Set(
+    pkg.types.filter((t) => t.exported).map((t) => t.name)
+  );
+  const entries = [];
+  for (const fn of pkg.functions) {
+    if (!fn.exported || fn.filename.endsWith('_test.go')) continue;
+    if (fn.receiver && !exported_types.has(fn.receiver.type)) continue;
+    entries.push({
+      kind: fn.receiver ? 'method' : 'function',
+      symbol: fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name,
+      filename: fn.filename,
+      line: fn.line,
+      fn
+    });
+  }
+  for (const type of pkg.types) {
+    if (type.kind !== 'interface' || !type.exported) continue;
+    if (type.filename.endsWith('_test.go')) continue;
+    for (const method of type.methods) {
+      entries.push({
+        kind: 'interface_method',
+        symbol: `${type.name}.${method.name}`,
+        filename: type.filename,
+        line: method.line || type.line,
+        fn: method
+      });
+    }
+  }
+  return entries;
+};
+
+/**
+ * Find the exported functions and methods of a set of packages with weak
+ * parameter names (see the module doc).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.acceptable] - Short names accepted whatever their type, replacing the default list
+ * @param {string[]} [options.reasons] - Only report these reasons (e.g. unnamed)
+ * @returns {Object} Functions with weak parameters by file and line, and the number of functions and parameters checked
+ */
+const find_weak_param_names = (packages, options = {}) => {
+  const acceptable = new Set(options.acceptable || DEFAULT_ACCEPTABLE_NAMES);
+  const reasons = new Set(options.reasons || PARAM_NAME_REASONS);
+  for (const reason of reasons) {
+    if (!PARAM_NAME_REASONS.includes(reason)) {
+      throw new Error(
+        `Unknown reason '${reason}', expected one of ${PARAM_NAME_REASONS.join(', ')}`
+      );
+    }
+  }
+
+  const functions = [];
+  let checked = 0;
+  let params = 0;
+  for (const pkg of packages) {
+    for (const entry of list_documented_functions(pkg)) {
+      checked++;
+      params += entry.fn.params.length;
+      const weak = find_weak_params(entry.fn, acceptable).filter((p) =>
+        reasons.has(p.reason)
+      );
+      if (weak.length === 0) continue;
+      const names = weak.map(
+        (p) => `${p.name || `#${p.position + 1}`} (${p.reason})`
+      );
+      functions.push({
+        function: entry.symbol,
+        kind: entry.kind,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: entry.filename,
+        line: entry.line,
+        signature: format_method_signature(entry.fn),
+        weak_params: weak,
+        message: `${entry.symbol} has weak parameter names: ${names.join(', ')}`
+      });
+    }
+  }
+
+  return {
+    functions: functions.sort(function sort_by_location(a, b) {
+      if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+      return a.line - b.line;
+    }),
+    checked,
+    params
+  };
+};
+
+/**
+ * Summarize a parameter naming report.
+ * @param {Object} report - Report (from find_weak_param_names)
+ * @returns {Object} Numbers of functions and parameters checked, flagged, and weak parameters by reason
+ */
+const summarize_param_names = (report) => {
+  const by_reason = Object.fromEntries(PARAM_NAME_REASONS.map((r) => [r, 0]));
+  for (const fn of report.functions) {
+    for (const param of fn.weak_params) by_reason[param.reason]++;
+  }
+  return {
+    functions: report.checked,
+    flagged: report.functions.length,
+    params: report.params,
+    weak_params: Object.values(by_reason).reduce((sum, n) => sum + n, 0),
+    by_reason
+  };
+};
+
+/**
+ * Report the weak parameter names of a set of Go sources, such as the
+ * files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_weak_param_names)
+ * @returns {Object} Functions with weak parameters and a summary
+ */
+const find_source_param_names = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const report = find_weak_param_names(group_go_packages(files), options);
+  return { functions: report.functions, summary: summarize_param_names(report) };
+};
+
+/**
+ * Report the weak parameter names of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_weak_param_names)
+ * @returns {Promise<Object>} Functions with weak parameters and a summary
+ */
+const analyze_project_param_names = async (project_id, options = {}) => {
+  const report = find_weak_param_names(
+    await load_go_packages(project_id),
+    options
+  );
+  return { functions: report.functions, summary: summarize_param_names(report) };
+};
+
+export {
+  analyze_project_param_names,
+  find_source_param_names,
+  find_weak_param_names,
+  summarize_param_names,
+  classify_param_name,
+  list_documented_functions,
+  DEFAULT_ACCEPTABLE_NAMES,
+  PARAM_NAME_REASONS
+};
@@ -0,0 +1,216 @@
+'use strict';
+
+/**
+ * @fileoverview Go predicate naming module.
+ * Flags the exported functions and methods returning a single `bool`
+ * whose names do not read as predicates: a call such as
+ * `if c.IsEmpty()` reads as a question, `if c.Empty()` may as well
+ * empty the config.  A name reads as a predicate when it starts with a
+ * predicate prefix, Is, Has, Can or Should by default, followed by a word
+ * (`IsEmpty`, not `Issue`).  Idiomatic names of the standard library
+ * (`Less` of sort.Interface, `Next` of iterators...) are accepted.
+ *
+ * Each flagged function comes with a predicate-style rename: `Allow` and
+ * `Permit` become Can (`AllowWrite` → `CanWrite`), `Need` and `Require`
+ * become Should, `KeyExists` becomes `HasKey`, a leading `Get`, `Check`,
+ * `Test` or `Verify` becomes Is, and other names are prefixed with Is
+ * (`Empty` → `IsEmpty`).
+ *
+ * Only signatures with a single bool result are checked, named or not:
+ * comma-ok results (`(string, bool)`) and `(bool, error)` are not
+ * predicates.  Exported functions, the exported methods of exported types
+ * and the methods of exported interfaces are checked.  Test files are
+ * left out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/predicates
+ */
+
+import {
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { format_method_signature } from './interfaces.mjs';
+import { list_documented_functions } from './paramnames.mjs';
+
+/**
+ * Prefixes of predicate names accepted by default.
+ */
+const DEFAULT_PREDICATE_PREFIXES = ['Is', 'Has', 'Can', 'Should'];
+
+/**
+ * Boolean function names accepted by default, idiomatic in Go.
+ */
+const DEFAULT_ACCEPTABLE_PREDICATES = [
+  'Less',
+  'Next',
+  'Equal',
+  'Contains',
+  'Match',
+  'Exists'
+];
+
+/**
+ * Rewrites of names into predicates, tried in order.
+ */
+const PREDICATE_REWRITES = [
+  [/^(?:Allows?|Permits?)(?=[A-Z])/, 'Can'],
+  [/^(?:Needs?|Requires?)(?=[A-Z])/, 'Should'],
+  [/^(?:Get|Check|Test|Verify)(?=[A-Z])/, 'Is']
+];
+
+// ============================================================================
+// NAMES
+// ============================================================================
+
+/**
+ * Tell whether a name reads as a predicate: it starts with one of the
+ * prefixes followed by a word, a digit or nothing more.
+ * @param {string} name - Function or method name
+ * @param {string[]} prefixes - Predicate prefixes
+ * @returns {boolean} True if the name reads as a predicate
+ */
+const is_predicate_name = (name, prefixes) => {
+  return prefixes.some((prefix) => {
+    if (!name.startsWith(prefix)) return false;
+    const next = name[prefix.length];
+    return next === undefined || /[A-Z0-9_]/.test(next);
+  });
+};
+
+/**
+ * Suggest a predicate-style rename of a function name (see the module
+ * doc).
+ * @param {string} name - Function or method name
+ * @returns {string} Suggested name
+ */
+const suggest_predicate_name = (name) => {
+  const exists = name.match(/^([A-Z]\w*?)Exists$/);
+  if (exists) return `Has${exists[1]}`;
+  for (const [pattern, prefix] of PREDICATE_REWRITES) {
+    if (pattern.test(name)) return name.replace(pattern, prefix);
+  }
+  return `Is${name}`;
+};
+
+/**
+ * Tell whether a function or method returns a single bool.
+ * @param {Object} fn - Function or method (from the Go parser)
+ * @returns {boolean} True if the only result is a bool
+ */
+const returns_single_bool = (fn) => {
+  return fn.results.length === 1 && fn.results[0].type === 'bool';
+};
+
+// ============================================================================
+// BOOLEAN FUNCTIONS
+// ============================================================================
+
+/**
+ * Find the exported functions and methods of a set of packages returning
+ * a bool without a predicate name (see the module doc).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.prefixes] - Predicate prefixes, replacing the default list
+ * @param {string[]} [options.acceptable] - Names accepted whatever their prefix, replacing the default list
+ * @returns {Object} Functions to rename by file and line, and the number of bool functions checked
+ */
+const find_predicate_names = (packages, options = {}) => {
+  const prefixes = options.prefixes || DEFAULT_PREDICATE_PREFIXES;
+  const acceptable = new Set(
+    options.acceptable || DEFAULT_ACCEPTABLE_PREDICATES
+  );
+
+  const functions = [];
+  let checked = 0;
+  for (const pkg of packages) {
+    for (const entry of list_documented_functions(pkg)) {
+      if (!returns_single_bool(entry.fn)) continue;
+      checked++;
+      const { name } = entry.fn;
+      if (acceptable.has(name) || is_predicate_name(name, prefixes)) continue;
+      const suggestion = suggest_predicate_name(name);
+      functions.push({
+        function: entry.symbol,
+        kind: entry.kind,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: entry.filename,
+        line: entry.line,
+        signature: format_method_signature(entry.fn),
+        suggestion,
+        message: `${entry.symbol} returns a bool but does not read as a predicate; consider renaming it ${suggestion}`
+      });
+    }
+  }
+
+  return {
+    functions: functions.sort(function sort_by_location(a, b) {
+      if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+      return a.line - b.line;
+    }),
+    checked
+  };
+};
+
+/**
+ * Summarize a predicate naming report.
+ * @param {Object} report - Report (from find_predicate_names)
+ * @returns {Object} Numbers of bool functions checked, flagged and well named, and flagged by kind
+ */
+const summarize_predicate_names = (report) => {
+  const by_kind = { function: 0, method: 0, interface_method: 0 };
+  for (const fn of report.functions) by_kind[fn.kind]++;
+  return {
+    functions: report.checked,
+    flagged: report.functions.length,
+    well_named: report.checked - report.functions.length,
+    by_kind
+  };
+};
+
+/**
+ * Report the bool functions without predicate names of a set of Go
+ * sources, such as the files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_predicate_names)
+ * @returns {Object} Functions to rename and a summary
+ */
+const find_source_predicate_names = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const report = find_predicate_names(group_go_packages(files), options);
+  return {
+    functions: report.functions,
+    summary: summarize_predicate_names(report)
+  };
+};
+
+/**
+ * Report the bool functions without predicate names of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_predicate_names)
+ * @returns {Promise<Object>} Functions to rename and a summary
+ */
+const analyze_project_predicate_names = async (project_id, options = {}) => {
+  const report = find_predicate_names(
+    await load_go_packages(project_id),
+    options
+  );
+  return {
+    functions: report.functions,
+    summary: summarize_predicate_names(report)
+  };
+};
+
+export {
+  analyze_project_predicate_names,
+  find_source_predicate_names,
+  find_predicate_names,
+  summarize_predicate_names,
+  is_predicate_name,
+  suggest_predicate_name,
+  DEFAULT_PREDICATE_PREFIXES,
+  DEFAULT_ACCEPTABLE_PREDICATES
+};
@@ -0,0 +1,651 @@
+'use strict';
+
+/**
+ * @fileoverview Go to protobuf schema module.
+ * Generates a proto3 definition (.proto) from the structs of a Go package
+ * tagged for serialization, to keep Go structs and proto schemas in sync.
+ * Each struct becomes a message and each exported field a message field;
+ * integer types with typed constants (`type Color int` with
+ * `Red Color = iota`) become enums.
+ *
+ * Type mapping rules:
+ * - bool, string: bool, string
+ * - int, int64: int64; int8, int16, int32, rune: int32
+ * - uint, uint64, uintptr: uint64; uint8, byte, uint16, uint32: uint32
+ * - float32: float; float64: double; []byte: bytes
+ * - time.Time, time.Duration: google.protobuf.Timestamp, Duration
+ * - interface{}, any: google.protobuf.Any
+ * - *T: T, optional for scalars and enums (proto3 field presence)
+ * - []T, [N]T: repeated T
+ * - map[K]V: map<K, V>, for integer, bool and string keys
+ * - struct types of the package: messages, declared alongside; anonymous
+ *   struct fields: messages nested in their parent message
+ * - enum types: enums, with a zero `<ENUM>_UNSPECIFIED` value added when
+ *   no constant is zero, as proto3 requires
+ * - other named types: the mapping of their underlying type
+ * Fields of other types (channels, functions, types of other packages)
+ * are skipped with a comment and a warning.
+ *
+ * Field numbers come from a protobuf tag, either as generated by
+ * protoc-gen-go (`protobuf:"varint,1,opt,name=id"`) or alone
+ * (`protobuf:"1"`); other fields are numbered in declaration order with
+ * the numbers not taken by tags.  Field names come from the json tag,
+ * or are the Go names in snake_case.  A `-` tag (protobuf or json)
+ * leaves the field out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/protobuf
+ */
+
+import {
+  find_matching,
+  get_comment_text,
+  parse_struct_type_fields,
+  build_constant_resolver,
+  parse_go_file,
+  group_go_packages,
+  load_go_sources
+} from './golang.mjs';
+import { parse_struct_tag } from './structs.mjs';
+
+/**
+ * Proto scalar types of the Go basic types.
+ */
+const PROTO_SCALAR_TYPES = {
+  bool: 'bool',
+  string: 'string',
+  int: 'int64',
+  int8: 'int32',
+  int16: 'int32',
+  int32: 'int32',
+  rune: 'int32',
+  int64: 'int64',
+  uint: 'uint64',
+  uint8: 'uint32',
+  byte: 'uint32',
+  uint16: 'uint32',
+  uint32: 'uint32',
+  uint64: 'uint64',
+  uintptr: 'uint64',
+  float32: 'float',
+  float64: 'double',
+  '[]byte': 'bytes'
+};
+
+/**
+ * Well known proto types of standard library types, with their import.
+ */
+const WELL_KNOWN_TYPES = {
+  'time.Time': {
+    type: 'google.protobuf.Timestamp',
+    import: 'google/protobuf/timestamp.proto'
+  },
+  'time.Duration': {
+    type: 'google.protobuf.Duration',
+    import: 'google/protobuf/duration.proto'
+  },
+  'interface{}': {
+    type: 'google.protobuf.Any',
+    import: 'google/protobuf/any.proto'
+  },
+  any: { type: 'google.protobuf.Any', import: 'google/protobuf/any.proto' }
+};
+
+/**
+ * Tag keys marking a struct as serialized.
+ */
+const SERIALIZATION_TAG_KEYS = [
+  'protobuf',
+  'json',
+  'xml',
+  'yaml',
+  'bson',
+  'msgpack'
+];
+
+/**
+ * Go integer types, the underlying types of enums.
+ */
+const INTEGER_TYPES = /^u?int(8|16|32|64)?$|^(byte|rune|uintptr)$/;
+
+/**
+ * Proto key types of maps: integers, bool and string.
+ */
+const MAP_KEY_TYPES = new Set([
+  'int32',
+  'int64',
+  'uint32',
+  'uint64',
+  'bool',
+  'string'
+]);
+
+// ============================================================================
+// NAMES
+// ============================================================================
+
+/**
+ * Convert a Go name to snake_case, keeping acronyms together
+ * (`UserID` is `user_id`, `HTTPServer` is `http_server`).
+ * @param {string} name - Go name
+ * @returns {string} snake_case name
+ */
+const to_snake_case = (name) => {
+  return name
+    .replace(/([A-Z]+)([A-Z][a-z])/g, '$1_$2')
+    .replace(/([a-z0-9])([A-Z])/g, '$1_$2')
+    .toLowerCase();
+};
+
+/**
+ * Name an enum value as proto style guides do: upper snake case, prefixed
+ * by the enum name (`Red` of Color is `COLOR_RED`).  A constant already
+ * prefixed by its type name (`ColorRed`) is not prefixed twice.
+ * @param {string} enum_name - Enum type name
+ * @param {string} name - Constant name
+ * @returns {string} Enum value name
+ */
+const to_enum_value_name = (enum_name, name) => {
+  const rest =
+    name.startsWith(enum_name) && name.length > enum_name.length
+      ? name.substring(enum_name.length)
+      : name;
+  return `${to_snake_case(enum_name)}_${to_snake_case(rest)}`.toUpperCase();
+};
+
+// ============================================================================
+// ENUMS
+// ============================================================================
+
+/**
+ * Find the enums of a package: named integer types with typed constants.
+ * A constant without a type or value in a group repeats the type of the
+ * previous one (`Green` after `Red Color = iota`).  Constants whose value
+ * cannot be evaluated are left out.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Map<string, Object>} Enums with their values by type name
+ */
+const find_go_enums = (pkg) => {
+  const enums = new Map();
+  for (const type of pkg.types) {
+    if (type.kind === 'named' && INTEGER_TYPES.test(type.underlying)) {
+      enums.set(type.name, { type, values: [] });
+    }
+  }
+  if (enums.size === 0) return enums;
+
+  const resolve = build_constant_resolver(pkg.consts);
+  let group = null;
+  let group_type = null;
+  for (const decl of pkg.consts) {
+    const key = decl.grouped ? `${decl.filename}:${decl.group_line}` : null;
+    if (key !== group) group_type = null;
+    group = key;
+    const type = decl.type || (decl.implicit ? group_type : null);
+    group_type = type;
+
+    const found = enums.get(type);
+    if (!found) continue;
+    for (const name of decl.names) {
+      const value = resolve(name);
+      if (name === '_' || value === null) continue;
+      found.values.push({ name, value, line: decl.line, doc: decl.doc });
+    }
+  }
+
+  for (const [name, found] of enums) {
+    if (found.values.length === 0) enums.delete(name);
+  }
+  return enums;
+};
+
+// ============================================================================
+// TYPE MAPPING
+// ============================================================================
+
+/**
+ * Map a Go type expression to a proto type (see the module rules).
+ * @param {string} type_text - Go type expression
+ * @param {Object} context - Package context (from build_proto_context)
+ * @param {Object} [state] - Mapping state
+ * @param {Set<string>} [state.seen] - Named types being mapped
+ * @returns {Object} Proto type with label, referenced message, enum and import, or an unsupported reason
+ */
+const map_proto_type = (type_text, context, state = { seen: new Set() }) => {
+  const text = type_text.trim().replace(/\s+/g, ' ');
+
+  if (PROTO_SCALAR_TYPES[text]) {
+    return { type: PROTO_SCALAR_TYPES[text], scalar: true };
+  }
+  if (WELL_KNOWN_TYPES[text] || text === 'interface {}') {
+    const known = WELL_KNOWN_TYPES[text] || WELL_KNOWN_TYPES['interface{}'];
+    return { type: known.type, import: known.import };
+  }
+
+  if (text.startsWith('*')) {
+    const inner = map_proto_type(text.substring(1), context, state);
+    return inner.scalar || inner.enum ? { ...inner, optional: true } : inner;
+  }
+
+  const slice = text.match(/^\[[^\]]*\]\s*(.+)$/);
+  if (slice) {
+    const inner = map_proto_type(slice[1], context, state);
+    if (inner.unsupported) return inner;
+    if (inner.repeated || inner.map) {
+      return { unsupported: `nested collection ${text}` };
+    }
+    return { ...inner, optional: false, repeated: true };
+  }
+
+  if (text.startsWith('map[')) {
+    const close = find_matching(text, 3);
+    if (close === -1) return { unsupported: text };
+    const key = map_proto_type(text.substring(4, close), context, state);
+    const value = map_proto_type(text.substring(close + 1), context, state);
+    if (key.unsupported || !MAP_KEY_TYPES.has(key.type)) {
+      return { unsupported: `map key ${text.substring(4, close)}` };
+    }
+    if (value.unsupported) return value;
+    if (value.repeated || value.map) {
+      return { unsupported: `nested collection ${text}` };
+    }
+    return {
+      type: `map<${key.type}, ${value.type}>`,
+      map: true,
+      message: value.message,
+      enum: value.enum,
+      import: value.import
+    };
+  }
+
+  if (context.enums.has(text)) return { type: text, enum: text };
+  const declared = context.types.get(text);
+  if (declared && declared.kind === 'struct') {
+    return { type: text, message: text };
+  }
+  if (declared && declared.kind === 'named' && !state.seen.has(text)) {
+    state.seen.add(text);
+    const underlying = map_proto_type(declared.underlying, context, state);
+    state.seen.delete(text);
+    return underlying;
+  }
+
+  return { unsupported: text };
+};
+
+/**
+ * Build the context mapping the types of a package.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object} Types by name and enums
+ */
+const build_proto_context = (pkg) => {
+  return {
+    pkg,
+    types: new Map(pkg.types.map((t) => [t.name, t])),
+    enums: find_go_enums(pkg)
+  };
+};
+
+// ============================================================================
+// MESSAGES
+// ============================================================================
+
+/**
+ * Get the field number of a protobuf tag: the first number of its value
+ * (`varint,1,opt,name=id` or `1`).
+ * @param {Object} tags - Tags by key (from parse_struct_tag)
+ * @returns {number|null} Field number, or null if the tag has none
+ */
+const get_tag_field_number = (tags) => {
+  const tag = tags.get('protobuf');
+  if (!tag) return null;
+  const number = tag.value.split(',').find((part) => /^\d+$/.test(part));
+  return number ? Number(number) : null;
+};
+
+/**
+ * Build the message of a struct: a field per exported field, numbered from
+ * protobuf tags or in declaration order.  Anonymous struct fields become
+ * nested messages named after the field.
+ * @param {string} name - Message name
+ * @param {Object[]} fields - Struct fields (from the Go parser)
+ * @param {Object} context - Package context (from build_proto_context)
+ * @param {string} owner - Go name of the struct, for warnings
+ * @returns {Object} Message with fields, nested messages, references, imports and warnings
+ */
+const build_proto_message = (name, fields, context, owner) => {
+  const message = {
+    name,
+    fields: [],
+    skipped: [],
+    nested: [],
+    messages: new Set(),
+    enums: new Set(),
+    imports: new Set(),
+    warnings: []
+  };
+  const warn = (text) => message.warnings.push(`${owner}: ${text}`);
+  const taken = new Set();
+  const pending = [];
+
+  for (const field of fields) {
+    const { tags } = parse_struct_tag(field.tag);
+    const names = field.embedded
+      ? [field.type.replace(/^\*/, '').trim()]
+      : field.names;
+
+    for (const go_name of names) {
+      if (!/^[A-Z]/.test(go_name)) continue;
+      const proto_tag = tags.get('protobuf');
+      const json_tag = tags.get('json');
+      if (
+        (proto_tag && proto_tag.value === '-') ||
+        (json_tag && json_tag.name === '-')
+      ) {
+        continue;
+      }
+
+      let mapped;
+      if (/^\*?\s*struct\s*\{/.test(field.type)) {
+        const nested_name = go_name;
+        const nested = build_proto_message(
+          nested_name,
+          parse_struct_type_fields(field.type.replace(/^\*/, '')),
+          context,
+          `${owner}.${go_name}`
+        );
+        message.nested.push(nested);
+        mapped = { type: nested_name };
+      } else {
+        mapped = map_proto_type(field.type, context);
+      }
+
+      if (mapped.unsupported) {
+        message.skipped.push({ name: go_name, type: field.type });
+        warn(
+          `skipped field ${go_name}: no proto type for ${mapped.unsupported}`
+        );
+        continue;
+      }
+
+      const number = get_tag_field_number(tags);
+      if (number !== null && taken.has(number)) {
+        warn(`field ${go_name} reuses field number ${number}`);
+      }
+      if (number !== null) taken.add(number);
+      if (mapped.message) message.messages.add(mapped.message);
+      if (mapped.enum) message.enums.add(mapped.enum);
+      if (mapped.import) message.imports.add(mapped.import);
+
+      pending.push({
+        name:
+          json_tag && json_tag.name ? json_tag.name : to_snake_case(go_name),
+        go_name,
+        number,
+        type: mapped.type,
+        label: mapped.repeated
+          ? 'repeated'
+          : mapped.optional
+            ? 'optional'
+            : null,
+        doc: field.doc || field.comment || null
+      });
+    }
+  }
+
+  // Untagged fields take the unused numbers in declaration order
+  let next = 1;
+  const names = new Set();
+  for (const field of pending) {
+    if (field.number === null) {
+      while (taken.has(next)) next++;
+      field.number = next;
+      taken.add(next);
+    }
+    if (names.has(field.name)) {
+      warn(
+        `field ${field.go_name} has the same proto name as another field, ${field.name}`
+      );
+    }
+    names.add(field.name);
+    message.fields.push(field);
+  }
+
+  return message;
+};
+
+/**
+ * Check whether a struct is tagged for serialization.
+ * @param {Object} type - Struct type declaration
+ * @returns {boolean} True if a field has a serialization tag
+ */
+const is_serialized_struct = (type) => {
+  return type.fields.some((field) => {
+    const { tags } = parse_struct_tag(field.tag);
+    return SERIALIZATION_TAG_KEYS.some((key) => tags.has(key));
+  });
+};
+
+// ============================================================================
+// SCHEMA
+// ============================================================================
+
+/**
+ * Format the doc comment of a declaration as proto comment lines.
+ * @param {string|null} doc - Doc comment (with markers)
+ * @param {string} indent - Indentation
+ * @returns {string[]} Comment lines
+ */
+const format_proto_comment = (doc, indent) => {
+  if (!doc) return [];
+  return get_comment_text(doc)
+    .split('\n')
+    .map((line) => `${indent}//${line ? ` ${line}` : ''}`);
+};
+
+/**
+ * Format a message and its nested messages.
+ * @param {Object} message - Message (from build_proto_message)
+ * @param {string|null} doc - Doc comment of the struct
+ * @param {string} [indent=''] - Indentation
+ * @returns {string[]} Lines
+ */
+const format_proto_message = (message, doc, indent = '') => {
+  const inner = `${indent}  `;
+  const lines = [
+    ...format_proto_comment(doc, indent),
+    `${indent}message ${message.name} {`
+  ];
+
+  for (const nested of message.nested) {
+    lines.push(...format_proto_message(nested, null, inner), '');
+  }
+  for (const field of message.fields) {
+    lines.push(...format_proto_comment(field.doc, inner));
+    const label = field.label ? `${field.label} ` : '';
+    lines.push(
+      `${inner}${label}${field.type} ${field.name} = ${field.number};`
+    );
+  }
+  for (const skipped of message.skipped) {
+    lines.push(`${inner}// skipped ${skipped.name}: ${skipped.type}`);
+  }
+
+  if (lines[lines.length - 1] === '') lines.pop();
+  lines.push(`${indent}}`);
+  return lines;
+};
+
+/**
+ * Format an enum, with a zero value added when none of its constants is
+ * zero.
+ * @param {string} name - Enum name
+ * @param {Object} found - Enum (from find_go_enums)
+ * @returns {string[]} Lines
+ */
+const format_proto_enum = (name, found) => {
+  const lines = [...format_proto_comment(found.type.doc, ''), `enum ${name} {`];
+  if (!found.values.some((v) => v.value === 0)) {
+    lines.push(`  ${to_enum_value_name(name, 'Unspecified')} = 0;`);
+  }
+  for (const value of [...found.values].sort((a, b) => a.value - b.value)) {
+    lines.push(`  ${to_enum_value_name(name, value.name)} = ${value.value};`);
+  }
+  lines.push('}');
+  return lines;
+};
+
+/**
+ * Generate the proto3 schema of a package: a message for each struct
+ * tagged for serialization (or for each requested type), the messages of
+ * the structs they refer to and the enums of their fields.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.types] - Structs to generate messages for (default: the serialized structs)
+ * @param {string} [options.module] - Module path, for the go_package option
+ * @returns {Object|null} Schema with filename, text, messages, enums and warnings, or null if the package has no messages
+ */
+const generate_proto_schema = (pkg, options = {}) => {
+  const context = build_proto_context(pkg);
+  const structs = pkg.types.filter(
+    (t) => t.kind === 'struct' && !t.filename.endsWith('_test.go')
+  );
+  const roots = options.types
+    ? structs.filter((t) => options.types.includes(t.name))
+    : structs.filter((t) => t.exported && is_serialized_struct(t));
+  if (roots.length === 0) return null;
+
+  // Follow the structs referenced by the fields of the messages
+  const messages = new Map();
+  const queue = [...roots];
+  while (queue.length > 0) {
+    const type = queue.shift();
+    if (messages.has(type.name)) continue;
+    const message = build_proto_message(
+      type.name,
+      type.fields,
+      context,
+      type.name
+    );
+    messages.set(type.name, { type, message });
+    const referenced = [message, ...message.nested].flatMap((m) => [
+      ...m.messages
+    ]);
+    for (const name of referenced) {
+      if (!messages.has(name)) queue.push(context.types.get(name));
+    }
+  }
+
+  const collect = (key) => {
+    const all = new Set();
+    const visit = (message) => {
+      for (const item of message[key]) all.add(item);
+      message.nested.forEach(visit);
+    };
+    for (const { message } of messages.values()) visit(message);
+    return [...all].sort();
+  };
+  const enums = collect('enums');
+  const imports = collect('imports');
+  const warnings = [];
+  const gather = (message) => {
+    warnings.push(...message.warnings);
+    message.nested.forEach(gather);
+  };
+  for (const { message } of messages.values()) gather(message);
+
+  const ordered = [...messages.values()].sort(function sort_by_position(a, b) {
+    if (a.type.filename !== b.type.filename) {
+      return a.type.filename < b.type.filename ? -1 : 1;
+    }
+    return a.type.line - b.type.line;
+  });
+
+  const lines = [
+    `// Generated from Go package ${pkg.name} (${pkg.directory}).`,
+    'syntax = "proto3";',
+    '',
+    `package ${pkg.name};`,
+    ''
+  ];
+  if (imports.length > 0) {
+    lines.push(...imports.map((i) => `import "${i}";`), '');
+  }
+  if (options.module) {
+    const go_package = [options.module, pkg.directory]
+      .filter((p) => p && p !== '.')
+      .join('/');
+    lines.push(`option go_package = "${go_package}";`, '');
+  }
+  for (const { type, message } of ordered) {
+    lines.push(...format_proto_message(message, type.doc), '');
+  }
+  for (const name of enums) {
+    lines.push(...format_proto_enum(name, context.enums.get(name)), '');
+  }
+
+  return {
+    package: pkg.name,
+    directory: pkg.directory,
+    filename: [pkg.directory, `${pkg.name}.proto`]
+      .filter((p) => p !== '.')
+      .join('/'),
+    text: `${lines.join('\n').trimEnd()}\n`,
+    messages: ordered.map((m) => m.type.name),
+    enums,
+    warnings
+  };
+};
+
+/**
+ * Generate the proto3 schemas of a set of Go sources, one per package with
+ * serialized structs.  Test files are left out.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see generate_proto_schema)
+ * @param {string} [options.package] - Only generate the package with this name or directory
+ * @returns {Object} Schemas and a summary
+ */
+const generate_source_proto = (sources, options = {}) => {
+  const files = sources
+    .filter((file) => !file.filename.endsWith('_test.go'))
+    .map((file) => parse_go_file(file.source, file.filename));
+  const packages = group_go_packages(files).filter(
+    (pkg) =>
+      !options.package ||
+      pkg.name === options.package ||
+      pkg.directory === options.package
+  );
+
+  const schemas = packages
+    .map((pkg) => generate_proto_schema(pkg, options))
+    .filter(Boolean)
+    .sort((a, b) => (a.filename < b.filename ? -1 : 1));
+  return {
+    schemas,
+    summary: {
+      files: schemas.length,
+      messages: schemas.reduce((sum, s) => sum + s.messages.length, 0),
+      enums: schemas.reduce((sum, s) => sum + s.enums.length, 0),
+      warnings: schemas.reduce((sum, s) => sum + s.warnings.length, 0)
+    }
+  };
+};
+
+/**
+ * Generate the proto3 schemas of a project.
+ * @param {number} project_id - The project ID
+ * @param {Object} [options] - Options (see generate_source_proto)
+ * @returns {Promise<Object>} Schemas and a summary
+ */
+const export_project_proto = async (project_id, options = {}) => {
+  return generate_source_proto(await load_go_sources(project_id), options);
+};
+
+export {
+  export_project_proto,
+  generate_source_proto,
+  generate_proto_schema,
+  find_go_enums,
+  map_proto_type,
+  to_snake_case,
+  PROTO_SCALAR_TYPES
+};
@@ -0,0 +1,418 @@
+'use strict';
+
+/**
+ * @fileoverview Go function purity module.
+ * Finds the side effects of each Go function that matter when it is called
+ * from several goroutines at once: reads and writes of package variables,
+ * and writes through pointer, slice and map arguments (including pointer
+ * receivers).  From these, each function gets a best-effort concurrency
+ * hint:
+ * - safe: only local state is used, a candidate for "safe for concurrent
+ *   use" in its doc comment
+ * - unsafe: a package variable or shared argument is written without a
+ *   lock, directly or through a called function of the package
+ * - unknown: package variables are read, or written under a lock
+ * Hints are heuristic: calls into other packages and writes through
+ * interfaces or aliases are not followed.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/purity
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  classify_type,
+  get_base_type,
+  find_go_assignments,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Concurrency hints, from most to least certain to be safe.
+ */
+const CONCURRENCY_HINTS = ['safe', 'unknown', 'unsafe'];
+
+/**
+ * Type kinds whose values share memory with the caller's copy.
+ */
+const SHARED_KINDS = new Set(['pointer', 'slice', 'map']);
+
+/**
+ * Lock acquisitions that guard the writes of a function.
+ */
+const LOCK_PATTERN = /\.\s*(?:R?Lock|TryR?Lock)\s*\(\s*\)/;
+
+/**
+ * Doc comment phrases claiming a function is safe for concurrent use.
+ */
+const SAFE_DOC_PATTERN =
+  /\b(safe for concurrent use|(concurrency|goroutine|thread)[- ]safe)\b/i;
+
+// ============================================================================
+// SIDE EFFECTS
+// ============================================================================
+
+/**
+ * Check whether a parameter type shares memory with the caller: pointers,
+ * slices, maps and variadics, directly or through a named type of the
+ * package.
+ * @param {Object} param - Parameter (from the Go parser)
+ * @param {Map<string, Object>} types - Package types by name
+ * @returns {boolean} Whether writes through the parameter are visible to the caller
+ */
+const is_shared_param = (param, types) => {
+  if (param.variadic) return true;
+  const kind = classify_type(param.type);
+  if (SHARED_KINDS.has(kind)) return true;
+  const type = kind === 'named' ? types.get(get_base_type(param.type)) : null;
+  return Boolean(type && SHARED_KINDS.has(classify_type(type.underlying)));
+};
+
+/**
+ * Find the names a function body defines locally (`:=`, `var`), which
+ * shadow package variables of the same name.
+ * @param {Object[]} assignments - Assignments (from find_go_assignments)
+ * @returns {Set<string>} Local names
+ */
+const get_local_names = (assignments) => {
+  return new Set(
+    assignments
+      .filter((a) => a.kind === 'define')
+      .flatMap((a) => a.targets.map((t) => t.base))
+  );
+};
+
+/**
+ * Find the side effects of a function: package variables it reads and
+ * writes, and the shared parameters (and pointer receiver) it writes
+ * through.  Parameters and locals shadow package variables.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @param {Object} pkg - Package containing the function
+ * @returns {Object} Reads, writes, argument writes and whether a lock is taken
+ */
+const find_side_effects = (fn, pkg) => {
+  const body = fn.body || '';
+  const masked = mask_source(body);
+  const line_index = build_line_index(body);
+  const types = new Map(pkg.types.map((t) => [t.name, t]));
+  const assignments = find_go_assignments(body, fn.body_line || fn.line);
+
+  const params = new Map(
+    fn.params.filter((p) => p.name && p.name !== '_').map((p) => [p.name, p])
+  );
+  const shadowed = new Set([...params.keys(), ...get_local_names(assignments)]);
+  if (fn.receiver && fn.receiver.name) shadowed.add(fn.receiver.name);
+  const globals = new Set(
+    pkg.vars.flatMap((v) => v.names).filter((n) => !shadowed.has(n))
+  );
+
+  const writes = [];
+  const argument_writes = [];
+  for (const assignment of assignments) {
+    if (assignment.kind === 'define') continue;
+    for (const target of assignment.targets) {
+      if (globals.has(target.base)) {
+        writes.push({
+          name: target.base,
+          target: target.text,
+          line: assignment.line
+        });
+        continue;
+      }
+      // Reassigning a parameter only changes the local copy
+      const through = target.field || target.indexed || target.dereferenced;
+      if (!through) continue;
+      const receiver = fn.receiver && fn.receiver.name === target.base;
+      const param = params.get(target.base);
+      if (
+        (receiver && fn.receiver.pointer) ||
+        (param && is_shared_param(param, types))
+      ) {
+        argument_writes.push({
+          name: target.base,
+          target: target.text,
+          line: assignment.line,
+          receiver: Boolean(receiver)
+        });
+      }
+    }
+  }
+
+  const reads = [];
+  const seen = new Set();
+  const pattern = /(?<![\w.])[A-Za-z_]\w*/g;
+  let match;
+  while ((match = pattern.exec(masked)) !== null) {
+    const name = match[0];
+    if (!globals.has(name) || seen.has(name)) continue;
+    if (writes.some((w) => w.name === name)) continue;
+    seen.add(name);
+    reads.push({
+      name,
+      line: (fn.body_line || fn.line) + line_at(line_index, match.index) - 1
+    });
+  }
+
+  return { reads, writes, argument_writes, locked: LOCK_PATTERN.test(masked) };
+};
+
+/**
+ * Find the calls of a function body to functions of its own package, and
+ * to methods through its receiver or parameters (`s.Inc()`).
+ * @param {Object} fn - Function (from group_go_packages)
+ * @returns {Object[]} Calls with callee key, receiver name and line
+ */
+const find_package_calls = (fn) => {
+  const body = fn.body || '';
+  const masked = mask_source(body);
+  const line_index = build_line_index(body);
+  const receivers = new Map(
+    fn.params.filter((p) => p.name).map((p) => [p.name, p.type])
+  );
+  if (fn.receiver && fn.receiver.name) {
+    receivers.set(
+      fn.receiver.name,
+      `${fn.receiver.pointer ? '*' : ''}${fn.receiver.type}`
+    );
+  }
+
+  const calls = [];
+  const pattern = /(?<![\w.])([A-Za-z_]\w*)(?:\s*\.\s*([A-Za-z_]\w*))?\s*\(/g;
+  let match;
+  while ((match = pattern.exec(masked)) !== null) {
+    const line =
+      (fn.body_line || fn.line) + line_at(line_index, match.index) - 1;
+    if (!match[2]) {
+      calls.push({ key: match[1], via: null, line });
+    } else if (receivers.has(match[1])) {
+      const type = get_base_type(receivers.get(match[1]));
+      calls.push({ key: `${type}.${match[2]}`, via: match[1], line });
+    }
+  }
+
+  return calls;
+};
+
+// ============================================================================
+// CONCURRENCY HINTS
+// ============================================================================
+
+/**
+ * Get the key of a function among the functions of its package.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @returns {string} Name, or Type.Name for methods
+ */
+const get_function_key = (fn) => {
+  return fn.receiver
+    ? `${get_base_type(fn.receiver.type)}.${fn.name}`
+    : fn.name;
+};
+
+/**
+ * Derive the concurrency hint of a function from its own side effects.
+ * @param {Object} effects - Side effects (from find_side_effects)
+ * @returns {Object} Hint and the reasons for it
+ */
+const get_own_hint = (effects) => {
+  const reasons = [];
+  for (const write of effects.writes) {
+    reasons.push({
+      kind: 'global_write',
+      name: write.name,
+      line: write.line,
+      message: `writes package variable ${write.name}`
+    });
+  }
+  for (const write of effects.argument_writes) {
+    reasons.push({
+      kind: write.receiver ? 'receiver_write' : 'argument_write',
+      name: write.name,
+      line: write.line,
+      message: write.receiver
+        ? `writes through pointer receiver ${write.name} (${write.target})`
+        : `writes through shared argument ${write.name} (${write.target})`
+    });
+  }
+  for (const read of effects.reads) {
+    reasons.push({
+      kind: 'global_read',
+      name: read.name,
+      line: read.line,
+      message: `reads package variable ${read.name}`
+    });
+  }
+
+  const writes = effects.writes.length + effects.argument_writes.length > 0;
+  let hint = 'safe';
+  if (writes && !effects.locked) hint = 'unsafe';
+  else if (writes || effects.reads.length > 0) hint = 'unknown';
+  if (writes && effects.locked) {
+    reasons.push({
+      kind: 'locked',
+      name: null,
+      line: null,
+      message: 'writes while holding a lock'
+    });
+  }
+  return { hint, reasons };
+};
+
+/**
+ * Compute the concurrency hints of the functions of a package.  A
+ * function is no safer than the package functions it calls: hints are
+ * propagated along calls until they no longer change.  A method's
+ * receiver writes only propagate to callers that pass a shared argument
+ * or pointer receiver as its receiver.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Map<Object, Object>} Hint and reasons by function
+ */
+const compute_concurrency_hints = (pkg) => {
+  const functions = pkg.functions.filter((fn) => fn.name && fn.body);
+  const by_key = new Map(functions.map((fn) => [get_function_key(fn), fn]));
+  const types = new Map(pkg.types.map((t) => [t.name, t]));
+  const hints = new Map();
+  const effects = new Map();
+
+  for (const fn of functions) {
+    effects.set(fn, find_side_effects(fn, pkg));
+    hints.set(fn, get_own_hint(effects.get(fn)));
+  }
+
+  const calls = new Map(functions.map((fn) => [fn, find_package_calls(fn)]));
+  let changed = true;
+  while (changed) {
+    changed = false;
+    for (const fn of functions) {
+      const own = hints.get(fn);
+      for (const call of calls.get(fn)) {
+        const callee = by_key.get(call.key);
+        if (!callee || callee === fn) continue;
+        let callee_hint = hints.get(callee).hint;
+
+        // Calling a mutating method on a value copy is harmless
+        if (call.via && callee_hint !== 'safe') {
+          const param = fn.params.find((p) => p.name === call.via);
+          const shared = param
+            ? is_shared_param(param, types)
+            : fn.receiver && fn.receiver.pointer;
+          const own_effects = effects.get(callee);
+          const only_receiver =
+            own_effects.writes.length === 0 &&
+            own_effects.reads.length === 0 &&
+            own_effects.argument_writes.every((w) => w.receiver);
+          if (!shared && only_receiver) continue;
+        }
+        if (callee_hint === 'unsafe' && effects.get(fn).locked) {
+          callee_hint = 'unknown';
+        }
+
+        const rank = CONCURRENCY_HINTS.indexOf(callee_hint);
+        if (rank <= CONCURRENCY_HINTS.indexOf(own.hint)) continue;
+        own.hint = callee_hint;
+        own.reasons.push({
+          kind: 'call',
+          name: call.key,
+          line: call.line,
+          message: `calls ${call.key}, which is ${callee_hint === 'unsafe' ? 'not safe' : 'not known to be safe'} for concurrent use`
+        });
+        changed = true;
+      }
+    }
+  }
+
+  return hints;
+};
+
+/**
+ * Annotate the functions of a set of packages with their concurrency
+ * hint.  This is opt-in: parsed functions carry no hint until annotated.
+ * Sets `concurrency_hint` (safe, unknown or unsafe) and
+ * `concurrency_reasons` on each function with a body.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} The packages, annotated in place
+ */
+const annotate_concurrency_hints = (packages) => {
+  for (const pkg of packages) {
+    for (const [fn, { hint, reasons }] of compute_concurrency_hints(pkg)) {
+      fn.concurrency_hint = hint;
+      fn.concurrency_reasons = reasons;
+    }
+  }
+  return packages;
+};
+
+/**
+ * List the concurrency hints of the functions of a set of packages.  A
+ * doc comment claiming safety for concurrent use on a function whose
+ * hint is unsafe is reported as a doc conflict.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string} [options.hint] - Only list functions with this hint
+ * @returns {Object[]} Functions with hint, reasons and doc claims, by file and line
+ * @throws {Error} If the hint is unknown
+ */
+const find_concurrency_hints = (packages, options = {}) => {
+  if (options.hint && !CONCURRENCY_HINTS.includes(options.hint)) {
+    throw new Error(
+      `Unknown hint '${options.hint}' (expected one of: ${CONCURRENCY_HINTS.join(', ')})`
+    );
+  }
+  const functions = [];
+
+  for (const pkg of packages) {
+    for (const [fn, { hint, reasons }] of compute_concurrency_hints(pkg)) {
+      if (options.hint && hint !== options.hint) continue;
+      const documented_safe = SAFE_DOC_PATTERN.test(fn.doc || '');
+      functions.push({
+        name: get_function_key(fn),
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        exported: fn.exported,
+        hint,
+        reasons,
+        documented_safe,
+        doc_conflict: documented_safe && hint === 'unsafe'
+      });
+    }
+  }
+
+  return functions.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Report the concurrency hints of the functions of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_concurrency_hints)
+ * @returns {Promise<Object>} Functions with their hint, and a summary
+ */
+const analyze_project_concurrency_hints = async (project_id, options = {}) => {
+  const packages = await load_go_packages(project_id);
+  const all = find_concurrency_hints(packages);
+  const functions = options.hint
+    ? find_concurrency_hints(packages, options)
+    : all;
+
+  const summary = {
+    total_functions: all.length,
+    safe: all.filter((f) => f.hint === 'safe').length,
+    unknown: all.filter((f) => f.hint === 'unknown').length,
+    unsafe: all.filter((f) => f.hint === 'unsafe').length,
+    doc_conflicts: all.filter((f) => f.doc_conflict).length
+  };
+  return { functions, summary };
+};
+
+export {
+  analyze_project_concurrency_hints,
+  find_concurrency_hints,
+  annotate_concurrency_hints,
+  compute_concurrency_hints,
+  find_side_effects,
+  CONCURRENCY_HINTS
+};
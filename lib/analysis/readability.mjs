@@ -8,6 +8,15 @@
  */
 
 import { query } from '../db.mjs';
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  split_block_entries,
+  strip_comments,
+  load_go_packages
+} from './golang.mjs';
 
 /**
  * Weights for different readability factors.
@@ -659,6 +668,336 @@ const analyze_project_readability = async (project_id) => {
   return results;
 };
 
+// ============================================================================
+// GO GUARD CLAUSES AND NESTING
+// ============================================================================
+
+/**
+ * Statements that end a guard clause by leaving the current flow.
+ */
+const TERMINATING_STATEMENT =
+  /^(return\b|panic\s*\(|continue\b|break\b|goto\b|os\.Exit\s*\(|log\.Fatal\w*\s*\(|log\.Panic\w*\s*\(|t\.Fatal\w*\s*\(|t\.Skip\w*\s*\(|b\.Fatal\w*\s*\()/;
+
+/**
+ * Maximum number of statements in a guard clause's block.
+ */
+const MAX_GUARD_STATEMENTS = 3;
+
+/**
+ * Check if the text before a '{' opens a control flow block (as opposed to
+ * a composite literal).
+ * @param {string} before - Statement text between the previous block and the brace
+ * @returns {boolean} True for if/for/switch/select/else blocks and function literals
+ */
+const opens_control_block = (before) => {
+  const text = before.trim();
+  if (text === '' || text === 'else') return true;
+  if (/^(else\s+)?(if|for|switch|select)\b/.test(text)) return true;
+  return /\bfunc\s*\(/.test(text);
+};
+
+/**
+ * Find the top level blocks of a statement, including the bodies of
+ * function literals passed as arguments.
+ * @param {string} masked - Masked statement text
+ * @returns {Object[]} Blocks with open, close and a control flag
+ */
+const find_statement_blocks = (masked) => {
+  const blocks = [];
+  const segments = [];
+  let segment_start = 0;
+  let paren_depth = 0;
+
+  for (let i = 0; i < masked.length; i++) {
+    const ch = masked[i];
+    if (ch === '[') {
+      const close = find_matching(masked, i);
+      if (close === -1) break;
+      i = close;
+    } else if (ch === '(') {
+      paren_depth++;
+      segments.push(segment_start);
+      segment_start = i + 1;
+    } else if (ch === ')') {
+      paren_depth--;
+      segment_start = segments.length > 0 ? segments.pop() : 0;
+    } else if (ch === ',' && paren_depth > 0) {
+      segment_start = i + 1;
+    } else if (ch === '{') {
+      const close = find_matching(masked, i);
+      if (close === -1) break;
+      const before = masked.substring(segment_start, i);
+      blocks.push({
+        open: i,
+        close,
+        // Inside call arguments only function literals open a block
+        control:
+          paren_depth > 0
+            ? /\bfunc\s*\(/.test(before)
+            : opens_control_block(before)
+      });
+      i = close;
+      if (paren_depth === 0) segment_start = close + 1;
+    }
+  }
+
+  return blocks;
+};
+
+/**
+ * Check if a block ends by leaving the current flow.
+ * @param {string} inner - Block text without braces
+ * @returns {Object} { terminates, statements, terminator }
+ */
+const get_block_exit = (inner) => {
+  const entries = split_block_entries(inner, 0);
+  const last = entries[entries.length - 1];
+  const match = last
+    ? strip_comments(last.text).trim().match(TERMINATING_STATEMENT)
+    : null;
+  return {
+    terminates: !!match,
+    statements: entries.length,
+    terminator: match ? match[1].replace(/\s*\($/, '') : null
+  };
+};
+
+/**
+ * Analyze the nesting structure of a Go function body.
+ * Nesting depth counts control flow blocks (if, for, switch, select and
+ * function literals) below the function's own body.  Guard clauses are
+ * `if` statements at the top level of the body without an else branch
+ * whose short block returns (or panics, continues, breaks).
+ * @param {string} body - Function body including its braces
+ * @param {number} [body_line=1] - Line of the opening brace
+ * @returns {Object} Nesting analysis with max depth, guards and else-after-return sites
+ */
+const analyze_go_nesting = (body, body_line = 1) => {
+  const inner = body.substring(body.indexOf('{') + 1, body.lastIndexOf('}'));
+  const offset = body.indexOf('{') + 1;
+  const masked = mask_source(inner);
+  const line_index = build_line_index(body);
+  const line_of = (position) =>
+    body_line + line_at(line_index, offset + position) - 1;
+
+  let max_depth = 0;
+  let max_depth_line = null;
+  const guard_clauses = [];
+  const else_after_return = [];
+
+  const walk = (start, end, depth) => {
+    const text = inner.substring(start, end);
+    for (const entry of split_block_entries(text, start)) {
+      const entry_masked = masked.substring(
+        entry.offset,
+        entry.offset + entry.text.length
+      );
+      const blocks = find_statement_blocks(entry_masked);
+      const is_if = /^if\b/.test(entry_masked);
+
+      blocks.forEach(function visit_block(block, index) {
+        const open = entry.offset + block.open;
+        const close = entry.offset + block.close;
+        const block_depth = block.control ? depth + 1 : depth;
+
+        if (block_depth > max_depth) {
+          max_depth = block_depth;
+          max_depth_line = line_of(open);
+        }
+
+        if (is_if && index === 0 && block.control) {
+          const exit = get_block_exit(inner.substring(open + 1, close));
+          const has_else = blocks.length > 1;
+          if (exit.terminates && has_else) {
+            else_after_return.push({
+              line: line_of(entry.offset + blocks[1].open),
+              terminator: exit.terminator
+            });
+          }
+          if (
+            depth === 0 &&
+            exit.terminates &&
+            !has_else &&
+            exit.statements <= MAX_GUARD_STATEMENTS
+          ) {
+            guard_clauses.push({
+              line: line_of(entry.offset),
+              condition: entry_masked
+                .substring(2, block.open)
+                .trim()
+                .replace(/\s+/g, ' '),
+              terminator: exit.terminator
+            });
+          }
+        }
+
+        walk(open + 1, close, block_depth);
+      });
+    }
+  };
+
+  walk(0, inner.length, 0);
+
+  const statements = split_block_entries(inner, 0);
+  const first = statements[0];
+  const leading_guard =
+    !!first && guard_clauses.some((g) => g.line === line_of(first.offset));
+
+  // A body wrapped in one `if` (followed by at most a final return) could
+  // invert the condition into a guard clause
+  let wrapping_if = null;
+  const body_lines = line_of(inner.length) - line_of(0);
+  statements.forEach(function find_wrapping_if(statement, index) {
+    const trailing = statements.length - index - 1;
+    const statement_masked = masked.substring(
+      statement.offset,
+      statement.offset + statement.text.length
+    );
+    if (!/^if\b/.test(statement_masked) || trailing > 1 || max_depth < 3) {
+      return;
+    }
+    if (trailing === 1 && !/^return\b/.test(statements[index + 1].text)) {
+      return;
+    }
+    const blocks = find_statement_blocks(statement_masked);
+    if (blocks.length !== 1) return;
+    const block_lines =
+      line_of(statement.offset + blocks[0].close) -
+      line_of(statement.offset + blocks[0].open);
+    if (block_lines > 0 && block_lines >= body_lines * 0.5) {
+      wrapping_if = { line: line_of(statement.offset) };
+    }
+  });
+
+  return {
+    max_depth,
+    max_depth_line,
+    statement_count: statements.length,
+    guard_clauses,
+    uses_guard_clauses: guard_clauses.length > 0,
+    leading_guard,
+    else_after_return,
+    wrapping_if
+  };
+};
+
+/**
+ * Score how well a function manages nesting (0-100, higher is better).
+ * Deep nesting lowers the score, an else branch after a return and a
+ * body wrapped in a single `if` are penalized, and guard clauses that keep
+ * the body shallow are rewarded.
+ * @param {Object} nesting - Nesting analysis (from analyze_go_nesting)
+ * @returns {Object} Score, rating, style and suggestions
+ */
+const score_nesting_quality = (nesting) => {
+  let score = calculate_score(
+    nesting.max_depth,
+    THRESHOLDS.nesting_depth,
+    true
+  );
+  score -= nesting.else_after_return.length * 10;
+  if (nesting.wrapping_if) score -= 15;
+  if (nesting.uses_guard_clauses && nesting.max_depth <= 2) score += 5;
+  score = Math.max(0, Math.min(100, Math.round(score)));
+
+  let style;
+  if (nesting.max_depth >= THRESHOLDS.nesting_depth.acceptable) {
+    style = 'deeply_nested';
+  } else if (nesting.uses_guard_clauses) {
+    style = 'guarded';
+  } else if (nesting.max_depth <= THRESHOLDS.nesting_depth.excellent) {
+    style = 'flat';
+  } else {
+    style = 'nested';
+  }
+
+  const suggestions = [];
+  if (nesting.wrapping_if) {
+    suggestions.push(
+      `Invert the condition at line ${nesting.wrapping_if.line} and return early to un-nest the function body`
+    );
+  }
+  for (const site of nesting.else_after_return) {
+    suggestions.push(
+      `Drop the else at line ${site.line}: the if block already ends with ${site.terminator}`
+    );
+  }
+  if (style === 'deeply_nested' && !nesting.wrapping_if) {
+    suggestions.push(
+      `Nesting reaches depth ${nesting.max_depth} at line ${nesting.max_depth_line}; use guard clauses or extract the inner block into a function`
+    );
+  }
+
+  let rating;
+  if (score >= 85) rating = 'excellent';
+  else if (score >= 70) rating = 'good';
+  else if (score >= 55) rating = 'acceptable';
+  else if (score >= 40) rating = 'poor';
+  else rating = 'very_poor';
+
+  return { score, rating, style, suggestions };
+};
+
+/**
+ * Analyze guard clause usage and nesting depth of a project's Go functions.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Per-function nesting analysis with a summary
+ */
+const analyze_project_nesting = async (project_id) => {
+  const packages = await load_go_packages(project_id);
+  const functions = [];
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (!fn.body) continue;
+      const nesting = analyze_go_nesting(fn.body, fn.body_line);
+      functions.push({
+        package: pkg.name,
+        name: fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name,
+        filename: fn.filename,
+        line: fn.line,
+        ...nesting,
+        ...score_nesting_quality(nesting)
+      });
+    }
+  }
+
+  functions.sort(function sort_by_score_asc(a, b) {
+    return a.score - b.score;
+  });
+
+  const by_style = {};
+  for (const fn of functions) {
+    by_style[fn.style] = (by_style[fn.style] || 0) + 1;
+  }
+
+  const count = functions.length;
+  return {
+    functions,
+    summary: {
+      functions_analyzed: count,
+      functions_with_guards: functions.filter((f) => f.uses_guard_clauses)
+        .length,
+      deeply_nested: by_style.deeply_nested || 0,
+      else_after_return: functions.reduce(
+        (sum, f) => sum + f.else_after_return.length,
+        0
+      ),
+      avg_max_depth:
+        count > 0
+          ? Math.round(
+              (functions.reduce((sum, f) => sum + f.max_depth, 0) / count) * 10
+            ) / 10
+          : 0,
+      avg_score:
+        count > 0
+          ? Math.round(functions.reduce((sum, f) => sum + f.score, 0) / count)
+          : 0,
+      by_style
+    }
+  };
+};
+
 export {
   calculate_readability_score,
   analyze_project_readability,
@@ -669,6 +1008,9 @@ export {
   analyze_line_lengths,
   detect_magic_numbers,
   analyze_boolean_complexity,
+  analyze_project_nesting,
+  analyze_go_nesting,
+  score_nesting_quality,
   READABILITY_WEIGHTS,
   THRESHOLDS
 };
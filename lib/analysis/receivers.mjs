@@ -0,0 +1,274 @@
+'use strict';
+
+/**
+ * @fileoverview Go receiver naming module.
+ * Reports the receiver names of the methods of each type and suggests a
+ * single consistent one, as Go style asks: a short name, usually the first
+ * letter of the type, used by every method (`c` for all the methods of a
+ * Calculator).  Types whose methods use different names, or generic names
+ * such as `this` and `self`, are flagged, with the renames that would make
+ * them consistent.
+ *
+ * The suggested name is the most used short name of the type (at most
+ * three lowercase letters and digits, not generic), or else the initials
+ * of the words of the type name (`br` for BufferedReader, `hs` for
+ * HTTPServer).  Renames clashing with a parameter, result or identifier
+ * of the method are marked as conflicts.
+ *
+ * Receivers belong to the type declaring the method: the methods promoted
+ * through an embedded type are counted with the embedded type, so that an
+ * embedding type is not flagged for the receivers of another.  Type
+ * arguments of generic receivers (`s *Stack[T]`) are left out.  Unnamed
+ * and blank receivers (`func (Calculator) Reset()`) are counted apart and
+ * never renamed.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/receivers
+ */
+
+import {
+  mask_source,
+  parse_go_file,
+  group_go_packages,
+  GO_BUILTINS,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Receiver names carrying no meaning, flagged whatever their consistency.
+ */
+const GENERIC_RECEIVER_NAMES = new Set(['this', 'self', 'me']);
+
+/**
+ * Go keywords, which cannot be receiver names.
+ */
+const GO_KEYWORDS = new Set([
+  'break',
+  'case',
+  'chan',
+  'const',
+  'continue',
+  'default',
+  'defer',
+  'else',
+  'fallthrough',
+  'for',
+  'func',
+  'go',
+  'goto',
+  'if',
+  'import',
+  'interface',
+  'map',
+  'package',
+  'range',
+  'return',
+  'select',
+  'struct',
+  'switch',
+  'type',
+  'var'
+]);
+
+/**
+ * Receiver names short enough to be idiomatic.
+ */
+const SHORT_RECEIVER_PATTERN = /^[a-z][a-z0-9]{0,2}$/;
+
+// ============================================================================
+// SUGGESTIONS
+// ============================================================================
+
+/**
+ * Suggest a receiver name from the initials of the words of a type name,
+ * at most three letters.  A name taken by a keyword or builtin falls back
+ * to the first letter.
+ * @param {string} type_name - Type name
+ * @returns {string} Receiver name
+ */
+const get_type_initials = (type_name) => {
+  const words = type_name.match(/[A-Z]+(?![a-z])|[A-Z]?[a-z0-9]+/g) || [
+    type_name
+  ];
+  const initials = words
+    .map((word) => word[0].toLowerCase())
+    .join('')
+    .substring(0, 3);
+  const builtins = new Set(Object.values(GO_BUILTINS).flat());
+  if (GO_KEYWORDS.has(initials) || builtins.has(initials)) {
+    return initials[0];
+  }
+  return initials;
+};
+
+/**
+ * Check whether a receiver name is idiomatic: short and not generic.
+ * @param {string} name - Receiver name
+ * @returns {boolean} True for an idiomatic name
+ */
+const is_short_receiver_name = (name) => {
+  return (
+    SHORT_RECEIVER_PATTERN.test(name) &&
+    !GENERIC_RECEIVER_NAMES.has(name) &&
+    !GO_KEYWORDS.has(name)
+  );
+};
+
+/**
+ * Suggest the receiver name of a type: its most used idiomatic name, by
+ * name on ties, or else its initials.
+ * @param {string} type_name - Type name
+ * @param {Object} names - Number of methods by receiver name
+ * @returns {string} Receiver name
+ */
+const suggest_receiver_name = (type_name, names) => {
+  const short = Object.entries(names)
+    .filter(([name]) => is_short_receiver_name(name))
+    .sort(function sort_by_use(a, b) {
+      return b[1] - a[1] || a[0].localeCompare(b[0]);
+    });
+  return short.length > 0 ? short[0][0] : get_type_initials(type_name);
+};
+
+/**
+ * Check whether renaming the receiver of a method to a name would clash
+ * with a parameter, result or identifier of the method.
+ * @param {Object} fn - Method (from the Go parser)
+ * @param {string} name - New receiver name
+ * @returns {boolean} True if the name is taken
+ */
+const has_receiver_conflict = (fn, name) => {
+  const taken = [...fn.params, ...fn.results].some((p) => p.name === name);
+  if (taken || !fn.body) return taken;
+  return new RegExp(`(?<![\\w.])${name}(?!\\w)`).test(mask_source(fn.body));
+};
+
+// ============================================================================
+// RECEIVER NAMES
+// ============================================================================
+
+/**
+ * Find the receiver names of the methods of a set of packages by type,
+ * with a suggested name and the renames making them consistent.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.inconsistent_only=false] - Only list types with issues
+ * @returns {Object[]} Types with their receiver names, issues and renames, by directory and name
+ */
+const find_receiver_names = (packages, options = {}) => {
+  const types = [];
+
+  for (const pkg of packages) {
+    for (const [type_name, methods] of Object.entries(pkg.methods)) {
+      const names = {};
+      let unnamed = 0;
+      for (const fn of methods) {
+        const name = fn.receiver.name;
+        if (!name || name === '_') {
+          unnamed++;
+          continue;
+        }
+        names[name] = (names[name] || 0) + 1;
+      }
+
+      const distinct = Object.keys(names);
+      const suggestion = suggest_receiver_name(type_name, names);
+      const issues = [];
+      if (distinct.length > 1) issues.push('inconsistent');
+      if (distinct.some((name) => GENERIC_RECEIVER_NAMES.has(name))) {
+        issues.push('generic');
+      }
+      if (issues.length === 0 && options.inconsistent_only) continue;
+
+      const renames =
+        issues.length === 0
+          ? []
+          : methods
+              .filter((fn) => fn.receiver.name && fn.receiver.name !== '_')
+              .filter((fn) => fn.receiver.name !== suggestion)
+              .map((fn) => ({
+                method: fn.name,
+                filename: fn.filename,
+                line: fn.line,
+                from: fn.receiver.name,
+                to: suggestion,
+                conflict: has_receiver_conflict(fn, suggestion)
+              }));
+
+      types.push({
+        type: type_name,
+        package: pkg.name,
+        directory: pkg.directory,
+        methods: methods.length,
+        names,
+        unnamed,
+        consistent: distinct.length <= 1,
+        suggestion,
+        issues,
+        renames
+      });
+    }
+  }
+
+  return types.sort(function sort_by_type(a, b) {
+    return (
+      a.directory.localeCompare(b.directory) || a.type.localeCompare(b.type)
+    );
+  });
+};
+
+/**
+ * Summarize the receiver names of a set of types.
+ * @param {Object[]} types - Types (from find_receiver_names)
+ * @returns {Object} Numbers of types, inconsistent and generic ones, and renames
+ */
+const summarize_receiver_names = (types) => {
+  const renames = types.flatMap((t) => t.renames);
+  return {
+    types: types.length,
+    inconsistent: types.filter((t) =>
+      t.issues.includes('inconsistent')
+    ).length,
+    generic: types.filter((t) => t.issues.includes('generic')).length,
+    renames: renames.length,
+    conflicts: renames.filter((r) => r.conflict).length
+  };
+};
+
+/**
+ * Report the receiver names of the types of a set of Go sources, such as
+ * the files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see find_receiver_names)
+ * @returns {Object} Types with their receiver names and a summary
+ */
+const find_source_receiver_names = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const types = find_receiver_names(group_go_packages(files), options);
+  return { types, summary: summarize_receiver_names(types) };
+};
+
+/**
+ * Report the receiver names of the types of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_receiver_names)
+ * @returns {Promise<Object>} Types with their receiver names and a summary
+ */
+const analyze_project_receiver_names = async (project_id, options = {}) => {
+  const types = find_receiver_names(
+    await load_go_packages(project_id),
+    options
+  );
+  return { types, summary: summarize_receiver_names(types) };
+};
+
+export {
+  analyze_project_receiver_names,
+  find_source_receiver_names,
+  find_receiver_names,
+  summarize_receiver_names,
+  suggest_receiver_name,
+  get_type_initials,
+  GENERIC_RECEIVER_NAMES
+};
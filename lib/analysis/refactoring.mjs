@@ -0,0 +1,1047 @@
+'use strict';
+
+/**
+ * @fileoverview Refactoring support analysis module.
+ * Computes the impact of refactorings (such as renaming a symbol) from the
+ * cross-reference index, and compares a symbol's declaration between two
+ * files, without modifying any source code.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/refactoring
+ */
+
+import { posix } from 'path';
+import { createHash } from 'crypto';
+import { query } from '../db.mjs';
+import { get_symbol_references } from '../model/symbol_reference.mjs';
+import { get_sourcecode } from '../model/sourcecode.mjs';
+import { analyze_identifier } from './naming.mjs';
+import {
+  parse_go_file,
+  is_exported,
+  strip_comments,
+  get_base_type,
+  find_go_assignments,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Valid identifier patterns by language; Go identifiers do not allow `$`.
+ */
+const IDENTIFIER_PATTERNS = {
+  go: /^[A-Za-z_][A-Za-z0-9_]*$/,
+  javascript: /^[A-Za-z_$][A-Za-z0-9_$]*$/
+};
+
+/**
+ * Go tokens: string, raw string and rune literals, identifiers, numbers,
+ * multi-character operators and single characters.
+ */
+const GO_TOKEN_PATTERN =
+  /"(?:[^"\\\n]|\\.)*"|`[^`]*`|'(?:[^'\\\n]|\\.)*'|[A-Za-z_]\w*|\d[\w.]*|:=|\.\.\.|&&|\|\||<-|<<=?|>>=?|&\^=?|[-+*/%&|^<>=!]=?|\S/g;
+
+/**
+ * Methods with fewer statements than this are trivial (getters, setters,
+ * single delegating calls) and not reported as duplicates.
+ */
+const MIN_DUPLICATE_METHOD_STATEMENTS = 2;
+
+/**
+ * Get the package (directory) a file belongs to.
+ * Rename analysis is scoped to a single package, which is the directory
+ * containing the symbol's definition.
+ * @param {string} filename - The filename
+ * @returns {string} The directory of the file ('.' for top level files)
+ */
+const get_package_for_file = (filename) => {
+  return posix.dirname(filename.replace(/\\/g, '/'));
+};
+
+/**
+ * Find the innermost function entity that contains a line in a file.
+ * @param {Object[]} entities - Entities with filename, start_line and end_line
+ * @param {string} filename - The filename
+ * @param {number} line - The line number
+ * @returns {Object|null} The innermost enclosing entity or null
+ */
+const find_enclosing_entity = (entities, filename, line) => {
+  let enclosing = null;
+
+  for (const entity of entities) {
+    if (entity.filename !== filename) continue;
+    if (line < entity.start_line || line > entity.end_line) continue;
+
+    if (
+      !enclosing ||
+      entity.end_line - entity.start_line <
+        enclosing.end_line - enclosing.start_line
+    ) {
+      enclosing = entity;
+    }
+  }
+
+  return enclosing;
+};
+
+/**
+ * Get a stable key for the scope a reference lives in.
+ * A function's own declaration is excluded so that its name resolves to
+ * the enclosing scope rather than to its own body.
+ * @param {Object[]} entities - Function entities in the package
+ * @param {Object} ref - Reference with filename and line
+ * @param {string} name - The name being referenced
+ * @returns {string} Scope key ('package' for package level references)
+ */
+const get_scope_key = (entities, ref, name) => {
+  const candidates = entities.filter(
+    (e) => !(e.symbol === name && e.start_line === ref.line)
+  );
+  const enclosing = find_enclosing_entity(candidates, ref.filename, ref.line);
+  if (!enclosing) return 'package';
+  return `${enclosing.filename}:${enclosing.start_line}`;
+};
+
+/**
+ * Validate a proposed new name.
+ * @param {string} symbol - The current symbol name
+ * @param {string} new_name - The proposed new name
+ * @param {string} [language='javascript'] - Language used for reserved words
+ * @returns {Object[]} Array of conflicts (empty if the name is valid)
+ */
+const validate_new_name = (symbol, new_name, language = 'javascript') => {
+  const conflicts = [];
+
+  const pattern =
+    IDENTIFIER_PATTERNS[language] || IDENTIFIER_PATTERNS.javascript;
+  if (!new_name || !pattern.test(new_name)) {
+    conflicts.push({
+      type: 'invalid_name',
+      message: `'${new_name}' is not a valid identifier`
+    });
+    return conflicts;
+  }
+
+  if (new_name === symbol) {
+    conflicts.push({
+      type: 'unchanged',
+      message: `'${new_name}' is the current name of the symbol`
+    });
+  }
+
+  const issues = analyze_identifier(new_name, language);
+  for (const issue of issues) {
+    if (issue.type === 'reserved_word') {
+      conflicts.push({ type: 'reserved_word', message: issue.message });
+    }
+  }
+
+  return conflicts;
+};
+
+/**
+ * Detect name collisions and shadowing that renaming would introduce.
+ *
+ * A collision occurs when the new name is already declared in the same
+ * scope as the symbol's definition (or at package level).  Shadowing occurs
+ * when a reference to the symbol sits inside a function that declares the
+ * new name locally (the reference would resolve to the local after the
+ * rename), or when a local symbol is renamed to a package level name that is
+ * used inside the same function.
+ * @param {Object} params - Parameters
+ * @param {string} params.symbol - The current symbol name
+ * @param {string} params.new_name - The proposed new name
+ * @param {Object[]} params.references - In-scope references to the symbol
+ * @param {Object[]} params.new_name_references - In-scope references to the new name
+ * @param {Object[]} params.entities - Entities declared in scope
+ * @returns {Object[]} Array of conflicts
+ */
+const detect_rename_conflicts = ({
+  symbol,
+  new_name,
+  references,
+  new_name_references,
+  entities
+}) => {
+  const conflicts = [];
+  const seen = new Set();
+
+  const add_conflict = (conflict) => {
+    const key = `${conflict.type}:${conflict.filename}:${conflict.line}`;
+    if (seen.has(key)) return;
+    seen.add(key);
+    conflicts.push(conflict);
+  };
+
+  const functions = entities.filter((e) => e.type === 'function');
+  const definitions = references.filter((r) => r.is_definition);
+  const definition_scopes = new Set(
+    definitions.map((r) => get_scope_key(functions, r, symbol))
+  );
+  const reference_scopes = new Set(
+    references.map((r) => get_scope_key(functions, r, symbol))
+  );
+
+  // Without a known definition assume the symbol is declared at package level
+  const is_package_level =
+    definitions.length === 0 || definition_scopes.has('package');
+
+  // Entities already named the new name are package level declarations
+  if (is_package_level) {
+    for (const entity of entities) {
+      if (entity.symbol !== new_name) continue;
+      add_conflict({
+        type: 'collision',
+        filename: entity.filename,
+        line: entity.start_line,
+        message: `'${new_name}' is already declared as a ${entity.type} in this package`
+      });
+    }
+  }
+
+  for (const ref of new_name_references) {
+    const scope = get_scope_key(functions, ref, new_name);
+
+    if (ref.is_definition) {
+      const same_scope =
+        scope === 'package' ? is_package_level : definition_scopes.has(scope);
+
+      if (same_scope) {
+        add_conflict({
+          type: 'collision',
+          filename: ref.filename,
+          line: ref.line,
+          message: `'${new_name}' is already declared in the same scope as '${symbol}'`
+        });
+      } else if (scope !== 'package' && reference_scopes.has(scope)) {
+        add_conflict({
+          type: 'shadowing',
+          filename: ref.filename,
+          line: ref.line,
+          message: `Local '${new_name}' would shadow renamed references to '${symbol}' in this function`
+        });
+      }
+    } else if (!is_package_level && definition_scopes.has(scope)) {
+      // A local symbol renamed to a name used from an outer scope
+      add_conflict({
+        type: 'shadowing',
+        filename: ref.filename,
+        line: ref.line,
+        message: `Renamed '${symbol}' would shadow the outer '${new_name}' used here`
+      });
+    }
+  }
+
+  return conflicts;
+};
+
+/**
+ * Tell whether a reference names a member of a value rather than a
+ * declaration of its scope: a field (`c.name`) or a method declaration.
+ * @param {Object} ref - Reference with symbol_type, is_definition and context
+ * @returns {boolean} True for fields and method declarations
+ */
+const is_member_reference = (ref) => {
+  if (ref.symbol_type === 'field') return true;
+  return Boolean(
+    ref.is_definition && /^\s*func\s*\(/.test(ref.context || '')
+  );
+};
+
+/**
+ * Keep the references that resolve to a symbol's definition, out of every
+ * reference in its package sharing the name.
+ * References linked to an entity match the definition's entity.  The
+ * others match by scope: a local resolves to the references of its own
+ * function, a package level declaration to the references outside of the
+ * functions declaring a local of the same name.  Fields and methods only
+ * match fields and methods (their receivers are not told apart), and the
+ * other definitions of the name are left out.  Without a definition every
+ * reference is kept.
+ * @param {Object} params - Parameters
+ * @param {string} params.symbol - The symbol name
+ * @param {Object|null} params.definition - The chosen definition reference
+ * @param {Object[]} params.references - References to the name in the package
+ * @param {Object[]} params.entities - Entities declared in the package
+ * @returns {Object[]} References to the definition
+ */
+const filter_definition_references = ({
+  symbol,
+  definition,
+  references,
+  entities
+}) => {
+  if (!definition?.is_definition) return references;
+
+  const functions = entities.filter((e) => e.type === 'function');
+  const scope_of = (ref) => get_scope_key(functions, ref, symbol);
+  const definition_scope = scope_of(definition);
+  const is_member = is_member_reference(definition);
+
+  // Functions declaring their own local of the name hide the package one
+  const local_scopes = new Set(
+    references
+      .filter((r) => r.is_definition && r !== definition)
+      .map(scope_of)
+      .filter((scope) => scope !== 'package')
+  );
+
+  return references.filter(function resolves_to_definition(ref) {
+    if (ref === definition) return true;
+    if (definition.definition_entity_id && ref.definition_entity_id) {
+      return ref.definition_entity_id === definition.definition_entity_id;
+    }
+    if (is_member_reference(ref) !== is_member) return false;
+    if (is_member) return !ref.is_definition;
+
+    const scope = scope_of(ref);
+    if (definition_scope !== 'package') return scope === definition_scope;
+    return !local_scopes.has(scope);
+  });
+};
+
+/**
+ * Build the list of edits needed to rename a symbol.
+ * @param {Object[]} references - In-scope references to the symbol
+ * @returns {Object[]} Edits sorted by filename, line and column
+ */
+const build_rename_edits = (references) => {
+  return references
+    .map(function format_edit(ref) {
+      return {
+        filename: ref.filename,
+        line: ref.line,
+        column_start: ref.column_start,
+        column_end: ref.column_end,
+        symbol_type: ref.symbol_type,
+        is_definition: ref.is_definition,
+        context: ref.context
+      };
+    })
+    .sort(function sort_by_location(a, b) {
+      if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+      if (a.line !== b.line) return a.line - b.line;
+      return (a.column_start || 0) - (b.column_start || 0);
+    });
+};
+
+/**
+ * Compute the impact of renaming a symbol within its package.
+ * Returns every location that would need editing (declaration and all
+ * references) plus any name collisions or shadowing that the new name
+ * would cause.  Cross-package references are reported but not included
+ * in the edits.
+ * @param {number} project_id - The project ID
+ * @param {string} symbol - The symbol to rename
+ * @param {string} new_name - The proposed new name
+ * @param {Object} [options] - Optional settings
+ * @param {string} [options.filename] - File used to pick the package when the symbol is defined in several
+ * @returns {Promise<Object>} Rename report
+ */
+const analyze_rename_impact = async (
+  project_id,
+  symbol,
+  new_name,
+  options = {}
+) => {
+  const all_references = await get_symbol_references({ project_id, symbol });
+
+  // Pick the package that owns the symbol's definition
+  const definition =
+    all_references.find(
+      (r) =>
+        r.is_definition &&
+        (!options.filename || r.filename === options.filename)
+    ) ||
+    all_references.find((r) => r.is_definition) ||
+    all_references[0];
+
+  const scope_file = options.filename || definition?.filename;
+  const pkg = scope_file ? get_package_for_file(scope_file) : null;
+
+  const in_scope = (ref) => get_package_for_file(ref.filename) === pkg;
+
+  const package_references = all_references.filter(in_scope);
+  const out_of_scope = all_references.filter((r) => !in_scope(r));
+
+  const new_name_references = new_name
+    ? (await get_symbol_references({ project_id, symbol: new_name })).filter(
+        in_scope
+      )
+    : [];
+
+  const entity_files = [
+    ...new Set(
+      package_references
+        .concat(new_name_references)
+        .map((r) => r.filename)
+    )
+  ];
+
+  // Functions give us local scopes; any entity named new_name in the
+  // package is a package level collision
+  const entities = (
+    await query`
+      SELECT symbol, type, filename, start_line, end_line, language
+      FROM entity
+      WHERE project_id = ${project_id}
+      AND (filename = ANY(${entity_files}) OR symbol = ${new_name || ''})
+    `
+  ).filter(in_scope);
+
+  // Other identifiers sharing the name in the package are not renamed
+  const references = filter_definition_references({
+    symbol,
+    definition,
+    references: package_references,
+    entities
+  });
+  const files = [...new Set(references.map((r) => r.filename))];
+
+  const language =
+    entities[0]?.language ||
+    (scope_file?.endsWith('.go') ? 'go' : 'javascript');
+
+  const conflicts = [
+    ...validate_new_name(symbol, new_name, language),
+    ...detect_rename_conflicts({
+      symbol,
+      new_name,
+      references,
+      new_name_references,
+      entities
+    })
+  ];
+
+  const edits = build_rename_edits(references);
+
+  return {
+    symbol,
+    new_name,
+    scope: {
+      package: pkg,
+      files: files.sort()
+    },
+    definition: definition
+      ? {
+          filename: definition.filename,
+          line: definition.line,
+          symbol_type: definition.symbol_type
+        }
+      : null,
+    edits,
+    conflicts,
+    out_of_scope: out_of_scope.map(function format_out_of_scope(ref) {
+      return {
+        filename: ref.filename,
+        line: ref.line,
+        context: ref.context
+      };
+    }),
+    summary: {
+      edit_count: edits.length,
+      file_count: files.length,
+      conflict_count: conflicts.length,
+      collision_count: conflicts.filter((c) => c.type === 'collision').length,
+      shadowing_count: conflicts.filter((c) => c.type === 'shadowing').length,
+      out_of_scope_count: out_of_scope.length,
+      safe: edits.length > 0 && conflicts.length === 0
+    }
+  };
+};
+
+// ============================================================================
+// SYMBOL COMPARISON
+// ============================================================================
+
+/**
+ * Normalize whitespace in a piece of declaration text.
+ * @param {string|null} text - Text to normalize
+ * @returns {string} Text with runs of whitespace collapsed
+ */
+const normalize_text = (text) => {
+  return (text || '').replace(/\s+/g, ' ').trim();
+};
+
+/**
+ * Format a parameter list as its types only (names are not part of the API).
+ * @param {Object[]} params - Parameters (from the Go parser)
+ * @returns {string} Comma separated parameter types
+ */
+const format_param_types = (params) => {
+  return params
+    .map((p) => `${p.variadic ? '...' : ''}${normalize_text(p.type)}`)
+    .join(', ');
+};
+
+/**
+ * Find a top level declaration in a parsed Go file.
+ * Methods are looked up as `Type.Method`; a bare method name is accepted
+ * when no other declaration has that name and only one type defines it.
+ * @param {Object} file - Parsed file (from parse_go_file)
+ * @param {string} symbol - Symbol name (e.g. `Parse` or `Config.Validate`)
+ * @returns {Object|null} Declaration with its kind, or null if not found
+ */
+const find_go_declaration = (file, symbol) => {
+  const [first, second] = symbol.split('.');
+
+  if (second) {
+    const method = file.functions.find(
+      (fn) => fn.receiver && fn.receiver.type === first && fn.name === second
+    );
+    return method ? { kind: 'method', decl: method } : null;
+  }
+
+  const fn = file.functions.find((f) => !f.receiver && f.name === symbol);
+  if (fn) return { kind: 'function', decl: fn };
+
+  const type = file.types.find((t) => t.name === symbol);
+  if (type) return { kind: 'type', decl: type };
+
+  for (const [kind, decls] of [
+    ['const', file.consts],
+    ['var', file.vars]
+  ]) {
+    const decl = decls.find((d) => d.names.includes(symbol));
+    if (decl) return { kind, decl };
+  }
+
+  const methods = file.functions.filter(
+    (f) => f.receiver && f.name === symbol
+  );
+  return methods.length === 1 ? { kind: 'method', decl: methods[0] } : null;
+};
+
+/**
+ * Describe a declaration in a form that can be compared.
+ * @param {Object} found - Declaration (from find_go_declaration)
+ * @param {string} symbol - The symbol name that was looked up
+ * @returns {Object} Declaration summary with kind, signature and structure
+ */
+const describe_go_declaration = ({ kind, decl }, symbol) => {
+  const base = {
+    kind,
+    line: decl.line,
+    end_line: decl.end_line,
+    doc: decl.doc || null
+  };
+
+  if (kind === 'function' || kind === 'method') {
+    return {
+      ...base,
+      name: decl.name,
+      signature: normalize_text(decl.signature),
+      receiver: decl.receiver
+        ? `${decl.receiver.pointer ? '*' : ''}${decl.receiver.type}`
+        : null,
+      receiver_name: decl.receiver ? decl.receiver.name : null,
+      type_params: normalize_text(decl.type_params) || null,
+      params: format_param_types(decl.params),
+      param_names: decl.params.map((p) => p.name || '_').join(', '),
+      results: format_param_types(decl.results),
+      body: decl.body ? normalize_text(decl.body) : null,
+      body_lines: decl.body ? decl.end_line - decl.body_line + 1 : 0
+    };
+  }
+
+  if (kind === 'type') {
+    return {
+      ...base,
+      name: decl.name,
+      type_kind: decl.kind,
+      type_params: normalize_text(decl.type_params) || null,
+      underlying:
+        decl.kind === 'struct' || decl.kind === 'interface'
+          ? null
+          : normalize_text(decl.underlying),
+      fields: decl.fields.flatMap(function describe_field(field) {
+        return field.names.map((name) => ({
+          name,
+          type: normalize_text(field.type),
+          tag: field.tag,
+          embedded: field.embedded
+        }));
+      }),
+      methods: decl.methods.map(function describe_method(method) {
+        return {
+          name: method.name,
+          signature: normalize_text(
+            `(${method.params_text}) ${method.results_text}`
+          )
+        };
+      }),
+      embeds: decl.embeds.map((e) => normalize_text(e.type))
+    };
+  }
+
+  // Constants and variables: pick the value matching the symbol's position
+  const index = decl.names.indexOf(symbol);
+  return {
+    ...base,
+    name: symbol,
+    type: decl.type ? normalize_text(decl.type) : null,
+    value: decl.values[index] ? normalize_text(decl.values[index]) : null
+  };
+};
+
+/**
+ * Compare two lists of named members (struct fields or interface methods).
+ * Changes to unexported struct fields are not API changes.  Adding a
+ * field is compatible, while adding a method to an interface breaks its
+ * implementations.
+ * @param {string} aspect - 'field' or 'method'
+ * @param {Object[]} before - Members in the first file
+ * @param {Object[]} after - Members in the second file
+ * @param {string[]} keys - Member properties to compare
+ * @returns {Object[]} Change records
+ */
+const compare_members = (aspect, before, after, keys) => {
+  const changes = [];
+  const after_by_name = new Map(after.map((m) => [m.name, m]));
+  const before_names = new Set(before.map((m) => m.name));
+  const is_api = (name) => aspect === 'method' || is_exported(name);
+
+  for (const member of before) {
+    const other = after_by_name.get(member.name);
+    if (!other) {
+      changes.push({
+        aspect,
+        change: 'removed',
+        name: member.name,
+        before: member,
+        after: null,
+        breaking: is_api(member.name)
+      });
+      continue;
+    }
+    for (const key of keys) {
+      if (member[key] !== other[key]) {
+        changes.push({
+          aspect,
+          change: 'changed',
+          name: member.name,
+          property: key,
+          before: member[key],
+          after: other[key],
+          breaking: is_api(member.name)
+        });
+      }
+    }
+  }
+
+  for (const member of after) {
+    if (before_names.has(member.name)) continue;
+    changes.push({
+      aspect,
+      change: 'added',
+      name: member.name,
+      before: null,
+      after: member,
+      breaking: aspect === 'method'
+    });
+  }
+
+  return changes;
+};
+
+/**
+ * Compare two descriptions of the same symbol.
+ * Signature, receiver, type parameter, structure and value changes are
+ * API changes; doc comment and body changes are reported but are not.
+ * @param {Object} a - Description from the first file (from describe_go_declaration)
+ * @param {Object} b - Description from the second file
+ * @returns {Object[]} Change records with aspect, before, after and a breaking flag
+ */
+const compare_go_declarations = (a, b) => {
+  const changes = [];
+  const check = (aspect, breaking) => {
+    if ((a[aspect] ?? null) !== (b[aspect] ?? null)) {
+      changes.push({
+        aspect,
+        change: 'changed',
+        before: a[aspect] ?? null,
+        after: b[aspect] ?? null,
+        breaking
+      });
+    }
+  };
+
+  check('kind', true);
+  if (a.kind !== b.kind) return changes;
+
+  if (a.kind === 'function' || a.kind === 'method') {
+    check('receiver', true);
+    check('type_params', true);
+    check('params', true);
+    check('results', true);
+    check('receiver_name', false);
+    check('param_names', false);
+    check('doc', false);
+    if (a.body !== b.body) {
+      changes.push({
+        aspect: 'body',
+        change: 'changed',
+        before: a.body_lines,
+        after: b.body_lines,
+        breaking: false
+      });
+    }
+    return changes;
+  }
+
+  if (a.kind === 'type') {
+    check('type_kind', true);
+    check('type_params', true);
+    check('underlying', true);
+    changes.push(
+      ...compare_members('field', a.fields, b.fields, [
+        'type',
+        'tag',
+        'embedded'
+      ]),
+      ...compare_members('method', a.methods, b.methods, ['signature'])
+    );
+    const embeds_a = a.embeds.join(', ');
+    const embeds_b = b.embeds.join(', ');
+    if (embeds_a !== embeds_b) {
+      changes.push({
+        aspect: 'embeds',
+        change: 'changed',
+        before: embeds_a,
+        after: embeds_b,
+        breaking: true
+      });
+    }
+    check('doc', false);
+    return changes;
+  }
+
+  check('type', true);
+  check('value', a.kind === 'const');
+  check('doc', false);
+  return changes;
+};
+
+/**
+ * Compare a symbol's declaration between two Go sources.
+ * @param {string} symbol - Symbol name (e.g. `Parse` or `Config.Validate`)
+ * @param {Object} file_a - First file with filename and source
+ * @param {Object} file_b - Second file with filename and source
+ * @returns {Object} Comparison with status, both declarations and the changes
+ */
+const compare_symbol_sources = (symbol, file_a, file_b) => {
+  const find = (file) => {
+    const found = find_go_declaration(
+      parse_go_file(file.source, file.filename),
+      symbol
+    );
+    return found ? describe_go_declaration(found, symbol) : null;
+  };
+
+  const a = find(file_a);
+  const b = find(file_b);
+
+  let status;
+  let changes = [];
+  if (!a && !b) {
+    status = 'missing';
+  } else if (!a) {
+    status = 'missing_in_a';
+  } else if (!b) {
+    status = 'missing_in_b';
+  } else {
+    changes = compare_go_declarations(a, b);
+    status = changes.length === 0 ? 'identical' : 'changed';
+  }
+
+  const breaking = changes.filter((c) => c.breaking);
+
+  return {
+    symbol,
+    file_a: file_a.filename,
+    file_b: file_b.filename,
+    status,
+    a,
+    b,
+    changes,
+    summary: {
+      change_count: changes.length,
+      breaking_count: breaking.length,
+      api_preserved: a !== null && b !== null && breaking.length === 0
+    }
+  };
+};
+
+/**
+ * Compare a symbol's declaration between two Go files of a project.
+ * @param {number} project_id - The project ID
+ * @param {string} symbol - Symbol name (e.g. `Parse` or `Config.Validate`)
+ * @param {string} file_a - First filename
+ * @param {string} file_b - Second filename
+ * @returns {Promise<Object>} Comparison (see compare_symbol_sources)
+ * @throws {Error} If either file is not part of the project
+ */
+const compare_symbol = async (project_id, symbol, file_a, file_b) => {
+  const load = async (filename) => {
+    const rows = await get_sourcecode({ project_id, filename });
+    if (rows.length === 0) {
+      throw new Error(`File '${filename}' not found in project`);
+    }
+    return { filename, source: rows[0].source };
+  };
+
+  return compare_symbol_sources(symbol, await load(file_a), await load(file_b));
+};
+
+// ============================================================================
+// DUPLICATE METHODS
+// ============================================================================
+
+/**
+ * Get the names a method declares locally: its receiver, parameters,
+ * named results and the variables defined in its body.
+ * @param {Object} fn - Method (from the Go parser)
+ * @returns {Set<string>} Local names
+ */
+const get_method_locals = (fn) => {
+  const locals = new Set();
+  for (const param of [...fn.params, ...fn.results]) {
+    if (param.name) locals.add(param.name);
+  }
+  for (const assignment of find_go_assignments(fn.body)) {
+    if (assignment.kind !== 'define') continue;
+    for (const target of assignment.targets) locals.add(target.base);
+  }
+  const body = strip_comments(fn.body || '');
+  for (const match of body.matchAll(/\bvar\s+(\w+(?:\s*,\s*\w+)*)/g)) {
+    for (const name of match[1].split(/\s*,\s*/)) locals.add(name);
+  }
+  locals.delete('_');
+  return locals;
+};
+
+/**
+ * Count the statements of a function body: its non-blank lines once
+ * comments are removed, not counting lines holding only closing brackets.
+ * @param {string} body - Function body, with its braces
+ * @returns {number} Statement count
+ */
+const count_body_statements = (body) => {
+  const inner = strip_comments(body || '').slice(1, -1);
+  return inner
+    .split('\n')
+    .map((line) => line.trim())
+    .filter((line) => line.length > 0 && !/^[)}\]]+[,;]?$/.test(line)).length;
+};
+
+/**
+ * Normalize a method into structural tokens, so that methods with the
+ * same logic on different types compare equal.  The receiver becomes
+ * `$recv`, the receiver's type `$type`, and the parameters and locals
+ * `$0`, `$1`, ... in order of appearance; comments and layout are
+ * ignored.  Field and method names, literals and everything else are
+ * kept.
+ * @param {Object} fn - Method (from the Go parser)
+ * @returns {Object} Tokens, the receiver fields read or written and the receiver methods called
+ */
+const normalize_method = (fn) => {
+  const receiver = fn.receiver.name;
+  const type_name = get_base_type(fn.receiver.type).replace(/^.*\./, '');
+  const locals = get_method_locals(fn);
+  const placeholders = new Map();
+  const text = strip_comments(
+    `(${fn.params_text}) (${fn.results_text}) ${fn.body}`
+  );
+  const raw = text.match(GO_TOKEN_PATTERN) || [];
+  const tokens = [];
+  const fields = new Set();
+  const calls = new Set();
+
+  raw.forEach(function normalize_token(token, i) {
+    const selector = raw[i - 1] === '.';
+    if (selector || !/^[A-Za-z_]/.test(token)) {
+      tokens.push(token);
+    } else if (receiver && token === receiver) {
+      tokens.push('$recv');
+      if (raw[i + 1] === '.' && raw[i + 2]) {
+        (raw[i + 3] === '(' ? calls : fields).add(raw[i + 2]);
+      }
+    } else if (token === type_name) {
+      tokens.push('$type');
+    } else if (locals.has(token)) {
+      if (!placeholders.has(token)) {
+        placeholders.set(token, `$${placeholders.size}`);
+      }
+      tokens.push(placeholders.get(token));
+    } else {
+      tokens.push(token);
+    }
+  });
+
+  return { tokens, fields: [...fields], calls: [...calls] };
+};
+
+/**
+ * Suggest how to remove a group of duplicate methods.  Methods that do
+ * not use their receiver can call one package function; methods that
+ * only use fields declared identically by every type can move, with
+ * those fields, to an embedded struct; otherwise the logic can move to
+ * a helper taking the values it needs.
+ * @param {Object[]} methods - Duplicate methods with their type declarations
+ * @param {Object} usage - Receiver fields and methods used (from normalize_method)
+ * @returns {Object} Suggestion with kind and message
+ */
+const get_duplicate_method_suggestion = (methods, usage) => {
+  const types = methods.map((m) => m.type);
+  if (usage.fields.length === 0 && usage.calls.length === 0) {
+    return {
+      kind: 'function',
+      message: `Extract the body to a package function called from ${types.join(', ')}`
+    };
+  }
+
+  const same_package = methods.every(
+    (m) => m.directory === methods[0].directory
+  );
+  const field_type = (decl, name) => {
+    const field = decl && decl.fields.find((f) => f.names.includes(name));
+    return field ? field.type.replace(/\s+/g, '') : null;
+  };
+  const shared_fields = usage.fields.every(function is_shared_field(name) {
+    const first = field_type(methods[0].decl, name);
+    return (
+      first !== null &&
+      methods.every((m) => field_type(m.decl, name) === first)
+    );
+  });
+
+  if (same_package && usage.calls.length === 0 && shared_fields) {
+    return {
+      kind: 'embed',
+      message: `Move ${usage.fields.join(', ')} to a struct embedded by ${types.join(', ')} and define ${methods[0].name} once on it`
+    };
+  }
+  return {
+    kind: 'helper',
+    message: `Extract the shared logic of ${types.join(', ')} to a helper taking the values it uses`
+  };
+};
+
+/**
+ * Find methods on different types with identical bodies, ignoring the
+ * receiver's name and type, comments and the names of parameters and
+ * locals.  Unlike detect_code_duplication, which estimates the token
+ * similarity of any two functions, this compares the structure of
+ * method sets exactly to find logic that could be written once.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {number} [options.min_statements=2] - Minimum statements of a reported method
+ * @returns {Object[]} Groups of duplicate methods, largest first
+ */
+const find_duplicate_methods = (packages, options = {}) => {
+  const min_statements =
+    options.min_statements || MIN_DUPLICATE_METHOD_STATEMENTS;
+  const groups = new Map();
+
+  for (const pkg of packages) {
+    const types = new Map(pkg.types.map((t) => [t.name, t]));
+    for (const fn of pkg.functions) {
+      if (!fn.receiver || !fn.body) continue;
+      const statements = count_body_statements(fn.body);
+      if (statements < min_statements) continue;
+
+      const normalized = normalize_method(fn);
+      const key = normalized.tokens.join(' ');
+      const type = get_base_type(fn.receiver.type);
+      if (!groups.has(key)) {
+        groups.set(key, { usage: normalized, methods: [] });
+      }
+      groups.get(key).methods.push({
+        type,
+        name: fn.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        end_line: fn.end_line,
+        lines: fn.end_line - fn.line + 1,
+        statements,
+        decl: types.get(type)
+      });
+    }
+  }
+
+  const duplicates = [];
+  for (const [key, group] of groups) {
+    const types = new Set(
+      group.methods.map((m) => `${m.directory}:${m.type}`)
+    );
+    if (types.size < 2) continue;
+
+    const methods = group.methods.map(({ decl, ...method }) => method);
+    duplicates.push({
+      hash: createHash('sha1').update(key).digest('hex').slice(0, 12),
+      names: [...new Set(methods.map((m) => m.name))],
+      types: [...new Set(methods.map((m) => m.type))],
+      methods,
+      statements: methods[0].statements,
+      duplicated_lines: methods.slice(1).reduce((sum, m) => sum + m.lines, 0),
+      receiver_fields: group.usage.fields,
+      receiver_calls: group.usage.calls,
+      suggestion: get_duplicate_method_suggestion(group.methods, group.usage)
+    });
+  }
+
+  return duplicates.sort(function sort_by_duplicated_lines(a, b) {
+    if (a.duplicated_lines !== b.duplicated_lines) {
+      return b.duplicated_lines - a.duplicated_lines;
+    }
+    return b.methods.length - a.methods.length;
+  });
+};
+
+/**
+ * Find methods with identical bodies across the types of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_duplicate_methods)
+ * @returns {Promise<Object>} Duplicate method groups with a summary
+ */
+const analyze_project_duplicate_methods = async (project_id, options = {}) => {
+  const groups = find_duplicate_methods(
+    await load_go_packages(project_id),
+    options
+  );
+
+  return {
+    groups,
+    summary: {
+      total_groups: groups.length,
+      duplicate_methods: groups.reduce((sum, g) => sum + g.methods.length, 0),
+      types_involved: new Set(
+        groups.flatMap((g) => g.methods.map((m) => `${m.directory}:${m.type}`))
+      ).size,
+      duplicated_lines: groups.reduce((sum, g) => sum + g.duplicated_lines, 0),
+      by_suggestion: groups.reduce(function count_suggestions(counts, g) {
+        counts[g.suggestion.kind] = (counts[g.suggestion.kind] || 0) + 1;
+        return counts;
+      }, {})
+    }
+  };
+};
+
+export {
+  analyze_rename_impact,
+  analyze_project_duplicate_methods,
+  find_duplicate_methods,
+  normalize_method,
+  count_body_statements,
+  compare_symbol,
+  compare_symbol_sources,
+  compare_go_declarations,
+  describe_go_declaration,
+  find_go_declaration,
+  detect_rename_conflicts,
+  validate_new_name,
+  filter_definition_references,
+  build_rename_edits,
+  find_enclosing_entity,
+  get_package_for_file
+};
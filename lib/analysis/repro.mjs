@@ -0,0 +1,565 @@
+'use strict';
+
+/**
+ * @fileoverview Go minimal reproduction module.
+ * Extracts a symbol and the declarations it depends on into a single,
+ * self-contained Go file, for filing a bug report or focusing a prompt on
+ * one piece of code.  Declarations of the symbol's package are copied as
+ * written, with their doc comments; methods are only copied when they are
+ * called or needed by a copied interface.
+ *
+ * Dependencies on other packages are stubbed so that the file stands on
+ * its own:
+ * - Standard library imports are kept
+ * - Project packages get renamed copies of their types, constants and
+ *   variables (`units.Length` becomes `units_Length`), and functions and
+ *   methods whose body panics
+ * - Other packages get opaque placeholders (`type render_Canvas
+ *   struct{}`, `func render_New(...any) any`, `var render_Default any`)
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/repro
+ */
+
+import {
+  mask_source,
+  find_matching,
+  get_base_type,
+  parse_go_file,
+  group_go_packages,
+  load_go_sources
+} from './golang.mjs';
+import { get_imported_packages } from './graph.mjs';
+import { get_doc_start } from './impact.mjs';
+
+/**
+ * Keywords after which a qualified name is a value rather than a type.
+ */
+const VALUE_KEYWORDS = new Set([
+  'return',
+  'case',
+  'go',
+  'defer',
+  'if',
+  'for',
+  'switch',
+  'range',
+  'else'
+]);
+
+/**
+ * Body of stubbed functions and methods.
+ */
+const STUB_BODY = '{\n\tpanic("stub")\n}';
+
+// ============================================================================
+// DECLARATIONS
+// ============================================================================
+
+/**
+ * Index the package level declarations of a package by name, and its
+ * methods by receiver type.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object} Symbols by name and methods by receiver type
+ */
+const build_declaration_index = (pkg) => {
+  const symbols = new Map();
+  const methods = new Map();
+
+  for (const fn of pkg.functions) {
+    if (!fn.name || fn.name === '_' || fn.name === 'init') continue;
+    if (fn.receiver) {
+      const receiver = get_base_type(fn.receiver.type);
+      if (!methods.has(receiver)) methods.set(receiver, []);
+      methods.get(receiver).push(fn);
+    } else {
+      symbols.set(fn.name, { kind: 'function', declaration: fn });
+    }
+  }
+  for (const type of pkg.types) {
+    symbols.set(type.name, { kind: 'type', declaration: type });
+  }
+  for (const [decls, kind] of [
+    [pkg.consts, 'const'],
+    [pkg.vars, 'var']
+  ]) {
+    for (const decl of decls) {
+      for (const name of decl.names.filter((n) => n !== '_')) {
+        symbols.set(name, { kind, declaration: decl });
+      }
+    }
+  }
+
+  return { symbols, methods };
+};
+
+/**
+ * Find the last line of a grouped const or var declaration, the line of
+ * its closing parenthesis.
+ * @param {string[]} lines - Source lines
+ * @param {number} group_line - Line of the `const (` or `var (` keyword
+ * @returns {number} Last line of the group
+ */
+const find_group_end = (lines, group_line) => {
+  const masked = mask_source(lines.slice(group_line - 1).join('\n'));
+  const close = find_matching(masked, masked.indexOf('('));
+  if (close === -1) return lines.length;
+  return group_line + (masked.substring(0, close).match(/\n/g) || []).length;
+};
+
+/**
+ * Get the source range of a declaration, including its doc comment.
+ * Grouped constants and variables are copied with their whole group, so
+ * that iota and implicit repetition keep their meaning; grouped types are
+ * copied on their own with a `type` keyword.
+ * @param {Object} declaration - Declaration (from the Go parser)
+ * @param {string} kind - function, method, type, const or var
+ * @param {string[]} lines - Source lines of the declaration's file
+ * @returns {Object} First and last lines, and whether the type is grouped
+ */
+const get_declaration_range = (declaration, kind, lines) => {
+  if ((kind === 'const' || kind === 'var') && declaration.grouped) {
+    return {
+      start: get_doc_start(lines, declaration.group_line),
+      end: find_group_end(lines, declaration.group_line),
+      grouped: false
+    };
+  }
+  const grouped =
+    kind === 'type' && !/^\s*type\b/.test(lines[declaration.line - 1] || '');
+  return {
+    start: get_doc_start(lines, declaration.line),
+    end: declaration.end_line || declaration.line,
+    grouped
+  };
+};
+
+/**
+ * Get the text a declaration is copied as.  Functions and methods of
+ * other packages are stubbed, keeping their signature.
+ * @param {Object} unit - Declaration unit
+ * @param {Map<string, string[]>} lines - Source lines by filename
+ * @returns {string} Declaration text
+ */
+const get_declaration_text = (unit, lines) => {
+  if (unit.stubbed) return `${unit.declaration.signature} ${STUB_BODY}`;
+
+  const file_lines = lines.get(unit.filename) || [];
+  const range = get_declaration_range(unit.declaration, unit.kind, file_lines);
+  const text = file_lines.slice(range.start - 1, range.end);
+  if (!range.grouped) return text.join('\n');
+
+  // Lift a `type (...)` entry out of its group
+  const dedented = text.map((line) => line.replace(/^\t/, ''));
+  const index = unit.declaration.line - range.start;
+  dedented[index] = `type ${dedented[index]}`;
+  return dedented.join('\n');
+};
+
+// ============================================================================
+// REFERENCES
+// ============================================================================
+
+/**
+ * Find the identifiers of masked Go code.  Identifiers following a dot
+ * are selectors; other identifiers followed by a dot carry the selected
+ * member, for package qualified names (`fmt.Sprintf`).
+ * @param {string} masked - Masked source (from mask_source)
+ * @returns {Object[]} Identifiers with offsets, member and selector flag
+ */
+const find_references = (masked) => {
+  const references = [];
+  const pattern = /(?<!\w)[A-Za-z_]\w*/g;
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    const before = masked.substring(0, match.index).trimEnd();
+    const reference = {
+      name: match[0],
+      start: match.index,
+      end: match.index + match[0].length,
+      selector: before.endsWith('.'),
+      member: null,
+      member_end: null
+    };
+    const after = masked
+      .substring(reference.end)
+      .match(/^\s*\.\s*([A-Za-z_]\w*)/);
+    if (after && !reference.selector) {
+      reference.member = after[1];
+      reference.member_end = reference.end + after[0].length;
+    }
+    references.push(reference);
+  }
+
+  return references;
+};
+
+/**
+ * Find the offsets of the names a declaration declares rather than
+ * references: method names, and the field and method names of struct and
+ * interface types.
+ * @param {string} masked - Masked declaration text
+ * @param {Object} unit - Declaration unit
+ * @returns {Set<number>} Offsets of declared names
+ */
+const find_declared_names = (masked, unit) => {
+  const offsets = new Set();
+
+  if (unit.kind === 'method') {
+    const open = masked.search(/\bfunc\s*\(/);
+    const close = find_matching(masked, masked.indexOf('(', open));
+    const name = masked.substring(close + 1).match(/^\s*/);
+    offsets.add(close + 1 + name[0].length);
+  }
+
+  if (unit.kind === 'type') {
+    const names = new Set([
+      ...unit.declaration.fields
+        .filter((f) => !f.embedded)
+        .flatMap((f) => f.names),
+      ...unit.declaration.methods.map((m) => m.name)
+    ]);
+    for (const reference of find_references(masked)) {
+      const before = masked.substring(0, reference.start).trimEnd();
+      if (names.has(reference.name) && /(^|[\n{;,])$/.test(before)) {
+        offsets.add(reference.start);
+      }
+    }
+  }
+
+  return offsets;
+};
+
+/**
+ * Classify an unresolved package qualified name from how it is used:
+ * called names become functions, names in type position become types
+ * and other names become variables.
+ * @param {string} masked - Masked declaration text
+ * @param {Object} reference - Qualified reference (from find_references)
+ * @param {Object} unit - Declaration unit
+ * @returns {string} func, type or var
+ */
+const classify_opaque_reference = (masked, reference, unit) => {
+  const before = masked.substring(0, reference.start).trimEnd();
+  const next = masked.substring(reference.member_end).trimStart()[0];
+  const previous = before[before.length - 1] || '';
+  const word = (before.match(/([A-Za-z_]\w*)$/) || [])[1];
+
+  if (/[*\][]/.test(previous)) return 'type';
+  if (next === '(') return 'func';
+  if (next === '{' || unit.kind === 'type') return 'type';
+  if (word && !VALUE_KEYWORDS.has(word)) return 'type';
+  return 'var';
+};
+
+/**
+ * Render the placeholder of a declaration of an unknown package.
+ * @param {Object} stub - Opaque stub with name and kind
+ * @returns {string} Go declaration
+ */
+const render_opaque_stub = (stub) => {
+  if (stub.kind === 'func') return `func ${stub.name}(...any) any ${STUB_BODY}`;
+  if (stub.kind === 'type') return `type ${stub.name} struct{}`;
+  return `var ${stub.name} any`;
+};
+
+// ============================================================================
+// MINIMAL REPRODUCTION
+// ============================================================================
+
+/**
+ * Find the declaration of a symbol: `Name`, `Type.Method`, optionally
+ * qualified by package name or directory (`geo.Circle.Area`).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} symbol - Symbol name
+ * @returns {Object} Package, kind and declaration of the symbol
+ * @throws {Error} If the symbol is not found or is ambiguous
+ */
+const find_repro_symbol = (packages, symbol) => {
+  const parts = symbol.split('.');
+  const candidates = [];
+
+  const add = (qualifier, type, name) => {
+    for (const pkg of packages) {
+      if (qualifier && pkg.name !== qualifier && pkg.directory !== qualifier) {
+        continue;
+      }
+      const index = build_declaration_index(pkg);
+      if (type) {
+        const method = (index.methods.get(type) || []).find(
+          (m) => m.name === name
+        );
+        if (method) {
+          candidates.push({ pkg, kind: 'method', declaration: method });
+        }
+      } else if (index.symbols.has(name)) {
+        candidates.push({ pkg, ...index.symbols.get(name) });
+      }
+    }
+  };
+
+  if (parts.length === 1) add(null, null, parts[0]);
+  if (parts.length === 2) {
+    add(parts[0], null, parts[1]);
+    add(null, parts[0], parts[1]);
+  }
+  if (parts.length >= 3) {
+    add(parts.slice(0, -2).join('.'), parts[parts.length - 2], parts.at(-1));
+  }
+
+  if (candidates.length === 0) {
+    throw new Error(`Symbol '${symbol}' not found in project`);
+  }
+  if (candidates.length > 1) {
+    throw new Error(
+      `Symbol '${symbol}' is ambiguous (found in: ${candidates.map((c) => c.pkg.directory).join(', ')})`
+    );
+  }
+  return candidates[0];
+};
+
+/**
+ * Build the minimal reproduction of a symbol: a Go file with the symbol
+ * and the declarations it transitively depends on.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} symbol - Symbol name (see find_repro_symbol)
+ * @param {Object} options - Options
+ * @param {Map<string, string[]>} options.lines - Source lines by filename
+ * @returns {Object} Go source, copied declarations, stubs and imports
+ * @throws {Error} If the symbol is not found or is ambiguous
+ */
+const build_minimal_repro = (packages, symbol, options) => {
+  const lines = options.lines;
+  const target = find_repro_symbol(packages, symbol);
+  const indexes = new Map(packages.map((p) => [p, build_declaration_index(p)]));
+
+  const units = new Map();
+  const queue = [];
+  const selectors = new Set();
+  const imports = new Map();
+  const opaque = new Map();
+  const prefixes = new Map();
+
+  const get_prefix = (pkg) => {
+    if (!prefixes.has(pkg)) {
+      const base = pkg.name.replace(/\W/g, '_');
+      const taken = new Set(prefixes.values());
+      let prefix = base;
+      for (let i = 2; taken.has(prefix) || prefix === target.pkg.name; i++) {
+        prefix = `${base}${i}`;
+      }
+      prefixes.set(pkg, prefix);
+    }
+    return prefixes.get(pkg);
+  };
+
+  const add_unit = (pkg, kind, declaration) => {
+    // Grouped constants and variables share the unit of their group
+    const line =
+      (kind === 'const' || kind === 'var') && declaration.grouped
+        ? declaration.group_line
+        : declaration.line;
+    const id = `${declaration.filename}:${kind}:${line}`;
+    if (units.has(id)) return;
+    const unit = {
+      id,
+      pkg,
+      kind,
+      declaration,
+      filename: declaration.filename,
+      stubbed:
+        pkg !== target.pkg && (kind === 'function' || kind === 'method')
+    };
+    unit.text = get_declaration_text(unit, lines);
+    units.set(id, unit);
+    queue.push(unit);
+  };
+
+  const process_unit = (unit) => {
+    const masked = mask_source(unit.text);
+    const index = indexes.get(unit.pkg);
+    const declared = find_declared_names(masked, unit);
+    const file_imports = new Map(
+      unit.pkg.imports
+        .filter((imp) => imp.filename === unit.filename)
+        .map((imp) => [imp.name || imp.path.split('/').pop(), imp])
+    );
+    const imported = get_imported_packages(unit.pkg, unit.filename, packages);
+    const replacements = [];
+
+    if (unit.declaration.kind === 'interface') {
+      for (const method of unit.declaration.methods) selectors.add(method.name);
+    }
+
+    for (const reference of find_references(masked)) {
+      if (reference.selector) {
+        selectors.add(reference.name);
+        continue;
+      }
+      if (declared.has(reference.start)) continue;
+
+      const imp = file_imports.get(reference.name);
+      if (imp && reference.member && !index.symbols.has(reference.name)) {
+        const project = imported.get(reference.name);
+        const dependency =
+          project && indexes.get(project).symbols.get(reference.member);
+        let replacement;
+        if (dependency) {
+          add_unit(project, dependency.kind, dependency.declaration);
+          replacement = `${get_prefix(project)}_${reference.member}`;
+        } else if (!project && !imp.path.split('/')[0].includes('.')) {
+          imports.set(imp.path, imp);
+          continue;
+        } else {
+          const key = `${imp.path}:${reference.member}`;
+          if (!opaque.has(key)) {
+            opaque.set(key, {
+              name: `${reference.name.replace(/\W/g, '_')}_${reference.member}`,
+              kind: classify_opaque_reference(masked, reference, unit),
+              path: imp.path,
+              member: reference.member
+            });
+          }
+          replacement = opaque.get(key).name;
+        }
+        replacements.push([reference.start, reference.member_end, replacement]);
+        continue;
+      }
+
+      const dependency = index.symbols.get(reference.name);
+      if (!dependency) continue;
+      add_unit(unit.pkg, dependency.kind, dependency.declaration);
+      if (unit.pkg !== target.pkg) {
+        const name = `${get_prefix(unit.pkg)}_${reference.name}`;
+        replacements.push([reference.start, reference.end, name]);
+      }
+    }
+
+    let text = unit.text;
+    for (const [start, end, replacement] of replacements.reverse()) {
+      text = text.substring(0, start) + replacement + text.substring(end);
+    }
+    unit.output = text;
+  };
+
+  add_unit(target.pkg, target.kind, target.declaration);
+  for (;;) {
+    while (queue.length > 0) process_unit(queue.shift());
+
+    // Copy the methods of copied types that are called or that copied
+    // interfaces need, until no more are added
+    for (const unit of [...units.values()]) {
+      if (unit.kind !== 'type' || unit.declaration.kind === 'interface') {
+        continue;
+      }
+      const methods = indexes.get(unit.pkg).methods.get(unit.declaration.name);
+      for (const method of methods || []) {
+        if (selectors.has(method.name)) add_unit(unit.pkg, 'method', method);
+      }
+    }
+    if (queue.length === 0) break;
+  }
+
+  const sorted = [...units.values()].sort(function sort_by_location(a, b) {
+    if ((a.pkg === target.pkg) !== (b.pkg === target.pkg)) {
+      return a.pkg === target.pkg ? -1 : 1;
+    }
+    if (a.pkg !== b.pkg) return get_prefix(a.pkg) < get_prefix(b.pkg) ? -1 : 1;
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.declaration.line - b.declaration.line;
+  });
+  const copied = sorted.filter((u) => u.pkg === target.pkg);
+  const stubs = [
+    ...sorted.filter((u) => u.pkg !== target.pkg).map((u) => u.output),
+    ...[...opaque.values()]
+      .sort(function sort_by_name(a, b) {
+        return a.name < b.name ? -1 : 1;
+      })
+      .map(render_opaque_stub)
+  ];
+
+  const out = [`package ${target.pkg.name}`];
+  const import_list = [...imports.values()].sort(function sort_by_path(a, b) {
+    return a.path < b.path ? -1 : 1;
+  });
+  const specs = import_list.map(
+    (imp) => `${imp.name ? `${imp.name} ` : ''}"${imp.path}"`
+  );
+  if (specs.length === 1) out.push(`import ${specs[0]}`);
+  if (specs.length > 1) {
+    out.push(`import (\n${specs.map((s) => `\t${s}`).join('\n')}\n)`);
+  }
+  out.push(...copied.map((u) => u.output));
+  if (stubs.length > 0) {
+    out.push(`// Stubs of the dependencies outside package ${target.pkg.name}.`);
+    out.push(...stubs);
+  }
+  const has_main = copied.some(
+    (u) => u.kind === 'function' && u.declaration.name === 'main'
+  );
+  if (target.pkg.name === 'main' && !has_main) out.push('func main() {}');
+
+  return {
+    symbol,
+    package: target.pkg.name,
+    directory: target.pkg.directory,
+    source: out.join('\n\n') + '\n',
+    declarations: sorted.map((u) => ({
+      name:
+        u.kind === 'method'
+          ? `${get_base_type(u.declaration.receiver.type)}.${u.declaration.name}`
+          : u.declaration.name || u.declaration.names.join(', '),
+      kind: u.kind,
+      package: u.pkg.name,
+      directory: u.pkg.directory,
+      filename: u.filename,
+      line: u.declaration.line,
+      stubbed: u.pkg !== target.pkg
+    })),
+    stubs: [...opaque.values()].map((s) => ({
+      name: s.name,
+      kind: s.kind,
+      path: s.path,
+      member: s.member
+    })),
+    imports: import_list.map((imp) => imp.path)
+  };
+};
+
+/**
+ * Build the minimal reproduction of a symbol of a set of Go sources.
+ * Test files are ignored.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {string} symbol - Symbol name (see find_repro_symbol)
+ * @returns {Object} Minimal reproduction (see build_minimal_repro)
+ * @throws {Error} If the symbol is not found or is ambiguous
+ */
+const minimal_repro_from_sources = (sources, symbol) => {
+  const lines = new Map();
+  const files = sources
+    .filter((file) => !file.filename.endsWith('_test.go'))
+    .map(function parse_source(file) {
+      lines.set(file.filename, file.source.split('\n'));
+      return parse_go_file(file.source, file.filename);
+    });
+  return build_minimal_repro(group_go_packages(files), symbol, { lines });
+};
+
+/**
+ * Build the minimal reproduction of a symbol of a project.
+ * @param {number} project_id - The project ID
+ * @param {string} symbol - Symbol name (see find_repro_symbol)
+ * @returns {Promise<Object>} Minimal reproduction (see build_minimal_repro)
+ * @throws {Error} If the symbol is not found or is ambiguous
+ */
+const generate_minimal_repro = async (project_id, symbol) => {
+  return minimal_repro_from_sources(await load_go_sources(project_id), symbol);
+};
+
+export {
+  generate_minimal_repro,
+  minimal_repro_from_sources,
+  build_minimal_repro,
+  find_repro_symbol,
+  find_references
+};
@@ -0,0 +1,302 @@
+'use strict';
+
+/**
+ * @fileoverview Go function risk module.
+ * Rolls complexity, coupling and optionally git churn up into a single
+ * risk score per function and method, to prioritize testing and review:
+ * a complex function many others call is where a bug does the most harm,
+ * and one that changes often is where bugs come in.
+ *
+ * The score combines four metrics (see complexity and coupling):
+ * - complexity: the cyclomatic complexity of the body
+ * - fan_in: the number of distinct functions calling it
+ * - fan_out: the number of distinct functions it calls
+ * - churn: the number of commits changing its file, when given
+ * Each metric is normalized on a log scale against the highest value of
+ * the code base, log(1 + x) / log(1 + max), so that a few outliers do not
+ * flatten the others; complexity counts from 0 for this, as the number of
+ * decision points (complexity - 1), so that a straight-line function adds
+ * nothing.  The score is the weighted mean of the normalized metrics,
+ * from 0 to 100:
+ *
+ *   score = 100 * Σ weight(m) * norm(m) / Σ weight(m)
+ *
+ * The default weights favour complexity and fan-in (0.4 and 0.3) over
+ * churn (0.2) and fan-out (0.1), and can be changed.  Without churn its
+ * weight is left out, so that scores do not depend on having a git
+ * repository.  Churn is counted by file: every function of a file shares
+ * its churn.  Functions of test files are left out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/risk
+ */
+
+import {
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { calculate_go_complexity } from './smells.mjs';
+import { compute_fan_metrics } from './coupling.mjs';
+
+/**
+ * Weights of the metrics of the risk score by default.
+ */
+const DEFAULT_RISK_WEIGHTS = {
+  complexity: 0.4,
+  fan_in: 0.3,
+  fan_out: 0.1,
+  churn: 0.2
+};
+
+/**
+ * Number of functions listed as riskiest by default.
+ */
+const DEFAULT_RISK_LIMIT = 20;
+
+// ============================================================================
+// CHURN
+// ============================================================================
+
+/**
+ * Count the commits changing each file from the output of
+ * `git log --format=%x00 --name-only`, where each commit starts with a NUL
+ * line followed by the names of its files.
+ * @param {string} output - Output of git log
+ * @returns {Map<string, number>} Number of commits by filename
+ */
+const parse_git_churn = (output) => {
+  const churn = new Map();
+  for (const commit of (output || '').split('\0')) {
+    const files = new Set(
+      commit
+        .split('\n')
+        .map((line) => line.trim())
+        .filter(Boolean)
+    );
+    for (const file of files) churn.set(file, (churn.get(file) || 0) + 1);
+  }
+  return churn;
+};
+
+/**
+ * Find the churn of a file, by its name or, for files named by absolute
+ * or longer paths, by the churn entry its name ends with.
+ * @param {Map<string, number>} churn - Number of commits by filename
+ * @param {string} filename - File to look up
+ * @returns {number} Number of commits changing the file
+ */
+const get_file_churn = (churn, filename) => {
+  if (churn.has(filename)) return churn.get(filename);
+  for (const [file, count] of churn) {
+    if (filename.endsWith(`/${file}`)) return count;
+  }
+  return 0;
+};
+
+// ============================================================================
+// RISK SCORES
+// ============================================================================
+
+/**
+ * Merge weights with the default ones, checking them.
+ * @param {Object} [weights] - Weights by metric
+ * @returns {Object} Weights of every metric
+ * @throws {Error} On an unknown metric or a negative weight
+ */
+const resolve_risk_weights = (weights = {}) => {
+  for (const [metric, weight] of Object.entries(weights)) {
+    if (!(metric in DEFAULT_RISK_WEIGHTS)) {
+      throw new Error(
+        `Unknown metric '${metric}', expected one of ${Object.keys(DEFAULT_RISK_WEIGHTS).join(', ')}`
+      );
+    }
+    if (typeof weight !== 'number' || !(weight >= 0)) {
+      throw new Error(`Invalid weight for ${metric}: ${weight}`);
+    }
+  }
+  return { ...DEFAULT_RISK_WEIGHTS, ...weights };
+};
+
+/**
+ * Normalize a metric on a log scale against its highest value.
+ * @param {number} value - Metric
+ * @param {number} max - Highest value of the metric
+ * @returns {number} Normalized metric, from 0 to 1
+ */
+const normalize_metric = (value, max) => {
+  return max > 0 ? Math.log1p(value) / Math.log1p(max) : 0;
+};
+
+/**
+ * Get the value of a metric of a function to normalize: the decision
+ * points for complexity, the metric itself otherwise.
+ * @param {Object} fn - Function with its metrics
+ * @param {string} metric - Metric name
+ * @returns {number} Value to normalize
+ */
+const get_metric_value = (fn, metric) => {
+  return metric === 'complexity' ? fn.complexity - 1 : fn[metric];
+};
+
+/**
+ * Round a score to one decimal.
+ * @param {number} value - Score
+ * @returns {number} Rounded score
+ */
+const round_score = (value) => Math.round(value * 10) / 10;
+
+/**
+ * Compute the risk score of every function and method of a set of
+ * packages (see the module doc).
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {Object} [options.weights] - Weights by metric, merged with the default ones
+ * @param {Map<string, number>} [options.churn] - Number of commits by filename (from parse_git_churn)
+ * @returns {Object[]} Functions with their metrics, score and the contribution of each metric, riskiest first
+ */
+const compute_risk_scores = (packages, options = {}) => {
+  const weights = resolve_risk_weights(options.weights);
+  const churn = options.churn || null;
+  const metrics = churn
+    ? Object.keys(weights)
+    : Object.keys(weights).filter((m) => m !== 'churn');
+  const total_weight = metrics.reduce((sum, m) => sum + weights[m], 0);
+  if (total_weight === 0) {
+    throw new Error(`The weights of ${metrics.join(', ')} are all 0`);
+  }
+
+  const bodies = new Map();
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      bodies.set(`${pkg.directory}:${name}:${fn.filename}`, fn.body);
+    }
+  }
+
+  const functions = compute_fan_metrics(packages).map((fn) => ({
+    id: fn.id,
+    name: fn.name,
+    kind: fn.kind,
+    package: fn.package,
+    directory: fn.directory,
+    filename: fn.filename,
+    line: fn.line,
+    complexity: calculate_go_complexity(
+      bodies.get(`${fn.directory}:${fn.name}:${fn.filename}`)
+    ),
+    fan_in: fn.fan_in,
+    fan_out: fn.fan_out,
+    churn: churn ? get_file_churn(churn, fn.filename) : null
+  }));
+
+  const max = Object.fromEntries(
+    metrics.map((m) => [
+      m,
+      functions.reduce((top, fn) => Math.max(top, get_metric_value(fn, m)), 0)
+    ])
+  );
+  return functions
+    .map(function score_function(fn) {
+      const factors = {};
+      let score = 0;
+      for (const metric of metrics) {
+        const value = get_metric_value(fn, metric);
+        const part =
+          (100 * weights[metric] * normalize_metric(value, max[metric])) /
+          total_weight;
+        factors[metric] = round_score(part);
+        score += part;
+      }
+      return { ...fn, score: round_score(score), factors };
+    })
+    .sort(function sort_by_score(a, b) {
+      return b.score - a.score || a.id.localeCompare(b.id);
+    });
+};
+
+/**
+ * Map the functions of a risk report to their score.
+ * @param {Object[]} functions - Functions (from compute_risk_scores)
+ * @returns {Object} Scores by function id
+ */
+const get_risk_scores = (functions) => {
+  return Object.fromEntries(functions.map((fn) => [fn.id, fn.score]));
+};
+
+/**
+ * Summarize the risk scores of a set of functions.
+ * @param {Object[]} functions - Functions (from compute_risk_scores)
+ * @param {Object} [options] - Options (see compute_risk_scores)
+ * @returns {Object} Number of functions, whether churn counts, weights, and mean and highest score
+ */
+const summarize_risk_scores = (functions, options = {}) => {
+  const weights = resolve_risk_weights(options.weights);
+  if (!options.churn) delete weights.churn;
+  return {
+    functions: functions.length,
+    churn: Boolean(options.churn),
+    weights,
+    mean_score:
+      functions.length > 0
+        ? round_score(
+            functions.reduce((sum, fn) => sum + fn.score, 0) / functions.length
+          )
+        : 0,
+    max_score: functions.length > 0 ? functions[0].score : 0
+  };
+};
+
+/**
+ * Build a risk report from scored functions.
+ * @param {Object[]} functions - Functions (from compute_risk_scores)
+ * @param {Object} options - Options (see compute_risk_scores)
+ * @returns {Object} Riskiest functions, scores by id and a summary
+ */
+const build_risk_report = (functions, options) => {
+  return {
+    functions: functions.slice(0, options.limit || DEFAULT_RISK_LIMIT),
+    scores: get_risk_scores(functions),
+    summary: summarize_risk_scores(functions, options)
+  };
+};
+
+/**
+ * Report the riskiest functions of a set of Go sources, such as the files
+ * of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see compute_risk_scores)
+ * @param {number} [options.limit=20] - Number of functions to list
+ * @returns {Object} Riskiest functions, scores by id and a summary
+ */
+const find_source_risk_scores = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const functions = compute_risk_scores(group_go_packages(files), options);
+  return build_risk_report(functions, options);
+};
+
+/**
+ * Report the riskiest functions of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_source_risk_scores)
+ * @returns {Promise<Object>} Riskiest functions, scores by id and a summary
+ */
+const analyze_project_risk_scores = async (project_id, options = {}) => {
+  const functions = compute_risk_scores(
+    await load_go_packages(project_id),
+    options
+  );
+  return build_risk_report(functions, options);
+};
+
+export {
+  analyze_project_risk_scores,
+  find_source_risk_scores,
+  compute_risk_scores,
+  get_risk_scores,
+  summarize_risk_scores,
+  parse_git_churn,
+  DEFAULT_RISK_WEIGHTS,
+  DEFAULT_RISK_LIMIT
+};
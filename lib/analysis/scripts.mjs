@@ -0,0 +1,60 @@
+'use strict';
+
+/**
+ * @fileoverview Go script analysis module.
+ * Lists the Go files excluded from every build with `//go:build ignore`,
+ * typically code generators and maintenance scripts run with `go run`.
+ * They are kept out of the project's packages, so this module reports
+ * them separately along with the `//go:generate` directives that run them.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/scripts
+ */
+
+import { get_comment_text, load_go_project } from './golang.mjs';
+import { get_doc_synopsis } from './godoc.mjs';
+
+/**
+ * Summarize a script.
+ * @param {Object} script - Script package (from group_go_scripts)
+ * @returns {Object} Script with package, doc synopsis, imports, declarations and generators
+ */
+const summarize_go_script = (script) => {
+  const [file] = script.files;
+  const header = file.package_doc || file.file_header;
+
+  return {
+    filename: script.filename,
+    directory: script.directory,
+    package: script.name,
+    synopsis: header ? get_doc_synopsis(get_comment_text(header)) : null,
+    has_main: script.functions.some((f) => !f.receiver && f.name === 'main'),
+    imports: script.imports.map((i) => i.path),
+    functions: script.functions.map((f) =>
+      f.receiver ? `${f.receiver.type}.${f.name}` : f.name
+    ),
+    types: script.types.map((t) => t.name),
+    generated_by: script.generated_by
+  };
+};
+
+/**
+ * List the scripts of a project: Go files excluded by `//go:build ignore`.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Scripts with a summary
+ */
+const analyze_go_scripts = async (project_id) => {
+  const result = await load_go_project(project_id, { include_scripts: true });
+  const scripts = result.scripts.map(summarize_go_script);
+
+  return {
+    scripts,
+    summary: {
+      total_scripts: scripts.length,
+      generators: scripts.filter((s) => s.generated_by.length > 0).length,
+      programs: scripts.filter((s) => s.has_main).length,
+      buildable_packages: result.packages.length
+    }
+  };
+};
+
+export { analyze_go_scripts, summarize_go_script };
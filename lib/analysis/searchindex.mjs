@@ -0,0 +1,377 @@
+'use strict';
+
+/**
+ * @fileoverview Go search index module.
+ * Exports the symbols of a project as flat, denormalized documents for
+ * ingestion by external search engines (Elasticsearch, OpenSearch,
+ * Meilisearch, Typesense...), one JSON document per line.  Unlike the
+ * analysis results, documents have no nesting: each one carries the
+ * package and file it belongs to, so it can be indexed on its own.
+ *
+ * Document schema (see SEARCH_INDEX_FIELDS):
+ * - id: Unique, stable document key: `<directory>:<qualifiedName>`
+ * - qualifiedName: Package qualified name (`geo.Triangle.Area`)
+ * - name: Unqualified name (`Area`)
+ * - kind: function, method, struct, interface, type, field, const or var
+ * - signature: One line declaration without body (`func (t *Triangle) Area() float64`)
+ * - doc: Doc comment text without comment markers, or an empty string
+ * - file: Project relative filename
+ * - line: 1-based line of the declaration
+ * - package: Package name
+ * - directory: Package directory
+ * - exported: Whether the symbol is exported
+ * - fingerprint: Hash of the declaration, changing when the code changes
+ *   and not when only the doc comment or position changes
+ *
+ * Callers can add their own fields with a hook; the schema fields cannot
+ * be overridden.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/searchindex
+ */
+
+import { createHash } from 'crypto';
+import {
+  parse_go_file,
+  group_go_packages,
+  get_base_type,
+  get_comment_text,
+  is_exported,
+  load_go_sources
+} from './golang.mjs';
+import { format_method_signature } from './interfaces.mjs';
+
+/**
+ * Fields of a search index document, with their JSON types.
+ */
+const SEARCH_INDEX_FIELDS = [
+  { name: 'id', type: 'string', description: 'Unique document key' },
+  {
+    name: 'qualifiedName',
+    type: 'string',
+    description: 'Package qualified name'
+  },
+  { name: 'name', type: 'string', description: 'Unqualified name' },
+  { name: 'kind', type: 'string', description: 'Symbol kind' },
+  {
+    name: 'signature',
+    type: 'string',
+    description: 'One line declaration without body'
+  },
+  { name: 'doc', type: 'string', description: 'Doc comment text' },
+  { name: 'file', type: 'string', description: 'Project relative filename' },
+  { name: 'line', type: 'integer', description: 'Line of the declaration' },
+  { name: 'package', type: 'string', description: 'Package name' },
+  { name: 'directory', type: 'string', description: 'Package directory' },
+  { name: 'exported', type: 'boolean', description: 'Whether exported' },
+  {
+    name: 'fingerprint',
+    type: 'string',
+    description: 'Hash of the declaration'
+  }
+];
+
+/**
+ * Symbol kinds of search index documents.
+ */
+const SEARCH_INDEX_KINDS = [
+  'function',
+  'method',
+  'struct',
+  'interface',
+  'type',
+  'field',
+  'const',
+  'var'
+];
+
+// ============================================================================
+// DOCUMENT FIELDS
+// ============================================================================
+
+/**
+ * Collapse whitespace so that a declaration fits on one line.
+ * @param {string} text - Declaration text
+ * @returns {string} Text on a single line
+ */
+const collapse_whitespace = (text) => {
+  return (text || '').replace(/\s+/g, ' ').trim();
+};
+
+/**
+ * Get the one line signature of a type declaration
+ * (`type Shape interface`, `type Count int`, `type ID = string`).
+ * @param {Object} type - Type declaration
+ * @returns {string} Signature
+ */
+const get_type_signature = (type) => {
+  const params = type.type_params ? `[${type.type_params}]` : '';
+  const head = `type ${type.name}${params}`;
+  if (type.kind === 'struct' || type.kind === 'interface') {
+    return `${head} ${type.kind}`;
+  }
+  const assign = type.kind === 'alias' ? ' =' : '';
+  return `${head}${assign} ${collapse_whitespace(type.underlying)}`;
+};
+
+/**
+ * Get the one line signature of a constant or variable.  Multi-line
+ * values, such as function literals, are elided.
+ * @param {string} keyword - const or var
+ * @param {Object} decl - Declaration (from the Go parser)
+ * @param {number} index - Index of the name in the declaration
+ * @returns {string} Signature
+ */
+const get_value_signature = (keyword, decl, index) => {
+  let signature = `${keyword} ${decl.names[index]}`;
+  if (decl.type) signature += ` ${decl.type}`;
+  const value = decl.implicit ? null : (decl.values || [])[index];
+  if (value) {
+    signature += value.includes('\n') ? ' = ...' : ` = ${value}`;
+  }
+  return signature;
+};
+
+/**
+ * Compute the fingerprint of a declaration: a hash of its kind and
+ * whitespace normalized text.
+ * @param {string} kind - Symbol kind
+ * @param {string} text - Declaration text, without doc comment
+ * @returns {string} Hexadecimal fingerprint
+ */
+const get_symbol_fingerprint = (kind, text) => {
+  return createHash('sha1')
+    .update(`${kind}\0${collapse_whitespace(text)}`)
+    .digest('hex')
+    .slice(0, 16);
+};
+
+// ============================================================================
+// DOCUMENTS
+// ============================================================================
+
+/**
+ * List the search index documents of a set of packages.
+ * The hook receives each document and the declaration it was built from
+ * (the enclosing type for fields and interface methods), with the
+ * package, and returns extra fields to add to the document.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {Map<string, string[]>} [options.lines] - Source lines by filename, for fingerprints
+ * @param {string[]} [options.kinds] - Only include these kinds (default: all)
+ * @param {boolean} [options.exported_only=false] - Only include exported symbols
+ * @param {Function} [options.fields] - Hook returning custom fields: (document, { declaration, package }) => Object
+ * @returns {Object[]} Documents by file and line
+ * @throws {Error} If a kind is unknown or the hook overrides a schema field
+ */
+const list_search_documents = (packages, options = {}) => {
+  const kinds = new Set(options.kinds || SEARCH_INDEX_KINDS);
+  for (const kind of kinds) {
+    if (!SEARCH_INDEX_KINDS.includes(kind)) {
+      throw new Error(
+        `Unknown kind '${kind}' (expected one of: ${SEARCH_INDEX_KINDS.join(', ')})`
+      );
+    }
+  }
+  const reserved = new Set(SEARCH_INDEX_FIELDS.map((f) => f.name));
+  const documents = [];
+
+  for (const pkg of packages) {
+    const get_text = (declaration) => {
+      const lines = options.lines && options.lines.get(declaration.filename);
+      if (!lines) return null;
+      const end = declaration.end_line || declaration.line;
+      return lines.slice(declaration.line - 1, end).join('\n');
+    };
+
+    const add = (symbol, declaration) => {
+      if (!kinds.has(symbol.kind)) return;
+      const exported = symbol.exported && is_exported(symbol.name);
+      if (options.exported_only && !exported) return;
+
+      const qualified_name = [pkg.name, symbol.container, symbol.name]
+        .filter(Boolean)
+        .join('.');
+      const document = {
+        id: `${pkg.directory}:${qualified_name}`,
+        qualifiedName: qualified_name,
+        name: symbol.name,
+        kind: symbol.kind,
+        signature: symbol.signature,
+        doc: get_comment_text(symbol.doc),
+        file: declaration.filename,
+        line: symbol.line,
+        package: pkg.name,
+        directory: pkg.directory,
+        exported,
+        fingerprint: get_symbol_fingerprint(
+          symbol.kind,
+          symbol.text || get_text(declaration) || symbol.signature
+        )
+      };
+
+      if (options.fields) {
+        const extra = options.fields(document, { declaration, package: pkg });
+        for (const [name, value] of Object.entries(extra || {})) {
+          if (reserved.has(name)) {
+            throw new Error(`Custom field '${name}' overrides a schema field`);
+          }
+          document[name] = value;
+        }
+      }
+      documents.push(document);
+    };
+
+    for (const fn of pkg.functions) {
+      if (!fn.name || fn.name === '_' || fn.name === 'init') continue;
+      const receiver = fn.receiver ? get_base_type(fn.receiver.type) : null;
+      add(
+        {
+          name: fn.name,
+          container: receiver,
+          kind: receiver ? 'method' : 'function',
+          signature: collapse_whitespace(fn.signature),
+          doc: fn.doc,
+          line: fn.line,
+          exported: !receiver || is_exported(receiver)
+        },
+        fn
+      );
+    }
+
+    for (const type of pkg.types) {
+      const kind = ['struct', 'interface'].includes(type.kind)
+        ? type.kind
+        : 'type';
+      add(
+        {
+          name: type.name,
+          kind,
+          signature: get_type_signature(type),
+          doc: type.doc,
+          line: type.line,
+          exported: true
+        },
+        type
+      );
+
+      for (const field of type.fields) {
+        if (field.embedded) continue;
+        for (const name of field.names) {
+          const signature = `${name} ${field.type}`;
+          add(
+            {
+              name,
+              container: type.name,
+              kind: 'field',
+              signature,
+              doc: field.doc || field.comment,
+              line: field.line,
+              exported: type.exported,
+              text: `${signature} ${field.tag || ''}`
+            },
+            type
+          );
+        }
+      }
+
+      for (const method of type.methods) {
+        add(
+          {
+            name: method.name,
+            container: type.name,
+            kind: 'method',
+            signature: format_method_signature(method),
+            doc: method.doc,
+            line: method.line,
+            exported: type.exported,
+            text: format_method_signature(method)
+          },
+          type
+        );
+      }
+    }
+
+    for (const [decls, keyword] of [
+      [pkg.consts, 'const'],
+      [pkg.vars, 'var']
+    ]) {
+      for (const decl of decls) {
+        decl.names.forEach(function add_value(name, index) {
+          if (name === '_') return;
+          const signature = get_value_signature(keyword, decl, index);
+          add(
+            {
+              name,
+              kind: keyword,
+              signature,
+              doc: decl.doc,
+              line: decl.line,
+              exported: true,
+              // Names declared together share the text of their line
+              text: decl.names.length > 1 ? signature : null
+            },
+            decl
+          );
+        });
+      }
+    }
+  }
+
+  return documents.sort(function sort_by_location(a, b) {
+    if (a.file !== b.file) return a.file < b.file ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Format documents as JSON lines: one document per line, each line
+ * terminated by a newline.
+ * @param {Object[]} documents - Documents
+ * @returns {string} JSON lines text
+ */
+const format_search_index = (documents) => {
+  return documents.map((d) => `${JSON.stringify(d)}\n`).join('');
+};
+
+/**
+ * Build the search index documents of a set of Go sources.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see list_search_documents)
+ * @returns {Object[]} Documents
+ */
+const build_search_index = (sources, options = {}) => {
+  const lines = new Map();
+  const files = sources.map(function parse_source(file) {
+    lines.set(file.filename, file.source.split('\n'));
+    return parse_go_file(file.source, file.filename);
+  });
+  return list_search_documents(group_go_packages(files), {
+    ...options,
+    lines
+  });
+};
+
+/**
+ * Export the search index documents of a project.
+ * @param {number} project_id - The project ID
+ * @param {Object} [options] - Options (see list_search_documents)
+ * @returns {Promise<Object>} Schema, documents and their count
+ */
+const export_search_index = async (project_id, options = {}) => {
+  const documents = build_search_index(
+    await load_go_sources(project_id),
+    options
+  );
+  return { schema: SEARCH_INDEX_FIELDS, documents, count: documents.length };
+};
+
+export {
+  export_search_index,
+  build_search_index,
+  list_search_documents,
+  format_search_index,
+  get_symbol_fingerprint,
+  get_type_signature,
+  SEARCH_INDEX_FIELDS,
+  SEARCH_INDEX_KINDS
+};
@@ -0,0 +1,481 @@
+'use strict';
+
+/**
+ * @fileoverview Go code smell score module.
+ * Rolls the diagnostics of the other Go analyses up into one sortable
+ * score per symbol and per file, answering "where should we refactor
+ * first?".
+ *
+ * Each diagnostic has a code, a kind and a weight (see SMELL_DIAGNOSTICS):
+ * - metric diagnostics (complexity, length, nesting, parameters) measure
+ *   a value against a threshold and score its relative overshoot,
+ *   `max(0, value - threshold) / threshold`, so a function with a
+ *   complexity of 30 against a threshold of 15 scores 1
+ * - count diagnostics (unchecked and discarded errors, panics, misplaced
+ *   contexts, missing docs) score the number of occurrences
+ *
+ * The score of a symbol is `sum(weight * amount)` over its diagnostics and
+ * the score of a file is the sum of the scores of its symbols.  Scores are
+ * rounded to two decimals; a weight of 0 disables a diagnostic.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/smells
+ */
+
+import {
+  mask_source,
+  find_matching,
+  build_line_index,
+  line_at,
+  load_go_packages
+} from './golang.mjs';
+import { analyze_go_nesting } from './readability.mjs';
+import { find_panic_sites } from './panics.mjs';
+import { check_context_params } from './conventions.mjs';
+import { find_undocumented_symbols } from './godoc.mjs';
+
+/**
+ * Diagnostics rolled up into smell scores, with their default weight and,
+ * for metric diagnostics, the threshold above which they score (the same
+ * as the code smell detection thresholds).
+ */
+const SMELL_DIAGNOSTICS = {
+  complexity: {
+    kind: 'metric',
+    weight: 1,
+    threshold: 15,
+    description: 'Cyclomatic complexity above the threshold'
+  },
+  length: {
+    kind: 'metric',
+    weight: 1,
+    threshold: 50,
+    description: 'Function longer than the threshold in lines'
+  },
+  nesting: {
+    kind: 'metric',
+    weight: 1,
+    threshold: 4,
+    description: 'Block nesting deeper than the threshold'
+  },
+  parameters: {
+    kind: 'metric',
+    weight: 0.5,
+    threshold: 5,
+    description: 'More parameters than the threshold'
+  },
+  unchecked_error: {
+    kind: 'count',
+    weight: 2,
+    description: 'Error returning package call made as a statement'
+  },
+  discarded_error: {
+    kind: 'count',
+    weight: 1,
+    description: 'Last result of a call assigned to the blank identifier'
+  },
+  panic: {
+    kind: 'count',
+    weight: 1,
+    description: 'Call of panic'
+  },
+  misplaced_context: {
+    kind: 'count',
+    weight: 1,
+    description: 'context.Context parameter that is not first'
+  },
+  undocumented: {
+    kind: 'count',
+    weight: 0.5,
+    description: 'Exported symbol without a doc comment'
+  }
+};
+
+/**
+ * Keywords that can start a line like a call (`return (a)`, `if (a) {`).
+ */
+const STATEMENT_KEYWORDS = new Set([
+  'return',
+  'if',
+  'for',
+  'switch',
+  'select',
+  'case',
+  'go',
+  'defer',
+  'func',
+  'else'
+]);
+
+// ============================================================================
+// DIAGNOSTICS
+// ============================================================================
+
+/**
+ * Compute the cyclomatic complexity of a Go function body: one plus the
+ * number of if, for and case clauses and of && and || operators.
+ * @param {string} body - Function body
+ * @returns {number} Cyclomatic complexity
+ */
+const calculate_go_complexity = (body) => {
+  const masked = mask_source(body || '');
+  const decisions = masked.match(/\b(?:if|for|case)\b|&&|\|\|/g) || [];
+  return decisions.length + 1;
+};
+
+/**
+ * Find the names of the package functions and methods whose last result
+ * is an error.  A method name only counts when every method of that name
+ * returns an error, since the receiver of a call is not resolved.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @returns {Object} { functions, methods } sets of names
+ */
+const get_error_returning = (pkg) => {
+  const functions = new Set();
+  const methods = new Map();
+
+  for (const fn of pkg.functions) {
+    const last = fn.results[fn.results.length - 1];
+    const returns_error = Boolean(last && last.type === 'error');
+    if (!fn.receiver) {
+      if (returns_error) functions.add(fn.name);
+    } else {
+      methods.set(fn.name, (methods.get(fn.name) ?? true) && returns_error);
+    }
+  }
+
+  return {
+    functions,
+    methods: new Set([...methods].filter(([, all]) => all).map(([n]) => n))
+  };
+};
+
+/**
+ * Find the error results that a function body ignores: calls of package
+ * functions returning an error made as statements (unchecked), and calls
+ * whose last result is assigned to the blank identifier (discarded), by
+ * the Go convention that the error is the last result.
+ * @param {Object} fn - Function (from the Go parser)
+ * @param {Object} error_returning - Names (from get_error_returning)
+ * @returns {Object} { unchecked, discarded } sites with line and text
+ */
+const find_ignored_errors = (fn, error_returning) => {
+  const body = fn.body || '';
+  const masked = mask_source(body);
+  const line_index = build_line_index(body);
+  const line_of = (offset) => fn.body_line + line_at(line_index, offset) - 1;
+  const unchecked = [];
+  const discarded = [];
+  let match;
+
+  const call_pattern = /^[ \t]*([A-Za-z_][\w.]*)\s*\(/gm;
+  while ((match = call_pattern.exec(masked)) !== null) {
+    const callee = match[1];
+    if (STATEMENT_KEYWORDS.has(callee)) continue;
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    if (close === -1) continue;
+    const newline = masked.indexOf('\n', close);
+    const end = newline === -1 ? masked.length : newline;
+    if (masked.substring(close + 1, end).trim() !== '') continue;
+
+    const parts = callee.split('.');
+    const name = parts[parts.length - 1];
+    const returns_error =
+      parts.length === 1
+        ? error_returning.functions.has(name)
+        : error_returning.methods.has(name);
+    if (!returns_error) continue;
+    unchecked.push({
+      line: line_of(match.index),
+      text: body.substring(match.index, close + 1).trim()
+    });
+  }
+
+  const blank_pattern =
+    /^[ \t]*(?:[\w.]+\s*,\s*)*_\s*:?=\s*([A-Za-z_]\w*(?:\.\w+)*)\s*\(/gm;
+  while ((match = blank_pattern.exec(masked)) !== null) {
+    const newline = masked.indexOf('\n', match.index);
+    discarded.push({
+      line: line_of(match.index),
+      text: body
+        .substring(match.index, newline === -1 ? undefined : newline)
+        .trim()
+    });
+  }
+
+  return { unchecked, discarded };
+};
+
+/**
+ * Collect the diagnostics of the symbols of a set of packages.  Each
+ * diagnostic is one code for one symbol, with its value: the metric for
+ * metric diagnostics and the number of occurrences for count
+ * diagnostics.  Test files are skipped.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Diagnostics with code, symbol, kind, location, value and lines
+ */
+const collect_smell_diagnostics = (packages) => {
+  const diagnostics = [];
+  const add = (pkg, code, symbol, value, lines = []) => {
+    if (!value) return;
+    diagnostics.push({
+      code,
+      symbol: symbol.name,
+      kind: symbol.kind,
+      package: pkg.name,
+      directory: pkg.directory,
+      filename: symbol.filename,
+      line: symbol.line,
+      value,
+      lines
+    });
+  };
+
+  for (const pkg of packages) {
+    const error_returning = get_error_returning(pkg);
+    const symbols = new Map();
+
+    for (const fn of pkg.functions) {
+      if (!fn.name || !fn.body || fn.filename.endsWith('_test.go')) continue;
+      const symbol = {
+        name: fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name,
+        kind: fn.receiver ? 'method' : 'function',
+        filename: fn.filename,
+        line: fn.line
+      };
+      symbols.set(symbol.name, symbol);
+
+      add(pkg, 'complexity', symbol, calculate_go_complexity(fn.body));
+      add(pkg, 'length', symbol, fn.end_line - fn.line + 1);
+      add(
+        pkg,
+        'nesting',
+        symbol,
+        analyze_go_nesting(fn.body, fn.body_line).max_depth
+      );
+      add(pkg, 'parameters', symbol, fn.params.length);
+
+      const ignored = find_ignored_errors(fn, error_returning);
+      const panics = find_panic_sites(fn.body, fn.body_line);
+      for (const [code, sites] of [
+        ['unchecked_error', ignored.unchecked],
+        ['discarded_error', ignored.discarded],
+        ['panic', panics]
+      ]) {
+        add(pkg, code, symbol, sites.length, sites.map((s) => s.line));
+      }
+    }
+
+    for (const misplaced of check_context_params(pkg).misplaced) {
+      if (misplaced.filename.endsWith('_test.go')) continue;
+      const symbol = symbols.get(misplaced.symbol) || {
+        name: misplaced.symbol,
+        kind: misplaced.kind,
+        filename: misplaced.filename,
+        line: misplaced.line
+      };
+      add(pkg, 'misplaced_context', symbol, 1, [misplaced.line]);
+    }
+
+    const undocumented = find_undocumented_symbols([pkg]).symbols;
+    const by_symbol = new Map();
+    for (const missing of undocumented) {
+      // Undocumented fields count against their struct
+      const name =
+        missing.kind === 'field' ? missing.name.split('.')[0] : missing.name;
+      if (!by_symbol.has(name)) {
+        const type = pkg.types.find((t) => t.name === name);
+        by_symbol.set(name, {
+          symbol: symbols.get(name) || {
+            name,
+            kind: type ? 'type' : missing.kind,
+            filename: type ? type.filename : missing.filename,
+            line: type ? type.line : missing.line
+          },
+          lines: []
+        });
+      }
+      by_symbol.get(name).lines.push(missing.line);
+    }
+    for (const { symbol, lines } of by_symbol.values()) {
+      add(pkg, 'undocumented', symbol, lines.length, lines);
+    }
+  }
+
+  return diagnostics;
+};
+
+// ============================================================================
+// SCORING
+// ============================================================================
+
+/**
+ * Resolve the weights and thresholds of the diagnostics.
+ * @param {Object} [options] - Options
+ * @param {Object} [options.weights] - Weights by diagnostic code
+ * @param {Object} [options.thresholds] - Thresholds by metric diagnostic code
+ * @returns {Object} Diagnostic settings by code
+ * @throws {Error} If a code is unknown or a weight is not a number
+ */
+const get_smell_settings = (options = {}) => {
+  const codes = Object.keys(SMELL_DIAGNOSTICS);
+  for (const code of [
+    ...Object.keys(options.weights || {}),
+    ...Object.keys(options.thresholds || {})
+  ]) {
+    if (!SMELL_DIAGNOSTICS[code]) {
+      throw new Error(
+        `Unknown diagnostic '${code}' (expected one of: ${codes.join(', ')})`
+      );
+    }
+  }
+
+  const settings = {};
+  for (const [code, diagnostic] of Object.entries(SMELL_DIAGNOSTICS)) {
+    const weight = (options.weights || {})[code] ?? diagnostic.weight;
+    if (typeof weight !== 'number' || Number.isNaN(weight)) {
+      throw new Error(`Weight of '${code}' must be a number`);
+    }
+    settings[code] = {
+      ...diagnostic,
+      weight,
+      threshold: (options.thresholds || {})[code] ?? diagnostic.threshold
+    };
+  }
+  return settings;
+};
+
+/**
+ * Round a score to two decimals.
+ * @param {number} score - Score
+ * @returns {number} Rounded score
+ */
+const round_score = (score) => Math.round(score * 100) / 100;
+
+/**
+ * Roll diagnostics up into symbol and file smell scores.  Diagnostics may
+ * come from collect_smell_diagnostics or any other source using the same
+ * codes.  Symbols with a score of 0 are left out.
+ * @param {Object[]} diagnostics - Diagnostics with code, symbol, directory, filename, line and value
+ * @param {Object} [options] - Options (see get_smell_settings)
+ * @returns {Object} Settings, symbols and files by descending score, and scores keyed by `<directory>:<symbol>`
+ */
+const score_smells = (diagnostics, options = {}) => {
+  const settings = get_smell_settings(options);
+  const symbols = new Map();
+
+  for (const diagnostic of diagnostics) {
+    const setting = settings[diagnostic.code];
+    if (!setting || setting.weight === 0) continue;
+    const amount =
+      setting.kind === 'metric'
+        ? Math.max(0, diagnostic.value - setting.threshold) / setting.threshold
+        : diagnostic.value;
+    if (amount === 0) continue;
+
+    const id = `${diagnostic.directory}:${diagnostic.symbol}`;
+    if (!symbols.has(id)) {
+      symbols.set(id, {
+        id,
+        symbol: diagnostic.symbol,
+        kind: diagnostic.kind,
+        package: diagnostic.package,
+        directory: diagnostic.directory,
+        filename: diagnostic.filename,
+        line: diagnostic.line,
+        score: 0,
+        diagnostics: []
+      });
+    }
+    const entry = symbols.get(id);
+    const points = setting.weight * amount;
+    entry.score += points;
+    entry.diagnostics.push({
+      code: diagnostic.code,
+      value: diagnostic.value,
+      threshold: setting.threshold ?? null,
+      points: round_score(points),
+      lines: diagnostic.lines || []
+    });
+  }
+
+  const sort_by_score = (a, b) => b.score - a.score || (a.id < b.id ? -1 : 1);
+  const scored = [...symbols.values()]
+    .map((s) => ({
+      ...s,
+      score: round_score(s.score),
+      diagnostics: s.diagnostics.sort((a, b) => b.points - a.points)
+    }))
+    .filter((s) => s.score > 0)
+    .sort(sort_by_score);
+
+  const files = new Map();
+  for (const symbol of scored) {
+    if (!files.has(symbol.filename)) {
+      files.set(symbol.filename, {
+        id: symbol.filename,
+        filename: symbol.filename,
+        score: 0,
+        symbols: 0
+      });
+    }
+    const file = files.get(symbol.filename);
+    file.score = round_score(file.score + symbol.score);
+    file.symbols++;
+  }
+
+  return {
+    settings,
+    symbols: scored,
+    files: [...files.values()]
+      .sort(sort_by_score)
+      .map(({ id, ...file }) => file),
+    scores: Object.fromEntries(scored.map((s) => [s.id, s.score]))
+  };
+};
+
+/**
+ * Compute the smell scores of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see get_smell_settings)
+ * @param {number} [options.limit] - Only return the worst symbols and files
+ * @returns {Promise<Object>} Symbols and files by descending score, with a summary
+ */
+const analyze_project_smell_scores = async (project_id, options = {}) => {
+  const packages = await load_go_packages(project_id);
+  const result = score_smells(collect_smell_diagnostics(packages), options);
+  const limit = (list) => (options.limit ? list.slice(0, options.limit) : list);
+
+  const by_code = {};
+  for (const symbol of result.symbols) {
+    for (const diagnostic of symbol.diagnostics) {
+      by_code[diagnostic.code] = (by_code[diagnostic.code] || 0) + 1;
+    }
+  }
+
+  return {
+    weights: Object.fromEntries(
+      Object.entries(result.settings).map(([code, s]) => [code, s.weight])
+    ),
+    symbols: limit(result.symbols),
+    files: limit(result.files),
+    summary: {
+      smelly_symbols: result.symbols.length,
+      smelly_files: result.files.length,
+      total_score: round_score(
+        result.symbols.reduce((sum, s) => sum + s.score, 0)
+      ),
+      by_code
+    }
+  };
+};
+
+export {
+  analyze_project_smell_scores,
+  score_smells,
+  collect_smell_diagnostics,
+  find_ignored_errors,
+  calculate_go_complexity,
+  get_smell_settings,
+  SMELL_DIAGNOSTICS
+};
@@ -0,0 +1,203 @@
+'use strict';
+
+/**
+ * @fileoverview Go package stability module.
+ * Computes Robert C. Martin's package metrics from the dependencies
+ * between the packages of a project, for a quantitative view of the
+ * health of an architecture:
+ * - afferent coupling (Ca): the number of project packages depending on
+ *   the package
+ * - efferent coupling (Ce): the number of project packages it depends on
+ * - instability: Ce / (Ca + Ce), from 0 (depended upon, hard to change)
+ *   to 1 (depending on others, free to change)
+ * - abstractness: the share of interfaces among the types it declares,
+ *   from 0 (concrete) to 1 (abstract)
+ * - distance from the main sequence: |A + I - 1|, 0 for packages
+ *   balancing abstractness against stability
+ * Packages far from the main sequence are in the zone of pain (concrete
+ * and stable, such as a widely used package without interfaces, painful
+ * to change) or in the zone of uselessness (abstract and unstable,
+ * interfaces nobody depends on).
+ *
+ * A package depends on another when one of its files imports it; imports
+ * of packages outside the project, such as the standard library, do not
+ * count.  A package without dependencies either way has an instability of
+ * 0, and one without types an abstractness of 0.  Test files are left
+ * out.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/stability
+ */
+
+import {
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import { get_imported_packages } from './graph.mjs';
+
+/**
+ * Distance from the main sequence above which a package is in a zone.
+ */
+const DEFAULT_DISTANCE_THRESHOLD = 0.5;
+
+/**
+ * Zones of a package relative to the main sequence.
+ */
+const STABILITY_ZONES = ['main_sequence', 'pain', 'uselessness'];
+
+// ============================================================================
+// PACKAGE DEPENDENCIES
+// ============================================================================
+
+/**
+ * Map each package to the project packages imported by its non-test
+ * files.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Map<Object, Set<Object>>} Imported packages by package
+ */
+const build_package_dependencies = (packages) => {
+  const dependencies = new Map(packages.map((pkg) => [pkg, new Set()]));
+  for (const pkg of packages) {
+    const filenames = new Set(
+      pkg.imports
+        .map((imp) => imp.filename)
+        .filter((filename) => !filename.endsWith('_test.go'))
+    );
+    for (const filename of filenames) {
+      for (const other of get_imported_packages(pkg, filename, packages).values()) {
+        dependencies.get(pkg).add(other);
+      }
+    }
+  }
+  return dependencies;
+};
+
+/**
+ * Round a metric to two decimals.
+ * @param {number} value - Metric
+ * @returns {number} Rounded metric
+ */
+const round_metric = (value) => Math.round(value * 100) / 100;
+
+// ============================================================================
+// PACKAGE METRICS
+// ============================================================================
+
+/**
+ * Compute the Martin metrics of every package of a set of packages (see
+ * the module doc).  Packages of external tests (`store_test`) are left
+ * out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {number} [options.threshold=0.5] - Distance from the main sequence above which a package is in a zone
+ * @returns {Object[]} Package metrics, the farthest from the main sequence first
+ */
+const compute_package_metrics = (packages, options = {}) => {
+  const threshold =
+    options.threshold === undefined
+      ? DEFAULT_DISTANCE_THRESHOLD
+      : options.threshold;
+  const project = packages.filter((pkg) => !/_test$/.test(pkg.name || ''));
+  const dependencies = build_package_dependencies(project);
+  const dependents = new Map(project.map((pkg) => [pkg, new Set()]));
+  for (const [pkg, imported] of dependencies) {
+    for (const other of imported) dependents.get(other).add(pkg);
+  }
+
+  const metrics = project.map(function compute_metrics(pkg) {
+    const types = pkg.types.filter((t) => !t.filename.endsWith('_test.go'));
+    const interfaces = types.filter((t) => t.kind === 'interface').length;
+    const afferent = dependents.get(pkg).size;
+    const efferent = dependencies.get(pkg).size;
+    const abstractness = types.length > 0 ? interfaces / types.length : 0;
+    const instability =
+      afferent + efferent > 0 ? efferent / (afferent + efferent) : 0;
+    const distance = Math.abs(abstractness + instability - 1);
+
+    let zone = 'main_sequence';
+    if (distance > threshold) {
+      zone = abstractness + instability < 1 ? 'pain' : 'uselessness';
+    }
+    const directories = (set) => [...set].map((p) => p.directory).sort();
+
+    return {
+      package: pkg.name,
+      directory: pkg.directory,
+      types: types.length,
+      interfaces,
+      afferent_coupling: afferent,
+      efferent_coupling: efferent,
+      abstractness: round_metric(abstractness),
+      instability: round_metric(instability),
+      distance: round_metric(distance),
+      zone,
+      dependents: directories(dependents.get(pkg)),
+      dependencies: directories(dependencies.get(pkg))
+    };
+  });
+
+  return metrics.sort(function sort_by_distance(a, b) {
+    return b.distance - a.distance || a.directory.localeCompare(b.directory);
+  });
+};
+
+/**
+ * Summarize the Martin metrics of a set of packages.
+ * @param {Object[]} metrics - Package metrics (from compute_package_metrics)
+ * @returns {Object} Numbers of packages, by zone, mean distance, abstractness and instability
+ */
+const summarize_package_metrics = (metrics) => {
+  const by_zone = Object.fromEntries(STABILITY_ZONES.map((z) => [z, 0]));
+  for (const entry of metrics) by_zone[entry.zone]++;
+  const mean = (key) =>
+    metrics.length > 0
+      ? round_metric(
+          metrics.reduce((sum, entry) => sum + entry[key], 0) / metrics.length
+        )
+      : 0;
+  return {
+    packages: metrics.length,
+    by_zone,
+    mean_abstractness: mean('abstractness'),
+    mean_instability: mean('instability'),
+    mean_distance: mean('distance')
+  };
+};
+
+/**
+ * Compute the Martin metrics of the packages of a set of Go sources, such
+ * as the files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options (see compute_package_metrics)
+ * @returns {Object} Package metrics with a summary
+ */
+const find_source_package_metrics = (sources, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const packages = compute_package_metrics(group_go_packages(files), options);
+  return { packages, summary: summarize_package_metrics(packages) };
+};
+
+/**
+ * Compute the Martin metrics of the packages of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see compute_package_metrics)
+ * @returns {Promise<Object>} Package metrics with a summary
+ */
+const analyze_project_package_metrics = async (project_id, options = {}) => {
+  const packages = compute_package_metrics(
+    await load_go_packages(project_id),
+    options
+  );
+  return { packages, summary: summarize_package_metrics(packages) };
+};
+
+export {
+  analyze_project_package_metrics,
+  find_source_package_metrics,
+  compute_package_metrics,
+  summarize_package_metrics,
+  DEFAULT_DISTANCE_THRESHOLD,
+  STABILITY_ZONES
+};
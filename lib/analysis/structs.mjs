@@ -0,0 +1,2695 @@
+'use strict';
+
+/**
+ * @fileoverview Go struct analysis module.
+ * Computes struct memory layouts (size, alignment and padding on 64-bit
+ * platforms) and analyzes how structs are declared and used, including
+ * whether their fields are initialized by constructors or by callers.
+ * Array lengths are evaluated from constant expressions (`[N]byte`,
+ * `[len(table)]int` over an implicit-length `[...]T{...}` variable).
+ * Also reports the methods and fields of structs that shadow a method
+ * promoted from an embedded type, and the types embedding a given type.
+ * Struct tags are extracted into schemas and validated against a tag
+ * policy, such as a json tag with a snake_case name on every field.
+ * Types are ranked by the total complexity of their methods, to find the
+ * "god types" concentrating the logic of a package.
+ *
+ * Types that must not be copied (containing a sync.Mutex, a WaitGroup or
+ * another lock, directly or through fields and arrays) are found like the
+ * copylocks check of `go vet` does, with the functions passing them by
+ * value.  Project types are locks when their pointer has Lock and Unlock
+ * methods their value lacks, which covers the `noCopy` marker idiom.
+ *
+ * Field types are counted across the tree into a histogram, with the
+ * field names declared with different types (an `ID` that is an int here
+ * and an int64 there), to standardize the types of a schema.
+ *
+ * Functions taking an options struct as their sole or last parameter
+ * (`NewServer(addr string, cfg Config)`) are linked to the struct, so
+ * that docs can present the available options together.
+ *
+ * Recursive structs reference themselves through their fields, directly
+ * (`Next *Node`, `Children []*Node`) or through a group of mutually
+ * recursive types (a `Dir` holding `File`s holding their parent `Dir`):
+ * the linked lists, trees and graphs of a code base.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/structs
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  find_matching,
+  split_top_level,
+  strip_comments,
+  get_comment_text,
+  is_exported,
+  classify_type,
+  get_base_type,
+  parse_struct_type_fields,
+  summarize_go_body,
+  build_constant_resolver,
+  split_composite_literal,
+  get_array_length,
+  parse_go_file,
+  group_go_packages,
+  load_go_packages
+} from './golang.mjs';
+import {
+  get_imported_packages,
+  get_node_id,
+  find_embed_edges,
+  get_method_key,
+  get_interface_methods,
+  get_type_methods,
+  get_interface_method_set,
+  get_type_method_set
+} from './graph.mjs';
+import { get_import_name } from './imports.mjs';
+import { format_method_signature } from './interfaces.mjs';
+import { calculate_go_complexity } from './smells.mjs';
+import { resolve_project_type } from './conventions.mjs';
+import { NAMING_PATTERNS } from './naming.mjs';
+
+/**
+ * Default size in bytes above which passing a struct by value is flagged.
+ */
+const DEFAULT_VALUE_PARAM_THRESHOLD = 80;
+
+/**
+ * Sizes and alignments of predeclared types on 64-bit platforms.
+ */
+const BASIC_TYPE_LAYOUTS = {
+  bool: { size: 1, align: 1 },
+  int8: { size: 1, align: 1 },
+  uint8: { size: 1, align: 1 },
+  byte: { size: 1, align: 1 },
+  int16: { size: 2, align: 2 },
+  uint16: { size: 2, align: 2 },
+  int32: { size: 4, align: 4 },
+  uint32: { size: 4, align: 4 },
+  rune: { size: 4, align: 4 },
+  float32: { size: 4, align: 4 },
+  int: { size: 8, align: 8 },
+  uint: { size: 8, align: 8 },
+  int64: { size: 8, align: 8 },
+  uint64: { size: 8, align: 8 },
+  uintptr: { size: 8, align: 8 },
+  float64: { size: 8, align: 8 },
+  complex64: { size: 8, align: 4 },
+  complex128: { size: 16, align: 8 },
+  string: { size: 16, align: 8 },
+  error: { size: 16, align: 8 },
+  any: { size: 16, align: 8 }
+};
+
+/**
+ * Layouts of commonly embedded standard library types.
+ */
+const KNOWN_TYPE_LAYOUTS = {
+  'unsafe.Pointer': { size: 8, align: 8 },
+  'time.Time': { size: 24, align: 8 },
+  'time.Duration': { size: 8, align: 8 },
+  'sync.Mutex': { size: 8, align: 4 },
+  'sync.RWMutex': { size: 24, align: 4 },
+  'sync.WaitGroup': { size: 16, align: 8 },
+  'sync.Once': { size: 12, align: 4 },
+  'atomic.Int32': { size: 4, align: 4 },
+  'atomic.Uint32': { size: 4, align: 4 },
+  'atomic.Int64': { size: 8, align: 8 },
+  'atomic.Uint64': { size: 8, align: 8 },
+  'atomic.Bool': { size: 4, align: 4 },
+  'atomic.Value': { size: 16, align: 8 },
+  'strings.Builder': { size: 32, align: 8 },
+  'bytes.Buffer': { size: 40, align: 8 },
+  'context.Context': { size: 16, align: 8 }
+};
+
+/**
+ * Word sized layout used for pointers, maps, channels and functions.
+ */
+const WORD_LAYOUT = { size: 8, align: 8 };
+
+/**
+ * How a field is typically initialized.
+ * - constructor: mostly by the type's constructor functions
+ * - caller: mostly by callers, in struct literals or by assignment
+ * - mixed: as often by constructors as by callers
+ * - none: never set outside the type's own methods
+ */
+const TYPICAL_INIT_KINDS = ['constructor', 'caller', 'mixed', 'none'];
+
+/**
+ * Heuristic kinds of structs, from their methods and exported fields.
+ * - data: no methods, or only accessors (getters, setters and String)
+ * - service: behavior methods, with no more exported fields than
+ *   behavior methods; the state is mostly behind the methods
+ * - mixed: behavior methods, but more exported fields than behavior
+ *   methods; data that also carries some logic
+ */
+const STRUCT_KINDS = ['data', 'service', 'mixed'];
+
+/**
+ * Methods that format a value rather than give it behavior.
+ */
+const FORMATTING_METHODS = new Set(['String', 'GoString', 'Error']);
+
+/**
+ * Kinds of method shadowing.
+ * - override: the shadowing method has the signature of the promoted one
+ * - collision: the shadowed name is a method with another signature, or a field
+ */
+const SHADOW_KINDS = ['override', 'collision'];
+
+/**
+ * Rules broken by struct tags: a required key is missing, a tag name does
+ * not follow the naming rule of its key, or the tag is not in the
+ * conventional `key:"value"` format.
+ */
+const TAG_RULES = ['required', 'naming', 'syntax'];
+
+/**
+ * Default tag policy: a snake_case json name on every field.
+ */
+const DEFAULT_TAG_POLICY = {
+  required: ['json'],
+  naming: { json: 'snake_case' }
+};
+
+/**
+ * Types of the standard library that must not be copied after first use,
+ * by import path and name.
+ */
+const NO_COPY_TYPES = new Set([
+  'sync.Mutex',
+  'sync.RWMutex',
+  'sync.WaitGroup',
+  'sync.Cond',
+  'sync.Once',
+  'sync.Map',
+  'sync.Pool',
+  'sync/atomic.Value',
+  'sync/atomic.Bool',
+  'sync/atomic.Int32',
+  'sync/atomic.Int64',
+  'sync/atomic.Uint32',
+  'sync/atomic.Uint64',
+  'sync/atomic.Uintptr',
+  'sync/atomic.Pointer'
+]);
+
+/**
+ * How field types are normalized before they are counted:
+ * - none: as written, `UserID` and `int64` are different types
+ * - alias: aliases (`type UserID = int64`) are replaced by their type
+ * - underlying: defined types (`type UserID int64`) are replaced by their
+ *   underlying type too, except structs and interfaces
+ */
+const FIELD_TYPE_RESOLUTIONS = ['none', 'alias', 'underlying'];
+
+/**
+ * Default number of types listed by complexity.
+ */
+const DEFAULT_TOP_TYPES = 10;
+
+/**
+ * Names of options structs (`ServerOptions`, `Config`, `DialOpts`).
+ */
+const OPTIONS_STRUCT_PATTERN = /(Options|Opts|Config|Settings|Params)$/;
+
+/**
+ * Default number of exported fields from which a plain data struct taken
+ * as the last parameter is an options struct, whatever its name.
+ */
+const DEFAULT_MIN_OPTION_FIELDS = 4;
+
+/**
+ * Kinds of struct recursion:
+ * - direct: a field references the struct itself
+ * - mutual: the struct references itself only through other structs
+ */
+const RECURSION_KINDS = ['direct', 'mutual'];
+
+/**
+ * Round an offset up to a multiple of an alignment.
+ * @param {number} offset - The offset
+ * @param {number} align - The alignment
+ * @returns {number} The aligned offset
+ */
+const align_to = (offset, align) => {
+  if (align <= 1) return offset;
+  return Math.ceil(offset / align) * align;
+};
+
+/**
+ * Build the type context for a package, used when computing layouts.
+ * Constants may use the length of the array variables of the package
+ * (`const n = len(table)`), implicit lengths (`[...]T{...}`) included.
+ * @param {Object} pkg - Package (from load_go_packages)
+ * @returns {Object} Context with a type map, constant resolver and array length resolver
+ */
+const build_layout_context = (pkg) => {
+  const types = new Map();
+  for (const type of pkg.types || []) {
+    types.set(type.name, type);
+  }
+
+  const arrays = new Map();
+  for (const decl of pkg.vars || []) {
+    decl.names.forEach((name, i) => {
+      const value = decl.values[i];
+      const literal = value ? split_composite_literal(value) : null;
+      const type = decl.type || (literal ? literal.type : null);
+      if (classify_type(type || '') === 'array') {
+        arrays.set(name, { type, literal: value });
+      }
+    });
+  }
+
+  const lengths = new Map();
+  const length = (name) => {
+    if (lengths.has(name)) return lengths.get(name);
+    const array = arrays.get(name);
+    if (!array) return null;
+    lengths.set(name, null);
+    const result = get_array_length(array.type, {
+      resolve,
+      length,
+      literal: array.literal
+    });
+    lengths.set(name, result.length);
+    return result.length;
+  };
+  const resolve = build_constant_resolver(pkg.consts || [], { length });
+
+  return {
+    types,
+    resolve,
+    length,
+    resolving: new Set()
+  };
+};
+
+/**
+ * Compute the layout of a list of struct fields.
+ * @param {Object[]} fields - Struct fields (from the Go parser)
+ * @param {Object} context - Layout context (from build_layout_context)
+ * @returns {Object} Layout with size, align, padding, fields and an exact flag
+ */
+const compute_fields_layout = (fields, context) => {
+  let offset = 0;
+  let max_align = 1;
+  let exact = true;
+  let data_size = 0;
+  const placed = [];
+
+  for (const field of fields) {
+    const layout = compute_type_layout(field.type, context);
+    if (!layout.exact) exact = false;
+
+    for (const name of field.names) {
+      offset = align_to(offset, layout.align);
+      const entry = {
+        name,
+        type: field.type,
+        offset,
+        size: layout.size,
+        align: layout.align,
+        line: field.line
+      };
+      if (layout.length !== undefined) entry.length = layout.length;
+      if (layout.symbolic_size) entry.symbolic_size = layout.symbolic_size;
+      placed.push(entry);
+      offset += layout.size;
+      data_size += layout.size;
+      max_align = Math.max(max_align, layout.align);
+    }
+  }
+
+  // A trailing zero-size field is padded so its address stays in bounds
+  const last = placed[placed.length - 1];
+  if (last && last.size === 0 && offset > 0) {
+    offset += 1;
+  }
+
+  const size = align_to(offset, max_align);
+  return {
+    size,
+    align: max_align,
+    padding: size - data_size,
+    exact,
+    fields: placed
+  };
+};
+
+/**
+ * Bind the type parameters of a generic type declaration to the type
+ * arguments of an instantiation (`Pair[string, int]`).
+ * @param {Object} declared - Type declaration (from the Go parser)
+ * @param {string} type_text - Instantiated type expression
+ * @returns {Map<string, string>|null} Type arguments by parameter name, or null when the type is not fully instantiated
+ */
+const bind_type_params = (declared, type_text) => {
+  const open = type_text.indexOf('[');
+  if (!declared.type_params || open === -1) return null;
+  const names = split_top_level(declared.type_params).map(
+    (param) => param.trim().split(/\s+/)[0]
+  );
+  const args = split_top_level(
+    type_text.substring(open + 1, type_text.length - 1)
+  ).map((arg) => arg.trim());
+  if (names.length !== args.length) return null;
+  return new Map(names.map((param, i) => [param, args[i]]));
+};
+
+/**
+ * Replace the type parameters in a type expression with their arguments.
+ * @param {string} type_text - Type expression
+ * @param {Map<string, string>|null} bindings - Type arguments by parameter name (from bind_type_params)
+ * @returns {string} The type expression with the arguments substituted
+ */
+const substitute_type_params = (type_text, bindings) => {
+  if (!bindings) return type_text;
+  return type_text.replace(/(?<![\w.])[A-Za-z_]\w*(?!\w)/g, (word) =>
+    bindings.has(word) ? bindings.get(word) : word
+  );
+};
+
+/**
+ * Compute the size and alignment of a Go type on a 64-bit platform.
+ * Unknown types (type parameters or types from other packages that are not
+ * in KNOWN_TYPE_LAYOUTS) are treated as a single word and mark the layout
+ * as inexact.  Arrays carry their length; an array whose length cannot be
+ * evaluated counts as one element, with its size kept symbolic
+ * (`N*8`).
+ * @param {string} type_text - Type expression
+ * @param {Object} context - Layout context (from build_layout_context)
+ * @returns {Object} Layout with size, align and an exact flag (struct layouts include fields, array layouts their length)
+ */
+const compute_type_layout = (type_text, context) => {
+  const text = (type_text || '').trim();
+  const kind = classify_type(text);
+
+  if (kind === 'pointer' || kind === 'map' || kind === 'chan') {
+    return { ...WORD_LAYOUT, exact: true };
+  }
+  if (kind === 'func') return { ...WORD_LAYOUT, exact: true };
+  if (kind === 'slice') return { size: 24, align: 8, exact: true };
+  if (kind === 'interface') return { size: 16, align: 8, exact: true };
+  if (kind === 'struct') {
+    return compute_fields_layout(parse_struct_type_fields(text), context);
+  }
+
+  if (kind === 'array') {
+    const { length, expr } = get_array_length(text, {
+      resolve: context.resolve,
+      length: context.length
+    });
+    const close = find_matching(mask_source(text), 0);
+    const element = compute_type_layout(text.substring(close + 1), context);
+    if (length === null) {
+      const count = /^[\w.]+$/.test(expr) ? expr : `(${expr})`;
+      return {
+        size: element.size,
+        align: element.align,
+        exact: false,
+        length,
+        symbolic_size: `${count}*${element.size}`
+      };
+    }
+    return {
+      size: length * element.size,
+      align: element.align,
+      exact: element.exact,
+      length
+    };
+  }
+
+  if (BASIC_TYPE_LAYOUTS[text]) {
+    return { ...BASIC_TYPE_LAYOUTS[text], exact: true };
+  }
+  if (KNOWN_TYPE_LAYOUTS[text]) {
+    return { ...KNOWN_TYPE_LAYOUTS[text], exact: true };
+  }
+
+  // Named type declared in this package, instantiated with its type arguments
+  const name = text.replace(/\[[\s\S]*\]$/, '');
+  const declared = context.types.get(name);
+  if (declared && !context.resolving.has(text)) {
+    const bindings = bind_type_params(declared, text);
+    context.resolving.add(text);
+    let layout;
+    if (declared.kind === 'struct') {
+      const fields = declared.fields.map((field) => ({
+        ...field,
+        type: substitute_type_params(field.type, bindings)
+      }));
+      layout = compute_fields_layout(fields, context);
+    } else if (declared.kind === 'interface') {
+      layout = { size: 16, align: 8, exact: true };
+    } else {
+      layout = compute_type_layout(
+        substitute_type_params(declared.underlying, bindings),
+        context
+      );
+    }
+    context.resolving.delete(text);
+    // Without type arguments the layout depends on the instantiation
+    if (declared.type_params && !bindings) layout = { ...layout, exact: false };
+    return layout;
+  }
+
+  return { ...WORD_LAYOUT, exact: false };
+};
+
+/**
+ * Compute layouts for all struct types declared in a package.
+ * @param {Object} pkg - Package (from load_go_packages)
+ * @param {Object} [context] - Layout context (built from the package if omitted)
+ * @returns {Object[]} Struct layouts
+ */
+const compute_struct_layouts = (pkg, context = build_layout_context(pkg)) => {
+  return (pkg.types || [])
+    .filter((t) => t.kind === 'struct')
+    .map(function layout_struct(type) {
+      const layout = compute_type_layout(type.name, context);
+      return {
+        name: type.name,
+        filename: type.filename,
+        line: type.line,
+        size: layout.size,
+        align: layout.align,
+        padding: layout.padding || 0,
+        exact: layout.exact,
+        fields: layout.fields || []
+      };
+    });
+};
+
+/**
+ * Resolve the struct a by-value parameter type refers to, if any.
+ * Pointers, interfaces and non-struct types return null.
+ * @param {string} type_text - Parameter type
+ * @param {Object} context - Layout context
+ * @returns {string|null} Struct name with its type arguments (or 'struct{...}' for literals), or null
+ */
+const get_value_struct_type = (type_text, context) => {
+  const text = (type_text || '').trim();
+  const kind = classify_type(text);
+  if (kind === 'struct') return 'struct{...}';
+  if (kind !== 'named') return null;
+
+  const name = text.replace(/\[[\s\S]*\]$/, '');
+  const declared = context.types.get(name);
+  if (declared && declared.kind === 'struct') return text;
+
+  // Struct aliases and definitions based on other structs
+  if (declared && (declared.kind === 'alias' || declared.kind === 'named')) {
+    return get_value_struct_type(declared.underlying, context) ? name : null;
+  }
+
+  return null;
+};
+
+/**
+ * Find functions that pass large structs by value.
+ * Both value receivers and by-value parameters are checked.  Variadic
+ * parameters are passed as slices and are not flagged.
+ * @param {Object} pkg - Package (from load_go_packages)
+ * @param {Object} [options] - Options
+ * @param {number} [options.threshold=80] - Size in bytes above which a struct is flagged
+ * @param {Object} [options.context] - Layout context (built from the package if omitted)
+ * @returns {Object[]} Findings
+ */
+const find_large_value_params = (pkg, options = {}) => {
+  const threshold = options.threshold || DEFAULT_VALUE_PARAM_THRESHOLD;
+  const context = options.context || build_layout_context(pkg);
+  const findings = [];
+
+  const check = (fn, kind, name, type) => {
+    const struct_name = get_value_struct_type(type, context);
+    if (!struct_name) return;
+
+    const layout = compute_type_layout(type, context);
+    if (layout.size <= threshold) return;
+
+    findings.push({
+      function: fn.name,
+      receiver: fn.receiver ? fn.receiver.type : null,
+      filename: fn.filename,
+      line: fn.line,
+      kind,
+      parameter: name,
+      type,
+      size: layout.size,
+      exact: layout.exact,
+      threshold,
+      message: `${kind === 'receiver' ? 'Receiver' : 'Parameter'} '${name || '_'}' copies ${layout.size} bytes of ${struct_name} on every call; consider passing a pointer`
+    });
+  };
+
+  for (const fn of pkg.functions || []) {
+    if (fn.receiver && !fn.receiver.pointer) {
+      check(fn, 'receiver', fn.receiver.name, fn.receiver.type);
+    }
+    for (const param of fn.params) {
+      if (param.variadic) continue;
+      check(fn, 'parameter', param.name, param.type);
+    }
+  }
+
+  return findings.sort(function sort_by_size_desc(a, b) {
+    return b.size - a.size;
+  });
+};
+
+/**
+ * Analyze struct sizes and large by-value parameters in a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {number} [options.threshold=80] - Size in bytes above which a struct is flagged
+ * @returns {Promise<Object>} Struct size analysis results
+ */
+const analyze_project_struct_sizes = async (project_id, options = {}) => {
+  const threshold = options.threshold || DEFAULT_VALUE_PARAM_THRESHOLD;
+  const packages = await load_go_packages(project_id);
+
+  const structs = [];
+  const large_value_params = [];
+
+  for (const pkg of packages) {
+    const context = build_layout_context(pkg);
+    for (const layout of compute_struct_layouts(pkg, context)) {
+      structs.push({ package: pkg.name, directory: pkg.directory, ...layout });
+    }
+    const findings = find_large_value_params(pkg, { threshold, context });
+    for (const finding of findings) {
+      large_value_params.push({ package: pkg.name, ...finding });
+    }
+  }
+
+  structs.sort(function sort_by_size_desc(a, b) {
+    return b.size - a.size;
+  });
+
+  return {
+    threshold,
+    structs,
+    large_value_params,
+    summary: {
+      total_structs: structs.length,
+      large_structs: structs.filter((s) => s.size > threshold).length,
+      largest_struct: structs[0]
+        ? { name: structs[0].name, size: structs[0].size }
+        : null,
+      total_padding: structs.reduce((sum, s) => sum + s.padding, 0),
+      large_value_param_count: large_value_params.length,
+      affected_functions: new Set(
+        large_value_params.map((f) => `${f.filename}:${f.line}`)
+      ).size
+    }
+  };
+};
+
+/**
+ * Resolve a possibly qualified name (`Name` or `pkg.Name`) to a node ID.
+ * @param {string} text - Name, optionally qualified with a package name
+ * @param {Object} pkg - Package the name is used in
+ * @param {Map<string, Object>} imported - Imported project packages by name
+ * @returns {string|null} Node ID, or null for packages outside the project
+ */
+const resolve_qualified_name = (text, pkg, imported) => {
+  const dot = text.indexOf('.');
+  if (dot === -1) return get_node_id(pkg.directory, text);
+  const other = imported.get(text.substring(0, dot));
+  return other ? get_node_id(other.directory, text.substring(dot + 1)) : null;
+};
+
+/**
+ * Find the constructors of a project's struct types: package functions
+ * whose first result is the struct or a pointer to it.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Map<string, Object>} structs - Struct types by node ID
+ * @returns {Map<string, string>} Struct node IDs by constructor node ID
+ */
+const find_constructors = (packages, structs) => {
+  const constructors = new Map();
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.receiver || fn.results.length === 0) continue;
+      const base = get_base_type(fn.results[0].type);
+      if (base.includes('.') || /^\[/.test(fn.results[0].type)) continue;
+      const type_id = get_node_id(pkg.directory, base);
+      if (structs.has(type_id)) {
+        constructors.set(get_node_id(pkg.directory, fn.name), type_id);
+      }
+    }
+  }
+
+  return constructors;
+};
+
+/**
+ * Find the variables of a function whose struct type is known: the
+ * receiver, parameters, typed `var` declarations and variables assigned
+ * a struct literal or the result of a constructor.
+ * @param {Object} fn - Function (from group_go_packages)
+ * @param {Function} resolve - Resolves a name to a node ID
+ * @param {Map<string, Object>} structs - Struct types by node ID
+ * @param {Map<string, string>} constructors - Struct node IDs by constructor node ID
+ * @returns {Map<string, string>} Struct node IDs by variable name
+ */
+const get_variable_types = (fn, resolve, structs, constructors) => {
+  const variables = new Map();
+  const bind_type = (name, type_text) => {
+    const id = resolve(get_base_type(type_text));
+    if (name && name !== '_' && id && structs.has(id)) variables.set(name, id);
+  };
+
+  if (fn.receiver) bind_type(fn.receiver.name, fn.receiver.type);
+  for (const param of fn.params) {
+    if (!param.variadic) bind_type(param.name, param.type);
+  }
+
+  const masked = mask_source(fn.body || '');
+  for (const match of masked.matchAll(/\bvar\s+(\w+)\s+(\*?[\w.]+)/g)) {
+    bind_type(match[1], match[2]);
+  }
+
+  const assigned =
+    /(?<![\w.])(\w+)(?:\s*,\s*\w+)*\s*:?=\s*(&\s*)?([\w.]+)\s*(?:\[[^\]]*\])?\s*([{(])/g;
+  for (const match of masked.matchAll(assigned)) {
+    if (match[4] === '{') {
+      bind_type(match[1], match[3]);
+      continue;
+    }
+    const type_id = constructors.get(resolve(match[3]));
+    if (type_id && match[1] !== '_') variables.set(match[1], type_id);
+  }
+
+  return variables;
+};
+
+/**
+ * Find the composite literals of known struct types in a function body.
+ * Keyed literals set the named fields; positional literals set them all.
+ * @param {string} body - Function body
+ * @param {number} body_line - Line of the start of the body
+ * @param {Function} resolve - Resolves a name to a node ID
+ * @param {Map<string, Object>} structs - Struct types by node ID
+ * @returns {Object[]} Literals with type node ID, line and the fields they set
+ */
+const find_struct_literals = (body, body_line, resolve, structs) => {
+  const masked = mask_source(body || '');
+  const line_index = build_line_index(body || '');
+  const pattern = /(?<![\w.])([\w.]+)\s*(?:\[[^\]]*\])?\s*\{/g;
+  const literals = [];
+  let match;
+
+  while ((match = pattern.exec(masked)) !== null) {
+    const id = resolve(match[1]);
+    if (!id || !structs.has(id)) continue;
+
+    const open = match.index + match[0].length - 1;
+    const close = find_matching(masked, open);
+    if (close === -1) break;
+
+    const entries = split_top_level(masked.substring(open + 1, close)).filter(
+      (e) => e.length > 0
+    );
+    const keyed = entries.every((e) => /^\w+\s*:/.test(e));
+    const fields = structs.get(id).fields.flatMap((f) => f.names);
+    literals.push({
+      type: id,
+      line: body_line + line_at(line_index, match.index) - 1,
+      fields: keyed ? entries.map((e) => e.match(/^\w+/)[0]) : fields
+    });
+  }
+
+  return literals;
+};
+
+/**
+ * Decide how a field is typically initialized from its counts.
+ * @param {number} by_constructor - Initializations by constructors
+ * @param {number} by_caller - Initializations by callers
+ * @returns {string} One of TYPICAL_INIT_KINDS
+ */
+const get_typical_init = (by_constructor, by_caller) => {
+  if (by_constructor === 0 && by_caller === 0) return 'none';
+  if (by_constructor > by_caller) return 'constructor';
+  if (by_caller > by_constructor) return 'caller';
+  return 'mixed';
+};
+
+/**
+ * Build the suggestion for a field of a type that has constructors.
+ * @param {Object} field - Field info
+ * @param {string[]} constructors - Names of the type's constructors
+ * @returns {string|null} Suggestion, or null
+ */
+const get_field_init_suggestion = (field, constructors) => {
+  if (constructors.length === 0) return null;
+  const names = constructors.join(', ');
+  if (field.typical_init === 'constructor' && field.by_caller === 0) {
+    return `Only set by ${names}; consider unexporting it with a getter`;
+  }
+  if (field.assignments > 0) {
+    return `Assigned by callers after ${names}; consider unexporting it with a setter`;
+  }
+  return null;
+};
+
+/**
+ * Determine how the exported fields of a project's structs are
+ * initialized.  Struct literals and field assignments are attributed to
+ * the type's constructors (package functions returning the struct) or to
+ * callers; assignments in the type's own methods are not counted.  The
+ * struct type of an assigned variable is only known from receivers,
+ * parameters, declarations, literals and constructor calls, so this is a
+ * heuristic.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Struct types with constructors and field info (typical_init per field)
+ */
+const find_field_initializations = (packages) => {
+  const structs = new Map();
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind !== 'struct') continue;
+      structs.set(get_node_id(pkg.directory, type.name), { ...type, pkg });
+    }
+  }
+  const constructors = find_constructors(packages, structs);
+
+  // Initialization sites by struct node ID and field name
+  const sites = new Map();
+  const record = (type_id, field, site) => {
+    const key = `${type_id}.${field}`;
+    if (!sites.has(key)) sites.set(key, []);
+    sites.get(key).push(site);
+  };
+
+  for (const pkg of packages) {
+    const imported_by_file = new Map();
+    for (const fn of pkg.functions) {
+      if (!imported_by_file.has(fn.filename)) {
+        imported_by_file.set(
+          fn.filename,
+          get_imported_packages(pkg, fn.filename, packages)
+        );
+      }
+      const imported = imported_by_file.get(fn.filename);
+      const resolve = (text) => resolve_qualified_name(text, pkg, imported);
+
+      const built_type = constructors.get(get_node_id(pkg.directory, fn.name));
+      const owner = fn.receiver
+        ? get_node_id(pkg.directory, get_base_type(fn.receiver.type))
+        : null;
+      const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      const get_context = (type_id) => {
+        if (!fn.receiver && type_id === built_type) return 'constructor';
+        return type_id === owner ? 'method' : 'caller';
+      };
+
+      for (const literal of find_struct_literals(
+        fn.body,
+        fn.body_line,
+        resolve,
+        structs
+      )) {
+        const context = get_context(literal.type);
+        if (context === 'method') continue;
+        for (const field of literal.fields) {
+          record(literal.type, field, {
+            function: name,
+            filename: fn.filename,
+            line: literal.line,
+            kind: 'literal',
+            context
+          });
+        }
+      }
+
+      const variables = get_variable_types(fn, resolve, structs, constructors);
+      for (const write of summarize_go_body(fn.body, fn.body_line)
+        .field_writes) {
+        const type_id = variables.get(write.base);
+        if (!type_id || write.read_write) continue;
+        const context = get_context(type_id);
+        if (context === 'method') continue;
+        record(type_id, write.field, {
+          function: name,
+          filename: fn.filename,
+          line: write.line,
+          kind: 'assignment',
+          context
+        });
+      }
+    }
+  }
+
+  const types = [];
+  for (const [type_id, type] of structs) {
+    const exported_fields = type.fields.filter(
+      (f) => !f.embedded && f.names.some(is_exported)
+    );
+    if (exported_fields.length === 0) continue;
+
+    const type_constructors = [...constructors]
+      .filter(([, built]) => built === type_id)
+      .map(([fn_id]) => fn_id.split(':')[1]);
+
+    const fields = [];
+    for (const field of exported_fields) {
+      for (const field_name of field.names.filter(is_exported)) {
+        const field_sites = sites.get(`${type_id}.${field_name}`) || [];
+        const by_constructor = field_sites.filter(
+          (s) => s.context === 'constructor'
+        ).length;
+        const by_caller = field_sites.length - by_constructor;
+        const info = {
+          name: field_name,
+          type: field.type,
+          line: field.line,
+          by_constructor,
+          by_caller,
+          literals: field_sites.filter(
+            (s) => s.kind === 'literal' && s.context === 'caller'
+          ).length,
+          assignments: field_sites.filter(
+            (s) => s.kind === 'assignment' && s.context === 'caller'
+          ).length,
+          typical_init: get_typical_init(by_constructor, by_caller),
+          sites: field_sites
+        };
+        info.suggestion = get_field_init_suggestion(info, type_constructors);
+        fields.push(info);
+      }
+    }
+
+    types.push({
+      name: type.name,
+      package: type.pkg.name,
+      directory: type.pkg.directory,
+      filename: type.filename,
+      line: type.line,
+      constructors: type_constructors,
+      fields
+    });
+  }
+
+  return types;
+};
+
+/**
+ * Analyze how the exported struct fields of a project are initialized.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Struct types with field info and a summary
+ */
+const analyze_project_field_init = async (project_id) => {
+  const types = find_field_initializations(await load_go_packages(project_id));
+  const fields = types.flatMap((t) => t.fields);
+
+  const by_typical_init = {};
+  for (const kind of TYPICAL_INIT_KINDS) {
+    by_typical_init[kind] = fields.filter((f) => f.typical_init === kind).length;
+  }
+
+  return {
+    types,
+    summary: {
+      types_analyzed: types.length,
+      types_with_constructors: types.filter((t) => t.constructors.length > 0)
+        .length,
+      fields_analyzed: fields.length,
+      by_typical_init,
+      suggestions: fields.filter((f) => f.suggestion).length
+    }
+  };
+};
+
+// ============================================================================
+// STRUCT KINDS
+// ============================================================================
+
+/**
+ * Classify the role of a method in its type.
+ * - getter: a single statement returning a receiver field
+ * - setter: a single statement assigning a parameter to a receiver field
+ * - formatting: String, GoString or Error returning a string
+ * - behavior: anything else
+ * @param {Object} fn - Method (from the Go parser)
+ * @returns {string} Method role
+ */
+const classify_method_role = (fn) => {
+  const results = fn.results.map((r) => r.type);
+  if (
+    FORMATTING_METHODS.has(fn.name) &&
+    fn.params.length === 0 &&
+    results.join(',') === 'string'
+  ) {
+    return 'formatting';
+  }
+
+  const receiver = fn.receiver.name;
+  const statements = strip_comments(fn.body || '{}')
+    .slice(1, -1)
+    .split(/[\n;]/)
+    .map((line) => line.trim())
+    .filter(Boolean);
+  if (!receiver || statements.length !== 1) return 'behavior';
+
+  const [statement] = statements;
+  const field = `${receiver}\\.\\w+(?:\\.\\w+)*`;
+  if (
+    fn.params.length === 0 &&
+    results.length === 1 &&
+    new RegExp(`^return\\s+&?${field}$`).test(statement)
+  ) {
+    return 'getter';
+  }
+
+  const assignment = statement.match(
+    new RegExp(`^${field}\\s*=\\s*([A-Za-z_]\\w*)$`)
+  );
+  if (
+    assignment &&
+    results.length === 0 &&
+    fn.params.length === 1 &&
+    fn.params[0].name === assignment[1]
+  ) {
+    return 'setter';
+  }
+
+  return 'behavior';
+};
+
+/**
+ * Classify a struct as data, service or mixed (see STRUCT_KINDS).
+ * @param {Object} type - Struct type declaration
+ * @param {Object[]} methods - Methods declared on the type
+ * @returns {Object} Kind with the method roles and the counts it is based on
+ */
+const classify_struct_kind = (type, methods) => {
+  const roles = methods.map((fn) => ({
+    name: fn.name,
+    role: classify_method_role(fn),
+    line: fn.line
+  }));
+  const behavior = roles.filter((r) => r.role === 'behavior').length;
+  const exported_fields = type.fields
+    .filter((f) => !f.embedded)
+    .reduce((sum, f) => sum + f.names.filter(is_exported).length, 0);
+
+  let kind = 'data';
+  if (behavior > 0) kind = exported_fields > behavior ? 'mixed' : 'service';
+
+  let reason;
+  if (methods.length === 0) {
+    reason = 'no methods';
+  } else if (behavior === 0) {
+    reason = 'only accessor methods';
+  } else {
+    reason = `${behavior} behavior methods, ${exported_fields} exported fields`;
+  }
+
+  return {
+    kind,
+    reason,
+    exported_fields,
+    accessor_methods: roles.length - behavior,
+    behavior_methods: behavior,
+    methods: roles
+  };
+};
+
+/**
+ * Classify the structs of a set of packages as data, service or mixed.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Structs with their kind
+ */
+const classify_structs = (packages) => {
+  const structs = [];
+
+  for (const pkg of packages) {
+    for (const type of pkg.types.filter((t) => t.kind === 'struct')) {
+      structs.push({
+        name: type.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: type.filename,
+        line: type.line,
+        exported: type.exported,
+        fields: type.fields.reduce((sum, f) => sum + f.names.length, 0),
+        ...classify_struct_kind(type, pkg.methods[type.name] || [])
+      });
+    }
+  }
+
+  return structs;
+};
+
+/**
+ * Classify the structs of a project as data, service or mixed.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Structs with their kind and a summary
+ */
+const analyze_project_struct_kinds = async (project_id) => {
+  const structs = classify_structs(await load_go_packages(project_id));
+
+  const by_kind = {};
+  for (const kind of STRUCT_KINDS) {
+    by_kind[kind] = structs.filter((s) => s.kind === kind).length;
+  }
+
+  return {
+    structs,
+    summary: {
+      total_structs: structs.length,
+      by_kind
+    }
+  };
+};
+
+// ============================================================================
+// METHOD SHADOWING
+// ============================================================================
+
+/**
+ * Get the methods an embedded type promotes, by name.
+ * @param {Object} embedded - Embedded type declaration
+ * @param {Object} pkg - Package of the type
+ * @param {string} outer - Name of the embedding type, not to be expanded
+ * @returns {Map<string, Object>} Methods by name
+ */
+const get_promoted_methods = (embedded, pkg, outer) => {
+  if (embedded.kind === 'interface') {
+    return get_interface_methods(embedded, pkg) || new Map();
+  }
+  return get_type_methods(embedded.name, pkg, new Set([outer]));
+};
+
+/**
+ * Check whether a method calls the method it shadows through the embedded
+ * field (`e.User.Name()`).
+ * @param {Object} fn - Shadowing method
+ * @param {string} field_name - Name of the embedded field
+ * @returns {boolean} True if the method delegates to the embedded one
+ */
+const calls_shadowed_method = (fn, field_name) => {
+  const receiver = fn.receiver.name;
+  if (!receiver || !fn.body) return false;
+  return new RegExp(
+    `\\b${receiver}\\.${field_name}\\.${fn.name}\\s*\\(`
+  ).test(strip_comments(fn.body));
+};
+
+/**
+ * Find the interfaces of a package that an embedded type implements and
+ * the embedding type no longer does.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object} type - Embedding struct
+ * @param {Object} embedded - Embedded type declaration
+ * @returns {string[]} Interface names
+ */
+const find_hidden_interfaces = (pkg, type, embedded) => {
+  const inner = embedded.kind === 'interface'
+    ? get_interface_method_set(embedded, pkg)
+    : get_type_method_set(embedded.name, pkg);
+  if (!inner) return [];
+  const outer = get_type_method_set(type.name, pkg);
+
+  const hidden = [];
+  for (const iface of pkg.types.filter((t) => t.kind === 'interface')) {
+    const wanted = get_interface_method_set(iface, pkg);
+    if (!wanted || wanted.size === 0) continue;
+    const keys = [...wanted];
+    if (keys.every((k) => inner.has(k)) && !keys.every((k) => outer.has(k))) {
+      hidden.push(iface.name);
+    }
+  }
+  return hidden;
+};
+
+/**
+ * Find the methods and fields of the structs of a set of packages that
+ * shadow a method promoted from an embedded type of the same package.
+ * A method with the promoted signature is an override; it is intentional
+ * whether or not it calls the embedded method.  A method with another
+ * signature, or a field, is a collision, and reported as accidental unless
+ * it calls the embedded method or its doc comment names the embedded type
+ * or says it overrides or shadows it.  Collisions list the interfaces of
+ * the package the embedded type implements and the struct no longer does.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object[]} Shadowing reports by file and line
+ */
+const find_method_shadowing = (packages) => {
+  const reports = [];
+
+  for (const pkg of packages) {
+    const types = new Map(pkg.types.map((t) => [t.name, t]));
+
+    for (const type of pkg.types.filter((t) => t.kind === 'struct')) {
+      const own = new Map(
+        (pkg.methods[type.name] || []).map((fn) => [fn.name, fn])
+      );
+      const fields = new Map();
+      for (const field of type.fields.filter((f) => !f.embedded)) {
+        for (const name of field.names) fields.set(name, field);
+      }
+
+      for (const field of type.fields.filter((f) => f.embedded)) {
+        const embedded = types.get(get_base_type(field.type));
+        if (!embedded || embedded.name === type.name) continue;
+        const field_name = field.names[0];
+        let hidden = null;
+
+        for (const [name, method] of get_promoted_methods(
+          embedded,
+          pkg,
+          type.name
+        )) {
+          const fn = own.get(name);
+          const shadowing_field = fn ? null : fields.get(name);
+          if (!fn && !shadowing_field) continue;
+
+          const shadowed = `${embedded.name}.${name}`;
+          const report = {
+            type: type.name,
+            package: pkg.name,
+            directory: pkg.directory,
+            filename: fn ? fn.filename : type.filename,
+            line: fn ? fn.line : shadowing_field.line,
+            member: fn ? 'method' : 'field',
+            name,
+            signature: fn
+              ? format_method_signature(fn)
+              : `${name} ${shadowing_field.type}`,
+            embedded: embedded.name,
+            embedded_signature: format_method_signature(method),
+            embedded_filename: method.filename || embedded.filename,
+            embedded_line: method.line,
+            kind:
+              fn && get_method_key(fn) === get_method_key(method)
+                ? 'override'
+                : 'collision',
+            delegates: fn ? calls_shadowed_method(fn, field_name) : false,
+            documented: fn
+              ? new RegExp(
+                  `\\b(${embedded.name}|overrides?|shadows?)\\b`,
+                  'i'
+                ).test(fn.doc || '')
+              : false,
+            hidden_interfaces: []
+          };
+          report.intentional =
+            report.kind === 'override' || report.delegates || report.documented;
+
+          if (report.kind === 'override') {
+            report.message = `${type.name}.${name} overrides ${shadowed}${report.delegates ? ' and calls it' : ''}`;
+          } else {
+            if (!hidden) hidden = find_hidden_interfaces(pkg, type, embedded);
+            report.hidden_interfaces = hidden.filter((iface) =>
+              get_interface_methods(types.get(iface), pkg).has(name)
+            );
+            report.message = fn
+              ? `${type.name}.${report.signature} hides ${embedded.name}.${report.embedded_signature} with a different signature`
+              : `field ${type.name}.${name} hides method ${embedded.name}.${report.embedded_signature}`;
+            if (report.hidden_interfaces.length > 0) {
+              report.message += `; ${type.name} no longer implements ${report.hidden_interfaces.join(', ')}`;
+            }
+          }
+          reports.push(report);
+        }
+      }
+    }
+  }
+
+  return reports.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  });
+};
+
+/**
+ * Report method shadowing through embedding across a project.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Shadowing reports with a summary
+ */
+const analyze_project_method_shadowing = async (project_id) => {
+  const shadowing = find_method_shadowing(await load_go_packages(project_id));
+
+  return {
+    shadowing,
+    summary: {
+      total_shadowed: shadowing.length,
+      overrides: shadowing.filter((s) => s.kind === 'override').length,
+      collisions: shadowing.filter((s) => s.kind === 'collision').length,
+      accidental: shadowing.filter((s) => !s.intentional).length,
+      types: new Set(shadowing.map((s) => `${s.directory}.${s.type}`)).size
+    }
+  };
+};
+
+// ============================================================================
+// EMBEDDERS
+// ============================================================================
+
+/**
+ * Find the struct and interface types embedding a type, directly or, when
+ * asked for, through other embedding types (Manager embeds Employee which
+ * embeds User).  Embedding across packages (`staff.User`) is followed for
+ * project packages.  Each embedder comes with the path from it down to
+ * the embedded type; when several paths exist, the shortest is kept.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {string} type_name - Type name, optionally qualified by package name or directory (`staff.User`)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.transitive=false] - Include types embedding the type through other types
+ * @param {boolean} [options.include_unexported=false] - Include unexported embedders
+ * @returns {Object} The embedded type and its embedders, by depth and name
+ * @throws {Error} If the type is not found or is ambiguous
+ */
+const find_embedders = (packages, type_name, options = {}) => {
+  const dot = type_name.lastIndexOf('.');
+  const qualifier = dot === -1 ? null : type_name.substring(0, dot);
+  const name = type_name.substring(dot + 1);
+
+  const targets = packages.filter(
+    (pkg) =>
+      (!qualifier || pkg.name === qualifier || pkg.directory === qualifier) &&
+      pkg.types.some((t) => t.name === name)
+  );
+  if (targets.length === 0) {
+    throw new Error(`Type '${type_name}' not found in project`);
+  }
+  if (targets.length > 1) {
+    // Qualify by package name, or by directory when the names clash
+    const qualified = targets.map((pkg) => {
+      const clash = targets.some((p) => p !== pkg && p.name === pkg.name);
+      return `${clash ? pkg.directory : pkg.name}.${name}`;
+    });
+    const candidates = targets.map((pkg, i) => {
+      const type = pkg.types.find((t) => t.name === name);
+      return `${qualified[i]} (${type.filename}:${type.line})`;
+    });
+    throw new Error(
+      `Type '${type_name}' is ambiguous, found in: ${candidates.join(', ')}; qualify it with its package, such as '${qualified[0]}'`
+    );
+  }
+  const target = targets[0];
+
+  // Node IDs to their type and package, and embedded types to embedders
+  const nodes = new Map();
+  const embedded_by = new Map();
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      nodes.set(get_node_id(pkg.directory, type.name), { type, pkg });
+    }
+    for (const edge of find_embed_edges(pkg, packages)) {
+      if (!embedded_by.has(edge.target)) embedded_by.set(edge.target, []);
+      embedded_by.get(edge.target).push(edge);
+    }
+  }
+
+  const label = (id) => {
+    const { type, pkg } = nodes.get(id);
+    return pkg === target ? type.name : `${pkg.name}.${type.name}`;
+  };
+
+  const target_id = get_node_id(target.directory, name);
+  const paths = new Map([[target_id, [label(target_id)]]]);
+  const embedders = [];
+  let frontier = [target_id];
+
+  for (let depth = 1; frontier.length > 0; depth++) {
+    const next = [];
+    for (const id of frontier) {
+      for (const edge of embedded_by.get(id) || []) {
+        if (paths.has(edge.source)) continue;
+        const path = [label(edge.source), ...paths.get(id)];
+        paths.set(edge.source, path);
+        next.push(edge.source);
+
+        const { type, pkg } = nodes.get(edge.source);
+        if (!type.exported && !options.include_unexported) continue;
+        embedders.push({
+          name: type.name,
+          kind: type.kind,
+          package: pkg.name,
+          directory: pkg.directory,
+          filename: type.filename,
+          line: type.line,
+          embed_line: edge.line,
+          exported: type.exported,
+          depth,
+          via: depth > 1 ? label(id) : null,
+          path
+        });
+      }
+    }
+    if (!options.transitive) break;
+    frontier = next;
+  }
+
+  const found = nodes.get(target_id).type;
+  return {
+    type: {
+      name,
+      kind: found.kind,
+      package: target.name,
+      directory: target.directory,
+      filename: found.filename,
+      line: found.line
+    },
+    embedders: embedders.sort(function sort_by_depth(a, b) {
+      if (a.depth !== b.depth) return a.depth - b.depth;
+      return a.path.join('.') < b.path.join('.') ? -1 : 1;
+    })
+  };
+};
+
+/**
+ * Summarize the embedders of a type.
+ * @param {Object[]} embedders - Embedders (from find_embedders)
+ * @returns {Object} Numbers of embedders by depth, kind and package
+ */
+const summarize_embedders = (embedders) => {
+  return {
+    total_embedders: embedders.length,
+    direct: embedders.filter((e) => e.depth === 1).length,
+    transitive: embedders.filter((e) => e.depth > 1).length,
+    structs: embedders.filter((e) => e.kind === 'struct').length,
+    interfaces: embedders.filter((e) => e.kind === 'interface').length,
+    packages: new Set(embedders.map((e) => e.directory)).size
+  };
+};
+
+/**
+ * List the types embedding a type of a set of Go sources, such as the
+ * files of a directory.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {string} type_name - Type name (see find_embedders)
+ * @param {Object} [options] - Options (see find_embedders)
+ * @returns {Object} The embedded type, its embedders and a summary
+ * @throws {Error} If the type is not found or is ambiguous
+ */
+const find_source_embedders = (sources, type_name, options = {}) => {
+  const files = sources.map((file) =>
+    parse_go_file(file.source, file.filename)
+  );
+  const result = find_embedders(group_go_packages(files), type_name, options);
+  return { ...result, summary: summarize_embedders(result.embedders) };
+};
+
+/**
+ * List the types embedding a type of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {string} type_name - Type name (see find_embedders)
+ * @param {Object} [options] - Options (see find_embedders)
+ * @returns {Promise<Object>} The embedded type, its embedders and a summary
+ * @throws {Error} If the type is not found or is ambiguous
+ */
+const analyze_project_embedders = async (
+  project_id,
+  type_name,
+  options = {}
+) => {
+  const result = find_embedders(
+    await load_go_packages(project_id),
+    type_name,
+    options
+  );
+  return { ...result, summary: summarize_embedders(result.embedders) };
+};
+
+// ============================================================================
+// STRUCT TAGS
+// ============================================================================
+
+/**
+ * Parse a struct tag in the conventional format of reflect.StructTag:
+ * space separated `key:"value"` pairs, where a value is a Go string
+ * literal.  The name of a value is the part before the first comma, and
+ * the rest are options (`json:"id,omitempty"`).
+ * @param {string} tag - Tag text, without its back quotes
+ * @returns {Object} Tags by key, with an error for malformed tags
+ */
+const parse_struct_tag = (tag) => {
+  const tags = new Map();
+  const text = tag || '';
+  let i = 0;
+
+  while (i < text.length) {
+    while (i < text.length && text[i] === ' ') i++;
+    if (i >= text.length) break;
+
+    const key_start = i;
+    while (
+      i < text.length &&
+      text[i] > ' ' &&
+      text[i] !== ':' &&
+      text[i] !== '"'
+    ) {
+      i++;
+    }
+    const key = text.substring(key_start, i);
+    if (!key || text[i] !== ':' || text[i + 1] !== '"') {
+      return { tags, error: `malformed tag at '${text.substring(key_start)}'` };
+    }
+
+    i += 2;
+    let value = '';
+    while (i < text.length && text[i] !== '"') {
+      if (text[i] === '\\' && i + 1 < text.length) i++;
+      value += text[i];
+      i++;
+    }
+    if (i >= text.length) {
+      return { tags, error: `unterminated value of tag ${key}` };
+    }
+    i++;
+
+    const [name, ...options] = value.split(',');
+    if (!tags.has(key)) tags.set(key, { value, name, options });
+  }
+
+  return { tags, error: null };
+};
+
+/**
+ * Check a tag policy and fill in its defaults.
+ * @param {Object} [policy] - Tag policy
+ * @param {string[]} [policy.required] - Tag keys every checked field must have
+ * @param {Object} [policy.naming] - Naming rule by tag key (a NAMING_PATTERNS name, such as snake_case)
+ * @param {boolean} [policy.include_embedded=false] - Check embedded fields
+ * @param {boolean} [policy.include_unexported=false] - Check unexported fields
+ * @param {boolean} [policy.all_structs=false] - Check structs without any tag of the policy's keys
+ * @returns {Object} Complete policy
+ * @throws {Error} If a naming rule is unknown
+ */
+const get_tag_policy = (policy = {}) => {
+  const complete = {
+    required: policy.required || DEFAULT_TAG_POLICY.required,
+    naming: policy.naming || DEFAULT_TAG_POLICY.naming,
+    include_embedded: Boolean(policy.include_embedded),
+    include_unexported: Boolean(policy.include_unexported),
+    all_structs: Boolean(policy.all_structs)
+  };
+  for (const [key, rule] of Object.entries(complete.naming)) {
+    if (!NAMING_PATTERNS[rule]) {
+      throw new Error(
+        `Unknown naming rule '${rule}' for tag ${key} (expected one of: ${Object.keys(NAMING_PATTERNS).join(', ')})`
+      );
+    }
+  }
+  return complete;
+};
+
+/**
+ * Extract the tag schema of a struct: the tags of each of its fields.
+ * @param {Object} type - Struct type declaration
+ * @returns {Object[]} Fields with name, type, line, embedded and exported flags, tags by key and tag error
+ */
+const extract_tag_schema = (type) => {
+  return type.fields.flatMap((field) => {
+    const { tags, error } = parse_struct_tag(field.tag);
+    const names = field.embedded ? [get_base_type(field.type)] : field.names;
+    return names.map((name) => ({
+      name,
+      type: field.type,
+      line: field.line,
+      embedded: field.embedded,
+      exported: is_exported(name),
+      tags: Object.fromEntries(tags),
+      tag_error: error
+    }));
+  });
+};
+
+/**
+ * Select the tag schemas a policy applies to: structs outside test files,
+ * by default only those tagging a field with one of the policy's keys
+ * (the structs meant for serialization), and their fields other than
+ * embedded and unexported ones unless asked for.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} policy - Complete tag policy (from get_tag_policy)
+ * @returns {Object[]} Structs with their package and checked fields
+ */
+const select_tag_schemas = (packages, policy) => {
+  const keys = new Set([...policy.required, ...Object.keys(policy.naming)]);
+  const schemas = [];
+
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind !== 'struct' || type.filename.endsWith('_test.go')) {
+        continue;
+      }
+      const fields = extract_tag_schema(type);
+      const tagged = fields.some((f) =>
+        Object.keys(f.tags).some((k) => keys.has(k))
+      );
+      if (!tagged && !policy.all_structs) continue;
+
+      schemas.push({
+        type,
+        pkg,
+        fields: fields.filter(
+          (f) =>
+            (policy.include_embedded || !f.embedded) &&
+            (policy.include_unexported || f.exported)
+        )
+      });
+    }
+  }
+
+  return schemas;
+};
+
+/**
+ * Validate the struct tags of a set of packages against a tag policy.
+ * A field ignored by a key (`json:"-"`) satisfies the requirement and
+ * its name is not checked; a field without a name in its tag
+ * (`json:",omitempty"`) is named after the field, and that name is
+ * checked.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [policy] - Tag policy (see get_tag_policy)
+ * @returns {Object[]} Diagnostics with struct, field, key, rule and message, by file and line
+ * @throws {Error} If a naming rule is unknown
+ */
+const validate_struct_tags = (packages, policy = {}) => {
+  const complete = get_tag_policy(policy);
+  const diagnostics = [];
+
+  for (const { type, pkg, fields } of select_tag_schemas(packages, complete)) {
+    for (const field of fields) {
+      const report = (key, rule, message, extra = {}) => {
+        diagnostics.push({
+          type: type.name,
+          field: field.name,
+          package: pkg.name,
+          directory: pkg.directory,
+          filename: type.filename,
+          line: field.line,
+          key,
+          rule,
+          severity: 'warning',
+          ...extra,
+          message: `${type.name}.${field.name}: ${message}`
+        });
+      };
+
+      if (field.tag_error) {
+        report(
+          null,
+          'syntax',
+          `struct tag is not in key:"value" format (${field.tag_error})`
+        );
+        continue;
+      }
+
+      for (const key of complete.required) {
+        if (!field.tags[key]) report(key, 'required', `missing ${key} tag`);
+      }
+
+      for (const [key, rule] of Object.entries(complete.naming)) {
+        const tag = field.tags[key];
+        if (!tag || tag.name === '-') continue;
+        const name = tag.name || field.name;
+        if (NAMING_PATTERNS[rule].test(name)) continue;
+        report(
+          key,
+          'naming',
+          tag.name
+            ? `${key} name "${name}" is not ${rule}`
+            : `${key} tag has no name, so the field name ${name} is used, which is not ${rule}`,
+          { value: tag.value, expected: rule }
+        );
+      }
+    }
+  }
+
+  return diagnostics.sort(function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line || a.field.localeCompare(b.field);
+  });
+};
+
+/**
+ * Validate the struct tags of a project against a tag policy.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [policy] - Tag policy (see get_tag_policy)
+ * @returns {Promise<Object>} Policy, diagnostics, tag schemas and a summary by rule
+ * @throws {Error} If a naming rule is unknown
+ */
+const analyze_project_struct_tags = async (project_id, policy = {}) => {
+  const packages = await load_go_packages(project_id);
+  const complete = get_tag_policy(policy);
+  const schemas = select_tag_schemas(packages, complete);
+  const diagnostics = validate_struct_tags(packages, complete);
+
+  const by_rule = Object.fromEntries(TAG_RULES.map((r) => [r, 0]));
+  for (const diagnostic of diagnostics) by_rule[diagnostic.rule]++;
+  return {
+    policy: complete,
+    diagnostics,
+    schemas: schemas.map(({ type, pkg, fields }) => ({
+      type: type.name,
+      package: pkg.name,
+      directory: pkg.directory,
+      filename: type.filename,
+      line: type.line,
+      fields
+    })),
+    summary: {
+      structs: schemas.length,
+      fields: schemas.reduce((sum, s) => sum + s.fields.length, 0),
+      total_diagnostics: diagnostics.length,
+      fields_with_diagnostics: new Set(
+        diagnostics.map((d) => `${d.directory}.${d.type}.${d.field}`)
+      ).size,
+      by_rule
+    }
+  };
+};
+
+// ============================================================================
+// TYPE COMPLEXITY
+// ============================================================================
+
+/**
+ * Compute the complexity of a type: the cyclomatic complexity of each of
+ * its methods (see calculate_go_complexity) and their total.  Promoted
+ * methods of embedded types of the package are included when asked, with
+ * the type declaring them; methods of the type shadow promoted ones.
+ * @param {Object} type - Type declaration
+ * @param {Object} pkg - Package containing the type
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.include_promoted=false] - Include promoted methods
+ * @returns {Object} Methods with complexity, total, average and maximum
+ */
+const compute_type_complexity = (type, pkg, options = {}) => {
+  const declared = (pkg.methods[type.name] || []).filter(
+    (fn) => !fn.filename.endsWith('_test.go')
+  );
+  const methods = options.include_promoted
+    ? [...get_type_methods(type.name, pkg).values()].filter(
+        (fn) => !fn.filename.endsWith('_test.go')
+      )
+    : declared;
+
+  const complexities = methods
+    .map((fn) => ({
+      name: fn.name,
+      complexity: calculate_go_complexity(fn.body),
+      line: fn.line,
+      promoted_from:
+        get_base_type(fn.receiver.type) === type.name
+          ? null
+          : get_base_type(fn.receiver.type)
+    }))
+    .sort(function sort_by_complexity(a, b) {
+      return b.complexity - a.complexity || a.name.localeCompare(b.name);
+    });
+  const total = complexities.reduce((sum, m) => sum + m.complexity, 0);
+
+  return {
+    method_count: complexities.length,
+    total_complexity: total,
+    average_complexity:
+      complexities.length > 0
+        ? Math.round((total / complexities.length) * 10) / 10
+        : 0,
+    max_complexity: complexities.length > 0 ? complexities[0].complexity : 0,
+    methods: complexities
+  };
+};
+
+/**
+ * Rank the types of a set of packages by the total complexity of their
+ * methods, the most complex first.  Types without methods, interfaces and
+ * types of test files are left out.  The share of a type is the part of
+ * the complexity of all the methods of the project it concentrates.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options (see compute_type_complexity)
+ * @returns {Object[]} Types with their complexity and share
+ */
+const find_type_complexity = (packages, options = {}) => {
+  const types = [];
+
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind === 'interface' || type.filename.endsWith('_test.go')) {
+        continue;
+      }
+      const complexity = compute_type_complexity(type, pkg, options);
+      if (complexity.method_count === 0) continue;
+      types.push({
+        name: type.name,
+        kind: type.kind,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: type.filename,
+        line: type.line,
+        exported: type.exported,
+        ...complexity
+      });
+    }
+  }
+
+  // Shares of the declared methods only, which promoted ones would count twice
+  const project_total = packages.reduce(
+    (sum, pkg) =>
+      sum +
+      Object.values(pkg.methods)
+        .flat()
+        .filter((fn) => !fn.filename.endsWith('_test.go'))
+        .reduce((total, fn) => total + calculate_go_complexity(fn.body), 0),
+    0
+  );
+
+  return types
+    .map((type) => ({
+      ...type,
+      share:
+        project_total > 0
+          ? Math.round((type.total_complexity / project_total) * 1000) / 10
+          : 0
+    }))
+    .sort(function sort_by_total_complexity(a, b) {
+      return (
+        b.total_complexity - a.total_complexity ||
+        a.directory.localeCompare(b.directory) ||
+        a.name.localeCompare(b.name)
+      );
+    });
+};
+
+/**
+ * Report the most complex types of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see compute_type_complexity)
+ * @param {number} [options.limit=10] - Number of types to list
+ * @returns {Promise<Object>} Most complex types and a summary
+ */
+const analyze_project_type_complexity = async (project_id, options = {}) => {
+  const types = find_type_complexity(
+    await load_go_packages(project_id),
+    options
+  );
+  const limit = options.limit || DEFAULT_TOP_TYPES;
+
+  return {
+    top_types: types.slice(0, limit),
+    summary: {
+      total_types: types.length,
+      total_methods: types.reduce((sum, t) => sum + t.method_count, 0),
+      average_complexity:
+        types.length > 0
+          ? Math.round(
+              (types.reduce((sum, t) => sum + t.total_complexity, 0) /
+                types.length) *
+                10
+            ) / 10
+          : 0,
+      most_complex: types.length > 0 ? types[0].name : null,
+      include_promoted: Boolean(options.include_promoted),
+      limit
+    }
+  };
+};
+
+// ============================================================================
+// COPY LOCKS
+// ============================================================================
+
+/**
+ * Check whether a type of a package is a lock: its pointer has Lock and
+ * Unlock methods and its value does not, like `sync.Mutex` and the
+ * `noCopy` marker (`func (*noCopy) Lock() {}`).
+ * @param {string} name - Type name
+ * @param {Object} pkg - Package containing the type
+ * @returns {boolean} True for lock types
+ */
+const is_lock_type = (name, pkg) => {
+  const methods = (pkg.methods[name] || []).filter(
+    (fn) =>
+      (fn.name === 'Lock' || fn.name === 'Unlock') &&
+      fn.params.length === 0 &&
+      fn.results.length === 0
+  );
+  return (
+    new Set(methods.map((fn) => fn.name)).size === 2 &&
+    methods.some((fn) => fn.receiver.pointer)
+  );
+};
+
+/**
+ * Find the lock a value of a type contains, following struct fields and
+ * array elements but not pointers, slices, maps, channels or interfaces,
+ * which share rather than copy what they refer to.  Types are resolved in
+ * their package, in the project packages imported by the file using them
+ * and in NO_COPY_TYPES.
+ * @param {string} type_text - Type expression
+ * @param {Object} pkg - Package the type is used in
+ * @param {string} filename - File the type is used in
+ * @param {Object[]} packages - All project packages
+ * @param {Set<string>} [seen] - Types already followed
+ * @returns {Object|null} Lock type and field path to it, or null
+ */
+const find_contained_lock = (
+  type_text,
+  pkg,
+  filename,
+  packages,
+  seen = new Set()
+) => {
+  const text = (type_text || '').trim();
+  const kind = classify_type(text);
+
+  if (kind === 'array') {
+    return find_contained_lock(
+      text.substring(text.indexOf(']') + 1),
+      pkg,
+      filename,
+      packages,
+      seen
+    );
+  }
+  if (kind === 'struct') {
+    return find_field_lock(
+      parse_struct_type_fields(text),
+      pkg,
+      filename,
+      packages,
+      seen
+    );
+  }
+  if (kind !== 'named') return null;
+
+  const name = text.replace(/\[[\s\S]*\]$/, '');
+  const dot = name.indexOf('.');
+  if (dot !== -1) {
+    const qualifier = name.substring(0, dot);
+    const member = name.substring(dot + 1);
+    const imp = pkg.imports.find(
+      (i) => i.filename === filename && get_import_name(i).name === qualifier
+    );
+    if (imp && NO_COPY_TYPES.has(`${imp.path}.${member}`)) {
+      return { lock: name, path: [] };
+    }
+    const target = get_imported_packages(pkg, filename, packages).get(
+      qualifier
+    );
+    if (!target) return null;
+    const declared = target.types.find((t) => t.name === member);
+    const lock =
+      declared &&
+      find_contained_lock(member, target, declared.filename, packages, seen);
+    return lock && lock.path.length === 0
+      ? { lock: `${qualifier}.${lock.lock}`, path: [] }
+      : lock;
+  }
+
+  const key = `${pkg.directory}:${name}`;
+  const declared = pkg.types.find((t) => t.name === name);
+  if (!declared || seen.has(key)) return null;
+  if (is_lock_type(name, pkg)) return { lock: name, path: [] };
+
+  seen.add(key);
+  let lock = null;
+  if (declared.kind === 'struct') {
+    lock = find_field_lock(
+      declared.fields,
+      pkg,
+      declared.filename,
+      packages,
+      seen
+    );
+  } else if (declared.kind === 'alias' || declared.kind === 'named') {
+    lock = find_contained_lock(
+      declared.underlying,
+      pkg,
+      declared.filename,
+      packages,
+      seen
+    );
+  }
+  seen.delete(key);
+  return lock;
+};
+
+/**
+ * Find the first lock contained by the fields of a struct (see
+ * find_contained_lock).  Embedded fields are named by their type.
+ * @param {Object[]} fields - Struct fields (from the Go parser)
+ * @param {Object} pkg - Package declaring the struct
+ * @param {string} filename - File declaring the struct
+ * @param {Object[]} packages - All project packages
+ * @param {Set<string>} seen - Types already followed
+ * @returns {Object|null} Lock type and field path to it, or null
+ */
+const find_field_lock = (fields, pkg, filename, packages, seen) => {
+  for (const field of fields) {
+    const lock = find_contained_lock(
+      field.type,
+      pkg,
+      filename,
+      packages,
+      seen
+    );
+    if (!lock) continue;
+    const name = field.embedded
+      ? get_base_type(field.type).replace(/^\w+\./, '')
+      : field.names[0];
+    return { lock: lock.lock, path: [name, ...lock.path] };
+  }
+  return null;
+};
+
+/**
+ * Format the lock a type contains: `Cache.mu (sync.Mutex)`, or the lock
+ * type itself.
+ * @param {string} type_name - Type containing the lock
+ * @param {Object} lock - Lock (from find_contained_lock)
+ * @returns {string} Description of the lock
+ */
+const format_contained_lock = (type_name, lock) => {
+  return lock.path.length === 0
+    ? lock.lock
+    : `${type_name}.${lock.path.join('.')} (${lock.lock})`;
+};
+
+/**
+ * Find the types of a set of packages that must not be copied, and the
+ * functions copying them: value receivers and by-value parameters of such
+ * types, which copy their locks on every call.  Variadic parameters are
+ * passed as slices and are not flagged.  Declarations of test files are
+ * left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @returns {Object} No-copy types and findings, by file and line
+ */
+const find_copy_locks = (packages) => {
+  const no_copy_types = [];
+  const findings = [];
+
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind === 'interface' || type.filename.endsWith('_test.go')) {
+        continue;
+      }
+      const lock = find_contained_lock(
+        type.name,
+        pkg,
+        type.filename,
+        packages
+      );
+      if (!lock) continue;
+      no_copy_types.push({
+        name: type.name,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: type.filename,
+        line: type.line,
+        lock: lock.lock,
+        path: lock.path.join('.')
+      });
+    }
+
+    const check = (fn, kind, name, type) => {
+      const lock = find_contained_lock(type, pkg, fn.filename, packages);
+      if (!lock) return;
+      const type_name = get_base_type(type);
+      findings.push({
+        function: fn.name,
+        receiver: fn.receiver ? fn.receiver.type : null,
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        kind,
+        parameter: name,
+        type,
+        lock: lock.lock,
+        path: lock.path.join('.'),
+        message: `${kind === 'receiver' ? 'Receiver' : 'Parameter'} '${name || '_'}' passes ${type_name} by value, copying ${format_contained_lock(type_name, lock)}; pass a pointer instead`
+      });
+    };
+
+    for (const fn of pkg.functions) {
+      if (fn.filename.endsWith('_test.go')) continue;
+      if (fn.receiver && !fn.receiver.pointer) {
+        check(fn, 'receiver', fn.receiver.name, fn.receiver.type);
+      }
+      for (const param of fn.params) {
+        if (param.variadic) continue;
+        check(fn, 'parameter', param.name, param.type);
+      }
+    }
+  }
+
+  function sort_by_location(a, b) {
+    if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+    return a.line - b.line;
+  }
+  return {
+    no_copy_types: no_copy_types.sort(sort_by_location),
+    findings: findings.sort(sort_by_location)
+  };
+};
+
+/**
+ * Report the types of a project that must not be copied and the functions
+ * passing them by value.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} No-copy types, findings and a summary
+ */
+const analyze_project_copy_locks = async (project_id) => {
+  const result = find_copy_locks(await load_go_packages(project_id));
+
+  return {
+    ...result,
+    summary: {
+      no_copy_types: result.no_copy_types.length,
+      findings: result.findings.length,
+      receivers: result.findings.filter((f) => f.kind === 'receiver').length,
+      parameters: result.findings.filter((f) => f.kind === 'parameter').length,
+      affected_functions: new Set(
+        result.findings.map((f) => `${f.filename}:${f.line}`)
+      ).size
+    }
+  };
+};
+
+// ============================================================================
+// FIELD TYPES
+// ============================================================================
+
+/**
+ * Normalize a field type for counting: whitespace is collapsed, struct
+ * literals are counted as `struct{...}` and type names of the project are
+ * resolved, inside composite types too (`[]UserID`), following the
+ * resolution (see FIELD_TYPE_RESOLUTIONS).  Generic types are kept.
+ * @param {string} type_text - Field type
+ * @param {Object} pkg - Package the type is used in
+ * @param {string} filename - File the type is used in
+ * @param {Object[]} packages - All project packages
+ * @param {string} resolution - Resolution (none, alias or underlying)
+ * @param {Set<string>} [seen] - Types already resolved
+ * @returns {string} Normalized type
+ */
+const normalize_field_type = (
+  type_text,
+  pkg,
+  filename,
+  packages,
+  resolution,
+  seen = new Set()
+) => {
+  const text = (type_text || '')
+    .replace(/\s+/g, ' ')
+    .trim()
+    .replace(/(\*|\]) /g, '$1')
+    .replace(/\b(struct|interface) \{/g, '$1{');
+  if (classify_type(text) === 'struct') return 'struct{...}';
+  if (resolution === 'none') return text;
+
+  return text.replace(
+    /(?<![\w.])([A-Za-z_]\w*)(?:\.([A-Za-z_]\w*))?(?![\w[])/g,
+    function resolve_name(match, first, second) {
+      const target = second
+        ? get_imported_packages(pkg, filename, packages).get(first)
+        : pkg;
+      const name = second || first;
+      const declared = target && target.types.find((t) => t.name === name);
+      const key = target ? `${target.directory}:${name}` : null;
+      if (!declared || declared.type_params || seen.has(key)) return match;
+      if (declared.kind === 'struct' || declared.kind === 'interface') {
+        return match;
+      }
+      if (declared.kind !== 'alias' && resolution !== 'underlying') {
+        return match;
+      }
+
+      seen.add(key);
+      const resolved = normalize_field_type(
+        declared.underlying,
+        target,
+        declared.filename,
+        packages,
+        resolution,
+        seen
+      );
+      seen.delete(key);
+      return resolved;
+    }
+  );
+};
+
+/**
+ * Count the field types of the structs of a set of packages, one per
+ * field name (`X, Y int` counts int twice).  The fields of struct
+ * literals are counted too.  Embedded fields are composition rather than
+ * data and are left out unless asked for, as are the structs of test
+ * files.  Fields declared with the same name and different types are
+ * reported as inconsistent.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {string} [options.resolution='alias'] - Type resolution (see FIELD_TYPE_RESOLUTIONS)
+ * @param {boolean} [options.include_embedded=false] - Count embedded fields
+ * @returns {Object} Histogram, types with their counts and inconsistent field names
+ * @throws {Error} If the resolution is unknown
+ */
+const find_field_types = (packages, options = {}) => {
+  const resolution = options.resolution || 'alias';
+  if (!FIELD_TYPE_RESOLUTIONS.includes(resolution)) {
+    throw new Error(
+      `Unknown type resolution '${resolution}' (expected one of: ${FIELD_TYPE_RESOLUTIONS.join(', ')})`
+    );
+  }
+
+  const types = new Map();
+  const names = new Map();
+  const count_fields = (fields, pkg, type) => {
+    for (const field of fields) {
+      if (field.embedded && !options.include_embedded) continue;
+      const normalized = normalize_field_type(
+        field.type,
+        pkg,
+        type.filename,
+        packages,
+        resolution
+      );
+      if (!types.has(normalized)) {
+        types.set(normalized, {
+          type: normalized,
+          count: 0,
+          structs: new Set()
+        });
+      }
+      const entry = types.get(normalized);
+      const field_names = field.embedded
+        ? [get_base_type(field.type).replace(/^\w+\./, '')]
+        : field.names;
+      entry.count += field_names.length;
+      entry.structs.add(`${pkg.directory}:${type.name}`);
+
+      for (const name of field_names) {
+        if (name === '_') continue;
+        if (!names.has(name)) names.set(name, new Map());
+        const by_type = names.get(name);
+        by_type.set(normalized, (by_type.get(normalized) || 0) + 1);
+      }
+
+      if (classify_type(field.type.trim()) === 'struct') {
+        count_fields(parse_struct_type_fields(field.type), pkg, type);
+      }
+    }
+  };
+
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind !== 'struct' || type.filename.endsWith('_test.go')) {
+        continue;
+      }
+      count_fields(type.fields, pkg, type);
+    }
+  }
+
+  const sorted = [...types.values()]
+    .map((entry) => ({
+      type: entry.type,
+      kind: classify_type(entry.type),
+      count: entry.count,
+      structs: entry.structs.size
+    }))
+    .sort(function sort_by_count(a, b) {
+      return b.count - a.count || (a.type < b.type ? -1 : 1);
+    });
+
+  const inconsistent_fields = [...names]
+    .filter(([, by_type]) => by_type.size > 1)
+    .map(([name, by_type]) => ({
+      name,
+      types: Object.fromEntries(
+        [...by_type].sort((a, b) => b[1] - a[1] || (a[0] < b[0] ? -1 : 1))
+      ),
+      total: [...by_type.values()].reduce((sum, n) => sum + n, 0)
+    }))
+    .sort(function sort_by_total(a, b) {
+      return b.total - a.total || (a.name < b.name ? -1 : 1);
+    });
+
+  return {
+    resolution,
+    histogram: Object.fromEntries(sorted.map((t) => [t.type, t.count])),
+    types: sorted,
+    inconsistent_fields
+  };
+};
+
+/**
+ * Report the field type histogram of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_field_types)
+ * @returns {Promise<Object>} Histogram, types, inconsistent fields and a summary
+ */
+const analyze_project_field_types = async (project_id, options = {}) => {
+  const result = find_field_types(await load_go_packages(project_id), options);
+
+  return {
+    ...result,
+    summary: {
+      total_fields: result.types.reduce((sum, t) => sum + t.count, 0),
+      distinct_types: result.types.length,
+      most_common: result.types.length > 0 ? result.types[0].type : null,
+      inconsistent_fields: result.inconsistent_fields.length,
+      resolution: result.resolution
+    }
+  };
+};
+
+// ============================================================================
+// OPTIONS STRUCTS
+// ============================================================================
+
+/**
+ * Check whether a struct type is an options struct, by its name, or by
+ * being a plain data struct (no methods, only exported fields) with many
+ * fields.
+ * @param {Object} type - Struct type (from the Go parser)
+ * @param {Object} pkg - Package of the type
+ * @param {number} min_fields - Number of fields from which a data struct is an options struct
+ * @returns {string|null} Reason (name or fields), or null
+ */
+const get_options_struct_reason = (type, pkg, min_fields) => {
+  if (OPTIONS_STRUCT_PATTERN.test(type.name)) return 'name';
+  const names = type.fields.flatMap((field) => field.names);
+  const plain =
+    (pkg.methods[type.name] || []).length === 0 &&
+    type.fields.every((field) => !field.embedded) &&
+    names.every((name) => is_exported(name));
+  return plain && names.length >= min_fields ? 'fields' : null;
+};
+
+/**
+ * List the options of an options struct: its fields, one per name, with
+ * their type and doc.
+ * @param {Object} type - Struct type (from the Go parser)
+ * @returns {Object[]} Options with name, type, doc and line
+ */
+const list_struct_options = (type) => {
+  return type.fields.flatMap((field) =>
+    field.names
+      .filter((name) => name !== '_')
+      .map((name) => ({
+        name,
+        type: field.type,
+        doc: get_comment_text(field.doc || field.comment || '') || null,
+        line: field.line
+      }))
+  );
+};
+
+/**
+ * Find the functions and methods of a set of packages whose sole or last
+ * parameter is an options struct of the project, by value or by pointer,
+ * linked to the struct and its options.  Variadic parameters (functional
+ * options), the methods of the options struct itself and test files are
+ * left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {number} [options.min_fields=4] - Number of exported fields from which a data struct is an options struct
+ * @returns {Object} Functions with their options_param, and options structs with the functions taking them
+ */
+const find_options_structs = (packages, options = {}) => {
+  const min_fields = options.min_fields || DEFAULT_MIN_OPTION_FIELDS;
+  const functions = [];
+  const structs = new Map();
+
+  for (const pkg of packages) {
+    for (const fn of pkg.functions) {
+      if (fn.filename.endsWith('_test.go') || fn.params.length === 0) continue;
+      const position = fn.params.length - 1;
+      const param = fn.params[position];
+      if (param.variadic) continue;
+      const resolved = resolve_project_type(
+        param.type,
+        pkg,
+        fn.filename,
+        packages
+      );
+      if (!resolved || resolved.type.kind !== 'struct') continue;
+      if (resolved.type.filename.endsWith('_test.go')) continue;
+      if (
+        fn.receiver &&
+        resolved.pkg === pkg &&
+        fn.receiver.type === resolved.type.name
+      ) {
+        continue;
+      }
+      const reason = get_options_struct_reason(
+        resolved.type,
+        resolved.pkg,
+        min_fields
+      );
+      if (!reason) continue;
+
+      const id = get_node_id(resolved.pkg.directory, resolved.type.name);
+      const symbol = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+      if (!structs.has(id)) {
+        structs.set(id, {
+          id,
+          name: resolved.type.name,
+          package: resolved.pkg.name,
+          directory: resolved.pkg.directory,
+          filename: resolved.type.filename,
+          line: resolved.type.line,
+          reason,
+          options: list_struct_options(resolved.type),
+          functions: []
+        });
+      }
+      structs.get(id).functions.push(get_node_id(pkg.directory, symbol));
+
+      functions.push({
+        symbol,
+        kind: fn.receiver ? 'method' : 'function',
+        package: pkg.name,
+        directory: pkg.directory,
+        filename: fn.filename,
+        line: fn.line,
+        exported: fn.exported,
+        options_param: {
+          name: param.name,
+          // 1-based, as in "the second parameter"
+          position: position + 1,
+          type: param.type,
+          pointer: resolved.pointer,
+          struct: id,
+          reason
+        }
+      });
+    }
+  }
+
+  return {
+    functions: functions.sort(function sort_by_location(a, b) {
+      if (a.filename !== b.filename) return a.filename < b.filename ? -1 : 1;
+      return a.line - b.line;
+    }),
+    structs: [...structs.values()].sort(function sort_by_id(a, b) {
+      return a.id < b.id ? -1 : 1;
+    })
+  };
+};
+
+/**
+ * Report the options structs of a project and the functions taking them.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_options_structs)
+ * @returns {Promise<Object>} Functions, options structs and a summary
+ */
+const analyze_project_options_structs = async (project_id, options = {}) => {
+  const result = find_options_structs(
+    await load_go_packages(project_id),
+    options
+  );
+
+  return {
+    ...result,
+    summary: {
+      functions: result.functions.length,
+      options_structs: result.structs.length,
+      by_name: result.structs.filter((s) => s.reason === 'name').length,
+      by_fields: result.structs.filter((s) => s.reason === 'fields').length
+    }
+  };
+};
+
+// ============================================================================
+// RECURSIVE STRUCTS
+// ============================================================================
+
+/**
+ * Find the project struct types referenced by a field type, through
+ * pointers, slices, maps, channels and the fields of struct literals.
+ * @param {string} type_text - Field type
+ * @param {Object} pkg - Package of the field
+ * @param {string} filename - File of the field
+ * @param {Object[]} packages - All project packages
+ * @param {Set<string>} type_params - Type parameter names of the struct
+ * @returns {string[]} Node IDs of the structs referenced
+ */
+const find_field_struct_refs = (
+  type_text,
+  pkg,
+  filename,
+  packages,
+  type_params
+) => {
+  if (classify_type(type_text.trim()) === 'struct') {
+    return parse_struct_type_fields(type_text).flatMap((field) =>
+      find_field_struct_refs(field.type, pkg, filename, packages, type_params)
+    );
+  }
+
+  const refs = [];
+  const pattern = /(?<![\w.])([A-Za-z_]\w*)(?:\.([A-Za-z_]\w*))?/g;
+  let match;
+  while ((match = pattern.exec(type_text)) !== null) {
+    const [, first, second] = match;
+    if (!second && type_params.has(first)) continue;
+    const target = second
+      ? get_imported_packages(pkg, filename, packages).get(first)
+      : pkg;
+    const name = second || first;
+    const type = target && target.types.find((t) => t.name === name);
+    if (type && type.kind === 'struct') {
+      refs.push(get_node_id(target.directory, name));
+    }
+  }
+  return refs;
+};
+
+/**
+ * Find the strongly connected components of a graph (Tarjan).
+ * @param {Map<string, Set<string>>} edges - Targets by node
+ * @returns {string[][]} Components
+ */
+const find_strong_components = (edges) => {
+  const index = new Map();
+  const low = new Map();
+  const stack = [];
+  const on_stack = new Set();
+  const components = [];
+  let counter = 0;
+
+  const connect = (node) => {
+    index.set(node, counter);
+    low.set(node, counter);
+    counter++;
+    stack.push(node);
+    on_stack.add(node);
+
+    for (const target of edges.get(node) || []) {
+      if (!edges.has(target)) continue;
+      if (!index.has(target)) {
+        connect(target);
+        low.set(node, Math.min(low.get(node), low.get(target)));
+      } else if (on_stack.has(target)) {
+        low.set(node, Math.min(low.get(node), index.get(target)));
+      }
+    }
+
+    if (low.get(node) === index.get(node)) {
+      const component = [];
+      let member;
+      do {
+        member = stack.pop();
+        on_stack.delete(member);
+        component.push(member);
+      } while (member !== node);
+      components.push(component.sort());
+    }
+  };
+
+  for (const node of edges.keys()) {
+    if (!index.has(node)) connect(node);
+  }
+  return components;
+};
+
+/**
+ * Find the recursive structs of a set of packages: the structs whose
+ * fields reference themselves directly, or through a group of mutually
+ * recursive structs.  Each struct lists the fields referencing itself and
+ * the other structs of its group.  Structs of test files are left out.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {boolean} [options.recursive_only=false] - Only list recursive structs
+ * @returns {Object[]} Structs with recursive, recursion, self_fields, group and group_fields, by ID
+ */
+const find_recursive_structs = (packages, options = {}) => {
+  const structs = new Map();
+  const edges = new Map();
+
+  for (const pkg of packages) {
+    for (const type of pkg.types) {
+      if (type.kind !== 'struct' || type.filename.endsWith('_test.go')) {
+        continue;
+      }
+      const id = get_node_id(pkg.directory, type.name);
+      const type_params = new Set(
+        split_top_level(type.type_params || '').map(
+          (param) => param.trim().split(/\s+/)[0]
+        )
+      );
+      const fields = [];
+      for (const field of type.fields) {
+        const refs = find_field_struct_refs(
+          field.type,
+          pkg,
+          type.filename,
+          packages,
+          type_params
+        );
+        if (refs.length === 0) continue;
+        const names = field.embedded
+          ? [get_base_type(field.type).replace(/^\w+\./, '')]
+          : field.names;
+        for (const name of names) {
+          fields.push({
+            name,
+            type: field.type,
+            via: classify_type(field.type.trim()),
+            line: field.line,
+            refs: [...new Set(refs)]
+          });
+        }
+      }
+      structs.set(id, { id, type, pkg, fields });
+      edges.set(id, new Set(fields.flatMap((field) => field.refs)));
+    }
+  }
+
+  const groups = new Map();
+  for (const component of find_strong_components(edges)) {
+    for (const id of component) groups.set(id, component);
+  }
+
+  const results = [];
+  for (const { id, type, pkg, fields } of structs.values()) {
+    const group = groups.get(id).filter((member) => member !== id);
+    const strip = ({ refs, ...field }) => field;
+    const self_fields = fields.filter((f) => f.refs.includes(id)).map(strip);
+    const group_fields = fields
+      .filter((f) => f.refs.some((ref) => group.includes(ref)))
+      .map((f) => ({
+        ...strip(f),
+        targets: f.refs.filter((ref) => group.includes(ref))
+      }));
+
+    let recursion = null;
+    if (self_fields.length > 0) recursion = 'direct';
+    else if (group.length > 0) recursion = 'mutual';
+    if (!recursion && options.recursive_only) continue;
+
+    results.push({
+      id,
+      name: type.name,
+      package: pkg.name,
+      directory: pkg.directory,
+      filename: type.filename,
+      line: type.line,
+      recursive: recursion !== null,
+      recursion,
+      self_fields,
+      group,
+      group_fields
+    });
+  }
+
+  return results.sort(function sort_by_id(a, b) {
+    return a.id < b.id ? -1 : 1;
+  });
+};
+
+/**
+ * Report the recursive structs of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_recursive_structs)
+ * @returns {Promise<Object>} Structs with a summary
+ */
+const analyze_project_recursive_structs = async (project_id, options = {}) => {
+  const all = find_recursive_structs(await load_go_packages(project_id));
+  const structs = options.recursive_only
+    ? all.filter((s) => s.recursive)
+    : all;
+
+  return {
+    structs,
+    summary: {
+      total_structs: all.length,
+      recursive: all.filter((s) => s.recursive).length,
+      direct: all.filter((s) => s.recursion === 'direct').length,
+      mutual: all.filter((s) => s.recursion === 'mutual').length
+    }
+  };
+};
+
+export {
+  analyze_project_recursive_structs,
+  find_recursive_structs,
+  RECURSION_KINDS,
+  analyze_project_options_structs,
+  find_options_structs,
+  OPTIONS_STRUCT_PATTERN,
+  DEFAULT_MIN_OPTION_FIELDS,
+  analyze_project_field_types,
+  find_field_types,
+  normalize_field_type,
+  FIELD_TYPE_RESOLUTIONS,
+  analyze_project_copy_locks,
+  find_copy_locks,
+  find_contained_lock,
+  NO_COPY_TYPES,
+  analyze_project_type_complexity,
+  find_type_complexity,
+  compute_type_complexity,
+  DEFAULT_TOP_TYPES,
+  analyze_project_struct_sizes,
+  analyze_project_struct_kinds,
+  analyze_project_method_shadowing,
+  find_method_shadowing,
+  SHADOW_KINDS,
+  analyze_project_struct_tags,
+  validate_struct_tags,
+  extract_tag_schema,
+  parse_struct_tag,
+  TAG_RULES,
+  DEFAULT_TAG_POLICY,
+  analyze_project_embedders,
+  find_source_embedders,
+  find_embedders,
+  summarize_embedders,
+  classify_structs,
+  classify_struct_kind,
+  classify_method_role,
+  STRUCT_KINDS,
+  analyze_project_field_init,
+  find_field_initializations,
+  find_struct_literals,
+  get_typical_init,
+  compute_type_layout,
+  compute_fields_layout,
+  compute_struct_layouts,
+  build_layout_context,
+  find_large_value_params,
+  align_to,
+  DEFAULT_VALUE_PARAM_THRESHOLD,
+  TYPICAL_INIT_KINDS,
+  BASIC_TYPE_LAYOUTS,
+  KNOWN_TYPE_LAYOUTS
+};
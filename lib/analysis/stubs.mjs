@@ -0,0 +1,313 @@
+'use strict';
+
+/**
+ * @fileoverview Go interface stub generation module.
+ * Generates a stub type implementing a Go interface, with one method per
+ * interface method returning zero values.  Parameter and result names are
+ * kept from the interface so the stub reads like the original
+ * (`Read(p []byte) (n int, err error)`); names are only synthesized
+ * (`arg0`, `arg1`, ...) when the interface omits them.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/stubs
+ */
+
+import {
+  parse_parameters,
+  get_base_type,
+  load_go_packages
+} from './golang.mjs';
+
+/**
+ * Zero values of Go's predeclared types.
+ */
+const BASIC_ZERO_VALUES = {
+  bool: 'false',
+  string: '""',
+  error: 'nil',
+  any: 'nil',
+  uintptr: '0',
+  byte: '0',
+  rune: '0'
+};
+
+/**
+ * Predeclared numeric types.
+ */
+const NUMERIC_TYPE = /^(u?int(8|16|32|64)?|float(32|64)|complex(64|128))$/;
+
+/**
+ * Pointer, slice, map, function, channel and interface types, whose zero
+ * value is nil.
+ */
+const NIL_TYPE =
+  /^(\*|\[\s*\]|map\s*\[|func\s*\(|(<-\s*)?chan\b|interface\s*\{)/;
+
+// ============================================================================
+// METHOD SETS
+// ============================================================================
+
+/**
+ * Collect the methods of an interface, including those of the interfaces
+ * it embeds from the same package.  Embedded interfaces from other
+ * packages cannot be resolved and are returned separately.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Object} iface - Interface type declaration
+ * @returns {Object} Methods and the embeds that could not be resolved
+ */
+const collect_interface_methods = (pkg, iface) => {
+  const interfaces = new Map(
+    pkg.types.filter((t) => t.kind === 'interface').map((t) => [t.name, t])
+  );
+  const methods = [];
+  const seen = new Set();
+  const unresolved = [];
+  const visited = new Set();
+
+  const visit = (type) => {
+    if (visited.has(type.name)) return;
+    visited.add(type.name);
+    for (const method of type.methods) {
+      if (seen.has(method.name)) continue;
+      seen.add(method.name);
+      methods.push(method);
+    }
+    for (const embed of type.embeds) {
+      const embedded = interfaces.get(embed.type);
+      if (embedded) {
+        visit(embedded);
+      } else {
+        unresolved.push(embed.type);
+      }
+    }
+  };
+  visit(iface);
+
+  return { methods, unresolved };
+};
+
+// ============================================================================
+// RENDERING
+// ============================================================================
+
+/**
+ * Get the zero value of a type, as a Go expression.  Local named types
+ * are resolved through the package; other named types use `*new(T)`,
+ * which is valid for any type.
+ * @param {string} type_text - Type expression
+ * @param {Map<string, Object>} types - Package types by name
+ * @param {Set<string>} [seen] - Types already resolved, to stop on cycles
+ * @returns {string} Zero value expression
+ */
+const get_zero_value = (type_text, types, seen = new Set()) => {
+  const text = (type_text || '').trim();
+  if (BASIC_ZERO_VALUES[text]) return BASIC_ZERO_VALUES[text];
+  if (NUMERIC_TYPE.test(text)) return '0';
+  if (NIL_TYPE.test(text)) return 'nil';
+  if (/^(\[|struct\s*\{)/.test(text)) return `${text}{}`;
+
+  const type = types.get(text);
+  if (type && !seen.has(text)) {
+    seen.add(text);
+    if (type.kind === 'interface') return 'nil';
+    if (type.kind === 'struct') return `${text}{}`;
+    if (type.kind === 'alias') {
+      return get_zero_value(type.underlying, types, seen);
+    }
+
+    // Convert to keep the named type: Count(0), Names(nil)
+    const zero = get_zero_value(type.underlying, types, seen);
+    if (zero.endsWith('{}')) return `${text}{}`;
+    if (!zero.startsWith('*new(')) return `${text}(${zero})`;
+  }
+  return `*new(${text})`;
+};
+
+/**
+ * Name the parameters of a method, keeping the interface's names and
+ * synthesizing `argN` for unnamed ones.  Synthesized names never clash
+ * with the names that are kept or with the receiver.
+ * @param {Object[]} params - Parameters (from the Go parser)
+ * @param {string} prefix - Prefix of synthesized names (arg or res)
+ * @param {Set<string>} taken - Names already in use, updated in place
+ * @returns {Object[]} Parameters with a name and whether it was synthesized
+ */
+const name_stub_params = (params, prefix, taken) => {
+  for (const param of params) {
+    if (param.name && param.name !== '_') taken.add(param.name);
+  }
+
+  let next = 0;
+  return params.map(function name_param(param) {
+    if (param.name) return { ...param, synthesized: false };
+    let name = `${prefix}${next++}`;
+    while (taken.has(name)) name = `${prefix}${next++}`;
+    taken.add(name);
+    return { ...param, name, synthesized: true };
+  });
+};
+
+/**
+ * Format a named parameter list.
+ * @param {Object[]} params - Named parameters
+ * @returns {string} Parameter list without parentheses
+ */
+const format_stub_params = (params) => {
+  return params
+    .map((p) => `${p.name} ${p.variadic ? '...' : ''}${p.type}`)
+    .join(', ');
+};
+
+/**
+ * Build the stub of one interface method.  Named results are kept and
+ * returned with a bare return; unnamed results are returned as zero
+ * values.
+ * @param {Object} method - Interface method (from the Go parser)
+ * @param {string} receiver - Receiver name
+ * @param {Map<string, Object>} types - Package types by name
+ * @returns {Object} Method with named params, results and the return statement
+ */
+const build_stub_method = (method, receiver, types) => {
+  const taken = new Set([receiver]);
+  const named_results = method.results.some((r) => r.name);
+  const params = name_stub_params(method.params, 'arg', taken);
+  const results = named_results
+    ? name_stub_params(method.results, 'res', taken)
+    : method.results;
+
+  let body = null;
+  if (named_results) {
+    body = 'return';
+  } else if (results.length > 0) {
+    const values = results.map((r) => get_zero_value(r.type, types));
+    body = `return ${values.join(', ')}`;
+  }
+
+  return { name: method.name, params, results, named_results, body };
+};
+
+/**
+ * Render a stub as Go source.
+ * @param {Object} stub - Stub (from generate_interface_stub)
+ * @returns {string} Go source of the stub type and its methods
+ */
+const render_go_stub = (stub) => {
+  const type_params = stub.type_params ? `[${stub.type_params}]` : '';
+  const type_args = stub.type_params
+    ? `[${parse_parameters(stub.type_params)
+        .map((p) => p.name || p.type)
+        .join(', ')}]`
+    : '';
+  const out = [
+    `// ${stub.type_name} is a stub implementation of ${stub.interface}.`,
+    `type ${stub.type_name}${type_params} struct{}`
+  ];
+
+  for (const method of stub.methods) {
+    let results = '';
+    if (method.named_results) {
+      results = ` (${format_stub_params(method.results)})`;
+    } else if (method.results.length === 1) {
+      results = ` ${method.results[0].type}`;
+    } else if (method.results.length > 1) {
+      results = ` (${method.results.map((r) => r.type).join(', ')})`;
+    }
+
+    out.push('');
+    out.push(`// ${method.name} implements ${stub.interface}.`);
+    const receiver = `${stub.receiver} *${stub.type_name}${type_args}`;
+    const params = format_stub_params(method.params);
+    out.push(`func (${receiver}) ${method.name}(${params})${results} {`);
+    if (method.body) out.push(`\t${method.body}`);
+    out.push('}');
+  }
+
+  for (const embed of stub.unresolved_embeds) {
+    out.push('');
+    out.push(`// TODO: implement the methods of ${embed}.`);
+  }
+
+  return out.join('\n') + '\n';
+};
+
+/**
+ * Generate a stub implementing an interface of a package.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {string} interface_name - Interface name
+ * @param {Object} [options] - Options
+ * @param {string} [options.type_name] - Stub type name (default Stub<Interface>)
+ * @returns {Object|null} Stub with methods and Go source, or null if the interface is not found
+ */
+const generate_interface_stub = (pkg, interface_name, options = {}) => {
+  const iface = pkg.types.find(
+    (t) => t.name === interface_name && t.kind === 'interface'
+  );
+  if (!iface) return null;
+
+  const types = new Map(pkg.types.map((t) => [t.name, t]));
+  const type_name = options.type_name || `Stub${interface_name}`;
+  const { methods, unresolved } = collect_interface_methods(pkg, iface);
+
+  // The receiver must not shadow a parameter kept from the interface
+  const names = new Set(
+    methods.flatMap((m) => [...m.params, ...m.results].map((p) => p.name))
+  );
+  let receiver = type_name[0].toLowerCase();
+  for (let i = 0; names.has(receiver); i++) receiver = `stub${i || ''}`;
+
+  const stub = {
+    interface: interface_name,
+    package: pkg.name,
+    directory: pkg.directory,
+    filename: iface.filename,
+    line: iface.line,
+    type_name,
+    type_params: iface.type_params,
+    receiver,
+    methods: methods.map((m) => build_stub_method(m, receiver, types)),
+    unresolved_embeds: unresolved
+  };
+  stub.source = render_go_stub(stub);
+  return stub;
+};
+
+/**
+ * Generate a stub implementing an interface of a project.
+ * @param {number} project_id - The project ID
+ * @param {string} interface_name - Interface name, optionally qualified by package directory (`store.Reader`) or name
+ * @param {Object} [options] - Options (see generate_interface_stub)
+ * @returns {Promise<Object>} Stub with methods and Go source
+ * @throws {Error} If the interface is not found or is ambiguous
+ */
+const generate_stub = async (project_id, interface_name, options = {}) => {
+  const dot = interface_name.lastIndexOf('.');
+  const qualifier = dot === -1 ? null : interface_name.substring(0, dot);
+  const name = get_base_type(interface_name.substring(dot + 1));
+
+  const stubs = (await load_go_packages(project_id))
+    .filter(
+      (pkg) =>
+        !qualifier || pkg.name === qualifier || pkg.directory === qualifier
+    )
+    .map((pkg) => generate_interface_stub(pkg, name, options))
+    .filter(Boolean);
+
+  if (stubs.length === 0) {
+    throw new Error(`Interface '${interface_name}' not found in project`);
+  }
+  if (stubs.length > 1) {
+    throw new Error(
+      `Interface '${interface_name}' is ambiguous (found in: ${stubs.map((s) => s.directory).join(', ')})`
+    );
+  }
+  return stubs[0];
+};
+
+export {
+  generate_stub,
+  generate_interface_stub,
+  render_go_stub,
+  build_stub_method,
+  name_stub_params,
+  collect_interface_methods,
+  get_zero_value
+};
@@ -0,0 +1,237 @@
+'use strict';
+
+/**
+ * @fileoverview Go public surface area module.
+ * Summarizes the size of the public API of a code base as a single
+ * number, the surface area: the exported symbols of its API catalog (from
+ * `cb catalog`), weighted by the complexity of their signatures.  Stored
+ * as a baseline and compared run after run, it tracks API growth over
+ * commits, and alerts when the API grows faster than a threshold.
+ *
+ * Each catalog entry weighs 1, plus:
+ * - functions and methods (interface methods included): 1 per parameter,
+ *   1 per result and 1 per type parameter
+ * - types: 1 per type parameter; their exported fields and methods are
+ *   entries of their own
+ * Constants, variables and fields weigh 1.  A variadic parameter counts
+ * as one parameter, and grouped names (`a, b int`) as one per name.
+ *
+ * The surface area is deterministic: it only depends on the declarations
+ * of the catalog, not on their order, docs or positions.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/surface
+ */
+
+import { parse_go_file, split_top_level, load_go_sources } from './golang.mjs';
+import { build_source_catalog } from './catalog.mjs';
+import { list_catalog_entries } from './changelog.mjs';
+
+/**
+ * Version of the surface area baseline format.
+ */
+const SURFACE_AREA_VERSION = 1;
+
+/**
+ * Default growth of the surface area, in percent, over which it alerts.
+ */
+const DEFAULT_MAX_GROWTH = 10;
+
+// ============================================================================
+// WEIGHTS
+// ============================================================================
+
+/**
+ * Count the type parameters of a type parameter list (`K comparable, V
+ * any` has 2).
+ * @param {string|null} type_params - Type parameter list
+ * @returns {number} Number of type parameters
+ */
+const count_type_params = (type_params) => {
+  if (!type_params) return 0;
+  return split_top_level(type_params).filter((p) => p.trim()).length;
+};
+
+/**
+ * Parse the signature of a catalog entry for its weight: the parameters,
+ * results and type parameters of functions and methods, and the type
+ * parameters of types.
+ * @param {Object} entry - Catalog entry
+ * @returns {Object} Numbers of params, results and type_params
+ */
+const parse_entry_signature = (entry) => {
+  const counts = { params: 0, results: 0, type_params: 0 };
+  const signature = entry.signature || '';
+
+  if (entry.kind === 'function' || entry.kind === 'method') {
+    // Interface methods have no func keyword
+    const file = /^func\b/.test(signature)
+      ? parse_go_file(`package p\n\n${signature} {}\n`)
+      : parse_go_file(`package p\n\ntype _ interface {\n${signature}\n}\n`);
+    const fn = file.functions[0] || (file.types[0] && file.types[0].methods[0]);
+    if (!fn) return counts;
+    counts.params = fn.params.length;
+    counts.results = fn.results.length;
+    counts.type_params = count_type_params(fn.type_params);
+  } else if (/^type\b/.test(signature)) {
+    const body = /\b(struct|interface)$/.test(signature) ? ' {}' : '';
+    const type = parse_go_file(`package p\n\n${signature}${body}\n`).types[0];
+    if (type) counts.type_params = count_type_params(type.type_params);
+  }
+
+  return counts;
+};
+
+/**
+ * Get the weight of a catalog entry in the surface area.
+ * @param {Object} entry - Catalog entry
+ * @returns {number} Weight
+ */
+const get_entry_weight = (entry) => {
+  const { params, results, type_params } = parse_entry_signature(entry);
+  return 1 + params + results + type_params;
+};
+
+// ============================================================================
+// SURFACE AREA
+// ============================================================================
+
+/**
+ * Compute the surface area of an API catalog: the sum of the weights of
+ * its entries.
+ * @param {Object} catalog - API catalog (from build_api_catalog)
+ * @returns {number} Surface area
+ * @throws {Error} If the catalog has another schema version
+ */
+const compute_surface_area = (catalog) => {
+  let surface_area = 0;
+  for (const entry of list_catalog_entries(catalog).values()) {
+    surface_area += get_entry_weight(entry);
+  }
+  return surface_area;
+};
+
+/**
+ * Summarize the surface area of an API catalog by kind and by package.
+ * The result is the baseline stored to compare later surface areas with.
+ * @param {Object} catalog - API catalog (from build_api_catalog)
+ * @returns {Object} Surface area with version, counts and weights by kind, and weights by package directory
+ * @throws {Error} If the catalog has another schema version
+ */
+const summarize_surface_area = (catalog) => {
+  const by_kind = {};
+  const packages = {};
+  let surface_area = 0;
+
+  for (const entry of list_catalog_entries(catalog).values()) {
+    const weight = get_entry_weight(entry);
+    if (!by_kind[entry.kind]) by_kind[entry.kind] = { count: 0, weight: 0 };
+    by_kind[entry.kind].count++;
+    by_kind[entry.kind].weight += weight;
+    const directory = entry.package.directory;
+    packages[directory] = (packages[directory] || 0) + weight;
+    surface_area += weight;
+  }
+
+  const sorted = (object) =>
+    Object.fromEntries(
+      Object.keys(object)
+        .sort()
+        .map((key) => [key, object[key]])
+    );
+  return {
+    version: SURFACE_AREA_VERSION,
+    surface_area,
+    by_kind: sorted(by_kind),
+    packages: sorted(packages)
+  };
+};
+
+/**
+ * Compare a surface area with a baseline: its change, in points and in
+ * percent, and the packages whose surface area changed, most grown first.
+ * It alerts when the growth is over the maximum growth.
+ * @param {Object} current - Current surface area (from summarize_surface_area)
+ * @param {Object} baseline - Baseline surface area (from summarize_surface_area)
+ * @param {Object} [options] - Options
+ * @param {number} [options.max_growth=10] - Growth in percent over which to alert
+ * @returns {Object} Delta, growth, alert flag and changed packages
+ * @throws {Error} If the baseline has another format version
+ */
+const compare_surface_area = (current, baseline, options = {}) => {
+  if (!baseline || baseline.version !== SURFACE_AREA_VERSION) {
+    throw new Error(
+      `Unsupported surface area baseline version: ${baseline && baseline.version}`
+    );
+  }
+  const max_growth = options.max_growth ?? DEFAULT_MAX_GROWTH;
+
+  const delta = current.surface_area - baseline.surface_area;
+  let growth = current.surface_area > 0 ? 100 : 0;
+  if (baseline.surface_area > 0) {
+    growth = Math.round((delta / baseline.surface_area) * 1000) / 10;
+  }
+
+  const before = baseline.packages || {};
+  const after = current.packages;
+  const packages = [...new Set([...Object.keys(before), ...Object.keys(after)])]
+    .map((directory) => ({
+      directory,
+      before: before[directory] || 0,
+      after: after[directory] || 0,
+      delta: (after[directory] || 0) - (before[directory] || 0)
+    }))
+    .filter((p) => p.delta !== 0)
+    .sort(function sort_by_delta(a, b) {
+      return b.delta - a.delta || a.directory.localeCompare(b.directory);
+    });
+
+  return {
+    surface_area: current.surface_area,
+    baseline_surface_area: baseline.surface_area,
+    delta,
+    growth,
+    max_growth,
+    alert: growth > max_growth,
+    packages
+  };
+};
+
+/**
+ * Summarize the surface area of a set of Go sources, such as the files of
+ * a directory (see build_source_catalog for the files left out).
+ * @param {Object[]} sources - Files with filename and source
+ * @returns {Object} Surface area (see summarize_surface_area)
+ */
+const find_source_surface_area = (sources) => {
+  return summarize_surface_area(
+    build_source_catalog(sources, { generated_at: '' })
+  );
+};
+
+/**
+ * Summarize the surface area of a project, compared with a baseline when
+ * one is given.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see compare_surface_area)
+ * @param {Object} [options.baseline] - Baseline surface area to compare with
+ * @returns {Promise<Object>} Surface area, with the comparison to the baseline
+ */
+const analyze_project_surface_area = async (project_id, options = {}) => {
+  const surface = find_source_surface_area(await load_go_sources(project_id));
+  if (!options.baseline) return { ...surface, comparison: null };
+  return {
+    ...surface,
+    comparison: compare_surface_area(surface, options.baseline, options)
+  };
+};
+
+export {
+  analyze_project_surface_area,
+  find_source_surface_area,
+  summarize_surface_area,
+  compare_surface_area,
+  compute_surface_area,
+  get_entry_weight,
+  SURFACE_AREA_VERSION,
+  DEFAULT_MAX_GROWTH
+};
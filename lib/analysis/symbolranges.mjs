@@ -0,0 +1,444 @@
+'use strict';
+
+/**
+ * @fileoverview Go symbol range module.
+ * Emits a symbol range file: the byte ranges of the declarations of each
+ * Go file with their kind, for editor integrations built on tree-sitter,
+ * whose highlighters and outliners address source by UTF-8 byte offsets
+ * and name nodes by grammar node type.  The ranges follow the nodes of
+ * tree-sitter-go, so that a tool can match them against its own tree.
+ *
+ * Format (version 1), a JSON document:
+ *
+ *   {
+ *     "format": "codebuddy-symbol-ranges",
+ *     "version": 1,
+ *     "encoding": "utf-8",
+ *     "files": [{
+ *       "path": "store/store.go",
+ *       "byte_length": 1234,
+ *       "symbols": [{
+ *         "id": 0,
+ *         "parent": null,
+ *         "name": "Store",
+ *         "kind": "struct",
+ *         "node_type": "type_spec",
+ *         "capture": "definition.class",
+ *         "start_byte": 120, "end_byte": 180,
+ *         "name_start_byte": 120, "name_end_byte": 125,
+ *         "start_point": { "row": 7, "column": 5 },
+ *         "end_point": { "row": 10, "column": 1 }
+ *       }]
+ *     }]
+ *   }
+ *
+ * - start_byte is inclusive and end_byte exclusive, as in tree-sitter;
+ *   points are zero-based rows and byte columns (TSPoint)
+ * - the name range covers the identifier (the `name` field of the node);
+ *   embedded fields have the range of their type, without `*`, and
+ *   imports that of their quoted path, named after the path
+ * - parent is the id of the enclosing symbol within the file (fields in
+ *   their struct, method elements in their interface), or null; symbols
+ *   are ordered by start byte, parents before their children
+ * - kind is one of SYMBOL_KINDS; node_type is the tree-sitter-go node; the
+ *   capture is the tags.scm capture name outliners use
+ * Methods are top-level in Go and tree-sitter alike: they are not
+ * children of their type, and carry the type name as receiver.  Package
+ * clauses and import specs are included, comments are not.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/symbolranges
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  find_matching,
+  parse_go_file,
+  load_go_sources
+} from './golang.mjs';
+
+/**
+ * Identifier of the symbol range format.
+ */
+const SYMBOL_RANGE_FORMAT = 'codebuddy-symbol-ranges';
+
+/**
+ * Version of the symbol range format.
+ */
+const SYMBOL_RANGE_VERSION = 1;
+
+/**
+ * Symbol kinds with their tree-sitter-go node type and tags capture.
+ */
+const SYMBOL_KINDS = new Map([
+  ['package', { node_type: 'package_clause', capture: 'definition.module' }],
+  ['import', { node_type: 'import_spec', capture: 'reference.module' }],
+  [
+    'function',
+    { node_type: 'function_declaration', capture: 'definition.function' }
+  ],
+  ['method', { node_type: 'method_declaration', capture: 'definition.method' }],
+  ['struct', { node_type: 'type_spec', capture: 'definition.class' }],
+  ['interface', { node_type: 'type_spec', capture: 'definition.interface' }],
+  ['type', { node_type: 'type_spec', capture: 'definition.type' }],
+  ['alias', { node_type: 'type_alias', capture: 'definition.type' }],
+  ['field', { node_type: 'field_declaration', capture: 'definition.field' }],
+  [
+    'interface_method',
+    { node_type: 'method_elem', capture: 'definition.method' }
+  ],
+  ['constant', { node_type: 'const_spec', capture: 'definition.constant' }],
+  ['variable', { node_type: 'var_spec', capture: 'definition.variable' }]
+]);
+
+// ============================================================================
+// OFFSETS
+// ============================================================================
+
+/**
+ * Build a converter from character offsets of a source to UTF-8 byte
+ * offsets and tree-sitter points.
+ * @param {string} source - Source text
+ * @returns {Object} Converter with bytes(offset), point(offset) and byte_length
+ */
+const build_byte_index = (source) => {
+  const line_index = build_line_index(source);
+  const line_bytes = [];
+  let bytes = 0;
+  for (let i = 0; i < line_index.length; i++) {
+    line_bytes.push(bytes);
+    const end = i + 1 < line_index.length ? line_index[i + 1] : source.length;
+    bytes += Buffer.byteLength(source.substring(line_index[i], end), 'utf8');
+  }
+
+  const row_at = (offset) => {
+    let low = 0;
+    let high = line_index.length - 1;
+    while (low < high) {
+      const mid = (low + high + 1) >> 1;
+      if (line_index[mid] <= offset) low = mid;
+      else high = mid - 1;
+    }
+    return low;
+  };
+  const column_at = (row, offset) =>
+    Buffer.byteLength(source.substring(line_index[row], offset), 'utf8');
+
+  return {
+    line_index,
+    byte_length: bytes,
+    bytes: (offset) => {
+      const row = row_at(offset);
+      return line_bytes[row] + column_at(row, offset);
+    },
+    point: (offset) => {
+      const row = row_at(offset);
+      return { row, column: column_at(row, offset) };
+    }
+  };
+};
+
+/**
+ * Find the end of the node starting at an offset of masked source: the
+ * first newline or `;` outside brackets, or the bracket closing the
+ * enclosing block.  Trailing blanks are left out.
+ * @param {string} masked - Masked source
+ * @param {number} start - Offset of the node
+ * @returns {number} Offset after the node
+ */
+const find_node_end = (masked, start) => {
+  let pos = start;
+  while (pos < masked.length) {
+    const ch = masked[pos];
+    if (ch === '(' || ch === '[' || ch === '{') {
+      const close = find_matching(masked, pos);
+      if (close === -1) return masked.length;
+      pos = close + 1;
+      continue;
+    }
+    if (ch === '\n' || ch === ';' || ch === ')' || ch === ']' || ch === '}') {
+      break;
+    }
+    pos++;
+  }
+  while (pos > start && /\s/.test(masked[pos - 1])) pos--;
+  return pos;
+};
+
+/**
+ * Find an identifier on a line of masked source, from an offset.
+ * @param {string} masked - Masked source
+ * @param {number[]} line_index - Line index of the source
+ * @param {number} line - 1-based line
+ * @param {string} name - Identifier, or a type expression for embedded fields
+ * @param {number} [from=0] - Offset to search from
+ * @returns {number} Offset of the identifier, or -1
+ */
+const find_on_line = (masked, line_index, line, name, from = 0) => {
+  const line_start = line_index[line - 1];
+  const line_end =
+    line < line_index.length ? line_index[line] - 1 : masked.length;
+  const start = Math.max(line_start, from);
+  const escaped = name.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+  const pattern = new RegExp(`(?<![\\w.])${escaped}(?!\\w)`, 'g');
+  pattern.lastIndex = start;
+  const match = pattern.exec(masked);
+  return match && match.index < line_end ? match.index : -1;
+};
+
+// ============================================================================
+// SYMBOL RANGES
+// ============================================================================
+
+/**
+ * Find the symbol ranges of a Go file (see the module doc for the fields
+ * of a symbol).
+ * @param {string} source - Go source text
+ * @param {string} [filename=''] - Filename
+ * @returns {Object} File with path, byte_length and symbols
+ */
+const find_file_symbol_ranges = (source, filename = '') => {
+  const file = parse_go_file(source, filename);
+  const masked = mask_source(source);
+  const index = build_byte_index(source);
+  const { line_index } = index;
+  const pending = [];
+
+  const add = (kind, name, start, end, name_start, fields = {}) => {
+    if (start === -1 || name_start === -1) return null;
+    const symbol = {
+      kind,
+      name,
+      start,
+      end,
+      name_start,
+      name_end: name_start + (fields.name_length ?? name.length),
+      parent: fields.parent ?? null,
+      receiver: fields.receiver ?? null
+    };
+    pending.push(symbol);
+    return symbol;
+  };
+
+  const package_start = masked.search(/\bpackage\s/);
+  if (package_start !== -1 && file.package) {
+    const name_start = masked.indexOf(file.package, package_start + 7);
+    add(
+      'package',
+      file.package,
+      package_start,
+      name_start + file.package.length,
+      name_start
+    );
+  }
+
+  for (const imp of file.imports) {
+    const start = line_index[imp.line - 1] + imp.column - 1;
+    const end = line_index[imp.end_line - 1] + imp.end_column - 1;
+    const path_start = masked.indexOf('"', start);
+    add('import', imp.path, start, end, path_start, {
+      name_length: imp.path.length + 2
+    });
+  }
+
+  for (const fn of file.functions) {
+    const start = find_on_line(masked, line_index, fn.line, 'func');
+    if (start === -1 || !fn.name) continue;
+    let after = start + 4;
+    if (fn.receiver_text !== null) {
+      after = find_matching(masked, masked.indexOf('(', after)) + 1;
+    }
+    const name_start = masked.indexOf(fn.name, after);
+    add(
+      fn.receiver ? 'method' : 'function',
+      fn.name,
+      start,
+      find_node_end(masked, start),
+      name_start,
+      { receiver: fn.receiver ? fn.receiver.type : null }
+    );
+  }
+
+  for (const type of file.types) {
+    const start = find_on_line(masked, line_index, type.line, type.name);
+    if (start === -1) continue;
+    const kind =
+      ['struct', 'interface', 'alias'].includes(type.kind) ? type.kind : 'type';
+    const end = find_node_end(masked, start);
+    const symbol = add(kind, type.name, start, end, start);
+    let cursor = start + type.name.length;
+
+    for (const field of type.fields) {
+      const name = field.embedded
+        ? field.type.replace(/^\*/, '')
+        : field.names[0];
+      const name_start = find_on_line(
+        masked,
+        line_index,
+        field.line,
+        name,
+        cursor
+      );
+      if (name_start === -1) continue;
+      const field_start =
+        field.embedded && masked[name_start - 1] === '*'
+          ? name_start - 1
+          : name_start;
+      const end = find_node_end(masked, field_start);
+      for (const field_name of field.embedded ? [name] : field.names) {
+        const at = field.embedded
+          ? name_start
+          : find_on_line(masked, line_index, field.line, field_name, cursor);
+        add('field', field_name, field_start, end, at, { parent: symbol });
+      }
+      cursor = end;
+    }
+
+    for (const method of type.methods) {
+      const name_start = find_on_line(
+        masked,
+        line_index,
+        method.line,
+        method.name,
+        cursor
+      );
+      if (name_start === -1) continue;
+      const end = find_node_end(masked, name_start);
+      add('interface_method', method.name, name_start, end, name_start, {
+        parent: symbol
+      });
+      cursor = end;
+    }
+  }
+
+  for (const [kind, decls] of [
+    ['constant', file.consts],
+    ['variable', file.vars]
+  ]) {
+    for (const decl of decls) {
+      let cursor = 0;
+      const start = find_on_line(masked, line_index, decl.line, decl.names[0]);
+      if (start === -1) continue;
+      const end = find_node_end(masked, start);
+      for (const name of decl.names) {
+        const name_start = find_on_line(
+          masked,
+          line_index,
+          decl.line,
+          name,
+          Math.max(start, cursor)
+        );
+        add(kind, name, start, end, name_start);
+        cursor = name_start + name.length;
+      }
+    }
+  }
+
+  pending.sort(function sort_by_range(a, b) {
+    return a.start - b.start || b.end - a.end || a.name_start - b.name_start;
+  });
+  const ids = new Map(pending.map((symbol, id) => [symbol, id]));
+  const symbols = pending.map(function format_symbol(symbol, id) {
+    const { node_type, capture } = SYMBOL_KINDS.get(symbol.kind);
+    return {
+      id,
+      parent: symbol.parent ? ids.get(symbol.parent) : null,
+      name: symbol.name,
+      kind: symbol.kind,
+      node_type,
+      capture,
+      start_byte: index.bytes(symbol.start),
+      end_byte: index.bytes(symbol.end),
+      name_start_byte: index.bytes(symbol.name_start),
+      name_end_byte: index.bytes(symbol.name_end),
+      start_point: index.point(symbol.start),
+      end_point: index.point(symbol.end),
+      ...(symbol.receiver ? { receiver: symbol.receiver } : {})
+    };
+  });
+
+  return { path: filename, byte_length: index.byte_length, symbols };
+};
+
+/**
+ * Build the symbol range document of a set of Go sources.
+ * @param {Object[]} sources - Files with filename and source
+ * @returns {Object} Symbol range document (see the module doc)
+ */
+const build_symbol_ranges = (sources) => {
+  return {
+    format: SYMBOL_RANGE_FORMAT,
+    version: SYMBOL_RANGE_VERSION,
+    encoding: 'utf-8',
+    files: sources.map((file) =>
+      find_file_symbol_ranges(file.source, file.filename)
+    )
+  };
+};
+
+/**
+ * Read a symbol range document, checking its format: the ranges must lie
+ * within their file and within their parent.
+ * @param {string|Object} input - Document, as JSON text or parsed
+ * @returns {Object} Document
+ * @throws {Error} If the document is not a valid symbol range document
+ */
+const parse_symbol_ranges = (input) => {
+  const document = typeof input === 'string' ? JSON.parse(input) : input;
+  if (!document || document.format !== SYMBOL_RANGE_FORMAT) {
+    throw new Error(`Not a ${SYMBOL_RANGE_FORMAT} document`);
+  }
+  if (document.version !== SYMBOL_RANGE_VERSION) {
+    throw new Error(`Unsupported symbol range version '${document.version}'`);
+  }
+
+  for (const file of document.files || []) {
+    file.symbols.forEach((symbol, id) => {
+      const where = `${file.path} symbol ${id} (${symbol.name})`;
+      if (symbol.id !== id) throw new Error(`${where}: id out of order`);
+      if (!SYMBOL_KINDS.has(symbol.kind)) {
+        throw new Error(`${where}: unknown kind '${symbol.kind}'`);
+      }
+      const ranges = [
+        [symbol.start_byte, symbol.end_byte],
+        [symbol.name_start_byte, symbol.name_end_byte]
+      ];
+      for (const [start, end] of ranges) {
+        if (!(start >= 0 && start <= end && end <= file.byte_length)) {
+          throw new Error(`${where}: range out of the file`);
+        }
+      }
+      if (symbol.parent === null) return;
+      const parent = file.symbols[symbol.parent];
+      if (
+        !parent ||
+        symbol.parent >= id ||
+        symbol.start_byte < parent.start_byte ||
+        symbol.end_byte > parent.end_byte
+      ) {
+        throw new Error(`${where}: not within its parent`);
+      }
+    });
+  }
+
+  return document;
+};
+
+/**
+ * Build the symbol range document of a project.
+ * @param {number} project_id - The project ID
+ * @returns {Promise<Object>} Symbol range document
+ */
+const analyze_project_symbol_ranges = async (project_id) => {
+  return build_symbol_ranges(await load_go_sources(project_id));
+};
+
+export {
+  analyze_project_symbol_ranges,
+  build_symbol_ranges,
+  find_file_symbol_ranges,
+  parse_symbol_ranges,
+  SYMBOL_KINDS,
+  SYMBOL_RANGE_FORMAT,
+  SYMBOL_RANGE_VERSION
+};
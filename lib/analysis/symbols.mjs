@@ -0,0 +1,324 @@
+'use strict';
+
+/**
+ * @fileoverview Go workspace symbol module.
+ * Lists the symbols of every package of a project in the shape of the LSP
+ * `workspace/symbol` response (name, kind, containerName and location),
+ * filtered with the fuzzy matching editors expect.  This is enough to back
+ * a "go to symbol in workspace" feature without running a language server.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/symbols
+ */
+
+import { posix } from 'path';
+import { pathToFileURL } from 'url';
+import { query } from '../db.mjs';
+import {
+  parse_go_file,
+  group_go_packages,
+  get_base_type,
+  load_go_sources
+} from './golang.mjs';
+
+/**
+ * Default maximum number of symbols returned, as in gopls.
+ */
+const DEFAULT_SYMBOL_LIMIT = 100;
+
+/**
+ * LSP SymbolKind values of Go declarations.
+ */
+const SYMBOL_KINDS = {
+  function: 12,
+  method: 6,
+  struct: 23,
+  interface: 11,
+  type: 5,
+  field: 8,
+  constant: 14,
+  variable: 13
+};
+
+// ============================================================================
+// FUZZY MATCHING
+// ============================================================================
+
+/**
+ * Check whether a character of a name starts a word: the first character,
+ * an upper case letter after a lower case one, or a character after `_`
+ * or `.`.
+ * @param {string} name - Symbol name
+ * @param {number} index - Character index
+ * @returns {boolean} True at word boundaries
+ */
+const is_word_start = (name, index) => {
+  if (index === 0) return true;
+  const previous = name[index - 1];
+  if (previous === '_' || previous === '.') return true;
+  return /[A-Z]/.test(name[index]) && /[a-z0-9]/.test(previous);
+};
+
+/**
+ * Score the positions a query matches in a name, taking each character at
+ * its next occurrence or, when asked, at a later word start.
+ * @param {string} name - Symbol name
+ * @param {string} pattern - Lower case query
+ * @param {boolean} prefer_word_starts - Jump ahead to word starts
+ * @returns {number|null} Score, or null if the positions do not match
+ */
+const score_positions = (name, pattern, prefer_word_starts) => {
+  const lower = name.toLowerCase();
+  let score = 0;
+  let previous = -2;
+
+  for (const ch of pattern) {
+    let found = lower.indexOf(ch, previous + 1);
+    if (prefer_word_starts && found !== previous + 1) {
+      for (let i = found; i !== -1; i = lower.indexOf(ch, i + 1)) {
+        if (is_word_start(name, i)) {
+          found = i;
+          break;
+        }
+      }
+    }
+    if (found === -1) return null;
+
+    score += 1;
+    if (found === previous + 1) score += 2;
+    if (is_word_start(name, found)) score += 3;
+    previous = found;
+  }
+
+  return score;
+};
+
+/**
+ * Score a fuzzy match of a query against a symbol name.
+ * The query matches when its characters appear in the name in order,
+ * ignoring case.  Consecutive characters, word starts, prefixes and
+ * exact matches score higher.  An empty query matches everything.
+ * @param {string} query_text - Query typed by the user
+ * @param {string} name - Symbol name
+ * @returns {number|null} Score, or null if the name does not match
+ */
+const score_fuzzy_match = (query_text, name) => {
+  const pattern = (query_text || '').toLowerCase();
+  if (pattern.length === 0) return 0;
+
+  const greedy = score_positions(name, pattern, false);
+  if (greedy === null) return null;
+  const word_starts = score_positions(name, pattern, true);
+
+  const lower = name.toLowerCase();
+  let score = Math.max(greedy, word_starts || 0);
+  if (lower.startsWith(pattern)) score += 5;
+  if (lower === pattern) score += 10;
+  return score;
+};
+
+// ============================================================================
+// SYMBOLS
+// ============================================================================
+
+/**
+ * Build the LSP range of a symbol's name on its declaration line.
+ * @param {string[]} lines - Source lines (may be empty)
+ * @param {number} line - 1-based declaration line
+ * @param {string} name - Symbol name
+ * @param {number} [from=0] - Column to start searching from
+ * @returns {Object} Range with 0-based start and end positions
+ */
+const get_name_range = (lines, line, name, from = 0) => {
+  const text = lines[line - 1] || '';
+  const pattern = new RegExp(`\\b${name}\\b`, 'g');
+  pattern.lastIndex = from;
+  const match = pattern.exec(text);
+  const character = match ? match.index : 0;
+  return {
+    start: { line: line - 1, character },
+    end: { line: line - 1, character: character + (match ? name.length : 0) }
+  };
+};
+
+/**
+ * Build the URI of a project file.
+ * @param {string} filename - Project relative filename
+ * @param {string} [root] - Absolute project directory
+ * @returns {string} file:// URI, or the filename when the root is unknown
+ */
+const get_file_uri = (filename, root) => {
+  if (!root) return filename;
+  return pathToFileURL(posix.join(root, filename)).href;
+};
+
+/**
+ * List the workspace symbols of a set of Go packages.
+ * Top level symbols use the package name as containerName; methods use
+ * their receiver type, and struct fields and interface methods their type.
+ * @param {Object[]} packages - Packages (from group_go_packages)
+ * @param {Object} [options] - Options
+ * @param {Map<string, string[]>} [options.lines] - Source lines by filename, for name columns
+ * @param {string} [options.root] - Absolute project directory, for file URIs
+ * @returns {Object[]} Workspace symbols
+ */
+const list_workspace_symbols = (packages, options = {}) => {
+  const symbols = [];
+
+  for (const pkg of packages) {
+    const add = (name, kind, container, filename, line, from) => {
+      const lines = (options.lines && options.lines.get(filename)) || [];
+      symbols.push({
+        name,
+        kind: SYMBOL_KINDS[kind],
+        containerName: container,
+        location: {
+          uri: get_file_uri(filename, options.root),
+          range: get_name_range(lines, line, name, from)
+        }
+      });
+    };
+
+    for (const fn of pkg.functions) {
+      if (!fn.name || fn.name === '_') continue;
+      if (fn.receiver) {
+        // Skip the receiver, which may share the method's name
+        const lines = (options.lines && options.lines.get(fn.filename)) || [];
+        const from = (lines[fn.line - 1] || '').indexOf(')') + 1;
+        const receiver = get_base_type(fn.receiver.type);
+        add(fn.name, 'method', receiver, fn.filename, fn.line, from);
+      } else if (fn.name !== 'init') {
+        add(fn.name, 'function', pkg.name, fn.filename, fn.line);
+      }
+    }
+
+    for (const type of pkg.types) {
+      const kind = SYMBOL_KINDS[type.kind] ? type.kind : 'type';
+      add(type.name, kind, pkg.name, type.filename, type.line);
+      for (const field of type.fields) {
+        for (const name of field.names) {
+          add(name, 'field', type.name, type.filename, field.line);
+        }
+      }
+      for (const method of type.methods) {
+        add(method.name, 'method', type.name, type.filename, method.line);
+      }
+    }
+
+    for (const [decls, kind] of [
+      [pkg.consts, 'constant'],
+      [pkg.vars, 'variable']
+    ]) {
+      for (const decl of decls) {
+        for (const name of decl.names.filter((n) => n !== '_')) {
+          add(name, kind, pkg.name, decl.filename, decl.line);
+        }
+      }
+    }
+  }
+
+  return symbols;
+};
+
+/**
+ * Filter workspace symbols with a fuzzy query and sort them by score.
+ * A query containing a dot is matched against `containerName.name`, so
+ * `Client.Do` finds the Do method of Client.
+ * @param {Object[]} symbols - Workspace symbols
+ * @param {string} [query_text=''] - Query typed by the user
+ * @param {number} [limit=100] - Maximum number of symbols to return
+ * @returns {Object[]} Matching symbols, best first
+ */
+const filter_workspace_symbols = (
+  symbols,
+  query_text = '',
+  limit = DEFAULT_SYMBOL_LIMIT
+) => {
+  const qualified = query_text.includes('.');
+  const scored = [];
+
+  for (const symbol of symbols) {
+    const name = qualified
+      ? `${symbol.containerName}.${symbol.name}`
+      : symbol.name;
+    const score = score_fuzzy_match(query_text, name);
+    if (score !== null) scored.push({ symbol, score });
+  }
+
+  scored.sort(function sort_by_score(a, b) {
+    if (a.score !== b.score) return b.score - a.score;
+    if (a.symbol.name !== b.symbol.name) {
+      return a.symbol.name < b.symbol.name ? -1 : 1;
+    }
+    const a_location = a.symbol.location;
+    const b_location = b.symbol.location;
+    if (a_location.uri !== b_location.uri) {
+      return a_location.uri < b_location.uri ? -1 : 1;
+    }
+    return a_location.range.start.line - b_location.range.start.line;
+  });
+
+  return scored.slice(0, limit).map((s) => s.symbol);
+};
+
+/**
+ * Format the workspace symbols of a set of Go sources, such as the files
+ * of a project.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options
+ * @param {string} [options.query=''] - Fuzzy query
+ * @param {number} [options.limit=100] - Maximum number of symbols to return
+ * @param {string} [options.root] - Absolute project directory, for file URIs
+ * @returns {Object[]} Workspace symbols in the LSP workspace/symbol shape
+ */
+const format_workspace_symbols = (sources, options = {}) => {
+  const lines = new Map();
+  const files = sources.map(function parse_source(file) {
+    lines.set(file.filename, file.source.split('\n'));
+    return parse_go_file(file.source, file.filename);
+  });
+
+  const symbols = list_workspace_symbols(group_go_packages(files), {
+    lines,
+    root: options.root
+  });
+  return filter_workspace_symbols(symbols, options.query, options.limit);
+};
+
+/**
+ * Get the workspace symbols of a project.
+ * File URIs are absolute when the project was imported from a local
+ * directory, and project relative otherwise.
+ * @param {number} project_id - The project ID
+ * @param {Object} [options] - Options
+ * @param {string} [options.query=''] - Fuzzy query
+ * @param {number} [options.limit=100] - Maximum number of symbols to return
+ * @returns {Promise<Object>} Query, symbols and the number of matches returned
+ */
+const get_workspace_symbols = async (project_id, options = {}) => {
+  const [project] = await query`
+    SELECT path FROM project WHERE id = ${project_id}
+  `;
+  const root =
+    project && posix.isAbsolute(project.path || '') ? project.path : null;
+
+  const symbols = format_workspace_symbols(
+    await load_go_sources(project_id),
+    { query: options.query, limit: options.limit, root }
+  );
+
+  return {
+    query: options.query || '',
+    symbols,
+    count: symbols.length
+  };
+};
+
+export {
+  get_workspace_symbols,
+  format_workspace_symbols,
+  list_workspace_symbols,
+  filter_workspace_symbols,
+  score_fuzzy_match,
+  SYMBOL_KINDS,
+  DEFAULT_SYMBOL_LIMIT
+};
@@ -0,0 +1,327 @@
+'use strict';
+
+/**
+ * @fileoverview Go symbol tree module.
+ * Builds a structural map of Go sources for code review, like `tree` but
+ * down to the symbols: directories, their files, and the declarations of
+ * each file (types, functions, constants and variables), with the
+ * methods of a type nested under it when declared in the same file.
+ * Every directory and file carries the number of symbols it declares,
+ * exported and unexported, so that a reviewer sees at a glance where the
+ * API of a change lives.
+ *
+ * The tree renders as text with `├──` connectors.  Its depth can be
+ * limited, collapsing the levels below into counts, and large trees fall
+ * back to a summary of the directories and files only.  Methods declared
+ * away from their type are listed in their own file as `Type.Method`.
+ * Test files are included.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/symboltree
+ */
+
+import { parse_go_file, is_exported, load_go_sources } from './golang.mjs';
+
+/**
+ * Symbols above which a tree renders as a summary unless asked otherwise.
+ */
+const DEFAULT_MAX_SYMBOLS = 500;
+
+/**
+ * Kinds of the symbols of a tree with the keyword labelling them.
+ */
+const KIND_LABELS = {
+  struct: 'struct',
+  interface: 'interface',
+  type: 'type',
+  alias: 'alias',
+  function: 'func',
+  method: 'method',
+  constant: 'const',
+  variable: 'var'
+};
+
+// ============================================================================
+// FILE SYMBOLS
+// ============================================================================
+
+/**
+ * Count the symbols of a list of symbol nodes, children included.
+ * @param {Object[]} symbols - Symbol nodes
+ * @returns {Object} Numbers of symbols, exported and unexported
+ */
+const count_symbols = (symbols) => {
+  const counts = { symbols: 0, exported: 0, unexported: 0 };
+  const visit = (nodes) => {
+    for (const node of nodes) {
+      counts.symbols++;
+      counts[node.exported ? 'exported' : 'unexported']++;
+      visit(node.children);
+    }
+  };
+  visit(symbols);
+  return counts;
+};
+
+/**
+ * List the symbols of a parsed Go file by line, with the methods of the
+ * types of the file nested under them.
+ * @param {Object} file - Parsed file (from parse_go_file)
+ * @returns {Object[]} Symbol nodes with name, kind, exported, line and children
+ */
+const build_file_symbols = (file) => {
+  const symbols = [];
+  const types = new Map();
+  const node = (name, kind, exported, line) => ({
+    name,
+    kind,
+    exported,
+    line,
+    children: []
+  });
+
+  for (const type of file.types) {
+    const kind = ['struct', 'interface', 'alias'].includes(type.kind)
+      ? type.kind
+      : 'type';
+    const entry = node(type.name, kind, type.exported, type.line);
+    types.set(type.name, entry);
+    symbols.push(entry);
+  }
+  for (const fn of file.functions) {
+    if (!fn.name) continue;
+    if (!fn.receiver) {
+      symbols.push(node(fn.name, 'function', fn.exported, fn.line));
+      continue;
+    }
+    const owner = types.get(fn.receiver.type);
+    if (owner) {
+      owner.children.push(node(fn.name, 'method', fn.exported, fn.line));
+    } else {
+      const name = `${fn.receiver.type}.${fn.name}`;
+      symbols.push(node(name, 'method', fn.exported, fn.line));
+    }
+  }
+  for (const [kind, decls] of [
+    ['constant', file.consts],
+    ['variable', file.vars]
+  ]) {
+    for (const decl of decls) {
+      for (const name of decl.names) {
+        if (name === '_') continue;
+        symbols.push(node(name, kind, is_exported(name), decl.line));
+      }
+    }
+  }
+
+  function sort_by_line(a, b) {
+    return a.line - b.line || a.name.localeCompare(b.name);
+  }
+  for (const entry of types.values()) entry.children.sort(sort_by_line);
+  return symbols.sort(sort_by_line);
+};
+
+// ============================================================================
+// TREE
+// ============================================================================
+
+/**
+ * Find the directory shared by a set of filenames.
+ * @param {string[]} filenames - Slash separated filenames
+ * @returns {string} Common directory, or '' if there is none
+ */
+const find_common_directory = (filenames) => {
+  if (filenames.length === 0) return '';
+  let common = filenames[0].split('/').slice(0, -1);
+  for (const filename of filenames.slice(1)) {
+    const parts = filename.split('/').slice(0, -1);
+    let i = 0;
+    while (i < common.length && common[i] === parts[i]) i++;
+    common = common.slice(0, i);
+  }
+  return common.join('/');
+};
+
+/**
+ * Add up the counts of the children of a directory node, recursively.
+ * @param {Object} directory - Directory node
+ * @returns {Object} Counts of the directory
+ */
+const total_directory = (directory) => {
+  const counts = { files: 0, symbols: 0, exported: 0, unexported: 0 };
+  for (const child of directory.children) {
+    if (child.type === 'directory') total_directory(child);
+    counts.files += child.type === 'file' ? 1 : child.counts.files;
+    counts.symbols += child.counts.symbols;
+    counts.exported += child.counts.exported;
+    counts.unexported += child.counts.unexported;
+  }
+  directory.counts = counts;
+  return counts;
+};
+
+/**
+ * Build the symbol tree of a set of Go sources (see the module doc).
+ * @param {Object[]} sources - Files with filename and source
+ * @returns {Object} Root directory node with path, children and counts
+ */
+const build_symbol_tree = (sources) => {
+  const filenames = sources.map((file) => file.filename.replace(/\\/g, '/'));
+  const base = find_common_directory(filenames);
+  const root = {
+    type: 'directory',
+    name: base ? base.split('/').pop() : '.',
+    path: base,
+    children: []
+  };
+  const directories = new Map([['', root]]);
+
+  const parent_of = (relative) => {
+    const slash = relative.lastIndexOf('/');
+    return slash === -1 ? '' : relative.slice(0, slash);
+  };
+  const get_directory = (relative) => {
+    if (directories.has(relative)) return directories.get(relative);
+    const slash = relative.lastIndexOf('/');
+    const parent = get_directory(parent_of(relative));
+    const directory = {
+      type: 'directory',
+      name: relative.slice(slash + 1),
+      path: base ? `${base}/${relative}` : relative,
+      children: []
+    };
+    parent.children.push(directory);
+    directories.set(relative, directory);
+    return directory;
+  };
+
+  sources.forEach((source, i) => {
+    const filename = filenames[i];
+    const relative = base ? filename.slice(base.length + 1) : filename;
+    const directory = get_directory(parent_of(relative));
+    const symbols = build_file_symbols(parse_go_file(source.source, filename));
+    directory.children.push({
+      type: 'file',
+      name: relative.slice(relative.lastIndexOf('/') + 1),
+      path: filename,
+      counts: count_symbols(symbols),
+      symbols
+    });
+  });
+
+  const sort_children = (directory) => {
+    directory.children.sort(function sort_by_type_and_name(a, b) {
+      if (a.type !== b.type) return a.type === 'directory' ? -1 : 1;
+      return a.name.localeCompare(b.name);
+    });
+    for (const child of directory.children) {
+      if (child.type === 'directory') sort_children(child);
+    }
+  };
+  sort_children(root);
+  total_directory(root);
+  return root;
+};
+
+// ============================================================================
+// RENDERING
+// ============================================================================
+
+/**
+ * Describe the counts of a directory or file node.
+ * @param {Object} node - Directory or file node
+ * @returns {string} Counts between parentheses
+ */
+const describe_counts = (node) => {
+  const { counts } = node;
+  const symbols = `${counts.symbols} symbol${counts.symbols === 1 ? '' : 's'}`;
+  const split =
+    counts.symbols > 0
+      ? `: ${counts.exported} exported, ${counts.unexported} unexported`
+      : '';
+  if (node.type === 'file') return `(${symbols}${split})`;
+  const files = `${counts.files} file${counts.files === 1 ? '' : 's'}`;
+  return `(${files}, ${symbols}${split})`;
+};
+
+/**
+ * Label a node of the tree.
+ * @param {Object} node - Directory, file or symbol node
+ * @param {boolean} collapsed - Whether the children of the node are hidden
+ * @returns {string} Label
+ */
+const label_node = (node, collapsed) => {
+  if (node.type === 'directory') return `${node.name}/ ${describe_counts(node)}`;
+  if (node.type === 'file') return `${node.name} ${describe_counts(node)}`;
+  let label = `${KIND_LABELS[node.kind]} ${node.name} [${node.line}]`;
+  if (collapsed && node.children.length > 0) {
+    const n = node.children.length;
+    label += ` (${n} method${n === 1 ? '' : 's'})`;
+  }
+  return label;
+};
+
+/**
+ * Render a symbol tree as text lines (see the module doc).
+ * @param {Object} tree - Root node (from build_symbol_tree)
+ * @param {Object} [options] - Options
+ * @param {number} [options.depth] - Levels below the root to show, collapsing the others (default: all)
+ * @param {boolean} [options.summary=false] - Show directories and files only
+ * @param {number} [options.max_symbols=500] - Symbols above which the tree renders as a summary, 0 for no limit
+ * @returns {string[]} Lines of the tree
+ */
+const format_symbol_tree = (tree, options = {}) => {
+  const depth = options.depth === undefined ? Infinity : options.depth;
+  const max_symbols =
+    options.max_symbols === undefined
+      ? DEFAULT_MAX_SYMBOLS
+      : options.max_symbols;
+  const large = max_symbols > 0 && tree.counts.symbols > max_symbols;
+  const summary = options.summary || large;
+
+  const lines = [label_node(tree, false)];
+  const children_of = (node) => {
+    if (node.type === 'directory') return node.children;
+    if (node.type === 'file') return summary ? [] : node.symbols;
+    return node.children;
+  };
+  const render = (nodes, indent, level) => {
+    nodes.forEach((node, i) => {
+      const is_last = i === nodes.length - 1;
+      const children = children_of(node);
+      const collapsed = level >= depth;
+      lines.push(
+        `${indent}${is_last ? '└── ' : '├── '}${label_node(node, collapsed)}`
+      );
+      if (!collapsed && children.length > 0) {
+        render(children, indent + (is_last ? '    ' : '│   '), level + 1);
+      }
+    });
+  };
+  if (depth > 0) render(children_of(tree), '', 1);
+
+  if (large && !options.summary) {
+    lines.push('');
+    lines.push(
+      `${tree.counts.symbols} symbols over ${max_symbols}, showing files only`
+    );
+  }
+  return lines;
+};
+
+/**
+ * Build the symbol tree of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @returns {Promise<Object>} Root directory node (see build_symbol_tree)
+ */
+const analyze_project_symbol_tree = async (project_id) => {
+  return build_symbol_tree(await load_go_sources(project_id));
+};
+
+export {
+  analyze_project_symbol_tree,
+  build_symbol_tree,
+  build_file_symbols,
+  format_symbol_tree,
+  DEFAULT_MAX_SYMBOLS,
+  KIND_LABELS
+};
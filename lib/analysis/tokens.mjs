@@ -0,0 +1,263 @@
+'use strict';
+
+/**
+ * @fileoverview Symbol token cost module.
+ * Annotates each Go symbol with the number of tokens of its signature and
+ * of its full declaration, and sums them per package, so that assistant
+ * UIs can show which symbols are expensive to include in a prompt and
+ * context builders can decide what to trim.  Tokenizers are pluggable and
+ * always named explicitly in results, since counts differ between them.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/tokens
+ */
+
+import {
+  parse_go_file,
+  group_go_packages,
+  load_go_sources
+} from './golang.mjs';
+import { get_doc_start } from './impact.mjs';
+
+/**
+ * Tokenizer used when none is given.
+ */
+const DEFAULT_TOKENIZER = 'code';
+
+// ============================================================================
+// TOKENIZERS
+// ============================================================================
+
+/**
+ * Registered tokenizers: functions counting the tokens of a text.
+ * - chars: one token per four characters, the usual estimate for
+ *   BPE vocabularies on English text
+ * - code: one token per identifier word, number group and punctuation
+ *   character, which tracks BPE counts on source code more closely
+ * - whitespace: one token per whitespace separated word
+ */
+const TOKENIZERS = new Map([
+  ['chars', (text) => Math.ceil(text.length / 4)],
+  [
+    'code',
+    (text) =>
+      (text.match(/[A-Z]?[a-z]+|[A-Z]+(?![a-z])|\d{1,3}|[^\s\w]|\n/g) || [])
+        .length
+  ],
+  ['whitespace', (text) => (text.match(/\S+/g) || []).length]
+]);
+
+/**
+ * Register a tokenizer, such as one backed by a model's real vocabulary.
+ * @param {string} name - Tokenizer name
+ * @param {Function} count - Function returning the number of tokens of a text
+ */
+const register_tokenizer = (name, count) => {
+  if (typeof count !== 'function') {
+    throw new Error(`Tokenizer '${name}' must be a function`);
+  }
+  TOKENIZERS.set(name, count);
+};
+
+/**
+ * List the names of the registered tokenizers.
+ * @returns {string[]} Tokenizer names
+ */
+const list_tokenizers = () => {
+  return [...TOKENIZERS.keys()];
+};
+
+/**
+ * Create a token counter for a tokenizer.  Counts are cached by text, so
+ * identical declarations are only tokenized once.
+ * @param {string} [name='code'] - Tokenizer name
+ * @returns {Function} Counter taking a text, with the tokenizer name as its tokenizer property
+ * @throws {Error} If the tokenizer is not registered
+ */
+const create_token_counter = (name = DEFAULT_TOKENIZER) => {
+  const count = TOKENIZERS.get(name);
+  if (!count) {
+    throw new Error(
+      `Unknown tokenizer '${name}' (expected one of: ${list_tokenizers().join(', ')})`
+    );
+  }
+
+  const cache = new Map();
+  const counter = (text) => {
+    if (!text) return 0;
+    if (!cache.has(text)) cache.set(text, count(text));
+    return cache.get(text);
+  };
+  counter.tokenizer = name;
+  return counter;
+};
+
+// ============================================================================
+// SYMBOL COSTS
+// ============================================================================
+
+/**
+ * Give a symbol a lazily computed token_cost property: the tokens of its
+ * signature, of its full declaration with doc comment, and their
+ * difference: the doc comment and, for functions, the body.  The cost is
+ * computed on first access and cached on the symbol.
+ * @param {Object} symbol - Symbol with signature_text and text
+ * @param {Function} counter - Token counter (from create_token_counter)
+ * @returns {Object} The symbol
+ */
+const attach_token_cost = (symbol, counter) => {
+  let cost = null;
+  Object.defineProperty(symbol, 'token_cost', {
+    enumerable: true,
+    get() {
+      if (cost === null) {
+        const signature = counter(symbol.signature_text);
+        const total = counter(symbol.text);
+        cost = {
+          signature,
+          body: Math.max(0, total - signature),
+          total
+        };
+      }
+      return cost;
+    }
+  });
+  return symbol;
+};
+
+/**
+ * List the symbols of a package with their texts.  The signature of a
+ * function or method is its declaration without doc comment or body; that
+ * of other symbols their declaration without doc comment.
+ * @param {Object} pkg - Package (from group_go_packages)
+ * @param {Map<string, string[]>} lines - Source lines by filename
+ * @returns {Object[]} Symbols with name, kind, location, signature_text and text
+ */
+const list_package_symbols = (pkg, lines) => {
+  const symbols = [];
+  const add = (name, kind, filename, line, end_line, signature_text, doc) => {
+    const file_lines = lines.get(filename) || [];
+    const start = doc ? get_doc_start(file_lines, line) : line;
+    const declaration = file_lines.slice(line - 1, end_line).join('\n');
+    symbols.push({
+      name,
+      kind,
+      package: pkg.name,
+      directory: pkg.directory,
+      filename,
+      line,
+      end_line,
+      signature_text: signature_text === null ? declaration : signature_text,
+      text: file_lines.slice(start - 1, end_line).join('\n')
+    });
+  };
+
+  for (const fn of pkg.functions) {
+    if (!fn.name) continue;
+    const name = fn.receiver ? `${fn.receiver.type}.${fn.name}` : fn.name;
+    const kind = fn.receiver ? 'method' : 'function';
+    add(name, kind, fn.filename, fn.line, fn.end_line, fn.signature, true);
+  }
+  for (const type of pkg.types) {
+    add(type.name, 'type', type.filename, type.line, type.end_line, null, true);
+  }
+  for (const [decls, kind] of [
+    [pkg.consts, 'const'],
+    [pkg.vars, 'var']
+  ]) {
+    for (const decl of decls) {
+      for (const name of decl.names.filter((n) => n !== '_')) {
+        const end_line = decl.end_line || decl.line;
+        const doc = !decl.grouped;
+        add(name, kind, decl.filename, decl.line, end_line, null, doc);
+      }
+    }
+  }
+
+  return symbols;
+};
+
+/**
+ * Compute the token costs of the symbols of a set of Go sources, with
+ * package totals.
+ * @param {Object[]} sources - Files with filename and source
+ * @param {Object} [options] - Options
+ * @param {string} [options.tokenizer='code'] - Tokenizer name
+ * @returns {Object} Tokenizer, symbols (with token_cost) and packages with totals
+ */
+const summarize_token_costs = (sources, options = {}) => {
+  const counter = create_token_counter(options.tokenizer);
+  const lines = new Map();
+  const files = sources.map(function parse_source(file) {
+    lines.set(file.filename, file.source.split('\n'));
+    return parse_go_file(file.source, file.filename);
+  });
+
+  const packages = group_go_packages(files).map(function cost_package(pkg) {
+    const symbols = list_package_symbols(pkg, lines).map((s) =>
+      attach_token_cost(s, counter)
+    );
+    return {
+      package: pkg.name,
+      directory: pkg.directory,
+      symbols,
+      signature_tokens: symbols.reduce(
+        (sum, s) => sum + s.token_cost.signature,
+        0
+      ),
+      total_tokens: symbols.reduce((sum, s) => sum + s.token_cost.total, 0)
+    };
+  });
+
+  return { tokenizer: counter.tokenizer, packages };
+};
+
+/**
+ * Compute the token costs of the symbols of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options
+ * @param {string} [options.tokenizer='code'] - Tokenizer name
+ * @param {number} [options.limit] - Only return the most expensive symbols
+ * @returns {Promise<Object>} Symbols by descending cost, package totals and a summary
+ */
+const analyze_project_token_costs = async (project_id, options = {}) => {
+  const result = summarize_token_costs(
+    await load_go_sources(project_id),
+    options
+  );
+
+  const symbols = result.packages
+    .flatMap(function format_symbols(pkg) {
+      return pkg.symbols.map(({ signature_text, text, ...symbol }) => symbol);
+    })
+    .sort(function sort_by_cost(a, b) {
+      return b.token_cost.total - a.token_cost.total;
+    });
+
+  return {
+    tokenizer: result.tokenizer,
+    symbols: options.limit ? symbols.slice(0, options.limit) : symbols,
+    packages: result.packages.map(({ symbols: package_symbols, ...pkg }) => ({
+      ...pkg,
+      symbol_count: package_symbols.length
+    })),
+    summary: {
+      total_symbols: symbols.length,
+      signature_tokens: result.packages.reduce(
+        (sum, p) => sum + p.signature_tokens,
+        0
+      ),
+      total_tokens: result.packages.reduce((sum, p) => sum + p.total_tokens, 0)
+    }
+  };
+};
+
+export {
+  analyze_project_token_costs,
+  summarize_token_costs,
+  list_package_symbols,
+  attach_token_cost,
+  create_token_counter,
+  register_tokenizer,
+  list_tokenizers,
+  DEFAULT_TOKENIZER
+};
@@ -0,0 +1,347 @@
+'use strict';
+
+/**
+ * @fileoverview Go unsafe usage module.
+ * Finds the places where Go code steps outside of the type and memory
+ * safety of the language, for security audits of "where do we do unsafe
+ * things":
+ * - unsafe: uses of the unsafe package (`unsafe.Pointer`, `unsafe.Add`)
+ * - reflect: uses of the reflect package, the raw header types and
+ *   unsafe address methods being the risky ones
+ * - linkname: `//go:linkname` directives, reaching into unexported
+ *   symbols of other packages
+ * - assembly: functions declared without a body, implemented in assembly
+ *   (or by a linkname)
+ * Package uses are resolved through the imports of each file: `unsafe` is
+ * only the unsafe package in files importing it under that name, so
+ * variables, fields and selectors named unsafe (`opts.unsafe`) are not
+ * reported, and aliased imports (`u "unsafe"`) are.
+ * Computed on-demand from source code - no database changes required.
+ * @module lib/unsafe
+ */
+
+import {
+  mask_source,
+  build_line_index,
+  line_at,
+  get_base_type,
+  parse_go_file,
+  load_go_sources
+} from './golang.mjs';
+import { get_import_name, get_package_names } from './imports.mjs';
+
+/**
+ * Kinds of unsafe usage.
+ */
+const UNSAFE_KINDS = ['unsafe', 'reflect', 'linkname', 'assembly'];
+
+/**
+ * Severity of the members of the unsafe package.  The layout functions
+ * are evaluated at compile time and do not touch memory.
+ */
+const UNSAFE_MEMBERS = {
+  Pointer: 'high',
+  Add: 'high',
+  Slice: 'high',
+  SliceData: 'high',
+  String: 'high',
+  StringData: 'high',
+  Sizeof: 'low',
+  Offsetof: 'low',
+  Alignof: 'low'
+};
+
+/**
+ * Members of the reflect package bypassing memory safety.
+ */
+const REFLECT_UNSAFE_MEMBERS = new Set([
+  'SliceHeader',
+  'StringHeader',
+  'NewAt'
+]);
+
+/**
+ * Methods of reflect values returning raw addresses.
+ */
+const REFLECT_UNSAFE_METHODS = /\.\s*(UnsafeAddr|UnsafePointer)\s*\(/g;
+
+/**
+ * A `//go:linkname localname [importpath.name]` directive.
+ */
+const LINKNAME_PATTERN = /^\/\/go:linkname\s+(\S+)(?:\s+(\S+))?/;
+
+// ============================================================================
+// SITES
+// ============================================================================
+
+/**
+ * Find the uses of a package in a masked source: its name qualifying an
+ * identifier (`unsafe.Pointer`), not itself a selector (`opts.unsafe.X`).
+ * @param {string} masked - Masked source
+ * @param {string} name - Name the file imports the package by
+ * @returns {Object[]} Uses with the member and offset
+ */
+const find_package_uses = (masked, name) => {
+  const pattern = new RegExp(
+    `(?<![\\w.])${name}\\s*\\.\\s*([A-Za-z_]\\w*)`,
+    'g'
+  );
+  const uses = [];
+  let match;
+  while ((match = pattern.exec(masked)) !== null) {
+    uses.push({ member: match[1], offset: match.index });
+  }
+  return uses;
+};
+
+/**
+ * Find the `//go:linkname` directives of a Go source file.
+ * @param {string} source - Go source code
+ * @returns {Object[]} Directives with local name, target and line
+ */
+const find_linkname_directives = (source) => {
+  const directives = [];
+  (source || '').split('\n').forEach(function check_line(text, index) {
+    const match = text.trim().match(LINKNAME_PATTERN);
+    if (!match) return;
+    directives.push({
+      local: match[1],
+      target: match[2] || null,
+      line: index + 1
+    });
+  });
+  return directives;
+};
+
+/**
+ * Find the unsafe usage sites of a Go source file.
+ * @param {string} source - Go source code
+ * @param {string} [filename=''] - Filename
+ * @param {Map<string, string>} [package_names] - Project package names by directory
+ * @returns {Object} File with its package, flags, sites and functions
+ */
+const find_file_unsafe_usage = (source, filename = '', package_names) => {
+  const file = parse_go_file(source, filename);
+  const masked = mask_source(source || '');
+  const line_index = build_line_index(masked);
+  const sites = [];
+
+  // The function whose declaration spans a line
+  const get_function = (line) => {
+    const fn = file.functions.find(
+      (f) => f.line <= line && line <= f.end_line
+    );
+    if (!fn) return null;
+    const receiver = fn.receiver ? get_base_type(fn.receiver.type) : null;
+    return receiver ? `${receiver}.${fn.name}` : fn.name;
+  };
+  const add = (site) =>
+    sites.push({ ...site, function: get_function(site.line) });
+
+  for (const imp of file.imports) {
+    if (imp.path !== 'unsafe' && imp.path !== 'reflect') continue;
+    add({
+      kind: imp.path,
+      name: imp.path,
+      site: 'import',
+      line: imp.line,
+      severity: 'info',
+      message: `imports "${imp.path}"${imp.name ? ` as ${imp.name}` : ''}`
+    });
+    if (imp.name === '_') continue;
+
+    const { name } = get_import_name(imp, package_names);
+    for (const use of find_package_uses(masked, name)) {
+      const line = line_at(line_index, use.offset);
+      if (imp.path === 'unsafe') {
+        add({
+          kind: 'unsafe',
+          name: `unsafe.${use.member}`,
+          site: 'use',
+          line,
+          severity: UNSAFE_MEMBERS[use.member] || 'high',
+          message:
+            UNSAFE_MEMBERS[use.member] === 'low'
+              ? `unsafe.${use.member} reads the memory layout of a type`
+              : `unsafe.${use.member} bypasses the type and memory safety of Go`
+        });
+      } else {
+        const risky = REFLECT_UNSAFE_MEMBERS.has(use.member);
+        add({
+          kind: 'reflect',
+          name: `reflect.${use.member}`,
+          site: 'use',
+          line,
+          severity: risky ? 'high' : 'low',
+          message: risky
+            ? `reflect.${use.member} accesses raw memory`
+            : `reflect.${use.member} inspects or modifies values at run time`
+        });
+      }
+    }
+
+    if (imp.path === 'reflect') {
+      let match;
+      REFLECT_UNSAFE_METHODS.lastIndex = 0;
+      while ((match = REFLECT_UNSAFE_METHODS.exec(masked)) !== null) {
+        add({
+          kind: 'reflect',
+          name: match[1],
+          site: 'use',
+          line: line_at(line_index, match.index),
+          severity: 'high',
+          message: `${match[1]} returns the raw address of a reflected value`
+        });
+      }
+    }
+  }
+
+  const linknames = find_linkname_directives(source);
+  for (const directive of linknames) {
+    const site = {
+      kind: 'linkname',
+      name: directive.local,
+      site: 'directive',
+      target: directive.target,
+      line: directive.line,
+      severity: 'high',
+      message: directive.target
+        ? `//go:linkname binds ${directive.local} to ${directive.target}`
+        : `//go:linkname exports ${directive.local} to other packages`
+    };
+    // Directives apply to the declaration of their local name
+    const fn = file.functions.find(
+      (f) => !f.receiver && f.name === directive.local
+    );
+    sites.push({ ...site, function: fn ? fn.name : get_function(site.line) });
+  }
+
+  const linked = new Set(linknames.map((d) => d.local));
+  for (const fn of file.functions) {
+    if (fn.body !== null || linked.has(fn.name)) continue;
+    add({
+      kind: 'assembly',
+      name: fn.name,
+      site: 'declaration',
+      line: fn.line,
+      severity: 'medium',
+      message: `${fn.name} is declared without a body, implemented in assembly`
+    });
+  }
+
+  sites.sort(function sort_by_line(a, b) {
+    return a.line - b.line || a.kind.localeCompare(b.kind);
+  });
+
+  // Functions with the kinds of unsafe usage in them
+  const functions = new Map();
+  for (const site of sites) {
+    if (!site.function) continue;
+    if (!functions.has(site.function)) {
+      functions.set(site.function, {
+        name: site.function,
+        kinds: new Set(),
+        sites: 0
+      });
+    }
+    const fn = functions.get(site.function);
+    fn.kinds.add(site.kind);
+    fn.sites++;
+  }
+
+  return {
+    filename,
+    package: file.package,
+    flags: Object.fromEntries(
+      UNSAFE_KINDS.map((kind) => [kind, sites.some((s) => s.kind === kind)])
+    ),
+    sites,
+    functions: [...functions.values()].map((fn) => ({
+      ...fn,
+      kinds: UNSAFE_KINDS.filter((kind) => fn.kinds.has(kind))
+    }))
+  };
+};
+
+/**
+ * Find the unsafe usage of a set of Go sources.  Files without unsafe
+ * usage are not listed.
+ * @param {Object[]} sources - Sources with filename and source
+ * @param {Object} [options] - Options
+ * @param {string[]} [options.kinds] - Kinds of usage to report (default: all)
+ * @returns {Object[]} Files with their flags, sites and functions
+ */
+const find_unsafe_usage = (sources, options = {}) => {
+  const kinds = options.kinds || UNSAFE_KINDS;
+  const unknown = kinds.filter((kind) => !UNSAFE_KINDS.includes(kind));
+  if (unknown.length > 0) {
+    throw new Error(
+      `Unknown unsafe usage kind: ${unknown.join(', ')} (expected ${UNSAFE_KINDS.join(', ')})`
+    );
+  }
+
+  const package_names = get_package_names(sources);
+  return sources
+    .map(function check_source(row) {
+      const file = find_file_unsafe_usage(
+        row.source,
+        row.filename,
+        package_names
+      );
+      const sites = file.sites.filter((s) => kinds.includes(s.kind));
+      return {
+        ...file,
+        sites,
+        functions: file.functions
+          .map((fn) => ({
+            ...fn,
+            kinds: fn.kinds.filter((k) => kinds.includes(k))
+          }))
+          .filter((fn) => fn.kinds.length > 0)
+      };
+    })
+    .filter((file) => file.sites.length > 0);
+};
+
+/**
+ * Report the unsafe usage of a project.
+ * @param {number} project_id - The project ID to analyze
+ * @param {Object} [options] - Options (see find_unsafe_usage)
+ * @returns {Promise<Object>} Files, functions and a summary
+ */
+const analyze_project_unsafe_usage = async (project_id, options = {}) => {
+  const files = find_unsafe_usage(await load_go_sources(project_id), options);
+  const sites = files.flatMap((file) => file.sites);
+  const functions = files.flatMap((file) =>
+    file.functions.map((fn) => ({
+      filename: file.filename,
+      package: file.package,
+      ...fn
+    }))
+  );
+
+  return {
+    files,
+    functions,
+    summary: {
+      files: files.length,
+      functions: functions.length,
+      sites: sites.length,
+      high_severity: sites.filter((s) => s.severity === 'high').length,
+      by_kind: Object.fromEntries(
+        UNSAFE_KINDS.map((kind) => [
+          kind,
+          sites.filter((s) => s.kind === kind).length
+        ])
+      )
+    }
+  };
+};
+
+export {
+  analyze_project_unsafe_usage,
+  find_unsafe_usage,
+  find_file_unsafe_usage,
+  find_linkname_directives,
+  UNSAFE_KINDS
+};
@@ -25,6 +25,8 @@ import {
   list_definitions,
   get_symbol_reference_summary,
   find_symbols_at_location,
+  analyze_rename_impact,
+  compare_symbol,
   get_class_hierarchy,
   find_implementations,
   analyze_class_hierarchy,
@@ -33,7 +35,74 @@ import {
   analyze_project_naming_conventions,
   analyze_project_readability_score,
   analyze_project_design_patterns,
-  analyze_project_test_coverage
+  analyze_project_test_coverage,
+  analyze_project_struct_sizes,
+  extract_literals,
+  analyze_project_channels,
+  analyze_project_nesting,
+  analyze_package_docs,
+  analyze_symbol_graph,
+  render_graph_html,
+  analyze_project_error_wrapping,
+  analyze_project_panics,
+  analyze_project_field_init,
+  get_workspace_symbols,
+  analyze_project_init_order,
+  analyze_go_scripts,
+  analyze_project_duplicate_methods,
+  analyze_project_context_params,
+  analyze_project_token_costs,
+  generate_stub,
+  analyze_project_undocumented,
+  analyze_project_doc_names,
+  analyze_project_struct_kinds,
+  analyze_project_near_misses,
+  export_search_index,
+  format_search_index,
+  analyze_build_constraints,
+  analyze_project_smell_scores,
+  analyze_project_method_shadowing,
+  analyze_project_conversions,
+  generate_minimal_repro,
+  analyze_project_concurrency_hints,
+  analyze_project_embedders,
+  analyze_project_escapes,
+  analyze_project_imports,
+  export_api_catalog,
+  export_project_proto,
+  analyze_project_loop_captures,
+  analyze_project_conversions_between,
+  analyze_project_method_sets,
+  analyze_project_interface_widths,
+  analyze_project_struct_tags,
+  analyze_project_coupling,
+  analyze_project_unsafe_usage,
+  analyze_project_call_chains,
+  analyze_project_inline_hints,
+  analyze_project_unused_interfaces,
+  analyze_project_type_complexity,
+  check_format_calls,
+  analyze_project_copy_locks,
+  analyze_project_field_types,
+  analyze_project_method_implementers,
+  analyze_project_deprecated_calls,
+  analyze_project_interface_guideline,
+  analyze_project_function_values,
+  analyze_project_magic_numbers,
+  analyze_project_error_results,
+  analyze_project_options_structs,
+  analyze_project_handlers_of_shape,
+  analyze_project_recursive_structs,
+  analyze_project_doc_links,
+  analyze_project_builtin_shadowing,
+  analyze_project_state_machines,
+  analyze_project_package_metrics,
+  analyze_project_param_names,
+  analyze_project_symbol_ranges,
+  analyze_project_state_aliasing,
+  analyze_project_unused_interface_methods,
+  analyze_project_predicate_names,
+  analyze_project_risk_scores
 } from '../../analysis/index.mjs';
 
 /**
@@ -387,6 +456,60 @@ const reference_summary = {
   }
 };
 
+// Cross-reference: Rename impact
+const rename_impact = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/rename-impact/{symbol}',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const { new_name, filename } = request.query;
+    if (!new_name) {
+      return h
+        .response({ error: 'new_name query parameter is required' })
+        .code(400);
+    }
+
+    const result = await analyze_rename_impact(
+      project_id,
+      request.params.symbol,
+      new_name,
+      { filename }
+    );
+    return result;
+  }
+};
+
+// Cross-reference: Compare a symbol between two files
+const symbol_compare = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/compare/{symbol}',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const { file_a, file_b } = request.query;
+    if (!file_a || !file_b) {
+      return h
+        .response({ error: 'file_a and file_b query parameters are required' })
+        .code(400);
+    }
+
+    try {
+      const result = await compare_symbol(
+        project_id,
+        request.params.symbol,
+        file_a,
+        file_b
+      );
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(404);
+    }
+  }
+};
+
 // Cross-reference: Symbols at location
 const symbols_at_location = {
   method: 'GET',
@@ -536,6 +659,1238 @@ const tests = {
   }
 };
 
+// Struct size and large by-value parameter analysis
+const struct_size = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/struct-size',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const threshold = request.query.threshold
+      ? parseInt(request.query.threshold)
+      : undefined;
+    const result = await analyze_project_struct_sizes(project_id, {
+      threshold
+    });
+    return result;
+  }
+};
+
+// Go literal extraction
+const literals = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/literals',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await extract_literals(project_id, {
+        kind: request.query.kind,
+        inline_only: request.query.inline_only === 'true'
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go channel and goroutine topology
+const channels = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/channels',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_channels(project_id, {
+      package: request.query.package,
+      function: request.query.function
+    });
+    return result;
+  }
+};
+
+// Go guard clause and nesting analysis
+const nesting = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/nesting',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_nesting(project_id);
+    return result;
+  }
+};
+
+// Go package doc comments
+const package_docs = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/package-docs',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_package_docs(project_id);
+    return result;
+  }
+};
+
+// Go symbol graph (JSON, or an interactive page with format=html)
+const symbol_graph = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/graph',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await analyze_symbol_graph(project_id, {
+        edge_types: request.query.edges
+          ? request.query.edges.split(',')
+          : undefined,
+        package: request.query.package
+      });
+      if (request.query.format === 'html') {
+        const title = `Symbol Graph: ${request.params.name}`;
+        return h
+          .response(render_graph_html(result, { title }))
+          .type('text/html');
+      }
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go error wrapping conventions
+const error_wrapping = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/error-wrapping',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await analyze_project_error_wrapping(project_id, {
+        expected: request.query.expected
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go panics reachable from exported functions
+const panics = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/panics',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const max_depth = request.query.max_depth
+      ? parseInt(request.query.max_depth)
+      : undefined;
+    const result = await analyze_project_panics(project_id, { max_depth });
+    return result;
+  }
+};
+
+// Go struct field initialization
+const field_init = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/field-init',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_field_init(project_id);
+    return result;
+  }
+};
+
+// Go workspace symbols (LSP workspace/symbol)
+const workspace_symbols = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/workspace-symbols',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const limit = request.query.limit
+      ? parseInt(request.query.limit)
+      : undefined;
+    const result = await get_workspace_symbols(project_id, {
+      query: request.query.query,
+      limit
+    });
+    return result;
+  }
+};
+
+// Go package variable initialization order
+const init_order = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/init-order',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_init_order(project_id);
+    return result;
+  }
+};
+
+// Go scripts excluded by go:build ignore
+const go_scripts = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/go-scripts',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_go_scripts(project_id);
+    return result;
+  }
+};
+
+// Go methods with identical bodies across types
+const duplicate_methods = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/duplicate-methods',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const min_statements = request.query.min_statements
+      ? parseInt(request.query.min_statements)
+      : undefined;
+    const result = await analyze_project_duplicate_methods(project_id, {
+      min_statements
+    });
+    return result;
+  }
+};
+
+// Go context.Context parameters that are not first
+const context_params = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/context-params',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const allow_types_before = request.query.allow_before
+      ? request.query.allow_before.split(',')
+      : [];
+    const result = await analyze_project_context_params(project_id, {
+      allow_types_before
+    });
+    return result;
+  }
+};
+
+// Token cost of each Go symbol
+const token_costs = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/token-costs',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const limit = request.query.limit
+      ? parseInt(request.query.limit)
+      : undefined;
+    try {
+      const result = await analyze_project_token_costs(project_id, {
+        tokenizer: request.query.tokenizer,
+        limit
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Stub implementing a Go interface
+const stub = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/stub',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    if (!request.query.interface) {
+      return h.response({ error: 'interface is required' }).code(400);
+    }
+    try {
+      const { interface: interface_name, type } = request.query;
+      const result = await generate_stub(project_id, interface_name, {
+        type_name: type
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(404);
+    }
+  }
+};
+
+// Go exported symbols without doc comments
+const undocumented = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/undocumented',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const kinds = request.query.kinds
+      ? request.query.kinds.split(',')
+      : undefined;
+    const exclude = request.query.exclude
+      ? request.query.exclude.split(',')
+      : [];
+    try {
+      const result = await analyze_project_undocumented(project_id, {
+        kinds,
+        exclude
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go doc comments not beginning with the declared name
+const doc_names = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/doc-names',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const kinds = request.query.kinds
+      ? request.query.kinds.split(',')
+      : undefined;
+    const exclude = request.query.exclude
+      ? request.query.exclude.split(',')
+      : [];
+    try {
+      const result = await analyze_project_doc_names(project_id, {
+        kinds,
+        exclude
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go struct kinds (data, service, mixed)
+const struct_kinds = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/struct-kinds',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_struct_kinds(project_id);
+    return result;
+  }
+};
+
+// Go types a few methods away from implementing an interface
+const near_misses = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/near-misses',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const max_missing = request.query.max_missing
+      ? parseInt(request.query.max_missing)
+      : undefined;
+    try {
+      const result = await analyze_project_near_misses(project_id, {
+        type: request.query.type,
+        interface: request.query.interface,
+        max_missing
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(404);
+    }
+  }
+};
+
+// Go symbols as search index documents (JSON, or JSON lines with format=jsonl)
+const search_index = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/search-index',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await export_search_index(project_id, {
+        kinds: request.query.kinds ? request.query.kinds.split(',') : undefined,
+        exported_only: request.query.exported === 'true'
+      });
+      if (request.query.format === 'jsonl') {
+        return h
+          .response(format_search_index(result.documents))
+          .type('application/x-ndjson');
+      }
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go effective build constraints, optionally for a target platform
+const build_constraints = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/build-constraints',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_build_constraints(project_id, {
+      goos: request.query.goos,
+      goarch: request.query.goarch,
+      tags: request.query.tags ? request.query.tags.split(',') : undefined
+    });
+    return result;
+  }
+};
+
+// Go code smell scores per symbol and file
+const smell_score = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/smell-score',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    // weights=panic:3,undocumented:0
+    let weights;
+    if (request.query.weights) {
+      weights = Object.fromEntries(
+        request.query.weights.split(',').map(function parse_weight(entry) {
+          const [code, value] = entry.split(':');
+          return [code, Number(value)];
+        })
+      );
+    }
+    try {
+      const result = await analyze_project_smell_scores(project_id, {
+        weights,
+        limit: request.query.limit ? parseInt(request.query.limit) : undefined
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go methods and fields shadowing promoted methods
+const method_shadowing = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/method-shadowing',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_method_shadowing(project_id);
+    return result;
+  }
+};
+
+// Go type conversions and unchecked type assertions
+const conversions = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/conversions',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_conversions(project_id, {
+      unchecked_only: request.query.unchecked === 'true'
+    });
+    return result;
+  }
+};
+
+// Minimal reproduction of a Go symbol
+const repro = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/repro',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    if (!request.query.symbol) {
+      return h.response({ error: 'symbol is required' }).code(400);
+    }
+    try {
+      const result = await generate_minimal_repro(
+        project_id,
+        request.query.symbol
+      );
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(404);
+    }
+  }
+};
+
+// Go concurrency safety hints
+const concurrency_hints = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/concurrency-hints',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await analyze_project_concurrency_hints(project_id, {
+        hint: request.query.hint
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go types embedding a type
+const embedders = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/embedders',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    if (!request.query.type) {
+      return h.response({ error: 'type is required' }).code(400);
+    }
+    try {
+      const result = await analyze_project_embedders(
+        project_id,
+        request.query.type,
+        {
+          transitive: request.query.transitive === 'true',
+          include_unexported: request.query.unexported === 'true'
+        }
+      );
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(404);
+    }
+  }
+};
+
+// Go functions returning pointers to their variables
+const escapes = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/escapes',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_escapes(project_id, {
+      include_composites: request.query.composites !== 'false'
+    });
+    return result;
+  }
+};
+
+// Go import usage
+const imports = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/imports',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_imports(project_id, {
+      unused_only: request.query.unused === 'true'
+    });
+    return result;
+  }
+};
+
+// Public API catalog of the Go packages of a project
+const catalog = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/catalog',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await export_api_catalog(project_id, {
+      module: request.query.module
+    });
+    return result;
+  }
+};
+
+// Proto3 schemas of the serialized Go structs of a project
+const proto = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/proto',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const types = request.query.type;
+    const result = await export_project_proto(project_id, {
+      types: types ? [].concat(types) : undefined,
+      package: request.query.package,
+      module: request.query.module
+    });
+    return result;
+  }
+};
+
+// Go goroutines capturing loop variables
+const loop_captures = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/loop-captures',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_loop_captures(project_id, {
+      go_version: request.query.go_version,
+      include_fixed: request.query.include_fixed === 'true'
+    });
+    return result;
+  }
+};
+
+// Go conversions between two types
+const conversions_between = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/conversions-between',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const { from, to } = request.query;
+    if (!from || !to) {
+      return h
+        .response({ error: 'from and to query parameters are required' })
+        .code(400);
+    }
+    const result = await analyze_project_conversions_between(
+      project_id,
+      from,
+      to
+    );
+    return result;
+  }
+};
+
+// Go value and pointer method sets of a type
+const method_sets = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/method-sets',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    if (!request.query.type) {
+      return h
+        .response({ error: 'type query parameter is required' })
+        .code(400);
+    }
+    try {
+      const result = await analyze_project_method_sets(
+        project_id,
+        request.query.type
+      );
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(404);
+    }
+  }
+};
+
+// Go interface widths (narrow, regular, wide)
+const interface_widths = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/interface-widths',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const max_methods = request.query.max_methods
+      ? parseInt(request.query.max_methods)
+      : undefined;
+    const result = await analyze_project_interface_widths(project_id, {
+      max_methods
+    });
+    return result;
+  }
+};
+
+// Go struct tags validated against a tag policy
+const struct_tags = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/struct-tags',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    // naming=json:snake_case,db:snake_case
+    const { require: required, naming } = request.query;
+    try {
+      const result = await analyze_project_struct_tags(project_id, {
+        required: required ? required.split(',') : undefined,
+        naming: naming
+          ? Object.fromEntries(naming.split(',').map((rule) => rule.split(':')))
+          : undefined,
+        include_embedded: request.query.include_embedded === 'true',
+        include_unexported: request.query.include_unexported === 'true',
+        all_structs: request.query.all_structs === 'true'
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Go function fan-in and fan-out
+const coupling = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/coupling',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const limit = request.query.limit
+      ? parseInt(request.query.limit)
+      : undefined;
+    const result = await analyze_project_coupling(project_id, {
+      limit,
+      include_tests: request.query.include_tests === 'true'
+    });
+    return result;
+  }
+};
+
+// Go unsafe, reflect, linkname and assembly usage
+const unsafe_usage = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/unsafe-usage',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const kind = request.query.kind;
+    try {
+      const result = await analyze_project_unsafe_usage(project_id, {
+        kinds: kind ? kind.split(',').map((k) => k.trim()) : undefined
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Longest Go call chain from an entry point
+const call_chain = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/call-chain',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const max_depth = request.query.max_depth
+      ? parseInt(request.query.max_depth)
+      : undefined;
+    try {
+      const result = await analyze_project_call_chains(project_id, {
+        root: request.query.root,
+        max_depth
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(404);
+    }
+  }
+};
+
+// Go functions likely to be inlined
+const inline_hints = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/inline-hints',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const budget = request.query.budget
+      ? parseInt(request.query.budget)
+      : undefined;
+    const result = await analyze_project_inline_hints(project_id, {
+      budget,
+      inlinable_only: request.query.inlinable === 'true'
+    });
+    return result;
+  }
+};
+
+// Go interfaces never used as a type
+const unused_interfaces = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/unused-interfaces',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_unused_interfaces(project_id, {
+      unexported_only: request.query.unexported === 'true'
+    });
+    return result;
+  }
+};
+
+// Go types with the most complex methods
+const type_complexity = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/type-complexity',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const limit = request.query.limit
+      ? parseInt(request.query.limit)
+      : undefined;
+    const result = await analyze_project_type_complexity(project_id, {
+      limit,
+      include_promoted: request.query.promoted === 'true'
+    });
+    return result;
+  }
+};
+
+// Go format calls checked against their arguments
+const format_calls = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/format-calls',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await check_format_calls(project_id, {
+      mismatched_only: request.query.mismatched === 'true'
+    });
+    return result;
+  }
+};
+
+// Go types that must not be copied, and functions copying them
+const copy_locks = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/copy-locks',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_copy_locks(project_id);
+    return result;
+  }
+};
+
+// Go struct field type histogram
+const field_types = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/field-types',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await analyze_project_field_types(project_id, {
+        resolution: request.query.resolution,
+        include_embedded: request.query.embedded === 'true'
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Types declaring a method with a given signature
+const method_implementers = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/method-implementers',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    if (!request.query.method || !request.query.signature) {
+      return h
+        .response({
+          error: 'method and signature query parameters are required'
+        })
+        .code(400);
+    }
+    try {
+      const result = await analyze_project_method_implementers(
+        project_id,
+        request.query.method,
+        request.query.signature
+      );
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Calls to deprecated standard library functions
+const deprecated_calls = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/deprecated-calls',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_deprecated_calls(project_id);
+    return result;
+  }
+};
+
+// Accept interfaces, return structs guideline
+const interface_guideline = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/interface-guideline',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await analyze_project_interface_guideline(project_id, {
+        guideline: request.query.guideline
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Functions used as values
+const function_values = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/function-values',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await analyze_project_function_values(project_id, {
+        values_only: request.query.values_only === 'true'
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Magic numbers
+const magic_numbers = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/magic-numbers',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      const result = await analyze_project_magic_numbers(project_id, {
+        min_occurrences: request.query.min
+          ? parseInt(request.query.min)
+          : undefined,
+        ignore:
+          typeof request.query.ignore === 'string'
+            ? request.query.ignore.split(',').map(Number)
+            : undefined
+      });
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Error results returned last
+const error_results = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/error-results',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_error_results(project_id);
+    return result;
+  }
+};
+
+// Options structs and the functions taking them
+const options_structs = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/options-structs',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_options_structs(project_id, {
+      min_fields: request.query.min_fields
+        ? parseInt(request.query.min_fields)
+        : undefined
+    });
+    return result;
+  }
+};
+
+// Functions matching a handler shape
+const handlers_of_shape = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/handlers',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    if (!request.query.shape) {
+      return h
+        .response({ error: 'shape query parameter is required' })
+        .code(400);
+    }
+    try {
+      const result = await analyze_project_handlers_of_shape(
+        project_id,
+        request.query.shape,
+        { functions_only: request.query.functions_only === 'true' }
+      );
+      return result;
+    } catch (error) {
+      return h.response({ error: error.message }).code(400);
+    }
+  }
+};
+
+// Recursive structs
+const recursive_structs = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/recursive-structs',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_recursive_structs(project_id, {
+      recursive_only: request.query.recursive_only === 'true'
+    });
+    return result;
+  }
+};
+
+// Go doc comment links, with the links to missing symbols
+const doc_links = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/doc-links',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const exclude = request.query.exclude
+      ? request.query.exclude.split(',')
+      : [];
+    const result = await analyze_project_doc_links(project_id, {
+      unresolved_only: request.query.unresolved_only === 'true',
+      exclude
+    });
+    return result;
+  }
+};
+
+// Go declarations shadowing builtin identifiers
+const builtin_shadowing = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/builtin-shadowing',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_builtin_shadowing(project_id, {
+      problematic_only: request.query.problematic_only === 'true'
+    });
+    return result;
+  }
+};
+
+// Go lifecycle state machines
+const state_machines = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/state-machines',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_state_machines(project_id, {
+      type: request.query.type,
+      dot: request.query.dot === 'true'
+    });
+    return result;
+  }
+};
+
+// Go package abstractness, instability and main sequence distance
+const package_metrics = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/package-metrics',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const result = await analyze_project_package_metrics(project_id, {
+      threshold: request.query.threshold
+        ? parseFloat(request.query.threshold)
+        : undefined
+    });
+    return result;
+  }
+};
+
+// Go exported functions with weak parameter names
+const param_names = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/param-names',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const split = (value) =>
+      value ? value.split(',').map((v) => v.trim()).filter(Boolean) : undefined;
+    try {
+      return await analyze_project_param_names(project_id, {
+        acceptable: split(request.query.acceptable),
+        reasons: split(request.query.reasons)
+      });
+    } catch (error) {
+      return h
+        .response({ error: error.message })
+        .code(400);
+    }
+  }
+};
+
+// Tree-sitter compatible byte ranges of Go symbols
+const symbol_ranges = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/symbol-ranges',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    return await analyze_project_symbol_ranges(project_id);
+  }
+};
+
+// Go methods returning internal slices and maps
+const state_aliasing = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/state-aliasing',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    return await analyze_project_state_aliasing(project_id, {
+      include_unexported: request.query.include_unexported === 'true'
+    });
+  }
+};
+
+// Go interface methods never called through their interface
+const unused_interface_methods = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/unused-interface-methods',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    try {
+      return await analyze_project_unused_interface_methods(project_id, {
+        unexported_only: request.query.unexported === 'true',
+        confidence: request.query.confidence
+      });
+    } catch (error) {
+      return h
+        .response({ error: error.message })
+        .code(400);
+    }
+  }
+};
+
+// Go bool functions without predicate names
+const predicate_names = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/predicate-names',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const split = (value) =>
+      value ? value.split(',').map((v) => v.trim()).filter(Boolean) : undefined;
+    return await analyze_project_predicate_names(project_id, {
+      prefixes: split(request.query.prefixes),
+      acceptable: split(request.query.acceptable)
+    });
+  }
+};
+
+// Go function risk scores
+const risk_scores = {
+  method: 'GET',
+  path: '/api/v1/projects/{name}/analysis/risk',
+  handler: async (request, h) => {
+    const project_id = await get_project_id(request.params.name, h);
+    if (typeof project_id !== 'number') return project_id;
+
+    const weights = {};
+    for (const metric of ['complexity', 'fan_in', 'fan_out']) {
+      if (request.query[metric] !== undefined) {
+        weights[metric] = parseFloat(request.query[metric]);
+      }
+    }
+    try {
+      return await analyze_project_risk_scores(project_id, {
+        limit: request.query.limit ? parseInt(request.query.limit) : undefined,
+        weights
+      });
+    } catch (error) {
+      return h
+        .response({ error: error.message })
+        .code(400);
+    }
+  }
+};
+
 /** @type {Object[]} All analysis routes */
 const analysis = [
   dashboard,
@@ -555,6 +1910,8 @@ const analysis = [
   definitions,
   reference_summary,
   symbols_at_location,
+  rename_impact,
+  symbol_compare,
   // Class hierarchy routes
   class_hierarchy,
   implementations,
@@ -570,7 +1927,73 @@ const analysis = [
   // Pattern detection analysis route
   patterns,
   // Test analysis route
-  tests
+  tests,
+  // Go analysis routes
+  struct_size,
+  literals,
+  channels,
+  nesting,
+  package_docs,
+  symbol_graph,
+  error_wrapping,
+  panics,
+  field_init,
+  workspace_symbols,
+  init_order,
+  go_scripts,
+  duplicate_methods,
+  context_params,
+  token_costs,
+  stub,
+  undocumented,
+  doc_names,
+  struct_kinds,
+  near_misses,
+  search_index,
+  build_constraints,
+  smell_score,
+  method_shadowing,
+  conversions,
+  repro,
+  concurrency_hints,
+  embedders,
+  escapes,
+  imports,
+  catalog,
+  proto,
+  loop_captures,
+  conversions_between,
+  method_sets,
+  interface_widths,
+  struct_tags,
+  coupling,
+  unsafe_usage,
+  call_chain,
+  inline_hints,
+  unused_interfaces,
+  type_complexity,
+  format_calls,
+  copy_locks,
+  field_types,
+  method_implementers,
+  deprecated_calls,
+  interface_guideline,
+  function_values,
+  magic_numbers,
+  error_results,
+  options_structs,
+  handlers_of_shape,
+  recursive_structs,
+  doc_links,
+  builtin_shadowing,
+  state_machines,
+  package_metrics,
+  param_names,
+  symbol_ranges,
+  state_aliasing,
+  unused_interface_methods,
+  predicate_names,
+  risk_scores
 ];
 
 export { analysis };
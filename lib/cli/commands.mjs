@@ -1,6 +1,36 @@
 'use strict';
 
-import { help, project, func, entity, analysis, reference, hierarchy } from './commands/index.mjs';
+import {
+  help,
+  project,
+  func,
+  entity,
+  analysis,
+  reference,
+  hierarchy,
+  compare,
+  graph,
+  panics,
+  embedders,
+  impact,
+  undocumented,
+  doc_names,
+  doccov,
+  search_index,
+  changed,
+  catalog,
+  proto,
+  changelog,
+  surface,
+  receivers,
+  inversion,
+  doubles,
+  doc_links,
+  cheatsheet,
+  symbol_ranges,
+  tree,
+  risk
+} from './commands/index.mjs';
 
 // A list of the commands for the CLI.
 const commands = {
@@ -10,7 +40,29 @@ const commands = {
   entity,
   analysis,
   reference,
-  hierarchy
+  hierarchy,
+  compare,
+  graph,
+  panics,
+  embedders,
+  impact,
+  undocumented,
+  'doc-names': doc_names,
+  doccov,
+  'search-index': search_index,
+  changed,
+  catalog,
+  proto,
+  changelog,
+  surface,
+  receivers,
+  inversion,
+  doubles,
+  'doc-links': doc_links,
+  cheatsheet,
+  'symbol-ranges': symbol_ranges,
+  tree,
+  risk
 };
 
 const handler = async (command, argv) => {
@@ -18,7 +18,62 @@ import {
   analyze_project_naming_conventions,
   analyze_project_readability_score,
   analyze_project_design_patterns,
-  analyze_project_test_coverage
+  analyze_project_test_coverage,
+  analyze_project_struct_sizes,
+  extract_literals,
+  analyze_project_channels,
+  analyze_project_nesting,
+  analyze_package_docs,
+  analyze_project_error_wrapping,
+  analyze_project_field_init,
+  get_workspace_symbols,
+  analyze_project_init_order,
+  analyze_go_scripts,
+  analyze_project_duplicate_methods,
+  analyze_project_context_params,
+  analyze_project_token_costs,
+  generate_stub,
+  analyze_project_struct_kinds,
+  analyze_project_near_misses,
+  analyze_build_constraints,
+  analyze_project_smell_scores,
+  analyze_project_method_shadowing,
+  analyze_project_conversions,
+  generate_minimal_repro,
+  analyze_project_concurrency_hints,
+  analyze_project_embedders,
+  analyze_project_escapes,
+  analyze_project_imports,
+  analyze_project_loop_captures,
+  analyze_project_conversions_between,
+  analyze_project_method_sets,
+  analyze_project_interface_widths,
+  analyze_project_struct_tags,
+  analyze_project_coupling,
+  analyze_project_unsafe_usage,
+  analyze_project_call_chains,
+  analyze_project_inline_hints,
+  analyze_project_unused_interfaces,
+  analyze_project_type_complexity,
+  check_format_calls,
+  analyze_project_copy_locks,
+  analyze_project_field_types,
+  analyze_project_method_implementers,
+  analyze_project_deprecated_calls,
+  analyze_project_interface_guideline,
+  analyze_project_function_values,
+  analyze_project_magic_numbers,
+  analyze_project_error_results,
+  analyze_project_options_structs,
+  analyze_project_handlers_of_shape,
+  analyze_project_recursive_structs,
+  analyze_project_builtin_shadowing,
+  analyze_project_state_machines,
+  analyze_project_package_metrics,
+  analyze_project_param_names,
+  analyze_project_state_aliasing,
+  analyze_project_unused_interface_methods,
+  analyze_project_predicate_names
 } from '../../analysis/index.mjs';
 
 const help = `usage: cb analysis [<args>]
@@ -42,6 +97,61 @@ Provides code analysis tools for a project.
   * readability - Calculate code readability scores
   * patterns - Detect design patterns and anti-patterns
   * tests - Analyze test code and coverage patterns
+  * struct-size - Compute Go struct sizes and flag large structs passed by value
+  * literals - List string, rune and numeric literals in Go code
+  * channels - Model Go channel and goroutine topology
+  * nesting - Detect guard clauses and score nesting in Go functions
+  * package-docs - Show Go package doc comments and doc conflicts
+  * error-wrapping - Check that Go packages wrap returned errors consistently
+  * field-init - Show whether exported Go struct fields are set by constructors or callers
+  * workspace-symbols - List Go symbols in the LSP workspace/symbol format
+  * init-order - Show the initialization order of Go package variables and detect cycles
+  * go-scripts - List Go scripts and generators excluded by go:build ignore
+  * duplicate-methods - Find methods with identical bodies across types
+  * context-params - Find Go functions that do not take context.Context first
+  * token-costs - Show the token cost of each Go symbol and package
+  * stub - Generate a stub type implementing a Go interface
+  * struct-kinds - Classify Go structs as data, service or mixed
+  * near-misses - Find types a few methods away from implementing an interface
+  * build-constraints - Show the effective build constraint of each Go file
+  * smell-score - Rank symbols and files by code smell score
+  * method-shadowing - Find methods and fields shadowing promoted methods
+  * conversions - Find type conversions and unchecked type assertions
+  * repro - Extract a symbol and its dependencies into a standalone Go file
+  * concurrency-hints - Mark functions as likely safe or unsafe for concurrent use
+  * embedders - List the types embedding a type, directly or transitively
+  * escapes - Find functions returning pointers to their local variables
+  * imports - Find unused imports of Go files
+  * loop-captures - Find goroutines capturing loop variables
+  * conversions-between - Find conversions between two types
+  * method-sets - Show the value and pointer method sets of a type
+  * interface-widths - Classify interfaces as narrow, regular or wide
+  * struct-tags - Validate struct tags against a tag policy
+  * coupling - Show function fan-in and fan-out and the most coupled functions
+  * unsafe-usage - Show unsafe, reflect, linkname and assembly usage sites
+  * call-chain - Show the longest call chain from main or a root function
+  * inline-hints - Show the functions likely to be inlined by the compiler
+  * unused-interfaces - Show the interfaces never used as a type
+  * type-complexity - Show the types with the most complex methods
+  * format-calls - Check the verbs of Printf-style calls against their arguments
+  * copy-locks - Show the types that must not be copied and where they are
+  * field-types - Show how often each struct field type is used
+  * method-implementers - Find the types declaring a method with a given signature
+  * deprecated-calls - Find calls to deprecated standard library functions
+  * interface-guideline - Check the accept interfaces, return structs guideline
+  * function-values - Find functions used as values (callbacks, handlers)
+  * magic-numbers - Find repeated numbers that should be named constants
+  * error-results - Check that error results are returned last
+  * options-structs - Link functions to the options structs they take
+  * handlers - Find the functions matching a handler shape
+  * recursive-structs - Find self-referential structs (lists, trees)
+  * builtin-shadowing - Find declarations shadowing Go builtins (len, new)
+  * state-machines - Infer the state machines of Go lifecycle types (best-effort)
+  * package-metrics - Compute abstractness, instability and main sequence distance of Go packages
+  * param-names - Find exported Go functions with unnamed or uninformative parameter names
+  * state-aliasing - Find Go methods returning internal slices or maps without copying
+  * unused-interface-methods - Find Go interface methods never called through their interface
+  * predicate-names - Find exported Go functions returning a bool without a predicate name
 `;
 
 const dashboard_help = `usage: cb analysis dashboard --project=<project_name>
@@ -228,1063 +338,4418 @@ Arguments:
   * --project=[project] - Name of the project (required)
 `;
 
-// Helper to get project ID
-const get_project_id = async (project) => {
-  const projects = await get_project_by_name({ name: project });
-  if (projects.length === 0) {
-    throw new Error(`Project '${project}' not found`);
-  }
-  return projects[0].id;
-};
+const struct_size_help = `usage: cb analysis struct-size --project=<project_name> [--threshold=<bytes>]
 
-const analysis_dashboard = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await get_analysis_dashboard(project_id);
+Compute the memory layout of Go structs (size, alignment and padding on
+64-bit platforms) and flag functions that pass large structs by value
+where a pointer would be cheaper:
+- Value receivers of large structs
+- By-value parameters of large structs
+- Interfaces, pointers and small structs are never flagged
 
-  console.log(`\n=== Analysis Dashboard: ${project} ===\n`);
-  console.log(`Health Score: ${result.healthScore}/100\n`);
+Arguments:
 
-  console.log('Summary:');
-  console.log(`  Dead Code: ${result.deadCode?.count || 0} issues`);
-  console.log(`  Duplications: ${result.duplication?.count || 0} found`);
-  console.log(`  Security: ${result.security?.count || 0} vulnerabilities`);
-  console.log(`  Code Smells: ${result.codeSmells?.count || 0} detected`);
-  console.log(
-    `  Documentation: ${result.documentation?.coverage || 0}% coverage`
-  );
-};
+  * --project=[project] - Name of the project (required)
+  * --threshold=[bytes] - Size in bytes above which a struct is flagged (default 80)
+`;
 
-const analysis_dead_code = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await detect_dead_code(project_id);
+const literals_help = `usage: cb analysis literals --project=<project_name> [--kind=<kind>] [--inline-only]
 
-  console.log(`\n=== Dead Code Analysis: ${project} ===\n`);
+List the string, rune and numeric literals in Go code with their values,
+locations and enclosing declarations.  Each literal is classified by where
+it appears:
+- const: part of a named constant declaration (acceptable)
+- var: initializer of a package level variable
+- import: an import path
+- struct_tag: a struct field tag
+- inline: hardcoded anywhere else (candidates for review)
 
-  if (!result.deadCode || result.deadCode.length === 0) {
-    console.log('No potentially dead code detected.');
-    return;
-  }
+Arguments:
 
-  console.log(
-    `Found ${result.deadCode.length} potentially unreferenced functions:\n`
-  );
-  for (const item of result.deadCode) {
-    console.log(`  * ${item.symbol} - ${item.filename}:${item.start_line}`);
-  }
-};
+  * --project=[project] - Name of the project (required)
+  * --kind=[kind] - Only list literals of this kind (string, rune, number)
+  * --inline-only - Only list inline literals
+`;
 
-const analysis_duplication = async ({ project, threshold = 0.7 }) => {
-  const project_id = await get_project_id(project);
-  const result = await detect_code_duplication(project_id, threshold);
+const channels_help = `usage: cb analysis channels --project=<project_name> [--package=<package>] [--function=<function>]
 
-  console.log(`\n=== Code Duplication Analysis: ${project} ===\n`);
-  console.log(`Threshold: ${threshold}\n`);
+Build a best-effort model of Go channels and goroutines:
+- Channels declared as locals, parameters, struct fields and package variables
+- Which functions send to, receive from and close each channel
+- Goroutine launch sites and the channels they are given
+- Channels passed to other functions are followed through their parameters
+- Local channels that are only sent to or only received from are flagged
 
-  if (!result.duplicates || result.duplicates.length === 0) {
-    console.log('No code duplication detected.');
-    return;
-  }
+Arguments:
 
-  console.log(`Found ${result.duplicates.length} duplicate pairs:\n`);
-  for (const dup of result.duplicates) {
-    console.log(`  Similarity: ${(dup.similarity * 100).toFixed(1)}%`);
-    console.log(
-      `    - ${dup.func1.symbol} (${dup.func1.filename}:${dup.func1.start_line})`
-    );
-    console.log(
-      `    - ${dup.func2.symbol} (${dup.func2.filename}:${dup.func2.start_line})`
-    );
-    console.log();
-  }
-};
+  * --project=[project] - Name of the project (required)
+  * --package=[package] - Only model this package (name or directory)
+  * --function=[function] - Only show operations of this function (e.g. Run or Worker.Collect)
+`;
 
-const analysis_dependencies = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_dependencies(project_id);
+const nesting_help = `usage: cb analysis nesting --project=<project_name>
 
-  console.log(`\n=== Dependency Analysis: ${project} ===\n`);
+Analyze how Go functions manage nesting and compute a nesting quality
+score (0-100) for each:
+- Maximum control flow nesting depth and where it occurs
+- Guard clauses (early returns at the top of the body)
+- Else branches after an if block that already returns
+- Bodies wrapped in a single if that could return early instead
 
-  if (result.circularDependencies && result.circularDependencies.length > 0) {
-    console.log(
-      `Circular Dependencies (${result.circularDependencies.length}):\n`
-    );
-    for (const cycle of result.circularDependencies) {
-      console.log(`  * ${cycle.join(' -> ')}`);
-    }
-    console.log();
-  } else {
-    console.log('No circular dependencies detected.\n');
-  }
+Arguments:
 
-  if (result.dependencies) {
-    console.log(`File Dependencies:\n`);
-    for (const [file, deps] of Object.entries(result.dependencies)) {
-      if (deps.length > 0) {
-        console.log(`  ${file}:`);
-        for (const dep of deps) {
-          console.log(`    -> ${dep}`);
-        }
-      }
-    }
-  }
-};
+  * --project=[project] - Name of the project (required)
+`;
 
-const analysis_security = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await detect_security_vulnerabilities(project_id);
+const package_docs_help = `usage: cb analysis package-docs --project=<project_name>
 
-  console.log(`\n=== Security Analysis: ${project} ===\n`);
+Show the package doc comment of each Go package:
+- The "// Package name ..." comment and the file that carries it
+  (doc.go takes precedence)
+- Other files that also carry a package doc (conflicts)
+- Docs that name a different package
+- File header comments (license banners etc.) that are not package docs
 
-  if (!result.vulnerabilities || result.vulnerabilities.length === 0) {
-    console.log('No potential security vulnerabilities detected.');
-    return;
-  }
+Arguments:
 
-  console.log(
-    `Found ${result.vulnerabilities.length} potential vulnerabilities:\n`
-  );
-  for (const vuln of result.vulnerabilities) {
-    console.log(`  [${vuln.severity}] ${vuln.type}`);
-    console.log(`    ${vuln.symbol} - ${vuln.filename}:${vuln.line}`);
-    console.log(`    ${vuln.description}\n`);
-  }
-};
+  * --project=[project] - Name of the project (required)
+`;
 
-const analysis_metrics = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await get_code_metrics(project_id);
+const error_wrapping_help = `usage: cb analysis error-wrapping --project=<project_name> [--expected=<convention>]
 
-  console.log(`\n=== Code Metrics: ${project} ===\n`);
+Check that each Go package propagates errors consistently, either by
+wrapping them (fmt.Errorf("...: %w", err)) or by returning them as-is.
+Return sites that format an error without %w count as unwrapped.
 
-  if (result.summary) {
-    console.log('Summary:');
-    console.log(`  Total Functions: ${result.summary.totalFunctions || 0}`);
-    console.log(`  Total Lines: ${result.summary.totalLines || 0}`);
-    console.log(
-      `  Average Complexity: ${(result.summary.avgComplexity || 0).toFixed(2)}`
-    );
-    console.log(
-      `  Average Lines/Function: ${(result.summary.avgLinesPerFunction || 0).toFixed(1)}`
-    );
-    console.log();
-  }
+With the default convention (auto) the majority pattern of each package
+is expected and the minority return sites are reported.
 
-  if (result.highComplexity && result.highComplexity.length > 0) {
-    console.log('High Complexity Functions:\n');
-    for (const func of result.highComplexity) {
-      console.log(
-        `  * ${func.symbol} (complexity: ${func.complexity}) - ${func.filename}:${func.start_line}`
-      );
-    }
-  }
-};
+Arguments:
 
-const analysis_smells = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await detect_code_smells(project_id);
+  * --project=[project] - Name of the project (required)
+  * --expected=[convention] - Expected convention: auto, wrap or no-wrap (default: auto)
+`;
 
-  console.log(`\n=== Code Smells: ${project} ===\n`);
+const field_init_help = `usage: cb analysis field-init --project=<project_name>
 
-  if (!result.smells || result.smells.length === 0) {
-    console.log('No code smells detected.');
-    return;
-  }
+Determine for each exported field of the project's Go structs whether it
+is typically initialized by a constructor (a package function returning
+the struct, such as NewClient) or set directly by callers, in struct
+literals or by assignment.  Writes in the struct's own methods are not
+counted.
 
-  // Group by type
-  const grouped = {};
-  for (const smell of result.smells) {
-    if (!grouped[smell.type]) {
-      grouped[smell.type] = [];
-    }
-    grouped[smell.type].push(smell);
-  }
+Fields only set by constructors may be better unexported with a getter;
+fields that callers assign after construction may be better unexported
+with a setter.  Variable types are inferred from declarations, literals
+and constructor calls, so the counts are a heuristic.
 
-  for (const [type, smells] of Object.entries(grouped)) {
-    console.log(`${type} (${smells.length}):\n`);
-    for (const smell of smells) {
-      console.log(
-        `  * ${smell.symbol} - ${smell.filename}:${smell.start_line}`
-      );
-      if (smell.details) {
-        console.log(`    ${smell.details}`);
-      }
-    }
-    console.log();
-  }
-};
+Arguments:
 
-const analysis_types = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_types(project_id);
+  * --project=[project] - Name of the project (required)
+`;
 
-  console.log(`\n=== Type Coverage Analysis: ${project} ===\n`);
+const workspace_symbols_help = `usage: cb analysis workspace-symbols --project=<project_name> [--query=<query>] [--limit=<count>]
 
-  if (result.summary) {
-    console.log('Summary:');
-    console.log(`  Total Functions: ${result.summary.total || 0}`);
-    console.log(`  With Type Hints: ${result.summary.typed || 0}`);
-    console.log(`  Without Type Hints: ${result.summary.untyped || 0}`);
-    console.log(`  Coverage: ${(result.summary.coverage || 0).toFixed(1)}%`);
-    console.log();
-  }
+Print the Go symbols of every package as JSON in the shape of the LSP
+workspace/symbol response (name, kind, containerName and location).
+Methods use their receiver type as containerName.
 
-  if (result.untyped && result.untyped.length > 0) {
-    console.log('Functions Without Type Hints:\n');
-    for (const func of result.untyped.slice(0, 20)) {
-      console.log(`  * ${func.symbol} - ${func.filename}:${func.start_line}`);
-    }
-    if (result.untyped.length > 20) {
-      console.log(`  ... and ${result.untyped.length - 20} more`);
-    }
-  }
-};
+The query is matched fuzzily: its characters must appear in the symbol
+name in order, ignoring case.  A query with a dot, such as Client.Do, is
+matched against containerName.name.
 
-const analysis_api = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_api_surface(project_id);
+Arguments:
 
-  console.log(`\n=== API Surface Analysis: ${project} ===\n`);
+  * --project=[project] - Name of the project (required)
+  * --query=[query] - Fuzzy query (default: all symbols)
+  * --limit=[count] - Maximum number of symbols (default: 100)
+`;
 
-  if (result.summary) {
-    console.log('Summary:');
-    console.log(`  Total Functions: ${result.summary.total || 0}`);
-    console.log(`  Public: ${result.summary.public || 0}`);
-    console.log(`  Private: ${result.summary.private || 0}`);
-    console.log(`  Entry Points: ${result.summary.entryPoints || 0}`);
-    console.log();
-  }
+const init_order_help = `usage: cb analysis init-order --project=<project_name>
 
-  if (result.entryPoints && result.entryPoints.length > 0) {
-    console.log('Entry Points:\n');
-    for (const func of result.entryPoints) {
-      console.log(`  * ${func.symbol} - ${func.filename}:${func.start_line}`);
-    }
-  }
-};
+Extract the dependencies between the package level variables of each Go
+package, directly in their initializers or through the package functions
+they call, and show the order Go initializes them in.
 
-const analysis_docs = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_documentation(project_id);
+Variables that depend on a variable declared after them are marked as
+reordered.  Initialization cycles, which fail to compile, are reported
+with the chain of references that forms them.
 
-  console.log(`\n=== Documentation Coverage: ${project} ===\n`);
+Arguments:
 
-  if (result.summary) {
-    console.log('Summary:');
-    console.log(`  Total Functions: ${result.summary.total || 0}`);
-    console.log(`  Documented: ${result.summary.documented || 0}`);
-    console.log(`  Undocumented: ${result.summary.undocumented || 0}`);
-    console.log(`  Coverage: ${(result.summary.coverage || 0).toFixed(1)}%`);
-    console.log();
-  }
+  * --project=[project] - Name of the project (required)
+`;
 
-  if (result.undocumented && result.undocumented.length > 0) {
-    console.log('Undocumented Functions:\n');
-    for (const func of result.undocumented.slice(0, 20)) {
-      console.log(`  * ${func.symbol} - ${func.filename}:${func.start_line}`);
-    }
-    if (result.undocumented.length > 20) {
-      console.log(`  ... and ${result.undocumented.length - 20} more`);
-    }
-  }
-};
+const go_scripts_help = `usage: cb analysis go-scripts --project=<project_name>
 
-const analysis_scope = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_variable_scope(project_id);
+List the Go files excluded from every build with "//go:build ignore",
+such as code generators and maintenance scripts run with "go run".  These
+files are not part of any package, so other analyses leave them out.
 
-  console.log(`\n=== Variable Scope Analysis: ${project} ===\n`);
+For each script its package, description, imports and functions are
+shown, along with the "//go:generate" directives that run it.
 
-  if (result.globals && result.globals.length > 0) {
-    console.log(`Global Variables (${result.globals.length}):\n`);
-    for (const g of result.globals.slice(0, 20)) {
-      console.log(`  * ${g.name} - ${g.filename}:${g.line}`);
-    }
-    if (result.globals.length > 20) {
-      console.log(`  ... and ${result.globals.length - 20} more`);
-    }
-    console.log();
-  }
+Arguments:
 
-  if (result.shadowing && result.shadowing.length > 0) {
-    console.log(`Variable Shadowing (${result.shadowing.length}):\n`);
-    for (const s of result.shadowing) {
-      console.log(
-        `  * ${s.variable} in ${s.function} - ${s.filename}:${s.line}`
-      );
-    }
-  }
+  * --project=[project] - Name of the project (required)
+`;
 
-  if (
-    (!result.globals || result.globals.length === 0) &&
-    (!result.shadowing || result.shadowing.length === 0)
-  ) {
-    console.log('No scope issues detected.');
-  }
-};
+const duplicate_methods_help = `usage: cb analysis duplicate-methods --project=<project_name> [--min-statements=<count>]
 
-const analysis_concurrency = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_project_concurrency(project_id);
+Find Go methods on different types that share identical logic.  Method
+bodies are compared structurally: the receiver, the names of parameters
+and locals, comments and layout are ignored.  Trivial one-liners such as
+getters are left out.
 
-  console.log(`\n=== Concurrency Analysis: ${project} ===\n`);
+Each group lists its methods and types with a suggestion: a package
+function when the receiver is unused, an embedded struct when only
+fields declared identically by every type are used, or a shared helper.
 
-  // Summary
-  if (result.summary) {
-    console.log('Summary:');
-    console.log(`  Functions Analyzed: ${result.summary.functions_analyzed}`);
-    console.log(
-      `  Functions with Concurrency: ${result.summary.functions_with_concurrency} (${result.summary.concurrency_percentage}%)`
-    );
-    console.log(
-      `  Files with Concurrency: ${result.summary.files_with_concurrency}`
-    );
-    console.log(`  Languages: ${result.summary.languages.join(', ') || 'N/A'}`);
-    console.log();
-    console.log('Pattern Counts:');
-    console.log(`  Async Patterns: ${result.summary.total_async_patterns}`);
-    console.log(`  Thread Patterns: ${result.summary.total_thread_patterns}`);
-    console.log(`  Sync Patterns: ${result.summary.total_sync_patterns}`);
-    console.log(`  Timer Patterns: ${result.summary.total_timer_patterns}`);
-    console.log(`  Warnings: ${result.summary.total_warnings}`);
-    console.log();
-  }
+Arguments:
 
-  // Async patterns grouped
-  if (
-    result.async_patterns_grouped &&
-    result.async_patterns_grouped.length > 0
-  ) {
-    console.log('Async Patterns:\n');
-    for (const p of result.async_patterns_grouped.slice(0, 10)) {
-      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
-    }
-    console.log();
-  }
+  * --project=[project] - Name of the project (required)
+  * --min-statements=[count] - Minimum statements of a method (default 2)
+`;
 
-  // Thread patterns grouped
-  if (
-    result.thread_patterns_grouped &&
-    result.thread_patterns_grouped.length > 0
-  ) {
-    console.log('Thread Patterns:\n');
-    for (const p of result.thread_patterns_grouped.slice(0, 10)) {
-      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
-    }
-    console.log();
-  }
+const context_params_help = `usage: cb analysis context-params --project=<project_name> [--allow-before=<types>]
 
-  // Sync patterns grouped
-  if (result.sync_patterns_grouped && result.sync_patterns_grouped.length > 0) {
-    console.log('Synchronization Patterns:\n');
-    for (const p of result.sync_patterns_grouped.slice(0, 10)) {
-      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
-    }
-    console.log();
-  }
+Find Go functions, methods, interface methods and function types that
+take a context.Context but not as their first parameter, as Go style
+requires.  Renamed and dot imports of "context" and aliases of
+context.Context are recognised.  Functions without a context are not
+reported.
 
-  // Timer patterns grouped
-  if (
-    result.timer_patterns_grouped &&
-    result.timer_patterns_grouped.length > 0
-  ) {
-    console.log('Timer Patterns:\n');
-    for (const p of result.timer_patterns_grouped.slice(0, 10)) {
-      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
-    }
-    console.log();
-  }
+Arguments:
 
-  // Warnings
-  if (result.warnings && result.warnings.length > 0) {
-    console.log(`Potential Issues (${result.warnings.length}):\n`);
-    for (const w of result.warnings.slice(0, 20)) {
-      console.log(`  [${w.severity}] ${w.description}`);
-      console.log(`    ${w.symbol} - ${w.filename}:${w.start_line}`);
-    }
-    if (result.warnings.length > 20) {
-      console.log(`  ... and ${result.warnings.length - 20} more`);
-    }
-    console.log();
-  }
+  * --project=[project] - Name of the project (required)
+  * --allow-before=[types] - Comma separated parameter types allowed before the context (e.g. *testing.T)
+`;
 
-  // Functions with concurrency
-  if (
-    result.functions_with_concurrency &&
-    result.functions_with_concurrency.length > 0
-  ) {
-    console.log(
-      `Functions with Concurrency (${result.functions_with_concurrency.length}):\n`
-    );
-    for (const fn of result.functions_with_concurrency.slice(0, 20)) {
-      const counts = [];
-      if (fn.pattern_counts.async > 0)
-        counts.push(`async:${fn.pattern_counts.async}`);
-      if (fn.pattern_counts.thread > 0)
-        counts.push(`thread:${fn.pattern_counts.thread}`);
-      if (fn.pattern_counts.sync > 0)
-        counts.push(`sync:${fn.pattern_counts.sync}`);
-      if (fn.pattern_counts.timer > 0)
-        counts.push(`timer:${fn.pattern_counts.timer}`);
-      console.log(
-        `  * ${fn.symbol} [${counts.join(', ')}] - ${fn.filename}:${fn.start_line}`
-      );
-    }
-    if (result.functions_with_concurrency.length > 20) {
-      console.log(
-        `  ... and ${result.functions_with_concurrency.length - 20} more`
-      );
-    }
-  }
+const token_costs_help = `usage: cb analysis token-costs --project=<project_name> [--tokenizer=<name>] [--limit=<count>]
 
-  if (
-    !result.functions_with_concurrency ||
-    result.functions_with_concurrency.length === 0
-  ) {
-    console.log('No concurrency patterns detected in this project.');
-  }
-};
+Show how many tokens each Go symbol costs when included in a prompt: its
+signature alone, and its full declaration with doc comment and body.
+Totals are given per package.  Counts depend on the tokenizer, which is
+always reported:
 
-const analysis_resources = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_project_resources(project_id);
+  * code - One token per identifier word, number group and punctuation character (default)
+  * chars - One token per four characters
+  * whitespace - One token per whitespace separated word
 
-  console.log(`\n=== Resource Analysis: ${project} ===\n`);
+Arguments:
 
-  // Summary
-  if (result.summary) {
-    console.log('Summary:');
-    console.log(`  Functions Analyzed: ${result.summary.functions_analyzed}`);
-    console.log(
-      `  Functions with Resources: ${result.summary.functions_with_resources} (${result.summary.resource_percentage}%)`
-    );
-    console.log(
-      `  Files with Resources: ${result.summary.files_with_resources}`
-    );
-    console.log(`  Languages: ${result.summary.languages.join(', ') || 'N/A'}`);
-    console.log(
-      `  Categories: ${result.summary.categories.join(', ') || 'N/A'}`
-    );
-    console.log();
-    console.log('Pattern Counts:');
-    console.log(`  Acquisitions: ${result.summary.total_acquisitions}`);
-    console.log(`  Releases: ${result.summary.total_releases}`);
-    console.log(
-      `  Safe Patterns (RAII/smart pointers): ${result.summary.total_safe_patterns}`
-    );
-    console.log(`  Potential Leaks: ${result.summary.total_potential_leaks}`);
-    console.log(`  Warnings: ${result.summary.total_warnings}`);
-    console.log();
-  }
+  * --project=[project] - Name of the project (required)
+  * --tokenizer=[name] - Tokenizer to count with (default code)
+  * --limit=[count] - Only show the most expensive symbols (default 20)
+`;
 
-  // Safe patterns grouped
-  if (result.safe_patterns_grouped && result.safe_patterns_grouped.length > 0) {
-    console.log(
-      'Safe Resource Patterns (RAII, smart pointers, context managers):\n'
-    );
-    for (const p of result.safe_patterns_grouped.slice(0, 10)) {
-      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
-    }
-    console.log();
-  }
+const stub_help = `usage: cb analysis stub --project=<project_name> --interface=<name> [--type=<name>]
 
-  // Acquisitions grouped
-  if (result.acquisitions_grouped && result.acquisitions_grouped.length > 0) {
-    console.log('Resource Acquisitions:\n');
-    for (const p of result.acquisitions_grouped.slice(0, 10)) {
-      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
-    }
-    console.log();
-  }
+Generate a stub type implementing a Go interface, with one method per
+interface method (including those of embedded interfaces of the same
+package) returning zero values.  Parameter and result names are kept
+from the interface, so "Read(p []byte) (n int, err error)" produces a
+stub using p, n and err; names such as arg0 are only synthesized when
+the interface omits them.
 
-  // Releases grouped
-  if (result.releases_grouped && result.releases_grouped.length > 0) {
-    console.log('Resource Releases:\n');
-    for (const p of result.releases_grouped.slice(0, 10)) {
-      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
-    }
-    console.log();
-  }
+Arguments:
 
-  // Potential leaks
-  if (
-    result.potential_leaks_grouped &&
-    result.potential_leaks_grouped.length > 0
-  ) {
-    console.log(
-      `Potential Resource Leaks (${result.potential_leaks.length}):\n`
-    );
-    for (const p of result.potential_leaks_grouped.slice(0, 10)) {
-      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
-      for (const loc of p.locations.slice(0, 3)) {
-        console.log(`      - ${loc.symbol} - ${loc.filename}:${loc.line}`);
-      }
-      if (p.locations.length > 3) {
-        console.log(`      ... and ${p.locations.length - 3} more locations`);
-      }
-    }
-    console.log();
-  }
+  * --project=[project] - Name of the project (required)
+  * --interface=[name] - Interface name, optionally qualified by package (e.g. store.Reader) (required)
+  * --type=[name] - Name of the stub type (default Stub<Interface>)
+`;
 
-  // Warnings
-  if (result.warnings && result.warnings.length > 0) {
-    console.log(`Warnings (${result.warnings.length}):\n`);
-    for (const w of result.warnings.slice(0, 20)) {
-      console.log(`  [${w.severity}] ${w.description}`);
-      console.log(`    ${w.symbol} - ${w.filename}:${w.start_line}`);
-    }
-    if (result.warnings.length > 20) {
-      console.log(`  ... and ${result.warnings.length - 20} more`);
-    }
-    console.log();
-  }
+const struct_kinds_help = `usage: cb analysis struct-kinds --project=<project_name>
 
-  if (
-    !result.functions_with_resources ||
-    result.functions_with_resources.length === 0
-  ) {
-    console.log('No resource management patterns detected in this project.');
-  }
-};
+Classify Go structs by whether they are plain data or carry behavior.
+This is a heuristic based on the methods of each struct and its exported
+fields:
 
-const analysis_naming = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_project_naming_conventions(project_id);
+  * data - No methods, or only accessors (getters, setters and String)
+  * service - Behavior methods, with no more exported fields than behavior methods
+  * mixed - Behavior methods, but more exported fields than behavior methods
 
-  console.log(`\n=== Naming Convention Analysis: ${project} ===\n`);
+A getter returns a receiver field in a single statement and a setter
+assigns a parameter to one; any other method is behavior.
 
-  // Summary
-  console.log('Summary:');
-  console.log(`  Total Identifiers: ${result.summary.total_identifiers}`);
-  console.log(`  Compliant: ${result.summary.compliant}`);
-  console.log(`  Non-Compliant: ${result.summary.non_compliant}`);
-  console.log(`  With Issues: ${result.summary.with_issues}`);
-  console.log(`  Compliance Rate: ${result.summary.compliance_rate}%`);
-  console.log();
+Arguments:
 
-  // Convention distribution
-  if (
-    result.convention_distribution &&
-    Object.keys(result.convention_distribution).length > 0
-  ) {
-    console.log('Convention Distribution:');
-    for (const [conv, count] of Object.entries(
-      result.convention_distribution
-    )) {
-      const percent = Math.round(
-        (count / result.summary.total_identifiers) * 100
-      );
-      console.log(`  ${conv}: ${count} (${percent}%)`);
-    }
-    console.log();
-  }
+  * --project=[project] - Name of the project (required)
+`;
 
-  // By language
-  if (
-    result.summary.by_language &&
-    Object.keys(result.summary.by_language).length > 0
-  ) {
-    console.log('By Language:');
-    for (const [lang, stats] of Object.entries(result.summary.by_language)) {
-      console.log(
-        `  ${lang}: ${stats.compliant}/${stats.total} compliant (${stats.compliance_rate}%)`
-      );
-    }
-    console.log();
-  }
+const near_misses_help = `usage: cb analysis near-misses --project=<project_name> [--type=<name>] [--interface=<name>] [--max-missing=<count>]
 
-  // Violations
-  if (result.violations && result.violations.length > 0) {
-    console.log(
-      `Naming Convention Violations (${result.violations.length}${result.violations_truncated ? '+' : ''}):\n`
-    );
-    for (const v of result.violations.slice(0, 20)) {
-      console.log(`  * ${v.symbol} (${v.type})`);
-      console.log(`      ${v.filename}:${v.line}`);
-      console.log(
-        `      Expected: ${v.expected.join(' or ')}, Found: ${v.detected.join(', ')}`
-      );
-    }
-    if (result.violations.length > 20) {
-      console.log(`  ... and ${result.violations.length - 20} more violations`);
-    }
-    console.log();
-  }
+Find Go types that almost implement an interface: they declare most of
+its methods but miss some, or declare a method with the interface's name
+and a different signature.  Each near miss lists the exact signatures
+still needed, e.g. "Triangle is 1 method away from implementing Shape:
+missing Perimeter() float64".
 
-  // Issues
-  if (result.issues && result.issues.length > 0) {
-    console.log(
-      `Naming Issues (${result.issues.length}${result.issues_truncated ? '+' : ''}):\n`
-    );
-    for (const i of result.issues.slice(0, 20)) {
-      console.log(`  * ${i.symbol} (${i.type}) - ${i.filename}:${i.line}`);
-      for (const issue of i.issues) {
-        console.log(`      - ${issue.message}`);
-      }
-    }
-    if (result.issues.length > 20) {
-      console.log(`  ... and ${result.issues.length - 20} more issues`);
-    }
-    console.log();
-  }
+With both --type and --interface, report that pair whether or not it is
+close.  Otherwise report every pair within --max-missing methods,
+optionally restricted to one type or interface.
 
-  if (result.summary.total_identifiers === 0) {
-    console.log('No identifiers found in this project.');
-  }
-};
+Arguments:
 
-const analysis_readability = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_project_readability_score(project_id);
+  * --project=[project] - Name of the project (required)
+  * --type=[name] - Type name, optionally qualified by package (e.g. geo.Triangle)
+  * --interface=[name] - Interface name, optionally qualified by package (e.g. geo.Shape)
+  * --max-missing=[count] - Largest number of missing or mismatched methods (default 1)
+`;
 
-  console.log(`\n=== Code Readability Analysis: ${project} ===\n`);
+const build_constraints_help = `usage: cb analysis build-constraints --project=<project_name> [--goos=<os>] [--goarch=<arch>] [--tags=<tags>]
 
-  // Summary
-  console.log('Summary:');
-  console.log(`  Total Functions: ${result.summary.total_functions}`);
-  console.log(`  Average Score: ${result.summary.avg_score}/100`);
-  console.log();
+Show the effective build constraint of each Go file: its //go:build (or
+// +build) expression ANDed with the GOOS and GOARCH implied by its name.
+A file poll_linux_amd64.go with "//go:build !purego" is only built when
+"!purego && linux && amd64" holds.  Recognised suffixes are _GOOS,
+_GOARCH and _GOOS_GOARCH, before an optional _test.
 
-  // Score distribution
-  console.log('Score Distribution:');
-  console.log(
-    `  Excellent (85+): ${result.summary.score_distribution.excellent}`
-  );
-  console.log(`  Good (70-84): ${result.summary.score_distribution.good}`);
-  console.log(
-    `  Acceptable (55-69): ${result.summary.score_distribution.acceptable}`
-  );
-  console.log(`  Poor (40-54): ${result.summary.score_distribution.poor}`);
-  console.log(
-    `  Very Poor (<40): ${result.summary.score_distribution.very_poor}`
-  );
-  console.log();
+With a target, every file is listed with whether it is built for that
+target; the unix tag is set for Unix-like systems, as the go command does.
 
-  // Metrics
-  console.log('Aggregate Metrics:');
-  console.log(
-    `  Avg Function Length: ${result.metrics.avg_function_length} lines`
-  );
-  console.log(`  Avg Nesting Depth: ${result.metrics.avg_nesting_depth}`);
-  console.log(`  Avg Line Length: ${result.metrics.avg_line_length} chars`);
-  console.log(`  Total Magic Numbers: ${result.metrics.total_magic_numbers}`);
-  console.log(
-    `  Total Complex Booleans: ${result.metrics.total_complex_booleans}`
-  );
-  console.log();
+Arguments:
 
-  // By language
-  if (
-    result.summary.by_language &&
-    Object.keys(result.summary.by_language).length > 0
-  ) {
-    console.log('By Language:');
-    for (const [lang, stats] of Object.entries(result.summary.by_language)) {
-      console.log(
-        `  ${lang}: ${stats.count} functions, avg score ${stats.avg_score}`
-      );
-    }
-    console.log();
-  }
+  * --project=[project] - Name of the project (required)
+  * --goos=[os] - Target operating system (e.g. linux)
+  * --goarch=[arch] - Target architecture (e.g. amd64)
+  * --tags=[tags] - Comma separated extra build tags of the target (e.g. purego,go1.22)
+`;
 
-  // Worst functions
-  if (result.worst_functions && result.worst_functions.length > 0) {
-    console.log('Least Readable Functions:\n');
-    for (const f of result.worst_functions.slice(0, 10)) {
-      console.log(`  * ${f.symbol} - Score: ${f.score} (${f.rating})`);
-      console.log(`      ${f.filename}:${f.line} (${f.lines} lines)`);
-      if (f.issues) {
-        const issues = [];
-        if (f.issues.nesting_depth > 4)
-          issues.push(`nesting: ${f.issues.nesting_depth}`);
-        if (f.issues.magic_numbers > 5)
-          issues.push(`magic numbers: ${f.issues.magic_numbers}`);
-        if (f.issues.complex_booleans > 0)
-          issues.push(`complex booleans: ${f.issues.complex_booleans}`);
-        if (f.issues.max_line_length > 120)
-          issues.push(`max line: ${f.issues.max_line_length}`);
-        if (issues.length > 0) {
-          console.log(`      Issues: ${issues.join(', ')}`);
-        }
-      }
-    }
-    console.log();
-  }
+const smell_score_help = `usage: cb analysis smell-score --project=<project_name> [--weights=<code=weight,...>] [--limit=<count>]
 
-  // Best functions
-  if (result.best_functions && result.best_functions.length > 0) {
-    console.log('Most Readable Functions:\n');
-    for (const f of result.best_functions.slice(0, 5)) {
-      console.log(`  * ${f.symbol} - Score: ${f.score} (${f.rating})`);
-      console.log(`      ${f.filename}:${f.line} (${f.lines} lines)`);
-    }
-    console.log();
-  }
+Rank Go symbols and files by a code smell score that rolls up the other
+diagnostics, to decide where to refactor first.
 
-  if (result.summary.total_functions === 0) {
-    console.log('No functions found in this project.');
-  }
-};
+Metric diagnostics score their relative overshoot of a threshold,
+max(0, value - threshold) / threshold; count diagnostics score their
+number of occurrences.  A symbol scores the weighted sum of its
+diagnostics, and a file the sum of its symbols.
 
-const analysis_patterns = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_project_design_patterns(project_id);
+Diagnostics (default weight):
 
-  console.log(`\n=== Design Pattern Analysis: ${project} ===\n`);
+  * complexity (1) - Cyclomatic complexity above 15
+  * length (1) - Functions longer than 50 lines
+  * nesting (1) - Blocks nested deeper than 4
+  * parameters (0.5) - More than 5 parameters
+  * unchecked_error (2) - Error returning package calls made as statements
+  * discarded_error (1) - Last results of calls assigned to _
+  * panic (1) - Calls of panic
+  * misplaced_context (1) - context.Context parameters that are not first
+  * undocumented (0.5) - Exported symbols without doc comments
 
-  // Summary
-  console.log('Summary:');
-  console.log(`  Total Functions Analyzed: ${result.summary.total_functions}`);
-  console.log(
-    `  Functions with Patterns: ${result.summary.functions_with_patterns}`
-  );
-  console.log(
-    `  Total Patterns Detected: ${result.summary.total_patterns_detected}`
-  );
-  console.log();
+Arguments:
 
-  // By category
-  console.log('Patterns by Category:');
-  console.log(
-    `  Creational: ${result.summary.patterns_by_category.creational}`
-  );
-  console.log(
-    `  Structural: ${result.summary.patterns_by_category.structural}`
-  );
-  console.log(
-    `  Behavioral: ${result.summary.patterns_by_category.behavioral}`
-  );
-  console.log(
-    `  Anti-patterns: ${result.summary.patterns_by_category['anti-pattern']}`
-  );
-  console.log();
+  * --project=[project] - Name of the project (required)
+  * --weights=[weights] - Comma separated weights overriding the defaults (e.g. panic=3,undocumented=0)
+  * --limit=[count] - Only show the worst symbols and files (default 20)
+`;
 
-  // Error handling summary
-  if (result.summary.error_handling) {
-    console.log('Error Handling:');
-    console.log(
-      `  Try/Catch Blocks: ${result.summary.error_handling.total_try_catch}`
-    );
-    console.log(
-      `  Empty Catch Blocks: ${result.summary.error_handling.total_empty_catch}`
-    );
-    console.log(
-      `  Finally/Defer Blocks: ${result.summary.error_handling.total_finally}`
-    );
-    console.log(
-      `  Functions with Error Handling: ${result.summary.error_handling.functions_with_error_handling}`
-    );
-    console.log();
-  }
+const method_shadowing_help = `usage: cb analysis method-shadowing --project=<project_name> [--accidental]
 
-  // Detected patterns
-  if (Object.keys(result.patterns).length > 0) {
-    console.log('Detected Patterns:\n');
-    for (const [id, data] of Object.entries(result.patterns)) {
-      console.log(
-        `  * ${data.name} (${data.category}): ${data.count} occurrences`
-      );
-      for (const loc of data.locations.slice(0, 3)) {
-        console.log(`      - ${loc.symbol} - ${loc.filename}:${loc.line}`);
+Find the methods and fields of Go structs that shadow a method promoted
+from an embedded type (Employee declaring Name() shadows User.Name()).
+
+Kinds:
+
+  * override - The shadowing method has the signature of the promoted one
+  * collision - A method with another signature, or a field, hides the
+    promoted method; the interfaces it no longer implements are listed
+
+Overrides are intentional.  Collisions are reported as accidental unless
+the method calls the embedded one (e.User.Name()) or its doc comment
+names the embedded type or says it overrides or shadows it.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --accidental - Only show accidental collisions
+`;
+
+const conversions_help = `usage: cb analysis conversions --project=<project_name> [--unchecked]
+
+List the type conversions (Fahrenheit(c*9/5 + 32), []byte(s)) and type
+assertions (x.(T)) of each Go function.
+
+Single-value assertions such as c := e.(Click) panic when e is not a
+Click, so they are reported separately with the comma-ok form to use
+instead (c, ok := e.(Click)).  Comma-ok assertions and type switches
+cannot panic.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --unchecked - Only show functions with unchecked assertions
+`;
+
+const repro_help = `usage: cb analysis repro --project=<project_name> --symbol=<name>
+
+Extract a Go symbol and the declarations it depends on into a single,
+self-contained Go file, for sharing a bug reproduction.
+
+Declarations of the symbol's package are copied with their doc comments.
+Methods are only copied when they are called, or when a copied interface
+needs them.  Dependencies on other packages are stubbed: project packages
+get renamed copies (units.Length becomes units_Length) and functions that
+panic, other packages get opaque placeholders.  Standard library imports
+are kept.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --symbol=[name] - Symbol (Name or Type.Method), optionally qualified by package (required)
+`;
+
+const concurrency_hints_help = `usage: cb analysis concurrency-hints --project=<project_name> [--hint=<hint>]
+
+Mark each Go function with a best-effort hint of whether it is safe to
+call from several goroutines at once:
+
+  * safe - Only local state is used
+  * unsafe - A package variable, a pointer, slice or map argument, or a
+    pointer receiver is written without a lock, directly or through a
+    called function of the package
+  * unknown - Package variables are read, or written under a lock
+
+Doc comments claiming safety for concurrent use on unsafe functions are
+reported as conflicts.  Calls into other packages are not followed.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --hint=[hint] - Only show functions with this hint (safe, unknown or unsafe)
+`;
+
+const embedders_help = `usage: cb analysis embedders --project=<project_name> --type=<name> [--transitive] [--unexported]
+
+List the public struct and interface types that embed a Go type, for
+impact analysis before changing the API of the embedded type: its methods
+are promoted to every embedder.
+
+With --transitive, types embedding the type through other types are
+listed too, with their embedding path (Manager > Employee > User).
+Embedding across project packages (staff.User) is followed.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --type=[name] - Embedded type, optionally qualified by package (required)
+  * --transitive - Include types embedding the type through other types
+  * --unexported - Include unexported embedders
+`;
+
+const escapes_help = `usage: cb analysis escapes --project=<project_name> [--no-composites]
+
+Find the Go functions returning the address of one of their variables
+(return &cfg).  This is legal Go, escape analysis moves the variable to
+the heap, and is reported as informational for those learning the
+language and for allocation sensitive code.
+
+Each escape is classified by what it points to:
+
+  * local - A variable declared in the function
+  * param - A parameter or value receiver, a copy of the caller's value
+  * range - A range variable, a copy of the element rather than the
+    element itself
+  * composite - A composite literal (&Config{...}), the idiomatic way to
+    allocate on the heap
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --no-composites - Do not list composite literals
+`;
+
+const imports_help = `usage: cb analysis imports --project=<project_name> [--unused]
+
+Cross-reference the imports of each Go file against the qualified
+identifiers (fmt.Println) used in it, and report the imports that are
+never used with their span.  Unused imports are a compile error in Go;
+this catches them before compiling, in editors and in generated code.
+
+Blank (_ "embed") and dot (. "math") imports are always used.  Imports
+of packages outside the standard library and the project are referred
+to by a name guessed from their path (gopkg.in/yaml.v3 is yaml).
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --unused - Only show files with unused imports
+`;
+
+const loop_captures_help = `usage: cb analysis loop-captures --project=<project_name> [--go-version=<version>] [--include-fixed]
+
+Find the goroutines launched in a loop with a function literal capturing
+a loop variable:
+
+  for _, v := range items {
+    go func() { use(v) }()
+  }
+
+Before Go 1.22 a loop declares its variables once, so every goroutine
+shares the variable and may see the value of a later iteration.  Go
+1.22 declares loop variables once per iteration and fixes the bug for
+modules declaring go 1.22 or later, and for files built with a
+//go:build go1.22 constraint.  Give the target Go version to suppress
+the captures it fixes.
+
+Goroutines are launched with go statements or groups (g.Go(func...),
+as with errgroup).  Variables passed as an argument of the function
+literal or copied before the launch (v := v) are not captured.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --go-version=[version] - Target Go version (e.g. 1.21)
+  * --include-fixed - Include captures fixed by the Go version
+`;
+
+const conversions_between_help = `usage: cb analysis conversions-between --project=<project_name> --from=<type> --to=<type>
+
+Find every conversion between two types across the project, in both
+directions: To(x) where x is a From, and From(x) where x is a To.  This
+is for auditing unit confusion, such as a Celsius value reinterpreted as
+Fahrenheit without the formula.
+
+Types are named alone (Celsius) or qualified by package
+(temperature.Celsius); predeclared types (float64) are accepted.  The
+type converted from is inferred, best effort, from the declared types of
+variables, fields, constants and function results; conversions whose
+operand type cannot be inferred are counted as unresolved.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --from=[type] - Type converted from (required)
+  * --to=[type] - Type converted to (required)
+`;
+
+const method_sets_help = `usage: cb analysis method-sets --project=<project_name> --type=<type>
+
+Show the method sets of a type for values and for pointers.  The value
+method set of T has the methods with value receivers, and the methods
+promoted through embedded pointers; the pointer method set of *T has
+every method.  Methods with pointer receivers are listed as pointer only.
+
+The interfaces of the project satisfied by *T are listed with whether a
+T value satisfies them too, which is the difference between assigning T
+or &T to an interface variable.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --type=[type] - Type name, optionally qualified by package (required)
+`;
+
+const interface_widths_help = `usage: cb analysis interface-widths --project=<project_name> [--max-methods=<n>]
+
+Classify the interfaces of a project by the size of their method set,
+including the methods of embedded interfaces:
+
+  * narrow - exactly one method (io.Reader, fmt.Stringer), the idiomatic kind
+  * regular - a few methods
+  * wide - more than --max-methods methods, a design smell: the bigger
+    the interface, the weaker the abstraction
+  * empty - no methods (interface{})
+  * constraint - a type set (~int | ~float64), usable as a type constraint only
+
+Embedded interfaces of the project and common standard library
+interfaces (io, fmt, sort, context) are resolved; when an embedded
+interface cannot be, the method count is a lower bound shown with a +.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --max-methods=[n] - Largest number of methods of an interface that is not wide (default 10)
+`;
+
+const struct_tags_help = `usage: cb analysis struct-tags --project=<project_name> [--require=<keys>] [--naming=<rules>] [--include-embedded] [--include-unexported] [--all-structs]
+
+Validate struct tags against a tag policy, for teams enforcing tag
+conventions: the tag keys every field must have, and a naming rule for
+the names of each key (snake_case, camelCase, PascalCase, kebab_case,
+...).  The default policy is a snake_case json name on every field.
+
+Fields ignored by a key (json:"-") satisfy it; fields without a name in
+their tag (json:",omitempty") are named after the field.  Tags not in
+the key:"value" format are reported too.  Only structs with a tag of one
+of the policy's keys are checked, unless --all-structs is given, and
+embedded and unexported fields are skipped unless asked for.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --require=[keys] - Comma separated tag keys every field must have (default json)
+  * --naming=[rules] - Comma separated key:rule naming rules (default json:snake_case)
+  * --include-embedded - Check embedded fields
+  * --include-unexported - Check unexported fields
+  * --all-structs - Check structs without any tag of the policy's keys
+`;
+
+const coupling_help = `usage: cb analysis coupling --project=<project_name> [--limit=<n>] [--include-tests]
+
+Compute the fan-out (distinct functions called) and fan-in (distinct
+callers) of every function and method from the call graph of the whole
+project, the classic coupling metrics.  Entry points such as main have a
+high fan-out and widely used helpers a high fan-in; functions with both
+are hotspots, listed by combined fan-in and fan-out.
+
+Only calls resolved to functions of the project count.  Test files are
+left out unless --include-tests is given, so that tests do not count as
+callers.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --limit=[n] - Number of most coupled functions to list (default 20)
+  * --include-tests - Include the functions of test files
+`;
+
+const unsafe_usage_help = `usage: cb analysis unsafe-usage --project=<project_name> [--kind=<kinds>]
+
+Find where Go code steps outside of the type and memory safety of the
+language, to audit "where do we do unsafe things".  Each file and function
+is flagged with the kinds of usage in it, and every import, call and
+directive site is reported with its severity:
+
+  * unsafe - Uses of the unsafe package (unsafe.Pointer, unsafe.Add);
+    the layout functions (Sizeof, Offsetof, Alignof) are low severity
+  * reflect - Uses of the reflect package; SliceHeader, StringHeader,
+    NewAt, UnsafeAddr and UnsafePointer are high severity
+  * linkname - //go:linkname directives
+  * assembly - Functions declared without a body
+
+Package uses are resolved through the imports of each file, so that
+variables, fields and selectors named unsafe are not reported.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --kind=[kinds] - Comma separated kinds to report (default: all)
+`;
+
+const call_chain_help = `usage: cb analysis call-chain --project=<project_name> [--root=<function>] [--max-depth=<n>]
+
+Find the longest call chain from an entry point: the deepest path of calls
+from main (or the given root) through the call graph, in order, with its
+length.  Deep chains make code hard to follow and can indicate
+over-layering.
+
+Calls back to a function already on the path (recursion) are not followed
+but reported as cycles, and the functions making them are marked as
+recursive.  Chains longer than the maximum depth are truncated.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --root=[function] - Root function, as name (Serve), qualified name
+    (server.Serve, Server.Start) or id (cmd/app:main) (default: the main
+    functions of main packages)
+  * --max-depth=[n] - Maximum number of calls in a chain (default 50)
+`;
+
+const inline_hints_help = `usage: cb analysis inline-hints --project=<project_name> [--budget=<n>] [--inlinable]
+
+Flag the functions likely to be inlined by the Go compiler: small,
+non-recursive functions with a single return and no defer, recover,
+closure, loop, goroutine or select.  This loosely follows the heuristics
+of the gc inliner, which inlines functions costing less than a budget of
+80 syntax tree nodes; it is a heuristic, not the decision of the compiler
+(see go build -gcflags=-m for that).
+
+Functions that are not candidates are listed with their reasons: cost,
+recursive, multiple_returns, defer, recover, closure, loop, goroutine,
+select, noinline (a //go:noinline directive) or no_body.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --budget=[n] - Inlining budget in syntax tree nodes (default 80)
+  * --inlinable - Only list the inlining candidates
+`;
+
+const unused_interfaces_help = `usage: cb analysis unused-interfaces --project=<project_name> [--unexported]
+
+Find interface pollution: interfaces never used as the type of a
+parameter, result, variable or field, nor embedded, used as a type
+constraint or in an expression such as a type assertion.  Compile-time
+assertions (var _ Shape = (*Square)(nil)) do not count as uses.
+
+Unused unexported interfaces are reported as warnings.  Unused exported
+interfaces may be part of the public API of their package and are
+reported as info.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --unexported - Only list the unexported interfaces
+`;
+
+const type_complexity_help = `usage: cb analysis type-complexity --project=<project_name> [--limit=<n>] [--promoted]
+
+Rank the types by the total cyclomatic complexity of their methods, to
+find the "god types" concentrating the logic of a package that should be
+split.  Each type is listed with its share of the complexity of all the
+methods of the project, its number of methods and its most complex
+methods.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --limit=[n] - Number of types to list (default 10)
+  * --promoted - Include the methods promoted from embedded types
+`;
+
+const format_calls_help = `usage: cb analysis format-calls --project=<project_name> [--mismatched]
+
+Check the format strings of the Printf-style calls of the fmt and log
+packages (fmt.Printf, fmt.Sprintf, fmt.Errorf, fmt.Fprintf, log.Fatalf,
+...) against the number of arguments of the call, as go vet does.  %%
+escapes, * widths and precisions and argument indexes (%[1]d) are
+handled.
+
+Issues are missing_args (more verbs than arguments), extra_args (more
+arguments than verbs) and no_verb (a format ending with a %).  Calls
+whose format is not a string literal, or whose arguments are spread
+(args...), are listed as unchecked.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --mismatched - Only list the calls with issues
+`;
+
+const copy_locks_help = `usage: cb analysis copy-locks --project=<project_name>
+
+Find the types that must not be copied, like the copylocks check of go
+vet: types containing a sync.Mutex, RWMutex, WaitGroup, Cond, Once, Map,
+Pool or sync/atomic value, directly or through struct fields and arrays.
+Project types whose pointer has Lock and Unlock methods are locks too,
+which covers the noCopy marker idiom.
+
+Value receivers and by-value parameters of these types are reported, as
+they copy the lock on every call.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+`;
+
+const field_types_help = `usage: cb analysis field-types --project=<project_name> [--resolution=<resolution>] [--embedded]
+
+Count how often each type is used by the fields of the structs of the
+project (string, int, time.Time, custom types), and list the field names
+declared with different types, such as an ID that is an int in one
+struct and an int64 in another.  This helps standardize the types of a
+schema.
+
+Types are normalized before they are counted, following the resolution:
+
+  * none - As written
+  * alias - Aliases (type Timestamp = time.Time) are replaced by their type
+  * underlying - Defined types (type UserID int64) are replaced by their
+    underlying type too, except structs and interfaces
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --resolution=[resolution] - Type resolution: none, alias or underlying (default alias)
+  * --embedded - Count embedded fields
+`;
+
+const method_implementers_help = `usage: cb analysis method-implementers --project=<project_name> --name=<name> --signature=<signature>
+
+Find the types declaring a method with a given name and signature, even
+when no interface states it: the types with a String() string method,
+for instance, show a convention of the code base.  Signatures match on
+their parameter and result types; parameter and result names are
+ignored.  Types declaring a method of that name with another signature
+are listed apart, as they break the convention.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --name=[name] - Method name (required)
+  * --signature=[signature] - Method signature, such as "() string" or "(p []byte) (int, error)" (required)
+`;
+
+const deprecated_calls_help = `usage: cb analysis deprecated-calls --project=<project_name>
+
+Find the calls to deprecated functions of the Go standard library, such
+as ioutil.ReadFile or rand.Seed, with the Go version deprecating them and
+their recommended replacement.  Calls are resolved through the imports of
+each file, so renamed imports are followed.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+`;
+
+const interface_guideline_help = `usage: cb analysis interface-guideline --project=<project_name> [--guideline=<guideline>]
+
+Check the exported functions and methods of a project against the Go
+guideline "accept interfaces, return structs".  This is a heuristic:
+
+  * accept_interfaces - A parameter of a project struct type that is
+    only used to call methods could be an interface, decoupling callers
+    from the implementation.  The interfaces of the project satisfied by
+    the type and declaring the methods called are suggested.
+  * return_structs - A result of a project interface type (other than
+    error) could be the concrete type when every return statement
+    returns the same exported struct.
+
+Parameters whose fields are read or which are passed on, factories
+returning several types and unexported implementations are not reported.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --guideline=[guideline] - Only check one guideline: accept_interfaces or return_structs
+`;
+
+const function_values_help = `usage: cb analysis function-values --project=<project_name> [--values-only]
+
+Find the Go functions and methods of a project used as values rather than
+called: passed as arguments, assigned to variables or fields, stored in
+composite literals (handler maps, struct fields) or returned.  A function
+used as a value may be called from anywhere the value flows, so the call
+graph alone misses its callers; treat such functions as reachable.
+
+Each function is reported with its number of direct calls and its value
+sites, classified as argument, assignment, field, composite, return or
+other.  Names shadowed by a parameter or local variable are left out.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --values-only - Only list functions used as values
+`;
+
+const magic_numbers_help = `usage: cb analysis magic-numbers --project=<project_name> [--min=<count>] [--ignore=<numbers>]
+
+Find the magic numbers of a project: number values used inline at least
+--min times rather than through a named constant.  Uses are grouped by
+value, so 4096 and 0x1000 are the same number, and only inline uses are
+counted: constant and package variable declarations are already named.
+Each number suggests extracting a named constant, or using the constants
+already declared with that value.  Test files are left out.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --min=[count] - Number of uses from which a number is magic (default 3)
+  * --ignore=[numbers] - Comma-separated numbers never reported (default 0,1,-1,2)
+`;
+
+const error_results_help = `usage: cb analysis error-results --project=<project_name>
+
+Check that the functions of a project returning an error return it as
+their last result, as Go convention requires: (float64, error), not
+(error, float64).  Functions, methods, interface methods and function
+types are checked.  Signatures returning several errors are flagged too,
+even when one of them is last.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+`;
+
+const options_structs_help = `usage: cb analysis options-structs --project=<project_name> [--min-fields=<count>]
+
+Find the Go functions and methods whose sole or last parameter is an
+options struct, and link them to it, so that docs can present the
+available options together.  A struct of the project passed by value or
+by pointer is an options struct when its name ends with Options, Opts,
+Config, Settings or Params, or when it is a plain data struct (only
+exported fields, no methods) with at least --min-fields fields.
+
+Each options struct is listed with its options (fields with their type
+and doc) and the functions taking it.  Variadic functional options, the
+methods of the options struct itself and test files are left out.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --min-fields=[count] - Exported fields from which a data struct is an options struct (default 4)
+`;
+
+const handlers_help = `usage: cb analysis handlers --project=<project_name> --shape=<shape> [--functions-only]
+
+Find the Go functions and methods of a project whose signature matches a
+handler shape, whatever their name, to discover the handlers of a
+middleware or pipeline framework and the routes registering them:
+
+  cb analysis handlers --project=api --shape="func(http.ResponseWriter, *http.Request)"
+  cb analysis handlers --project=jobs --shape="func(context.Context) error"
+
+The shape is a function signature, with or without the func keyword.
+Parameter and result names are ignored, and a type _ matches any type.
+Renamed imports are followed, and the types of a package may be
+qualified with its name (*web.Request).  Test files are left out.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --shape=[shape] - Handler shape (required)
+  * --functions-only - Leave out methods
+`;
+
+const recursive_structs_help = `usage: cb analysis recursive-structs --project=<project_name>
+
+Find the recursive structs of a project: the linked lists, trees and
+graphs whose fields reference the struct itself, through pointers,
+slices, maps or channels (Next *Node, Children []*Node).
+
+  * direct - A field of the struct references the struct itself
+  * mutual - The struct references itself only through a group of other
+    structs (a Dir holding Files holding their parent Dir)
+
+Each struct is listed with its self-referencing fields and the other
+structs of its group.  Structs of test files are left out.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+`;
+
+const builtin_shadowing_help = `usage: cb analysis builtin-shadowing --project=<project_name> [--problematic]
+
+Find the declarations named after a Go builtin (len, cap, new, make,
+error, string, nil), which hide the builtin wherever they are in scope.
+Each is reported with the builtin it hides, and why it is problematic:
+
+  * package - A package level declaration hides the builtin in every file
+  * used - The scope also calls or refers to the builtin (len(s),
+    string(b), []string), which now refers to the declaration
+  * constant - true, false, nil or iota is shadowed
+
+Other shadowing is harmless: a local len never calling the builtin.
+Locals are in scope from the end of their statement, so len := len(s)
+reads the builtin.  Methods and struct fields shadow nothing.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --problematic - Only list problematic shadowing
+`;
+
+const state_machines_help = `usage: cb analysis state-machines --project=<project_name> [--type=<type>] [--dot]
+
+Infer the state machines implied by Go lifecycle types: structs whose
+methods (Start, Pause, Stop) assign the constants of a state field, a
+field of an enum type or named state, status, phase or stage.  Each
+assignment is a transition, from the states allowed by the guards before
+or around it (if s.state != Idle { return }, switch s.state).  A machine
+is complete when every state is reachable from the initial state, set by
+a composite literal or the zero value of the enum.
+
+This is best-effort documentation: guards the analysis does not
+understand allow any state.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --type=[type] - Only infer the state machine of this type
+  * --dot - Print each state machine as a Graphviz digraph
+`;
+
+const package_metrics_help = `usage: cb analysis package-metrics --project=<project_name> [--threshold=<distance>]
+
+Compute the Martin metrics of each Go package from the imports between
+the packages of the project:
+
+  * Ca / Ce - Afferent and efferent coupling: the project packages
+    depending on the package, and those it depends on
+  * I - Instability, Ce / (Ca + Ce)
+  * A - Abstractness, the share of interfaces among its types
+  * D - Distance from the main sequence, |A + I - 1|
+
+Packages farther than the threshold from the main sequence are in the
+zone of pain (concrete and stable) or of uselessness (abstract and
+unstable).  Imports outside the project and test files do not count.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --threshold=[distance] - Distance above which a package is in a zone (default 0.5)
+`;
+
+const param_names_help = `usage: cb analysis param-names --project=<project_name> [--acceptable=<names>] [--reason=<reasons>]
+
+Find the exported Go functions and methods whose parameter names
+documentation cannot refer to:
+
+  * unnamed - The parameter list has no names (Add(int, int))
+  * blank - The parameter is named _
+  * numbered - A placeholder with a number (arg0, p1)
+  * short - A single letter (Add(a, b int)), a borderline case
+
+Idiomatic short names are accepted: i, j, k, n, ok, err, ctx, id, fn, db
+and tx by default, and single letters for their conventional types (w
+http.ResponseWriter, r *http.Request, t *testing.T, p []byte).
+Receivers are never flagged.  Exported functions, methods of exported
+types and methods of exported interfaces are checked.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --acceptable=[names] - Comma separated short names to accept, replacing the default list
+  * --reason=[reasons] - Comma separated reasons to report
+`;
+
+const state_aliasing_help = `usage: cb analysis state-aliasing --project=<project_name> [--include-unexported]
+
+Find the Go methods returning an unexported slice or map field of their
+receiver rather than a copy of it: callers share the backing array or
+the map and can change the state of the value from outside.
+
+  * field - The field itself (return c.items)
+  * subslice - A slice of the field (return c.items[1:])
+  * local - A local variable holding the field
+
+Returns of a copy (slices.Clone, maps.Clone, append to nil, copy or a
+filling loop) are listed apart.  Returning an element is not reported.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --include-unexported - Also check unexported methods
+`;
+
+const unused_interface_methods_help = `usage: cb analysis unused-interface-methods --project=<project_name> [--unexported] [--confidence=<level>]
+
+Find the interface methods no caller invokes through their interface,
+candidates for removal from the abstraction.  Calls are tracked through
+the parameters, variables, fields, collections, type assertions and type
+parameters typed with the interface; a call through an interface
+embedding another counts for the one declaring the method.  Calls in
+test files do not count.
+
+Tracking is heuristic.  A method has a low confidence when it may be
+used after all:
+
+  * exported - The interface is exported, other modules may call it
+  * struct_embedding - The interface is embedded in a struct
+  * reflection - The project calls methods through reflect
+  * standard_method - The method is that of a standard library interface
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --unexported - Only report the methods of unexported interfaces
+  * --confidence=[level] - Only report the methods of this confidence (high or low)
+`;
+
+const predicate_names_help = `usage: cb analysis predicate-names --project=<project_name> [--prefix=<prefixes>] [--acceptable=<names>]
+
+Find the exported Go functions and methods returning a single bool whose
+names do not read as predicates: a name should start with Is, Has, Can
+or Should followed by a word (IsEmpty, not Empty or Issue).  Each
+function comes with a suggested rename (Empty to IsEmpty, AllowWrite to
+CanWrite, KeyExists to HasKey).
+
+Comma-ok results ((string, bool)) and (bool, error) are not checked.
+Idiomatic names (Less, Next, Equal, Contains, Match, Exists) are accepted
+by default.  Exported functions, methods of exported types and methods
+of exported interfaces are checked.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --prefix=[prefixes] - Comma separated predicate prefixes, replacing the default list
+  * --acceptable=[names] - Comma separated names to accept, replacing the default list
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const analysis_dashboard = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await get_analysis_dashboard(project_id);
+
+  console.log(`\n=== Analysis Dashboard: ${project} ===\n`);
+  console.log(`Health Score: ${result.healthScore}/100\n`);
+
+  console.log('Summary:');
+  console.log(`  Dead Code: ${result.deadCode?.count || 0} issues`);
+  console.log(`  Duplications: ${result.duplication?.count || 0} found`);
+  console.log(`  Security: ${result.security?.count || 0} vulnerabilities`);
+  console.log(`  Code Smells: ${result.codeSmells?.count || 0} detected`);
+  console.log(
+    `  Documentation: ${result.documentation?.coverage || 0}% coverage`
+  );
+};
+
+const analysis_dead_code = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await detect_dead_code(project_id);
+
+  console.log(`\n=== Dead Code Analysis: ${project} ===\n`);
+
+  if (!result.deadCode || result.deadCode.length === 0) {
+    console.log('No potentially dead code detected.');
+    return;
+  }
+
+  console.log(
+    `Found ${result.deadCode.length} potentially unreferenced functions:\n`
+  );
+  for (const item of result.deadCode) {
+    console.log(`  * ${item.symbol} - ${item.filename}:${item.start_line}`);
+  }
+};
+
+const analysis_duplication = async ({ project, threshold = 0.7 }) => {
+  const project_id = await get_project_id(project);
+  const result = await detect_code_duplication(project_id, threshold);
+
+  console.log(`\n=== Code Duplication Analysis: ${project} ===\n`);
+  console.log(`Threshold: ${threshold}\n`);
+
+  if (!result.duplicates || result.duplicates.length === 0) {
+    console.log('No code duplication detected.');
+    return;
+  }
+
+  console.log(`Found ${result.duplicates.length} duplicate pairs:\n`);
+  for (const dup of result.duplicates) {
+    console.log(`  Similarity: ${(dup.similarity * 100).toFixed(1)}%`);
+    console.log(
+      `    - ${dup.func1.symbol} (${dup.func1.filename}:${dup.func1.start_line})`
+    );
+    console.log(
+      `    - ${dup.func2.symbol} (${dup.func2.filename}:${dup.func2.start_line})`
+    );
+    console.log();
+  }
+};
+
+const analysis_dependencies = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_dependencies(project_id);
+
+  console.log(`\n=== Dependency Analysis: ${project} ===\n`);
+
+  if (result.circularDependencies && result.circularDependencies.length > 0) {
+    console.log(
+      `Circular Dependencies (${result.circularDependencies.length}):\n`
+    );
+    for (const cycle of result.circularDependencies) {
+      console.log(`  * ${cycle.join(' -> ')}`);
+    }
+    console.log();
+  } else {
+    console.log('No circular dependencies detected.\n');
+  }
+
+  if (result.dependencies) {
+    console.log(`File Dependencies:\n`);
+    for (const [file, deps] of Object.entries(result.dependencies)) {
+      if (deps.length > 0) {
+        console.log(`  ${file}:`);
+        for (const dep of deps) {
+          console.log(`    -> ${dep}`);
+        }
+      }
+    }
+  }
+};
+
+const analysis_security = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await detect_security_vulnerabilities(project_id);
+
+  console.log(`\n=== Security Analysis: ${project} ===\n`);
+
+  if (!result.vulnerabilities || result.vulnerabilities.length === 0) {
+    console.log('No potential security vulnerabilities detected.');
+    return;
+  }
+
+  console.log(
+    `Found ${result.vulnerabilities.length} potential vulnerabilities:\n`
+  );
+  for (const vuln of result.vulnerabilities) {
+    console.log(`  [${vuln.severity}] ${vuln.type}`);
+    console.log(`    ${vuln.symbol} - ${vuln.filename}:${vuln.line}`);
+    console.log(`    ${vuln.description}\n`);
+  }
+};
+
+const analysis_metrics = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await get_code_metrics(project_id);
+
+  console.log(`\n=== Code Metrics: ${project} ===\n`);
+
+  if (result.summary) {
+    console.log('Summary:');
+    console.log(`  Total Functions: ${result.summary.totalFunctions || 0}`);
+    console.log(`  Total Lines: ${result.summary.totalLines || 0}`);
+    console.log(
+      `  Average Complexity: ${(result.summary.avgComplexity || 0).toFixed(2)}`
+    );
+    console.log(
+      `  Average Lines/Function: ${(result.summary.avgLinesPerFunction || 0).toFixed(1)}`
+    );
+    console.log();
+  }
+
+  if (result.highComplexity && result.highComplexity.length > 0) {
+    console.log('High Complexity Functions:\n');
+    for (const func of result.highComplexity) {
+      console.log(
+        `  * ${func.symbol} (complexity: ${func.complexity}) - ${func.filename}:${func.start_line}`
+      );
+    }
+  }
+};
+
+const analysis_smells = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await detect_code_smells(project_id);
+
+  console.log(`\n=== Code Smells: ${project} ===\n`);
+
+  if (!result.smells || result.smells.length === 0) {
+    console.log('No code smells detected.');
+    return;
+  }
+
+  // Group by type
+  const grouped = {};
+  for (const smell of result.smells) {
+    if (!grouped[smell.type]) {
+      grouped[smell.type] = [];
+    }
+    grouped[smell.type].push(smell);
+  }
+
+  for (const [type, smells] of Object.entries(grouped)) {
+    console.log(`${type} (${smells.length}):\n`);
+    for (const smell of smells) {
+      console.log(
+        `  * ${smell.symbol} - ${smell.filename}:${smell.start_line}`
+      );
+      if (smell.details) {
+        console.log(`    ${smell.details}`);
+      }
+    }
+    console.log();
+  }
+};
+
+const analysis_types = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_types(project_id);
+
+  console.log(`\n=== Type Coverage Analysis: ${project} ===\n`);
+
+  if (result.summary) {
+    console.log('Summary:');
+    console.log(`  Total Functions: ${result.summary.total || 0}`);
+    console.log(`  With Type Hints: ${result.summary.typed || 0}`);
+    console.log(`  Without Type Hints: ${result.summary.untyped || 0}`);
+    console.log(`  Coverage: ${(result.summary.coverage || 0).toFixed(1)}%`);
+    console.log();
+  }
+
+  if (result.untyped && result.untyped.length > 0) {
+    console.log('Functions Without Type Hints:\n');
+    for (const func of result.untyped.slice(0, 20)) {
+      console.log(`  * ${func.symbol} - ${func.filename}:${func.start_line}`);
+    }
+    if (result.untyped.length > 20) {
+      console.log(`  ... and ${result.untyped.length - 20} more`);
+    }
+  }
+};
+
+const analysis_api = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_api_surface(project_id);
+
+  console.log(`\n=== API Surface Analysis: ${project} ===\n`);
+
+  if (result.summary) {
+    console.log('Summary:');
+    console.log(`  Total Functions: ${result.summary.total || 0}`);
+    console.log(`  Public: ${result.summary.public || 0}`);
+    console.log(`  Private: ${result.summary.private || 0}`);
+    console.log(`  Entry Points: ${result.summary.entryPoints || 0}`);
+    console.log();
+  }
+
+  if (result.entryPoints && result.entryPoints.length > 0) {
+    console.log('Entry Points:\n');
+    for (const func of result.entryPoints) {
+      console.log(`  * ${func.symbol} - ${func.filename}:${func.start_line}`);
+    }
+  }
+};
+
+const analysis_docs = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_documentation(project_id);
+
+  console.log(`\n=== Documentation Coverage: ${project} ===\n`);
+
+  if (result.summary) {
+    console.log('Summary:');
+    console.log(`  Total Functions: ${result.summary.total || 0}`);
+    console.log(`  Documented: ${result.summary.documented || 0}`);
+    console.log(`  Undocumented: ${result.summary.undocumented || 0}`);
+    console.log(`  Coverage: ${(result.summary.coverage || 0).toFixed(1)}%`);
+    console.log();
+  }
+
+  if (result.undocumented && result.undocumented.length > 0) {
+    console.log('Undocumented Functions:\n');
+    for (const func of result.undocumented.slice(0, 20)) {
+      console.log(`  * ${func.symbol} - ${func.filename}:${func.start_line}`);
+    }
+    if (result.undocumented.length > 20) {
+      console.log(`  ... and ${result.undocumented.length - 20} more`);
+    }
+  }
+};
+
+const analysis_scope = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_variable_scope(project_id);
+
+  console.log(`\n=== Variable Scope Analysis: ${project} ===\n`);
+
+  if (result.globals && result.globals.length > 0) {
+    console.log(`Global Variables (${result.globals.length}):\n`);
+    for (const g of result.globals.slice(0, 20)) {
+      console.log(`  * ${g.name} - ${g.filename}:${g.line}`);
+    }
+    if (result.globals.length > 20) {
+      console.log(`  ... and ${result.globals.length - 20} more`);
+    }
+    console.log();
+  }
+
+  if (result.shadowing && result.shadowing.length > 0) {
+    console.log(`Variable Shadowing (${result.shadowing.length}):\n`);
+    for (const s of result.shadowing) {
+      console.log(
+        `  * ${s.variable} in ${s.function} - ${s.filename}:${s.line}`
+      );
+    }
+  }
+
+  if (
+    (!result.globals || result.globals.length === 0) &&
+    (!result.shadowing || result.shadowing.length === 0)
+  ) {
+    console.log('No scope issues detected.');
+  }
+};
+
+const analysis_concurrency = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_concurrency(project_id);
+
+  console.log(`\n=== Concurrency Analysis: ${project} ===\n`);
+
+  // Summary
+  if (result.summary) {
+    console.log('Summary:');
+    console.log(`  Functions Analyzed: ${result.summary.functions_analyzed}`);
+    console.log(
+      `  Functions with Concurrency: ${result.summary.functions_with_concurrency} (${result.summary.concurrency_percentage}%)`
+    );
+    console.log(
+      `  Files with Concurrency: ${result.summary.files_with_concurrency}`
+    );
+    console.log(`  Languages: ${result.summary.languages.join(', ') || 'N/A'}`);
+    console.log();
+    console.log('Pattern Counts:');
+    console.log(`  Async Patterns: ${result.summary.total_async_patterns}`);
+    console.log(`  Thread Patterns: ${result.summary.total_thread_patterns}`);
+    console.log(`  Sync Patterns: ${result.summary.total_sync_patterns}`);
+    console.log(`  Timer Patterns: ${result.summary.total_timer_patterns}`);
+    console.log(`  Warnings: ${result.summary.total_warnings}`);
+    console.log();
+  }
+
+  // Async patterns grouped
+  if (
+    result.async_patterns_grouped &&
+    result.async_patterns_grouped.length > 0
+  ) {
+    console.log('Async Patterns:\n');
+    for (const p of result.async_patterns_grouped.slice(0, 10)) {
+      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
+    }
+    console.log();
+  }
+
+  // Thread patterns grouped
+  if (
+    result.thread_patterns_grouped &&
+    result.thread_patterns_grouped.length > 0
+  ) {
+    console.log('Thread Patterns:\n');
+    for (const p of result.thread_patterns_grouped.slice(0, 10)) {
+      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
+    }
+    console.log();
+  }
+
+  // Sync patterns grouped
+  if (result.sync_patterns_grouped && result.sync_patterns_grouped.length > 0) {
+    console.log('Synchronization Patterns:\n');
+    for (const p of result.sync_patterns_grouped.slice(0, 10)) {
+      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
+    }
+    console.log();
+  }
+
+  // Timer patterns grouped
+  if (
+    result.timer_patterns_grouped &&
+    result.timer_patterns_grouped.length > 0
+  ) {
+    console.log('Timer Patterns:\n');
+    for (const p of result.timer_patterns_grouped.slice(0, 10)) {
+      console.log(`  * ${p.type} (${p.count}): ${p.description}`);
+    }
+    console.log();
+  }
+
+  // Warnings
+  if (result.warnings && result.warnings.length > 0) {
+    console.log(`Potential Issues (${result.warnings.length}):\n`);
+    for (const w of result.warnings.slice(0, 20)) {
+      console.log(`  [${w.severity}] ${w.description}`);
+      console.log(`    ${w.symbol} - ${w.filename}:${w.start_line}`);
+    }
+    if (result.warnings.length > 20) {
+      console.log(`  ... and ${result.warnings.length - 20} more`);
+    }
+    console.log();
+  }
+
+  // Functions with concurrency
+  if (
+    result.functions_with_concurrency &&
+    result.functions_with_concurrency.length > 0
+  ) {
+    console.log(
+      `Functions with Concurrency (${result.functions_with_concurrency.length}):\n`
+    );
+    for (const fn of result.functions_with_concurrency.slice(0, 20)) {
+      const counts = [];
+      if (fn.pattern_counts.async > 0)
+        counts.push(`async:${fn.pattern_counts.async}`);
+      if (fn.pattern_counts.thread > 0)
+        counts.push(`thread:${fn.pattern_counts.thread}`);
+      if (fn.pattern_counts.sync > 0)
+        counts.push(`sync:${fn.pattern_counts.sync}`);
+      if (fn.pattern_counts.timer > 0)
+        counts.push(`timer:${fn.pattern_counts.timer}`);
+      console.log(
+        `  * ${fn.symbol} [${counts.join(', ')}] - ${fn.filename}:${fn.start_line}`
+      );
+    }
+    if (result.functions_with_concurrency.length > 20) {
+      console.log(
+        `  ... and ${result.functions_with_concurrency.length - 20} more`
+      );
+    }
+  }
+
+  if (
+    !result.functions_with_concurrency ||
+    result.functions_with_concurrency.length === 0
+  ) {
+    console.log('No concurrency patterns detected in this project.');
+  }
+};
+
+const analysis_resources = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_resources(project_id);
+
+  console.log(`\n=== Resource Analysis: ${project} ===\n`);
+
+  // Summary
+  if (result.summary) {
+    console.log('Summary:');
+    console.log(`  Functions Analyzed: ${result.summary.functions_analyzed}`);
+    console.log(
+      `  Functions with Resources: ${result.summary.functions_with_resources} (${result.summary.resource_percentage}%)`
+    );
+    console.log(
+      `  Files with Resources: ${result.summary.files_with_resources}`
+    );
+    console.log(`  Languages: ${result.summary.languages.join(', ') || 'N/A'}`);
+    console.log(
+      `  Categories: ${result.summary.categories.join(', ') || 'N/A'}`
+    );
+    console.log();
+    console.log('Pattern Counts:');
+    console.log(`  Acquisitions: ${result.summary.total_acquisitions}`);
+    console.log(`  Releases: ${result.summary.total_releases}`);
+    console.log(
+      `  Safe Patterns (RAII/smart pointers): ${result.summary.total_safe_patterns}`
+    );
+    console.log(`  Potential Leaks: ${result.summary.total_potential_leaks}`);
+    console.log(`  Warnings: ${result.summary.total_warnings}`);
+    console.log();
+  }
+
+  // Safe patterns grouped
+  if (result.safe_patterns_grouped && result.safe_patterns_grouped.length > 0) {
+    console.log(
+      'Safe Resource Patterns (RAII, smart pointers, context managers):\n'
+    );
+    for (const p of result.safe_patterns_grouped.slice(0, 10)) {
+      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
+    }
+    console.log();
+  }
+
+  // Acquisitions grouped
+  if (result.acquisitions_grouped && result.acquisitions_grouped.length > 0) {
+    console.log('Resource Acquisitions:\n');
+    for (const p of result.acquisitions_grouped.slice(0, 10)) {
+      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
+    }
+    console.log();
+  }
+
+  // Releases grouped
+  if (result.releases_grouped && result.releases_grouped.length > 0) {
+    console.log('Resource Releases:\n');
+    for (const p of result.releases_grouped.slice(0, 10)) {
+      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
+    }
+    console.log();
+  }
+
+  // Potential leaks
+  if (
+    result.potential_leaks_grouped &&
+    result.potential_leaks_grouped.length > 0
+  ) {
+    console.log(
+      `Potential Resource Leaks (${result.potential_leaks.length}):\n`
+    );
+    for (const p of result.potential_leaks_grouped.slice(0, 10)) {
+      console.log(`  * ${p.name} (${p.count}): ${p.description}`);
+      for (const loc of p.locations.slice(0, 3)) {
+        console.log(`      - ${loc.symbol} - ${loc.filename}:${loc.line}`);
+      }
+      if (p.locations.length > 3) {
+        console.log(`      ... and ${p.locations.length - 3} more locations`);
+      }
+    }
+    console.log();
+  }
+
+  // Warnings
+  if (result.warnings && result.warnings.length > 0) {
+    console.log(`Warnings (${result.warnings.length}):\n`);
+    for (const w of result.warnings.slice(0, 20)) {
+      console.log(`  [${w.severity}] ${w.description}`);
+      console.log(`    ${w.symbol} - ${w.filename}:${w.start_line}`);
+    }
+    if (result.warnings.length > 20) {
+      console.log(`  ... and ${result.warnings.length - 20} more`);
+    }
+    console.log();
+  }
+
+  if (
+    !result.functions_with_resources ||
+    result.functions_with_resources.length === 0
+  ) {
+    console.log('No resource management patterns detected in this project.');
+  }
+};
+
+const analysis_naming = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_naming_conventions(project_id);
+
+  console.log(`\n=== Naming Convention Analysis: ${project} ===\n`);
+
+  // Summary
+  console.log('Summary:');
+  console.log(`  Total Identifiers: ${result.summary.total_identifiers}`);
+  console.log(`  Compliant: ${result.summary.compliant}`);
+  console.log(`  Non-Compliant: ${result.summary.non_compliant}`);
+  console.log(`  With Issues: ${result.summary.with_issues}`);
+  console.log(`  Compliance Rate: ${result.summary.compliance_rate}%`);
+  console.log();
+
+  // Convention distribution
+  if (
+    result.convention_distribution &&
+    Object.keys(result.convention_distribution).length > 0
+  ) {
+    console.log('Convention Distribution:');
+    for (const [conv, count] of Object.entries(
+      result.convention_distribution
+    )) {
+      const percent = Math.round(
+        (count / result.summary.total_identifiers) * 100
+      );
+      console.log(`  ${conv}: ${count} (${percent}%)`);
+    }
+    console.log();
+  }
+
+  // By language
+  if (
+    result.summary.by_language &&
+    Object.keys(result.summary.by_language).length > 0
+  ) {
+    console.log('By Language:');
+    for (const [lang, stats] of Object.entries(result.summary.by_language)) {
+      console.log(
+        `  ${lang}: ${stats.compliant}/${stats.total} compliant (${stats.compliance_rate}%)`
+      );
+    }
+    console.log();
+  }
+
+  // Violations
+  if (result.violations && result.violations.length > 0) {
+    console.log(
+      `Naming Convention Violations (${result.violations.length}${result.violations_truncated ? '+' : ''}):\n`
+    );
+    for (const v of result.violations.slice(0, 20)) {
+      console.log(`  * ${v.symbol} (${v.type})`);
+      console.log(`      ${v.filename}:${v.line}`);
+      console.log(
+        `      Expected: ${v.expected.join(' or ')}, Found: ${v.detected.join(', ')}`
+      );
+    }
+    if (result.violations.length > 20) {
+      console.log(`  ... and ${result.violations.length - 20} more violations`);
+    }
+    console.log();
+  }
+
+  // Issues
+  if (result.issues && result.issues.length > 0) {
+    console.log(
+      `Naming Issues (${result.issues.length}${result.issues_truncated ? '+' : ''}):\n`
+    );
+    for (const i of result.issues.slice(0, 20)) {
+      console.log(`  * ${i.symbol} (${i.type}) - ${i.filename}:${i.line}`);
+      for (const issue of i.issues) {
+        console.log(`      - ${issue.message}`);
+      }
+    }
+    if (result.issues.length > 20) {
+      console.log(`  ... and ${result.issues.length - 20} more issues`);
+    }
+    console.log();
+  }
+
+  if (result.summary.total_identifiers === 0) {
+    console.log('No identifiers found in this project.');
+  }
+};
+
+const analysis_readability = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_readability_score(project_id);
+
+  console.log(`\n=== Code Readability Analysis: ${project} ===\n`);
+
+  // Summary
+  console.log('Summary:');
+  console.log(`  Total Functions: ${result.summary.total_functions}`);
+  console.log(`  Average Score: ${result.summary.avg_score}/100`);
+  console.log();
+
+  // Score distribution
+  console.log('Score Distribution:');
+  console.log(
+    `  Excellent (85+): ${result.summary.score_distribution.excellent}`
+  );
+  console.log(`  Good (70-84): ${result.summary.score_distribution.good}`);
+  console.log(
+    `  Acceptable (55-69): ${result.summary.score_distribution.acceptable}`
+  );
+  console.log(`  Poor (40-54): ${result.summary.score_distribution.poor}`);
+  console.log(
+    `  Very Poor (<40): ${result.summary.score_distribution.very_poor}`
+  );
+  console.log();
+
+  // Metrics
+  console.log('Aggregate Metrics:');
+  console.log(
+    `  Avg Function Length: ${result.metrics.avg_function_length} lines`
+  );
+  console.log(`  Avg Nesting Depth: ${result.metrics.avg_nesting_depth}`);
+  console.log(`  Avg Line Length: ${result.metrics.avg_line_length} chars`);
+  console.log(`  Total Magic Numbers: ${result.metrics.total_magic_numbers}`);
+  console.log(
+    `  Total Complex Booleans: ${result.metrics.total_complex_booleans}`
+  );
+  console.log();
+
+  // By language
+  if (
+    result.summary.by_language &&
+    Object.keys(result.summary.by_language).length > 0
+  ) {
+    console.log('By Language:');
+    for (const [lang, stats] of Object.entries(result.summary.by_language)) {
+      console.log(
+        `  ${lang}: ${stats.count} functions, avg score ${stats.avg_score}`
+      );
+    }
+    console.log();
+  }
+
+  // Worst functions
+  if (result.worst_functions && result.worst_functions.length > 0) {
+    console.log('Least Readable Functions:\n');
+    for (const f of result.worst_functions.slice(0, 10)) {
+      console.log(`  * ${f.symbol} - Score: ${f.score} (${f.rating})`);
+      console.log(`      ${f.filename}:${f.line} (${f.lines} lines)`);
+      if (f.issues) {
+        const issues = [];
+        if (f.issues.nesting_depth > 4)
+          issues.push(`nesting: ${f.issues.nesting_depth}`);
+        if (f.issues.magic_numbers > 5)
+          issues.push(`magic numbers: ${f.issues.magic_numbers}`);
+        if (f.issues.complex_booleans > 0)
+          issues.push(`complex booleans: ${f.issues.complex_booleans}`);
+        if (f.issues.max_line_length > 120)
+          issues.push(`max line: ${f.issues.max_line_length}`);
+        if (issues.length > 0) {
+          console.log(`      Issues: ${issues.join(', ')}`);
+        }
+      }
+    }
+    console.log();
+  }
+
+  // Best functions
+  if (result.best_functions && result.best_functions.length > 0) {
+    console.log('Most Readable Functions:\n');
+    for (const f of result.best_functions.slice(0, 5)) {
+      console.log(`  * ${f.symbol} - Score: ${f.score} (${f.rating})`);
+      console.log(`      ${f.filename}:${f.line} (${f.lines} lines)`);
+    }
+    console.log();
+  }
+
+  if (result.summary.total_functions === 0) {
+    console.log('No functions found in this project.');
+  }
+};
+
+const analysis_patterns = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_design_patterns(project_id);
+
+  console.log(`\n=== Design Pattern Analysis: ${project} ===\n`);
+
+  // Summary
+  console.log('Summary:');
+  console.log(`  Total Functions Analyzed: ${result.summary.total_functions}`);
+  console.log(
+    `  Functions with Patterns: ${result.summary.functions_with_patterns}`
+  );
+  console.log(
+    `  Total Patterns Detected: ${result.summary.total_patterns_detected}`
+  );
+  console.log();
+
+  // By category
+  console.log('Patterns by Category:');
+  console.log(
+    `  Creational: ${result.summary.patterns_by_category.creational}`
+  );
+  console.log(
+    `  Structural: ${result.summary.patterns_by_category.structural}`
+  );
+  console.log(
+    `  Behavioral: ${result.summary.patterns_by_category.behavioral}`
+  );
+  console.log(
+    `  Anti-patterns: ${result.summary.patterns_by_category['anti-pattern']}`
+  );
+  console.log();
+
+  // Error handling summary
+  if (result.summary.error_handling) {
+    console.log('Error Handling:');
+    console.log(
+      `  Try/Catch Blocks: ${result.summary.error_handling.total_try_catch}`
+    );
+    console.log(
+      `  Empty Catch Blocks: ${result.summary.error_handling.total_empty_catch}`
+    );
+    console.log(
+      `  Finally/Defer Blocks: ${result.summary.error_handling.total_finally}`
+    );
+    console.log(
+      `  Functions with Error Handling: ${result.summary.error_handling.functions_with_error_handling}`
+    );
+    console.log();
+  }
+
+  // Detected patterns
+  if (Object.keys(result.patterns).length > 0) {
+    console.log('Detected Patterns:\n');
+    for (const [id, data] of Object.entries(result.patterns)) {
+      console.log(
+        `  * ${data.name} (${data.category}): ${data.count} occurrences`
+      );
+      for (const loc of data.locations.slice(0, 3)) {
+        console.log(`      - ${loc.symbol} - ${loc.filename}:${loc.line}`);
+      }
+      if (data.locations.length > 3) {
+        console.log(`      ... and ${data.locations.length - 3} more`);
+      }
+    }
+    console.log();
+  }
+
+  // Anti-patterns
+  if (result.anti_patterns && result.anti_patterns.length > 0) {
+    console.log(`Anti-patterns Detected (${result.anti_patterns.length}):\n`);
+    for (const ap of result.anti_patterns.slice(0, 10)) {
+      console.log(`  * ${ap.pattern}: ${ap.symbol}`);
+      console.log(`      ${ap.filename}:${ap.line}`);
+      console.log(`      ${ap.description}`);
+    }
+    console.log();
+  }
+
+  // Error handling issues
+  if (result.error_handling_issues && result.error_handling_issues.length > 0) {
+    console.log(
+      `Error Handling Issues (${result.error_handling_issues.length}):\n`
+    );
+    for (const issue of result.error_handling_issues.slice(0, 10)) {
+      console.log(
+        `  [${issue.severity}] ${issue.symbol} - ${issue.filename}:${issue.line}`
+      );
+      console.log(`    ${issue.message}`);
+    }
+    console.log();
+  }
+
+  if (result.summary.total_patterns_detected === 0) {
+    console.log('No design patterns detected in this project.');
+  }
+};
+
+const analysis_tests = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_test_coverage(project_id);
+
+  console.log(`\n=== Test Analysis: ${project} ===\n`);
+
+  // Summary
+  console.log('Summary:');
+  console.log(`  Total Files: ${result.summary.total_files}`);
+  console.log(`  Test Files: ${result.summary.test_files}`);
+  console.log(`  Non-Test Files: ${result.summary.non_test_files}`);
+  console.log(`  Total Functions: ${result.summary.total_functions}`);
+  console.log(`  Test Functions: ${result.summary.test_functions}`);
+  console.log(`  Non-Test Functions: ${result.summary.non_test_functions}`);
+  console.log(`  Test-to-Code Ratio: ${result.summary.test_to_code_ratio}`);
+  console.log();
+
+  // Assertion stats
+  console.log('Assertion Statistics:');
+  console.log(`  Total Assertions: ${result.summary.total_assertions}`);
+  console.log(
+    `  Avg Assertions per Test: ${result.summary.avg_assertions_per_test}`
+  );
+  console.log(`  Total Mocks/Stubs: ${result.summary.total_mocks}`);
+  console.log();
+
+  // By language
+  if (
+    result.summary.by_language &&
+    Object.keys(result.summary.by_language).length > 0
+  ) {
+    console.log('By Language:');
+    for (const [lang, stats] of Object.entries(result.summary.by_language)) {
+      console.log(
+        `  ${lang}: ${stats.test_functions}/${stats.total_functions} test functions (${stats.test_ratio}%)`
+      );
+    }
+    console.log();
+  }
+
+  // Test files
+  if (result.test_files && result.test_files.length > 0) {
+    console.log(`Test Files (${result.test_files.length}):\n`);
+    for (const f of result.test_files.slice(0, 15)) {
+      console.log(`  * ${f.filename} (${f.language})`);
+    }
+    if (result.test_files.length > 15) {
+      console.log(`  ... and ${result.test_files.length - 15} more`);
+    }
+    console.log();
+  }
+
+  // Top test functions by assertions
+  if (result.test_functions && result.test_functions.length > 0) {
+    console.log('Top Test Functions (by assertions):\n');
+    for (const f of result.test_functions.slice(0, 10)) {
+      console.log(
+        `  * ${f.symbol} - ${f.assertions} assertions, ${f.mocks} mocks`
+      );
+      console.log(`      ${f.filename}:${f.line}`);
+    }
+    console.log();
+  }
+
+  // Coverage gaps
+  if (result.coverage_gaps && result.coverage_gaps.length > 0) {
+    console.log(
+      `Potential Coverage Gaps (${result.coverage_gaps.length}${result.coverage_gaps_truncated ? '+' : ''}):\n`
+    );
+    for (const gap of result.coverage_gaps.slice(0, 15)) {
+      console.log(`  * ${gap.filename}`);
+      console.log(`      ${gap.message}`);
+    }
+    if (result.coverage_gaps.length > 15) {
+      console.log(`  ... and ${result.coverage_gaps.length - 15} more`);
+    }
+    console.log();
+  }
+
+  // Naming issues
+  if (result.naming_issues && result.naming_issues.length > 0) {
+    console.log(
+      `Test Naming Issues (${result.naming_issues.length}${result.naming_issues_truncated ? '+' : ''}):\n`
+    );
+    for (const issue of result.naming_issues.slice(0, 10)) {
+      console.log(`  * ${issue.symbol} - ${issue.filename}:${issue.line}`);
+      for (const i of issue.issues) {
+        console.log(`      - ${i}`);
+      }
+    }
+    console.log();
+  }
+
+  if (result.summary.test_functions === 0) {
+    console.log('No test functions found in this project.');
+  }
+};
+
+const analysis_struct_size = async ({ project, threshold }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_struct_sizes(project_id, { threshold });
+
+  console.log(`\n=== Struct Size Analysis: ${project} ===\n`);
+  console.log(`Threshold: ${result.threshold} bytes`);
+  console.log(`Structs: ${result.summary.total_structs}`);
+  console.log(`Large Structs: ${result.summary.large_structs}`);
+  console.log(`Total Padding: ${result.summary.total_padding} bytes\n`);
+
+  if (result.structs.length > 0) {
+    console.log('Largest Structs:');
+    for (const s of result.structs.slice(0, 10)) {
+      const approx = s.exact ? '' : ' (approximate)';
+      console.log(
+        `  ${s.name}: ${s.size} bytes, ${s.padding} padding${approx} - ${s.filename}:${s.line}`
+      );
+    }
+    console.log();
+  }
+
+  if (result.large_value_params.length === 0) {
+    console.log('No large structs passed by value.');
+    return;
+  }
+
+  console.log(
+    `Large Structs Passed by Value (${result.large_value_params.length}):`
+  );
+  for (const f of result.large_value_params) {
+    const name = f.receiver ? `${f.receiver}.${f.function}` : f.function;
+    console.log(`  ${name} - ${f.filename}:${f.line}`);
+    console.log(`    ${f.message}`);
+  }
+};
+
+const analysis_literals = async ({
+  project,
+  kind,
+  'inline-only': inline_only
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await extract_literals(project_id, { kind, inline_only });
+
+  console.log(`\n=== Literals: ${project} ===\n`);
+  console.log(
+    `Total: ${result.summary.total_literals} literals in ${result.summary.file_count} files`
+  );
+  console.log(
+    `Const: ${result.summary.const_count}, Inline: ${result.summary.inline_count}\n`
+  );
+
+  if (result.literals.length === 0) {
+    console.log('No literals found.');
+    return;
+  }
+
+  let current_file = null;
+  for (const literal of result.literals) {
+    if (literal.filename !== current_file) {
+      if (current_file) console.log();
+      current_file = literal.filename;
+      console.log(`${literal.filename}:`);
+    }
+    const where = literal.enclosing ? ` in ${literal.enclosing.name}` : '';
+    console.log(
+      `  ${literal.line}:${literal.column} [${literal.kind}, ${literal.context}] ${literal.text}${where}`
+    );
+  }
+};
+
+const analysis_channels = async ({ project, package: pkg, function: fn }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_channels(project_id, {
+    package: pkg,
+    function: fn
+  });
+
+  console.log(`\n=== Channel Topology: ${project} ===\n`);
+  console.log(`Channels: ${result.summary.total_channels}`);
+  console.log(`Operations: ${result.summary.total_operations}`);
+  console.log(`Goroutines: ${result.summary.total_goroutines}`);
+  console.log(`Warnings: ${result.summary.total_warnings}\n`);
+
+  if (result.packages.length === 0) {
+    console.log('No channels or goroutines found.');
+    return;
+  }
+
+  for (const model of result.packages) {
+    console.log(`Package ${model.package} (${model.directory}):`);
+
+    for (const channel of model.channels) {
+      const type = channel.type || 'unknown type';
+      console.log(
+        `  ${channel.id} [${channel.kind}, ${type}] ${channel.direction}`
+      );
+      if (channel.senders.length > 0) {
+        console.log(`    send: ${channel.senders.join(', ')}`);
+      }
+      if (channel.receivers.length > 0) {
+        console.log(`    receive: ${channel.receivers.join(', ')}`);
+      }
+      if (channel.closers.length > 0) {
+        console.log(`    close: ${channel.closers.join(', ')}`);
+      }
+    }
+
+    if (model.goroutines.length > 0) {
+      console.log('  Goroutines:');
+      for (const g of model.goroutines) {
+        const channels =
+          g.channels.length > 0 ? ` (${g.channels.join(', ')})` : '';
+        console.log(
+          `    ${g.function} -> go ${g.target}${channels} - ${g.filename}:${g.line}`
+        );
+      }
+    }
+
+    for (const w of model.warnings) {
+      console.log(`  [${w.type}] ${w.filename}:${w.line} - ${w.message}`);
+    }
+    console.log();
+  }
+};
+
+const analysis_nesting = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_nesting(project_id);
+
+  console.log(`\n=== Nesting Analysis: ${project} ===\n`);
+  console.log(`Functions Analyzed: ${result.summary.functions_analyzed}`);
+  console.log(`Using Guard Clauses: ${result.summary.functions_with_guards}`);
+  console.log(`Deeply Nested: ${result.summary.deeply_nested}`);
+  console.log(`Average Max Depth: ${result.summary.avg_max_depth}`);
+  console.log(`Average Score: ${result.summary.avg_score}/100\n`);
+
+  const flagged = result.functions.filter((f) => f.suggestions.length > 0);
+  if (flagged.length === 0) {
+    console.log('No nesting issues found.');
+    return;
+  }
+
+  console.log('Functions to Improve:');
+  for (const fn of flagged.slice(0, 20)) {
+    console.log(
+      `  ${fn.name} (score ${fn.score}, depth ${fn.max_depth}) - ${fn.filename}:${fn.line}`
+    );
+    for (const suggestion of fn.suggestions) {
+      console.log(`    ${suggestion}`);
+    }
+  }
+};
+
+const analysis_package_docs = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_package_docs(project_id);
+
+  console.log(`\n=== Package Docs: ${project} ===\n`);
+  console.log(`Packages: ${result.summary.total_packages}`);
+  console.log(`Documented: ${result.summary.documented_packages}`);
+  console.log(`With Conflicts: ${result.summary.packages_with_conflicts}\n`);
+
+  for (const pkg of result.packages) {
+    console.log(`${pkg.package} (${pkg.directory})`);
+    if (!pkg.documented) {
+      console.log('  (no package doc)');
+    } else {
+      console.log(`  ${pkg.synopsis}`);
+      console.log(`  from ${pkg.doc_file}:${pkg.doc_line}`);
+    }
+    if (pkg.name_mismatch) {
+      console.log(`  doc names package '${pkg.name_mismatch}'`);
+    }
+    for (const conflict of pkg.conflicts) {
+      const kind = conflict.same_text ? 'duplicate' : 'conflicting';
+      console.log(`  ${kind} doc in ${conflict.filename}:${conflict.line}`);
+    }
+  }
+};
+
+const analysis_error_wrapping = async ({ project, expected }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_error_wrapping(project_id, {
+    expected
+  });
+
+  console.log(`\n=== Error Wrapping: ${project} ===\n`);
+  console.log(`Expected Convention: ${result.expected}`);
+  console.log(`Packages Analyzed: ${result.summary.packages_analyzed}`);
+  console.log(`Consistent Packages: ${result.summary.consistent_packages}`);
+  console.log(`Deviations: ${result.summary.total_deviations}\n`);
+
+  for (const pkg of result.packages) {
+    console.log(
+      `${pkg.package} (${pkg.directory}) - ${pkg.convention}, ${pkg.counts.wrapped || 0} wrapped, ${(pkg.counts.raw || 0) + (pkg.counts.annotated || 0)} unwrapped`
+    );
+    if (pkg.detected === 'mixed' && pkg.convention === 'mixed') {
+      console.log('  Evenly mixed; use --expected to choose a convention');
+    }
+    for (const deviation of pkg.deviations) {
+      console.log(
+        `  ${deviation.filename}:${deviation.line} ${deviation.function}: ${deviation.message}`
+      );
+    }
+  }
+};
+
+const analysis_field_init = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_field_init(project_id);
+  const counts = result.summary.by_typical_init;
+
+  console.log(`\n=== Field Initialization: ${project} ===\n`);
+  console.log(`Structs: ${result.summary.types_analyzed}`);
+  console.log(`Exported Fields: ${result.summary.fields_analyzed}`);
+  console.log(
+    `Constructor: ${counts.constructor}, Caller: ${counts.caller}, Mixed: ${counts.mixed}, Unset: ${counts.none}\n`
+  );
+
+  for (const type of result.types) {
+    const built = type.constructors.length
+      ? ` (constructors: ${type.constructors.join(', ')})`
+      : '';
+    console.log(
+      `${type.package}.${type.name}${built} - ${type.filename}:${type.line}`
+    );
+    for (const field of type.fields) {
+      console.log(
+        `  ${field.name}: ${field.typical_init} (${field.by_constructor} constructor, ${field.literals} literal, ${field.assignments} assignment)`
+      );
+      if (field.suggestion) console.log(`    ${field.suggestion}`);
+    }
+  }
+};
+
+const analysis_workspace_symbols = async ({ project, query, limit }) => {
+  const project_id = await get_project_id(project);
+  const result = await get_workspace_symbols(project_id, { query, limit });
+
+  console.log(JSON.stringify(result.symbols, null, 2));
+};
+
+const analysis_init_order = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_init_order(project_id);
+
+  console.log(`\n=== Initialization Order: ${project} ===\n`);
+  console.log(`Packages: ${result.summary.packages_analyzed}`);
+  console.log(`Variables: ${result.summary.total_variables}`);
+  console.log(`Reordered Variables: ${result.summary.reordered_variables}`);
+  console.log(`Initialization Cycles: ${result.summary.cycles}\n`);
+
+  for (const pkg of result.packages) {
+    console.log(`${pkg.package} (${pkg.directory})`);
+    console.log(`  Order: ${pkg.order.join(', ') || '(none)'}`);
+    for (const variable of pkg.variables.filter((v) => v.reordered)) {
+      console.log(
+        `  ${variable.name} - ${variable.filename}:${variable.line} is initialized after ${variable.requires.join(', ')}`
+      );
+    }
+    for (const cycle of pkg.cycles) {
+      console.log(`  ${cycle.filename}:${cycle.line} ${cycle.message}`);
+    }
+  }
+};
+
+const analysis_go_scripts = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_go_scripts(project_id);
+
+  console.log(`\n=== Go Scripts: ${project} ===\n`);
+  console.log(`Scripts: ${result.summary.total_scripts}`);
+  console.log(`Run by go:generate: ${result.summary.generators}\n`);
+
+  if (result.scripts.length === 0) {
+    console.log('No files are excluded with //go:build ignore.');
+    return;
+  }
+
+  for (const script of result.scripts) {
+    console.log(`${script.filename} (package ${script.package})`);
+    if (script.synopsis) console.log(`  ${script.synopsis}`);
+    console.log(`  Functions: ${script.functions.join(', ') || '(none)'}`);
+    for (const generator of script.generated_by) {
+      console.log(
+        `  Run by ${generator.filename}:${generator.line}: ${generator.command}`
+      );
+    }
+  }
+};
+
+const analysis_duplicate_methods = async ({
+  project,
+  'min-statements': min_statements
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_duplicate_methods(project_id, {
+    min_statements
+  });
+
+  console.log(`\n=== Duplicate Methods: ${project} ===\n`);
+  console.log(`Groups: ${result.summary.total_groups}`);
+  console.log(`Methods: ${result.summary.duplicate_methods}`);
+  console.log(`Duplicated Lines: ${result.summary.duplicated_lines}\n`);
+
+  if (result.groups.length === 0) {
+    console.log('No methods with identical bodies found.');
+    return;
+  }
+
+  for (const group of result.groups) {
+    console.log(
+      `${group.names.join('/')} on ${group.types.join(', ')} (${group.statements} statements)`
+    );
+    for (const method of group.methods) {
+      console.log(
+        `  ${method.type}.${method.name} ${method.filename}:${method.line}`
+      );
+    }
+    console.log(`  Suggestion: ${group.suggestion.message}`);
+  }
+};
+
+const analysis_context_params = async ({
+  project,
+  'allow-before': allow_before
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_context_params(project_id, {
+    allow_types_before: allow_before ? allow_before.split(',') : []
+  });
+
+  console.log(`\n=== Context Parameters: ${project} ===\n`);
+  console.log(
+    `Functions Taking a Context: ${result.summary.functions_with_context}`
+  );
+  console.log(`Misplaced: ${result.summary.misplaced}\n`);
+
+  const misplaced = result.packages.flatMap((p) => p.misplaced);
+  if (misplaced.length === 0) {
+    console.log('Every context.Context is the first parameter.');
+    return;
+  }
+
+  for (const item of misplaced) {
+    console.log(
+      `${item.filename}:${item.line} ${item.symbol} (parameter ${item.position} of ${item.parameter_count})`
+    );
+  }
+};
+
+const analysis_token_costs = async ({ project, tokenizer, limit = 20 }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_token_costs(project_id, {
+    tokenizer,
+    limit
+  });
+
+  console.log(`\n=== Token Costs: ${project} ===\n`);
+  console.log(`Tokenizer: ${result.tokenizer}`);
+  console.log(`Symbols: ${result.summary.total_symbols}`);
+  console.log(`Signature Tokens: ${result.summary.signature_tokens}`);
+  console.log(`Total Tokens: ${result.summary.total_tokens}\n`);
+
+  console.log('Packages:');
+  for (const pkg of result.packages) {
+    console.log(
+      `  ${pkg.directory} (${pkg.package}): ${pkg.total_tokens} tokens, ${pkg.signature_tokens} in signatures`
+    );
+  }
+
+  console.log('\nMost Expensive Symbols:');
+  for (const symbol of result.symbols) {
+    console.log(
+      `  ${symbol.token_cost.total} ${symbol.kind} ${symbol.name} (signature ${symbol.token_cost.signature}) ${symbol.filename}:${symbol.line}`
+    );
+  }
+};
+
+const analysis_stub = async ({ project, interface: iface, type }) => {
+  if (!iface) {
+    throw new Error('--interface is required');
+  }
+  const project_id = await get_project_id(project);
+  const stub = await generate_stub(project_id, iface, { type_name: type });
+
+  console.log(stub.source);
+};
+
+const analysis_struct_kinds = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_struct_kinds(project_id);
+
+  console.log(`\n=== Struct Kinds: ${project} ===\n`);
+  console.log(`Structs: ${result.summary.total_structs}`);
+  for (const [kind, count] of Object.entries(result.summary.by_kind)) {
+    console.log(`  ${kind}: ${count}`);
+  }
+
+  for (const kind of ['service', 'mixed', 'data']) {
+    const structs = result.structs.filter((s) => s.kind === kind);
+    if (structs.length === 0) continue;
+    console.log(`\n${kind[0].toUpperCase()}${kind.slice(1)}:`);
+    for (const s of structs) {
+      console.log(`  ${s.name} (${s.reason}) - ${s.filename}:${s.line}`);
+    }
+  }
+};
+
+const print_near_miss = (report) => {
+  const location = `${report.filename}:${report.line}`;
+  if (report.satisfies) {
+    const by = report.value_satisfies ? '' : ' (as a pointer)';
+    console.log(`${report.type} implements ${report.interface}${by}`);
+    return;
+  }
+  console.log(`${report.message}\n  ${location}`);
+  for (const method of report.missing) {
+    console.log(`  missing: ${method.signature}`);
+  }
+  for (const method of report.mismatched) {
+    console.log(`  have:    ${method.actual} (${method.filename}:${method.line})`);
+    console.log(`  want:    ${method.expected}`);
+  }
+};
+
+const analysis_near_misses = async ({
+  project,
+  type,
+  interface: iface,
+  'max-missing': max_missing
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_near_misses(project_id, {
+    type,
+    interface: iface,
+    max_missing
+  });
+
+  console.log(`\n=== Interface Near Misses: ${project} ===\n`);
+  if (!result.near_misses) {
+    print_near_miss(result);
+    return;
+  }
+
+  console.log(`Near Misses: ${result.summary.total_near_misses}`);
+  console.log(`Max Missing: ${result.summary.max_missing}\n`);
+  if (result.near_misses.length === 0) {
+    console.log('No types close to implementing an interface found.');
+    return;
+  }
+  for (const report of result.near_misses) {
+    print_near_miss(report);
+    console.log('');
+  }
+};
+
+const analysis_build_constraints = async ({ project, goos, goarch, tags }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_build_constraints(project_id, {
+    goos,
+    goarch,
+    tags: typeof tags === 'string' ? tags.split(',') : undefined
+  });
+
+  console.log(`\n=== Build Constraints: ${project} ===\n`);
+  console.log(`Files: ${result.summary.total_files}`);
+  console.log(`Constrained: ${result.summary.constrained_files}`);
+  console.log(`  By Build Line: ${result.summary.build_constrained}`);
+  console.log(`  By Filename: ${result.summary.filename_constrained}`);
+  if (result.target) {
+    const platform = [result.target.goos, result.target.goarch]
+      .filter(Boolean)
+      .join('/');
+    const target_tags = result.target.tags.join(', ');
+    console.log(`\nTarget: ${platform} (tags: ${target_tags})`);
+    console.log(`Included: ${result.summary.included}`);
+    console.log(`Excluded: ${result.summary.excluded}`);
+  }
+  console.log('');
+
+  for (const file of result.files) {
+    let status = '';
+    if (result.target) status = file.included ? '[+] ' : '[-] ';
+    const test = file.test ? ' (test)' : '';
+    const expression = file.expression || '(unconstrained)';
+    console.log(`${status}${file.filename}${test}: ${expression}`);
+    if (file.error) console.log(`    ${file.error}`);
+  }
+};
+
+// Helper to parse code=weight lists
+const parse_weights = (text) => {
+  if (typeof text !== 'string') return undefined;
+  const weights = {};
+  for (const entry of text.split(',').filter(Boolean)) {
+    const [code, value] = entry.split('=');
+    weights[code.trim()] = Number(value);
+  }
+  return weights;
+};
+
+const analysis_smell_score = async ({ project, weights, limit = 20 }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_smell_scores(project_id, {
+    weights: parse_weights(weights),
+    limit
+  });
+
+  console.log(`\n=== Code Smells: ${project} ===\n`);
+  console.log(`Smelly Symbols: ${result.summary.smelly_symbols}`);
+  console.log(`Smelly Files: ${result.summary.smelly_files}`);
+  console.log(`Total Score: ${result.summary.total_score}`);
+
+  if (result.symbols.length === 0) {
+    console.log('\nNo code smells found.');
+    return;
+  }
+
+  console.log('\nSymbols:');
+  for (const symbol of result.symbols) {
+    const codes = symbol.diagnostics
+      .map((d) => `${d.code} ${d.points}`)
+      .join(', ');
+    console.log(
+      `  ${symbol.score.toFixed(2)}  ${symbol.symbol} - ${symbol.filename}:${symbol.line} (${codes})`
+    );
+  }
+
+  console.log('\nFiles:');
+  for (const file of result.files) {
+    const symbols = `${file.symbols} symbols`;
+    console.log(`  ${file.score.toFixed(2)}  ${file.filename} (${symbols})`);
+  }
+};
+
+const analysis_method_shadowing = async ({ project, accidental }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_method_shadowing(project_id);
+
+  console.log(`\n=== Method Shadowing: ${project} ===\n`);
+  console.log(`Shadowed Methods: ${result.summary.total_shadowed}`);
+  console.log(`  Overrides: ${result.summary.overrides}`);
+  console.log(`  Collisions: ${result.summary.collisions}`);
+  console.log(`  Accidental: ${result.summary.accidental}`);
+
+  const shadowing = accidental
+    ? result.shadowing.filter((s) => !s.intentional)
+    : result.shadowing;
+  if (shadowing.length === 0) {
+    console.log('\nNo shadowing found.');
+    return;
+  }
+
+  console.log('');
+  for (const s of shadowing) {
+    const label = s.intentional ? s.kind : `${s.kind}, accidental`;
+    console.log(`  [${label}] ${s.message} - ${s.filename}:${s.line}`);
+  }
+};
+
+const analysis_conversions = async ({ project, unchecked }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_conversions(project_id, {
+    unchecked_only: unchecked
+  });
+
+  console.log(`\n=== Conversions and Assertions: ${project} ===\n`);
+  console.log(`Functions: ${result.summary.functions}`);
+  console.log(`Conversions: ${result.summary.conversions}`);
+  console.log(`Assertions: ${result.summary.assertions}`);
+  console.log(`Type Switches: ${result.summary.type_switches}`);
+  console.log(`Unchecked Assertions: ${result.summary.unchecked_assertions}`);
+
+  if (result.unchecked_assertions.length > 0) {
+    console.log('\nUnchecked Assertions:');
+    for (const u of result.unchecked_assertions) {
+      console.log(`  ${u.function}: ${u.message} - ${u.filename}:${u.line}`);
+      console.log(`    use: ${u.suggestion}`);
+    }
+  }
+
+  if (unchecked || result.functions.length === 0) return;
+
+  console.log('\nFunctions:');
+  for (const fn of result.functions) {
+    const types = [...new Set(fn.conversions.map((c) => c.type))];
+    const parts = [];
+    if (types.length > 0) parts.push(`converts to ${types.join(', ')}`);
+    if (fn.assertions.length > 0) {
+      parts.push(
+        `${fn.assertions.length} assertions (${fn.unchecked_assertions} unchecked)`
+      );
+    }
+    console.log(`  ${fn.name} - ${parts.join('; ')} - ${fn.filename}:${fn.line}`);
+  }
+};
+
+const analysis_repro = async ({ project, symbol }) => {
+  if (!symbol) {
+    throw new Error('--symbol is required');
+  }
+  const project_id = await get_project_id(project);
+  const repro = await generate_minimal_repro(project_id, symbol);
+
+  console.log(repro.source);
+};
+
+const analysis_concurrency_hints = async ({ project, hint }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_concurrency_hints(project_id, { hint });
+
+  console.log(`\n=== Concurrency Hints: ${project} ===\n`);
+  console.log(`Functions: ${result.summary.total_functions}`);
+  console.log(`Safe: ${result.summary.safe}`);
+  console.log(`Unknown: ${result.summary.unknown}`);
+  console.log(`Unsafe: ${result.summary.unsafe}`);
+  console.log(`Doc Conflicts: ${result.summary.doc_conflicts}`);
+
+  if (result.functions.length === 0) return;
+
+  console.log('\nFunctions:');
+  for (const fn of result.functions) {
+    const conflict = fn.doc_conflict ? ' [doc claims safe]' : '';
+    console.log(
+      `  ${fn.name} - ${fn.hint}${conflict} - ${fn.filename}:${fn.line}`
+    );
+    for (const reason of fn.reasons) {
+      const line = reason.line ? ` (line ${reason.line})` : '';
+      console.log(`    ${reason.message}${line}`);
+    }
+  }
+};
+
+const analysis_embedders = async ({
+  project,
+  type,
+  transitive,
+  unexported
+}) => {
+  if (!type) {
+    throw new Error('--type is required');
+  }
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_embedders(project_id, type, {
+    transitive,
+    include_unexported: unexported
+  });
+
+  console.log(
+    `\n=== Embedders of ${result.type.package}.${result.type.name}: ${project} ===\n`
+  );
+  console.log(`Embedders: ${result.summary.total_embedders}`);
+  console.log(`Direct: ${result.summary.direct}`);
+  console.log(`Transitive: ${result.summary.transitive}`);
+
+  if (result.embedders.length === 0) return;
+
+  console.log('\nTypes:');
+  for (const e of result.embedders) {
+    const path = e.depth > 1 ? ` (${e.path.join(' > ')})` : '';
+    console.log(
+      `  ${e.package}.${e.name} [${e.kind}]${path} - ${e.filename}:${e.line}`
+    );
+  }
+};
+
+const analysis_escapes = async ({
+  project,
+  'no-composites': no_composites
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_escapes(project_id, {
+    include_composites: !no_composites
+  });
+
+  console.log(`\n=== Escaping Pointers: ${project} ===\n`);
+  console.log(`Escapes: ${result.summary.total_escapes}`);
+  console.log(`Functions: ${result.summary.functions}`);
+  for (const [kind, count] of Object.entries(result.summary.by_kind)) {
+    console.log(`  ${kind}: ${count}`);
+  }
+
+  if (result.escapes.length === 0) return;
+
+  console.log('\nReturns:');
+  for (const e of result.escapes) {
+    console.log(`  ${e.function}: ${e.message} - ${e.filename}:${e.line}`);
+  }
+};
+
+const analysis_imports = async ({ project, unused }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_imports(project_id, {
+    unused_only: unused
+  });
+
+  console.log(`\n=== Import Usage: ${project} ===\n`);
+  console.log(`Files: ${result.summary.files}`);
+  console.log(`Imports: ${result.summary.imports}`);
+  console.log(`Unused Imports: ${result.summary.unused_imports}`);
+  console.log(`Guessed Names: ${result.summary.guessed_names}`);
+
+  if (result.unused_imports.length > 0) {
+    console.log('\nUnused:');
+    for (const u of result.unused_imports) {
+      console.log(`  ${u.message} - ${u.filename}:${u.line}:${u.column}`);
+    }
+  }
+
+  if (unused || result.files.length === 0) return;
+
+  console.log('\nFiles:');
+  for (const file of result.files) {
+    const names = file.imports.map((imp) => `${imp.name}(${imp.uses})`);
+    console.log(`  ${file.filename}: ${names.join(', ')}`);
+  }
+};
+
+const analysis_loop_captures = async ({
+  project,
+  'go-version': go_version,
+  'include-fixed': include_fixed
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_loop_captures(project_id, {
+    go_version: typeof go_version === 'string' ? go_version : undefined,
+    include_fixed
+  });
+
+  console.log(`\n=== Loop Variable Captures: ${project} ===\n`);
+  console.log(`Captures: ${result.summary.total_captures}`);
+  console.log(`Functions: ${result.summary.functions}`);
+  console.log(`Fixed by Go version: ${result.summary.fixed_by_go_version}`);
+
+  if (result.captures.length === 0) return;
+
+  console.log('\nCaptures:');
+  for (const c of result.captures) {
+    const fixed = c.fixed_by ? ` (fixed by ${c.fixed_by})` : '';
+    console.log(
+      `  ${c.function}: ${c.variable} captured by goroutine${fixed} - ${c.filename}:${c.line}`
+    );
+  }
+};
+
+const analysis_conversions_between = async ({ project, from, to }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_conversions_between(
+    project_id,
+    from,
+    to
+  );
+
+  console.log(`\n=== Conversions Between ${from} and ${to}: ${project} ===\n`);
+  console.log(`Conversions: ${result.summary.total_conversions}`);
+  console.log(`  ${from} to ${to}: ${result.summary.forward}`);
+  console.log(`  ${to} to ${from}: ${result.summary.reverse}`);
+  console.log(`Functions: ${result.summary.functions}`);
+  console.log(`Unresolved: ${result.summary.unresolved}`);
+
+  if (result.conversions.length === 0) return;
+
+  console.log('\nConversions:');
+  for (const c of result.conversions) {
+    console.log(
+      `  ${c.function}: ${c.text} (${c.from} to ${c.to}) - ${c.filename}:${c.line}:${c.column}`
+    );
+  }
+};
+
+const analysis_method_sets = async ({ project, type }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_method_sets(project_id, type);
+
+  console.log(`\n=== Method Sets: ${result.package}.${result.type} ===\n`);
+  console.log(`Defined at: ${result.filename}:${result.line}`);
+
+  console.log(`\nValue method set (${result.type}):`);
+  for (const signature of result.value_method_set) {
+    console.log(`  ${signature}`);
+  }
+  console.log(`\nPointer method set (*${result.type}):`);
+  for (const signature of result.pointer_method_set) {
+    console.log(`  ${signature}`);
+  }
+  if (result.pointer_only.length > 0) {
+    console.log(`\nPointer only: ${result.pointer_only.join(', ')}`);
+  }
+
+  if (result.interfaces.length === 0) return;
+  console.log('\nInterfaces:');
+  for (const i of result.interfaces) {
+    const by = i.value ? result.type : `*${result.type} only`;
+    console.log(`  ${i.package}.${i.interface} - satisfied by ${by}`);
+  }
+};
+
+const analysis_interface_widths = async ({
+  project,
+  'max-methods': max_methods
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_interface_widths(project_id, {
+    max_methods
+  });
+
+  console.log(`\n=== Interface Widths: ${project} ===\n`);
+  console.log(`Interfaces: ${result.summary.total_interfaces}`);
+  console.log(`  Narrow (1 method): ${result.summary.narrow}`);
+  console.log(`  Regular: ${result.summary.regular}`);
+  console.log(
+    `  Wide (more than ${result.summary.max_methods}): ${result.summary.wide}`
+  );
+  console.log(`  Empty: ${result.summary.empty}`);
+  console.log(`  Constraints: ${result.summary.constraint}`);
+  console.log(`Average Methods: ${result.summary.average_methods}`);
+
+  if (result.interfaces.length === 0) return;
+
+  console.log('\nInterfaces:');
+  for (const i of result.interfaces) {
+    const count = `${i.method_count}${i.complete ? '' : '+'}`;
+    console.log(
+      `  ${i.package}.${i.name}: ${count} method${i.method_count === 1 ? '' : 's'} (${i.classification}) - ${i.filename}:${i.line}`
+    );
+    if (i.message) console.log(`    ${i.message}`);
+  }
+};
+
+const analysis_struct_tags = async ({
+  project,
+  require: required,
+  naming,
+  'include-embedded': include_embedded,
+  'include-unexported': include_unexported,
+  'all-structs': all_structs
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_struct_tags(project_id, {
+    required: typeof required === 'string' ? required.split(',') : undefined,
+    naming:
+      typeof naming === 'string'
+        ? Object.fromEntries(naming.split(',').map((rule) => rule.split(':')))
+        : undefined,
+    include_embedded,
+    include_unexported,
+    all_structs
+  });
+
+  console.log(`\n=== Struct Tags: ${project} ===\n`);
+  console.log(`Required: ${result.policy.required.join(', ') || 'none'}`);
+  console.log(
+    `Naming: ${
+      Object.entries(result.policy.naming)
+        .map(([key, rule]) => `${key} ${rule}`)
+        .join(', ') || 'none'
+    }`
+  );
+  console.log(`Structs Checked: ${result.summary.structs}`);
+  console.log(`Fields Checked: ${result.summary.fields}`);
+  console.log(`Diagnostics: ${result.summary.total_diagnostics}`);
+  for (const [rule, count] of Object.entries(result.summary.by_rule)) {
+    console.log(`  ${rule}: ${count}`);
+  }
+
+  if (result.diagnostics.length === 0) return;
+
+  console.log('\nDiagnostics:');
+  for (const d of result.diagnostics) {
+    console.log(`  ${d.filename}:${d.line}: ${d.message}`);
+  }
+};
+
+const analysis_coupling = async ({
+  project,
+  limit,
+  'include-tests': include_tests
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_coupling(project_id, {
+    limit,
+    include_tests
+  });
+
+  console.log(`\n=== Function Coupling: ${project} ===\n`);
+  console.log(`Functions: ${result.summary.total_functions}`);
+  console.log(`Average Fan-In: ${result.summary.average_fan_in}`);
+  console.log(`Average Fan-Out: ${result.summary.average_fan_out}`);
+  if (result.summary.highest_fan_in) {
+    const { id, fan_in } = result.summary.highest_fan_in;
+    console.log(`Highest Fan-In: ${id} (${fan_in})`);
+  }
+  if (result.summary.highest_fan_out) {
+    const { id, fan_out } = result.summary.highest_fan_out;
+    console.log(`Highest Fan-Out: ${id} (${fan_out})`);
+  }
+  console.log(`Isolated: ${result.summary.isolated}`);
+
+  if (result.top_coupled.length === 0) return;
+
+  console.log('\nMost Coupled:');
+  for (const fn of result.top_coupled) {
+    console.log(
+      `  ${fn.package}.${fn.name}: fan-in ${fn.fan_in}, fan-out ${fn.fan_out} - ${fn.filename}:${fn.line}`
+    );
+  }
+};
+
+const analysis_unsafe_usage = async ({ project, kind }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_unsafe_usage(project_id, {
+    kinds: kind ? kind.split(',').map((k) => k.trim()) : undefined
+  });
+
+  console.log(`\n=== Unsafe Usage: ${project} ===\n`);
+  console.log(`Files: ${result.summary.files}`);
+  console.log(`Functions: ${result.summary.functions}`);
+  console.log(`Sites: ${result.summary.sites}`);
+  console.log(`High Severity: ${result.summary.high_severity}`);
+  for (const [name, count] of Object.entries(result.summary.by_kind)) {
+    if (count > 0) console.log(`  ${name}: ${count}`);
+  }
+
+  for (const file of result.files) {
+    console.log(`\n${file.filename}:`);
+    for (const site of file.sites) {
+      const where = site.function ? ` in ${site.function}` : '';
+      console.log(
+        `  ${site.line}: [${site.severity}] ${site.message}${where}`
+      );
+    }
+  }
+};
+
+const analysis_call_chain = async ({
+  project,
+  root,
+  'max-depth': max_depth
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_call_chains(project_id, {
+    root,
+    max_depth
+  });
+
+  console.log(`\n=== Longest Call Chain: ${project} ===\n`);
+  console.log(`Roots: ${result.summary.roots}`);
+  console.log(`Longest: ${result.summary.longest} calls`);
+  console.log(`Cycles: ${result.summary.cycles}`);
+
+  for (const chain of result.chains) {
+    const truncated = chain.truncated ? ' (truncated)' : '';
+    console.log(`\n${chain.root}: ${chain.length} calls${truncated}`);
+    chain.chain.forEach(function print_step(step, depth) {
+      const recursive = step.recursive ? ' [recursive]' : '';
+      const call = step.call_line ? ` (called at line ${step.call_line})` : '';
+      console.log(
+        `  ${'  '.repeat(depth)}${step.package}.${step.name}${recursive} - ${step.filename}:${step.line}${call}`
+      );
+    });
+    for (const cycle of chain.cycles) {
+      console.log(`  cycle: ${cycle.from} -> ${cycle.to} (line ${cycle.line})`);
+    }
+  }
+};
+
+const analysis_inline_hints = async ({ project, budget, inlinable }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_inline_hints(project_id, {
+    budget,
+    inlinable_only: inlinable
+  });
+
+  console.log(`\n=== Inlining Hints: ${project} ===\n`);
+  console.log(`Functions: ${result.summary.total_functions}`);
+  console.log(`Inlinable: ${result.summary.inlinable}`);
+  console.log(`Budget: ${result.summary.budget}`);
+  for (const [reason, count] of Object.entries(result.summary.by_reason)) {
+    console.log(`  ${reason}: ${count}`);
+  }
+
+  if (result.functions.length === 0) return;
+
+  console.log('\nFunctions:');
+  for (const fn of result.functions) {
+    const { inlinable: candidate, cost, reasons } = fn.inline_hint;
+    const hint = candidate ? 'inlinable' : reasons.join(', ');
+    const estimate = cost === null ? '' : ` (cost ${cost})`;
+    console.log(
+      `  ${fn.package}.${fn.function}: ${hint}${estimate} - ${fn.filename}:${fn.line}`
+    );
+  }
+};
+
+const analysis_unused_interfaces = async ({ project, unexported }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_unused_interfaces(project_id, {
+    unexported_only: unexported
+  });
+
+  console.log(`\n=== Unused Interfaces: ${project} ===\n`);
+  console.log(`Interfaces: ${result.summary.total_interfaces}`);
+  console.log(`Unused: ${result.summary.unused}`);
+  console.log(`  Exported: ${result.summary.unused_exported}`);
+  console.log(`  Unexported: ${result.summary.unused_unexported}`);
+
+  if (result.interfaces.length === 0) return;
+
+  console.log('\nInterfaces:');
+  for (const iface of result.interfaces) {
+    const assertions =
+      iface.assertions > 0 ? ` (${iface.assertions} assertions)` : '';
+    console.log(
+      `  [${iface.severity}] ${iface.package}.${iface.name}${assertions} - ${iface.filename}:${iface.line}`
+    );
+    console.log(`    ${iface.message}`);
+  }
+};
+
+const analysis_type_complexity = async ({ project, limit, promoted }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_type_complexity(project_id, {
+    limit,
+    include_promoted: promoted
+  });
+
+  console.log(`\n=== Type Complexity: ${project} ===\n`);
+  console.log(`Types with methods: ${result.summary.total_types}`);
+  console.log(`Methods: ${result.summary.total_methods}`);
+  console.log(`Average complexity: ${result.summary.average_complexity}`);
+
+  if (result.top_types.length === 0) return;
+
+  console.log('\nMost complex types:');
+  for (const type of result.top_types) {
+    console.log(
+      `  ${type.package}.${type.name}: ${type.total_complexity} (${type.share}%, ${type.method_count} methods, max ${type.max_complexity}) - ${type.filename}:${type.line}`
+    );
+    for (const method of type.methods.slice(0, 3)) {
+      const from = method.promoted_from ? ` (from ${method.promoted_from})` : '';
+      console.log(`    ${method.name}${from}: ${method.complexity}`);
+    }
+  }
+};
+
+const analysis_format_calls = async ({ project, mismatched }) => {
+  const project_id = await get_project_id(project);
+  const result = await check_format_calls(project_id, {
+    mismatched_only: mismatched
+  });
+
+  console.log(`\n=== Format Calls: ${project} ===\n`);
+  console.log(`Calls: ${result.summary.total_calls}`);
+  console.log(
+    `Checked: ${result.summary.checked}, Unchecked: ${result.summary.unchecked}`
+  );
+  console.log(`Mismatched: ${result.summary.mismatched}`);
+  for (const [kind, count] of Object.entries(result.summary.by_issue)) {
+    if (count > 0) console.log(`  ${kind}: ${count}`);
+  }
+
+  if (result.calls.length === 0) return;
+
+  console.log('\nCalls:');
+  for (const call of result.calls) {
+    const where = call.enclosing ? ` in ${call.enclosing.name}` : '';
+    const status = !call.checked
+      ? 'unchecked'
+      : call.issues.length > 0
+        ? call.issues.map((i) => i.kind).join(', ')
+        : 'ok';
+    console.log(
+      `  ${call.filename}:${call.line}:${call.column} ${call.function}${where}: ${status}`
+    );
+    for (const issue of call.issues) {
+      console.log(`    ${issue.message}`);
+    }
+  }
+};
+
+const analysis_copy_locks = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_copy_locks(project_id);
+
+  console.log(`\n=== Copy Locks: ${project} ===\n`);
+  console.log(`No-copy types: ${result.summary.no_copy_types}`);
+  console.log(`Locks passed by value: ${result.summary.findings}`);
+  console.log(`  Receivers: ${result.summary.receivers}`);
+  console.log(`  Parameters: ${result.summary.parameters}`);
+
+  if (result.no_copy_types.length > 0) {
+    console.log('\nNo-copy types:');
+    for (const type of result.no_copy_types) {
+      const path = type.path ? `${type.path} ` : '';
+      console.log(
+        `  ${type.package}.${type.name}: ${path}${type.lock} - ${type.filename}:${type.line}`
+      );
+    }
+  }
+
+  if (result.findings.length > 0) {
+    console.log('\nPassed by value:');
+    for (const finding of result.findings) {
+      const name = finding.receiver
+        ? `${finding.receiver}.${finding.function}`
+        : finding.function;
+      console.log(
+        `  ${finding.package}.${name} - ${finding.filename}:${finding.line}`
+      );
+      console.log(`    ${finding.message}`);
+    }
+  }
+};
+
+const analysis_field_types = async ({ project, resolution, embedded }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_field_types(project_id, {
+    resolution,
+    include_embedded: embedded
+  });
+
+  console.log(`\n=== Field Types: ${project} ===\n`);
+  console.log(`Fields: ${result.summary.total_fields}`);
+  console.log(`Distinct types: ${result.summary.distinct_types}`);
+  console.log(`Resolution: ${result.summary.resolution}`);
+
+  if (result.types.length > 0) {
+    console.log('\nTypes:');
+    for (const type of result.types) {
+      console.log(
+        `  ${String(type.count).padStart(5)}  ${type.type} (${type.structs} structs)`
+      );
+    }
+  }
+
+  if (result.inconsistent_fields.length > 0) {
+    console.log('\nFields declared with different types:');
+    for (const field of result.inconsistent_fields) {
+      const types = Object.entries(field.types)
+        .map(([type, count]) => `${type} (${count})`)
+        .join(', ');
+      console.log(`  ${field.name}: ${types}`);
+    }
+  }
+};
+
+const analysis_method_implementers = async ({ project, name, signature }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_method_implementers(
+    project_id,
+    name,
+    signature
+  );
+
+  console.log(`\n=== Method Implementers: ${result.method} ===\n`);
+  console.log(`Implementers: ${result.summary.implementers}`);
+  console.log(`Packages: ${result.summary.packages}`);
+
+  if (result.implementers.length > 0) {
+    console.log('\nTypes:');
+    for (const i of result.implementers) {
+      const receiver = i.pointer_receiver ? `*${i.type}` : i.type;
+      console.log(`  ${i.package}.${receiver} - ${i.filename}:${i.line}`);
+    }
+  }
+
+  if (result.mismatches.length > 0) {
+    console.log('\nOther signatures:');
+    for (const i of result.mismatches) {
+      console.log(
+        `  ${i.package}.${i.type} ${i.signature} - ${i.filename}:${i.line}`
+      );
+    }
+  }
+};
+
+const analysis_deprecated_calls = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_deprecated_calls(project_id);
+
+  console.log(`\n=== Deprecated Calls: ${project} ===\n`);
+  console.log(`Calls: ${result.summary.total_calls}`);
+  console.log(`Files: ${result.summary.files}`);
+
+  for (const call of result.calls) {
+    console.log(`\n  ${call.filename}:${call.line}:${call.column}`);
+    console.log(`    ${call.message}`);
+  }
+};
+
+const analysis_interface_guideline = async ({ project, guideline }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_interface_guideline(project_id, {
+    guideline
+  });
+
+  console.log(`\n=== Accept Interfaces, Return Structs: ${project} ===\n`);
+  console.log(`Accept interfaces: ${result.summary.accept_interfaces}`);
+  console.log(`Return structs: ${result.summary.return_structs}`);
+
+  for (const issue of result.issues) {
+    console.log(`\n  ${issue.filename}:${issue.line} (${issue.guideline})`);
+    console.log(`    ${issue.message}`);
+  }
+};
+
+const analysis_function_values = async ({
+  project,
+  'values-only': values_only
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_function_values(project_id, {
+    values_only
+  });
+
+  console.log(`\n=== Function Values: ${project} ===\n`);
+  console.log(`Functions: ${result.summary.total_functions}`);
+  console.log(`Used as values: ${result.summary.used_as_value}`);
+  console.log(`Only called: ${result.summary.called_only}`);
+  console.log(`Unreferenced: ${result.summary.unreferenced}`);
+
+  for (const fn of result.functions) {
+    let usage = fn.calls > 0 ? 'called' : 'unreferenced';
+    if (fn.used_as_value) usage = 'value';
+    console.log(
+      `\n  ${fn.directory}: ${fn.name} (${usage}, ${fn.calls} calls)`
+    );
+    for (const site of fn.value_sites) {
+      console.log(`    ${site.filename}:${site.line} ${site.kind}`);
+    }
+  }
+};
+
+const analysis_magic_numbers = async ({ project, min, ignore }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_magic_numbers(project_id, {
+    min_occurrences: min,
+    ignore:
+      typeof ignore === 'string' ? ignore.split(',').map(Number) : undefined
+  });
+
+  console.log(`\n=== Magic Numbers: ${project} ===\n`);
+  console.log(`Magic numbers: ${result.summary.magic_numbers}`);
+  console.log(`Occurrences: ${result.summary.occurrences}`);
+
+  for (const number of result.numbers) {
+    console.log(`\n  ${number.message}`);
+    for (const site of number.sites) {
+      const enclosing = site.enclosing ? ` (${site.enclosing.name})` : '';
+      console.log(
+        `    ${site.filename}:${site.line}:${site.column}${enclosing}`
+      );
+    }
+  }
+};
+
+const analysis_error_results = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_error_results(project_id);
+
+  console.log(`\n=== Error Results: ${project} ===\n`);
+  console.log(
+    `Functions Returning an Error: ${result.summary.functions_with_error}`
+  );
+  console.log(`Misplaced: ${result.summary.misplaced}`);
+  console.log(`Several Errors: ${result.summary.multiple}\n`);
+
+  const misplaced = result.packages.flatMap((p) => p.misplaced);
+  if (misplaced.length === 0) {
+    console.log('Every error is the last result.');
+    return;
+  }
+
+  for (const item of misplaced) {
+    console.log(`${item.filename}:${item.line} ${item.message}`);
+  }
+};
+
+const analysis_options_structs = async ({
+  project,
+  'min-fields': min_fields
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_options_structs(project_id, {
+    min_fields
+  });
+
+  console.log(`\n=== Options Structs: ${project} ===\n`);
+  console.log(`Options structs: ${result.summary.options_structs}`);
+  console.log(`Functions: ${result.summary.functions}`);
+
+  for (const struct of result.structs) {
+    console.log(
+      `\n  ${struct.filename}:${struct.line} ${struct.name} (${struct.reason})`
+    );
+    for (const option of struct.options) {
+      const doc = option.doc ? ` - ${option.doc.split('\n')[0]}` : '';
+      console.log(`    ${option.name} ${option.type}${doc}`);
+    }
+    console.log(`    used by: ${struct.functions.join(', ')}`);
+  }
+};
+
+const analysis_handlers = async ({
+  project,
+  shape,
+  'functions-only': functions_only
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_handlers_of_shape(project_id, shape, {
+    functions_only
+  });
+
+  console.log(`\n=== Handlers: ${result.shape} ===\n`);
+  console.log(`Handlers: ${result.summary.handlers}`);
+  console.log(`Packages: ${result.summary.packages}`);
+
+  if (result.handlers.length > 0) {
+    console.log('');
+    for (const handler of result.handlers) {
+      console.log(
+        `  ${handler.filename}:${handler.line} ${handler.signature}`
+      );
+    }
+  }
+};
+
+const analysis_recursive_structs = async ({ project }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_recursive_structs(project_id, {
+    recursive_only: true
+  });
+
+  console.log(`\n=== Recursive Structs: ${project} ===\n`);
+  console.log(`Structs: ${result.summary.total_structs}`);
+  console.log(`Directly recursive: ${result.summary.direct}`);
+  console.log(`Mutually recursive: ${result.summary.mutual}`);
+
+  for (const struct of result.structs) {
+    console.log(
+      `\n  ${struct.filename}:${struct.line} ${struct.name} (${struct.recursion})`
+    );
+    for (const field of struct.self_fields) {
+      console.log(`    ${field.name} ${field.type}`);
+    }
+    if (struct.group.length > 0) {
+      console.log(`    group: ${struct.group.join(', ')}`);
+    }
+  }
+};
+
+const analysis_builtin_shadowing = async ({ project, problematic }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_builtin_shadowing(project_id, {
+    problematic_only: problematic
+  });
+
+  console.log(`\n=== Builtin Shadowing: ${project} ===\n`);
+  console.log(`Declarations: ${result.summary.total}`);
+  console.log(`Problematic: ${result.summary.problematic}`);
+  console.log(`Harmless: ${result.summary.harmless}\n`);
+
+  for (const shadow of result.shadows) {
+    const level = shadow.problematic ? shadow.reason : 'harmless';
+    console.log(
+      `  ${shadow.filename}:${shadow.line} [${level}] ${shadow.message}`
+    );
+  }
+};
+
+const analysis_state_machines = async ({ project, type, dot }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_state_machines(project_id, {
+    type,
+    dot
+  });
+
+  if (dot) {
+    for (const machine of result.machines) console.log(`${machine.dot}\n`);
+    return;
+  }
+
+  console.log(`\n=== State Machines: ${project} ===\n`);
+  console.log(`Machines: ${result.summary.machines}`);
+  console.log(`Complete: ${result.summary.complete}`);
+  console.log(`States: ${result.summary.states}`);
+  console.log(`Transitions: ${result.summary.transitions}`);
+
+  for (const machine of result.machines) {
+    const status = machine.complete ? 'complete' : 'incomplete';
+    console.log(
+      `\n  ${machine.filename}:${machine.line} ${machine.package}.${machine.type}.${machine.field} (${status})`
+    );
+    console.log(`    Initial: ${machine.initial || 'unknown'}`);
+    console.log(`    States: ${machine.states.map((s) => s.name).join(', ')}`);
+    for (const transition of machine.transitions) {
+      console.log(
+        `    ${transition.from.join('|')} -> ${transition.to} (${transition.method}, line ${transition.line})`
+      );
+    }
+    if (machine.unreachable.length > 0) {
+      console.log(`    Unreachable: ${machine.unreachable.join(', ')}`);
+    }
+  }
+};
+
+const analysis_package_metrics = async ({ project, threshold }) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_package_metrics(project_id, {
+    threshold
+  });
+
+  console.log(`\n=== Package Metrics: ${project} ===\n`);
+  console.log(`Packages: ${result.summary.packages}`);
+  console.log(`  Main sequence: ${result.summary.by_zone.main_sequence}`);
+  console.log(`  Zone of pain: ${result.summary.by_zone.pain}`);
+  console.log(`  Zone of uselessness: ${result.summary.by_zone.uselessness}`);
+  console.log(`Mean distance: ${result.summary.mean_distance}\n`);
+
+  for (const entry of result.packages) {
+    console.log(
+      `  ${entry.directory} A=${entry.abstractness} I=${entry.instability} D=${entry.distance} (Ca=${entry.afferent_coupling}, Ce=${entry.efferent_coupling}) ${entry.zone}`
+    );
+  }
+};
+
+const analysis_param_names = async ({ project, acceptable, reason }) => {
+  const project_id = await get_project_id(project);
+  const split = (value) =>
+    value ? value.split(',').map((v) => v.trim()).filter(Boolean) : undefined;
+  const result = await analyze_project_param_names(project_id, {
+    acceptable: split(acceptable),
+    reasons: split(reason)
+  });
+
+  console.log(`\n=== Parameter Names: ${project} ===\n`);
+  console.log(`Functions: ${result.summary.functions}`);
+  console.log(`Flagged: ${result.summary.flagged}`);
+  console.log(`Weak Parameters: ${result.summary.weak_params}`);
+  for (const [name, count] of Object.entries(result.summary.by_reason)) {
+    console.log(`  ${name}: ${count}`);
+  }
+  console.log('');
+
+  for (const fn of result.functions) {
+    console.log(`  ${fn.filename}:${fn.line} ${fn.message}`);
+  }
+};
+
+const analysis_state_aliasing = async ({
+  project,
+  'include-unexported': include_unexported
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_state_aliasing(project_id, {
+    include_unexported
+  });
+
+  console.log(`\n=== State Aliasing: ${project} ===\n`);
+  console.log(`Aliasing Returns: ${result.summary.aliases}`);
+  for (const [kind, count] of Object.entries(result.summary.by_kind)) {
+    console.log(`  ${kind}: ${count}`);
+  }
+  console.log(`Types: ${result.summary.types}`);
+  console.log(`Copies: ${result.summary.copies}`);
+  console.log('');
+
+  for (const alias of result.aliases) {
+    console.log(`  ${alias.filename}:${alias.line} ${alias.message}`);
+  }
+};
+
+const analysis_unused_interface_methods = async ({
+  project,
+  unexported,
+  confidence
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_project_unused_interface_methods(project_id, {
+    unexported_only: unexported,
+    confidence
+  });
+
+  console.log(`\n=== Unused Interface Methods: ${project} ===\n`);
+  console.log(`Interfaces: ${result.summary.interfaces}`);
+  console.log(`  Fully Used: ${result.summary.used_interfaces}`);
+  console.log(`  Partially Used: ${result.summary.partial_interfaces}`);
+  console.log(`  Unused: ${result.summary.unused_interfaces}`);
+  console.log(`Methods: ${result.summary.methods}`);
+  console.log(`Unused Methods: ${result.summary.unused}`);
+  console.log(`  High Confidence: ${result.summary.by_confidence.high}`);
+  console.log(`  Low Confidence: ${result.summary.by_confidence.low}`);
+  console.log('');
+
+  for (const method of result.unused) {
+    console.log(
+      `  [${method.confidence}] ${method.filename}:${method.line} ${method.message}`
+    );
+  }
+};
+
+const analysis_predicate_names = async ({ project, prefix, acceptable }) => {
+  const project_id = await get_project_id(project);
+  const split = (value) =>
+    value ? value.split(',').map((v) => v.trim()).filter(Boolean) : undefined;
+  const result = await analyze_project_predicate_names(project_id, {
+    prefixes: split(prefix),
+    acceptable: split(acceptable)
+  });
+
+  console.log(`\n=== Predicate Names: ${project} ===\n`);
+  console.log(`Bool Functions: ${result.summary.functions}`);
+  console.log(`Well Named: ${result.summary.well_named}`);
+  console.log(`Flagged: ${result.summary.flagged}`);
+  for (const [kind, count] of Object.entries(result.summary.by_kind)) {
+    console.log(`  ${kind}: ${count}`);
+  }
+  console.log('');
+
+  for (const fn of result.functions) {
+    console.log(`  ${fn.filename}:${fn.line} ${fn.message}`);
+  }
+};
+
+const analysis = {
+  command: 'analysis',
+  description: 'Code analysis tools',
+  commands: {
+    dashboard: analysis_dashboard,
+    'dead-code': analysis_dead_code,
+    duplication: analysis_duplication,
+    dependencies: analysis_dependencies,
+    security: analysis_security,
+    metrics: analysis_metrics,
+    smells: analysis_smells,
+    types: analysis_types,
+    api: analysis_api,
+    docs: analysis_docs,
+    scope: analysis_scope,
+    concurrency: analysis_concurrency,
+    resources: analysis_resources,
+    naming: analysis_naming,
+    readability: analysis_readability,
+    patterns: analysis_patterns,
+    tests: analysis_tests,
+    'struct-size': analysis_struct_size,
+    literals: analysis_literals,
+    channels: analysis_channels,
+    nesting: analysis_nesting,
+    'package-docs': analysis_package_docs,
+    'error-wrapping': analysis_error_wrapping,
+    'field-init': analysis_field_init,
+    'workspace-symbols': analysis_workspace_symbols,
+    'init-order': analysis_init_order,
+    'go-scripts': analysis_go_scripts,
+    'duplicate-methods': analysis_duplicate_methods,
+    'context-params': analysis_context_params,
+    'token-costs': analysis_token_costs,
+    stub: analysis_stub,
+    'struct-kinds': analysis_struct_kinds,
+    'near-misses': analysis_near_misses,
+    'build-constraints': analysis_build_constraints,
+    'smell-score': analysis_smell_score,
+    'method-shadowing': analysis_method_shadowing,
+    conversions: analysis_conversions,
+    repro: analysis_repro,
+    'concurrency-hints': analysis_concurrency_hints,
+    embedders: analysis_embedders,
+    escapes: analysis_escapes,
+    imports: analysis_imports,
+    'loop-captures': analysis_loop_captures,
+    'conversions-between': analysis_conversions_between,
+    'method-sets': analysis_method_sets,
+    'interface-widths': analysis_interface_widths,
+    'struct-tags': analysis_struct_tags,
+    coupling: analysis_coupling,
+    'unsafe-usage': analysis_unsafe_usage,
+    'call-chain': analysis_call_chain,
+    'inline-hints': analysis_inline_hints,
+    'unused-interfaces': analysis_unused_interfaces,
+    'type-complexity': analysis_type_complexity,
+    'format-calls': analysis_format_calls,
+    'copy-locks': analysis_copy_locks,
+    'field-types': analysis_field_types,
+    'method-implementers': analysis_method_implementers,
+    'deprecated-calls': analysis_deprecated_calls,
+    'interface-guideline': analysis_interface_guideline,
+    'function-values': analysis_function_values,
+    'magic-numbers': analysis_magic_numbers,
+    'error-results': analysis_error_results,
+    'options-structs': analysis_options_structs,
+    handlers: analysis_handlers,
+    'recursive-structs': analysis_recursive_structs,
+    'builtin-shadowing': analysis_builtin_shadowing,
+    'state-machines': analysis_state_machines,
+    'package-metrics': analysis_package_metrics,
+    'param-names': analysis_param_names,
+    'state-aliasing': analysis_state_aliasing,
+    'unused-interface-methods': analysis_unused_interface_methods,
+    'predicate-names': analysis_predicate_names
+  },
+  help,
+  command_help: {
+    dashboard: dashboard_help,
+    'dead-code': dead_code_help,
+    duplication: duplication_help,
+    dependencies: dependencies_help,
+    security: security_help,
+    metrics: metrics_help,
+    smells: smells_help,
+    types: types_help,
+    api: api_help,
+    docs: docs_help,
+    scope: scope_help,
+    concurrency: concurrency_help,
+    resources: resources_help,
+    naming: naming_help,
+    readability: readability_help,
+    patterns: patterns_help,
+    tests: tests_help,
+    'struct-size': struct_size_help,
+    literals: literals_help,
+    channels: channels_help,
+    nesting: nesting_help,
+    'package-docs': package_docs_help,
+    'error-wrapping': error_wrapping_help,
+    'field-init': field_init_help,
+    'workspace-symbols': workspace_symbols_help,
+    'init-order': init_order_help,
+    'go-scripts': go_scripts_help,
+    'duplicate-methods': duplicate_methods_help,
+    'context-params': context_params_help,
+    'token-costs': token_costs_help,
+    stub: stub_help,
+    'struct-kinds': struct_kinds_help,
+    'near-misses': near_misses_help,
+    'build-constraints': build_constraints_help,
+    'smell-score': smell_score_help,
+    'method-shadowing': method_shadowing_help,
+    conversions: conversions_help,
+    repro: repro_help,
+    'concurrency-hints': concurrency_hints_help,
+    embedders: embedders_help,
+    escapes: escapes_help,
+    imports: imports_help,
+    'loop-captures': loop_captures_help,
+    'conversions-between': conversions_between_help,
+    'method-sets': method_sets_help,
+    'interface-widths': interface_widths_help,
+    'struct-tags': struct_tags_help,
+    coupling: coupling_help,
+    'unsafe-usage': unsafe_usage_help,
+    'call-chain': call_chain_help,
+    'inline-hints': inline_hints_help,
+    'unused-interfaces': unused_interfaces_help,
+    'type-complexity': type_complexity_help,
+    'format-calls': format_calls_help,
+    'copy-locks': copy_locks_help,
+    'field-types': field_types_help,
+    'method-implementers': method_implementers_help,
+    'deprecated-calls': deprecated_calls_help,
+    'interface-guideline': interface_guideline_help,
+    'function-values': function_values_help,
+    'magic-numbers': magic_numbers_help,
+    'error-results': error_results_help,
+    'options-structs': options_structs_help,
+    handlers: handlers_help,
+    'recursive-structs': recursive_structs_help,
+    'builtin-shadowing': builtin_shadowing_help,
+    'state-machines': state_machines_help,
+    'package-metrics': package_metrics_help,
+    'param-names': param_names_help,
+    'state-aliasing': state_aliasing_help,
+    'unused-interface-methods': unused_interface_methods_help,
+    'predicate-names': predicate_names_help
+  },
+  command_arguments: {
+    dashboard: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'dead-code': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    duplication: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      threshold: {
+        type: 'number',
+        description: 'Similarity threshold 0.0-1.0 (default 0.7)'
+      }
+    },
+    dependencies: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    security: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    metrics: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    smells: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    types: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    api: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    docs: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    scope: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    concurrency: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    resources: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    naming: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    readability: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    patterns: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    tests: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'struct-size': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      threshold: {
+        type: 'number',
+        description: 'Size in bytes above which a struct is flagged (default 80)'
+      }
+    },
+    literals: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      kind: {
+        type: 'string',
+        description: 'Only list literals of this kind (string, rune, number)'
+      },
+      'inline-only': {
+        type: 'boolean',
+        description: 'Only list inline literals'
+      }
+    },
+    channels: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      package: {
+        type: 'string',
+        description: 'Only model this package (name or directory)'
+      },
+      function: {
+        type: 'string',
+        description: 'Only show operations of this function'
+      }
+    },
+    nesting: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'package-docs': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'error-wrapping': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      expected: {
+        type: 'string',
+        description: 'Expected convention (auto, wrap, no-wrap)'
+      }
+    },
+    'field-init': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'workspace-symbols': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      query: {
+        type: 'string',
+        description: 'Fuzzy query'
+      },
+      limit: {
+        type: 'number',
+        description: 'Maximum number of symbols'
+      }
+    },
+    'init-order': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'go-scripts': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'duplicate-methods': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      'min-statements': {
+        type: 'number',
+        description: 'Minimum statements of a reported method (default 2)'
+      }
+    },
+    'context-params': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      'allow-before': {
+        type: 'string',
+        description: 'Comma separated parameter types allowed before the context'
+      }
+    },
+    'token-costs': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      tokenizer: {
+        type: 'string',
+        description: 'Tokenizer to count with (code, chars, whitespace)'
+      },
+      limit: {
+        type: 'number',
+        description: 'Only show the most expensive symbols (default 20)'
+      }
+    },
+    stub: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      interface: {
+        type: 'string',
+        description: 'Interface name, optionally qualified by package'
+      },
+      type: {
+        type: 'string',
+        description: 'Name of the stub type'
+      }
+    },
+    'struct-kinds': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      }
+    },
+    'near-misses': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      type: {
+        type: 'string',
+        description: 'Type name, optionally qualified by package'
+      },
+      interface: {
+        type: 'string',
+        description: 'Interface name, optionally qualified by package'
+      },
+      'max-missing': {
+        type: 'number',
+        description: 'Largest number of missing or mismatched methods (default 1)'
+      }
+    },
+    'build-constraints': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      goos: {
+        type: 'string',
+        description: 'Target operating system'
+      },
+      goarch: {
+        type: 'string',
+        description: 'Target architecture'
+      },
+      tags: {
+        type: 'string',
+        description: 'Comma separated extra build tags of the target'
+      }
+    },
+    'smell-score': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      weights: {
+        type: 'string',
+        description: 'Comma separated code=weight overrides'
+      },
+      limit: {
+        type: 'number',
+        description: 'Only show the worst symbols and files (default 20)'
+      }
+    },
+    'method-shadowing': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      accidental: {
+        type: 'boolean',
+        description: 'Only show accidental collisions'
+      }
+    },
+    conversions: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      unchecked: {
+        type: 'boolean',
+        description: 'Only show functions with unchecked assertions'
+      }
+    },
+    repro: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      symbol: {
+        type: 'string',
+        description: 'Symbol name, optionally qualified by package'
+      }
+    },
+    'concurrency-hints': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      hint: {
+        type: 'string',
+        description: 'Only show functions with this hint (safe, unknown or unsafe)'
+      }
+    },
+    embedders: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      type: {
+        type: 'string',
+        description: 'Embedded type, optionally qualified by package'
+      },
+      transitive: {
+        type: 'boolean',
+        description: 'Include types embedding the type through other types'
+      },
+      unexported: {
+        type: 'boolean',
+        description: 'Include unexported embedders'
+      }
+    },
+    escapes: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      'no-composites': {
+        type: 'boolean',
+        description: 'Do not list composite literals'
+      }
+    },
+    imports: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      unused: {
+        type: 'boolean',
+        description: 'Only show files with unused imports'
+      }
+    },
+    'loop-captures': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      'go-version': {
+        type: 'string',
+        description: 'Target Go version (the go directive of go.mod)'
+      },
+      'include-fixed': {
+        type: 'boolean',
+        description: 'Include captures fixed by the Go version'
+      }
+    },
+    'conversions-between': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      from: {
+        type: 'string',
+        required: true,
+        description: 'Type converted from (e.g. Celsius)'
+      },
+      to: {
+        type: 'string',
+        required: true,
+        description: 'Type converted to (e.g. Fahrenheit)'
+      }
+    },
+    'method-sets': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      type: {
+        type: 'string',
+        required: true,
+        description: 'Type name, optionally qualified (e.g. Counter, counter.Counter)'
+      }
+    },
+    'interface-widths': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      'max-methods': {
+        type: 'number',
+        description: 'Largest number of methods of an interface that is not wide (default 10)'
+      }
+    },
+    'struct-tags': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      require: {
+        type: 'string',
+        description: 'Comma separated tag keys every field must have (default json)'
+      },
+      naming: {
+        type: 'string',
+        description: 'Comma separated key:rule naming rules (default json:snake_case)'
+      },
+      'include-embedded': {
+        type: 'boolean',
+        description: 'Check embedded fields'
+      },
+      'include-unexported': {
+        type: 'boolean',
+        description: 'Check unexported fields'
+      },
+      'all-structs': {
+        type: 'boolean',
+        description: 'Check structs without any tag of the policy keys'
+      }
+    },
+    coupling: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      limit: {
+        type: 'number',
+        description: 'Number of most coupled functions to list (default 20)'
+      },
+      'include-tests': {
+        type: 'boolean',
+        description: 'Include the functions of test files'
+      }
+    },
+    'unsafe-usage': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      kind: {
+        type: 'string',
+        description:
+          'Comma separated kinds to report: unsafe, reflect, linkname, assembly'
+      }
+    },
+    'call-chain': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      root: {
+        type: 'string',
+        description: 'Root function (default: the main functions)'
+      },
+      'max-depth': {
+        type: 'number',
+        description: 'Maximum number of calls in a chain (default 50)'
+      }
+    },
+    'inline-hints': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      budget: {
+        type: 'number',
+        description: 'Inlining budget in syntax tree nodes (default 80)'
+      },
+      inlinable: {
+        type: 'boolean',
+        description: 'Only list the inlining candidates'
+      }
+    },
+    'unused-interfaces': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      unexported: {
+        type: 'boolean',
+        description: 'Only list the unexported interfaces'
       }
-      if (data.locations.length > 3) {
-        console.log(`      ... and ${data.locations.length - 3} more`);
+    },
+    'type-complexity': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      limit: {
+        type: 'number',
+        description: 'Number of types to list (default 10)'
+      },
+      promoted: {
+        type: 'boolean',
+        description: 'Include the methods promoted from embedded types'
       }
-    }
-    console.log();
-  }
-
-  // Anti-patterns
-  if (result.anti_patterns && result.anti_patterns.length > 0) {
-    console.log(`Anti-patterns Detected (${result.anti_patterns.length}):\n`);
-    for (const ap of result.anti_patterns.slice(0, 10)) {
-      console.log(`  * ${ap.pattern}: ${ap.symbol}`);
-      console.log(`      ${ap.filename}:${ap.line}`);
-      console.log(`      ${ap.description}`);
-    }
-    console.log();
-  }
-
-  // Error handling issues
-  if (result.error_handling_issues && result.error_handling_issues.length > 0) {
-    console.log(
-      `Error Handling Issues (${result.error_handling_issues.length}):\n`
-    );
-    for (const issue of result.error_handling_issues.slice(0, 10)) {
-      console.log(
-        `  [${issue.severity}] ${issue.symbol} - ${issue.filename}:${issue.line}`
-      );
-      console.log(`    ${issue.message}`);
-    }
-    console.log();
-  }
-
-  if (result.summary.total_patterns_detected === 0) {
-    console.log('No design patterns detected in this project.');
-  }
-};
-
-const analysis_tests = async ({ project }) => {
-  const project_id = await get_project_id(project);
-  const result = await analyze_project_test_coverage(project_id);
-
-  console.log(`\n=== Test Analysis: ${project} ===\n`);
-
-  // Summary
-  console.log('Summary:');
-  console.log(`  Total Files: ${result.summary.total_files}`);
-  console.log(`  Test Files: ${result.summary.test_files}`);
-  console.log(`  Non-Test Files: ${result.summary.non_test_files}`);
-  console.log(`  Total Functions: ${result.summary.total_functions}`);
-  console.log(`  Test Functions: ${result.summary.test_functions}`);
-  console.log(`  Non-Test Functions: ${result.summary.non_test_functions}`);
-  console.log(`  Test-to-Code Ratio: ${result.summary.test_to_code_ratio}`);
-  console.log();
-
-  // Assertion stats
-  console.log('Assertion Statistics:');
-  console.log(`  Total Assertions: ${result.summary.total_assertions}`);
-  console.log(
-    `  Avg Assertions per Test: ${result.summary.avg_assertions_per_test}`
-  );
-  console.log(`  Total Mocks/Stubs: ${result.summary.total_mocks}`);
-  console.log();
-
-  // By language
-  if (
-    result.summary.by_language &&
-    Object.keys(result.summary.by_language).length > 0
-  ) {
-    console.log('By Language:');
-    for (const [lang, stats] of Object.entries(result.summary.by_language)) {
-      console.log(
-        `  ${lang}: ${stats.test_functions}/${stats.total_functions} test functions (${stats.test_ratio}%)`
-      );
-    }
-    console.log();
-  }
-
-  // Test files
-  if (result.test_files && result.test_files.length > 0) {
-    console.log(`Test Files (${result.test_files.length}):\n`);
-    for (const f of result.test_files.slice(0, 15)) {
-      console.log(`  * ${f.filename} (${f.language})`);
-    }
-    if (result.test_files.length > 15) {
-      console.log(`  ... and ${result.test_files.length - 15} more`);
-    }
-    console.log();
-  }
-
-  // Top test functions by assertions
-  if (result.test_functions && result.test_functions.length > 0) {
-    console.log('Top Test Functions (by assertions):\n');
-    for (const f of result.test_functions.slice(0, 10)) {
-      console.log(
-        `  * ${f.symbol} - ${f.assertions} assertions, ${f.mocks} mocks`
-      );
-      console.log(`      ${f.filename}:${f.line}`);
-    }
-    console.log();
-  }
-
-  // Coverage gaps
-  if (result.coverage_gaps && result.coverage_gaps.length > 0) {
-    console.log(
-      `Potential Coverage Gaps (${result.coverage_gaps.length}${result.coverage_gaps_truncated ? '+' : ''}):\n`
-    );
-    for (const gap of result.coverage_gaps.slice(0, 15)) {
-      console.log(`  * ${gap.filename}`);
-      console.log(`      ${gap.message}`);
-    }
-    if (result.coverage_gaps.length > 15) {
-      console.log(`  ... and ${result.coverage_gaps.length - 15} more`);
-    }
-    console.log();
-  }
-
-  // Naming issues
-  if (result.naming_issues && result.naming_issues.length > 0) {
-    console.log(
-      `Test Naming Issues (${result.naming_issues.length}${result.naming_issues_truncated ? '+' : ''}):\n`
-    );
-    for (const issue of result.naming_issues.slice(0, 10)) {
-      console.log(`  * ${issue.symbol} - ${issue.filename}:${issue.line}`);
-      for (const i of issue.issues) {
-        console.log(`      - ${i}`);
+    },
+    'format-calls': {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      mismatched: {
+        type: 'boolean',
+        description: 'Only list the calls with issues'
       }
-    }
-    console.log();
-  }
-
-  if (result.summary.test_functions === 0) {
-    console.log('No test functions found in this project.');
-  }
-};
-
-const analysis = {
-  command: 'analysis',
-  description: 'Code analysis tools',
-  commands: {
-    dashboard: analysis_dashboard,
-    'dead-code': analysis_dead_code,
-    duplication: analysis_duplication,
-    dependencies: analysis_dependencies,
-    security: analysis_security,
-    metrics: analysis_metrics,
-    smells: analysis_smells,
-    types: analysis_types,
-    api: analysis_api,
-    docs: analysis_docs,
-    scope: analysis_scope,
-    concurrency: analysis_concurrency,
-    resources: analysis_resources,
-    naming: analysis_naming,
-    readability: analysis_readability,
-    patterns: analysis_patterns,
-    tests: analysis_tests
-  },
-  help,
-  command_help: {
-    dashboard: dashboard_help,
-    'dead-code': dead_code_help,
-    duplication: duplication_help,
-    dependencies: dependencies_help,
-    security: security_help,
-    metrics: metrics_help,
-    smells: smells_help,
-    types: types_help,
-    api: api_help,
-    docs: docs_help,
-    scope: scope_help,
-    concurrency: concurrency_help,
-    resources: resources_help,
-    naming: naming_help,
-    readability: readability_help,
-    patterns: patterns_help,
-    tests: tests_help
-  },
-  command_arguments: {
-    dashboard: {
+    },
+    'copy-locks': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
       }
     },
-    'dead-code': {
+    'field-types': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      resolution: {
+        type: 'string',
+        description: 'Type resolution: none, alias or underlying (default alias)'
+      },
+      embedded: {
+        type: 'boolean',
+        description: 'Count embedded fields'
       }
     },
-    duplication: {
+    'method-implementers': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
       },
-      threshold: {
-        type: 'number',
-        description: 'Similarity threshold 0.0-1.0 (default 0.7)'
+      name: {
+        type: 'string',
+        required: true,
+        description: 'Method name (e.g. String)'
+      },
+      signature: {
+        type: 'string',
+        required: true,
+        description: 'Method signature (e.g. "() string")'
       }
     },
-    dependencies: {
+    'deprecated-calls': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
       }
     },
-    security: {
+    'interface-guideline': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      guideline: {
+        type: 'string',
+        description: 'Only check one guideline: accept_interfaces or return_structs'
       }
     },
-    metrics: {
+    'function-values': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      'values-only': {
+        type: 'boolean',
+        description: 'Only list functions used as values'
       }
     },
-    smells: {
+    'magic-numbers': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      min: {
+        type: 'number',
+        description: 'Number of uses from which a number is magic (default 3)'
+      },
+      ignore: {
+        type: 'string',
+        description: 'Comma-separated numbers never reported (default 0,1,-1,2)'
       }
     },
-    types: {
+    'error-results': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
       }
     },
-    api: {
+    'options-structs': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      'min-fields': {
+        type: 'number',
+        description: 'Exported fields from which a data struct is an options struct (default 4)'
       }
     },
-    docs: {
+    handlers: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      shape: {
+        type: 'string',
+        required: true,
+        description: 'Handler shape (e.g. "func(context.Context) error")'
+      },
+      'functions-only': {
+        type: 'boolean',
+        description: 'Leave out methods'
+      }
+    },
+    'recursive-structs': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
       }
     },
-    scope: {
+    'builtin-shadowing': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      problematic: {
+        type: 'boolean',
+        description: 'Only list problematic shadowing'
       }
     },
-    concurrency: {
+    'state-machines': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      type: {
+        type: 'string',
+        description: 'Only infer the state machine of this type'
+      },
+      dot: {
+        type: 'boolean',
+        description: 'Print each state machine as a Graphviz digraph'
       }
     },
-    resources: {
+    'package-metrics': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      threshold: {
+        type: 'number',
+        description: 'Distance from the main sequence above which a package is in a zone (default 0.5)'
       }
     },
-    naming: {
+    'param-names': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      acceptable: {
+        type: 'string',
+        description: 'Comma separated short names to accept, replacing the default list'
+      },
+      reason: {
+        type: 'string',
+        description: 'Comma separated reasons to report (unnamed, blank, numbered, short)'
       }
     },
-    readability: {
+    'state-aliasing': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      'include-unexported': {
+        type: 'boolean',
+        description: 'Also check unexported methods'
       }
     },
-    patterns: {
+    'unused-interface-methods': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      unexported: {
+        type: 'boolean',
+        description: 'Only report the methods of unexported interfaces'
+      },
+      confidence: {
+        type: 'string',
+        description: 'Only report the methods of this confidence (high or low)'
       }
     },
-    tests: {
+    'predicate-names': {
       project: {
         type: 'string',
         description: 'Name of the project',
         required: true
+      },
+      prefix: {
+        type: 'string',
+        description: 'Comma separated predicate prefixes, replacing Is, Has, Can and Should'
+      },
+      acceptable: {
+        type: 'string',
+        description: 'Comma separated names to accept, replacing the default list'
       }
     }
   }
@@ -0,0 +1,96 @@
+'use strict';
+
+import { writeFile } from 'fs/promises';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  export_api_catalog,
+  build_source_catalog,
+  format_api_catalog
+} from '../../analysis/index.mjs';
+import {
+  read_go_sources,
+  read_module_path,
+  get_output_paths
+} from '../sources.mjs';
+
+const help = `usage: cb catalog [<dir>] [--project=<project>] [-o <file>] [--module=<path>]
+
+Generate a JSON catalog of the public API of every Go package of a code
+base: exported types with their fields and methods, functions, constants
+and variables, each with its signature, doc comment and position.  The
+catalog is written to standard output unless an output file is given.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk, and the module path is read from its go.mod.
+
+Entries are identified by <directory>:<qualifiedName> (the ids of
+cb search-index) and cross-linked by them:
+
+  * links - Catalog types the declaration of an entry refers to
+  * referenced_by - Entries referring to a type
+
+The catalog carries its schema_version and a generated_at timestamp.
+Packages are sorted by directory and entries by qualified name, so that
+catalogs of the same code only differ by their timestamp.  Set
+SOURCE_DATE_EPOCH to fix the timestamp for reproducible output.
+
+Arguments:
+
+  * <dir> - Directory to catalog
+  * --project=[project] - Name of an imported project to catalog instead
+  * -o [file], --output=[file] - File to write (default: standard output)
+  * --module=[path] - Module path, to give packages their import path
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+// Helper to get the generation timestamp, fixed by SOURCE_DATE_EPOCH
+const get_generated_at = () => {
+  const epoch = Number(process.env.SOURCE_DATE_EPOCH);
+  if (process.env.SOURCE_DATE_EPOCH && Number.isInteger(epoch)) {
+    return new Date(epoch * 1000).toISOString();
+  }
+  return new Date().toISOString();
+};
+
+const handler = async (argv) => {
+  const { directory, output } = get_output_paths(argv);
+  const options = {
+    module: typeof argv.module === 'string' ? argv.module : undefined,
+    generated_at: get_generated_at()
+  };
+
+  let result;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    result = await export_api_catalog(project_id, options);
+  } else {
+    if (!options.module) options.module = await read_module_path(directory);
+    result = build_source_catalog(await read_go_sources(directory), options);
+  }
+
+  const content = format_api_catalog(result);
+  if (!output) {
+    process.stdout.write(content);
+    return;
+  }
+
+  await writeFile(output, content);
+  console.log(`Wrote ${result.summary.packages} packages to ${output}`);
+};
+
+const catalog = {
+  command: 'catalog',
+  description: 'Generate a JSON catalog of the public Go API',
+  handler,
+  help
+};
+
+export { catalog };
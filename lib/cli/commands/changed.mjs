@@ -0,0 +1,170 @@
+'use strict';
+
+import { diff_revisions } from '../../analysis/index.mjs';
+import { run_git } from '../sources.mjs';
+
+const help = `usage: cb changed <revision_a> [<revision_b>] [--breaking-only] [--tests] [--format=<format>]
+
+List the Go symbols that changed between two revisions of the current git
+repository: what actually changed, semantically, rather than which lines.
+Both revisions are parsed in full, so packages and files added or removed
+between them are compared too.
+
+Each changed symbol is added, removed, renamed (found unchanged under
+another name), moved (found unchanged in another package) or modified,
+with the declaration changes reported as by "cb compare".  Changes to the
+exported API of a package are flagged as breaking.
+
+Arguments:
+
+  * <revision_a> - Old revision, any git revision such as main or HEAD~3 (required)
+  * <revision_b> - New revision (default: HEAD)
+  * --breaking-only - Only list breaking changes
+  * --tests - Compare _test.go files too
+  * --format=[format] - Output format: text or json (default: text)
+`;
+
+const CHANGE_MARKERS = {
+  added: '+',
+  removed: '-',
+  renamed: 'R',
+  moved: 'M',
+  modified: '~'
+};
+
+const STATUS_MARKERS = {
+  added: 'A',
+  deleted: 'D',
+  modified: 'M',
+  renamed: 'R'
+};
+
+// A revision source reading files from the revisions of a git repository
+const create_git_source = (root) => {
+  return {
+    list_files: async (revision) => {
+      const output = await run_git(
+        ['ls-tree', '-r', '-z', '--name-only', revision],
+        root
+      );
+      return output.split('\0').filter(Boolean);
+    },
+    read_file: async (revision, filename) => {
+      try {
+        return await run_git(['show', `${revision}:${filename}`], root);
+      } catch {
+        return null;
+      }
+    }
+  };
+};
+
+// Helper to format a value in a change record
+const format_value = (value) => {
+  if (value === null || value === undefined) return '(none)';
+  if (typeof value === 'object') return value.type || value.signature || '';
+  return String(value).replace(/\s+/g, ' ');
+};
+
+const format_change = (change) => {
+  const name = change.name ? ` ${change.name}` : '';
+  const property = change.property ? ` ${change.property}` : '';
+  const label = `${change.aspect}${name}${property} ${change.change}`;
+  if (change.aspect === 'body') {
+    return `${label}: ${change.before} -> ${change.after} lines`;
+  }
+  if (change.change === 'added') {
+    return `${label}: ${format_value(change.after)}`;
+  }
+  if (change.change === 'removed') {
+    return `${label}: ${format_value(change.before)}`;
+  }
+  return `${label}: ${format_value(change.before)} -> ${format_value(change.after)}`;
+};
+
+const print_text = (result) => {
+  const { summary } = result;
+  console.log(`\n=== Changed: ${result.from}..${result.to} ===\n`);
+  console.log(
+    `${summary.symbols_changed} symbols changed in ${summary.files_changed} files (${summary.breaking_changes} breaking)`
+  );
+
+  if (result.files.length > 0) {
+    console.log('\nFiles:');
+    for (const file of result.files) {
+      const renamed = file.old_filename ? ` (from ${file.old_filename})` : '';
+      console.log(
+        `  ${STATUS_MARKERS[file.status]} ${file.filename}${renamed}`
+      );
+    }
+  }
+
+  if (result.packages.length > 0) {
+    console.log('\nPackages:');
+    for (const pkg of result.packages) {
+      const flag = pkg.breaking ? ' [breaking]' : '';
+      const names =
+        pkg.status === 'renamed' ? ` (${pkg.old_name} -> ${pkg.name})` : '';
+      console.log(`  ${pkg.status} ${pkg.directory}${names}${flag}`);
+    }
+  }
+
+  if (result.symbols.length === 0) {
+    console.log('\nNo symbol changes.');
+    return;
+  }
+
+  console.log('\nSymbols:');
+  for (const symbol of result.symbols) {
+    const flag = symbol.breaking ? ' [breaking]' : '';
+    let from = '';
+    if (symbol.old_name || symbol.old_directory) {
+      const directory = symbol.old_directory || symbol.directory;
+      from = ` (from ${directory}.${symbol.old_name || symbol.name})`;
+    }
+    const location =
+      symbol.change === 'removed' ? '' : ` - ${symbol.filename}:${symbol.line}`;
+    console.log(
+      `  ${CHANGE_MARKERS[symbol.change]} ${symbol.kind} ${symbol.directory}.${symbol.name}${from}${flag}${location}`
+    );
+    for (const change of symbol.changes) {
+      const breaking = change.breaking ? ' [breaking]' : '';
+      console.log(`      ${format_change(change)}${breaking}`);
+    }
+  }
+};
+
+const handler = async (argv) => {
+  const [from, to = 'HEAD'] = argv._.map(String);
+  if (!from) {
+    console.log(help);
+    return;
+  }
+  const format = typeof argv.format === 'string' ? argv.format : 'text';
+  if (!['text', 'json'].includes(format)) {
+    throw new Error(`Unknown format '${format}' (expected text or json)`);
+  }
+
+  const root = (
+    await run_git(['rev-parse', '--show-toplevel'])
+  ).trim();
+  const result = await diff_revisions(create_git_source(root), from, to, {
+    breaking_only: Boolean(argv['breaking-only']),
+    include_tests: Boolean(argv.tests)
+  });
+
+  if (format === 'json') {
+    console.log(JSON.stringify(result, null, 2));
+  } else {
+    print_text(result);
+  }
+};
+
+const changed = {
+  command: 'changed',
+  description: 'List the symbols changed between two git revisions',
+  handler,
+  help
+};
+
+export { changed };
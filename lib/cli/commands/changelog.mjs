@@ -0,0 +1,92 @@
+'use strict';
+
+import { writeFile } from 'fs/promises';
+import { diff_api_catalogs, format_changelog } from '../../analysis/index.mjs';
+import { read_json } from '../sources.mjs';
+
+const help = `usage: cb changelog <old-catalog> <new-catalog> [-o <file>] [--title=<title>] [--template=<file>] [--json]
+
+Write a Markdown changelog of the public Go API from two catalogs of
+cb catalog, grouped by package: the symbols added, changed, removed and
+newly deprecated (with a Deprecated: paragraph in their doc comment).
+Changes that break the API are flagged: removed symbols, changed
+parameter, result and field types, and methods added to interfaces.
+Doc and body changes are left out.
+
+Packages are sorted by directory and symbols by qualified name, and no
+timestamp is written, so that the changelog of the same catalogs is
+always the same.
+
+The template is a JSON object replacing some of the line formats, with
+{name} placeholders:
+
+  * title - {title}
+  * summary - {added}, {changed}, {removed}, {deprecated}, {breaking}
+  * package - {package}, {directory}, {import_path}
+  * section - {section}
+  * added, removed - {breaking}, {name}, {kind}, {signature}
+  * changed - {breaking}, {name}, {kind}, {before}, {after}
+  * deprecated - {name}, {kind}, {signature}, {notice}
+  * breaking - the marker {breaking} stands for on breaking changes
+
+Arguments:
+
+  * <old-catalog> - Catalog of the previous release
+  * <new-catalog> - Catalog of the new release
+  * -o [file], --output=[file] - File to write (default: standard output)
+  * --title=[title] - Changelog title (default: API changes)
+  * --template=[file] - JSON file with line formats
+  * --json - Write the changes as JSON instead of Markdown
+`;
+
+// Helper to split the arguments into the catalogs and the output file of
+// --output=<file>, -o=<file> or -o <file>; flags are parsed as booleans, so
+// the file of -o <file> is the last positional argument
+const get_paths = (argv) => {
+  const positional = argv._.map(String);
+  let output = null;
+  if (typeof argv.output === 'string') output = argv.output;
+  else if (typeof argv.o === 'string') output = argv.o;
+  else if (argv.o === true && positional.length > 2) output = positional.pop();
+  return { before: positional[0], after: positional[1], output };
+};
+
+const handler = async (argv) => {
+  const { before, after, output } = get_paths(argv);
+  if (!before || !after) {
+    throw new Error('Give the old and the new catalog');
+  }
+
+  const diff = diff_api_catalogs(
+    await read_json(before, 'catalog'),
+    await read_json(after, 'catalog')
+  );
+  const content = argv.json
+    ? `${JSON.stringify(diff, null, 2)}\n`
+    : format_changelog(diff, {
+        title: typeof argv.title === 'string' ? argv.title : undefined,
+        template:
+          typeof argv.template === 'string'
+            ? await read_json(argv.template, 'template')
+            : undefined
+      });
+
+  if (!output) {
+    process.stdout.write(content);
+    return;
+  }
+
+  await writeFile(output, content);
+  console.log(
+    `Wrote ${diff.summary.packages} packages (${diff.summary.breaking} breaking changes) to ${output}`
+  );
+};
+
+const changelog = {
+  command: 'changelog',
+  description: 'Write a Markdown changelog of the Go API from two catalogs',
+  handler,
+  help
+};
+
+export { changelog };
@@ -0,0 +1,86 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_cheatsheets,
+  build_source_cheatsheets
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb cheatsheet [<dir>] [--project=<project>] [--package=<dir>] [--max-lines=<n>] [--all] [--json]
+
+Print a terse reference card of the public API of each Go package, to
+keep open while using it: the constructors of each exported type, its
+methods grouped by construction, mutation and query, then the free
+functions, each with a one-line signature and the first sentence of its
+doc comment.
+
+A card is kept within a screenful by leaving out the least important
+entries, with a count of those left out: constructors come first, then
+the functions and methods called most often (tests included), then the
+documented ones.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --package=[dir] - Only print the card of the package in this directory
+  * --max-lines=[n] - Lines of each card (default: 50)
+  * --all - List every entry, whatever the length of the card
+  * --json - Write the cards as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = {
+    max_lines: argv.all
+      ? 0
+      : argv['max-lines'] !== undefined
+        ? parseInt(argv['max-lines'], 10)
+        : undefined,
+    directory: typeof argv.package === 'string' ? argv.package : undefined
+  };
+
+  let result;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_cheatsheets(project_id, options);
+  } else {
+    const target = argv._[0] ? String(argv._[0]) : '.';
+    result = build_source_cheatsheets(await read_go_sources(target), options);
+  }
+
+  if (argv.json) {
+    process.stdout.write(`${JSON.stringify(result, null, 2)}\n`);
+    return;
+  }
+
+  if (result.sheets.length === 0) {
+    console.log('No Go packages found');
+    return;
+  }
+  for (const sheet of result.sheets) {
+    console.log('');
+    for (const line of sheet.lines) console.log(line);
+  }
+};
+
+const cheatsheet = {
+  command: 'cheatsheet',
+  description: 'Print a quick reference card of the API of Go packages',
+  handler,
+  help
+};
+
+export { cheatsheet };
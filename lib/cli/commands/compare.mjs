@@ -0,0 +1,124 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import { import_file } from '../../sourcecode.mjs';
+import {
+  compare_symbol,
+  compare_symbol_sources
+} from '../../analysis/index.mjs';
+
+const help = `usage: cb compare <symbol> <file_a> <file_b> [--project=<project>]
+
+Compare a single symbol's declaration between two Go files, for example to
+check that a refactor or a move between files preserved its API.
+
+Reports changes to:
+- Signature (parameters, results and type parameters)
+- Receiver (including pointer vs value receivers)
+- Struct fields, interface methods and embedded types
+- Constant and variable types and values
+- Doc comments and function bodies (not API changes)
+
+Methods are named as Type.Method.  With --project the files are read from
+the imported project, otherwise they are read from disk.
+
+Arguments:
+
+  * <symbol> - Symbol to compare (required)
+  * <file_a> - File with the original declaration (required)
+  * <file_b> - File with the new declaration (required)
+  * --project=[project] - Name of the project containing both files
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+// Helper to format a value in a change record
+const format_value = (value) => {
+  if (value === null || value === undefined) return '(none)';
+  if (typeof value === 'object') return value.type || value.signature || '';
+  return String(value).replace(/\s+/g, ' ');
+};
+
+const handler = async (argv) => {
+  const [symbol, file_a, file_b] = argv._.map(String);
+  if (!symbol || !file_a || !file_b) {
+    console.log(help);
+    return;
+  }
+
+  let result;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    result = await compare_symbol(project_id, symbol, file_a, file_b);
+  } else {
+    result = compare_symbol_sources(
+      symbol,
+      { filename: file_a, source: await import_file(file_a) },
+      { filename: file_b, source: await import_file(file_b) }
+    );
+  }
+
+  console.log(`\n=== Compare '${symbol}' ===\n`);
+  console.log(`A: ${file_a}${result.a ? `:${result.a.line}` : ''}`);
+  console.log(`B: ${file_b}${result.b ? `:${result.b.line}` : ''}\n`);
+
+  if (result.status === 'missing') {
+    console.log(`'${symbol}' was not found in either file.`);
+    return;
+  }
+  if (result.status === 'missing_in_a') {
+    console.log(`'${symbol}' is not declared in ${file_a}.`);
+    return;
+  }
+  if (result.status === 'missing_in_b') {
+    console.log(`'${symbol}' is not declared in ${file_b}.`);
+    return;
+  }
+  if (result.status === 'identical') {
+    console.log('No changes.');
+    return;
+  }
+
+  console.log(
+    `Changes: ${result.summary.change_count} (${result.summary.breaking_count} API)`
+  );
+  console.log(
+    `API preserved: ${result.summary.api_preserved ? 'yes' : 'no'}\n`
+  );
+
+  for (const change of result.changes) {
+    const flag = change.breaking ? ' [api]' : '';
+    const name = change.name ? ` ${change.name}` : '';
+    const property = change.property ? ` ${change.property}` : '';
+    const label = `${change.aspect}${name}${property} ${change.change}`;
+    if (change.aspect === 'body') {
+      console.log(
+        `  ${label}${flag}: ${change.before} -> ${change.after} lines`
+      );
+    } else if (change.change === 'added') {
+      console.log(`  ${label}${flag}: ${format_value(change.after)}`);
+    } else if (change.change === 'removed') {
+      console.log(`  ${label}${flag}: ${format_value(change.before)}`);
+    } else {
+      console.log(`  ${label}${flag}:`);
+      console.log(`    - ${format_value(change.before)}`);
+      console.log(`    + ${format_value(change.after)}`);
+    }
+  }
+};
+
+const compare = {
+  command: 'compare',
+  description: "Compare a symbol's declaration between two files",
+  handler,
+  help
+};
+
+export { compare };
@@ -0,0 +1,104 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_doc_links,
+  find_source_doc_links
+} from '../../analysis/index.mjs';
+import { read_go_sources, get_list } from '../sources.mjs';
+
+const help = `usage: cb doc-links [<dir>] [--project=<project>] [--unresolved] [--exclude=<glob>] [--strict] [--json]
+
+Extract the doc links of the package docs and exported Go symbols
+([Calculator], [Calculator.Add], [store.Entry]) and resolve them against
+the symbols of the code base, for cross-linked documentation and
+link-rot detection.  A link to a type, function, constant, variable,
+method or field missing from a project package is unresolved; links to
+other packages, such as the standard library, are external.  URLs, bare
+or from link definitions ("[Go spec]: https://go.dev/ref/spec"), are
+listed apart.  Code blocks are skipped.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --unresolved - Only list the symbols with unresolved links
+  * --exclude=[glob] - Skip files matching a glob, such as generated code (e.g. "*_gen.go"); may be repeated
+  * --strict - Exit with a non-zero status when links are unresolved, for CI
+  * --json - Write the report as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = {
+    unresolved_only: argv.unresolved === true,
+    exclude: get_list(argv.exclude) || []
+  };
+
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_doc_links(project_id, options);
+  } else {
+    target = argv._[0] ? String(argv._[0]) : '.';
+    result = find_source_doc_links(await read_go_sources(target), options);
+  }
+
+  if (argv.json) {
+    process.stdout.write(`${JSON.stringify(result, null, 2)}\n`);
+  } else {
+    const { summary } = result;
+    console.log(`\n=== Doc Links: ${target} ===\n`);
+    console.log(`Doc Links: ${summary.links}`);
+    console.log(`  Resolved: ${summary.resolved}`);
+    console.log(`  External: ${summary.external}`);
+    console.log(`  Unresolved: ${summary.unresolved}`);
+    console.log(`URLs: ${summary.urls}`);
+
+    for (const symbol of result.symbols) {
+      console.log(
+        `\n  ${symbol.filename}:${symbol.line} ${symbol.kind} ${symbol.name}`
+      );
+      for (const link of symbol.doc_links) {
+        const to = link.resolved
+          ? ` -> ${link.resolved.directory}:${link.resolved.name} (${link.resolved.kind})`
+          : '';
+        console.log(`    ${link.text} ${link.status}${to}`);
+      }
+      for (const url of symbol.urls) {
+        console.log(`    ${url.text ? `[${url.text}] ` : ''}${url.url}`);
+      }
+    }
+
+    if (result.unresolved.length > 0) console.log('');
+    for (const link of result.unresolved) {
+      console.log(`${link.filename}:${link.line}: ${link.message}`);
+    }
+  }
+
+  if (result.unresolved.length > 0 && argv.strict) {
+    process.exitCode = 1;
+  }
+};
+
+const doc_links = {
+  command: 'doc-links',
+  description: 'Resolve Go doc comment links and flag the broken ones',
+  handler,
+  help
+};
+
+export { doc_links };
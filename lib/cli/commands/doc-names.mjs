@@ -0,0 +1,85 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_doc_names,
+  find_source_doc_names
+} from '../../analysis/index.mjs';
+import { read_go_sources, get_list } from '../sources.mjs';
+
+const help = `usage: cb doc-names [<dir>] [--project=<project>] [--kinds=<kinds>] [--exclude=<glob>] [--strict]
+
+List the doc comments of exported Go symbols that do not begin with the
+declared name, the godoc convention ("Add adds ...").  An article may
+come first ("A Reader reads ..."), methods may be named with their type
+("Reader.Read ...") and a leading "Deprecated:" paragraph is skipped.
+A name written with different case, usually an acronym ("Url" for URL),
+is reported as a case mismatch.  Group doc comments, which describe the
+group, and undocumented symbols (see cb undocumented) are not checked.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --kinds=[kinds] - Comma separated kinds to check: function, method, type, field, const, var (default: function,method,type,const,var)
+  * --exclude=[glob] - Skip files matching a glob, such as generated code (e.g. "*_gen.go"); may be repeated
+  * --strict - Exit with a non-zero status when doc comments are misnamed, for CI
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = {
+    kinds: get_list(argv.kinds),
+    exclude: get_list(argv.exclude) || []
+  };
+
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_doc_names(project_id, options);
+  } else {
+    target = argv._[0] ? String(argv._[0]) : '.';
+    result = find_source_doc_names(await read_go_sources(target), options);
+  }
+
+  console.log(`\n=== Doc Comment Names: ${target} ===\n`);
+  console.log(`Kinds: ${result.kinds.join(', ')}`);
+  console.log(`Doc Comments Checked: ${result.summary.checked}`);
+  console.log(`Misnamed: ${result.summary.mismatched}`);
+  console.log(`  Case Mismatches: ${result.summary.by_case}\n`);
+
+  if (result.symbols.length === 0) {
+    console.log('Every doc comment begins with the declared name.');
+    return;
+  }
+
+  for (const symbol of result.symbols) {
+    console.log(`${symbol.filename}:${symbol.line}: ${symbol.message}`);
+  }
+
+  if (argv.strict) {
+    process.exitCode = 1;
+  }
+};
+
+const doc_names = {
+  command: 'doc-names',
+  description: 'List Go doc comments not beginning with the declared name',
+  handler,
+  help
+};
+
+export { doc_names };
@@ -0,0 +1,124 @@
+'use strict';
+
+import path from 'path';
+import { writeFile } from 'fs/promises';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_doc_coverage,
+  find_source_doc_coverage,
+  compare_doc_coverage
+} from '../../analysis/index.mjs';
+import { read_go_sources, read_json, get_list } from '../sources.mjs';
+
+const help = `usage: cb doccov [<dir>] [--project=<project>] [--baseline=<file>] [--update] [--kinds=<kinds>] [--exclude=<glob>] [--strict]
+
+Report the doc coverage of the exported Go symbols, the ratio of those
+with a doc comment, and its change since a stored baseline: the delta,
+the newly added symbols without docs, and the symbols whose docs were
+added or removed.  Test files are skipped.
+
+The baseline is a small JSON file, written by --update and read by later
+runs; commit it to track coverage over time.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --baseline=[file] - Baseline file (default: .doccov.json in <dir>, or in the current directory with --project)
+  * --update - Write the current coverage to the baseline file
+  * --kinds=[kinds] - Comma separated kinds to check: function, method, type, field, const, var (default: function,method,type,field)
+  * --exclude=[glob] - Skip files matching a glob, such as generated code (e.g. "*_gen.go"); may be repeated
+  * --strict - Exit with a non-zero status when coverage regressed from the baseline, for CI
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = {
+    kinds: get_list(argv.kinds),
+    exclude: get_list(argv.exclude) || []
+  };
+
+  let coverage;
+  let target;
+  let directory = '.';
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    coverage = await analyze_project_doc_coverage(project_id, options);
+  } else {
+    directory = argv._[0] ? String(argv._[0]) : '.';
+    target = directory;
+    coverage = find_source_doc_coverage(
+      await read_go_sources(directory),
+      options
+    );
+  }
+
+  const baseline_file =
+    typeof argv.baseline === 'string'
+      ? argv.baseline
+      : path.join(directory, '.doccov.json');
+  const baseline = await read_json(baseline_file, 'baseline', true);
+  const { checked, documented } = coverage.summary;
+
+  console.log(`\n=== Doc Coverage: ${target} ===\n`);
+  console.log(`Kinds: ${coverage.kinds.join(', ')}`);
+  console.log(`Documented: ${documented} of ${checked}`);
+  console.log(`Coverage: ${coverage.summary.coverage}%`);
+
+  let comparison = null;
+  if (baseline) {
+    comparison = compare_doc_coverage(coverage, baseline);
+    const sign = comparison.delta > 0 ? '+' : '';
+    console.log(`Baseline: ${comparison.baseline_coverage}%`);
+    console.log(`Delta: ${sign}${comparison.delta}%`);
+    console.log(
+      `Symbols: ${comparison.added} added, ${comparison.removed} removed`
+    );
+
+    const list = (title, ids) => {
+      if (ids.length === 0) return;
+      console.log(`\n${title}:`);
+      for (const id of ids) console.log(`  ${id}`);
+    };
+    list('New Symbols Without Docs', comparison.new_undocumented);
+    list('Docs Removed', comparison.lost_docs);
+    list('Newly Documented', comparison.newly_documented);
+  } else if (!argv.update) {
+    console.log(
+      `\nNo baseline at ${baseline_file}; run with --update to create one.`
+    );
+  }
+
+  if (argv.update) {
+    const { version, kinds, summary, symbols } = coverage;
+    const content = { version, kinds, summary, symbols };
+    await writeFile(baseline_file, `${JSON.stringify(content, null, 2)}\n`);
+    console.log(`\nWrote baseline to ${baseline_file}`);
+  }
+
+  if (argv.strict && comparison && comparison.regression) {
+    console.log('\nDoc coverage regressed from the baseline.');
+    process.exitCode = 1;
+  }
+};
+
+const doccov = {
+  command: 'doccov',
+  description: 'Report Go doc coverage and its change since a baseline',
+  handler,
+  help
+};
+
+export { doccov };
@@ -0,0 +1,95 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_test_doubles,
+  find_source_test_doubles
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb doubles [<dir>] [--project=<project>] [--missing] [--strict] [--json]
+
+Verify that every exported Go interface has at least one test double: a
+mock, fake or stub type implementing it, declared in a _test.go file or
+in a package of doubles (mocks, fakes, stubs, or a name ending in mock).
+Interfaces without one are reported, as they are harder to test against.
+
+Doubles are recognized by their methods, not their names: any type of a
+test file or doubles package whose method set satisfies the interface.
+Empty interfaces, and interfaces embedding one of another package, are
+skipped.  Test files are read along with the sources.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --missing - Only list the interfaces without a test double
+  * --strict - Exit with a non-zero status when an interface has no test double, for CI
+  * --json - Write the report as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = { missing_only: argv.missing === true };
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_test_doubles(project_id, options);
+  } else {
+    target = argv._[0] ? String(argv._[0]) : '.';
+    result = find_source_test_doubles(await read_go_sources(target), options);
+  }
+
+  if (argv.json) {
+    process.stdout.write(`${JSON.stringify(result, null, 2)}\n`);
+  } else {
+    const { summary } = result;
+    console.log(`\n=== Test Doubles: ${target} ===\n`);
+    console.log(`Exported interfaces: ${summary.interfaces}`);
+    console.log(`  with a test double: ${summary.with_double}`);
+    console.log(`  without: ${summary.without_double}`);
+    console.log(`Coverage: ${summary.coverage}%`);
+
+    for (const iface of result.interfaces) {
+      const status = iface.has_double ? 'ok' : 'MISSING';
+      console.log(
+        `\n  [${status}] ${iface.package}.${iface.interface} (${iface.filename}:${iface.line})`
+      );
+      for (const double of iface.doubles) {
+        const pointer = double.pointer_only ? ' (pointer)' : '';
+        console.log(
+          `    ${double.package}.${double.type}${pointer} ${double.filename}:${double.line}`
+        );
+      }
+    }
+    if (result.skipped.length > 0) {
+      console.log(`\nSkipped: ${result.skipped.join(', ')}`);
+    }
+  }
+
+  if (result.summary.without_double > 0 && argv.strict) {
+    process.exitCode = 1;
+  }
+};
+
+const doubles = {
+  command: 'doubles',
+  description: 'Verify that exported Go interfaces have test doubles',
+  handler,
+  help
+};
+
+export { doubles };
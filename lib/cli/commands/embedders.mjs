@@ -0,0 +1,92 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_embedders,
+  find_source_embedders
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb embedders <type> [<dir>] [--project=<project>] [--transitive] [--unexported]
+
+List the public struct and interface types that embed a Go type, for
+impact analysis before changing the API of the embedded type: its methods
+are promoted to every embedder.
+
+With --transitive, types embedding the type through other types are
+listed too, with their embedding path (Manager > Employee > User).
+Embedding across packages of the tree (staff.User) is followed.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <type> - Embedded type, optionally qualified by package (staff.User)
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --transitive - Include types embedding the type through other types
+  * --unexported - Include unexported embedders
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const type = argv._[0] ? String(argv._[0]) : null;
+  if (!type) {
+    console.log(help);
+    return;
+  }
+  const options = {
+    transitive: Boolean(argv.transitive),
+    include_unexported: Boolean(argv.unexported)
+  };
+
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_embedders(project_id, type, options);
+  } else {
+    target = argv._[1] ? String(argv._[1]) : '.';
+    result = find_source_embedders(
+      await read_go_sources(target),
+      type,
+      options
+    );
+  }
+
+  console.log(
+    `\n=== Embedders of ${result.type.package}.${result.type.name}: ${target} ===\n`
+  );
+  console.log(`Embedders: ${result.summary.total_embedders}`);
+  console.log(`Direct: ${result.summary.direct}`);
+  console.log(`Transitive: ${result.summary.transitive}`);
+
+  if (result.embedders.length === 0) return;
+
+  console.log('\nTypes:');
+  for (const e of result.embedders) {
+    const path = e.depth > 1 ? ` (${e.path.join(' > ')})` : '';
+    console.log(
+      `  ${e.package}.${e.name} [${e.kind}]${path} - ${e.filename}:${e.line}`
+    );
+  }
+};
+
+const embedders = {
+  command: 'embedders',
+  description: 'List the types embedding a Go type',
+  handler,
+  help
+};
+
+export { embedders };
@@ -0,0 +1,83 @@
+'use strict';
+
+import { writeFile } from 'fs/promises';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_symbol_graph,
+  render_graph_html
+} from '../../analysis/index.mjs';
+
+const help = `usage: cb graph --project=<project> [--output=<file>] [--format=<format>] [--edges=<types>] [--package=<package>]
+
+Render the symbol graph of a project's Go code: functions, methods and
+types connected by calls, interface implementations and embedding.
+
+The default output is a self-contained interactive HTML page (no network
+access needed) that supports pan and zoom, click-to-focus on a symbol and
+its neighbours, filtering by edge type and collapsing packages into single
+nodes.  Use --format=json for the underlying graph data.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --output=[file] - File to write (default: <project>-graph.html or .json)
+  * --format=[format] - Output format: html or json (default: html)
+  * --edges=[types] - Comma separated edge types: calls, implements, embeds (default: all)
+  * --package=[package] - Only include the package with this name or directory
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  if (typeof argv.project !== 'string') {
+    console.log(help);
+    return;
+  }
+
+  const format = typeof argv.format === 'string' ? argv.format : 'html';
+  if (format !== 'html' && format !== 'json') {
+    throw new Error(`Unknown format '${format}' (expected html or json)`);
+  }
+
+  const project_id = await get_project_id(argv.project);
+  const graph = await analyze_symbol_graph(project_id, {
+    edge_types:
+      typeof argv.edges === 'string' ? argv.edges.split(',') : undefined,
+    package: typeof argv.package === 'string' ? argv.package : undefined
+  });
+
+  const output =
+    typeof argv.output === 'string'
+      ? argv.output
+      : `${argv.project}-graph.${format}`;
+  const content =
+    format === 'json'
+      ? JSON.stringify(graph, null, 2)
+      : render_graph_html(graph, { title: `Symbol Graph: ${argv.project}` });
+  await writeFile(output, content);
+
+  console.log(`\n=== Symbol Graph: ${argv.project} ===\n`);
+  console.log(`Packages: ${graph.summary.total_packages}`);
+  console.log(`Symbols: ${graph.summary.total_nodes}`);
+  console.log(`Edges: ${graph.summary.total_edges}`);
+  for (const [type, count] of Object.entries(graph.summary.edges_by_type)) {
+    console.log(`  ${type}: ${count}`);
+  }
+  console.log(`\nWrote ${output}`);
+};
+
+const graph = {
+  command: 'graph',
+  description: 'Render the symbol graph as an interactive HTML page',
+  handler,
+  help
+};
+
+export { graph };
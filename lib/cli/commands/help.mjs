@@ -4,6 +4,28 @@ import { project } from './project.mjs';
 import { func } from './function.mjs';
 import { entity } from './entity.mjs';
 import { analysis } from './analysis.mjs';
+import { compare } from './compare.mjs';
+import { graph } from './graph.mjs';
+import { panics } from './panics.mjs';
+import { embedders } from './embedders.mjs';
+import { impact } from './impact.mjs';
+import { undocumented } from './undocumented.mjs';
+import { doc_names } from './doc-names.mjs';
+import { doccov } from './doccov.mjs';
+import { search_index } from './search-index.mjs';
+import { changed } from './changed.mjs';
+import { catalog } from './catalog.mjs';
+import { proto } from './proto.mjs';
+import { changelog } from './changelog.mjs';
+import { surface } from './surface.mjs';
+import { receivers } from './receivers.mjs';
+import { inversion } from './inversion.mjs';
+import { doubles } from './doubles.mjs';
+import { doc_links } from './doc-links.mjs';
+import { cheatsheet } from './cheatsheet.mjs';
+import { symbol_ranges } from './symbol-ranges.mjs';
+import { tree } from './tree.mjs';
+import { risk } from './risk.mjs';
 
 const help_text = `usage: cb [--version] [--help] <command> [<args>]
 
@@ -15,6 +37,28 @@ ${project.command} - ${project.description}
 ${func.command} - ${func.description}
 ${entity.command} - ${entity.description}
 ${analysis.command} - ${analysis.description}
+${compare.command} - ${compare.description}
+${graph.command} - ${graph.description}
+${panics.command} - ${panics.description}
+${embedders.command} - ${embedders.description}
+${impact.command} - ${impact.description}
+${undocumented.command} - ${undocumented.description}
+${doc_names.command} - ${doc_names.description}
+${doccov.command} - ${doccov.description}
+${search_index.command} - ${search_index.description}
+${changed.command} - ${changed.description}
+${catalog.command} - ${catalog.description}
+${proto.command} - ${proto.description}
+${changelog.command} - ${changelog.description}
+${surface.command} - ${surface.description}
+${receivers.command} - ${receivers.description}
+${inversion.command} - ${inversion.description}
+${doubles.command} - ${doubles.description}
+${doc_links.command} - ${doc_links.description}
+${cheatsheet.command} - ${cheatsheet.description}
+${symbol_ranges.command} - ${symbol_ranges.description}
+${tree.command} - ${tree.description}
+${risk.command} - ${risk.description}
 `;
 
 // Commands that we know about.
@@ -22,7 +66,29 @@ const commands = {
   project,
   function: func,
   entity,
-  analysis
+  analysis,
+  compare,
+  graph,
+  panics,
+  embedders,
+  impact,
+  undocumented,
+  'doc-names': doc_names,
+  doccov,
+  'search-index': search_index,
+  changed,
+  catalog,
+  proto,
+  changelog,
+  surface,
+  receivers,
+  inversion,
+  doubles,
+  'doc-links': doc_links,
+  cheatsheet,
+  'symbol-ranges': symbol_ranges,
+  tree,
+  risk
 };
 
 // Help uses a single handler function to provide help for specific commands.
@@ -0,0 +1,157 @@
+'use strict';
+
+import path from 'path';
+import { existsSync } from 'fs';
+import { readFile } from 'fs/promises';
+import { summarize_diff_impact } from '../../analysis/index.mjs';
+import { run_git } from '../sources.mjs';
+
+const help = `usage: cb impact [<revision>...] [--stdin] [--format=<format>]
+
+Summarize the symbol level impact of a diff: the Go functions, methods,
+types, constants and variables it adds, removes or modifies, which is
+handy for pull request descriptions.
+
+The diff is taken from "git diff <revision>..." in the current
+repository, so "cb impact main" compares main with the working tree and
+"cb impact main..feature" two revisions.  With --stdin a unified diff is
+read from standard input instead and applies to the files in the current
+directory.  Added, deleted and renamed files are supported; files other
+than Go files are listed without symbols.
+
+Arguments:
+
+  * <revision> - Revisions passed to git diff (default: the index)
+  * --stdin - Read the diff from standard input
+  * --format=[format] - Output format: text, markdown or json (default: text)
+`;
+
+const CHANGE_MARKERS = {
+  added: '+',
+  removed: '-',
+  modified: '~'
+};
+
+const STATUS_MARKERS = {
+  added: 'A',
+  deleted: 'D',
+  modified: 'M',
+  renamed: 'R'
+};
+
+// Helper to read standard input
+const read_stdin = async () => {
+  const chunks = [];
+  for await (const chunk of process.stdin) chunks.push(chunk);
+  return Buffer.concat(chunks).toString('utf-8');
+};
+
+// The revision holding the new side of a diff, or null for the working tree
+const get_new_revision = (revisions) => {
+  if (revisions.length >= 2) return revisions[1];
+  const range = revisions.length === 1 && revisions[0].match(/\.\.\.?(.*)$/);
+  if (range) return range[1] || 'HEAD';
+  return null;
+};
+
+// Helper to read the new version of a file from disk or a revision
+const build_source_reader = (root, revision) => {
+  return async (filename) => {
+    if (revision) {
+      try {
+        return await run_git(['show', `${revision}:${filename}`]);
+      } catch {
+        return null;
+      }
+    }
+    const full_path = path.join(root, filename);
+    return existsSync(full_path) ? await readFile(full_path, 'utf-8') : null;
+  };
+};
+
+const format_counts = (item) => {
+  return `+${item.lines_added} -${item.lines_removed}`;
+};
+
+const print_text = (result) => {
+  const { summary } = result;
+  console.log(
+    `${summary.files_changed} files changed: ${summary.symbols_added} symbols added, ${summary.symbols_removed} removed, ${summary.symbols_modified} modified\n`
+  );
+
+  for (const file of result.files) {
+    const renamed = file.old_filename ? ` (from ${file.old_filename})` : '';
+    console.log(
+      `${STATUS_MARKERS[file.status]} ${file.filename}${renamed} (${format_counts(file)})`
+    );
+    if (file.error) console.log(`  ${file.error}`);
+    for (const symbol of file.symbols) {
+      console.log(
+        `  ${CHANGE_MARKERS[symbol.change]} ${symbol.kind} ${symbol.name} (${format_counts(symbol)})`
+      );
+    }
+  }
+};
+
+const print_markdown = (result) => {
+  const { summary } = result;
+  console.log('### Symbol changes\n');
+  console.log(
+    `${summary.symbols_added} added, ${summary.symbols_removed} removed, ${summary.symbols_modified} modified across ${summary.files_changed} files.\n`
+  );
+
+  for (const file of result.files.filter((f) => f.symbols.length > 0)) {
+    const renamed = file.old_filename ? `, from \`${file.old_filename}\`` : '';
+    console.log(`**\`${file.filename}\`** (${file.status}${renamed})\n`);
+    for (const symbol of file.symbols) {
+      console.log(`- ${symbol.change} ${symbol.kind} \`${symbol.name}\``);
+    }
+    console.log();
+  }
+};
+
+const handler = async (argv) => {
+  const format = typeof argv.format === 'string' ? argv.format : 'text';
+  if (!['text', 'markdown', 'json'].includes(format)) {
+    throw new Error(
+      `Unknown format '${format}' (expected text, markdown or json)`
+    );
+  }
+
+  const revisions = argv._.map((r) => String(r));
+  let diff;
+  let read_source;
+  if (argv.stdin || revisions[0] === '-') {
+    diff = await read_stdin();
+    read_source = build_source_reader(process.cwd(), null);
+  } else {
+    const root = (await run_git(['rev-parse', '--show-toplevel'])).trim();
+    diff = await run_git([
+      'diff',
+      '--no-color',
+      '--no-ext-diff',
+      ...revisions,
+      '--'
+    ]);
+    read_source = build_source_reader(root, get_new_revision(revisions));
+  }
+
+  const result = await summarize_diff_impact(diff, read_source);
+
+  if (format === 'json') {
+    console.log(JSON.stringify(result, null, 2));
+  } else if (format === 'markdown') {
+    print_markdown(result);
+  } else {
+    print_text(result);
+  }
+};
+
+const impact = {
+  command: 'impact',
+  description: 'Summarize the symbols changed by a diff',
+  handler,
+  help
+};
+
+export { impact };
@@ -7,3 +7,25 @@ export * from './entity.mjs';
 export * from './analysis.mjs';
 export * from './reference.mjs';
 export * from './hierarchy.mjs';
+export * from './compare.mjs';
+export * from './graph.mjs';
+export * from './panics.mjs';
+export * from './embedders.mjs';
+export * from './impact.mjs';
+export * from './undocumented.mjs';
+export * from './doc-names.mjs';
+export * from './doccov.mjs';
+export * from './search-index.mjs';
+export * from './changed.mjs';
+export * from './catalog.mjs';
+export * from './proto.mjs';
+export * from './changelog.mjs';
+export * from './surface.mjs';
+export * from './receivers.mjs';
+export * from './inversion.mjs';
+export * from './doubles.mjs';
+export * from './doc-links.mjs';
+export * from './cheatsheet.mjs';
+export * from './symbol-ranges.mjs';
+export * from './tree.mjs';
+export * from './risk.mjs';
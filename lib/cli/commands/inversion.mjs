@@ -0,0 +1,93 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_dependency_inversions,
+  find_source_dependency_inversions
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb inversion [<dir>] [--project=<project>] [--json]
+
+Report where higher-level Go packages depend directly on the concrete
+types of lower-level packages when an interface of the project would do,
+for architecture reviews around dependency inversion.  A parameter or
+struct field of a concrete type of another package (*store.Store) is
+reported when it is only used through methods, and a project interface
+satisfied by the type declares them all; that interface is suggested,
+preferring the interfaces of the depending package.
+
+Packages are layered by their project imports: level 0 imports no project
+package, and each other package is one level above its highest import.
+Parameters and fields whose own fields are read need the concrete type
+and are not reported.  This is a heuristic, scoped to the code base.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --json - Write the report as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_dependency_inversions(project_id);
+  } else {
+    target = argv._[0] ? String(argv._[0]) : '.';
+    result = find_source_dependency_inversions(await read_go_sources(target));
+  }
+
+  if (argv.json) {
+    process.stdout.write(`${JSON.stringify(result, null, 2)}\n`);
+    return;
+  }
+
+  console.log(`\n=== Dependency Inversion: ${target} ===\n`);
+  console.log(`Concrete dependencies: ${result.summary.dependencies}`);
+  console.log(`  parameters: ${result.summary.parameters}`);
+  console.log(`  fields: ${result.summary.fields}`);
+
+  if (result.inversions.length === 0) {
+    console.log('\nNo concrete dependency has a project interface to use.');
+    return;
+  }
+
+  for (const inversion of result.inversions) {
+    const { depends_on } = inversion;
+    console.log(
+      `\n  ${inversion.filename}:${inversion.line} ${inversion.message}`
+    );
+    console.log(
+      `    ${inversion.directory} (level ${inversion.level}) -> ${depends_on.directory} (level ${depends_on.level})`
+    );
+    console.log(`    methods used: ${inversion.methods_used.join(', ')}`);
+    if (inversion.interfaces.length > 1) {
+      console.log(`    interfaces: ${inversion.interfaces.join(', ')}`);
+    }
+  }
+};
+
+const inversion = {
+  command: 'inversion',
+  description: 'Report concrete Go dependencies that could use an interface',
+  handler,
+  help
+};
+
+export { inversion };
@@ -0,0 +1,100 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_panics,
+  find_source_panics
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb panics [<dir>] [--project=<project>] [--max-depth=<depth>]
+
+List the exported Go functions that can reach a panic through the call
+graph, a risk signal for library consumers.  For each one the nearest
+panic site and the shortest call path to it are shown.
+
+- Methods are part of the API when their receiver type is exported
+- Test files and main packages are skipped
+- Functions that recover() in a deferred call stop the search
+- Calls through interfaces reach every implementing type in the code
+- Calls of function values cannot be followed and are listed separately
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --max-depth=[depth] - Maximum call depth to search (default: 10)
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const max_depth = argv['max-depth'] ? parseInt(argv['max-depth']) : 10;
+
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_panics(project_id, { max_depth });
+  } else {
+    target = argv._[0] ? String(argv._[0]) : '.';
+    result = find_source_panics(await read_go_sources(target), { max_depth });
+  }
+
+  console.log(`\n=== Reachable Panics: ${target} ===\n`);
+  console.log(`Exported Functions: ${result.summary.exported_functions}`);
+  console.log(`Reaching a Panic: ${result.summary.functions_reaching_panic}`);
+  console.log(`Panicking Directly: ${result.summary.direct_panics}`);
+  console.log(`Panic Sites: ${result.summary.panic_sites}\n`);
+
+  if (result.functions.length === 0) {
+    console.log('No exported function can reach a panic.');
+  }
+
+  for (const fn of result.functions) {
+    console.log(`${fn.function} - ${fn.filename}:${fn.line}`);
+    console.log(
+      `  panics at ${fn.panic.filename}:${fn.panic.line}: ${fn.panic.text}`
+    );
+    if (!fn.direct) {
+      const steps = fn.path.map(function format_step(step) {
+        return step.via ? `${step.function} (via ${step.via})` : step.function;
+      });
+      console.log(`  path: ${steps.join(' -> ')}`);
+    }
+    if (fn.indirect_calls > 0) {
+      console.log(
+        `  plus ${fn.indirect_calls} unresolved function value calls`
+      );
+    }
+  }
+
+  if (result.unresolved.length > 0) {
+    console.log('\nMay Panic Through Function Values:');
+    for (const fn of result.unresolved) {
+      console.log(
+        `  ${fn.function} - ${fn.filename}:${fn.line} (${fn.indirect_calls} calls)`
+      );
+    }
+  }
+};
+
+const panics = {
+  command: 'panics',
+  description: 'List exported functions that can reach a panic',
+  handler,
+  help
+};
+
+export { panics };
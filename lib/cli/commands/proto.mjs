@@ -0,0 +1,114 @@
+'use strict';
+
+import path from 'path';
+import { writeFile, mkdir } from 'fs/promises';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  export_project_proto,
+  generate_source_proto
+} from '../../analysis/index.mjs';
+import {
+  read_go_sources,
+  read_module_path,
+  get_output_paths,
+  get_list
+} from '../sources.mjs';
+
+const help = `usage: cb proto [<dir>] [--project=<project>] [--type=<type>] [-o <dir>] [--module=<path>]
+
+Generate proto3 schemas (.proto) from the Go structs tagged for
+serialization (json, protobuf, xml, yaml, bson or msgpack tags), one
+schema per package.  Each struct becomes a message, and the structs and
+enums its fields refer to are generated along with it.  Schemas are
+written to standard output unless an output directory is given.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk, and the module path is read from its go.mod.
+
+Field numbers come from protobuf tags (protobuf:"varint,1,opt,name=id"
+or protobuf:"1"); other fields are numbered in declaration order.  Field
+names come from json tags, or are the Go names in snake_case.
+
+Type mapping:
+
+  * bool, string, float32, float64 - bool, string, float, double
+  * int, int64, uint, uint64 - int64, uint64
+  * int8, int16, int32, uint8, uint16, uint32 - int32, uint32
+  * []byte - bytes
+  * time.Time, time.Duration - google.protobuf.Timestamp, Duration
+  * *T - T, optional for scalars and enums
+  * []T, map[K]V - repeated T, map<K, V>
+  * structs of the package - messages (nested for anonymous structs)
+  * integer types with typed constants - enums
+  * named types - the mapping of their underlying type
+
+Fields of other types are skipped with a comment and a warning.
+
+Arguments:
+
+  * <dir> - Directory to read
+  * --project=[project] - Name of an imported project to read instead
+  * --type=[type] - Struct to generate a message for (default: all
+    serialized structs), may be repeated or comma separated
+  * --package=[package] - Only generate the package with this name or
+    directory
+  * -o [dir], --output=[dir] - Directory to write the schemas to
+  * --module=[path] - Module path, for the go_package option
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const { directory, output } = get_output_paths(argv);
+  const options = {
+    types: get_list(argv.type),
+    package: typeof argv.package === 'string' ? argv.package : undefined,
+    module: typeof argv.module === 'string' ? argv.module : undefined
+  };
+
+  let result;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    result = await export_project_proto(project_id, options);
+  } else {
+    if (!options.module) options.module = await read_module_path(directory);
+    result = generate_source_proto(await read_go_sources(directory), options);
+  }
+
+  for (const schema of result.schemas) {
+    for (const warning of schema.warnings) {
+      console.error(`${schema.filename}: ${warning}`);
+    }
+  }
+
+  if (!output) {
+    process.stdout.write(result.schemas.map((s) => s.text).join('\n'));
+    return;
+  }
+
+  for (const schema of result.schemas) {
+    const filename = path.join(output, schema.filename);
+    await mkdir(path.dirname(filename), { recursive: true });
+    await writeFile(filename, schema.text);
+  }
+  const { messages, enums, files } = result.summary;
+  console.log(
+    `Wrote ${messages} messages and ${enums} enums in ${files} files to ${output}`
+  );
+};
+
+const proto = {
+  command: 'proto',
+  description: 'Generate proto3 schemas from serialized Go structs',
+  handler,
+  help
+};
+
+export { proto };
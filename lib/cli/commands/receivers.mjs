@@ -0,0 +1,104 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_receiver_names,
+  find_source_receiver_names
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb receivers [<dir>] [--project=<project>] [--inconsistent] [--fix-suggestions] [--json]
+
+Report the receiver names of the methods of each Go type, and suggest a
+single consistent short name: the most used name of one to three letters,
+or else the initials of the type (br for BufferedReader).  Types whose
+methods use different receiver names, or generic names such as this and
+self, are flagged.
+
+Methods promoted through an embedded type count with the embedded type.
+Unnamed receivers are counted apart and never renamed.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --inconsistent - Only list the types with inconsistent or generic names
+  * --fix-suggestions - Print the receiver renames making the names consistent, without applying them
+  * --json - Write the receiver names as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = { inconsistent_only: Boolean(argv.inconsistent) };
+
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_receiver_names(project_id, options);
+  } else {
+    target = argv._[0] ? String(argv._[0]) : '.';
+    result = find_source_receiver_names(await read_go_sources(target), options);
+  }
+
+  if (argv.json) {
+    process.stdout.write(`${JSON.stringify(result, null, 2)}\n`);
+    return;
+  }
+
+  if (argv['fix-suggestions']) {
+    for (const type of result.types) {
+      for (const rename of type.renames) {
+        const conflict = rename.conflict ? ' (conflict)' : '';
+        console.log(
+          `${rename.filename}:${rename.line}: ${type.type}.${rename.method}: ${rename.from} -> ${rename.to}${conflict}`
+        );
+      }
+    }
+    return;
+  }
+
+  console.log(`\n=== Receiver Names: ${target} ===\n`);
+  console.log(`Types: ${result.summary.types}`);
+  console.log(`Inconsistent: ${result.summary.inconsistent}`);
+  console.log(`Generic: ${result.summary.generic}`);
+
+  for (const type of result.types) {
+    const names = Object.entries(type.names)
+      .map(([name, count]) => `${name} (${count})`)
+      .join(', ');
+    const status =
+      type.issues.length > 0
+        ? `${type.issues.join(', ')}; suggest ${type.suggestion}`
+        : 'consistent';
+    console.log(`\n  ${type.directory}: ${type.type} - ${status}`);
+    console.log(`    ${names || 'unnamed'}`);
+  }
+
+  if (result.summary.renames > 0) {
+    console.log(
+      `\n${result.summary.renames} receivers to rename; run with --fix-suggestions to list them.`
+    );
+  }
+};
+
+const receivers = {
+  command: 'receivers',
+  description: 'Report Go receiver names and suggest consistent ones',
+  handler,
+  help
+};
+
+export { receivers };
@@ -6,7 +6,8 @@ import {
   go_to_definition,
   list_definitions,
   get_symbol_reference_summary,
-  find_symbols_at_location
+  find_symbols_at_location,
+  analyze_rename_impact
 } from '../../analysis/index.mjs';
 
 const help = `usage: cb reference [<args>]
@@ -18,6 +19,7 @@ Cross-reference browser for finding symbol usages and definitions.
   * list - List all definitions in a project
   * summary - Show symbol reference summary
   * at - Find symbols at a specific location
+  * rename - Show the impact of renaming a symbol
 `;
 
 const find_help = `usage: cb reference find --project=<project> --symbol=<symbol> [--filename=<filename>] [--definitions-only]
@@ -75,6 +77,21 @@ Arguments:
   * --column=[column] - Column number for precise matching
 `;
 
+const rename_help = `usage: cb reference rename --project=<project> --symbol=<symbol> --new-name=<name> [--filename=<filename>]
+
+Show every location that would need editing to rename a symbol, along with
+any name collisions or shadowing the new name would cause.  The analysis is
+scoped to the package (directory) containing the symbol's definition; no
+files are modified.
+
+Arguments:
+
+  * --project=[project] - Name of the project (required)
+  * --symbol=[symbol] - Symbol name to rename (required)
+  * --new-name=[name] - Proposed new name (required)
+  * --filename=[filename] - File containing the definition to rename
+`;
+
 // Helper to get project ID
 const get_project_id = async (project) => {
   const projects = await get_project_by_name({ name: project });
@@ -260,6 +277,62 @@ const reference_at = async ({ project, filename, line, column }) => {
   }
 };
 
+const reference_rename = async ({
+  project,
+  symbol,
+  'new-name': new_name,
+  filename
+}) => {
+  const project_id = await get_project_id(project);
+  const result = await analyze_rename_impact(project_id, symbol, new_name, {
+    filename
+  });
+
+  console.log(`\n=== Rename '${symbol}' to '${new_name}' ===\n`);
+
+  if (result.summary.edit_count === 0) {
+    console.log(`No references to '${symbol}' found.`);
+    return;
+  }
+
+  console.log(`Package: ${result.scope.package}`);
+  console.log(
+    `Edits: ${result.summary.edit_count} in ${result.summary.file_count} files`
+  );
+  console.log(`Safe: ${result.summary.safe ? 'yes' : 'no'}\n`);
+
+  if (result.conflicts.length > 0) {
+    console.log('Conflicts:');
+    for (const conflict of result.conflicts) {
+      const location = conflict.filename
+        ? `${conflict.filename}:${conflict.line} - `
+        : '';
+      console.log(`  [${conflict.type}] ${location}${conflict.message}`);
+    }
+    console.log();
+  }
+
+  let current_file = null;
+  for (const edit of result.edits) {
+    if (edit.filename !== current_file) {
+      if (current_file) console.log();
+      current_file = edit.filename;
+      console.log(`${edit.filename}:`);
+    }
+    const flag_str = edit.is_definition ? ' [def]' : '';
+    console.log(`  ${edit.line}:${edit.column_start}${flag_str}`);
+    if (edit.context) {
+      console.log(`    ${edit.context}`);
+    }
+  }
+
+  if (result.out_of_scope.length > 0) {
+    console.log(
+      `\n${result.summary.out_of_scope_count} references outside ${result.scope.package} were not included.`
+    );
+  }
+};
+
 const reference = {
   command: 'reference',
   description: 'Cross-reference browser for finding symbol usages',
@@ -268,7 +341,8 @@ const reference = {
     definition: reference_definition,
     list: reference_list,
     summary: reference_summary,
-    at: reference_at
+    at: reference_at,
+    rename: reference_rename
   },
   help,
   command_help: {
@@ -276,7 +350,8 @@ const reference = {
     definition: definition_help,
     list: list_help,
     summary: summary_help,
-    at: at_help
+    at: at_help,
+    rename: rename_help
   },
   command_arguments: {
     find: {
@@ -357,6 +432,27 @@ const reference = {
         type: 'number',
         description: 'Column number for precise matching'
       }
+    },
+    rename: {
+      project: {
+        type: 'string',
+        description: 'Name of the project',
+        required: true
+      },
+      symbol: {
+        type: 'string',
+        description: 'Symbol name to rename',
+        required: true
+      },
+      'new-name': {
+        type: 'string',
+        description: 'Proposed new name',
+        required: true
+      },
+      filename: {
+        type: 'string',
+        description: 'File containing the definition to rename'
+      }
     }
   }
 };
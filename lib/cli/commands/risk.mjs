@@ -0,0 +1,139 @@
+'use strict';
+
+import { promisify } from 'util';
+import { execFile as child_exec_file } from 'child_process';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_risk_scores,
+  find_source_risk_scores,
+  parse_git_churn
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const exec_file = promisify(child_exec_file);
+
+const help = `usage: cb risk [<dir>] [--project=<project>] [--limit=<n>] [--churn] [--since=<date>] [--weights=<weights>] [--json]
+
+List the riskiest Go functions and methods, to prioritize testing and
+review: a risk score from 0 to 100 combining the cyclomatic complexity,
+fan-in and fan-out of each function and, with --churn, the number of
+commits changing its file.
+
+Each metric is normalized on a log scale against the highest value of
+the code base, and the score is their weighted mean:
+
+  score = 100 * sum(weight * log(1 + value) / log(1 + max)) / sum(weight)
+
+Complexity counts its decision points (complexity - 1).  The default
+weights are complexity 0.4, fan_in 0.3, fan_out 0.1 and churn 0.2; the
+churn weight only counts with --churn.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.  Churn is read from the git repository of <dir>, or
+of the current directory with --project; without a repository the scores
+are computed without it.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --limit=[n] - Number of functions to list (default: 20)
+  * --churn - Weigh in the commits changing each file
+  * --since=[date] - Only count the commits since this date (git log --since)
+  * --weights=[weights] - Comma separated metric:weight pairs (complexity:0.5,churn:0)
+  * --json - Write the functions, their scores and the summary as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+// Helper to count the commits changing the Go files of a directory
+const read_git_churn = async (directory, since) => {
+  const args = ['log', '--format=%x00', '--name-only', '--relative'];
+  if (since) args.push(`--since=${since}`);
+  args.push('--', '*.go');
+  try {
+    const { stdout } = await exec_file('git', args, {
+      cwd: directory,
+      maxBuffer: 64 * 1024 * 1024
+    });
+    return parse_git_churn(stdout);
+  } catch {
+    console.error(`Not a git repository: ${directory}, ignoring churn`);
+    return null;
+  }
+};
+
+// Helper to parse metric:weight pairs
+const parse_weights = (text) => {
+  if (typeof text !== 'string') return undefined;
+  return Object.fromEntries(
+    text
+      .split(',')
+      .filter(Boolean)
+      .map((pair) => {
+        const [metric, weight] = pair.split(':');
+        return [metric.trim(), parseFloat(weight)];
+      })
+  );
+};
+
+const handler = async (argv) => {
+  const directory = argv._[0] ? String(argv._[0]) : '.';
+  const options = {
+    limit: argv.limit !== undefined ? parseInt(argv.limit, 10) : undefined,
+    weights: parse_weights(argv.weights),
+    churn: argv.churn
+      ? await read_git_churn(
+          typeof argv.project === 'string' ? '.' : directory,
+          typeof argv.since === 'string' ? argv.since : undefined
+        )
+      : undefined
+  };
+
+  let result;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_risk_scores(project_id, options);
+  } else {
+    result = find_source_risk_scores(await read_go_sources(directory), options);
+  }
+
+  if (argv.json) {
+    process.stdout.write(`${JSON.stringify(result, null, 2)}\n`);
+    return;
+  }
+
+  const { summary } = result;
+  console.log(`\n=== Risk ===\n`);
+  console.log(`Functions: ${summary.functions}`);
+  console.log(
+    `Weights: ${Object.entries(summary.weights)
+      .map(([metric, weight]) => `${metric} ${weight}`)
+      .join(', ')}`
+  );
+  console.log(`Mean Score: ${summary.mean_score}`);
+  console.log('');
+
+  for (const fn of result.functions) {
+    const churn = fn.churn === null ? '' : `, churn ${fn.churn}`;
+    console.log(
+      `  ${fn.score.toFixed(1).padStart(5)} ${fn.package}.${fn.name} (complexity ${fn.complexity}, fan-in ${fn.fan_in}, fan-out ${fn.fan_out}${churn}) - ${fn.filename}:${fn.line}`
+    );
+  }
+};
+
+const risk = {
+  command: 'risk',
+  description: 'List the riskiest Go functions by complexity, coupling and churn',
+  handler,
+  help
+};
+
+export { risk };
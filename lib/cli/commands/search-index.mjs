@@ -0,0 +1,88 @@
+'use strict';
+
+import { writeFile } from 'fs/promises';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  export_search_index,
+  build_search_index,
+  format_search_index
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb search-index [<dir>] [--project=<project>] [--output=<file>] [--kinds=<kinds>] [--exported]
+
+Export the Go symbols of a project as JSON lines for ingestion by a search
+engine: one flat document per function, method, type, field, constant and
+variable, each carrying its package and file so it can be indexed on its
+own.  Documents are written to standard output unless --output is given.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Document fields:
+
+  * id - Unique, stable document key: <directory>:<qualifiedName>
+  * qualifiedName - Package qualified name (e.g. geo.Triangle.Area)
+  * name - Unqualified name (e.g. Area)
+  * kind - function, method, struct, interface, type, field, const or var
+  * signature - One line declaration without body
+  * doc - Doc comment text without comment markers, or an empty string
+  * file - Project relative filename
+  * line - 1-based line of the declaration
+  * package - Package name
+  * directory - Package directory
+  * exported - Whether the symbol is exported
+  * fingerprint - Hash of the declaration; unchanged when only the doc
+    comment or the position of the symbol changes
+
+Arguments:
+
+  * <dir> - Directory to export
+  * --project=[project] - Name of an imported project to export instead
+  * --output=[file] - File to write (default: standard output)
+  * --kinds=[kinds] - Comma separated kinds to include (default: all)
+  * --exported - Only include exported symbols
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = {
+    kinds: typeof argv.kinds === 'string' ? argv.kinds.split(',') : undefined,
+    exported_only: Boolean(argv.exported)
+  };
+
+  let documents;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    documents = (await export_search_index(project_id, options)).documents;
+  } else {
+    const directory = argv._[0] ? String(argv._[0]) : '.';
+    documents = build_search_index(await read_go_sources(directory), options);
+  }
+
+  const content = format_search_index(documents);
+  if (typeof argv.output !== 'string') {
+    process.stdout.write(content);
+    return;
+  }
+
+  await writeFile(argv.output, content);
+  console.log(`Wrote ${documents.length} documents to ${argv.output}`);
+};
+
+const search_index = {
+  command: 'search-index',
+  description: 'Export Go symbols as JSON lines for search engines',
+  handler,
+  help
+};
+
+export { search_index };
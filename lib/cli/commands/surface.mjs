@@ -0,0 +1,142 @@
+'use strict';
+
+import path from 'path';
+import { writeFile } from 'fs/promises';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_surface_area,
+  find_source_surface_area,
+  summarize_surface_area,
+  compare_surface_area,
+  DEFAULT_MAX_GROWTH
+} from '../../analysis/index.mjs';
+import { read_go_sources, read_json } from '../sources.mjs';
+
+const help = `usage: cb surface [<dir>] [--project=<project>] [--catalog=<file>] [--baseline=<file>] [--update] [--max-growth=<percent>] [--strict] [--json]
+
+Report the public surface area of the Go packages of a code base: a single
+number summarizing the size of their public API, and its change since a
+stored baseline.  Each exported symbol of the API catalog (see cb catalog)
+weighs 1, plus 1 per parameter, result and type parameter of functions
+and methods, and 1 per type parameter of types.  Fields, constants and
+variables weigh 1.
+
+The baseline is a small JSON file, written by --update and read by later
+runs; commit it to track the API growth over time.  The surface area
+alerts when it grew more than the maximum growth since the baseline.
+
+Without --project or --catalog the Go files of <dir> (default: the
+current directory) are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --catalog=[file] - Catalog of cb catalog to analyze instead
+  * --baseline=[file] - Baseline file (default: .surface.json in <dir>, or in the current directory with --project or --catalog)
+  * --update - Write the current surface area to the baseline file
+  * --max-growth=[percent] - Growth over which to alert (default: ${DEFAULT_MAX_GROWTH})
+  * --strict - Exit with a non-zero status when the growth alerts, for CI
+  * --json - Write the surface area and comparison as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const max_growth =
+    argv['max-growth'] !== undefined
+      ? parseFloat(argv['max-growth'])
+      : DEFAULT_MAX_GROWTH;
+  if (Number.isNaN(max_growth)) {
+    throw new Error(`Invalid maximum growth '${argv['max-growth']}'`);
+  }
+
+  let surface;
+  let target;
+  let directory = '.';
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    surface = await analyze_project_surface_area(project_id);
+  } else if (typeof argv.catalog === 'string') {
+    target = argv.catalog;
+    surface = summarize_surface_area(await read_json(argv.catalog, 'catalog'));
+  } else {
+    directory = argv._[0] ? String(argv._[0]) : '.';
+    target = directory;
+    surface = find_source_surface_area(await read_go_sources(directory));
+  }
+
+  const baseline_file =
+    typeof argv.baseline === 'string'
+      ? argv.baseline
+      : path.join(directory, '.surface.json');
+  const baseline = await read_json(baseline_file, 'baseline', true);
+  const comparison = baseline
+    ? compare_surface_area(surface, baseline, { max_growth })
+    : null;
+
+  if (argv.json) {
+    process.stdout.write(
+      `${JSON.stringify({ ...surface, comparison }, null, 2)}\n`
+    );
+  } else {
+    console.log(`\n=== Surface Area: ${target} ===\n`);
+    console.log(`Surface area: ${surface.surface_area}`);
+    for (const [kind, { count, weight }] of Object.entries(surface.by_kind)) {
+      console.log(`  ${kind}: ${count} (weight ${weight})`);
+    }
+
+    if (comparison) {
+      const sign = (n) => (n > 0 ? `+${n}` : `${n}`);
+      console.log(`\nBaseline: ${comparison.baseline_surface_area}`);
+      console.log(
+        `Delta: ${sign(comparison.delta)} (${sign(comparison.growth)}%)`
+      );
+      if (comparison.packages.length > 0) {
+        console.log('\nPackages:');
+        for (const pkg of comparison.packages) {
+          console.log(
+            `  ${pkg.directory}: ${pkg.before} -> ${pkg.after} (${sign(pkg.delta)})`
+          );
+        }
+      }
+    } else if (!argv.update) {
+      console.log(
+        `\nNo baseline at ${baseline_file}; run with --update to create one.`
+      );
+    }
+  }
+
+  if (argv.update) {
+    const { version, surface_area, by_kind, packages } = surface;
+    const content = { version, surface_area, by_kind, packages };
+    await writeFile(baseline_file, `${JSON.stringify(content, null, 2)}\n`);
+    if (!argv.json) console.log(`\nWrote baseline to ${baseline_file}`);
+  }
+
+  if (comparison && comparison.alert) {
+    if (!argv.json) {
+      console.log(
+        `\nSurface area grew ${comparison.growth}%, over the maximum of ${comparison.max_growth}%.`
+      );
+    }
+    if (argv.strict) process.exitCode = 1;
+  }
+};
+
+const surface = {
+  command: 'surface',
+  description: 'Report the Go public API surface area and its growth',
+  handler,
+  help
+};
+
+export { surface };
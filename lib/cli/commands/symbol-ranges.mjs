@@ -0,0 +1,85 @@
+'use strict';
+
+import { writeFile } from 'fs/promises';
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_symbol_ranges,
+  build_symbol_ranges
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb symbol-ranges [<dir>] [--project=<project>] [--output=<file>]
+
+Emit a symbol range file for editor integrations built on tree-sitter:
+the UTF-8 byte ranges of the declarations of each Go file, with their
+kind, tree-sitter-go node type and tags capture, nested through parent
+references.  The document is written to standard output unless --output
+is given.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Format (version 1): a JSON object with format "codebuddy-symbol-ranges",
+version, encoding "utf-8" and files, each with path, byte_length and
+symbols.  Symbol fields:
+
+  * id - Index of the symbol in its file, ordered by start byte
+  * parent - id of the enclosing symbol (fields in their struct, method
+    elements in their interface), or null
+  * name - Declared name (the quoted path for imports)
+  * kind - package, import, function, method, struct, interface, type,
+    alias, field, interface_method, constant or variable
+  * node_type - tree-sitter-go node (function_declaration, type_spec...)
+  * capture - tags.scm capture (definition.function, definition.class...)
+  * start_byte, end_byte - Byte range of the node, end exclusive
+  * name_start_byte, name_end_byte - Byte range of the name
+  * start_point, end_point - Zero-based row and byte column of the range
+  * receiver - Receiver type of methods
+
+Arguments:
+
+  * <dir> - Directory to export
+  * --project=[project] - Name of an imported project to export instead
+  * --output=[file] - File to write (default: standard output)
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  let document;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    document = await analyze_project_symbol_ranges(project_id);
+  } else {
+    const directory = argv._[0] ? String(argv._[0]) : '.';
+    document = build_symbol_ranges(await read_go_sources(directory));
+  }
+
+  const content = `${JSON.stringify(document, null, 2)}\n`;
+  if (typeof argv.output !== 'string') {
+    process.stdout.write(content);
+    return;
+  }
+
+  await writeFile(argv.output, content);
+  const symbols = document.files.reduce((n, f) => n + f.symbols.length, 0);
+  console.log(
+    `Wrote ${symbols} symbols of ${document.files.length} files to ${argv.output}`
+  );
+};
+
+const symbol_ranges = {
+  command: 'symbol-ranges',
+  description: 'Emit tree-sitter compatible byte ranges of Go symbols',
+  handler,
+  help
+};
+
+export { symbol_ranges };
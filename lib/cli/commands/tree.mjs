@@ -0,0 +1,78 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_symbol_tree,
+  build_symbol_tree,
+  format_symbol_tree
+} from '../../analysis/index.mjs';
+import { read_go_sources } from '../sources.mjs';
+
+const help = `usage: cb tree [<dir>] [--project=<project>] [--depth=<n>] [--summary] [--all] [--json]
+
+Print a directory, file and symbol tree of Go sources, like tree but
+down to the declarations, for a structural map of a change or package:
+the types of each file with their methods, its functions, constants and
+variables, each with its line.  Directories and files show their number
+of symbols, exported and unexported.
+
+Trees of more than 500 symbols are summarized, showing directories and
+files only, unless --all is given.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to map
+  * --project=[project] - Name of an imported project to map instead
+  * --depth=[n] - Levels to show, collapsing the deeper ones into counts
+  * --summary - Show directories and files only
+  * --all - List every symbol, however large the tree
+  * --json - Write the whole tree as JSON
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  let tree;
+  if (typeof argv.project === 'string') {
+    const project_id = await get_project_id(argv.project);
+    tree = await analyze_project_symbol_tree(project_id);
+  } else {
+    const directory = argv._[0] ? String(argv._[0]) : '.';
+    tree = build_symbol_tree(await read_go_sources(directory));
+  }
+
+  if (argv.json) {
+    process.stdout.write(`${JSON.stringify(tree, null, 2)}\n`);
+    return;
+  }
+
+  if (tree.counts.files === 0) {
+    console.log('No Go files found');
+    return;
+  }
+  const lines = format_symbol_tree(tree, {
+    depth: argv.depth !== undefined ? parseInt(argv.depth, 10) : undefined,
+    summary: Boolean(argv.summary),
+    max_symbols: argv.all ? 0 : undefined
+  });
+  for (const line of lines) console.log(line);
+};
+
+const tree = {
+  command: 'tree',
+  description: 'Print a directory, file and symbol tree of Go sources',
+  handler,
+  help
+};
+
+export { tree };
@@ -0,0 +1,84 @@
+'use strict';
+
+import { get_project_by_name } from '../../model/project.mjs';
+import {
+  analyze_project_undocumented,
+  find_source_undocumented
+} from '../../analysis/index.mjs';
+import { read_go_sources, get_list } from '../sources.mjs';
+
+const help = `usage: cb undocumented [<dir>] [--project=<project>] [--kinds=<kinds>] [--exclude=<glob>] [--strict]
+
+List the exported Go symbols that lack a doc comment, the classic godoc
+completeness check.  Methods are checked when their receiver type is
+exported and fields when their struct is; a trailing comment documents a
+field.  Test files are skipped.
+
+Without --project the Go files of <dir> (default: the current directory)
+are read from disk.
+
+Arguments:
+
+  * <dir> - Directory to analyze
+  * --project=[project] - Name of an imported project to analyze instead
+  * --kinds=[kinds] - Comma separated kinds to require docs for: function, method, type, field, const, var (default: function,method,type,field)
+  * --exclude=[glob] - Skip files matching a glob, such as generated code (e.g. "*_gen.go"); may be repeated
+  * --strict - Exit with a non-zero status when symbols are undocumented, for CI
+`;
+
+// Helper to get project ID
+const get_project_id = async (project) => {
+  const projects = await get_project_by_name({ name: project });
+  if (projects.length === 0) {
+    throw new Error(`Project '${project}' not found`);
+  }
+  return projects[0].id;
+};
+
+const handler = async (argv) => {
+  const options = {
+    kinds: get_list(argv.kinds),
+    exclude: get_list(argv.exclude) || []
+  };
+
+  let result;
+  let target;
+  if (typeof argv.project === 'string') {
+    target = argv.project;
+    const project_id = await get_project_id(argv.project);
+    result = await analyze_project_undocumented(project_id, options);
+  } else {
+    target = argv._[0] ? String(argv._[0]) : '.';
+    result = find_source_undocumented(await read_go_sources(target), options);
+  }
+
+  console.log(`\n=== Undocumented Symbols: ${target} ===\n`);
+  console.log(`Kinds: ${result.kinds.join(', ')}`);
+  console.log(`Exported Symbols Checked: ${result.summary.checked}`);
+  console.log(`Undocumented: ${result.summary.undocumented}`);
+  console.log(`Coverage: ${result.summary.coverage}%\n`);
+
+  if (result.symbols.length === 0) {
+    console.log('Every exported symbol has a doc comment.');
+    return;
+  }
+
+  for (const symbol of result.symbols) {
+    console.log(
+      `${symbol.filename}:${symbol.line}: ${symbol.kind} ${symbol.name} has no doc comment`
+    );
+  }
+
+  if (argv.strict) {
+    process.exitCode = 1;
+  }
+};
+
+const undocumented = {
+  command: 'undocumented',
+  description: 'List exported Go symbols without doc comments',
+  handler,
+  help
+};
+
+export { undocumented };
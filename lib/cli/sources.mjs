@@ -0,0 +1,129 @@
+'use strict';
+
+/**
+ * @fileoverview Helpers shared by the CLI commands.
+ * Reads the files of a directory from disk for the commands that analyze
+ * a directory or a git revision instead of an imported project, and
+ * parses their options and paths.
+ * @module lib/cli/sources
+ */
+
+import path from 'path';
+import { promisify } from 'util';
+import { execFile as child_exec_file } from 'child_process';
+import { readFile } from 'fs/promises';
+import { import_file, get_all_filenames_with_type } from '../sourcecode.mjs';
+
+const exec_file = promisify(child_exec_file);
+
+/**
+ * Read the Go files of a directory, named relative to it with forward
+ * slashes and sorted by filename.
+ * @param {string} directory - The directory to read
+ * @returns {Promise<Object[]>} Files with filename and source
+ */
+const read_go_sources = async (directory) => {
+  const filenames = await get_all_filenames_with_type(directory, 'go');
+  const sources = [];
+  for (const filename of filenames.sort()) {
+    sources.push({
+      filename: path.relative(directory, filename).split(path.sep).join('/'),
+      source: await import_file(filename)
+    });
+  }
+  return sources;
+};
+
+/**
+ * Run git and return its output.
+ * @param {string[]} args - Arguments of git
+ * @param {string} [cwd] - Directory to run git in (default: the current directory)
+ * @returns {Promise<string>} Standard output of git
+ * @throws {Error} If git fails
+ */
+const run_git = async (args, cwd) => {
+  const { stdout } = await exec_file('git', args, {
+    cwd,
+    maxBuffer: 64 * 1024 * 1024
+  });
+  return stdout;
+};
+
+/**
+ * Read the module path of a directory from its go.mod.
+ * @param {string} directory - The module directory
+ * @returns {Promise<string|null>} The module path, or null without a go.mod
+ */
+const read_module_path = async (directory) => {
+  try {
+    const go_mod = await readFile(path.join(directory, 'go.mod'), 'utf-8');
+    const match = go_mod.match(/^module\s+"?([^\s"]+)"?/m);
+    return match ? match[1] : null;
+  } catch {
+    return null;
+  }
+};
+
+/**
+ * Read a JSON file, such as a catalog or a baseline, naming it in errors.
+ * @param {string} filename - The file to read
+ * @param {string} label - What the file is, for error messages
+ * @param {boolean} [optional=false] - Return null when the file does not exist
+ * @returns {Promise<Object|null>} The parsed file, or null for a missing optional file
+ * @throws {Error} If the file cannot be read or is not valid JSON
+ */
+const read_json = async (filename, label, optional = false) => {
+  let text;
+  try {
+    text = await readFile(filename, 'utf-8');
+  } catch (error) {
+    if (optional) return null;
+    throw error;
+  }
+  try {
+    return JSON.parse(text);
+  } catch (error) {
+    throw new Error(`Invalid ${label} ${filename}: ${error.message}`);
+  }
+};
+
+/**
+ * Split the arguments of a command into the directory to read and the
+ * output path of --output=<path>, -o=<path> or -o <path>.  Flags are
+ * parsed as booleans, so the path of -o <path> is the last positional
+ * argument.
+ * @param {Object} argv - Parsed arguments (from minimist)
+ * @returns {Object} Directory (default: the current directory) and output path or null
+ */
+const get_output_paths = (argv) => {
+  const positional = argv._.map(String);
+  let output = null;
+  if (typeof argv.output === 'string') output = argv.output;
+  else if (typeof argv.o === 'string') output = argv.o;
+  else if (argv.o === true && positional.length > 0) output = positional.pop();
+  return { directory: positional[0] || '.', output };
+};
+
+/**
+ * Read an option that may be repeated or comma separated
+ * (`--kinds=type,func --kinds=method`).
+ * @param {string|string[]|boolean|undefined} value - Option value (from minimist)
+ * @returns {string[]|undefined} Values, or undefined when the option is not given
+ */
+const get_list = (value) => {
+  if (value === undefined || typeof value === 'boolean') return undefined;
+  return [value]
+    .flat()
+    .flatMap((v) => String(v).split(','))
+    .map((v) => v.trim())
+    .filter(Boolean);
+};
+
+export {
+  read_go_sources,
+  run_git,
+  read_module_path,
+  read_json,
+  get_output_paths,
+  get_list
+};
@@ -940,6 +940,68 @@ const get_type_from_position = (entities, { start_line }) => {
   }
 };
 
+/**
+ * Determine whether a Go identifier is the target of an assignment.
+ * Handles plain, short variable and compound assignments (`x += 1`),
+ * increment/decrement statements (`c.value++`) and range clauses.  For a
+ * selector such as `c.value` the field is the write target, not `c`.
+ * @param {Object} node - The identifier node
+ * @returns {Object|null} Symbol context for a write, or null if not a write
+ */
+const get_go_write_context = (node) => {
+  let target = node;
+  let is_field = false;
+
+  const parent = node.parent;
+  if (parent?.type === 'selector_expression') {
+    if (parent.childForFieldName('field')?.id !== node.id) return null;
+    target = parent;
+    is_field = true;
+  }
+
+  // Writes through an index or parentheses still write the operand
+  while (
+    target.parent &&
+    (target.parent.type === 'parenthesized_expression' ||
+      (target.parent.type === 'index_expression' &&
+        target.parent.childForFieldName('operand')?.id === target.id))
+  ) {
+    target = target.parent;
+  }
+
+  const container = target.parent;
+  if (!container) return null;
+
+  let statement = null;
+  if (
+    container.type === 'inc_statement' ||
+    container.type === 'dec_statement'
+  ) {
+    statement = container;
+  } else if (
+    container.type === 'expression_list' &&
+    container.parent &&
+    ['assignment_statement', 'short_var_declaration', 'range_clause'].includes(
+      container.parent.type
+    ) &&
+    container.parent.childForFieldName('left')?.id === container.id
+  ) {
+    statement = container.parent;
+  }
+  if (!statement) return null;
+
+  const defines =
+    !is_field &&
+    target === node &&
+    (statement.type === 'short_var_declaration' ||
+      (statement.type === 'range_clause' && statement.text.includes(':=')));
+  return {
+    symbol_type: is_field ? 'field' : 'variable',
+    is_definition: defines,
+    is_write: true
+  };
+};
+
 /**
  * Determine the symbol type based on the parent node context.
  * @param {Object} node - The identifier node
@@ -1008,6 +1070,12 @@ const get_symbol_context = (node, language) => {
     return { symbol_type: 'parameter', is_definition: true, is_write: false };
   }
 
+  // Go assignments, including compound assignments and c.value++
+  if (language === 'go') {
+    const write_context = get_go_write_context(node);
+    if (write_context) return write_context;
+  }
+
   // Check for variable declarations
   const declaration_types = [
     'variable_declaration',
@@ -24,7 +24,68 @@ import {
   analyze_project_naming_conventions,
   analyze_project_readability_score,
   analyze_project_design_patterns,
-  analyze_project_test_coverage
+  analyze_project_test_coverage,
+  analyze_project_struct_sizes,
+  extract_literals,
+  analyze_project_channels,
+  analyze_project_nesting,
+  analyze_package_docs,
+  analyze_symbol_graph,
+  analyze_project_error_wrapping,
+  analyze_project_panics,
+  analyze_project_field_init,
+  get_workspace_symbols,
+  analyze_project_init_order,
+  analyze_go_scripts,
+  analyze_project_duplicate_methods,
+  analyze_project_context_params,
+  analyze_project_token_costs,
+  generate_stub,
+  analyze_project_undocumented,
+  analyze_project_doc_names,
+  analyze_project_struct_kinds,
+  analyze_project_near_misses,
+  analyze_build_constraints,
+  analyze_project_smell_scores,
+  analyze_project_method_shadowing,
+  analyze_project_conversions,
+  generate_minimal_repro,
+  analyze_project_concurrency_hints,
+  analyze_project_embedders,
+  analyze_project_escapes,
+  analyze_project_imports,
+  analyze_project_loop_captures,
+  analyze_project_conversions_between,
+  analyze_project_method_sets,
+  analyze_project_interface_widths,
+  analyze_project_struct_tags,
+  analyze_project_coupling,
+  analyze_project_unsafe_usage,
+  analyze_project_call_chains,
+  analyze_project_inline_hints,
+  analyze_project_unused_interfaces,
+  analyze_project_type_complexity,
+  check_format_calls,
+  analyze_project_copy_locks,
+  analyze_project_field_types,
+  analyze_project_method_implementers,
+  analyze_project_deprecated_calls,
+  analyze_project_interface_guideline,
+  analyze_project_function_values,
+  analyze_project_magic_numbers,
+  analyze_project_error_results,
+  analyze_project_options_structs,
+  analyze_project_handlers_of_shape,
+  analyze_project_recursive_structs,
+  analyze_project_doc_links,
+  analyze_project_builtin_shadowing,
+  analyze_project_state_machines,
+  analyze_project_package_metrics,
+  analyze_project_param_names,
+  analyze_project_state_aliasing,
+  analyze_project_unused_interface_methods,
+  analyze_project_predicate_names,
+  analyze_project_risk_scores
 } from '../../analysis/index.mjs';
 
 // =============================================================================
@@ -291,43 +352,2520 @@ export const analysis_tests_handler = async ({ project_name }) => {
   };
 };
 
+/**
+ * Computes Go struct sizes and finds large structs passed by value.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.threshold=80] - Size in bytes above which a struct is flagged
+ * @returns {Promise<Object>} MCP response with struct size analysis
+ */
+export const analysis_struct_size_handler = async ({
+  project_name,
+  threshold
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_struct_sizes(project_id, { threshold });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Extracts string, rune and numeric literals from Go code.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.kind] - Only return literals of this kind
+ * @param {boolean} [params.inline_only=false] - Only return inline literals
+ * @returns {Promise<Object>} MCP response with literals
+ */
+export const analysis_literals_handler = async ({
+  project_name,
+  kind,
+  inline_only = false
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await extract_literals(project_id, { kind, inline_only });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Models Go channels, channel operations and goroutine launch sites.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.package] - Only model this package
+ * @param {string} [params.function] - Only include operations of this function
+ * @returns {Promise<Object>} MCP response with the concurrency model
+ */
+export const analysis_channels_handler = async ({
+  project_name,
+  package: pkg,
+  function: fn
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_channels(project_id, {
+    package: pkg,
+    function: fn
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Detects guard clauses and scores nesting in Go functions.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with nesting analysis
+ */
+export const analysis_nesting_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_nesting(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Extracts and merges the package doc comments of Go packages.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with package docs
+ */
+export const analysis_package_docs_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_package_docs(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Builds the symbol graph of a project's Go code.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.edge_types] - Edge types to include
+ * @param {string} [params.package] - Only include this package
+ * @returns {Promise<Object>} MCP response with the symbol graph
+ */
+export const analysis_symbol_graph_handler = async ({
+  project_name,
+  edge_types,
+  package: package_name
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_symbol_graph(project_id, {
+    edge_types,
+    package: package_name
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Checks that Go packages wrap returned errors consistently.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.expected='auto'] - Expected convention
+ * @returns {Promise<Object>} MCP response with the convention check
+ */
+export const analysis_error_wrapping_handler = async ({
+  project_name,
+  expected
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_error_wrapping(project_id, {
+    expected
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds exported Go functions that can reach a panic.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.max_depth=10] - Maximum call depth to search
+ * @returns {Promise<Object>} MCP response with reachable panics
+ */
+export const analysis_panics_handler = async ({ project_name, max_depth }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_panics(project_id, { max_depth });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Reports whether exported Go struct fields are set by constructors or callers.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with field initialization info
+ */
+export const analysis_field_init_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_field_init(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Lists Go symbols in the LSP workspace/symbol format.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.query] - Fuzzy query
+ * @param {number} [params.limit=100] - Maximum number of symbols
+ * @returns {Promise<Object>} MCP response with workspace symbols
+ */
+export const analysis_workspace_symbols_handler = async ({
+  project_name,
+  query,
+  limit
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await get_workspace_symbols(project_id, { query, limit });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Computes the initialization order of Go package variables.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with dependencies, order and cycles
+ */
+export const analysis_init_order_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_init_order(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Lists Go scripts and generators excluded by go:build ignore.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with scripts
+ */
+export const analysis_go_scripts_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_go_scripts(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go methods with identical bodies across types.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.min_statements=2] - Minimum statements of a method
+ * @returns {Promise<Object>} MCP response with duplicate method groups
+ */
+export const analysis_duplicate_methods_handler = async ({
+  project_name,
+  min_statements
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_duplicate_methods(project_id, {
+    min_statements
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go functions that take a context.Context, but not first.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.allow_types_before] - Types allowed before the context
+ * @returns {Promise<Object>} MCP response with misplaced context parameters
+ */
+export const analysis_context_params_handler = async ({
+  project_name,
+  allow_types_before
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_context_params(project_id, {
+    allow_types_before
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Computes the token cost of each Go symbol and package.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.tokenizer='code'] - Tokenizer name
+ * @param {number} [params.limit] - Only return the most expensive symbols
+ * @returns {Promise<Object>} MCP response with token costs
+ */
+export const analysis_token_costs_handler = async ({
+  project_name,
+  tokenizer,
+  limit
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_token_costs(project_id, {
+    tokenizer,
+    limit
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Generates a stub type implementing a Go interface.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} params.interface_name - Interface name
+ * @param {string} [params.type_name] - Name of the stub type
+ * @returns {Promise<Object>} MCP response with the stub
+ */
+export const analysis_stub_handler = async ({
+  project_name,
+  interface_name,
+  type_name
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await generate_stub(project_id, interface_name, {
+    type_name
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Lists exported Go symbols without doc comments.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.kinds] - Kinds to require docs for
+ * @param {string[]} [params.exclude] - Globs of files to skip
+ * @returns {Promise<Object>} MCP response with undocumented symbols
+ */
+export const analysis_undocumented_handler = async ({
+  project_name,
+  kinds,
+  exclude
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_undocumented(project_id, {
+    kinds,
+    exclude
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Lists Go doc comments not beginning with the declared name.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.kinds] - Kinds to check
+ * @param {string[]} [params.exclude] - Globs of files to skip
+ * @returns {Promise<Object>} MCP response with misnamed doc comments
+ */
+export const analysis_doc_names_handler = async ({
+  project_name,
+  kinds,
+  exclude
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_doc_names(project_id, {
+    kinds,
+    exclude
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Classifies Go structs as data, service or mixed.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with struct kinds
+ */
+export const analysis_struct_kinds_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_struct_kinds(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go types a few methods away from implementing an interface.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.type_name] - Type name
+ * @param {string} [params.interface_name] - Interface name
+ * @param {number} [params.max_missing=1] - Largest number of missing or mismatched methods
+ * @returns {Promise<Object>} MCP response with near-miss reports
+ */
+export const analysis_near_misses_handler = async ({
+  project_name,
+  type_name,
+  interface_name,
+  max_missing
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_near_misses(project_id, {
+    type: type_name,
+    interface: interface_name,
+    max_missing
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Shows the effective build constraint of each Go file of a project.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.goos] - Target operating system
+ * @param {string} [params.goarch] - Target architecture
+ * @param {string[]} [params.tags] - Extra build tags of the target
+ * @returns {Promise<Object>} MCP response with file constraints
+ */
+export const analysis_build_constraints_handler = async ({
+  project_name,
+  goos,
+  goarch,
+  tags
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_build_constraints(project_id, {
+    goos,
+    goarch,
+    tags
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Ranks Go symbols and files by code smell score.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {Object} [params.weights] - Weights by diagnostic code
+ * @param {number} [params.limit=20] - Only return the worst symbols and files
+ * @returns {Promise<Object>} MCP response with smell scores
+ */
+export const analysis_smell_score_handler = async ({
+  project_name,
+  weights,
+  limit
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_smell_scores(project_id, {
+    weights,
+    limit
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go methods and fields shadowing promoted methods.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with shadowing reports
+ */
+export const analysis_method_shadowing_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_method_shadowing(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Lists Go type conversions and unchecked type assertions.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.unchecked_only=false] - Only functions with unchecked assertions
+ * @returns {Promise<Object>} MCP response with conversions and assertions
+ */
+export const analysis_conversions_handler = async ({
+  project_name,
+  unchecked_only
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_conversions(project_id, {
+    unchecked_only
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Extracts a Go symbol and its dependencies into a standalone Go file.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} params.symbol - Symbol name
+ * @returns {Promise<Object>} MCP response with the reproduction
+ */
+export const analysis_repro_handler = async ({ project_name, symbol }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await generate_minimal_repro(project_id, symbol);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Marks Go functions as likely safe or unsafe for concurrent use.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.hint] - Only functions with this hint
+ * @returns {Promise<Object>} MCP response with concurrency hints
+ */
+export const analysis_concurrency_hints_handler = async ({
+  project_name,
+  hint
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_concurrency_hints(project_id, { hint });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Lists the Go types embedding a type, directly or transitively.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} params.type_name - Embedded type name
+ * @param {boolean} [params.transitive=false] - Include transitive embedders
+ * @param {boolean} [params.include_unexported=false] - Include unexported embedders
+ * @returns {Promise<Object>} MCP response with the embedders
+ */
+export const analysis_embedders_handler = async ({
+  project_name,
+  type_name,
+  transitive,
+  include_unexported
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_embedders(project_id, type_name, {
+    transitive,
+    include_unexported
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go functions returning pointers to their local variables.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.include_composites=true] - Include composite literals
+ * @returns {Promise<Object>} MCP response with escaping pointers
+ */
+export const analysis_escapes_handler = async ({
+  project_name,
+  include_composites
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_escapes(project_id, {
+    include_composites
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the unused imports of Go files.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.unused_only=false] - Only list files with unused imports
+ * @returns {Promise<Object>} MCP response with import usage
+ */
+export const analysis_imports_handler = async ({
+  project_name,
+  unused_only
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_imports(project_id, { unused_only });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go goroutines capturing loop variables.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.go_version] - Target Go version
+ * @param {boolean} [params.include_fixed=false] - Include captures fixed by the Go version
+ * @returns {Promise<Object>} MCP response with loop variable captures
+ */
+export const analysis_loop_captures_handler = async ({
+  project_name,
+  go_version,
+  include_fixed
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_loop_captures(project_id, {
+    go_version,
+    include_fixed
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the Go conversions between two types.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} params.from - Type converted from
+ * @param {string} params.to - Type converted to
+ * @returns {Promise<Object>} MCP response with conversion spans
+ */
+export const analysis_conversions_between_handler = async ({
+  project_name,
+  from,
+  to
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_conversions_between(
+    project_id,
+    from,
+    to
+  );
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Shows the value and pointer method sets of a Go type.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} params.type_name - Type name
+ * @returns {Promise<Object>} MCP response with method sets and interfaces
+ */
+export const analysis_method_sets_handler = async ({
+  project_name,
+  type_name
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_method_sets(project_id, type_name);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Classifies Go interfaces by the size of their method set.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.max_methods] - Largest number of methods of an interface that is not wide
+ * @returns {Promise<Object>} MCP response with interface widths
+ */
+export const analysis_interface_widths_handler = async ({
+  project_name,
+  max_methods
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_interface_widths(project_id, {
+    max_methods
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Validates Go struct tags against a tag policy.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.required] - Tag keys every field must have
+ * @param {Object} [params.naming] - Naming rule by tag key
+ * @param {boolean} [params.include_embedded=false] - Check embedded fields
+ * @param {boolean} [params.include_unexported=false] - Check unexported fields
+ * @param {boolean} [params.all_structs=false] - Check structs without any tag of the policy keys
+ * @returns {Promise<Object>} MCP response with tag diagnostics
+ */
+export const analysis_struct_tags_handler = async ({
+  project_name,
+  required,
+  naming,
+  include_embedded,
+  include_unexported,
+  all_structs
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_struct_tags(project_id, {
+    required,
+    naming,
+    include_embedded,
+    include_unexported,
+    all_structs
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Computes the fan-in and fan-out of Go functions.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.limit] - Number of most coupled functions to list
+ * @param {boolean} [params.include_tests=false] - Include the functions of test files
+ * @returns {Promise<Object>} MCP response with coupling metrics
+ */
+export const analysis_coupling_handler = async ({
+  project_name,
+  limit,
+  include_tests
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_coupling(project_id, {
+    limit,
+    include_tests
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds unsafe, reflect, linkname and assembly usage in Go code.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.kinds] - Kinds of usage to report
+ * @returns {Promise<Object>} MCP response with usage sites
+ */
+export const analysis_unsafe_usage_handler = async ({ project_name, kinds }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_unsafe_usage(project_id, { kinds });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the longest call chain from an entry point.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.root] - Root function
+ * @param {number} [params.max_depth] - Maximum number of calls in a chain
+ * @returns {Promise<Object>} MCP response with call chains
+ */
+export const analysis_call_chain_handler = async ({
+  project_name,
+  root,
+  max_depth
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_call_chains(project_id, {
+    root,
+    max_depth
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Flags the Go functions likely to be inlined by the compiler.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.budget] - Inlining budget in syntax tree nodes
+ * @param {boolean} [params.inlinable_only=false] - Only list the inlining candidates
+ * @returns {Promise<Object>} MCP response with inline hints
+ */
+export const analysis_inline_hints_handler = async ({
+  project_name,
+  budget,
+  inlinable_only
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_inline_hints(project_id, {
+    budget,
+    inlinable_only
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the Go interfaces never used as a type.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.unexported_only=false] - Only list the unexported interfaces
+ * @returns {Promise<Object>} MCP response with unused interfaces
+ */
+export const analysis_unused_interfaces_handler = async ({
+  project_name,
+  unexported_only
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_unused_interfaces(project_id, {
+    unexported_only
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Ranks the Go types by the total complexity of their methods.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.limit] - Number of types to list
+ * @param {boolean} [params.include_promoted=false] - Include promoted methods
+ * @returns {Promise<Object>} MCP response with the most complex types
+ */
+export const analysis_type_complexity_handler = async ({
+  project_name,
+  limit,
+  include_promoted
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_type_complexity(project_id, {
+    limit,
+    include_promoted
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Checks the verbs of Go Printf-style calls against their arguments.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.mismatched_only=false] - Only list the calls with issues
+ * @returns {Promise<Object>} MCP response with format calls
+ */
+export const analysis_format_calls_handler = async ({
+  project_name,
+  mismatched_only
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await check_format_calls(project_id, { mismatched_only });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the Go types that must not be copied and the functions copying them.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with no-copy types and findings
+ */
+export const analysis_copy_locks_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_copy_locks(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Counts how often each Go struct field type is used.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.resolution='alias'] - Type resolution
+ * @param {boolean} [params.include_embedded=false] - Count embedded fields
+ * @returns {Promise<Object>} MCP response with the field type histogram
+ */
+export const analysis_field_types_handler = async ({
+  project_name,
+  resolution,
+  include_embedded
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_field_types(project_id, {
+    resolution,
+    include_embedded
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the types declaring a method with a given signature.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} params.method_name - Method name
+ * @param {string} params.signature - Method signature
+ * @returns {Promise<Object>} MCP response with the implementers
+ */
+export const analysis_method_implementers_handler = async ({
+  project_name,
+  method_name,
+  signature
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_method_implementers(
+    project_id,
+    method_name,
+    signature
+  );
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the calls to deprecated standard library functions.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with the deprecated calls
+ */
+export const analysis_deprecated_calls_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_deprecated_calls(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Checks the accept interfaces, return structs guideline.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.guideline] - Only check one guideline
+ * @returns {Promise<Object>} MCP response with the issues
+ */
+export const analysis_interface_guideline_handler = async ({
+  project_name,
+  guideline
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_interface_guideline(project_id, {
+    guideline
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the functions used as values.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.values_only=false] - Only list functions used as values
+ * @returns {Promise<Object>} MCP response with the functions and their value sites
+ */
+export const analysis_function_values_handler = async ({
+  project_name,
+  values_only = false
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_function_values(project_id, {
+    values_only
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the magic numbers that should be named constants.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.min_occurrences] - Number of uses from which a number is magic
+ * @param {number[]} [params.ignore] - Numbers never reported
+ * @returns {Promise<Object>} MCP response with the magic numbers
+ */
+export const analysis_magic_numbers_handler = async ({
+  project_name,
+  min_occurrences,
+  ignore
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_magic_numbers(project_id, {
+    min_occurrences,
+    ignore
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Checks that error results are returned last.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @returns {Promise<Object>} MCP response with the misplaced error results
+ */
+export const analysis_error_results_handler = async ({ project_name }) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_error_results(project_id);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the options structs and the functions taking them.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.min_fields] - Exported fields from which a data struct is an options struct
+ * @returns {Promise<Object>} MCP response with the functions and options structs
+ */
+export const analysis_options_structs_handler = async ({
+  project_name,
+  min_fields
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_options_structs(project_id, {
+    min_fields
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the functions and methods matching a handler shape.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} params.shape - Handler shape
+ * @param {boolean} [params.functions_only=false] - Leave out methods
+ * @returns {Promise<Object>} MCP response with the handlers
+ */
+export const analysis_handlers_of_shape_handler = async ({
+  project_name,
+  shape,
+  functions_only = false
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_handlers_of_shape(project_id, shape, {
+    functions_only
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the recursive structs.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.recursive_only=true] - Only list recursive structs
+ * @returns {Promise<Object>} MCP response with the structs
+ */
+export const analysis_recursive_structs_handler = async ({
+  project_name,
+  recursive_only = true
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_recursive_structs(project_id, {
+    recursive_only
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Extracts and resolves the doc comment links of Go symbols.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.unresolved_only=false] - Only list symbols with unresolved links
+ * @param {string[]} [params.exclude] - Globs of files to skip
+ * @returns {Promise<Object>} MCP response with the doc links
+ */
+export const analysis_doc_links_handler = async ({
+  project_name,
+  unresolved_only = false,
+  exclude
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_doc_links(project_id, {
+    unresolved_only,
+    exclude
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds the declarations shadowing Go builtin identifiers.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.problematic_only=false] - Only list problematic shadowing
+ * @returns {Promise<Object>} MCP response with the shadowing declarations
+ */
+export const analysis_builtin_shadowing_handler = async ({
+  project_name,
+  problematic_only = false
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_builtin_shadowing(project_id, {
+    problematic_only
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Infers the state machines of Go lifecycle types.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string} [params.type] - Only infer the state machine of this type
+ * @param {boolean} [params.dot=false] - Add the Graphviz digraph of each machine
+ * @returns {Promise<Object>} MCP response with the state machines
+ */
+export const analysis_state_machines_handler = async ({
+  project_name,
+  type,
+  dot = false
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_state_machines(project_id, {
+    type,
+    dot
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Computes the Martin metrics of Go packages.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.threshold=0.5] - Distance from the main sequence above which a package is in a zone
+ * @returns {Promise<Object>} MCP response with the package metrics
+ */
+export const analysis_package_metrics_handler = async ({
+  project_name,
+  threshold
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_package_metrics(project_id, {
+    threshold
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds exported Go functions with weak parameter names.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.acceptable] - Short names to accept, replacing the default list
+ * @param {string[]} [params.reasons] - Reasons to report
+ * @returns {Promise<Object>} MCP response with the functions
+ */
+export const analysis_param_names_handler = async ({
+  project_name,
+  acceptable,
+  reasons
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_param_names(project_id, {
+    acceptable,
+    reasons
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go methods returning internal slices or maps without copying.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.include_unexported=false] - Also check unexported methods
+ * @returns {Promise<Object>} MCP response with the aliasing returns
+ */
+export const analysis_state_aliasing_handler = async ({
+  project_name,
+  include_unexported
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_state_aliasing(project_id, {
+    include_unexported
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds Go interface methods never called through their interface.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {boolean} [params.unexported_only=false] - Only report the methods of unexported interfaces
+ * @param {string} [params.confidence] - Only report the methods of this confidence
+ * @returns {Promise<Object>} MCP response with the unused methods
+ */
+export const analysis_unused_interface_methods_handler = async ({
+  project_name,
+  unexported_only,
+  confidence
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_unused_interface_methods(project_id, {
+    unexported_only,
+    confidence
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Finds exported Go functions returning a bool without a predicate name.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {string[]} [params.prefixes] - Predicate prefixes, replacing the default list
+ * @param {string[]} [params.acceptable] - Names to accept, replacing the default list
+ * @returns {Promise<Object>} MCP response with the functions
+ */
+export const analysis_predicate_names_handler = async ({
+  project_name,
+  prefixes,
+  acceptable
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_predicate_names(project_id, {
+    prefixes,
+    acceptable
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Scores the risk of Go functions from their complexity and coupling.
+ * @param {Object} params - Parameters
+ * @param {string} params.project_name - Project name
+ * @param {number} [params.limit=20] - Number of functions to list
+ * @param {Object} [params.weights] - Weights by metric
+ * @returns {Promise<Object>} MCP response with the riskiest functions
+ */
+export const analysis_risk_scores_handler = async ({
+  project_name,
+  limit,
+  weights
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_project_risk_scores(project_id, {
+    limit,
+    weights
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
 // =============================================================================
 // Tool Definitions (for registration)
 // =============================================================================
 
-export const analysis_tools = [
+export const analysis_tools = [
+  {
+    name: 'analysis_dashboard',
+    description: `Returns a comprehensive analysis dashboard for a project with health scores and summaries of all analysis types including:
+- Health score (0-100) and rating
+- Dead code statistics
+- Code duplication metrics
+- Dependency analysis
+- Security vulnerabilities count
+- Code metrics and maintainability
+- Code smells
+- Type coverage
+- API surface analysis
+- Documentation coverage
+- Variable scope issues
+
+This is the best starting point for understanding overall code quality.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_dashboard_handler
+  },
+  {
+    name: 'analysis_dead_code',
+    description: `Detects dead code (unreferenced functions) in a project. A function is considered dead if:
+- It has no callers (no other function calls it)
+- It's not a likely entry point (main, init, handler, test, etc.)
+
+Returns list of potentially dead functions with their locations and line counts, sorted by size. Useful for identifying code that can be safely removed.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_dead_code_handler
+  },
+  {
+    name: 'analysis_duplication',
+    description: `Detects code duplication in a project by finding functions with similar structure. Uses AST-based similarity comparison.
+
+The threshold parameter (0.0-1.0) controls how similar functions must be to be considered duplicates:
+- 0.7 (default): Moderate similarity, catches most duplicates
+- 0.8+: High similarity, only very similar code
+- 0.5-0.7: Loose similarity, may include false positives
+
+Returns groups of similar functions that could potentially be refactored.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      threshold: z
+        .number()
+        .optional()
+        .default(0.7)
+        .describe('Similarity threshold (0.0-1.0, default 0.7)')
+    },
+    handler: analysis_duplication_handler
+  },
+  {
+    name: 'analysis_dependencies',
+    description: `Analyzes file dependencies in a project. Shows:
+- Import/include relationships between files
+- Circular dependencies (files that depend on each other)
+- Dependency graph metrics
+- Most depended-upon files (high fan-in)
+- Files with most dependencies (high fan-out)
+
+Circular dependencies are often a sign of poor architecture and can cause issues.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_dependencies_handler
+  },
+  {
+    name: 'analysis_security',
+    description: `Detects potential security vulnerabilities in the code including:
+- SQL injection risks
+- Command injection risks
+- Cross-site scripting (XSS) vulnerabilities
+- Hardcoded credentials/secrets
+- Insecure cryptographic usage
+- Path traversal vulnerabilities
+- Unsafe deserialization
+
+Returns vulnerabilities categorized by severity (high, medium, low) with locations and descriptions.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_security_handler
+  },
+  {
+    name: 'analysis_metrics',
+    description: `Calculates aggregate code metrics for a project including:
+- Total functions and lines of code
+- Average/max cyclomatic complexity
+- Average/max function length
+- Average/max nesting depth
+- Maintainability index (0-100 scale)
+- Maintainability rating (A-F)
+
+The maintainability index combines complexity, size, and documentation metrics into a single score.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_metrics_handler
+  },
+  {
+    name: 'analysis_code_smells',
+    description: `Detects code smells - patterns that indicate potential problems:
+- Long methods (functions with too many lines)
+- God functions (functions that do too much)
+- Long parameter lists
+- Deep nesting
+- Feature envy (functions that use other classes more than their own)
+- Data clumps (groups of data that appear together)
+
+Returns smells with severity, location, and refactoring suggestions.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_code_smells_handler
+  },
+  {
+    name: 'analysis_types',
+    description: `Analyzes type usage in a project:
+- Functions with type annotations vs without
+- Type coverage percentage
+- Most common types used
+- Functions that could benefit from type annotations
+
+Useful for gradually typed languages like Python and JavaScript/TypeScript.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_types_handler
+  },
+  {
+    name: 'analysis_api_surface',
+    description: `Analyzes the public API surface of a project:
+- Public vs private functions
+- Exported symbols
+- Entry points
+- Documentation coverage for public APIs
+
+Helps understand what interfaces a library/module exposes.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_api_surface_handler
+  },
+  {
+    name: 'analysis_documentation',
+    description: `Analyzes documentation coverage in a project:
+- Functions with docstrings/comments vs without
+- Documentation coverage percentage
+- Quality of existing documentation
+- Functions most in need of documentation (complex but undocumented)
+
+Good documentation improves maintainability and helps onboarding.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_documentation_handler
+  },
+  {
+    name: 'analysis_scope',
+    description: `Analyzes variable scope issues in a project:
+- Global variables that could be local
+- Variable shadowing (inner scope redefines outer variable)
+- Unused variables
+- Variables with too wide a scope
+
+Proper scoping reduces bugs and improves code clarity.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_scope_handler
+  },
+  {
+    name: 'analysis_concurrency',
+    description:
+      'Analyzes concurrency patterns in a project. Detects async/await, threads, locks, synchronization primitives, and potential race conditions.',
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_concurrency_handler
+  },
+  {
+    name: 'analysis_resources',
+    description:
+      'Analyzes memory and resource management patterns in a project. Detects resource acquisition/release, smart pointers, RAII patterns, and potential resource leaks.',
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_resources_handler
+  },
+  {
+    name: 'analysis_naming',
+    description: `Analyzes naming conventions in a project:
+- Detects naming style (camelCase, snake_case, PascalCase, etc.)
+- Identifies inconsistent naming
+- Flags names that are too short or unclear
+- Suggests naming improvements
+
+Consistent naming improves code readability.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_naming_handler
+  },
+  {
+    name: 'analysis_readability',
+    description: `Calculates readability scores for code in a project:
+- Function-level readability scores
+- Factors affecting readability (length, complexity, naming, etc.)
+- Most and least readable functions
+- Suggestions for improving readability
+
+Readable code is easier to maintain and has fewer bugs.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_readability_handler
+  },
+  {
+    name: 'analysis_patterns',
+    description: `Detects design patterns used in a project:
+- Creational patterns (Factory, Singleton, Builder, etc.)
+- Structural patterns (Adapter, Decorator, Facade, etc.)
+- Behavioral patterns (Observer, Strategy, Command, etc.)
+
+Understanding patterns helps comprehend code architecture.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_patterns_handler
+  },
+  {
+    name: 'analysis_tests',
+    description: `Analyzes test coverage and quality in a project:
+- Test files and test functions found
+- Functions with tests vs without
+- Test-to-code ratio
+- Test naming conventions
+- Identifies untested critical functions
+
+Good test coverage reduces bugs and enables safe refactoring.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_tests_handler
+  },
+  {
+    name: 'analysis_struct_size',
+    description: `Computes the memory layout of Go structs (size, alignment and padding on 64-bit platforms) and flags functions that pass large structs by value:
+- Value receivers and by-value parameters above the byte threshold
+- The computed size of each flagged parameter, including padding
+- Interfaces, pointers and small structs are never flagged
+
+Passing a pointer instead avoids copying the struct on every call.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      threshold: z
+        .number()
+        .optional()
+        .describe('Size in bytes above which a struct is flagged (default 80)')
+    },
+    handler: analysis_struct_size_handler
+  },
+  {
+    name: 'analysis_literals',
+    description: `Extracts string, rune and numeric literals from Go code with their values, spans and enclosing symbol. Each literal is classified by context:
+- const: part of a named constant declaration (acceptable)
+- var: initializer of a package level variable
+- import / struct_tag: import paths and struct field tags
+- inline: hardcoded anywhere else
+
+Useful for auditing hardcoded strings, URLs and magic numbers.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      kind: z
+        .enum(['string', 'rune', 'number'])
+        .optional()
+        .describe('Only return literals of this kind'),
+      inline_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only return inline literals')
+    },
+    handler: analysis_literals_handler
+  },
+  {
+    name: 'analysis_channels',
+    description: `Builds a best-effort model of Go channel and goroutine topology for each package:
+- Channels declared as locals, parameters, struct fields and package variables, with element type, buffer size and declared direction
+- Which functions send to, receive from and close each channel (direction recorded from usage)
+- Goroutine launch sites and the channels passed to them
+- Channels passed to other functions are followed through their parameters
+- Local channels that are only sent to or only received from are flagged
+
+Useful for reasoning about pipeline structure and potential deadlocks.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      package: z
+        .string()
+        .optional()
+        .describe('Only model this package (name or directory)'),
+      function: z
+        .string()
+        .optional()
+        .describe(
+          'Only include operations of this function (e.g. Run or Worker.Collect)'
+        )
+    },
+    handler: analysis_channels_handler
+  },
+  {
+    name: 'analysis_nesting',
+    description: `Analyzes how Go functions manage nesting and scores each from 0-100:
+- Maximum control flow nesting depth and the line where it occurs
+- Guard clauses (early returns at the top of the body) and whether the first statement is one
+- Else branches after an if block that already returns
+- Bodies wrapped in a single if that could return early instead
+
+Useful for readability coaching: prefer guard clauses over deeply nested conditionals.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_nesting_handler
+  },
+  {
+    name: 'analysis_package_docs',
+    description: `Extracts the package doc comment of each Go package, merged across its files:
+- The "// Package name ..." doc text, its first sentence, and the file carrying it (doc.go takes precedence)
+- Other files that also carry a package doc (conflicts), and whether their text matches
+- Docs that name a different package
+- File header comments (license banners, file descriptions) that are not package docs
+
+Useful for documentation generation and for an overview of what each package does.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_package_docs_handler
+  },
+  {
+    name: 'analysis_symbol_graph',
+    description: `Builds the symbol graph of a project's Go code:
+- Nodes for functions, methods, structs, interfaces and other named types
+- "calls" edges between functions and methods (with a call count)
+- "implements" edges from types to the project interfaces they satisfy
+- "embeds" edges for embedded struct fields and interfaces
+
+Useful for exploring how a codebase fits together. The same graph can be viewed interactively with the cb graph command.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      edge_types: z
+        .array(z.string())
+        .optional()
+        .describe(
+          'Edge types to include: calls, implements, embeds (default: all)'
+        ),
+      package: z
+        .string()
+        .optional()
+        .describe('Only include the package with this name or directory')
+    },
+    handler: analysis_symbol_graph_handler
+  },
+  {
+    name: 'analysis_error_wrapping',
+    description: `Checks that each Go package propagates errors consistently:
+- Classifies every error return site: wrapped (fmt.Errorf with %w), raw (returned as-is), annotated (formatted without %w), new, sentinel or nil
+- Detects each package's convention from the majority of propagated errors (wrap vs no-wrap)
+- Reports each return site that deviates from the expected convention
+
+Useful for enforcing a team's error handling style.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      expected: z
+        .enum(['auto', 'wrap', 'no-wrap'])
+        .optional()
+        .default('auto')
+        .describe(
+          'Expected convention; auto uses the majority pattern of each package'
+        )
+    },
+    handler: analysis_error_wrapping_handler
+  },
+  {
+    name: 'analysis_panics',
+    description: `Finds the exported Go functions that can reach a panic through the call graph:
+- The nearest panic site (panic or log.Panic) and the shortest call path to it
+- Functions that recover() in a deferred call stop the search
+- Calls through interfaces reach every implementing type in the project
+- Exported functions that call function values, which cannot be followed, are listed as unresolved
+
+Useful for robustness audits of library APIs.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      max_depth: z
+        .number()
+        .optional()
+        .default(10)
+        .describe('Maximum call depth to search')
+    },
+    handler: analysis_panics_handler
+  },
+  {
+    name: 'analysis_field_init',
+    description: `Determines how each exported field of the project's Go structs is typically initialized:
+- constructor: set by the struct's constructor functions (package functions returning it)
+- caller: set directly by callers in struct literals or assignments
+- mixed or none when neither dominates or the field is never set
+- Suggests unexporting fields with a getter or setter where callers bypass or override constructors
+
+A heuristic based on struct literals and field assignments across the project.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_field_init_handler
+  },
+  {
+    name: 'analysis_workspace_symbols',
+    description: `Lists the Go symbols of every package in the shape of the LSP workspace/symbol response:
+- name, kind (LSP SymbolKind), containerName and location (uri and range)
+- Methods use their receiver type as containerName; fields and interface methods their type
+- The query is fuzzy matched against names; a query with a dot (Client.Do) also matches the container
+
+Useful for "go to symbol in workspace" without a language server.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      query: z
+        .string()
+        .optional()
+        .describe('Fuzzy query; omit to list all symbols'),
+      limit: z
+        .number()
+        .optional()
+        .default(100)
+        .describe('Maximum number of symbols to return')
+    },
+    handler: analysis_workspace_symbols_handler
+  },
+  {
+    name: 'analysis_init_order',
+    description: `Computes the initialization order of each Go package's variables:
+- Dependency edges between package variables and the package functions their initializers call
+- The order Go initializes the variables in, and which variables are reordered because they depend on later declarations
+- Initialization cycles (compile errors) with the chain of references that forms them
+
+Useful for diagnosing init-order bugs.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_init_order_handler
+  },
+  {
+    name: 'analysis_go_scripts',
+    description: `Lists the Go files excluded from every build with //go:build ignore, such as code generators run with go run:
+- Package, description, imports, functions and types of each script
+- The //go:generate directives that run it
+- Scripts are kept separate from buildable packages, which other analyses use
+
+Useful for understanding how generated code is produced.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_go_scripts_handler
+  },
+  {
+    name: 'analysis_duplicate_methods',
+    description: `Finds Go methods on different types with identical logic, ignoring the receiver:
+- Groups of methods with their types and locations
+- Bodies are compared structurally, ignoring parameter and local names, comments and layout
+- Trivial one-liners are excluded
+- Suggests a package function, an embedded struct or a shared helper
+
+Useful for finding extract-to-function and embedding candidates.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      min_statements: z
+        .number()
+        .optional()
+        .default(2)
+        .describe('Minimum statements of a reported method')
+    },
+    handler: analysis_duplicate_methods_handler
+  },
+  {
+    name: 'analysis_context_params',
+    description: `Checks that Go functions take context.Context as their first parameter:
+- Functions, methods, interface methods and function types whose context is not first
+- The context parameter's name and 1-based position
+- Renamed and dot imports of context and aliases of context.Context are resolved
+- Functions without a context are not reported
+
+Useful for enforcing Go API style.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      allow_types_before: z
+        .array(z.string())
+        .optional()
+        .describe('Parameter types allowed before the context (e.g. *testing.T)')
+    },
+    handler: analysis_context_params_handler
+  },
+  {
+    name: 'analysis_token_costs',
+    description: `Computes how many tokens each Go symbol costs to include in context:
+- Tokens of the signature and of the full declaration, doc comment and body
+- Package totals
+- Symbols sorted by cost, most expensive first
+- The tokenizer used is always reported (code, chars or whitespace)
+
+Useful for deciding which symbols to include or trim when building context.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      tokenizer: z
+        .enum(['code', 'chars', 'whitespace'])
+        .optional()
+        .default('code')
+        .describe('Tokenizer to count with'),
+      limit: z
+        .number()
+        .optional()
+        .describe('Only return the most expensive symbols')
+    },
+    handler: analysis_token_costs_handler
+  },
+  {
+    name: 'analysis_stub',
+    description: `Generates a stub type implementing a Go interface:
+- One method per interface method, including embedded interfaces of the same package
+- Parameter and result names are kept from the interface; arg0, arg1... only when omitted
+- Methods return zero values, or use a bare return with named results
+- Embedded interfaces from other packages are listed for manual completion
+
+Useful for writing test doubles and scaffolding implementations.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      interface_name: z
+        .string()
+        .describe('Interface name, optionally qualified by package (e.g. store.Reader)'),
+      type_name: z
+        .string()
+        .optional()
+        .describe('Name of the stub type (default Stub<Interface>)')
+    },
+    handler: analysis_stub_handler
+  },
+  {
+    name: 'analysis_undocumented',
+    description: `Lists the exported Go symbols that lack a doc comment (godoc completeness):
+- Functions, methods of exported types, types and fields of exported structs by default
+- Constants and variables on request
+- Files can be excluded with globs, such as generated code
+- Coverage per kind
+
+Useful for improving API documentation before a release.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      kinds: z
+        .array(z.enum(['function', 'method', 'type', 'field', 'const', 'var']))
+        .optional()
+        .describe('Kinds to require docs for (default function, method, type, field)'),
+      exclude: z
+        .array(z.string())
+        .optional()
+        .describe('Globs of files to skip (e.g. *_gen.go)')
+    },
+    handler: analysis_undocumented_handler
+  },
+  {
+    name: 'analysis_doc_names',
+    description: `Lists the doc comments of exported Go symbols that do not begin with the declared name ("Add adds ..."), the godoc convention:
+- A leading article (A, An, The) and a type prefix on methods (Reader.Read) are accepted
+- A leading "Deprecated:" paragraph is skipped
+- Names written with different case, usually acronyms (Url for URL), are reported as case mismatches
+- Group doc comments and undocumented symbols are not checked
+
+Reports each symbol with the actual first word of its comment.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      kinds: z
+        .array(z.enum(['function', 'method', 'type', 'field', 'const', 'var']))
+        .optional()
+        .describe('Kinds to check (default function, method, type, const, var)'),
+      exclude: z
+        .array(z.string())
+        .optional()
+        .describe('Globs of files to skip (e.g. *_gen.go)')
+    },
+    handler: analysis_doc_names_handler
+  },
+  {
+    name: 'analysis_struct_kinds',
+    description: `Classifies Go structs as plain data or behavior-bearing (a heuristic):
+- data: no methods, or only getters, setters and String
+- service: behavior methods with the state mostly behind them
+- mixed: mostly exported fields with some behavior methods
+- The role of each method (getter, setter, formatting, behavior)
+
+Useful for architectural overviews and organizing documentation.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_struct_kinds_handler
+  },
+  {
+    name: 'analysis_near_misses',
+    description: `Finds Go types that almost implement an interface:
+- Lists the interface methods present, missing and declared with the wrong signature
+- Gives the exact missing signatures, e.g. "missing Perimeter() float64"
+- Resolves embedded interfaces and methods promoted from embedded fields
+- With both type_name and interface_name, reports that pair; otherwise scans the project
+
+Useful for explaining "does not implement" errors and finishing partial implementations.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      type_name: z
+        .string()
+        .optional()
+        .describe('Type name, optionally qualified by package (e.g. geo.Triangle)'),
+      interface_name: z
+        .string()
+        .optional()
+        .describe('Interface name, optionally qualified by package (e.g. geo.Shape)'),
+      max_missing: z
+        .number()
+        .optional()
+        .default(1)
+        .describe('Largest number of missing or mismatched methods')
+    },
+    handler: analysis_near_misses_handler
+  },
+  {
+    name: 'analysis_build_constraints',
+    description: `Shows the effective build constraint of each Go file:
+- The //go:build or // +build expression ANDed with filename suffixes (_linux, _amd64, _linux_amd64)
+- Test files (_test.go) are flagged; _test is not treated as a tag
+- With goos, goarch or tags, reports which files are built for that target
+
+Useful for understanding platform specific code and which files a build includes.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      goos: z
+        .string()
+        .optional()
+        .describe('Target operating system (e.g. linux)'),
+      goarch: z
+        .string()
+        .optional()
+        .describe('Target architecture (e.g. amd64)'),
+      tags: z
+        .array(z.string())
+        .optional()
+        .describe('Extra build tags of the target (e.g. purego)')
+    },
+    handler: analysis_build_constraints_handler
+  },
+  {
+    name: 'analysis_smell_score',
+    description: `Ranks Go symbols and files by a code smell score rolling up other diagnostics:
+- Metrics (complexity, length, nesting, parameters) score their relative overshoot of a threshold
+- Counts (unchecked_error, discarded_error, panic, misplaced_context, undocumented) score occurrences
+- A symbol scores the weighted sum of its diagnostics, a file the sum of its symbols
+- Weights are configurable per diagnostic code; a weight of 0 disables a diagnostic
+
+Useful for deciding where to refactor first.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      weights: z
+        .record(z.number())
+        .optional()
+        .describe('Weights by diagnostic code (e.g. { "panic": 3 })'),
+      limit: z
+        .number()
+        .optional()
+        .default(20)
+        .describe('Only return the worst symbols and files')
+    },
+    handler: analysis_smell_score_handler
+  },
+  {
+    name: 'analysis_method_shadowing',
+    description: `Finds Go struct methods and fields that shadow a method promoted from an embedded type:
+- override: same signature as the promoted method (intentional)
+- collision: different signature, or a field, hiding the promoted method
+- Collisions are accidental unless the method calls the embedded one or its doc says so
+- Lists the interfaces a collision stops the struct from implementing
+
+Useful for catching embedded methods hidden by mistake.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        )
+    },
+    handler: analysis_method_shadowing_handler
+  },
+  {
+    name: 'analysis_conversions',
+    description: `Lists the type conversions and type assertions of each Go function:
+- Conversions to predeclared, slice, pointer and project types (Fahrenheit(c*9/5 + 32))
+- Type assertions: comma-ok (v, ok := x.(T)), single-value (x.(T)) and type switches
+- Single-value assertions panic on a type mismatch and are reported with the comma-ok form to use
+
+Useful for finding assertions that can panic.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      unchecked_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list functions with unchecked assertions')
+    },
+    handler: analysis_conversions_handler
+  },
+  {
+    name: 'analysis_repro',
+    description: `Extracts a Go symbol and the declarations it depends on into a single, self-contained Go file:
+- Types, functions, constants and variables of the symbol's package, with doc comments
+- Methods that are called or needed by copied interfaces
+- Renamed copies of project package types, and functions that panic
+- Opaque placeholders for other packages; standard library imports are kept
+
+Useful for sharing a bug reproduction or focusing on one piece of code.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      symbol: z
+        .string()
+        .describe('Symbol (Name or Type.Method), optionally qualified by package (e.g. geo.Circle.Area)')
+    },
+    handler: analysis_repro_handler
+  },
+  {
+    name: 'analysis_concurrency_hints',
+    description: `Marks each Go function with a best-effort hint of whether it is safe for concurrent use:
+- safe: only local state is used
+- unsafe: package variables, pointer/slice/map arguments or pointer receivers are written without a lock, directly or through called package functions
+- unknown: package variables are read, or written under a lock
+- Doc comments claiming "safe for concurrent use" on unsafe functions are flagged
+
+Useful for documenting concurrency guarantees and finding shared state.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      hint: z
+        .enum(['safe', 'unknown', 'unsafe'])
+        .optional()
+        .describe('Only list functions with this hint')
+    },
+    handler: analysis_concurrency_hints_handler
+  },
+  {
+    name: 'analysis_embedders',
+    description: `Lists the public Go struct and interface types that embed a type:
+- Direct embedders, and on request types embedding it through other types
+- The embedding path of transitive embedders (Manager > Employee > User)
+- Embedding across project packages (staff.User)
+
+Useful for impact analysis before changing the API of an embedded type.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      type_name: z
+        .string()
+        .describe('Embedded type, optionally qualified by package (e.g. staff.User)'),
+      transitive: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Include types embedding the type through other types'),
+      include_unexported: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Include unexported embedders')
+    },
+    handler: analysis_embedders_handler
+  },
+  {
+    name: 'analysis_escapes',
+    description: `Finds the Go functions returning the address of one of their variables (return &x), which escapes to the heap:
+- local: a variable declared in the function
+- param: a parameter or value receiver, a copy of the caller's value
+- range: a range variable, a copy of the element rather than the element itself
+- composite: a composite literal (&Config{...}), the idiomatic heap allocation
+
+Informational; useful for learning Go and for allocation sensitive code.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      include_composites: z
+        .boolean()
+        .optional()
+        .default(true)
+        .describe('Include composite literals (&T{...})')
+    },
+    handler: analysis_escapes_handler
+  },
+  {
+    name: 'analysis_imports',
+    description: `Cross-references the imports of each Go file against the qualified identifiers (pkg.Name) used in it and reports unused imports with their span (line, column, end_line, end_column).
+
+Blank (_) and dot (.) imports are always used. Names of packages outside the standard library and the project are guessed from their path (guessed is set). Useful to catch unused imports before compiling, in editors and in generated code.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      unused_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list files with unused imports')
+    },
+    handler: analysis_imports_handler
+  },
+  {
+    name: 'analysis_loop_captures',
+    description: `Finds goroutines launched in a loop with a function literal capturing a loop variable (for _, v := range xs { go func() { use(v) }() }), reporting the capture site.
+
+Before Go 1.22 all iterations share the loop variable, so goroutines may see a later value. Go 1.22 declares loop variables per iteration: give go_version to suppress the captures it fixes. Files with a //go:build go1.22 constraint are always fixed.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      go_version: z
+        .string()
+        .optional()
+        .describe('Target Go version, the go directive of go.mod (e.g. 1.21)'),
+      include_fixed: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Include captures fixed by the Go version')
+    },
+    handler: analysis_loop_captures_handler
+  },
+  {
+    name: 'analysis_conversions_between',
+    description: `Finds every conversion between two Go types across a project, in both directions (To(x) where x is a From, From(x) where x is a To), with their span.
+
+For auditing unit confusion, such as Celsius values reinterpreted as Fahrenheit without the formula. The operand type is inferred best effort from variables, fields, constants and function results; uninferrable conversions are counted as unresolved.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      from: z
+        .string()
+        .describe('Type converted from, alone or qualified (Celsius, temperature.Celsius)'),
+      to: z
+        .string()
+        .describe('Type converted to, alone or qualified (Fahrenheit)')
+    },
+    handler: analysis_conversions_between_handler
+  },
+  {
+    name: 'analysis_method_sets',
+    description: `Shows the method sets of a Go type separately for values (T: value receiver methods, and methods promoted through embedded pointers) and pointers (*T: every method), with the project interfaces satisfied by *T and whether T values satisfy them too.
+
+Use this to find out why a value does not implement an interface while a pointer does.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      type_name: z
+        .string()
+        .describe('Type name, alone or qualified by package (Counter, counter.Counter)')
+    },
+    handler: analysis_method_sets_handler
+  },
+  {
+    name: 'analysis_interface_widths',
+    description: `Classifies the Go interfaces of a project by the size of their method set, including embedded interfaces:
+- narrow: exactly one method (io.Reader, fmt.Stringer), the idiomatic kind
+- regular: a few methods
+- wide: more than max_methods methods, a potential design smell
+- empty: no methods; constraint: a type set (~int | ~float64)
+
+Useful for interface design coaching: wide interfaces are candidates for splitting.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      max_methods: z
+        .number()
+        .optional()
+        .default(10)
+        .describe('Largest number of methods of an interface that is not wide')
+    },
+    handler: analysis_interface_widths_handler
+  },
+  {
+    name: 'analysis_struct_tags',
+    description: `Validates Go struct tags against a tag policy: the tag keys every field must have and a naming rule for each key's names (default: a snake_case json name on every field).
+
+Reports missing keys, names breaking the rule and tags not in key:"value" format, with the tag schema of each checked struct. Fields ignored with "-" comply; unnamed tags (json:",omitempty") use the field name. Only structs tagged with a policy key are checked unless all_structs is set.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      required: z
+        .array(z.string())
+        .optional()
+        .describe('Tag keys every field must have (default ["json"])'),
+      naming: z
+        .record(z.string())
+        .optional()
+        .describe(
+          'Naming rule by tag key: snake_case, camelCase, PascalCase, SCREAMING_SNAKE_CASE, kebab_case, flatcase, UPPERCASE (default {"json": "snake_case"})'
+        ),
+      include_embedded: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Check embedded fields'),
+      include_unexported: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Check unexported fields'),
+      all_structs: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Check structs without any tag of the policy keys')
+    },
+    handler: analysis_struct_tags_handler
+  },
+  {
+    name: 'analysis_coupling',
+    description: `Computes the coupling metrics of every Go function and method from the project's call graph:
+- fan-out: number of distinct functions it calls (high for entry points such as main)
+- fan-in: number of distinct callers (high for widely used helpers)
+
+Returns the metrics with callers and callees of each function, and the most coupled functions by combined fan-in and fan-out. Useful for finding hotspots where a change spreads in both directions.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      limit: z
+        .number()
+        .optional()
+        .default(20)
+        .describe('Number of most coupled functions to list'),
+      include_tests: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Include the functions of test files')
+    },
+    handler: analysis_coupling_handler
+  },
+  {
+    name: 'analysis_unsafe_usage',
+    description: `Audits where Go code steps outside of the type and memory safety of the language. Flags each file and function with the kinds of usage in it and reports every import, call and directive site with its severity:
+- unsafe: uses of the unsafe package (unsafe.Pointer, unsafe.Add; Sizeof, Offsetof and Alignof are low severity)
+- reflect: uses of the reflect package (SliceHeader, StringHeader, NewAt, UnsafeAddr and UnsafePointer are high severity)
+- linkname: //go:linkname directives
+- assembly: functions declared without a body
+
+Package uses are resolved through the imports of each file, so variables and fields named unsafe are not reported.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      kinds: z
+        .array(z.enum(['unsafe', 'reflect', 'linkname', 'assembly']))
+        .optional()
+        .describe('Kinds of usage to report (default: all)')
+    },
+    handler: analysis_unsafe_usage_handler
+  },
+  {
+    name: 'analysis_call_chain',
+    description: `Finds the longest call chain from main (or a given root function) through the Go call graph: the ordered path of functions with its length. Deep chains can indicate over-layering.
+
+Recursive calls are not followed but reported as cycles, with the functions making them marked as recursive. Chains are bounded by a maximum depth.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      root: z
+        .string()
+        .optional()
+        .describe(
+          'Root function: name, qualified name (server.Serve, Server.Start) or id (cmd/app:main); default: the main functions'
+        ),
+      max_depth: z
+        .number()
+        .optional()
+        .default(50)
+        .describe('Maximum number of calls in a chain')
+    },
+    handler: analysis_call_chain_handler
+  },
+  {
+    name: 'analysis_inline_hints',
+    description: `Flags the Go functions likely to be inlined by the compiler: small, non-recursive functions with a single return and no defer, recover, closure, loop, goroutine or select. Loosely follows the gc inliner's budget of 80 syntax tree nodes; a heuristic, not the compiler's decision.
+
+Returns each function with its inline hint: inlinable, estimated cost and the reasons it is not a candidate (cost, recursive, multiple_returns, defer, recover, closure, loop, goroutine, select, noinline, no_body). Useful for suggesting hot-path optimizations.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      budget: z
+        .number()
+        .optional()
+        .default(80)
+        .describe('Inlining budget in syntax tree nodes'),
+      inlinable_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list the inlining candidates')
+    },
+    handler: analysis_inline_hints_handler
+  },
+  {
+    name: 'analysis_unused_interfaces',
+    description: `Finds interface pollution in Go code: interfaces never used as the type of a parameter, result, variable or field, nor embedded, used as a type constraint or in an expression. Compile-time assertions (var _ I = (*T)(nil)) do not count as uses.
+
+Returns each unused interface with its location, the number of compile-time assertions and a severity: warning for unexported interfaces, info for exported ones, which may be part of the public API.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      unexported_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list the unexported interfaces')
+    },
+    handler: analysis_unused_interfaces_handler
+  },
+  {
+    name: 'analysis_type_complexity',
+    description: `Ranks Go types by the total cyclomatic complexity of their methods, to find the "god types" concentrating the logic of a package that should be split.
+
+Returns the most complex types with their total, average and maximum method complexity, their share of the project's method complexity and their methods by complexity. Promoted methods of embedded types can be included.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      limit: z
+        .number()
+        .optional()
+        .default(10)
+        .describe('Number of types to list'),
+      include_promoted: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Include the methods promoted from embedded types')
+    },
+    handler: analysis_type_complexity_handler
+  },
   {
-    name: 'analysis_dashboard',
-    description: `Returns a comprehensive analysis dashboard for a project with health scores and summaries of all analysis types including:
-- Health score (0-100) and rating
-- Dead code statistics
-- Code duplication metrics
-- Dependency analysis
-- Security vulnerabilities count
-- Code metrics and maintainability
-- Code smells
-- Type coverage
-- API surface analysis
-- Documentation coverage
-- Variable scope issues
+    name: 'analysis_format_calls',
+    description: `Checks the format strings of Go Printf-style calls (fmt.Printf, fmt.Sprintf, fmt.Errorf, fmt.Fprintf, log.Printf, log.Fatalf, ...) against the number of arguments of the call, as go vet does. Handles %% escapes, * widths and precisions and argument indexes.
 
-This is the best starting point for understanding overall code quality.`,
+Returns each call with its location, enclosing function, verbs, argument counts and issues: missing_args, extra_args or no_verb. Calls with a non-literal format or spread arguments are unchecked.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      mismatched_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list the calls with issues')
     },
-    handler: analysis_dashboard_handler
+    handler: analysis_format_calls_handler
   },
   {
-    name: 'analysis_dead_code',
-    description: `Detects dead code (unreferenced functions) in a project. A function is considered dead if:
-- It has no callers (no other function calls it)
-- It's not a likely entry point (main, init, handler, test, etc.)
+    name: 'analysis_copy_locks',
+    description: `Finds the Go types that must not be copied, like go vet's copylocks check: types containing a sync.Mutex, RWMutex, WaitGroup, Once or other sync or sync/atomic value, directly or through fields and arrays, and project lock types such as the noCopy marker.
 
-Returns list of potentially dead functions with their locations and line counts, sorted by size. Useful for identifying code that can be safely removed.`,
+Returns the no-copy types with the path to their lock, and the value receivers and by-value parameters copying them on every call.`,
     schema: {
       project_name: z
         .string()
@@ -335,42 +2873,53 @@ Returns list of potentially dead functions with their locations and line counts,
           'The name of the project to analyze (use project_list to see available projects)'
         )
     },
-    handler: analysis_dead_code_handler
+    handler: analysis_copy_locks_handler
   },
   {
-    name: 'analysis_duplication',
-    description: `Detects code duplication in a project by finding functions with similar structure. Uses AST-based similarity comparison.
-
-The threshold parameter (0.0-1.0) controls how similar functions must be to be considered duplicates:
-- 0.7 (default): Moderate similarity, catches most duplicates
-- 0.8+: High similarity, only very similar code
-- 0.5-0.7: Loose similarity, may include false positives
+    name: 'analysis_field_types',
+    description: `Counts how often each type is used by the fields of the Go structs of a project (string, int, time.Time, custom types), and lists the field names declared with different types, such as an ID that is an int in one struct and an int64 in another. Useful to standardize the types of a schema.
 
-Returns groups of similar functions that could potentially be refactored.`,
+Types are normalized before counting: none keeps them as written, alias replaces aliases by their type, underlying also replaces defined types (type UserID int64) by their underlying type.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
         ),
-      threshold: z
-        .number()
+      resolution: z
+        .enum(['none', 'alias', 'underlying'])
         .optional()
-        .default(0.7)
-        .describe('Similarity threshold (0.0-1.0, default 0.7)')
+        .default('alias')
+        .describe('How type names are resolved before counting'),
+      include_embedded: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Count embedded fields')
     },
-    handler: analysis_duplication_handler
+    handler: analysis_field_types_handler
   },
   {
-    name: 'analysis_dependencies',
-    description: `Analyzes file dependencies in a project. Shows:
-- Import/include relationships between files
-- Circular dependencies (files that depend on each other)
-- Dependency graph metrics
-- Most depended-upon files (high fan-in)
-- Files with most dependencies (high fan-out)
+    name: 'analysis_method_implementers',
+    description: `Finds the Go types declaring a method with a given name and signature, whatever the interfaces they implement: the types with a String() string method, for instance. Useful to understand a convention of a code base that no interface states.
 
-Circular dependencies are often a sign of poor architecture and can cause issues.`,
+Signatures match on their parameter and result types, ignoring names. Types declaring a method of that name with another signature are listed as mismatches.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      method_name: z.string().describe('Method name (e.g. String)'),
+      signature: z
+        .string()
+        .describe('Method signature without the name (e.g. "() string")')
+    },
+    handler: analysis_method_implementers_handler
+  },
+  {
+    name: 'analysis_deprecated_calls',
+    description: `Finds the calls to deprecated functions of the Go standard library (ioutil.ReadFile, rand.Seed, strings.Title...) in a project, with the call site, the Go version deprecating the function and its recommended replacement. Calls are resolved through the imports of each file. Useful to keep a code base current.`,
     schema: {
       project_name: z
         .string()
@@ -378,60 +2927,71 @@ Circular dependencies are often a sign of poor architecture and can cause issues
           'The name of the project to analyze (use project_list to see available projects)'
         )
     },
-    handler: analysis_dependencies_handler
+    handler: analysis_deprecated_calls_handler
   },
   {
-    name: 'analysis_security',
-    description: `Detects potential security vulnerabilities in the code including:
-- SQL injection risks
-- Command injection risks
-- Cross-site scripting (XSS) vulnerabilities
-- Hardcoded credentials/secrets
-- Insecure cryptographic usage
-- Path traversal vulnerabilities
-- Unsafe deserialization
+    name: 'analysis_interface_guideline',
+    description: `Checks the exported Go functions of a project against the "accept interfaces, return structs" guideline, as a heuristic. Flags parameters of a project struct type only used to call methods (accept_interfaces), suggesting the project interfaces the type satisfies that declare those methods, and results of a project interface type always returned as the same exported struct (return_structs).
 
-Returns vulnerabilities categorized by severity (high, medium, low) with locations and descriptions.`,
+Parameters whose fields are read or which are passed on, factories returning several types, error results and unexported implementations are not reported.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      guideline: z
+        .enum(['accept_interfaces', 'return_structs'])
+        .optional()
+        .describe('Only check one guideline')
     },
-    handler: analysis_security_handler
+    handler: analysis_interface_guideline_handler
   },
   {
-    name: 'analysis_metrics',
-    description: `Calculates aggregate code metrics for a project including:
-- Total functions and lines of code
-- Average/max cyclomatic complexity
-- Average/max function length
-- Average/max nesting depth
-- Maintainability index (0-100 scale)
-- Maintainability rating (A-F)
+    name: 'analysis_function_values',
+    description: `Finds the Go functions and methods of a project used as values rather than called directly: passed as arguments (callbacks), assigned to variables or struct fields, stored in composite literals (handler maps) or returned. Reports for each function whether it is used as a value, its number of direct calls and its value sites (argument, assignment, field, composite, return or other).
 
-The maintainability index combines complexity, size, and documentation metrics into a single score.`,
+A function used as a value may be called indirectly from anywhere the value flows: treat it as reachable even without direct callers.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      values_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list functions used as values')
     },
-    handler: analysis_metrics_handler
+    handler: analysis_function_values_handler
   },
   {
-    name: 'analysis_code_smells',
-    description: `Detects code smells - patterns that indicate potential problems:
-- Long methods (functions with too many lines)
-- God functions (functions that do too much)
-- Long parameter lists
-- Deep nesting
-- Feature envy (functions that use other classes more than their own)
-- Data clumps (groups of data that appear together)
+    name: 'analysis_magic_numbers',
+    description: `Finds the magic numbers of a Go project: number values used inline in several places (3 by default) rather than through a named constant. Uses are grouped by value (4096 and 0x1000 are the same number); constant and package variable declarations are not counted. Each number is reported with its sites and a suggestion: extract a named constant, or use the constants already declared with that value.
 
-Returns smells with severity, location, and refactoring suggestions.`,
+The common numbers 0, 1, -1 and 2 are left out unless ignore is given. Test files are left out.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      min_occurrences: z
+        .number()
+        .int()
+        .optional()
+        .describe('Number of uses from which a number is magic (default 3)'),
+      ignore: z
+        .array(z.number())
+        .optional()
+        .describe('Numbers never reported (default [0, 1, -1, 2])')
+    },
+    handler: analysis_magic_numbers_handler
+  },
+  {
+    name: 'analysis_error_results',
+    description: `Checks that the Go functions of a project returning an error return it as their last result, as Go convention requires ((float64, error), not (error, float64)). Functions, methods, interface methods and function types are checked. Reports each misplaced error with its symbol, 1-based position and number of results; signatures returning several errors are flagged as multiple.`,
     schema: {
       project_name: z
         .string()
@@ -439,176 +2999,284 @@ Returns smells with severity, location, and refactoring suggestions.`,
           'The name of the project to analyze (use project_list to see available projects)'
         )
     },
-    handler: analysis_code_smells_handler
+    handler: analysis_error_results_handler
   },
   {
-    name: 'analysis_types',
-    description: `Analyzes type usage in a project:
-- Functions with type annotations vs without
-- Type coverage percentage
-- Most common types used
-- Functions that could benefit from type annotations
+    name: 'analysis_options_structs',
+    description: `Finds the Go functions and methods whose sole or last parameter is an options struct (the "options struct" pattern, such as NewServer(addr string, cfg Config)), and links each to the struct, so that docs can present the available options together. A project struct, by value or pointer, is an options struct when its name ends with Options, Opts, Config, Settings or Params, or when it is a plain data struct (only exported fields, no methods) with many fields (4 by default).
 
-Useful for gradually typed languages like Python and JavaScript/TypeScript.`,
+Returns the functions with their options_param (name, position, type, struct, reason) and the options structs with their options (fields with type and doc) and the functions taking them. Functional options (variadic) are left out.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      min_fields: z
+        .number()
+        .int()
+        .optional()
+        .describe(
+          'Exported fields from which a data struct is an options struct (default 4)'
         )
     },
-    handler: analysis_types_handler
+    handler: analysis_options_structs_handler
   },
   {
-    name: 'analysis_api_surface',
-    description: `Analyzes the public API surface of a project:
-- Public vs private functions
-- Exported symbols
-- Entry points
-- Documentation coverage for public APIs
+    name: 'analysis_handlers_of_shape',
+    description: `Finds the Go functions and methods of a project whose signature matches a handler shape, whatever their name: every func(http.ResponseWriter, *http.Request), func(context.Context) error or other callback shape of a middleware or pipeline framework. Useful to discover registered handlers and route definitions.
 
-Helps understand what interfaces a library/module exposes.`,
+The shape is a function signature, with or without the func keyword; parameter names are ignored and the type _ matches any type. Renamed imports are followed. Test files are left out.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      shape: z
+        .string()
+        .describe(
+          'Handler shape, e.g. "func(http.ResponseWriter, *http.Request)" or "func(context.Context) error"'
+        ),
+      functions_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Leave out methods')
     },
-    handler: analysis_api_surface_handler
+    handler: analysis_handlers_of_shape_handler
   },
   {
-    name: 'analysis_documentation',
-    description: `Analyzes documentation coverage in a project:
-- Functions with docstrings/comments vs without
-- Documentation coverage percentage
-- Quality of existing documentation
-- Functions most in need of documentation (complex but undocumented)
+    name: 'analysis_recursive_structs',
+    description: `Finds the recursive Go structs of a project: linked lists, trees and graphs whose fields reference the struct itself through pointers, slices, maps or channels (Next *Node, Children []*Node). Each struct has recursive, recursion (direct when a field references the struct itself, mutual when it only does through a group of other structs), the self-referencing fields and the other structs of its recursive group.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      recursive_only: z
+        .boolean()
+        .optional()
+        .default(true)
+        .describe('Only list recursive structs')
+    },
+    handler: analysis_recursive_structs_handler
+  },
+  {
+    name: 'analysis_doc_links',
+    description: `Extracts the doc links of the package docs and exported Go symbols of a project ([Calculator], [Calculator.Add], [store.Entry], [example.com/app/store.Entry]) and resolves them against the project's symbols, for cross-linked documentation and link-rot detection:
+- resolved: the type, function, constant, variable, method or field is declared in the project
+- external: the link targets a package outside the project, such as the standard library
+- unresolved: the symbol is missing from a project package
 
-Good documentation improves maintainability and helps onboarding.`,
+URLs, bare or from link definitions ([Go spec]: https://go.dev/ref/spec), are listed separately as urls. Code blocks are skipped.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      unresolved_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list symbols with unresolved links'),
+      exclude: z
+        .array(z.string())
+        .optional()
+        .describe('Globs of files to skip (e.g. *_gen.go)')
     },
-    handler: analysis_documentation_handler
+    handler: analysis_doc_links_handler
   },
   {
-    name: 'analysis_scope',
-    description: `Analyzes variable scope issues in a project:
-- Global variables that could be local
-- Variable shadowing (inner scope redefines outer variable)
-- Unused variables
-- Variables with too wide a scope
+    name: 'analysis_builtin_shadowing',
+    description: `Finds the Go parameters, results, receivers, variables and package level declarations named after a builtin identifier (len, cap, new, make, error, string, nil), which hide the builtin in their scope. Each shadowing has the builtin it hides (builtin_kind function, type or constant), problematic and a reason:
+- package: a package level declaration hides the builtin in every file of the package
+- used: the scope also calls or refers to the builtin (len(s), string(b), []string), which now refers to the declaration
+- constant: true, false, nil or iota is shadowed
+- unused: harmless, the builtin is not needed in the scope
 
-Proper scoping reduces bugs and improves code clarity.`,
+Locals are in scope from the end of their statement, so len := len(s) reads the builtin. Methods and struct fields shadow nothing.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      problematic_only: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Only list problematic shadowing')
     },
-    handler: analysis_scope_handler
+    handler: analysis_builtin_shadowing_handler
   },
   {
-    name: 'analysis_concurrency',
-    description:
-      'Analyzes concurrency patterns in a project. Detects async/await, threads, locks, synchronization primitives, and potential race conditions.',
+    name: 'analysis_state_machines',
+    description: `Infers, best-effort, the state machines implied by Go lifecycle types: structs whose methods (Start, Pause, Stop) assign the constants of a state field (a field of an enum type, or named state, status, phase or stage). Each machine has its field, state_type, initial state, states (initial, terminal, reachable), transitions (method, from states or * for any, to, line) and edges as a graph, complete when every state is reachable from the initial state, and the unreachable states.
+
+Transitions are limited by the guards of the method: if s.state != A { return } before the assignment (from A), if s.state == A { return } (from any state but A), an enclosing if s.state == A or case A of a switch s.state. Other guards allow any state.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      type: z
+        .string()
+        .optional()
+        .describe('Only infer the state machine of this type'),
+      dot: z
+        .boolean()
+        .optional()
+        .default(false)
+        .describe('Add the Graphviz digraph of each machine')
     },
-    handler: analysis_concurrency_handler
+    handler: analysis_state_machines_handler
   },
   {
-    name: 'analysis_resources',
-    description:
-      'Analyzes memory and resource management patterns in a project. Detects resource acquisition/release, smart pointers, RAII patterns, and potential resource leaks.',
+    name: 'analysis_package_metrics',
+    description: `Computes Robert C. Martin's metrics of each Go package from the imports between the packages of the project, for a quantitative view of dependency health:
+- afferent_coupling (Ca): project packages depending on the package
+- efferent_coupling (Ce): project packages it depends on
+- instability: Ce / (Ca + Ce), 0 when stable, 1 when unstable
+- abstractness: share of interfaces among its types
+- distance: distance from the main sequence, |A + I - 1|
+
+Packages farther than the threshold from the main sequence are in the zone of pain (concrete and stable, hard to change) or of uselessness (abstract and unstable). Imports outside the project and test files do not count.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      threshold: z
+        .number()
+        .optional()
+        .describe(
+          'Distance from the main sequence above which a package is in a zone (default 0.5)'
         )
     },
-    handler: analysis_resources_handler
+    handler: analysis_package_metrics_handler
   },
   {
-    name: 'analysis_naming',
-    description: `Analyzes naming conventions in a project:
-- Detects naming style (camelCase, snake_case, PascalCase, etc.)
-- Identifies inconsistent naming
-- Flags names that are too short or unclear
-- Suggests naming improvements
+    name: 'analysis_param_names',
+    description: `Finds the exported Go functions, methods of exported types and methods of exported interfaces whose parameter names documentation cannot refer to. Each function has its signature and weak_params (name, position, type, reason):
+- unnamed: the parameter list has no names (Add(int, int))
+- blank: the parameter is named _
+- numbered: a placeholder with a number (arg0, p1)
+- short: a single letter (Add(a, b int)), a borderline case
 
-Consistent naming improves code readability.`,
+Idiomatic short names are accepted: i, j, k, n, ok, err, ctx, id, fn, db and tx by default, and single letters for their conventional types (w http.ResponseWriter, r *http.Request, t *testing.T, p []byte). Receivers are never flagged.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      acceptable: z
+        .array(z.string())
+        .optional()
+        .describe('Short names to accept, replacing the default list'),
+      reasons: z
+        .array(z.enum(['unnamed', 'blank', 'numbered', 'short']))
+        .optional()
+        .describe('Only report these reasons')
     },
-    handler: analysis_naming_handler
+    handler: analysis_param_names_handler
   },
   {
-    name: 'analysis_readability',
-    description: `Calculates readability scores for code in a project:
-- Function-level readability scores
-- Factors affecting readability (length, complexity, naming, etc.)
-- Most and least readable functions
-- Suggestions for improving readability
+    name: 'analysis_state_aliasing',
+    description: `Finds the Go methods returning an unexported slice or map field of their receiver rather than a copy, letting callers change the internal state. Each alias has the type, method, field, collection (slice or map) and kind:
+- field: the field itself (return c.items)
+- subslice: a slice of the field (return c.items[1:])
+- local: a local variable holding the field
 
-Readable code is easier to maintain and has fewer bugs.`,
+Returns of a copy (slices.Clone, maps.Clone, append to nil, copy, a filling loop) are listed in copies. Exported methods are checked unless include_unexported is set.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      include_unexported: z
+        .boolean()
+        .optional()
+        .describe('Also check unexported methods')
     },
-    handler: analysis_readability_handler
+    handler: analysis_state_aliasing_handler
   },
   {
-    name: 'analysis_patterns',
-    description: `Detects design patterns used in a project:
-- Creational patterns (Factory, Singleton, Builder, etc.)
-- Structural patterns (Adapter, Decorator, Facade, etc.)
-- Behavioral patterns (Observer, Strategy, Command, etc.)
+    name: 'analysis_unused_interface_methods',
+    description: `Finds the Go interface methods no caller invokes through their interface, candidates for removal from the abstraction (interface minimization). Calls are tracked through parameters, variables, fields, collections, type assertions and type parameters typed with the interface; calls through an embedding interface count for the declaring one, calls in test files are given apart as test_calls. Each interface is classified as used, partial or unused.
 
-Understanding patterns helps comprehend code architecture.`,
+Tracking is heuristic: each unused method has a confidence, low when it may be used after all, with the reasons in uncertainty:
+- exported: the interface is exported, other modules may call it
+- struct_embedding: the interface is embedded in a struct
+- reflection: the project calls methods through reflect
+- standard_method: the method is that of a standard library interface (String, Close...)`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      unexported_only: z
+        .boolean()
+        .optional()
+        .describe('Only report the methods of unexported interfaces'),
+      confidence: z
+        .enum(['high', 'low'])
+        .optional()
+        .describe('Only report the methods of this confidence')
     },
-    handler: analysis_patterns_handler
+    handler: analysis_unused_interface_methods_handler
   },
   {
-    name: 'analysis_tests',
-    description: `Analyzes test coverage and quality in a project:
-- Test files and test functions found
-- Functions with tests vs without
-- Test-to-code ratio
-- Test naming conventions
-- Identifies untested critical functions
+    name: 'analysis_predicate_names',
+    description: `Finds the exported Go functions, methods of exported types and methods of exported interfaces returning a single bool whose names do not read as predicates (Is, Has, Can or Should followed by a word). Each function has its signature and a suggested rename: Empty to IsEmpty, AllowWrite to CanWrite, NeedsFlush to ShouldFlush, KeyExists to HasKey.
 
-Good test coverage reduces bugs and enables safe refactoring.`,
+Comma-ok results ((string, bool)) and (bool, error) are not checked. Idiomatic names (Less, Next, Equal, Contains, Match, Exists) are accepted by default.`,
     schema: {
       project_name: z
         .string()
         .describe(
           'The name of the project to analyze (use project_list to see available projects)'
-        )
+        ),
+      prefixes: z
+        .array(z.string())
+        .optional()
+        .describe('Predicate prefixes, replacing Is, Has, Can and Should'),
+      acceptable: z
+        .array(z.string())
+        .optional()
+        .describe('Names to accept, replacing the default list')
     },
-    handler: analysis_tests_handler
+    handler: analysis_predicate_names_handler
+  },
+  {
+    name: 'analysis_risk_scores',
+    description: `Scores the risk of each Go function and method from 0 to 100, to prioritize testing and review: the weighted mean of its cyclomatic complexity (counted by decision points), fan-in and fan-out, each normalized on a log scale against the highest value of the project (log(1 + x) / log(1 + max)). High-complexity, high-fan-in functions are the riskiest. Default weights: complexity 0.4, fan_in 0.3, fan_out 0.1. Returns the riskiest functions with their metrics and the contribution of each (factors), the score of every function by id (scores) and a summary. Git churn is only available from the risk command.`,
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project to analyze (use project_list to see available projects)'
+        ),
+      limit: z
+        .number()
+        .optional()
+        .describe('Number of functions to list (default: 20)'),
+      weights: z
+        .object({
+          complexity: z.number().min(0).optional(),
+          fan_in: z.number().min(0).optional(),
+          fan_out: z.number().min(0).optional()
+        })
+        .optional()
+        .describe('Weights of the metrics, merged with the default ones')
+    },
+    handler: analysis_risk_scores_handler
   }
 ];
@@ -12,7 +12,9 @@ import {
   go_to_definition,
   list_definitions,
   get_symbol_reference_summary,
-  find_symbols_at_location
+  find_symbols_at_location,
+  analyze_rename_impact,
+  compare_symbol
 } from '../../analysis/index.mjs';
 
 // =============================================================================
@@ -140,6 +142,52 @@ export const symbols_at_location_handler = async ({
   };
 };
 
+/**
+ * Computes the impact of renaming a symbol within its package.
+ * @param {Object} params - Parameters
+ * @param {string} params.project - Project name
+ * @param {string} params.symbol - Symbol name to rename
+ * @param {string} params.new_name - Proposed new name
+ * @param {string} [params.filename] - File containing the definition to rename
+ * @returns {Promise<Object>} MCP response with edit locations and conflicts
+ */
+export const rename_impact_handler = async ({
+  project_name,
+  symbol,
+  new_name,
+  filename
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await analyze_rename_impact(project_id, symbol, new_name, {
+    filename
+  });
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
+/**
+ * Compares a symbol's declaration between two files of a project.
+ * @param {Object} params - Parameters
+ * @param {string} params.project - Project name
+ * @param {string} params.symbol - Symbol name (Type.Method for methods)
+ * @param {string} params.file_a - File with the original declaration
+ * @param {string} params.file_b - File with the new declaration
+ * @returns {Promise<Object>} MCP response with the declaration changes
+ */
+export const compare_symbol_handler = async ({
+  project_name,
+  symbol,
+  file_a,
+  file_b
+}) => {
+  const project_id = await get_project_id(project_name);
+  const result = await compare_symbol(project_id, symbol, file_a, file_b);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(result) }]
+  };
+};
+
 // =============================================================================
 // Tool Definitions (for registration)
 // =============================================================================
@@ -237,5 +285,42 @@ export const reference_tools = [
         .describe('Column number for precise matching')
     },
     handler: symbols_at_location_handler
+  },
+  {
+    name: 'rename_impact',
+    description:
+      'Computes the impact of renaming a symbol within its package: every declaration and reference that would need editing, plus any name collisions or shadowing the new name would cause. Does not modify any files.',
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project (use project_list to see available projects)'
+        ),
+      symbol: z.string().describe('Symbol name to rename'),
+      new_name: z.string().describe('Proposed new name for the symbol'),
+      filename: z
+        .string()
+        .optional()
+        .describe('File containing the definition to rename (for context)')
+    },
+    handler: rename_impact_handler
+  },
+  {
+    name: 'compare_symbol',
+    description:
+      "Compares a single symbol's declaration between two Go files of a project, reporting signature, receiver, type parameter, field, method, value and doc comment changes. Changes that affect the API are flagged. Useful for checking that moving or refactoring code preserved its API.",
+    schema: {
+      project_name: z
+        .string()
+        .describe(
+          'The name of the project (use project_list to see available projects)'
+        ),
+      symbol: z
+        .string()
+        .describe('Symbol name to compare (Type.Method for methods)'),
+      file_a: z.string().describe('File with the original declaration'),
+      file_b: z.string().describe('File with the new declaration')
+    },
+    handler: compare_symbol_handler
   }
 ];
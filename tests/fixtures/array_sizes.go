@@ -0,0 +1,35 @@
+package sizes
+
+import "example.com/app/limits"
+
+const (
+	keySize   = 32
+	blockSize = keySize * 2
+)
+
+var primes = [...]int{2, 3, 5, 7, 11}
+
+var sparse = [...]string{4: "e", "f"}
+
+var points = [...]struct{ X, Y int32 }{{1, 2}, {3, 4}, {5, 6}}
+
+const primeCount = len(primes)
+
+// Keyed arrays use a constant length.
+type Keyed struct {
+	Key   [keySize]byte
+	Block [blockSize]byte
+}
+
+// Counted arrays take their length from implicit-length arrays.
+type Counted struct {
+	Primes [primeCount]uint16
+	Sparse [len(sparse)]bool
+	Points [len(points)]int64
+}
+
+// Remote arrays cannot be sized from this package.
+type Remote struct {
+	ID     int64
+	Buffer [limits.MaxSize]uint32
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event is something that happened.
+type Event interface {
+	Name() string
+}
+
+// Click is a mouse click.
+type Click struct {
+	X, Y int
+}
+
+// Name returns the name of the click.
+func (c Click) Name() string { return "click" }
+
+// Millis is a duration in milliseconds.
+type Millis int64
+
+// Handle dispatches an event, trusting its type.
+func Handle(e Event) int {
+	c := e.(Click)
+	return c.X + e.(*Click).Y
+}
+
+// SafeHandle dispatches an event, checking its type.
+func SafeHandle(e Event) int {
+	if c, ok := e.(Click); ok {
+		return c.X
+	}
+	p, ok := e.(*Click)
+	if !ok {
+		return 0
+	}
+	return p.Y
+}
+
+// Describe describes an event with a type switch.
+func Describe(v any) string {
+	switch e := v.(type) {
+	case Click:
+		return fmt.Sprintf("click at %d,%d", e.X, e.Y)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Lookup reads a click from a map of values.
+func Lookup(values map[string]any, key string) Click {
+	return values[key].(Click)
+}
+
+// Elapsed converts a duration.
+func Elapsed(d time.Duration) Millis {
+	ms := Millis(d / time.Millisecond)
+	label := []byte(fmt.Sprint(int64(ms)))
+	_ = string(label)
+	return ms
+}
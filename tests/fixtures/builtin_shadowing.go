@@ -0,0 +1,85 @@
+package shadow
+
+import "strings"
+
+// max predates the builtin and hides it in the whole package.
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Pad pads s to len characters: the builtin len cannot be called.
+func Pad(s string, len int) string {
+	for i := 0; i < len; i++ {
+		s += " "
+	}
+	return s
+}
+
+// Count counts the words of s; its len parameter breaks the len call.
+func Count(s string, len int) int {
+	words := strings.Fields(s)
+	if len > 0 {
+		return len
+	}
+	return cap(words) - len(words)
+}
+
+// Size reads the builtin before shadowing it.
+func Size(items []string) int {
+	len := len(items)
+	return len * 2
+}
+
+// Grow calls new after shadowing it.
+func Grow(n int) *int {
+	new := n + 1
+	p := new(int)
+	*p = new
+	return p
+}
+
+// Convert names a result after a type it still needs.
+func Convert(b []byte) (string string) {
+	return string(b)
+}
+
+// Describe uses a local variable named error, and no error type after.
+func Describe(ok bool) string {
+	var error = "failed"
+	if ok {
+		error = "ok"
+	}
+	return error
+}
+
+// Apply takes a callback named copy, whose calls are its own.
+func Apply(copy func(int) int) int {
+	return copy(1)
+}
+
+// Flags shadows a constant.
+func Flags() bool {
+	true := false
+	return true
+}
+
+// Each calls a function literal with a parameter named cap.
+func Each(items []int, fn func(int)) {
+	walk := func(cap int) {
+		fn(cap)
+	}
+	walk(len(items))
+}
+
+type list struct {
+	len  int
+	next *list
+}
+
+// append is a method and shadows nothing.
+func (l *list) append(v int) {
+	l.len += v
+}
@@ -0,0 +1,57 @@
+package main
+
+import "strings"
+
+// Server forwards requests through its layers.
+type Server struct {
+	prefix string
+}
+
+func main() {
+	s := &Server{}
+	s.Handle("request")
+	isEven(4)
+	log("started")
+}
+
+// Handle is the first layer.
+func (s *Server) Handle(request string) string {
+	return s.route(request)
+}
+
+func (s *Server) route(request string) string {
+	return dispatch(request)
+}
+
+func dispatch(request string) string {
+	return validate(request)
+}
+
+func validate(request string) string {
+	return normalize(request)
+}
+
+func normalize(request string) string {
+	return strings.ToLower(trim(request))
+}
+
+func trim(request string) string {
+	log(request)
+	return strings.TrimSpace(request)
+}
+
+func log(message string) {}
+
+func isEven(n int) bool {
+	if n == 0 {
+		return true
+	}
+	return isOdd(n - 1)
+}
+
+func isOdd(n int) bool {
+	if n == 0 {
+		return false
+	}
+	return isEven(n - 1)
+}
@@ -0,0 +1,11 @@
+package geo
+
+// Square is a shape with four equal sides.
+type Square struct {
+	Side float64
+}
+
+// Area returns the area of the square.
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
@@ -0,0 +1,8 @@
+package plot
+
+import "example.com/geo"
+
+// Plot draws a shape.
+func Plot(s geo.Shape) string {
+	return geo.Summarize(s)
+}
@@ -0,0 +1,6 @@
+package geo
+
+// Polygon is a shape with straight sides.
+type Polygon struct {
+	Points [][2]float64
+}
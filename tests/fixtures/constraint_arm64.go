@@ -0,0 +1,6 @@
+// +build linux,cgo darwin
+// +build !purego
+
+package poll
+
+func arm64Wait() error { return nil }
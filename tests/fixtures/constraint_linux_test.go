@@ -0,0 +1,9 @@
+package poll
+
+import "testing"
+
+func TestWait(t *testing.T) {
+	if err := Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
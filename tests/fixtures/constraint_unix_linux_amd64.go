@@ -0,0 +1,6 @@
+//go:build (linux || darwin) && amd64
+
+package poll
+
+// unixWait waits on linux.
+func unixWait() error { return nil }
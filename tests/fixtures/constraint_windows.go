@@ -0,0 +1,6 @@
+//go:build go1.21 || purego
+
+package poll
+
+// Wait waits with IOCP.
+func Wait() error { return nil }
@@ -0,0 +1,52 @@
+package fetch
+
+import (
+	"context"
+	stdctx "context"
+	"net/http"
+	"testing"
+)
+
+// Context is an alias of context.Context.
+type Context = context.Context
+
+// Fetcher fetches documents.
+type Fetcher interface {
+	// Fetch takes its context first.
+	Fetch(ctx context.Context, url string) ([]byte, error)
+	// Store takes its context last.
+	Store(key string, data []byte, ctx context.Context) error
+}
+
+// Callback is called with a misplaced context.
+type Callback func(code int, ctx context.Context)
+
+// Get takes its context first.
+func Get(ctx context.Context, url string) (*http.Response, error) {
+	return nil, nil
+}
+
+// Post takes its context after the url.
+func Post(url string, ctx context.Context, body []byte) error {
+	return nil
+}
+
+// Client is an HTTP client.
+type Client struct{}
+
+// Do takes its context first through the alias.
+func (c *Client) Do(ctx Context, req *http.Request) error {
+	return nil
+}
+
+// Retry takes a renamed context import last.
+func (c *Client) Retry(attempts int, ctx stdctx.Context) error {
+	return nil
+}
+
+// Parse does not take a context.
+func Parse(data []byte) error {
+	return nil
+}
+
+func helper(t *testing.T, ctx context.Context) {}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// noCopy marks a struct that must not be copied after first use.
+type noCopy struct{}
+
+func (*noCopy) Lock()   {}
+func (*noCopy) Unlock() {}
+
+// Cache guards its entries with a mutex.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// Get returns an entry; the value receiver copies the mutex.
+func (c Cache) Get(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[key]
+}
+
+// Set stores an entry.
+func (c *Cache) Set(key, value string) {
+	c.mu.Lock()
+	c.entries[key] = value
+	c.mu.Unlock()
+}
+
+// Pool embeds a wait group.
+type Pool struct {
+	sync.WaitGroup
+	size int
+}
+
+// Stats holds counters behind the noCopy marker.
+type Stats struct {
+	_    noCopy
+	hits atomic.Int64
+}
+
+// Shards has an array of caches.
+type Shards struct {
+	shards [4]Cache
+}
+
+// Ref only points to a cache, and is safe to copy.
+type Ref struct {
+	cache  *Cache
+	caches []Cache
+}
+
+// Dump prints a cache passed by value.
+func Dump(c Cache, pool *Pool, shards Shards, ref Ref) {}
+
+// Wait waits for a pool passed by value.
+func Wait(p Pool, pools ...Pool) {
+	p.Wait()
+}
@@ -0,0 +1,53 @@
+package app
+
+import "fmt"
+
+// Config is the application configuration.
+type Config struct {
+	Name string
+}
+
+// Server serves requests.
+type Server struct {
+	cfg Config
+}
+
+func main() {
+	cfg := load()
+	s := newServer(cfg)
+	s.Start()
+	report(cfg.Name)
+}
+
+func load() Config {
+	return Config{Name: normalize("app")}
+}
+
+func newServer(cfg Config) *Server {
+	logf("new server %s", cfg.Name)
+	return &Server{cfg: cfg}
+}
+
+// Start starts the server.
+func (s *Server) Start() {
+	logf("starting %s", normalize(s.cfg.Name))
+	s.listen()
+}
+
+func (s *Server) listen() {
+	logf("listening")
+}
+
+func report(name string) {
+	logf("report %s", name)
+}
+
+func normalize(name string) string {
+	return name
+}
+
+func logf(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+}
+
+func unused() {}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	iu "io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Load reads a configuration file.
+func Load(path string) ([]byte, error) {
+	data, err := iu.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+// Heading formats a section heading.
+func Heading(name string) string {
+	// strings.Title(name) used to be called here
+	return strings.Title(strings.TrimSpace(name)) + ": ioutil.ReadFile()"
+}
+
+type store struct{}
+
+func (s store) ReadFile(name string) ([]byte, error) { return nil, nil }
+
+// Fetch reads through a local value, not the ioutil package.
+func Fetch() {
+	ioutil := store{}
+	ioutil.ReadFile("x")
+	_ = rand.Intn(10)
+}
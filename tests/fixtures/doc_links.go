@@ -0,0 +1,47 @@
+// Package calc implements a [Calculator] over [store.Register] values.
+//
+// See the [Go spec] for the arithmetic rules.
+//
+// [Go spec]: https://go.dev/ref/spec
+package calc
+
+import (
+	"io"
+
+	"example.com/app/store"
+	st "example.com/app/store"
+)
+
+// Precision is the default precision; see [Calculator.Round].
+const Precision = 2
+
+// Calculator adds numbers, keeping a [*store.Register].  It is written
+// to an [io.Writer] by [Calculator.Print].  Results are rounded to
+// [Precision] digits, and kept in [Calculator.Total].
+type Calculator struct {
+	// Total is the running total, reset by [Calculator.Clear].
+	Total float64
+	reg   *st.Register
+}
+
+// Add adds a number, as [Calculator.Sum] does for several.  The value is
+// stored with [st.Register.Set], then see [Subtract] and [store.Counter].
+//
+// It used to be [Calcualtor.Add]; the values are in m[index] and
+// a[i] form in code, where [not a link] stays plain text.
+//
+//	x := c.Add(1) // [Inside] code blocks is not a link
+func (c *Calculator) Add(n float64) {
+	c.Total += n
+	c.reg.Set(n)
+}
+
+// Round rounds the total, as described at https://example.com/rounding.
+// It uses [example.com/app/store.Register] and [math.Round].
+func (c *Calculator) Round() {}
+
+// Print writes the total to w.
+func (c *Calculator) Print(w io.Writer) {}
+
+// Clear clears the total.
+func (c *Calculator) Clear() {}
@@ -0,0 +1,13 @@
+// Package store keeps registers.
+package store
+
+// Register holds a value; see [Register.Get] and [Register.Value].
+type Register struct {
+	Value float64
+}
+
+// Set sets the value.
+func (r *Register) Set(v float64) { r.Value = v }
+
+// Get gets the value, without a link to [Register.Reset].
+func (r *Register) Get() float64 { return r.Value }
@@ -0,0 +1,67 @@
+package fetch
+
+// Colors of the status line.  A group doc describes the group.
+const (
+	Red   = "red"
+	Green = "green"
+)
+
+const (
+	// Timeout bounds each request.
+	Timeout = 30
+
+	// how many times to retry.
+	Retries = 3
+)
+
+// MaxSize, MinSize bound the body size.
+const MaxSize, MinSize = 1 << 20, 0
+
+// A Client fetches documents.
+type Client struct {
+	// Base URL of every request.
+	Base string
+}
+
+// Fetch fetches a document.
+func (c *Client) Fetch(path string) string {
+	return c.Base + path
+}
+
+// Client.Close releases the connection.
+func (c *Client) Close() {}
+
+// Returns the number of documents fetched.
+func (c *Client) Count() int {
+	return 0
+}
+
+// UrlParser parses document URLs.
+type URLParser struct{}
+
+// ID's are the identifiers of documents.
+type ID string
+
+// Deprecated: use Fetch.
+func Get(path string) string {
+	return path
+}
+
+// Deprecated: use NewClient.
+//
+// Dial connects to the server.
+func Dial() *Client {
+	return &Client{}
+}
+
+// Deprecated: use NewClient.
+//
+// Opens a connection.
+func Open() *Client {
+	return &Client{}
+}
+
+// This function creates a client.
+func NewClient() *Client {
+	return &Client{}
+}
@@ -0,0 +1,20 @@
+package store_test
+
+import "example.com/app/store"
+
+type recordingNotifier struct {
+	entries []store.Entry
+}
+
+func (r *recordingNotifier) Notify(entry store.Entry) {
+	r.entries = append(r.entries, entry)
+}
+
+// wrongCache takes an unqualified Entry, of no package here.
+type wrongCache struct{}
+
+func (wrongCache) Lookup(key string) (Entry, bool) {
+	return Entry{}, false
+}
+
+func (wrongCache) Evict(key string) {}
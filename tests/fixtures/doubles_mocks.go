@@ -0,0 +1,16 @@
+package mocks
+
+// Clock is a mock clock.
+type Clock struct {
+	Time int64
+}
+
+// Now returns the mocked time.
+func (c Clock) Now() int64 {
+	return c.Time
+}
+
+// Ticker is an interface of the mocks package, not checked itself.
+type Ticker interface {
+	Tick()
+}
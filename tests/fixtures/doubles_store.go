@@ -0,0 +1,54 @@
+package store
+
+// Entry is a stored entry.
+type Entry struct {
+	Key   string
+	Value string
+}
+
+// Store stores entries.
+type Store interface {
+	Get(key string) (Entry, bool)
+	Put(entry Entry) error
+}
+
+// Cache caches entries.
+type Cache interface {
+	Lookup(key string) (Entry, bool)
+	Evict(key string)
+}
+
+// Clock tells the time.
+type Clock interface {
+	Now() int64
+}
+
+// Notifier notifies subscribers of a change.
+type Notifier interface {
+	Notify(entry Entry)
+}
+
+// Any is empty and has no double to check.
+type Any interface{}
+
+// reader is unexported and left out.
+type reader interface {
+	Read(key string) Entry
+}
+
+// Memory is the production store.
+type Memory struct {
+	entries map[string]Entry
+}
+
+// Get gets an entry.
+func (m *Memory) Get(key string) (Entry, bool) {
+	entry, ok := m.entries[key]
+	return entry, ok
+}
+
+// Put puts an entry.
+func (m *Memory) Put(entry Entry) error {
+	m.entries[entry.Key] = entry
+	return nil
+}
@@ -0,0 +1,24 @@
+package store
+
+import "testing"
+
+type fakeStore struct {
+	entries map[string]Entry
+}
+
+func (f *fakeStore) Get(key string) (Entry, bool) {
+	entry, ok := f.entries[key]
+	return entry, ok
+}
+
+func (f *fakeStore) Put(entry Entry) error {
+	f.entries[entry.Key] = entry
+	return nil
+}
+
+func TestFakeStore(t *testing.T) {
+	var s Store = &fakeStore{entries: map[string]Entry{}}
+	if err := s.Put(Entry{Key: "a"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"errors"
+	"strings"
+)
+
+var errInvalidKey = errors.New("invalid key")
+
+// FileStore stores values in files.
+type FileStore struct {
+	dir    string
+	hits   int
+	misses int
+}
+
+// MemoryStore stores values in memory.
+type MemoryStore struct {
+	values map[string]string
+	hits   int
+	misses int
+}
+
+// Validate checks a key.
+func (f *FileStore) Validate(key string) error {
+	// Keys are path segments
+	if key == "" || strings.ContainsAny(key, "/\\") {
+		return errInvalidKey
+	}
+	return nil
+}
+
+// Validate checks a key.
+func (m *MemoryStore) Validate(name string) error {
+	if name == "" || strings.ContainsAny(name, "/\\") {
+		return errInvalidKey
+	}
+
+	return nil
+}
+
+// HitRate returns the share of lookups that found a value.
+func (f *FileStore) HitRate() float64 {
+	total := f.hits + f.misses
+	if total == 0 {
+		return 0
+	}
+	return float64(f.hits) / float64(total)
+}
+
+// HitRate returns the share of lookups that found a value.
+func (m *MemoryStore) HitRate() float64 {
+	lookups := m.hits + m.misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(m.hits) / float64(lookups)
+}
+
+// Reset clears the statistics.
+func (f *FileStore) Reset() {
+	f.hits = 0
+	f.misses = 0
+	f.dir = ""
+}
+
+// Reset clears the statistics.
+func (m *MemoryStore) Reset() {
+	m.hits = 0
+	m.misses = 0
+	m.values = nil
+}
+
+// Name is trivial and duplicated on purpose.
+func (f *FileStore) Name() string {
+	return "store"
+}
+
+// Name is trivial and duplicated on purpose.
+func (m *MemoryStore) Name() string {
+	return "store"
+}
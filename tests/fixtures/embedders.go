@@ -0,0 +1,21 @@
+package directory
+
+import "example.com/app/staff"
+
+// Entry is a directory entry for an employee.
+type Entry struct {
+	*staff.Employee
+	Room string
+}
+
+// Lister names the entries of a directory.
+type Lister interface {
+	staff.Named
+	List() []string
+}
+
+// record is an internal entry of a user.
+type record struct {
+	staff.User
+	seen bool
+}
@@ -0,0 +1,51 @@
+package calc
+
+import "errors"
+
+// ErrDivByZero is returned when dividing by zero.
+var ErrDivByZero = errors.New("division by zero")
+
+// Solver solves equations.
+type Solver interface {
+	// Solve returns the error last.
+	Solve(eq string) (float64, error)
+	// Check returns the error first.
+	Check(eq string) (error, bool)
+}
+
+// Handler returns its error first.
+type Handler func(input string) (error, string)
+
+// Divide returns its error last.
+func Divide(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, ErrDivByZero
+	}
+	return a / b, nil
+}
+
+// Validate only returns an error.
+func Validate(a float64) error {
+	return nil
+}
+
+// Parse returns its error before the value.
+func Parse(s string) (err error, value float64) {
+	return nil, 0
+}
+
+// Merge returns two errors.
+func Merge(a, b float64) (float64, error, error) {
+	return a + b, nil, nil
+}
+
+// Sum returns no error.
+func Sum(a, b float64) float64 {
+	return a + b
+}
+
+type state struct{}
+
+func (s *state) reset() (error, int) {
+	return nil, 0
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrEmpty is returned for empty files.
+var ErrEmpty = errors.New("config: empty file")
+
+// Load reads and parses a config file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+	cfg, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes a config file.
+func Save(path string, cfg *Config) error {
+	data, err := cfg.Marshal()
+	if err != nil {
+		return err
+	}
+	if werr := os.WriteFile(path, data, 0o644); werr != nil {
+		return fmt.Errorf("save %s: %v", path, werr)
+	}
+	return nil
+}
+
+// Config holds settings.
+type Config struct {
+	Name string
+}
+
+// Marshal encodes the config.
+func (c *Config) Marshal() ([]byte, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("config: missing name")
+	}
+	encode := func() error {
+		return errors.New("not reached")
+	}
+	if err := encode(); err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+	return []byte(c.Name), nil
+}
+
+func parse(data []byte) (*Config, error) {
+	return &Config{Name: string(data)}, nil
+}
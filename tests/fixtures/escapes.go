@@ -0,0 +1,84 @@
+package config
+
+// Config holds settings.
+type Config struct {
+	Name  string
+	Limit int
+	inner Settings
+}
+
+// Settings are nested settings.
+type Settings struct {
+	Debug bool
+}
+
+var defaults Config
+
+// NewConfig allocates a config.
+func NewConfig(name string) *Config {
+	return &Config{
+		Name:  name,
+		Limit: 10,
+	}
+}
+
+// Load builds a config in a local variable.
+func Load(name string) *Config {
+	cfg := Config{Name: name}
+	cfg.Limit = 5
+	return &cfg
+}
+
+// Empty declares a zero config.
+func Empty() (*Config, error) {
+	var c Config
+	return &c, nil
+}
+
+// Find looks up a config by name.
+func Find(configs []Config, name string) *Config {
+	for _, c := range configs {
+		if c.Name == name {
+			return &c
+		}
+	}
+	return nil
+}
+
+// FindIndex returns the element itself.
+func FindIndex(configs []Config, name string) *Config {
+	for i := range configs {
+		if configs[i].Name == name {
+			return &configs[i]
+		}
+	}
+	return nil
+}
+
+// Copy returns a pointer to its argument.
+func Copy(c Config) *Config {
+	return &c
+}
+
+// Default returns the package defaults.
+func Default() *Config {
+	return &defaults
+}
+
+// Inner returns the nested settings of a copy.
+func (c Config) Inner() *Settings {
+	return &c.inner
+}
+
+// Shared returns the nested settings of the receiver.
+func (c *Config) Shared() *Settings {
+	return &c.inner
+}
+
+// Later returns a generator of configs.
+func Later() func() *Config {
+	return func() *Config {
+		c := Config{}
+		return &c
+	}
+}
@@ -0,0 +1,25 @@
+package server
+
+import "time"
+
+// Config configures a server.
+type Config struct {
+	// Network
+
+	// Host is the host to listen on.
+	Host string
+	Port int
+
+	// Timeouts for each connection.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// Logger receives the server logs.
+	Logger func(string)
+
+	limits struct {
+		// Limits
+
+		max int
+	}
+}
@@ -0,0 +1,34 @@
+package client
+
+import "time"
+
+// Client talks to a server.
+type Client struct {
+	Addr    string
+	Retries int
+	Timeout time.Duration
+	Debug   bool
+	conn    *conn
+}
+
+// Options configures a request.
+type Options struct {
+	Verbose bool
+	Limit   int
+}
+
+type conn struct{}
+
+// NewClient returns a client for addr.
+func NewClient(addr string) *Client {
+	c := &Client{Addr: addr, Retries: 3}
+	c.Timeout = 30 * time.Second
+	return c
+}
+
+// Do sends a request.
+func (c *Client) Do(opts Options) error {
+	c.Debug = opts.Verbose
+	c.Retries--
+	return nil
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"time"
+
+	"example.com/app/client"
+)
+
+func main() {
+	c := client.NewClient("localhost:8080")
+	c.Timeout = 5 * time.Second
+	c.Debug = true
+	opts := client.Options{Verbose: true}
+	c.Do(opts)
+}
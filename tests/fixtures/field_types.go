@@ -0,0 +1,42 @@
+package store
+
+import "time"
+
+// UserID identifies a user.
+type UserID int64
+
+// Timestamp is an alias of time.Time.
+type Timestamp = time.Time
+
+// Base holds the common columns.
+type Base struct {
+	CreatedAt Timestamp
+	UpdatedAt time.Time
+}
+
+// User is a stored user.
+type User struct {
+	Base
+	ID    UserID
+	Name  string
+	Email string
+	Tags  []string
+}
+
+// Order is a stored order.
+type Order struct {
+	ID     int
+	UserID UserID
+	Total  int64
+	Items  []UserID
+	Meta   struct {
+		Source string
+		Notes  string
+	}
+}
+
+// Invoice is a stored invoice.
+type Invoice struct {
+	ID, Number int64
+	Order      *Order
+}
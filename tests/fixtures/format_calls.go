@@ -0,0 +1,33 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	stdlog "log"
+	"os"
+)
+
+// Summary prints the totals of a report.
+func Summary(name string, total int, ratio float64) string {
+	fmt.Printf("%s: %d items (%.2f%%)\n", name, total, ratio)
+	fmt.Fprintf(os.Stderr, "%-10s|%*d\n", name, 8, total)
+	fmt.Printf("%[1]s and %[1]q\n", name)
+	return fmt.Sprintf("%s has %d items", name)
+}
+
+// Fail builds the error of a failed report.
+func Fail(name string, code int) error {
+	if code == 0 {
+		return errors.New("no code")
+	}
+	stdlog.Printf("failing %s", name, code)
+	fmt.Println("100%")
+	return fmt.Errorf("report %s failed with 50%", name)
+}
+
+// Log prints a message with its arguments.
+func Log(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+	fmt.Printf("%v %v\n", args...)
+	fmt.Printf("done\n", len(args))
+}
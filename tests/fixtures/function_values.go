@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Route maps a path to its handler.
+type Route struct {
+	Path    string
+	Handler func(w http.ResponseWriter, r *http.Request)
+}
+
+// Server dispatches requests to handlers.
+type Server struct {
+	routes  []Route
+	onError func(error)
+}
+
+var routes = map[string]func(w http.ResponseWriter, r *http.Request){
+	"/health": handleHealth,
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(normalize("index")))
+}
+
+func logError(err error) {}
+
+func normalize(s string) string {
+	return strings.ToLower(s)
+}
+
+func byPath(routes []Route) func(i, j int) bool {
+	return func(i, j int) bool { return routes[i].Path < routes[j].Path }
+}
+
+func lessRoute(a, b Route) bool {
+	return a.Path < b.Path
+}
+
+func unused() {}
+
+func pick() func(string) string {
+	return normalize
+}
+
+// NewServer creates a server with the default routes.
+func NewServer() *Server {
+	s := &Server{
+		routes: []Route{{Path: "/", Handler: handleIndex}},
+	}
+	s.onError = logError
+	http.HandleFunc("/health", handleHealth)
+	return s
+}
+
+// Handle dispatches a request.
+func (s *Server) Handle(w http.ResponseWriter, r *http.Request) {
+	if s.onError == nil {
+		s.onError = logError
+	}
+	sort.Slice(s.routes, byPath(s.routes))
+	transform := normalize
+	_ = transform(r.URL.Path)
+	_ = lessRoute(s.routes[0], s.routes[0])
+	serve(s.Handle)
+}
+
+func serve(handler func(w http.ResponseWriter, r *http.Request)) {}
+
+func shadowed() {
+	normalize := func(s string) string { return s }
+	_ = normalize
+}
@@ -0,0 +1,28 @@
+//go:build ignore
+
+// This program generates tables.go. Run it with go generate.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var sizes = []int{8, 16, 32}
+
+func main() {
+	f, err := os.Create("tables.go")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	fmt.Fprintln(f, "package tables")
+	for _, size := range sizes {
+		fmt.Fprintln(f, render(size))
+	}
+}
+
+func render(size int) string {
+	return fmt.Sprintf("const Size%d = %d", size, size)
+}
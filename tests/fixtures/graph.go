@@ -0,0 +1,53 @@
+package shapes
+
+import (
+	"fmt"
+
+	"example.com/app/geom"
+)
+
+// Shape is anything with an area.
+type Shape interface {
+	Area() float64
+}
+
+// Named has a name.
+type Named interface {
+	Shape
+	Name() string
+}
+
+// Base holds shared fields.
+type Base struct {
+	label string
+}
+
+// Name returns the label.
+func (b Base) Name() string {
+	return b.label
+}
+
+// Square is a square shape.
+type Square struct {
+	Base
+	side float64
+}
+
+// Area computes the area.
+func (s *Square) Area() float64 {
+	return geom.Mul(s.side, s.side)
+}
+
+// Describe prints the shape twice.
+func Describe(s *Square) string {
+	area := s.Area()
+	text := format(area)
+	return text + format(s.Area())
+}
+
+func format(v float64) string {
+	if v > 0 {
+		return fmt.Sprintf("%.2f", v)
+	}
+	return "0"
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"context"
+	stdctx "context"
+	"net/http"
+)
+
+// Request is a request of the pipeline.
+type Request struct{}
+
+// Index serves the index page.
+func Index(w http.ResponseWriter, r *http.Request) {}
+
+// Health serves the health check.
+func Health(rw http.ResponseWriter, req *http.Request) {}
+
+// Server serves pages.
+type Server struct{}
+
+// ServeHTTP serves any page.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+// Migrate is a pipeline step.
+func Migrate(ctx context.Context) error {
+	return nil
+}
+
+// Seed is a pipeline step importing context under another name.
+func Seed(c stdctx.Context) error {
+	return nil
+}
+
+// Check returns no error.
+func Check(ctx context.Context) bool {
+	return true
+}
+
+// Handle handles a pipeline request.
+func Handle(ctx context.Context, req *Request) error {
+	return nil
+}
+
+// Register registers a handler.
+func Register(path string, handler http.HandlerFunc) {}
+
+// Log logs values.
+func Log(format string, args ...interface{}) {}
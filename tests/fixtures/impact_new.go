@@ -0,0 +1,19 @@
+package store
+
+// Store keeps items in memory.
+type Store struct {
+	items map[string]string
+}
+
+// Get returns the item for key and whether it exists.
+func (s *Store) Get(key string) (string, bool) {
+	value, ok := s.items[key]
+	return value, ok
+}
+
+// Set stores an item.
+func (s *Store) Set(key, value string) {
+	s.items[key] = value
+}
+
+const version = 1
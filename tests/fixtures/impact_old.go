@@ -0,0 +1,18 @@
+package store
+
+// Store keeps items in memory.
+type Store struct {
+	items map[string]string
+}
+
+// Get returns the item for key.
+func (s *Store) Get(key string) string {
+	return s.items[key]
+}
+
+// Reset removes every item.
+func (s *Store) Reset() {
+	s.items = map[string]string{}
+}
+
+const version = 1
@@ -0,0 +1,18 @@
+package cycle
+
+var a = b + 1
+
+var b = f()
+
+func f() int {
+	return a
+}
+
+var c = g(3)
+
+func g(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return g(n - 1)
+}
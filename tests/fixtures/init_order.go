@@ -0,0 +1,35 @@
+package config
+
+import "os"
+
+var (
+	// Path depends on Dir, which is declared after it.
+	Path = Dir + "/config.json"
+	Dir  = defaultDir()
+	home = os.Getenv("HOME")
+)
+
+var Loaded, Err = load()
+
+var settings = map[string]string{"path": Path}
+
+var greeting = lookup("hello")
+
+var verbose bool
+
+func defaultDir() string {
+	return home + "/.app"
+}
+
+func load() (bool, error) {
+	_, err := os.Stat(Path)
+	return err == nil, err
+}
+
+func lookup(home string) string {
+	return home
+}
+
+func init() {
+	settings["loaded"] = "yes"
+}
@@ -0,0 +1,68 @@
+package mathx
+
+import "sync"
+
+// Add is small enough to inline.
+func Add(a, b int) int {
+	return a + b
+}
+
+// Point is a point on a plane.
+type Point struct {
+	X, Y int
+}
+
+// Norm1 is a small method.
+func (p Point) Norm1() int {
+	return abs(p.X) + abs(p.Y)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RecursiveFactorial calls itself.
+func RecursiveFactorial(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * RecursiveFactorial(n-1)
+}
+
+// Sum loops over its values.
+func Sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// Locked defers the unlock.
+func Locked(mu *sync.Mutex, f func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	f()
+}
+
+// Adder returns a closure.
+func Adder(n int) func(int) int {
+	return func(m int) int { return n + m }
+}
+
+// Pinned is never inlined.
+//
+//go:noinline
+func Pinned() int {
+	return 42
+}
+
+// Polynomial is over the budget.
+func Polynomial(x int) int {
+	return 1 + 2*x + 3*x*x + 4*x*x*x + 5*x*x*x*x + 6*x*x*x*x*x +
+		7*x*x*x*x*x*x + 8*x*x*x*x*x*x*x + 9*x*x*x*x*x*x*x*x +
+		10*x*x*x*x*x*x*x*x*x
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Getter reads values.
+type Getter interface {
+	Get(key string) (string, error)
+}
+
+// Repository reads and writes values.
+type Repository interface {
+	Getter
+	Put(key, value string) error
+}
+
+// Store is an in-memory repository.
+type Store struct {
+	values map[string]string
+	Name   string
+}
+
+// NewStore creates an empty store.
+func NewStore() Repository {
+	return &Store{values: map[string]string{}}
+}
+
+// Open opens a named store, returning it as a Repository.
+func Open(name string) (Repository, error) {
+	if name == "" {
+		return nil, errors.New("store: no name")
+	}
+	return &Store{
+		values: map[string]string{},
+		Name:   name,
+	}, nil
+}
+
+// OpenAny opens a store of either kind.
+func OpenAny(cached bool) Repository {
+	if cached {
+		return &cache{}
+	}
+	return &Store{}
+}
+
+// NewHidden keeps its implementation private.
+func NewHidden() Getter {
+	return &cache{}
+}
+
+// Get returns the value of a key.
+func (s *Store) Get(key string) (string, error) {
+	v, ok := s.values[key]
+	if !ok {
+		return "", fmt.Errorf("store: %q not found", key)
+	}
+	return v, nil
+}
+
+// Put sets the value of a key.
+func (s *Store) Put(key, value string) error {
+	s.values[key] = value
+	return nil
+}
+
+type cache struct{}
+
+func (c *cache) Get(key string) (string, error) { return "", nil }
+func (c *cache) Put(key, value string) error    { return nil }
+
+// Lookup only calls Get on the store.
+func Lookup(s *Store, key string) string {
+	v, _ := s.Get(key)
+	return v
+}
+
+// Copy reads from one store and writes to another.
+func Copy(dst *Store, src *Store, key string) error {
+	v, err := src.Get(key)
+	if err != nil {
+		return err
+	}
+	return dst.Put(key, v)
+}
+
+// Describe reads a field, which needs the concrete type.
+func Describe(s *Store) string {
+	v, _ := s.Get("description")
+	return s.Name + ": " + v
+}
+
+// Forward passes the store on.
+func Forward(s *Store) string {
+	return Lookup(s, "key")
+}
+
+// Close only calls a method no interface declares.
+func Close(s *Store) {
+	s.Reset()
+}
+
+// Reset empties the store.
+func (s *Store) Reset() {
+	s.values = map[string]string{}
+}
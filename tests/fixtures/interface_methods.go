@@ -0,0 +1,83 @@
+package store
+
+// Store keeps values.
+type Store interface {
+	Get(key string) (string, bool)
+	Set(key, value string)
+	Delete(key string)
+	Flush() error
+}
+
+// Reader reads values.
+type Reader interface {
+	Get(key string) (string, bool)
+	Keys() []string
+}
+
+// ReadCloser is a Reader to close.
+type ReadCloser interface {
+	Reader
+	Close() error
+}
+
+type cache interface {
+	lookup(key string) string
+	evict()
+	reset()
+	String() string
+}
+
+type logger interface {
+	log(msg string)
+	level() int
+}
+
+// Service keeps values in a store.
+type Service struct {
+	store Store
+}
+
+type app struct {
+	logger
+}
+
+// Put sets a value through a field.
+func (s *Service) Put(key, value string) {
+	s.store.Set(key, value)
+}
+
+// Lookup gets a value through a parameter.
+func Lookup(st Store, key string) string {
+	v, _ := st.Get(key)
+	return v
+}
+
+// Drop deletes a key from stores.
+func Drop(stores []Store, key string) {
+	for _, st := range stores {
+		st.Delete(key)
+	}
+}
+
+// Open opens a reader.
+func Open() ReadCloser {
+	return nil
+}
+
+// Keys lists the keys of a reader.
+func Keys() []string {
+	rc := Open()
+	defer rc.Close()
+	return rc.Keys()
+}
+
+func warm(v any, key string) string {
+	c := v.(cache)
+	return c.lookup(key)
+}
+
+func resetAll[C cache](items []C) {
+	for _, item := range items {
+		item.reset()
+	}
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"io"
+
+	"example.com/codec"
+)
+
+// Getter gets a value.
+type Getter interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// Blob is a readable, closable value.
+type Blob interface {
+	io.ReadCloser
+}
+
+// Encoder embeds an interface of a package outside the project.
+type Encoder interface {
+	codec.Encoder
+}
+
+// Any accepts every value.
+type Any interface{}
+
+// Number is a type constraint.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Store does everything.
+type Store interface {
+	Getter
+	Put(key string, value []byte) error
+	Delete(key string) error
+	List(prefix string) ([]string, error)
+	Watch(prefix string) <-chan string
+	Close() error
+	Stats() map[string]int
+	Compact() error
+	Backup(w io.Writer) error
+	Restore(r io.Reader) error
+	Lock(key string) error
+	Unlock(key string) error
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"io"
+
+	"example.com/app/service"
+	"example.com/app/store"
+)
+
+// Closer closes resources.
+type Closer interface {
+	Close() error
+}
+
+// Shutdown closes the store.
+func Shutdown(st *store.Store, svc *service.Service) {
+	st.Close()
+	_ = io.EOF
+}
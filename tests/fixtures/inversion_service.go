@@ -0,0 +1,39 @@
+package service
+
+import "example.com/app/store"
+
+// Getter gets users.
+type Getter interface {
+	Get(id string) (string, bool)
+}
+
+// Service serves users.
+type Service struct {
+	users *store.Store
+	cache *store.Cache
+	raw   *store.Store
+}
+
+// Lookup finds a user through the service store.
+func (s *Service) Lookup(id string) string {
+	s.cache.Flush()
+	user, _ := s.users.Get(id)
+	return user + s.raw.Path
+}
+
+// Find only gets users from the store.
+func Find(st *store.Store, id string) string {
+	user, _ := st.Get(id)
+	return user
+}
+
+// Save stores and gets users.
+func Save(st *store.Store, id string) {
+	st.Put(id, id)
+	st.Get(id)
+}
+
+// Where reads a field of the store.
+func Where(st *store.Store) string {
+	return st.Path
+}
@@ -0,0 +1,29 @@
+package store
+
+// Store keeps users in memory.
+type Store struct {
+	Path  string
+	users map[string]string
+}
+
+// Get returns a user.
+func (s *Store) Get(id string) (string, bool) {
+	user, ok := s.users[id]
+	return user, ok
+}
+
+// Put stores a user.
+func (s *Store) Put(id, user string) {
+	s.users[id] = user
+}
+
+// Close releases the store.
+func (s *Store) Close() error {
+	return nil
+}
+
+// Cache is a store without an interface.
+type Cache struct{}
+
+// Flush empties the cache.
+func (c *Cache) Flush() {}
@@ -0,0 +1,93 @@
+package server
+
+import "errors"
+
+// State is the lifecycle state of a server.
+type State int
+
+const (
+	Idle State = iota
+	Running
+	Paused
+	Stopped
+	Failed
+)
+
+var ErrState = errors.New("invalid state")
+
+// Server is a server with a start/pause/stop lifecycle.
+type Server struct {
+	name  string
+	state State
+}
+
+// NewServer creates an idle server.
+func NewServer(name string) *Server {
+	return &Server{name: name, state: Idle}
+}
+
+// Start starts an idle server.
+func (s *Server) Start() error {
+	if s.state != Idle {
+		return ErrState
+	}
+	s.state = Running
+	return nil
+}
+
+// Pause pauses a running server.
+func (s *Server) Pause() {
+	if s.state == Running {
+		s.state = Paused
+	}
+}
+
+// Resume resumes a paused server.
+func (s *Server) Resume() error {
+	switch s.state {
+	case Paused:
+		s.state = Running
+	default:
+		return ErrState
+	}
+	return nil
+}
+
+// Stop stops the server from any state but stopped.
+func (s *Server) Stop() {
+	if s.state == Stopped {
+		return
+	}
+	s.state = Stopped
+}
+
+func (s *Server) Name() string {
+	return s.name
+}
+
+// Job has an untyped status field.
+type Job struct {
+	status string
+}
+
+const (
+	pending = "pending"
+	done    = "done"
+)
+
+func newJob() *Job {
+	return &Job{status: pending}
+}
+
+func (j *Job) Finish() {
+	j.status = done
+}
+
+// Counter has no state field.
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Inc() {
+	c.n = c.n + 1
+}
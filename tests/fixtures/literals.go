@@ -0,0 +1,46 @@
+package literals
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultEndpoint is a named constant and is fine to hardcode.
+const DefaultEndpoint = "https://api.example.com/v1"
+
+const (
+	maxRetries = 3
+	backoff    = 250 * time.Millisecond
+	mask       = 0xFF
+)
+
+var userAgent = "literals/1.0"
+
+// Config holds client settings.
+type Config struct {
+	Endpoint string `json:"endpoint"`
+	Timeout  int    `json:"timeout,omitempty"`
+	buffer   [64]byte
+}
+
+// Fetch downloads a resource with hardcoded values.
+func Fetch(path string) (*http.Response, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	url := "https://cdn.example.com/" + path
+	return client.Get(url)
+}
+
+// Scale applies magic numbers.
+func Scale(x float64) float64 {
+	const ratio = 1.5
+	if x < -1 {
+		return 0
+	}
+	return x*ratio + 3.25e2
+}
+
+func (c *Config) describe() string {
+	sep := ','
+	return fmt.Sprintf("%s%c\t%d", c.Endpoint, sep, c.Timeout-1)
+}
@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Job is a unit of work.
+type Job struct {
+	ID   int
+	Name string
+}
+
+// RunAll runs every job in its own goroutine.  Each goroutine captures
+// the range variable job, shared by all iterations before Go 1.22.
+func RunAll(jobs []Job) {
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fmt.Println(job.Name)
+		}()
+	}
+	wg.Wait()
+}
+
+// Count captures the index of a three-clause loop.
+func Count(n int) {
+	for i := 0; i < n; i++ {
+		go func() {
+			fmt.Println(i)
+		}()
+	}
+}
+
+// Fetch captures the key and value of a map range in an errgroup.
+func Fetch(urls map[string]string) error {
+	var g errgroup.Group
+	for name, url := range urls {
+		g.Go(func() error {
+			return get(name, url)
+		})
+	}
+	return g.Wait()
+}
+
+// PassArgument passes the range variable to the function literal.
+func PassArgument(jobs []Job) {
+	for _, job := range jobs {
+		go func(job Job) {
+			fmt.Println(job.Name)
+		}(job)
+	}
+}
+
+// CopyFirst copies the range variable before launching the goroutine.
+func CopyFirst(jobs []Job) {
+	for _, job := range jobs {
+		job := job
+		go func() {
+			fmt.Println(job.ID)
+		}()
+	}
+}
+
+// Direct launches a named function call, which evaluates its arguments
+// immediately.
+func Direct(jobs []Job) {
+	for _, job := range jobs {
+		go process(job)
+	}
+}
+
+// Outer captures the outer loop variable from within an inner loop.
+func Outer(groups [][]Job) {
+	for g, jobs := range groups {
+		for _, job := range jobs {
+			go func(j Job) {
+				fmt.Println(g, j.ID)
+			}(job)
+		}
+	}
+}
+
+func get(name, url string) error {
+	return nil
+}
+
+func process(job Job) {}
@@ -0,0 +1,14 @@
+//go:build go1.22
+
+package worker
+
+import "fmt"
+
+// Modern relies on the per-iteration loop variables of Go 1.22.
+func Modern(jobs []Job) {
+	for _, job := range jobs {
+		go func() {
+			fmt.Println(job.Name)
+		}()
+	}
+}
@@ -0,0 +1,66 @@
+package retry
+
+import "time"
+
+// MaxAttempts is the number of attempts before giving up.
+const MaxAttempts = 5
+
+const (
+	bufferSize = 4096
+	scale      = 100 * 2
+)
+
+var defaultDelay = 250 * time.Millisecond
+
+// Retry calls fn until it succeeds.
+func Retry(fn func() error) error {
+	var err error
+	for i := 0; i < 5; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		time.Sleep(time.Duration(i+1) * 250 * time.Millisecond)
+	}
+	return err
+}
+
+// Backoff returns the delay before an attempt.
+func Backoff(attempt int) time.Duration {
+	if attempt > 5 {
+		attempt = 5
+	}
+	return time.Duration(attempt*250) * time.Millisecond
+}
+
+// Buffer allocates a read buffer.
+func Buffer() []byte {
+	return make([]byte, 0x1000)
+}
+
+// Window returns the size of the sliding window.
+func Window(n int) int {
+	if n > 4096 {
+		return 4096
+	}
+	return n * 2
+}
+
+// Percent formats a ratio.
+func Percent(ratio float64) float64 {
+	return ratio * 100
+}
+
+// Offset returns the previous index.
+func Offset(i int) int {
+	return i - 1
+}
+
+// Ratio parses a percentage.
+func Ratio(percent float64) float64 {
+	return percent / 100
+}
+
+// Timeout returns the time to wait for all the attempts.
+func Timeout() time.Duration {
+	return MaxAttempts * 250 * time.Millisecond
+}
@@ -0,0 +1,65 @@
+package shapes
+
+import "fmt"
+
+// Point is a point of the plane.
+type Point struct {
+	X, Y int
+}
+
+// String formats the point.
+func (p Point) String() string {
+	return fmt.Sprintf("(%d, %d)", p.X, p.Y)
+}
+
+// Close compares two points.
+func (p Point) Close(other Point, delta int) bool {
+	return abs(p.X-other.X) <= delta && abs(p.Y-other.Y) <= delta
+}
+
+// Polygon is a closed path of points.
+type Polygon struct {
+	Points []Point
+}
+
+// String formats the polygon.
+func (g *Polygon) String() string {
+	return fmt.Sprint(g.Points)
+}
+
+// Close appends the first point to the end of the path.
+func (g *Polygon) Close() error {
+	g.Points = append(g.Points, g.Points[0])
+	return nil
+}
+
+// Color is a named color.
+type Color int
+
+// String formats the color.
+func (c Color) String() (name string) {
+	return [...]string{"red", "green", "blue"}[c]
+}
+
+// Grid is a grid of cells.
+type Grid[T any] struct {
+	Cells [][]T
+}
+
+// String formats the grid, with a given indentation.
+func (g *Grid[T]) String(indent int) string {
+	return fmt.Sprint(g.Cells)
+}
+
+// Close releases the grid.
+func (g *Grid[T]) Close() error {
+	g.Cells = nil
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,39 @@
+package counter
+
+// Incrementer is satisfied by types with an Increment method.
+type Incrementer interface {
+	Increment()
+}
+
+// Valuer is satisfied by types with a Value method.
+type Valuer interface {
+	Value() int
+}
+
+// Counter has a value receiver method and a pointer receiver method.
+// Counter satisfies Valuer; only *Counter satisfies Incrementer.
+type Counter struct {
+	n int
+}
+
+// Value returns the count.
+func (c Counter) Value() int {
+	return c.n
+}
+
+// Increment adds one to the count.
+func (c *Counter) Increment() {
+	c.n++
+}
+
+// Tracked embeds a *Counter: its value method set has Increment, so
+// Tracked values satisfy Incrementer.
+type Tracked struct {
+	*Counter
+	Name string
+}
+
+// Wrapped embeds a Counter: only *Wrapped has Increment.
+type Wrapped struct {
+	Counter
+}
@@ -0,0 +1,62 @@
+package geo
+
+import "math"
+
+// Shape is a closed figure.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+// Solid is a shape with a volume.
+type Solid interface {
+	Shape
+	Volume() float64
+}
+
+// Circle implements Shape.
+type Circle struct {
+	R float64
+}
+
+// Area returns the area of the circle.
+func (c Circle) Area() float64 { return math.Pi * c.R * c.R }
+
+// Perimeter returns the circumference of the circle.
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.R }
+
+// Triangle is one method away from implementing Shape.
+type Triangle struct {
+	A, B, C float64
+}
+
+// Area returns the area of the triangle.
+func (t *Triangle) Area() float64 {
+	s := (t.A + t.B + t.C) / 2
+	return math.Sqrt(s * (s - t.A) * (s - t.B) * (s - t.C))
+}
+
+// Square declares Perimeter with the wrong result type.
+type Square struct {
+	Side int
+}
+
+// Area returns the area of the square.
+func (s Square) Area() float64 { return float64(s.Side * s.Side) }
+
+// Perimeter returns the perimeter of the square.
+func (s Square) Perimeter() int { return 4 * s.Side }
+
+// Cube gets Area and Perimeter from its embedded Square.
+type Cube struct {
+	Square
+}
+
+// Volume returns the volume of the cube.
+func (c Cube) Volume() float64 { return float64(c.Side * c.Side * c.Side) }
+
+// Label only shares a name.
+type Label string
+
+// Area is unrelated to Shape.Area.
+func (l Label) Area() string { return string(l) }
@@ -0,0 +1,73 @@
+package nesting
+
+import (
+	"errors"
+	"testing"
+)
+
+// Divide returns early when the divisor is zero.
+func Divide(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+// Validate uses a series of guard clauses.
+func Validate(name string, age int) error {
+	if name == "" {
+		return errors.New("name required")
+	}
+	if age < 0 {
+		return errors.New("age must be positive")
+	}
+	for _, r := range name {
+		if r == ' ' {
+			return errors.New("name has spaces")
+		}
+	}
+	return nil
+}
+
+// Process nests every condition inside the previous one.
+func Process(items []int, limit int) (int, error) {
+	total := 0
+	if items != nil {
+		if len(items) > 0 {
+			for _, item := range items {
+				if item > 0 {
+					if total+item <= limit {
+						total += item
+					}
+				}
+			}
+		}
+	}
+	return total, nil
+}
+
+// Classify returns in the if branch but still uses an else.
+func Classify(n int) string {
+	if n < 0 {
+		return "negative"
+	} else {
+		return "non-negative"
+	}
+}
+
+// Sum has no branching at all.
+func Sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestSum(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		if Sum([]int{1, 2}) != 3 {
+			t.Fatal("bad sum")
+		}
+	})
+}
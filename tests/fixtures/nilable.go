@@ -0,0 +1,39 @@
+package lookup
+
+import "io"
+
+// Node is a tree node.
+type Node struct {
+	Children []*Node
+}
+
+// Finder finds nodes.
+type Finder interface {
+	Find(name string) (*Node, bool)
+}
+
+// Index maps names to nodes.
+type Index map[string]*Node
+
+// Visitor is called for each node.
+type Visitor func(n *Node) error
+
+// Point is a value type.
+type Point struct{ X, Y int }
+
+// Divide divides a by b.
+func Divide(a, b float64) (float64, error) {
+	return a / b, nil
+}
+
+func Pointer() *Node                 { return nil }
+func Slice() []Node                  { return nil }
+func Map() map[string]int            { return nil }
+func Channel() <-chan int            { return nil }
+func Func() func() int               { return nil }
+func Interface() interface{ Close() } { return nil }
+func Any() any                       { return nil }
+func Named() (Finder, Index, Visitor) { return nil, nil, nil }
+func Value() (p Point, n int, s string, ok bool) { return }
+func Array() [4]byte                 { return [4]byte{} }
+func Reader() io.Reader              { return nil }
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"time"
+)
+
+// Config configures a server.
+type Config struct {
+	// Addr is the address to listen on.
+	Addr string
+	// Timeout bounds each request.
+	Timeout time.Duration
+	MaxConns int // zero means unlimited
+}
+
+// Merge combines two configurations.
+func (c Config) Merge(other Config) Config {
+	return c
+}
+
+// DialOptions tunes a connection.
+type DialOptions struct {
+	Retries int
+}
+
+// Report is a plain data struct with many fields.
+type Report struct {
+	Title, Author string
+	Pages         int
+	Draft         bool
+}
+
+// Point is a small value type.
+type Point struct {
+	X, Y int
+}
+
+// User has behavior.
+type User struct {
+	Name, Email, Role, Team string
+}
+
+// Greet greets the user.
+func (u User) Greet() string {
+	return "hello " + u.Name
+}
+
+// Option configures a server functionally.
+type Option func(*Config)
+
+// NewServer creates a server.
+func NewServer(name string, cfg Config) *Server {
+	return &Server{}
+}
+
+// Server serves requests.
+type Server struct{}
+
+// Dial connects with its options last.
+func (s *Server) Dial(ctx context.Context, opts *DialOptions) error {
+	return nil
+}
+
+// Publish publishes a report.
+func Publish(r Report) {}
+
+// Move moves to a point.
+func Move(p Point) {}
+
+// Save saves a user.
+func Save(u User) {}
+
+// Start takes functional options.
+func Start(opts ...Option) {}
+
+// Configure takes the config first.
+func Configure(cfg Config, name string) {}
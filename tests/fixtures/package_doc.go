@@ -0,0 +1,9 @@
+// Copyright 2024 The Mux Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license.
+
+//go:build !js
+
+// Package mux implements a request router.
+//
+// Routes are matched in the order they are registered.
+package mux
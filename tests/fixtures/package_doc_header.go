@@ -0,0 +1,9 @@
+/*
+ * Router internals.
+ */
+
+// Package router implements the routing tree.
+package mux
+
+// Route is a registered route.
+type Route struct{}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"fmt"
+	"log"
+)
+
+// Node is a parsed node.
+type Node interface {
+	Kind() string
+}
+
+// Leaf is a node without children.
+type Leaf struct{}
+
+// Kind panics for leaves without a kind.
+func (Leaf) Kind() string {
+	panic("leaf has no kind")
+}
+
+// Parser parses input.
+type Parser struct {
+	input string
+}
+
+// Parse parses the input.
+func (p *Parser) Parse() Node {
+	return p.expr(0)
+}
+
+func (p *Parser) expr(depth int) Node {
+	if depth > 100 {
+		panic(fmt.Sprintf("too deep: %d", depth))
+	}
+	return p.term()
+}
+
+func (p *Parser) term() Node {
+	return Leaf{}
+}
+
+// MustParse parses input or panics.
+func MustParse(input string) Node {
+	p := &Parser{input: input}
+	return p.Parse()
+}
+
+// Describe returns the kind of a node.
+func Describe(n Node) string {
+	return n.Kind()
+}
+
+// SafeParse parses input and recovers from panics.
+func SafeParse(input string) (n Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("parse: %v", r)
+		}
+	}()
+	return MustParse(input), nil
+}
+
+// Guarded uses a recovering helper.
+func Guarded() {
+	defer catch()
+	Fail()
+}
+
+func catch() {
+	recover()
+}
+
+// Fail logs and panics.
+func Fail() {
+	log.Panicf("failed")
+}
+
+// Apply calls fn on the input.
+func Apply(input string, fn func(string) error) error {
+	return fn(input)
+}
+
+// Length is always safe.
+func Length(input string) int {
+	return len(input)
+}
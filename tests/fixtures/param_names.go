@@ -0,0 +1,64 @@
+package calc
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Calculator adds numbers.
+type Calculator struct {
+	total int
+}
+
+// Add adds two numbers.
+func Add(a int, b int) int {
+	return a + b
+}
+
+// Sum adds the numbers of a list.
+func Sum(values ...int) int {
+	return 0
+}
+
+// Scale scales a value.
+func Scale(int, float64) float64 {
+	return 0
+}
+
+// Apply applies an operation.
+func (c *Calculator) Apply(ctx context.Context, op string, _ int) error {
+	return nil
+}
+
+// Merge merges two calculators.
+func (c *Calculator) Merge(arg0 *Calculator, arg1 *Calculator) {}
+
+// Serve serves the total.
+func Serve(w http.ResponseWriter, r *http.Request) {}
+
+// Copy copies a reader to a writer.
+func Copy(dst io.Writer, src io.Reader, n int) (int, error) {
+	return 0, nil
+}
+
+// Hypot computes the hypotenuse.
+func Hypot(x, y float64) float64 {
+	return 0
+}
+
+// Operator applies an operation.
+type Operator interface {
+	// Do applies the operation.
+	Do(x int, y int) int
+	Name() string
+}
+
+func add(a, b int) int {
+	return a + b
+}
+
+type hidden struct{}
+
+// Run runs.
+func (h hidden) Run(a int) {}
@@ -0,0 +1,83 @@
+package config
+
+// Config holds settings.
+type Config struct {
+	values map[string]string
+}
+
+// Checker checks configs.
+type Checker interface {
+	Ready() bool
+	IsStale() bool
+}
+
+// IsEmpty tells whether the config has no values.
+func (c *Config) IsEmpty() bool {
+	return len(c.values) == 0
+}
+
+// HasKey tells whether a key is set.
+func (c *Config) HasKey(key string) bool {
+	_, ok := c.values[key]
+	return ok
+}
+
+// CanWrite tells whether the config is writable.
+func CanWrite(path string) bool {
+	return path != ""
+}
+
+// ShouldRetry tells whether to retry after an error.
+func ShouldRetry(err error) bool {
+	return err != nil
+}
+
+// Empty tells whether the config has no values.
+func (c *Config) Empty() bool {
+	return len(c.values) == 0
+}
+
+// Valid tells whether the config is valid.
+func (c *Config) Valid() (ok bool) {
+	return true
+}
+
+// CheckSigned tells whether a user signed in.
+func CheckSigned(user string) bool {
+	return user != ""
+}
+
+// AllowWrite tells whether writes are allowed.
+func AllowWrite() bool {
+	return false
+}
+
+// KeyExists tells whether a key is set.
+func (c *Config) KeyExists(key string) bool {
+	return c.HasKey(key)
+}
+
+// Issue tells whether the config has an issue.
+func Issue() bool {
+	return false
+}
+
+// Lookup reads a key.
+func (c *Config) Lookup(key string) (bool, error) {
+	return c.HasKey(key), nil
+}
+
+// Get reads a key, comma-ok style.
+func (c *Config) Get(key string) (string, bool) {
+	v, ok := c.values[key]
+	return v, ok
+}
+
+// Less orders keys.
+func Less(a, b string) bool {
+	return a < b
+}
+
+func empty(c *Config) bool {
+	return c.IsEmpty()
+}
@@ -0,0 +1,54 @@
+package shop
+
+import "time"
+
+// Status is the state of an order.
+type Status int
+
+const (
+	StatusPending Status = iota + 1
+	StatusPaid
+	StatusShipped
+)
+
+// Priority is how urgently an order is handled.
+type Priority uint8
+
+const (
+	Low Priority = iota
+	High
+)
+
+// Money is an amount in cents.
+type Money int64
+
+// Item is a line of an order.
+type Item struct {
+	SKU      string `json:"sku"`
+	Quantity int32  `json:"quantity"`
+	Price    Money  `json:"price"`
+}
+
+// Order is a customer order.
+type Order struct {
+	ID       int64             `protobuf:"varint,1,opt,name=id" json:"id"`
+	Customer string            `protobuf:"bytes,3,opt,name=customer" json:"customer"`
+	Items    []Item            `json:"items"`
+	Status   Status            `json:"status"`
+	Priority *Priority         `json:"priority,omitempty"`
+	Note     *string           `json:"note,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Created  time.Time         `json:"created_at"`
+	Shipping struct {
+		Street string `json:"street"`
+		City   string `json:"city"`
+	} `json:"shipping"`
+	Done     chan bool `json:"done"`
+	Secret   string    `json:"-"`
+	internal int
+}
+
+// Clock has no serialization tags.
+type Clock struct {
+	Now func() time.Time
+}
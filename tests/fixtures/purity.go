@@ -0,0 +1,92 @@
+// Package counter counts events.
+package counter
+
+import "sync"
+
+const limit = 10
+
+var (
+	total int
+	mu    sync.Mutex
+	names = map[string]int{}
+)
+
+// Add adds n to the running total.
+func Add(n int) {
+	total += n
+}
+
+// AddTwice adds n twice.  It is safe for concurrent use.
+func AddTwice(n int) {
+	Add(n)
+	Add(n)
+}
+
+// Sum returns the sum of values.  It is safe for concurrent use.
+func Sum(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return Clamp(sum)
+}
+
+// Clamp limits n to the package limit.
+func Clamp(n int) int {
+	if n > limit {
+		return limit
+	}
+	return n
+}
+
+// Total returns the running total.
+func Total() int {
+	return total
+}
+
+// Record counts a name, guarded by a lock.
+func Record(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	names[name]++
+}
+
+// Fill sets every value.
+func Fill(values []int, v int) {
+	for i := range values {
+		values[i] = v
+	}
+}
+
+// Shadow declares a local named like a package variable.
+func Shadow() int {
+	total := 1
+	return total + 1
+}
+
+// Stats counts calls.
+type Stats struct {
+	count int
+}
+
+// Inc increments the count.
+func (s *Stats) Inc() {
+	s.count++
+}
+
+// Count returns the count.
+func (s Stats) Count() int {
+	return s.count
+}
+
+// Bump increments shared stats.
+func Bump(s *Stats) {
+	s.Inc()
+}
+
+// Copy increments a copy of the stats.
+func Copy(s Stats) Stats {
+	s.count++
+	s.Inc()
+	return s
+}
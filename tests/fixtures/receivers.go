@@ -0,0 +1,48 @@
+package calc
+
+// Calculator uses c for all its methods.
+type Calculator struct {
+	value float64
+}
+
+func (c *Calculator) Add(n float64)    { c.value += n }
+func (c *Calculator) Sub(n float64)    { c.value -= n }
+func (c Calculator) Value() float64    { return c.value }
+func (Calculator) Name() string        { return "calculator" }
+
+// BufferedReader mixes names.
+type BufferedReader struct {
+	buf []byte
+}
+
+func (br *BufferedReader) Read(p []byte) (int, error) { return copy(p, br.buf), nil }
+func (br *BufferedReader) Reset()                     { br.buf = nil }
+func (reader *BufferedReader) Len() int              { return len(reader.buf) }
+func (b *BufferedReader) Peek(br int) []byte          { return b.buf[:br] }
+
+// HTTPServer uses a generic name everywhere.
+type HTTPServer struct {
+	addr string
+}
+
+func (this *HTTPServer) Addr() string { return this.addr }
+
+// Stack is generic.
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(v T) { s.items = append(s.items, v) }
+func (stack *Stack[T]) Pop() T {
+	v := stack.items[len(stack.items)-1]
+	stack.items = stack.items[:len(stack.items)-1]
+	return v
+}
+
+// Logged embeds a Calculator, whose methods it promotes.
+type Logged struct {
+	Calculator
+	lines []string
+}
+
+func (l *Logged) Log(line string) { l.lines = append(l.lines, line) }
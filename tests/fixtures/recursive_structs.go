@@ -0,0 +1,52 @@
+package tree
+
+// Node is a node of a linked list.
+type Node struct {
+	Value int
+	Next  *Node
+}
+
+// Tree is a node of a generic tree.
+type Tree[T any] struct {
+	Value    T
+	Children []*Tree[T]
+	Index    map[string]*Tree[T]
+}
+
+// Dir is a directory holding files.
+type Dir struct {
+	Name  string
+	Files []*File
+	Meta  struct {
+		Owner *User
+	}
+}
+
+// File is a file of a directory.
+type File struct {
+	Name   string
+	Parent *Dir
+}
+
+// User owns directories.
+type User struct {
+	Name string
+	Home *Dir
+}
+
+// Point is not recursive.
+type Point struct {
+	X, Y int
+}
+
+// Shape references a point only.
+type Shape struct {
+	Center Point
+	Path   []Point
+}
+
+// Graph is recursive and references other structs.
+type Graph struct {
+	Nodes  []*Graph
+	Origin *Point
+}
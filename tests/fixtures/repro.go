@@ -0,0 +1,75 @@
+// Package shapes describes and draws simple shapes.
+package shapes
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"example.com/app/units"
+	"github.com/acme/render"
+)
+
+// Kind identifies a shape.
+type Kind int
+
+const (
+	// KindCircle is a circle.
+	KindCircle Kind = iota
+	// KindSquare is a square.
+	KindSquare
+)
+
+type (
+	// Shape is a shape with an area.
+	Shape interface {
+		Area() float64
+		Name() string
+	}
+
+	// Circle is a circle of a given radius.
+	Circle struct {
+		Radius units.Length
+		Label  string
+	}
+)
+
+// Area returns the area of the circle.
+func (c Circle) Area() float64 {
+	return math.Pi * float64(c.Radius) * float64(c.Radius)
+}
+
+// Name returns the name of the circle.
+func (c Circle) Name() string {
+	return "circle"
+}
+
+// Perimeter returns the perimeter of the circle.
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * float64(c.Radius)
+}
+
+// Describe formats a shape with its area.
+func Describe(s Shape) string {
+	return fmt.Sprintf("%s: %.2f %s", s.Name(), s.Area(), units.Suffix)
+}
+
+// Upper returns the name of a shape in upper case.
+func Upper(s Shape) string {
+	return strings.ToUpper(s.Name())
+}
+
+// Draw renders a shape on a canvas.
+func Draw(canvas *render.Canvas, s Shape) error {
+	return render.Text(canvas, Describe(s))
+}
+
+// Grow scales a circle.
+func Grow(c Circle, factor float64) Circle {
+	return Circle{Radius: units.Scale(c.Radius, factor), Label: c.Label}
+}
+
+// Unit returns a unit circle.
+func Unit() Shape {
+	return Circle{Radius: 1, Label: "unit"}
+}
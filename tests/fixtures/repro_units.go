@@ -0,0 +1,20 @@
+// Package units defines measurement units.
+package units
+
+import "strconv"
+
+// Length is a length in meters.
+type Length float64
+
+// Suffix is appended to formatted lengths.
+const Suffix = "m"
+
+// Scale multiplies a length by a factor.
+func Scale(l Length, factor float64) Length {
+	return Length(float64(l) * factor)
+}
+
+// String formats the length.
+func (l Length) String() string {
+	return strconv.FormatFloat(float64(l), 'f', 2, 64) + Suffix
+}
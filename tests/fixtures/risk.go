@@ -0,0 +1,42 @@
+package billing
+
+// Parse parses an amount.
+func Parse(text string) int {
+	total := 0
+	for _, c := range text {
+		if c >= '0' && c <= '9' {
+			total = total*10 + int(c-'0')
+		} else if c == '-' || c == '+' {
+			continue
+		}
+	}
+	return total
+}
+
+// Invoice sums amounts.
+func Invoice(items []string) int {
+	sum := 0
+	for _, item := range items {
+		sum += Parse(item)
+	}
+	return sum
+}
+
+// Refund parses a refund.
+func Refund(text string) int {
+	return -Parse(text)
+}
+
+// Tax parses a tax.
+func Tax(text string) int {
+	return Parse(text) / 10
+}
+
+// Report prints a report.
+func Report(items []string) int {
+	return Invoice(items) + Refund(items[0]) + Tax(items[0]) + round()
+}
+
+func round() int {
+	return 0
+}
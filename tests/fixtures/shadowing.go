@@ -0,0 +1,76 @@
+package staff
+
+import "strings"
+
+// Named is implemented by anything with a display name.
+type Named interface {
+	Name() string
+}
+
+// User is an account holder.
+type User struct {
+	First string
+	Last  string
+}
+
+// Name returns the full name of the user.
+func (u User) Name() string {
+	return u.First + " " + u.Last
+}
+
+// Email returns the address of the user.
+func (u User) Email() string {
+	return strings.ToLower(u.First) + "@example.com"
+}
+
+// ID returns the identifier of the user.
+func (u User) ID() string {
+	return u.Email()
+}
+
+// Employee is a user with a title.
+type Employee struct {
+	User
+	Title string
+}
+
+// Name returns the name of the employee, prefixed by their title.
+func (e Employee) Name() string {
+	return e.Title + " " + e.User.Name()
+}
+
+// Email returns the work address of the employee.
+func (e Employee) Email() string {
+	return strings.ToLower(e.Last) + "@corp.example.com"
+}
+
+// Contractor is a user working for an agency.
+type Contractor struct {
+	User
+	Agency string
+}
+
+// Name returns the name of the contractor, formally or not.
+func (c Contractor) Name(formal bool) string {
+	if formal {
+		return c.Last
+	}
+	return c.First
+}
+
+// Email shadows User.Email to use the domain of the agency.
+func (c Contractor) Email(domain string) string {
+	return strings.ToLower(c.First) + "@" + domain
+}
+
+// Guest is a user with a temporary identifier.
+type Guest struct {
+	User
+	ID string
+}
+
+// Manager is an employee with reports.
+type Manager struct {
+	Employee
+	Reports []string
+}
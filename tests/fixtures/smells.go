@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+)
+
+var errEmpty = errors.New("empty")
+
+type Syncer struct {
+	Root string
+	dry  bool
+}
+
+// save writes one entry.
+func save(name string) error {
+	if name == "" {
+		return errEmpty
+	}
+	return nil
+}
+
+// Close releases the syncer.
+func (s *Syncer) Close() error { return nil }
+
+func (s *Syncer) Run(names []string, limit string, force bool, verbose bool, ctx context.Context) {
+	max, _ := strconv.Atoi(limit)
+	for i, name := range names {
+		if i > max {
+			if force {
+				if verbose && name != "" {
+					save(name)
+				}
+			} else if name == "stop" || name == "halt" {
+				panic("stopped")
+			}
+		}
+	}
+	_ = os.Remove(s.Root)
+	s.Close()
+}
+
+// Count is small and clean.
+func Count(names []string) int {
+	return len(names)
+}
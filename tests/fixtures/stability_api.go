@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+
+	"example.com/app/model"
+	"example.com/app/service"
+	"example.com/app/store"
+)
+
+// Handler serves users over HTTP.
+type Handler struct {
+	service *service.Service
+	reader  store.Reader
+	mux     *http.ServeMux
+}
+
+func (h *Handler) user(id model.ID) {}
@@ -0,0 +1,10 @@
+package model
+
+// ID identifies a user.
+type ID string
+
+// User is a user of the application.
+type User struct {
+	ID   ID
+	Name string
+}
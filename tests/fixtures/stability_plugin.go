@@ -0,0 +1,13 @@
+package plugin
+
+import "example.com/app/model"
+
+// Plugin extends the application.
+type Plugin interface {
+	Name() string
+}
+
+// Loader loads the plugins of a user.
+type Loader interface {
+	Load(user model.User) []Plugin
+}
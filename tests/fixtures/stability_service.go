@@ -0,0 +1,20 @@
+package service
+
+import (
+	"example.com/app/model"
+	"example.com/app/store"
+)
+
+// Hook is called on changes.
+type Hook interface {
+	Changed(user model.User)
+}
+
+// Option configures a service.
+type Option func(*Service)
+
+// Service serves users.
+type Service struct {
+	store store.Store
+	hooks []Hook
+}
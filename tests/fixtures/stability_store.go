@@ -0,0 +1,18 @@
+package store
+
+import "example.com/app/model"
+
+// Reader reads users.
+type Reader interface {
+	Get(id model.ID) (model.User, bool)
+}
+
+// Store reads and writes users.
+type Store interface {
+	Reader
+	Put(user model.User)
+}
+
+type memory struct {
+	users map[model.ID]model.User
+}
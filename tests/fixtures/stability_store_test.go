@@ -0,0 +1,13 @@
+package store
+
+import (
+	"testing"
+
+	"example.com/app/api"
+)
+
+type fakeStore struct{}
+
+func TestStore(t *testing.T) {
+	_ = api.Handler{}
+}
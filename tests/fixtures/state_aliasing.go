@@ -0,0 +1,89 @@
+package container
+
+import (
+	"maps"
+	"slices"
+)
+
+// Tags is a list of tags.
+type Tags []string
+
+// Container holds items.
+type Container[T any] struct {
+	items  []T
+	index  map[string]int
+	tags   Tags
+	Public []T
+	name   string
+}
+
+// Get returns an element.
+func (c *Container[T]) Get(i int) T {
+	return c.items[i]
+}
+
+// Items returns the items.
+func (c *Container[T]) Items() []T {
+	return c.items
+}
+
+// Tail returns the items but the first.
+func (c *Container[T]) Tail() []T {
+	return c.items[1:]
+}
+
+// Index returns the index and whether it is set.
+func (c *Container[T]) Index() (map[string]int, bool) {
+	return c.index, c.index != nil
+}
+
+// Tags returns the tags.
+func (c *Container[T]) Tags() Tags {
+	tags := c.tags
+	return tags
+}
+
+// Exposed returns an exported field.
+func (c *Container[T]) Exposed() []T {
+	return c.Public
+}
+
+// Name returns the name.
+func (c *Container[T]) Name() string {
+	return c.name
+}
+
+// Cloned returns a copy of the items.
+func (c *Container[T]) Cloned() []T {
+	return slices.Clone(c.items)
+}
+
+// Appended returns a copy of the items.
+func (c *Container[T]) Appended() []T {
+	return append([]T(nil), c.items...)
+}
+
+// Copied returns a copy of the items.
+func (c *Container[T]) Copied() []T {
+	out := make([]T, len(c.items))
+	copy(out, c.items)
+	return out
+}
+
+// IndexCopy returns a copy of the index.
+func (c *Container[T]) IndexCopy() map[string]int {
+	out := make(map[string]int, len(c.index))
+	for k, v := range c.index {
+		out[k] = v
+	}
+	return out
+}
+
+// MapClone returns a copy of the index.
+func (c *Container[T]) MapClone() map[string]int {
+	return maps.Clone(c.index)
+}
+
+func (c *Container[T]) raw() []T {
+	return c.items
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Point is plain data.
+type Point struct {
+	X int
+	Y int
+}
+
+// User is data with accessors.
+type User struct {
+	ID   int
+	Name string
+	role string
+}
+
+// Role returns the role of the user.
+func (u *User) Role() string {
+	return u.role
+}
+
+// SetRole sets the role of the user.
+func (u *User) SetRole(role string) {
+	u.role = role
+}
+
+// String formats the user.
+func (u User) String() string {
+	return fmt.Sprintf("%d:%s", u.ID, u.Name)
+}
+
+// Counter keeps its state behind methods.
+type Counter struct {
+	count int
+}
+
+// Increment adds one.
+func (c *Counter) Increment() {
+	c.count++
+}
+
+// Decrement removes one, stopping at zero.
+func (c *Counter) Decrement() {
+	if c.count > 0 {
+		c.count--
+	}
+}
+
+// Value returns the count.
+func (c *Counter) Value() int {
+	return c.count
+}
+
+// Calculator accumulates a value.
+type Calculator struct {
+	Value float64
+}
+
+// Add adds to the value.
+func (c *Calculator) Add(n float64) {
+	c.Value += n
+}
+
+// Multiply multiplies the value.
+func (c *Calculator) Multiply(n float64) {
+	c.Value *= n
+}
+
+// Config is mostly data with some validation.
+type Config struct {
+	Host    string
+	Port    int
+	Timeout int
+	Retries int
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	if c.Host == "" {
+		return errors.New("missing host")
+	}
+	if c.Port <= 0 {
+		return errors.New("invalid port")
+	}
+	return nil
+}
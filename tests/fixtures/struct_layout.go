@@ -0,0 +1,85 @@
+package layout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const bufferSize = 8
+
+// Small fits in two words and is cheap to copy.
+type Small struct {
+	ID    int32
+	Valid bool
+}
+
+// Padded has poorly ordered fields: 1 + 7 (pad) + 8 + 1 + 7 (pad) = 24 bytes.
+type Padded struct {
+	A bool
+	B int64
+	C bool
+}
+
+// Large is well over the default threshold when passed by value.
+type Large struct {
+	Name      string
+	Tags      []string
+	Created   time.Time
+	Updated   time.Time
+	Counts    [bufferSize * 2]int32
+	Owner     *Small
+	Metadata  map[string]string
+	Reference Small
+}
+
+// Guarded embeds a mutex.
+type Guarded struct {
+	mu    sync.Mutex
+	items []string
+}
+
+// Reader is an interface and must never be flagged.
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+// Process takes a large struct by value.
+func Process(ctx context.Context, l Large, s Small) error {
+	_ = l
+	_ = s
+	return ctx.Err()
+}
+
+// ProcessPointer takes a large struct by pointer.
+func ProcessPointer(l *Large) {}
+
+// Summary has a large value receiver.
+func (l Large) Summary() string {
+	return l.Name
+}
+
+// Rename has a pointer receiver and is fine.
+func (l *Large) Rename(name string) {
+	l.Name = name
+}
+
+// Consume takes an interface, which is never flagged.
+func Consume(r Reader, items ...Large) {}
+
+// Describe takes a small struct by value.
+func Describe(s Small, p Padded) string {
+	return ""
+}
+
+// Pair is a generic struct whose size depends on its type arguments.
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Store takes a large instantiation of Pair by value.
+func Store(p Pair[Padded, [bufferSize]int64]) {}
+
+// Flag takes a small instantiation of Pair by value.
+func Flag(p Pair[int32, bool]) {}
@@ -0,0 +1,34 @@
+package api
+
+import "sync"
+
+// Base holds the fields shared by every resource.
+type Base struct {
+	ID int `json:"id"`
+}
+
+// User complies with the tag policy.
+type User struct {
+	ID       int    `json:"id" db:"user_id"`
+	Name     string `json:"name" db:"user_name"`
+	Email    string `json:"email" db:"email"`
+	IsActive bool   `json:"is_active" db:"active"`
+}
+
+// Order breaks the tag policy.
+type Order struct {
+	Base
+	Total     float64 `json:"total"`
+	Currency  string
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:",omitempty"`
+	Secret    string `json:"-"`
+	Notes     string `json: "notes"`
+	internal  int
+}
+
+// Cache has no tags and is not serialized.
+type Cache struct {
+	mu    sync.Mutex
+	Items map[string]string
+}
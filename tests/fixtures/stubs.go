@@ -0,0 +1,35 @@
+package kv
+
+import (
+	"context"
+	"io"
+)
+
+// Count is a number of keys.
+type Count int
+
+// Keys is a list of keys.
+type Keys []string
+
+// Entry is a stored entry.
+type Entry struct {
+	Key   string
+	Value []byte
+}
+
+// Store is a key value store.
+type Store interface {
+	io.Closer
+	Getter
+
+	Put(key string, value []byte) error
+	Watch(context.Context, func(string)) <-chan Entry
+	List(s string, limit int) (Keys, error)
+	Len() Count
+	First() (Entry, bool)
+}
+
+// Getter reads entries.
+type Getter interface {
+	Get(string) ([]byte, error)
+}
@@ -0,0 +1,48 @@
+// Package geo provides géométrie helpers — shapes and points.
+package geo
+
+import (
+	"fmt"
+	m "math"
+)
+
+// Pi is π, approximately.
+const Pi = 3.14
+
+const (
+	// Origin names the zéro point.
+	Origin = "origin"
+	A, B   = 1, 2
+)
+
+var registry = map[string]Shape{}
+
+// Point is a point — in two dimensions.
+type Point struct {
+	X, Y float64 // coordonnées
+	*fmt.Stringer
+	Label string
+}
+
+// Shape is a shape.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+type Small struct{ W int; H int }
+
+type ID = string
+
+// Distance returns the distance between deux points.
+func Distance(p, q Point) float64 {
+	dx := p.X - q.X // Δx
+	dy := p.Y - q.Y
+	return m.Sqrt(dx*dx + dy*dy)
+}
+
+// Scale scales a point.
+func (p *Point) Scale(f float64) {
+	p.X *= f
+	p.Y *= f
+}
@@ -0,0 +1,66 @@
+package temperature
+
+// Celsius is a temperature in degrees Celsius.
+type Celsius float64
+
+// Fahrenheit is a temperature in degrees Fahrenheit.
+type Fahrenheit float64
+
+// Kelvin is a temperature in kelvins.
+type Kelvin float64
+
+// Reading is a sensor reading.
+type Reading struct {
+	Sensor string
+	Value  Celsius
+}
+
+// Boiling is the boiling point of water.
+const Boiling Celsius = 100
+
+// ToFahrenheit converts the temperature with the proper formula.
+func (c Celsius) ToFahrenheit() Fahrenheit {
+	return Fahrenheit(c*9/5 + 32)
+}
+
+// ToCelsius converts the temperature with the proper formula.
+func (f Fahrenheit) ToCelsius() Celsius {
+	return Celsius((f - 32) * 5 / 9)
+}
+
+// Relabel reinterprets a reading without converting it: a unit
+// confusion bug.
+func Relabel(r Reading) Fahrenheit {
+	return Fahrenheit(r.Value)
+}
+
+// Average averages Fahrenheit readings into Celsius, forgetting the
+// formula.
+func Average(values []Fahrenheit) Celsius {
+	var total Fahrenheit
+	for _, v := range values {
+		total += v
+	}
+	mean := total / Fahrenheit(len(values))
+	return Celsius(mean)
+}
+
+// BoilingFahrenheit converts a constant.
+func BoilingFahrenheit() Fahrenheit {
+	return Fahrenheit(Boiling)
+}
+
+// Nested converts through another call.
+func Nested(c Celsius) Fahrenheit {
+	return Fahrenheit(Celsius(c).ToFahrenheit())
+}
+
+// Absolute converts to kelvins.
+func Absolute(c Celsius) Kelvin {
+	return Kelvin(c + 273.15)
+}
+
+// FromRaw converts a raw number.
+func FromRaw(raw float64) Celsius {
+	return Celsius(raw)
+}
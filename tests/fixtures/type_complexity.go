@@ -0,0 +1,94 @@
+package engine
+
+import "strings"
+
+// Base holds the shared validation of the engine types.
+type Base struct {
+	strict bool
+}
+
+// Validate checks a name.
+func (b *Base) Validate(name string) bool {
+	if name == "" {
+		return false
+	}
+	if b.strict && strings.ContainsAny(name, " \t") {
+		return false
+	}
+	return true
+}
+
+// Configure sets the options of the validation.
+func (b *Base) Configure(options map[string]bool) {
+	for key, value := range options {
+		if key == "strict" {
+			b.strict = value
+		}
+	}
+}
+
+// Engine parses, plans and runs jobs: a god type.
+type Engine struct {
+	*Base
+	jobs  []string
+	state map[string]int
+}
+
+// Parse splits a spec into jobs.
+func (e *Engine) Parse(spec string) error {
+	for _, line := range strings.Split(spec, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !e.Validate(line) {
+			continue
+		}
+		e.jobs = append(e.jobs, line)
+	}
+	return nil
+}
+
+// Plan orders the jobs.
+func (e *Engine) Plan() {
+	for i := range e.jobs {
+		for j := i + 1; j < len(e.jobs); j++ {
+			if e.state[e.jobs[j]] < e.state[e.jobs[i]] {
+				e.jobs[i], e.jobs[j] = e.jobs[j], e.jobs[i]
+			}
+		}
+	}
+}
+
+// Run runs the jobs.
+func (e *Engine) Run() int {
+	done := 0
+	for _, job := range e.jobs {
+		switch e.state[job] {
+		case 0:
+			e.state[job] = 1
+		case 1:
+			done++
+		default:
+			if e.state[job] > 10 && e.state[job] < 100 {
+				done--
+			}
+		}
+	}
+	return done
+}
+
+// Validate overrides the validation of Base.
+func (e *Engine) Validate(name string) bool {
+	return name != ""
+}
+
+// Counter counts.
+type Counter int
+
+// Inc increments the counter.
+func (c *Counter) Inc() { *c++ }
+
+// Option is not a receiver of any method.
+type Option struct {
+	Name string
+}
@@ -0,0 +1,39 @@
+package buffers
+
+import (
+	"reflect"
+	u "unsafe"
+)
+
+// Options configure a buffer; unsafe is a field, not the package.
+type Options struct {
+	unsafe bool
+}
+
+// BytesToString converts without copying.
+func BytesToString(b []byte) string {
+	return *(*string)(u.Pointer(&b))
+}
+
+// HeaderSize is the size of a slice header.
+func HeaderSize() uintptr {
+	var h reflect.SliceHeader
+	return u.Sizeof(h)
+}
+
+// Address returns the address of a reflected value.
+func Address(v any) uintptr {
+	return reflect.ValueOf(v).UnsafeAddr()
+}
+
+// Allowed reports whether unsafe conversions are allowed.
+func (o *Options) Allowed() bool {
+	unsafe := o.unsafe
+	return unsafe
+}
+
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+// memclr is implemented in assembly.
+func memclr(p u.Pointer, n uintptr)
@@ -0,0 +1,31 @@
+//go:build ignore
+
+// This file does not compile on purpose: os and yaml are imported but
+// never used.  The build constraint keeps it out of Go tooling.
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	_ "embed"
+	. "math"
+
+	"github.com/example/units/v2"
+	yaml "gopkg.in/yaml.v3"
+	str "strconv"
+)
+
+// Format formats a value rounded to its nearest integer.
+func Format(v float64) string {
+	// os.Exit is only mentioned in a comment
+	label := "yaml.Marshal"
+	return fmt.Sprintf("%s %s", strings.TrimSpace(label), str.Itoa(int(Round(v))))
+}
+
+// Convert converts a length to meters.
+func Convert(l units.Length) float64 {
+	return l.
+		Meters()
+}
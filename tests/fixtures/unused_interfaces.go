@@ -0,0 +1,75 @@
+package shapes
+
+import "fmt"
+
+// Shape is used as a parameter type.
+type Shape interface {
+	Area() float64
+}
+
+// Sized is embedded in Measured.
+type Sized interface {
+	Size() int
+}
+
+// Measured is used as a field type.
+type Measured interface {
+	Sized
+	Measure() float64
+}
+
+// Painter is only checked by a compile-time assertion.
+type Painter interface {
+	Paint(color string)
+}
+
+// Comparable is a generic interface nobody uses.
+type Comparable[T any] interface {
+	Compare(other T) int
+}
+
+// Number is used as a type constraint.
+type Number interface {
+	~int | ~float64
+}
+
+// renderer is used in a type assertion.
+type renderer interface {
+	render() string
+}
+
+// visitor is never used; it only refers to itself.
+type visitor interface {
+	visit(shape Shape) visitor
+}
+
+type Square struct {
+	Side float64
+}
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+func (s *Square) Paint(color string) {}
+
+var _ Painter = (*Square)(nil)
+
+type Report struct {
+	Target Measured
+}
+
+// Describe prints the area of a shape.
+func Describe(s Shape) {
+	if r, ok := s.(renderer); ok {
+		fmt.Println(r.render())
+	}
+	fmt.Println(s.Area())
+}
+
+// Sum adds numbers.
+func Sum[T Number](values ...T) T {
+	var total T
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -14,6 +14,7 @@ import './lib/java_parsing.mjs';
 import './lib/csharp_parsing.mjs';
 import './lib/typescript_parsing.mjs';
 import './lib/class_struct_parsing.mjs';
+import './lib/cli.mjs';
 import './lib/mcp_tools.mjs';
 import './lib/mcp_handlers.mjs';
 import './lib/mcp_config.mjs';
@@ -33,6 +34,56 @@ import './lib/analysis/patterns.mjs';
 import './lib/analysis/index.mjs';
 import './lib/analysis/concurrency.mjs';
 import './lib/analysis/resources.mjs';
+import './lib/analysis/refactoring.mjs';
+import './lib/analysis/golang.mjs';
+import './lib/analysis/structs.mjs';
+import './lib/analysis/literals.mjs';
+import './lib/analysis/godoc.mjs';
+import './lib/analysis/graph.mjs';
+import './lib/analysis/errors.mjs';
+import './lib/analysis/panics.mjs';
+import './lib/analysis/symbols.mjs';
+import './lib/analysis/initorder.mjs';
+import './lib/analysis/impact.mjs';
+import './lib/analysis/scripts.mjs';
+import './lib/analysis/conventions.mjs';
+import './lib/analysis/tokens.mjs';
+import './lib/analysis/stubs.mjs';
+import './lib/analysis/interfaces.mjs';
+import './lib/analysis/searchindex.mjs';
+import './lib/analysis/constraints.mjs';
+import './lib/analysis/smells.mjs';
+import './lib/analysis/changes.mjs';
+import './lib/analysis/conversions.mjs';
+import './lib/analysis/repro.mjs';
+import './lib/analysis/purity.mjs';
+import './lib/analysis/escapes.mjs';
+import './lib/analysis/imports.mjs';
+import './lib/analysis/catalog.mjs';
+import './lib/analysis/loopvars.mjs';
+import './lib/analysis/coupling.mjs';
+import './lib/analysis/protobuf.mjs';
+import './lib/analysis/unsafe.mjs';
+import './lib/analysis/callchains.mjs';
+import './lib/analysis/inlining.mjs';
+import './lib/analysis/changelog.mjs';
+import './lib/analysis/deprecated.mjs';
+import './lib/analysis/surface.mjs';
+import './lib/analysis/receivers.mjs';
+import './lib/analysis/funcvalues.mjs';
+import './lib/analysis/inversion.mjs';
+import './lib/analysis/doubles.mjs';
+import './lib/analysis/builtins.mjs';
+import './lib/analysis/cheatsheet.mjs';
+import './lib/analysis/lifecycle.mjs';
+import './lib/analysis/stability.mjs';
+import './lib/analysis/paramnames.mjs';
+import './lib/analysis/symbolranges.mjs';
+import './lib/analysis/aliasing.mjs';
+import './lib/analysis/symboltree.mjs';
+import './lib/analysis/ifacemethods.mjs';
+import './lib/analysis/predicates.mjs';
+import './lib/analysis/risk.mjs';
 import './lib/model/entity.mjs';
 import './lib/model/project.mjs';
 import './lib/model/relationship.mjs';
@@ -0,0 +1,68 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go internal state aliasing functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { find_source_state_aliasing } from '../../../lib/analysis/aliasing.mjs';
+
+const sources = [
+  {
+    filename: 'container/container.go',
+    source: readFileSync('./tests/fixtures/state_aliasing.go', 'utf-8')
+  }
+];
+
+const { aliases, copies, summary } = find_source_state_aliasing(sources);
+const find_alias = (method) => aliases.find((a) => a.method === method);
+const find_copy = (method) => copies.find((c) => c.method === method);
+
+// ============ find_source_state_aliasing tests ============
+
+await test('find_source_state_aliasing finds returned fields', async (t) => {
+  const items = find_alias('Items');
+  t.assert.eq(items.field, 'items', 'Should give the field returned');
+  t.assert.eq(items.kind, 'field', 'Should report the field itself');
+  t.assert.eq(items.collection, 'slice', 'Should give the collection kind');
+  t.assert.eq(items.line, 27, 'Should report the line of the return');
+  t.assert.eq(items.message, 'Container.Items returns the internal slice c.items; callers can modify it, return a copy instead', 'Should describe the alias');
+  const index = find_alias('Index');
+  t.assert.eq(index.collection, 'map', 'Should check map fields');
+  t.assert.eq(index.position, 0, 'Should give the position of the returned value');
+});
+
+await test('find_source_state_aliasing finds subslices and locals', async (t) => {
+  t.assert.eq(find_alias('Tail').kind, 'subslice', 'Should report subslices');
+  const tags = find_alias('Tags');
+  t.assert.eq(tags.kind, 'local', 'Should follow locals holding the field');
+  t.assert.eq(tags.collection, 'slice', 'Should follow named slice types');
+  t.assert.eq(tags.field_type, 'Tags', 'Should give the field type');
+});
+
+await test('find_source_state_aliasing lists copies', async (t) => {
+  t.assert.eq(find_copy('Cloned').kind, 'clone', 'Should recognize slices.Clone');
+  t.assert.eq(find_copy('MapClone').kind, 'clone', 'Should recognize maps.Clone');
+  t.assert.eq(find_copy('Appended').kind, 'append', 'Should recognize appending to nil');
+  t.assert.eq(find_copy('Copied').kind, 'copy', 'Should recognize copy');
+  t.assert.eq(find_copy('IndexCopy').kind, 'loop', 'Should recognize filling loops');
+  t.assert.ok(!find_alias('Cloned'), 'Should not report copies as aliases');
+});
+
+await test('find_source_state_aliasing skips elements, exported fields and unexported methods', async (t) => {
+  t.assert.ok(!find_alias('Get'), 'Should not report returned elements');
+  t.assert.ok(!find_alias('Exposed'), 'Should not report exported fields');
+  t.assert.ok(!find_alias('Name'), 'Should not report other fields');
+  t.assert.ok(!find_alias('raw'), 'Should skip unexported methods by default');
+  const all = find_source_state_aliasing(sources, { include_unexported: true });
+  t.assert.ok(all.aliases.find((a) => a.method === 'raw'), 'Should check unexported methods on request');
+});
+
+await test('find_source_state_aliasing summarizes', async (t) => {
+  t.assert.eq(summary.aliases, 4, 'Should count aliases');
+  t.assert.eq(summary.copies, 5, 'Should count copies');
+  t.assert.eq(summary.types, 1, 'Should count the types concerned');
+  t.assert.eq(summary.by_kind.field, 2, 'Should count aliases by kind');
+  t.assert.eq(summary.copies_by_kind.clone, 2, 'Should count copies by kind');
+});
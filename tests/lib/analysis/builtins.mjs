@@ -0,0 +1,81 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go builtin shadowing functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_source_builtin_shadowing,
+  uses_builtin
+} from '../../../lib/analysis/builtins.mjs';
+
+const sources = [
+  {
+    filename: 'shadow/shadow.go',
+    source: readFileSync('./tests/fixtures/builtin_shadowing.go', 'utf-8')
+  }
+];
+
+const { shadows, summary } = find_source_builtin_shadowing(sources);
+const find_shadow = (fn, name) =>
+  shadows.find((s) => s.function === fn && s.name === name);
+
+// ============ uses_builtin tests ============
+
+await test('uses_builtin finds calls, conversions and type uses', async (t) => {
+  t.assert.ok(uses_builtin('n := len(s)', 'len'), 'Should find builtin calls');
+  t.assert.ok(!uses_builtin('n := len + 1', 'len'), 'Should ignore other uses');
+  t.assert.ok(!uses_builtin('n := s.len(x)', 'len'), 'Should ignore method calls');
+  t.assert.ok(!uses_builtin('copy(1)', 'copy', true), 'Should ignore calls of function values');
+  t.assert.ok(uses_builtin('s := string(b)', 'string'), 'Should find conversions');
+  t.assert.ok(uses_builtin('m := map[string]int{}', 'string'), 'Should find map keys');
+  t.assert.ok(uses_builtin('var names []string', 'string'), 'Should find slice elements');
+  t.assert.ok(uses_builtin('var e error', 'error'), 'Should find declared types');
+  t.assert.ok(!uses_builtin('return error', 'error'), 'Should ignore values');
+});
+
+// ============ find_source_builtin_shadowing tests ============
+
+await test('find_source_builtin_shadowing reports a parameter named len', async (t) => {
+  const pad = find_shadow('Pad', 'len');
+  t.assert.eq(pad.declaration, 'parameter', 'Should report the parameter');
+  t.assert.eq(pad.builtin_kind, 'function', 'Should name the builtin it hides');
+  t.assert.eq(pad.problematic, false, 'Should be harmless when len is not called');
+  const count = find_shadow('Count', 'len');
+  t.assert.eq(count.reason, 'used', 'Should flag a len call in the scope');
+  t.assert.eq(count.problematic, true, 'Should be problematic');
+  t.assert.eq(count.line, 22, 'Should report the line of the function');
+});
+
+await test('find_source_builtin_shadowing scopes locals from their statement end', async (t) => {
+  const size = find_shadow('Size', 'len');
+  t.assert.eq(size.declaration, 'variable', 'Should report the local variable');
+  t.assert.eq(size.reason, 'unused', 'Should let the declaration read the builtin');
+  t.assert.eq(size.line, 32, 'Should report the line of the declaration');
+  t.assert.eq(find_shadow('Grow', 'new').reason, 'used', 'Should flag later calls');
+  t.assert.eq(find_shadow('Describe', 'error').reason, 'unused', 'Should accept a var never used as a type');
+});
+
+await test('find_source_builtin_shadowing distinguishes package and constant shadowing', async (t) => {
+  const max = shadows.find((s) => s.name === 'max');
+  t.assert.eq(max.declaration, 'function', 'Should report package functions');
+  t.assert.eq(max.reason, 'package', 'Should flag the whole package');
+  t.assert.eq(find_shadow('Flags', 'true').reason, 'constant', 'Should flag shadowed constants');
+  t.assert.eq(find_shadow('Convert', 'string').reason, 'used', 'Should flag type conversions');
+  t.assert.eq(find_shadow('Apply', 'copy').problematic, false, 'Should accept callbacks calling themselves');
+  t.assert.eq(find_shadow('Each', 'cap').declaration, 'parameter', 'Should include function literal parameters');
+  t.assert.ok(!shadows.some((s) => s.name === 'append'), 'Should not report methods');
+});
+
+await test('find_source_builtin_shadowing summarizes and filters', async (t) => {
+  t.assert.eq(summary.total, 10, 'Should count declarations');
+  t.assert.eq(summary.problematic, 5, 'Should count problematic ones');
+  t.assert.eq(summary.harmless, 5, 'Should count harmless ones');
+  t.assert.eq(summary.by_builtin.len, 3, 'Should count by builtin');
+  const problematic = find_source_builtin_shadowing(sources, {
+    problematic_only: true
+  });
+  t.assert.eq(problematic.shadows.length, 5, 'Should only list problematic shadowing');
+});
@@ -0,0 +1,84 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go call chain functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_longest_call_chains,
+  find_longest_chain,
+  build_call_graph
+} from '../../../lib/analysis/callchains.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/callchain.go', 'utf-8'),
+    'cmd/app/main.go'
+  )
+]);
+
+// ============ find_longest_call_chains tests ============
+
+await test('find_longest_call_chains follows the deepest path from main', async (t) => {
+  const [chain] = find_longest_call_chains(packages);
+
+  t.assert.eq(chain.root, 'cmd/app:main', 'main is the default root');
+  t.assert.eq(chain.length, 7, 'seven calls');
+  t.assert.eq(
+    chain.chain.map((step) => step.name),
+    [
+      'main',
+      'Server.Handle',
+      'Server.route',
+      'dispatch',
+      'validate',
+      'normalize',
+      'trim',
+      'log'
+    ],
+    'ordered chain'
+  );
+  t.assert.eq(chain.chain[1].call_line, 12, 'line of the call');
+  t.assert.ok(!chain.truncated, 'not truncated');
+});
+
+await test('find_longest_call_chains marks cycles', async (t) => {
+  const [chain] = find_longest_call_chains(packages, { root: 'isOdd' });
+
+  t.assert.eq(chain.length, 1, 'the cycle is not followed');
+  t.assert.ok(chain.chain[1].recursive, 'isEven calls back');
+  t.assert.eq(
+    chain.cycles,
+    [{ from: 'cmd/app:isEven', to: 'cmd/app:isOdd', line: 49 }],
+    'cycle'
+  );
+});
+
+await test('find_longest_call_chains resolves roots', async (t) => {
+  const [chain] = find_longest_call_chains(packages, { root: 'Server.route' });
+  t.assert.eq(chain.length, 5, 'method root');
+
+  let error = null;
+  try {
+    find_longest_call_chains(packages, { root: 'missing' });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error && error.message.includes('not found'), 'unknown root');
+});
+
+// ============ find_longest_chain tests ============
+
+await test('find_longest_chain bounds the depth', async (t) => {
+  const functions = build_call_graph(packages);
+  const chain = find_longest_chain(functions, 'cmd/app:main', { max_depth: 3 });
+
+  t.assert.eq(chain.length, 3, 'three calls');
+  t.assert.ok(chain.truncated, 'truncated');
+});
@@ -0,0 +1,88 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go API catalog functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  build_source_catalog,
+  format_api_catalog,
+  CATALOG_SCHEMA_VERSION
+} from '../../../lib/analysis/catalog.mjs';
+
+const sources = [
+  {
+    filename: 'units/units.go',
+    source: readFileSync('./tests/fixtures/repro_units.go', 'utf-8')
+  },
+  {
+    filename: 'shapes/shapes.go',
+    source: readFileSync('./tests/fixtures/repro.go', 'utf-8')
+  },
+  { filename: 'shapes/shapes_test.go', source: 'package shapes\n\nfunc TestArea() {}\n' },
+  { filename: 'vendor/x/x.go', source: 'package x\n\nfunc X() {}\n' }
+];
+const options = { module: 'example.com/app', generated_at: '2026-01-01T00:00:00.000Z' };
+const catalog = build_source_catalog(sources, options);
+const package_of = (directory) => catalog.packages.find((p) => p.directory === directory);
+const type_of = (directory, name) => package_of(directory).types.find((t) => t.name === name);
+
+// ============ build_source_catalog tests ============
+
+await test('build_source_catalog lists the public API of every package', async (t) => {
+  t.assert.eq(catalog.schema_version, CATALOG_SCHEMA_VERSION, 'Should have the schema version');
+  t.assert.eq(catalog.generated_at, '2026-01-01T00:00:00.000Z', 'Should have the timestamp');
+  t.assert.eq(catalog.packages.map((p) => p.directory).join(', '), 'shapes, units', 'Should sort packages, skipping vendor');
+
+  const shapes = package_of('shapes');
+  t.assert.eq(shapes.import_path, 'example.com/app/shapes', 'Should have the import path');
+  t.assert.eq(shapes.doc, 'Package shapes describes and draws simple shapes.', 'Should have the package doc');
+  t.assert.eq(shapes.files.join(', '), 'shapes/shapes.go', 'Should skip test files');
+
+  const circle = type_of('shapes', 'Circle');
+  t.assert.eq(circle.id, 'shapes:shapes.Circle', 'Should have the search index id');
+  t.assert.eq(circle.signature, 'type Circle struct', 'Should have the signature');
+  t.assert.eq(circle.methods.map((m) => m.name).join(', '), 'Area, Name, Perimeter', 'Should nest methods by name');
+  t.assert.eq(circle.fields.map((f) => f.name).join(', '), 'Label, Radius', 'Should nest exported fields');
+
+  const units = package_of('units');
+  t.assert.eq(units.functions.map((f) => f.name).join(', '), 'Scale', 'Should list functions');
+  t.assert.eq(units.constants.map((c) => c.name).join(', '), 'Suffix', 'Should list constants');
+});
+
+await test('build_source_catalog cross-links entries by id', async (t) => {
+  const radius = type_of('shapes', 'Circle').fields.find((f) => f.name === 'Radius');
+  t.assert.eq(radius.links.join(', '), 'units:units.Length', 'Should link types of imported packages');
+
+  const length = type_of('units', 'Length');
+  t.assert.ok(length.referenced_by.includes('shapes:shapes.Circle.Radius'), 'Should list referring entries');
+  t.assert.ok(length.referenced_by.includes('units:units.Scale'), 'Should list referring functions');
+  t.assert.ok(
+    catalog.packages.every((p) =>
+      p.types.every((type) => type.links.every((id) => id !== type.id))
+    ),
+    'Should not link entries to themselves'
+  );
+});
+
+await test('build_source_catalog is deterministic', async (t) => {
+  const reversed = build_source_catalog([...sources].reverse(), options);
+  t.assert.eq(format_api_catalog(reversed), format_api_catalog(catalog), 'Should not depend on file order');
+  t.assert.ok(format_api_catalog(catalog).endsWith('}\n'), 'Should end with a newline');
+});
+
+await test('build_source_catalog keeps the field groups of structs', async (t) => {
+  const grouped = build_source_catalog(
+    [{ filename: 'server/config.go', source: readFileSync('./tests/fixtures/field_groups.go', 'utf-8') }],
+    options
+  );
+  const [config] = grouped.packages[0].types;
+  t.assert.eq(
+    config.fields.map((f) => `${f.name}:${f.group}:${f.group_header}`),
+    ['Host:0:Network', 'Logger:2:null', 'Port:0:Network', 'ReadTimeout:1:Timeouts for each connection.', 'WriteTimeout:1:Timeouts for each connection.'],
+    'Fields should have their group and section header'
+  );
+  t.assert.eq(type_of('units', 'Length').group, undefined, 'Only fields should have a group');
+});
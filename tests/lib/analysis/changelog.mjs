@@ -0,0 +1,100 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go API changelog functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { build_source_catalog } from '../../../lib/analysis/catalog.mjs';
+import {
+  diff_api_catalogs,
+  format_changelog,
+  get_deprecation_notice
+} from '../../../lib/analysis/changelog.mjs';
+
+const build_catalog = (fixture, extra) =>
+  build_source_catalog(
+    [
+      {
+        filename: 'shapes/shapes.go',
+        source: readFileSync(`./tests/fixtures/${fixture}`, 'utf-8')
+      },
+      { filename: 'shapes/extra.go', source: `package geo\n\n${extra}` }
+    ],
+    { module: 'example.com/app', generated_at: '2024-01-01T00:00:00.000Z' }
+  );
+
+const before = build_catalog(
+  'changed_shapes_v1.go',
+  'const Max = 1\n\ntype Point struct {\n\tX int `json:"x"`\n\tY int\n}\n\n' +
+    'func Move(p Point, dx int) Point { return p }\n'
+);
+const after = build_catalog(
+  'changed_shapes_v2.go',
+  '// Max is the largest size.\n//\n// Deprecated: use Limit instead.\nconst Max = 2\n\n' +
+    'type Point struct {\n\tX int `json:"x,omitempty"`\n\tY int64\n}\n\n' +
+    'func Move(point Point, delta int) Point { return point }\n'
+);
+const diff = diff_api_catalogs(before, after);
+const [shapes] = diff.packages;
+const names = (section) =>
+  shapes[section].map((c) => `${c.name}${c.breaking ? '!' : ''}`).join(' ');
+
+await test('get_deprecation_notice finds the Deprecated paragraph', async (t) => {
+  t.assert.eq(get_deprecation_notice('Old does things.\n\nDeprecated: use\nNew.'), 'Deprecated: use New.', 'Should join the notice lines');
+  t.assert.eq(get_deprecation_notice('Old is not Deprecated: really.'), null, 'Should only match paragraph starts');
+});
+
+await test('diff_api_catalogs groups changes with breaking flags', async (t) => {
+  t.assert.eq(diff.packages.length, 1, 'Should group the changes by package');
+  t.assert.eq(names('added'), 'geo.Circle.Perimeter geo.Shape.Perimeter! geo.Summarize', 'Methods added to interfaces are breaking');
+  t.assert.eq(names('removed'), 'geo.Describe!', 'Removed symbols are breaking');
+  t.assert.eq(
+    names('changed'),
+    'geo.Max geo.Move geo.Point.Y! geo.Scale!',
+    'Values and parameter names do not break the API; types do'
+  );
+  const scale = shapes.changed.find((c) => c.name === 'geo.Scale');
+  t.assert.eq(scale.aspects.map((a) => a.aspect).join(), 'results', 'Should say what changed');
+  t.assert.eq(names('deprecated'), 'geo.Max', 'Should list the newly deprecated symbols');
+  t.assert.eq(diff.summary.breaking, 4, 'Should count the breaking changes');
+  t.assert.eq(diff_api_catalogs(after, after).packages.length, 0, 'Identical catalogs have no changes');
+});
+
+await test('format_changelog writes stable Markdown', async (t) => {
+  const markdown = format_changelog(diff, { title: 'Release 2.0' });
+  t.assert.ok(markdown.startsWith('# Release 2.0\n\n3 added, 4 changed'), 'Should start with the title and summary');
+  t.assert.ok(markdown.includes('## example.com/app/shapes\n\n### Added\n'), 'Should head packages with their import path');
+  t.assert.ok(
+    markdown.includes('- **Breaking:** `geo.Scale` (function): `func Scale(c Circle, factor float64) Circle` → `func Scale(c Circle, factor float64) *Circle`'),
+    'Should flag breaking changes'
+  );
+  t.assert.ok(markdown.includes('- `geo.Max` (const): Deprecated: use Limit instead.'), 'Should include deprecation notices');
+  t.assert.eq(markdown, format_changelog(diff_api_catalogs(before, after), { title: 'Release 2.0' }), 'Should be stable');
+});
+
+await test('format_changelog applies templates', async (t) => {
+  const markdown = format_changelog(diff, {
+    template: { package: '## Package {package}', removed: '* REMOVED {name}', breaking: '[!] ' }
+  });
+  t.assert.ok(markdown.includes('## Package geo\n'), 'Should replace the package format');
+  t.assert.ok(markdown.includes('* REMOVED geo.Describe\n'), 'Should replace entry formats');
+  t.assert.ok(markdown.includes('- [!] `geo.Scale`'), 'Should replace the breaking marker');
+
+  let message = '';
+  try {
+    format_changelog(diff, { template: { entry: '{name}' } });
+  } catch (error) {
+    message = error.message;
+  }
+  t.assert.ok(message.includes('Unknown changelog template format: entry'), 'Should reject unknown formats');
+
+  message = '';
+  try {
+    diff_api_catalogs({ ...before, schema_version: 99 }, after);
+  } catch (error) {
+    message = error.message;
+  }
+  t.assert.ok(message.includes('Unsupported catalog schema version 99'), 'Should check the schema version');
+});
@@ -0,0 +1,98 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for symbol level change functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  diff_revisions,
+  diff_revision_sources,
+  create_memory_source
+} from '../../../lib/analysis/changes.mjs';
+
+const load_fixture = (filename) =>
+  readFileSync(`./tests/fixtures/${filename}`, 'utf-8');
+
+const source = create_memory_source({
+  v1: [
+    { filename: 'geo/shapes.go', source: load_fixture('changed_shapes_v1.go') },
+    { filename: 'geo/legacy.go', source: load_fixture('changed_legacy.go') },
+    { filename: 'geo/shapes_test.go', source: 'package geo\n' }
+  ],
+  v2: [
+    { filename: 'geo/shapes.go', source: load_fixture('changed_shapes_v2.go') },
+    { filename: 'geo/polygon.go', source: load_fixture('changed_polygon.go') },
+    { filename: 'plot/plot.go', source: load_fixture('changed_plot.go') }
+  ]
+});
+const result = await diff_revisions(source, 'v1', 'v2');
+const change_of = (key) => result.symbols.find((s) => s.key === key);
+
+// ============ diff_revisions tests ============
+
+await test('diff_revisions lists added and removed files and packages', async (t) => {
+  const status = (filename) =>
+    result.files.find((f) => f.filename === filename).status;
+  t.assert.eq(status('geo/legacy.go'), 'deleted', 'legacy.go was removed');
+  t.assert.eq(status('geo/polygon.go'), 'added', 'polygon.go was added');
+  t.assert.eq(status('geo/shapes.go'), 'modified', 'shapes.go was modified');
+  t.assert.ok(!result.files.some((f) => f.filename.endsWith('_test.go')), 'Should skip test files');
+  t.assert.eq(result.packages.length, 1, 'Only plot is a new package');
+  t.assert.eq(result.packages[0].status, 'added', 'plot was added');
+});
+
+await test('diff_revisions compares symbols and flags breaking changes', async (t) => {
+  t.assert.eq(change_of('geo:Square.Area').change, 'removed', 'Methods of removed files are removed');
+  t.assert.ok(change_of('geo:Square').breaking, 'Removing an exported type breaks');
+  t.assert.eq(change_of('geo:Polygon').change, 'added', 'Types of added files are added');
+  t.assert.ok(!change_of('geo:Polygon').breaking, 'Additions do not break');
+  t.assert.ok(change_of('geo:Shape').breaking, 'Adding an interface method breaks');
+  t.assert.ok(change_of('geo:Scale').breaking, 'Changing results breaks');
+  t.assert.ok(!change_of('geo:Circle.Area').breaking, 'Body changes do not break');
+  t.assert.eq(change_of('geo:Unit').changes[0].aspect, 'doc', 'Doc changes are reported');
+  t.assert.ok(!change_of('geo:Circle'), 'Unchanged symbols are not listed');
+});
+
+await test('diff_revisions detects renamed symbols', async (t) => {
+  const renamed = change_of('geo:Summarize');
+  t.assert.eq(renamed.change, 'renamed', 'Describe was renamed');
+  t.assert.eq(renamed.old_name, 'Describe', 'Should keep the old name');
+  t.assert.ok(renamed.breaking, 'Renaming an exported function breaks');
+  t.assert.ok(!change_of('geo:formatFloat').breaking, 'Renaming an unexported function does not break');
+  t.assert.ok(!change_of('geo:Describe'), 'The old name is not reported as removed');
+});
+
+await test('diff_revision_sources moves methods with renamed and moved types', async (t) => {
+  const before = [
+    {
+      filename: 'store/item.go',
+      source: 'package store\n\ntype Item struct{ ID int }\n\nfunc (i *Item) Key() int { return i.ID }\n'
+    }
+  ];
+  const after = [
+    {
+      filename: 'model/entry.go',
+      source: 'package model\n\ntype Entry struct{ ID int }\n\nfunc (i *Entry) Key() int { return i.ID }\n'
+    }
+  ];
+  const diff = diff_revision_sources(before, after);
+  const method = diff.symbols.find((s) => s.name === 'Entry.Key');
+  t.assert.eq(method.change, 'renamed', 'The method follows its type');
+  t.assert.eq(method.old_name, 'Item.Key', 'Should keep the old name');
+  t.assert.eq(method.old_directory, 'store', 'Should keep the old directory');
+  t.assert.eq(diff.packages.map((p) => p.status).join(','), 'added,removed', 'Packages were replaced');
+  t.assert.ok(diff.packages[1].breaking, 'Removing a package with exported types breaks');
+});
+
+await test('diff_revision_sources keeps only breaking changes when asked', async (t) => {
+  const before = [{ filename: 'geo/shapes.go', source: load_fixture('changed_shapes_v1.go') }];
+  const after = [{ filename: 'geo/shapes.go', source: load_fixture('changed_shapes_v2.go') }];
+  const diff = diff_revision_sources(before, after, { breaking_only: true });
+  t.assert.ok(diff.symbols.length > 0, 'Should keep breaking symbols');
+  t.assert.ok(diff.symbols.every((s) => s.breaking), 'Should only keep breaking symbols');
+  const scale = diff.symbols.find((s) => s.name === 'Scale');
+  t.assert.eq(scale.changes.map((c) => c.aspect).join(','), 'results', 'Should drop doc and body changes');
+  t.assert.ok(diff.summary.symbols_changed > diff.symbols.length, 'The summary counts every change');
+});
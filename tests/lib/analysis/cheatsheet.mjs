@@ -0,0 +1,84 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go API cheat sheet functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  build_source_cheatsheets,
+  classify_cheatsheet_method
+} from '../../../lib/analysis/cheatsheet.mjs';
+import { parse_go_file } from '../../../lib/analysis/golang.mjs';
+
+const sources = [
+  ['cheatsheet.go', 'cache/cache.go'],
+  ['cheatsheet_test.go', 'cache/cache_test.go']
+].map(([fixture, filename]) => ({
+  filename,
+  source: readFileSync(`./tests/fixtures/${fixture}`, 'utf-8')
+}));
+
+const [full] = build_source_cheatsheets(sources, { max_lines: 0 }).sheets;
+const cache = full.types.find((t) => t.name === 'Cache');
+const names = (section) => section.entries.map((e) => e.name);
+
+// ============ classify_cheatsheet_method tests ============
+
+await test('classify_cheatsheet_method separates mutations from queries', async (t) => {
+  const file = parse_go_file(sources[0].source, 'cache/cache.go');
+  const method = (name) => file.functions.find((f) => f.name === name);
+  t.assert.eq(classify_cheatsheet_method(method('SetName')), 'mutation', 'Should classify setters');
+  t.assert.eq(classify_cheatsheet_method(method('Touch')), 'mutation', 'Should classify receiver field writes');
+  t.assert.eq(classify_cheatsheet_method(method('Put')), 'mutation', 'Should classify mutating names');
+  t.assert.eq(classify_cheatsheet_method(method('Name')), 'query', 'Should classify getters');
+  t.assert.eq(classify_cheatsheet_method(method('Len')), 'query', 'Should classify methods with results');
+});
+
+// ============ build_source_cheatsheets tests ============
+
+await test('build_source_cheatsheets groups the methods of a type', async (t) => {
+  t.assert.eq(full.name, 'cache', 'Should build the sheet of the package');
+  t.assert.eq(names(cache.groups.construction).join(','), 'Must,New', 'Should list the constructors');
+  t.assert.eq(names(cache.groups.mutation).join(','), 'Put,SetName,Touch', 'Should list the mutations');
+  t.assert.eq(names(cache.groups.query).join(','), 'Get,Len,Name,String', 'Should list the queries');
+  t.assert.ok(!names(cache.groups.mutation).includes('evict'), 'Should leave out unexported methods');
+});
+
+await test('build_source_cheatsheets lists free functions and interfaces', async (t) => {
+  t.assert.eq(names(full.functions).join(','), 'Keys,Version', 'Should not list constructors as functions');
+  const store = full.types.find((t) => t.name === 'Store');
+  t.assert.eq(names(store.groups.query).join(','), 'Keys,Load', 'Should classify interface methods by results');
+  t.assert.eq(names(store.groups.mutation).join(','), 'Save', 'Should classify interface methods by name');
+});
+
+await test('build_source_cheatsheets formats one-line entries', async (t) => {
+  const get = cache.groups.query.entries.find((e) => e.name === 'Get');
+  t.assert.eq(get.signature, 'Get(key string) (string, bool)', 'Should format the signature');
+  t.assert.eq(get.synopsis, 'Get returns the value of a key.', 'Should keep the first sentence');
+  t.assert.eq(get.calls, 1, 'Should count calls from tests');
+  t.assert.ok(full.lines.includes('    Get(key string) (string, bool)  // Get returns the value of a key.'), 'Should format the line');
+  t.assert.ok(full.lines.includes('    Len() int'), 'Should format undocumented entries');
+  t.assert.eq(full.omitted, 0, 'Should keep every entry without a limit');
+});
+
+await test('build_source_cheatsheets prioritizes within the line limit', async (t) => {
+  const [sheet] = build_source_cheatsheets(sources, { max_lines: 14 }).sheets;
+  t.assert.ok(sheet.lines.length <= 14, 'Should keep within the limit');
+  const card = sheet.types.find((t) => t.name === 'Cache');
+  t.assert.eq(names(card.groups.construction).join(','), 'Must,New', 'Should keep constructors first');
+  t.assert.eq(names(sheet.functions).join(','), 'Keys,Version', 'Should keep frequently called functions');
+  t.assert.eq(sheet.omitted, 10, 'Should count the entries left out');
+  t.assert.eq(sheet.lines[sheet.lines.length - 1], '... 10 more in other types and groups', 'Should mention the entries left out');
+});
+
+await test('build_source_cheatsheets rejects invalid limits', async (t) => {
+  let error = null;
+  try {
+    build_source_cheatsheets(sources, { max_lines: -1 });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should throw on a negative limit');
+});
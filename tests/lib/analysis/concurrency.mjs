@@ -6,14 +6,22 @@
  */
 
 import { test } from 'st';
+import { readFileSync } from 'fs';
 import { query } from '../../../lib/db.mjs';
 import { insert_or_update_project } from '../../../lib/model/project.mjs';
 import { insert_or_update_entity } from '../../../lib/model/entity.mjs';
 import {
   analyze_function_concurrency,
   analyze_project_concurrency,
+  build_concurrency_model,
+  get_channel_direction,
+  get_channel_element_type,
   CONCURRENCY_PATTERNS
 } from '../../../lib/analysis/concurrency.mjs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
 
 // Counter to ensure unique project names
 let test_counter = 0;
@@ -563,5 +571,66 @@ await test('analyze_project_concurrency groups patterns by type', async (t) => {
   }
 });
 
+// ============ Go channel topology tests ============
+
+const channel_source = readFileSync('./tests/fixtures/channels.go', 'utf-8');
+const channel_model = build_concurrency_model(
+  group_go_packages([parse_go_file(channel_source, 'pipeline/channels.go')])[0]
+);
+
+const find_channel = (id) => channel_model.channels.find((c) => c.id === id);
+
+await test('get_channel_direction reads directional channel types', async (t) => {
+  t.assert.eq(get_channel_direction('chan int'), 'both', 'Bidirectional');
+  t.assert.eq(get_channel_direction('chan<- int'), 'send', 'Send-only');
+  t.assert.eq(get_channel_direction('<-chan int'), 'receive', 'Receive-only');
+  t.assert.eq(get_channel_element_type('<-chan []byte'), '[]byte', 'Element type');
+});
+
+await test('build_concurrency_model finds declared channels', async (t) => {
+  const jobs = find_channel('Run:jobs');
+  t.assert.ok(jobs, 'Should find local channel');
+  t.assert.eq(jobs.kind, 'local', 'Should be local');
+  t.assert.eq(jobs.element_type, 'int', 'Should record element type');
+  t.assert.eq(find_channel('Run:squares').buffer, 'n', 'Should record buffer size');
+  t.assert.eq(find_channel('events').kind, 'package', 'Should find package channel');
+  t.assert.eq(find_channel('Worker.results').kind, 'field', 'Should find field channel');
+});
+
+await test('build_concurrency_model follows channels through calls', async (t) => {
+  const jobs = find_channel('Run:jobs');
+  t.assert.eq(jobs.aliases.join(','), 'produce:out,square:in', 'Should alias parameters');
+  t.assert.eq(jobs.senders.join(','), 'produce', 'produce sends on jobs');
+  t.assert.eq(jobs.receivers.join(','), 'square', 'square receives from jobs');
+  t.assert.eq(jobs.closers.join(','), 'produce', 'produce closes jobs');
+  t.assert.eq(jobs.direction, 'both', 'Should be used in both directions');
+  t.assert.ok(!find_channel('produce:out'), 'Aliases should not be separate channels');
+});
+
+await test('build_concurrency_model records goroutine launch sites', async (t) => {
+  const launches = channel_model.goroutines.filter((g) => g.function === 'Run');
+  t.assert.eq(
+    launches.map((g) => g.target).join(','),
+    'produce,square,func literal',
+    'Should find named and anonymous goroutines'
+  );
+  t.assert.eq(launches[1].channels.join(','), 'Run:jobs,Run:squares', 'Should record channel arguments');
+  const closure_ops = channel_model.operations.filter((op) => op.goroutine === launches[2].line);
+  t.assert.eq(closure_ops.length, 2, 'Should attribute closure operations to the goroutine');
+});
+
+await test('build_concurrency_model records direction from usage', async (t) => {
+  t.assert.eq(find_channel('Run:done').direction, 'receive', 'done is only received from');
+  t.assert.eq(find_channel('Leak:stuck').direction, 'send', 'stuck is only sent to');
+  const select_ops = channel_model.operations.filter((op) => op.in_select);
+  t.assert.eq(select_ops.length, 2, 'Should flag operations inside select');
+});
+
+await test('build_concurrency_model warns about channels without a receiver', async (t) => {
+  t.assert.eq(channel_model.warnings.length, 1, 'Should have one warning');
+  t.assert.eq(channel_model.warnings[0].type, 'no_receiver', 'Should be no_receiver');
+  t.assert.eq(channel_model.warnings[0].channel, 'Leak:stuck', 'Should flag stuck');
+});
+
 // Final cleanup
 await cleanup_all_test_projects();
@@ -0,0 +1,52 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go build constraint functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file } from '../../../lib/analysis/golang.mjs';
+import { summarize_build_constraints } from '../../../lib/analysis/constraints.mjs';
+
+const files = [
+  'constraint_arm64.go',
+  'constraint_linux.go',
+  'constraint_linux_amd64.go',
+  'constraint_linux_test.go',
+  'constraint_unix.go',
+  'constraint_windows.go'
+].map((name) =>
+  parse_go_file(readFileSync(`./tests/fixtures/${name}`, 'utf-8'), `poll/${name}`)
+);
+files.push(parse_go_file('package poll\n', 'poll/poll.go'));
+
+// ============ summarize_build_constraints tests ============
+
+await test('summarize_build_constraints lists constrained files', async (t) => {
+  const result = summarize_build_constraints(files);
+  t.assert.eq(result.target, null, 'Should have no target');
+  t.assert.ok(!result.files.some((f) => f.filename === 'poll/poll.go'), 'Should skip unconstrained files');
+  t.assert.eq(result.summary.total_files, 7, 'Should count every file');
+  t.assert.eq(result.summary.constrained_files, 6, 'Should count constrained files');
+  t.assert.eq(result.summary.filename_constrained, 5, 'Should count suffix constrained files');
+  t.assert.eq(result.summary.by_goos.linux, 3, 'Should count files by GOOS');
+  t.assert.eq(result.summary.test_files, 1, 'Should count test files');
+});
+
+await test('summarize_build_constraints evaluates a target', async (t) => {
+  const result = summarize_build_constraints(files, { goos: 'linux', goarch: 'amd64' });
+  const included = result.files.filter((f) => f.included).map((f) => f.filename);
+  t.assert.eq(
+    included.join(','),
+    'poll/constraint_linux.go,poll/constraint_linux_amd64.go,poll/constraint_linux_test.go,poll/constraint_unix.go,poll/poll.go',
+    'Should include the files built for linux/amd64'
+  );
+  t.assert.ok(result.target.tags.includes('unix'), 'Should set the unix tag');
+
+  const purego = summarize_build_constraints(files, { goos: 'windows', goarch: 'arm64', tags: ['purego'] });
+  const windows = purego.files.filter((f) => f.included).map((f) => f.filename);
+  t.assert.ok(windows.includes('poll/constraint_windows.go'), 'Should honour extra tags');
+  t.assert.ok(!windows.includes('poll/constraint_arm64.go'), 'Should apply the build line and the suffix');
+  t.assert.eq(purego.summary.excluded, 5, 'Should count excluded files');
+});
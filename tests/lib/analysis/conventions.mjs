@@ -0,0 +1,157 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go API convention analysis functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  check_context_params,
+  is_context_type,
+  get_context_qualifiers,
+  find_interface_guideline_issues,
+  check_error_results
+} from '../../../lib/analysis/conventions.mjs';
+
+const [context_pkg] = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/context_params.go', 'utf-8'),
+    'fetch/fetch.go'
+  )
+]);
+
+// ============ is_context_type tests ============
+
+await test('is_context_type resolves the context import', async (t) => {
+  const qualifiers = get_context_qualifiers(context_pkg.imports).get(
+    'fetch/fetch.go'
+  );
+  t.assert.ok(qualifiers.has('context') && qualifiers.has('stdctx'), 'Should record both imports');
+  t.assert.ok(is_context_type('context.Context', qualifiers), 'Should match context.Context');
+  t.assert.ok(is_context_type('stdctx.Context', qualifiers), 'Should match a renamed import');
+  t.assert.ok(!is_context_type('other.Context', qualifiers), 'Should not match other packages');
+  t.assert.ok(!is_context_type('Context', qualifiers), 'Should not match a local type');
+  t.assert.ok(is_context_type('Context', new Set(['']), new Set()), 'Should match a dot import');
+  t.assert.ok(is_context_type('Ctx', qualifiers, new Set(['Ctx'])), 'Should match aliases');
+});
+
+// ============ check_context_params tests ============
+
+await test('check_context_params flags misplaced context parameters', async (t) => {
+  const result = check_context_params(context_pkg);
+  const misplaced = result.misplaced.map((m) => m.symbol).sort();
+  t.assert.eq(
+    misplaced.join(','),
+    'Callback,Client.Retry,Fetcher.Store,Post,helper',
+    'Should flag functions, methods, interface methods and func types'
+  );
+
+  const post = result.misplaced.find((m) => m.symbol === 'Post');
+  t.assert.eq(post.position, 2, 'Should report the 1-based position');
+  t.assert.eq(post.parameter_count, 3, 'Should report the parameter count');
+  t.assert.eq(post.parameter, 'ctx', 'Should report the parameter name');
+  t.assert.eq(post.kind, 'function', 'Should report the kind');
+});
+
+await test('check_context_params ignores correct and context-free functions', async (t) => {
+  const result = check_context_params(context_pkg);
+  t.assert.ok(result.with_context.includes('Get'), 'Get takes a context');
+  t.assert.ok(result.with_context.includes('Client.Do'), 'Should resolve the alias');
+  t.assert.ok(!result.with_context.includes('Parse'), 'Parse does not take a context');
+  t.assert.ok(!result.misplaced.some((m) => m.symbol === 'Get' || m.symbol === 'Client.Do'), 'Should not flag correct ones');
+});
+
+await test('check_context_params allows configured types before the context', async (t) => {
+  const result = check_context_params(context_pkg, {
+    allow_types_before: ['*testing.T']
+  });
+  t.assert.ok(!result.misplaced.some((m) => m.symbol === 'helper'), 'Should allow *testing.T first');
+  t.assert.eq(result.misplaced.length, 4, 'Should still flag the others');
+});
+
+// ============ find_interface_guideline_issues tests ============
+
+const guideline_packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/interface_guideline.go', 'utf-8'),
+    'store/store.go'
+  ),
+  parse_go_file(
+    'package app\n\nimport "example.com/app/store"\n\n' +
+      'func Make() store.Getter { return &store.Store{} }\n',
+    'app/app.go'
+  )
+]);
+
+await test('find_interface_guideline_issues flags concrete parameters used through methods', async (t) => {
+  const issues = find_interface_guideline_issues(guideline_packages, {
+    guideline: 'accept_interfaces'
+  });
+  t.assert.eq(
+    issues.map((i) => `${i.symbol}:${i.parameter}`).join(','),
+    'Lookup:s,Copy:dst,Copy:src,Close:s',
+    'Should skip parameters whose fields are read or which are passed on'
+  );
+  t.assert.eq(issues[0].interfaces.join(','), 'store.Getter,store.Repository', 'Should suggest the satisfied interfaces, smallest first');
+  t.assert.eq(issues[1].interfaces.join(','), 'store.Repository', 'Should only suggest interfaces declaring the methods called');
+  t.assert.eq(issues[3].interfaces.length, 0, 'Should suggest no interface when none declares the methods');
+  t.assert.ok(issues[3].message.includes('an interface with Reset'), 'Should name the methods an interface needs');
+});
+
+await test('find_interface_guideline_issues flags interfaces always returned as one struct', async (t) => {
+  const issues = find_interface_guideline_issues(guideline_packages, {
+    guideline: 'return_structs'
+  });
+  t.assert.eq(
+    issues.map((i) => `${i.symbol}:${i.concrete}`).join(','),
+    'Make:*store.Store,NewStore:*Store,Open:*Store',
+    'Should skip factories of several types, unexported types and error results'
+  );
+  t.assert.eq(issues[2].position, 1, 'Should give the position of the result');
+  let error = null;
+  try {
+    find_interface_guideline_issues(guideline_packages, { guideline: 'other' });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject unknown guidelines');
+});
+
+// ============ check_error_results tests ============
+
+const [error_pkg] = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/error_results.go', 'utf-8'),
+    'calc/calc.go'
+  )
+]);
+
+await test('check_error_results flags errors not returned last', async (t) => {
+  const result = check_error_results(error_pkg);
+  const misplaced = Object.fromEntries(result.misplaced.map((m) => [m.symbol, m]));
+  t.assert.eq(
+    Object.keys(misplaced).sort().join(','),
+    'Handler,Merge,Parse,Solver.Check,state.reset',
+    'Should flag functions, methods, interface methods and function types'
+  );
+  t.assert.eq(misplaced.Parse.position, 1, 'Should give the 1-based error position');
+  t.assert.eq(misplaced.Parse.result_count, 2, 'Should give the number of results');
+  t.assert.eq(misplaced.Parse.message, 'Parse returns error as result 1 of 2; it should be the last', 'Should explain the issue');
+  t.assert.eq(misplaced['state.reset'].exported, false, 'Should mark unexported methods');
+  t.assert.eq(misplaced.Handler.kind, 'func_type', 'Should check function types');
+});
+
+await test('check_error_results flags several error results', async (t) => {
+  const result = check_error_results(error_pkg);
+  const merge = result.misplaced.find((m) => m.symbol === 'Merge');
+  t.assert.eq(merge.issue, 'multiple', 'Should flag several errors even when one is last');
+  t.assert.eq(merge.positions.join(','), '2,3', 'Should give every error position');
+  t.assert.ok(result.with_error.includes('Divide'), 'Should list compliant functions returning an error');
+  t.assert.ok(result.with_error.includes('Validate'), 'Should accept a single error result');
+  t.assert.ok(!result.with_error.includes('Sum'), 'Should leave functions without an error');
+});
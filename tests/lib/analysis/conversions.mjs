@@ -0,0 +1,103 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go conversion and type assertion functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_type_conversions,
+  list_unchecked_assertions,
+  get_conversion_types,
+  find_conversions_between
+} from '../../../lib/analysis/conversions.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/assertions.go', 'utf-8'),
+    'events/events.go'
+  ),
+  parse_go_file(
+    'package report\n\nfunc Wait(n int) events.Millis {\n\treturn events.Millis(n)\n}\n',
+    'report/report.go'
+  )
+]);
+const functions = find_type_conversions(packages);
+const function_of = (name) => functions.find((f) => f.name === name);
+
+// ============ find_type_conversions tests ============
+
+await test('find_type_conversions lists conversions per function', async (t) => {
+  const elapsed = function_of('Elapsed');
+  t.assert.eq(elapsed.conversions.map((c) => c.type).join(','), 'Millis,[]byte,int64,string', 'Should find every conversion');
+  t.assert.eq(function_of('Wait').conversions[0].type, 'events.Millis', 'Should find qualified conversions');
+  t.assert.ok(get_conversion_types(packages, packages[1]).has('events.Click'), 'Other packages types are qualified');
+  t.assert.ok(!function_of('Name'), 'Functions without conversions or assertions are skipped');
+});
+
+await test('find_type_conversions counts unchecked assertions', async (t) => {
+  t.assert.eq(function_of('Handle').unchecked_assertions, 2, 'Handle asserts twice without checking');
+  t.assert.eq(function_of('SafeHandle').unchecked_assertions, 0, 'SafeHandle uses comma-ok');
+  t.assert.eq(function_of('SafeHandle').assertions.length, 2, 'Checked assertions are still listed');
+  t.assert.eq(function_of('Describe').assertions[0].form, 'switch', 'Type switches are checked');
+});
+
+// ============ list_unchecked_assertions tests ============
+
+await test('list_unchecked_assertions suggests the comma-ok form', async (t) => {
+  const unchecked = list_unchecked_assertions(functions);
+  t.assert.eq(unchecked.length, 3, 'Handle and Lookup can panic');
+  const lookup = unchecked.find((u) => u.function === 'Lookup');
+  t.assert.eq(lookup.expression, 'values[key]', 'Should keep the operand');
+  t.assert.eq(lookup.suggestion, 'v, ok := values[key].(Click)', 'Should suggest comma-ok');
+  t.assert.ok(lookup.message.includes('panics'), 'Should explain the risk');
+});
+
+// ============ find_conversions_between tests ============
+
+const temperature = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/temperature.go', 'utf-8'),
+    'temperature/temperature.go'
+  )
+]);
+
+await test('find_conversions_between finds conversions in both directions', async (t) => {
+  const { conversions, unresolved } = find_conversions_between(temperature, 'Celsius', 'Fahrenheit');
+  t.assert.eq(
+    conversions.map((c) => `${c.function}:${c.direction}`).join(', '),
+    'Celsius.ToFahrenheit:forward, Fahrenheit.ToCelsius:reverse, Relabel:forward, Average:reverse, BoilingFahrenheit:forward',
+    'Should find every Celsius and Fahrenheit conversion'
+  );
+  t.assert.eq(unresolved, 0, 'Should infer every operand type');
+
+  const relabel = conversions.find((c) => c.function === 'Relabel');
+  t.assert.eq(relabel.from, 'temperature.Celsius', 'Should qualify the source type');
+  t.assert.eq(relabel.text, 'Fahrenheit(r.Value)', 'Should have the conversion text');
+  t.assert.eq(relabel.line, 34, 'Should have the line');
+  t.assert.eq(relabel.column, 9, 'Should have the column');
+  t.assert.eq(relabel.end_column, 28, 'Should end after the closing parenthesis');
+});
+
+await test('find_conversions_between infers operand types', async (t) => {
+  const find = (from, to) =>
+    find_conversions_between(temperature, from, to).conversions.map((c) => c.function).join(', ');
+  t.assert.eq(find('temperature.Celsius', 'Kelvin'), 'Absolute', 'Should accept qualified types');
+  t.assert.eq(find('float64', 'Celsius'), 'FromRaw', 'Should accept predeclared types');
+  t.assert.eq(find('Kelvin', 'Fahrenheit'), '', 'Should skip other types');
+});
+
+await test('find_conversions_between follows method results and variables', async (t) => {
+  const pkg = temperature[0];
+  const only = (name) => {
+    const fn = pkg.functions.find((f) => f.name === name);
+    return find_conversions_between([{ ...pkg, functions: [fn] }], 'Celsius', 'Fahrenheit');
+  };
+  t.assert.eq(only('Nested').conversions.length, 0, 'Celsius(c).ToFahrenheit() is a Fahrenheit');
+  t.assert.eq(only('Average').conversions[0].from, 'temperature.Fahrenheit', 'mean is a Fahrenheit');
+});
@@ -0,0 +1,72 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go function coupling functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  compute_fan_metrics,
+  find_top_coupled
+} from '../../../lib/analysis/coupling.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/coupling.go', 'utf-8'),
+    'app/main.go'
+  ),
+  parse_go_file(
+    'package app\n\nimport "testing"\n\nfunc TestNormalize(t *testing.T) {\n\tnormalize("x")\n}\n',
+    'app/main_test.go'
+  )
+]);
+const metrics_of = (functions, id) => functions.find((fn) => fn.id === id);
+
+// ============ compute_fan_metrics tests ============
+
+await test('compute_fan_metrics counts distinct callees and callers', async (t) => {
+  const functions = compute_fan_metrics(packages);
+  const main = metrics_of(functions, 'app:main');
+  t.assert.eq(main.fan_out, 4, 'main calls four functions');
+  t.assert.eq(main.fan_in, 0, 'Nothing calls main');
+  t.assert.eq(
+    main.callees.join(' '),
+    'app:Server.Start app:load app:newServer app:report',
+    'Should list the callees'
+  );
+
+  const logf = metrics_of(functions, 'app:logf');
+  t.assert.eq(logf.fan_in, 4, 'logf is called from four functions');
+  t.assert.eq(logf.fan_out, 0, 'fmt.Printf is outside the project');
+  t.assert.eq(metrics_of(functions, 'app:Server.Start').kind, 'method', 'Should include methods');
+  t.assert.eq(metrics_of(functions, 'app:unused').coupling, 0, 'Isolated functions have no coupling');
+});
+
+await test('compute_fan_metrics leaves out test files unless asked for', async (t) => {
+  const functions = compute_fan_metrics(packages);
+  t.assert.ok(!metrics_of(functions, 'app:TestNormalize'), 'Tests are not functions');
+  t.assert.eq(metrics_of(functions, 'app:normalize').fan_in, 2, 'Tests are not callers');
+
+  const with_tests = compute_fan_metrics(packages, { include_tests: true });
+  t.assert.eq(metrics_of(with_tests, 'app:normalize').fan_in, 3, 'Should count tests when asked');
+});
+
+// ============ find_top_coupled tests ============
+
+await test('find_top_coupled ranks by combined fan-in and fan-out', async (t) => {
+  const top = find_top_coupled(packages, { limit: 3 });
+  t.assert.eq(
+    top.map((fn) => fn.id).join(' '),
+    'app:Server.Start app:logf app:main',
+    'Functions both calling and called rank first among ties'
+  );
+  t.assert.ok(
+    !find_top_coupled(packages).some((fn) => fn.id === 'app:unused'),
+    'Isolated functions are not listed'
+  );
+});
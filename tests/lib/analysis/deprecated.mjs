@@ -0,0 +1,55 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go deprecated standard library functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_deprecated_calls,
+  format_deprecation
+} from '../../../lib/analysis/deprecated.mjs';
+
+// ============ format_deprecation tests ============
+
+await test('format_deprecation names the replacement', async (t) => {
+  t.assert.eq(
+    format_deprecation('ioutil.ReadFile', { since: '1.16', replacement: 'os.ReadFile' }),
+    'ioutil.ReadFile is deprecated since Go 1.16: use os.ReadFile',
+    'Should give the version and the replacement'
+  );
+  t.assert.ok(
+    format_deprecation('x509.DecryptPEMBlock', { since: '1.16', replacement: null }).includes(
+      'no replacement'
+    ),
+    'Should say when there is no replacement'
+  );
+});
+
+// ============ find_deprecated_calls tests ============
+
+await test('find_deprecated_calls resolves calls through imports', async (t) => {
+  const calls = find_deprecated_calls(
+    readFileSync('./tests/fixtures/deprecated_calls.go', 'utf-8'),
+    'config/config.go'
+  );
+  t.assert.eq(
+    calls.map((c) => `${c.function}@${c.line}`).join(','),
+    'io/ioutil.ReadFile@13,math/rand.Seed@21,strings.Title@27',
+    'Should find the deprecated calls, skipping comments, strings and local values'
+  );
+  t.assert.eq(calls[0].call, 'iu.ReadFile', 'Should follow renamed imports');
+  t.assert.eq(calls[0].replacement, 'os.ReadFile', 'Should give the replacement');
+  t.assert.eq(calls[0].column, 15, 'Should give the column of the call');
+  t.assert.eq(calls[0].enclosing.name, 'Load', 'Should give the enclosing function');
+  t.assert.eq(calls[1].since, '1.20', 'Should give the version deprecating the function');
+});
+
+await test('find_deprecated_calls ignores files without deprecated imports', async (t) => {
+  const calls = find_deprecated_calls(
+    'package util\n\nimport "os"\n\nfunc Read() { os.ReadFile("x") }\n',
+    'util/util.go'
+  );
+  t.assert.eq(calls.length, 0, 'Should not report current functions');
+});
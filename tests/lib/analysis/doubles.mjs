@@ -0,0 +1,86 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go test double functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_source_test_doubles,
+  summarize_test_doubles,
+  DOUBLE_PACKAGE_PATTERN
+} from '../../../lib/analysis/doubles.mjs';
+
+const sources = [
+  ['doubles_store.go', 'store/store.go'],
+  ['doubles_store_test.go', 'store/store_test.go'],
+  ['doubles_external_test.go', 'store/external_test.go'],
+  ['doubles_mocks.go', 'store/mocks/mocks.go']
+].map(([fixture, filename]) => ({
+  filename,
+  source: readFileSync(`./tests/fixtures/${fixture}`, 'utf-8')
+}));
+
+const report = find_source_test_doubles(sources);
+const find_interface = (name) =>
+  report.interfaces.find((i) => i.interface === name);
+
+// ============ find_source_test_doubles tests ============
+
+await test('find_source_test_doubles finds doubles in test files', async (t) => {
+  const store = find_interface('Store');
+  t.assert.eq(store.has_double, true, 'Should find a double for Store');
+  t.assert.eq(store.doubles.length, 1, 'Should not count the production type');
+  t.assert.eq(store.doubles[0].type, 'fakeStore', 'Should name the double');
+  t.assert.eq(store.doubles[0].filename, 'store/store_test.go', 'Should report the file of the double');
+  t.assert.eq(store.doubles[0].pointer_only, true, 'Should flag pointer receivers');
+});
+
+await test('find_source_test_doubles qualifies types for external test packages', async (t) => {
+  const notifier = find_interface('Notifier');
+  t.assert.eq(notifier.has_double, true, 'Should find a double in package store_test');
+  t.assert.eq(notifier.doubles[0].package, 'store_test', 'Should report the package of the double');
+  const cache = find_interface('Cache');
+  t.assert.eq(cache.has_double, false, 'Should not match unqualified types from another package');
+});
+
+await test('find_source_test_doubles finds doubles in mocks packages', async (t) => {
+  const clock = find_interface('Clock');
+  t.assert.eq(clock.doubles.length, 1, 'Should find the mock');
+  t.assert.eq(clock.doubles[0].directory, 'store/mocks', 'Should report the directory of the mock');
+  t.assert.eq(clock.doubles[0].pointer_only, false, 'Should accept value receivers');
+  t.assert.ok(!find_interface('Ticker'), 'Should not check interfaces of mocks packages');
+});
+
+await test('find_source_test_doubles leaves out unexported and empty interfaces', async (t) => {
+  t.assert.ok(!find_interface('reader'), 'Should skip unexported interfaces');
+  t.assert.ok(!find_interface('Any'), 'Should not check empty interfaces');
+  t.assert.eq(report.skipped.length, 1, 'Should list the skipped interface');
+  t.assert.eq(report.skipped[0], 'store.Any', 'Should name the skipped interface');
+});
+
+await test('find_source_test_doubles lists only missing doubles on request', async (t) => {
+  const missing = find_source_test_doubles(sources, { missing_only: true });
+  t.assert.eq(missing.interfaces.length, 1, 'Should list one interface');
+  t.assert.eq(missing.interfaces[0].interface, 'Cache', 'Should list the untested interface');
+  t.assert.eq(missing.summary.interfaces, 4, 'Should summarize every interface');
+});
+
+// ============ summarize_test_doubles tests ============
+
+await test('summarize_test_doubles computes the coverage', async (t) => {
+  t.assert.eq(report.summary.with_double, 3, 'Should count interfaces with doubles');
+  t.assert.eq(report.summary.without_double, 1, 'Should count interfaces without doubles');
+  t.assert.eq(report.summary.coverage, 75, 'Should compute the coverage in percent');
+  t.assert.eq(summarize_test_doubles([]).coverage, 100, 'Should cover an empty project fully');
+});
+
+// ============ DOUBLE_PACKAGE_PATTERN tests ============
+
+await test('DOUBLE_PACKAGE_PATTERN recognizes packages of doubles', async (t) => {
+  t.assert.ok(DOUBLE_PACKAGE_PATTERN.test('mocks'), 'Should match mocks');
+  t.assert.ok(DOUBLE_PACKAGE_PATTERN.test('fake'), 'Should match fake');
+  t.assert.ok(DOUBLE_PACKAGE_PATTERN.test('storemock'), 'Should match a mock suffix');
+  t.assert.ok(!DOUBLE_PACKAGE_PATTERN.test('store'), 'Should not match other packages');
+});
@@ -0,0 +1,81 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go error handling analysis functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file, group_go_packages } from '../../../lib/analysis/golang.mjs';
+import {
+  analyze_package_error_wrapping,
+  check_wrapping_convention,
+  find_return_statements,
+  classify_error_value
+} from '../../../lib/analysis/errors.mjs';
+
+const [pkg] = group_go_packages([
+  parse_go_file(readFileSync('./tests/fixtures/error_wrapping.go', 'utf-8'), 'config/config.go')
+]);
+
+// ============ find_return_statements tests ============
+
+await test('find_return_statements splits returned values', async (t) => {
+  const body = '{\n\tif x {\n\t\treturn nil, fmt.Errorf("a, b: %w",\n\t\t\terr)\n\t}\n\treturn v, nil\n}';
+  const result = find_return_statements(body, 10);
+  t.assert.eq(result.length, 2, 'Should find two returns');
+  t.assert.eq(result[0].line, 12, 'Should record the line');
+  t.assert.eq(result[0].values.length, 2, 'Should not split inside calls');
+  t.assert.eq(result[1].values.join(' '), 'v nil', 'Should split top level values');
+});
+
+await test('find_return_statements skips function literals', async (t) => {
+  const body = '{\n\tf := func() error {\n\t\treturn nil\n\t}\n\treturn f()\n}';
+  const result = find_return_statements(body);
+  t.assert.eq(result.map((r) => r.expression).join(' '), 'f()', 'Should only find the outer return');
+});
+
+// ============ classify_error_value tests ============
+
+await test('classify_error_value classifies returned errors', async (t) => {
+  t.assert.eq(classify_error_value('fmt.Errorf("x: %w", err)'), 'wrapped', '%w wraps');
+  t.assert.eq(classify_error_value('errors.Wrap(err, "x")'), 'wrapped', 'errors.Wrap wraps');
+  t.assert.eq(classify_error_value('fmt.Errorf("x: %v", err)'), 'annotated', '%v annotates');
+  t.assert.eq(classify_error_value('fmt.Errorf("x: %s", err.Error())'), 'annotated', 'Error() annotates');
+  t.assert.eq(classify_error_value('fmt.Errorf("bad %d", n)'), 'new', 'No error is a new error');
+  t.assert.eq(classify_error_value('err'), 'raw', 'Variables are raw');
+  t.assert.eq(classify_error_value('ErrNotFound'), 'sentinel', 'Err names are sentinels');
+  t.assert.eq(classify_error_value('io.EOF'), 'sentinel', 'Qualified sentinels');
+  t.assert.eq(classify_error_value('nil'), 'nil', 'nil');
+});
+
+// ============ check_wrapping_convention tests ============
+
+await test('analyze_package_error_wrapping flags the minority pattern', async (t) => {
+  const result = analyze_package_error_wrapping(pkg);
+  t.assert.eq(result.detected, 'wrap', 'Most errors are wrapped');
+  t.assert.eq(result.counts.wrapped, 3, 'Should count wrapped errors');
+  t.assert.eq(
+    result.deviations.map((d) => `${d.function}:${d.line}:${d.category}`).join(' '),
+    'Save:32:raw Save:35:annotated',
+    'Should report each deviating return site'
+  );
+  t.assert.ok(!result.consistent, 'Should not be consistent');
+});
+
+await test('analyze_package_error_wrapping honours the expected convention', async (t) => {
+  const result = analyze_package_error_wrapping(pkg, { expected: 'no-wrap' });
+  t.assert.eq(result.convention, 'no-wrap', 'Should use the expected convention');
+  t.assert.eq(result.deviations.length, 3, 'Wrapped errors should deviate');
+  t.assert.eq(result.deviations[0].function, 'Load', 'Should point at Load');
+});
+
+await test('check_wrapping_convention reports evenly mixed packages', async (t) => {
+  const result = check_wrapping_convention([
+    { category: 'wrapped', expression: 'fmt.Errorf("a: %w", err)' },
+    { category: 'raw', expression: 'err' }
+  ]);
+  t.assert.eq(result.detected, 'mixed', 'Should be mixed');
+  t.assert.eq(result.deviations.length, 0, 'Should not pick a minority');
+  t.assert.ok(!result.consistent, 'Mixed is not consistent');
+});
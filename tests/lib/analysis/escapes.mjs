@@ -0,0 +1,69 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go escaping pointer functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_escaping_pointers,
+  find_local_variables
+} from '../../../lib/analysis/escapes.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/escapes.go', 'utf-8'),
+    'config/config.go'
+  )
+]);
+const escapes = find_escaping_pointers(packages);
+const escapes_of = (name) => escapes.filter((e) => e.function === name);
+
+// ============ find_local_variables tests ============
+
+await test('find_local_variables separates range variables', async (t) => {
+  const locals = find_local_variables(
+    '{\n\tfor i, v := range items {\n\t\tx := v\n\t}\n\tvar a, b int\n\tif ok := f(); ok {}\n}'
+  );
+  t.assert.eq(locals.get('v'), 'range', 'v is a range variable');
+  t.assert.eq(locals.get('x'), 'local', 'x is defined with :=');
+  t.assert.eq(locals.get('b'), 'local', 'b is declared with var');
+  t.assert.eq(locals.get('ok'), 'local', 'ok is defined in an if statement');
+});
+
+// ============ find_escaping_pointers tests ============
+
+await test('find_escaping_pointers reports addresses of locals', async (t) => {
+  const load = escapes_of('Load')[0];
+  t.assert.eq(load.kind, 'local', 'cfg is a local variable');
+  t.assert.eq(load.expression, '&cfg', 'Should report the expression');
+  t.assert.eq(load.line, 29, 'Should report the line of the return');
+  t.assert.eq(load.severity, 'info', 'Escapes are informational');
+  t.assert.eq(escapes_of('Empty')[0].variable, 'c', 'var declarations are locals');
+  t.assert.eq(escapes_of('Find')[0].kind, 'range', 'Range variables are copies');
+  t.assert.eq(escapes_of('Copy')[0].kind, 'param', 'Parameters are copies');
+  const inner = escapes_of('Config.Inner')[0];
+  t.assert.eq(inner.field, 'inner', 'Should report the field of a value receiver');
+  t.assert.ok(inner.message.includes('value receiver'), 'Should name the receiver');
+});
+
+await test('find_escaping_pointers distinguishes composite literals', async (t) => {
+  const create = escapes_of('NewConfig')[0];
+  t.assert.eq(create.kind, 'composite', '&Config{...} is a composite literal');
+  t.assert.eq(create.expression, '&Config{...}', 'Should elide the literal');
+  const variables = find_escaping_pointers(packages, { include_composites: false });
+  t.assert.ok(!variables.some((e) => e.kind === 'composite'), 'Composites can be excluded');
+});
+
+await test('find_escaping_pointers ignores shared addresses', async (t) => {
+  t.assert.eq(escapes_of('FindIndex').length, 0, 'Slice elements are not local');
+  t.assert.eq(escapes_of('Default').length, 0, 'Package variables are not local');
+  t.assert.eq(escapes_of('Config.Shared').length, 0, 'Pointer receivers are shared');
+  t.assert.eq(escapes_of('Later').length, 0, 'Returns of function literals are skipped');
+  t.assert.eq(escapes.length, 6, 'Should find every escape');
+});
@@ -0,0 +1,63 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go function value functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_function_values,
+  classify_value_site
+} from '../../../lib/analysis/funcvalues.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/function_values.go', 'utf-8'),
+    'handlers/handlers.go'
+  )
+]);
+
+const functions = Object.fromEntries(
+  find_function_values(packages).map((fn) => [fn.name, fn])
+);
+
+// ============ classify_value_site tests ============
+
+await test('classify_value_site classifies a value by its context', async (t) => {
+  const kind = (code) => classify_value_site(code, code.indexOf('fn'));
+  t.assert.eq(kind('sort.Slice(xs, fn)'), 'argument', 'Should classify call arguments');
+  t.assert.eq(kind('x := fn'), 'assignment', 'Should classify declarations');
+  t.assert.eq(kind('s.handler = fn'), 'field', 'Should classify field assignments');
+  t.assert.eq(kind('Route{Handler: fn}'), 'composite', 'Should classify keyed elements');
+  t.assert.eq(kind('[]func(){fn}'), 'composite', 'Should classify elements');
+  t.assert.eq(kind('return fn'), 'return', 'Should classify returned values');
+  t.assert.eq(kind('if x == fn {'), 'other', 'Should classify comparisons as other');
+});
+
+// ============ find_function_values tests ============
+
+await test('find_function_values finds functions used as values', async (t) => {
+  const kinds = (name) => functions[name].value_sites.map((site) => site.kind);
+  t.assert.eq(functions.handleHealth.used_as_value, true, 'Should find a function passed as an argument');
+  t.assert.eq(kinds('handleHealth').sort().join(','), 'argument,composite', 'Should find the argument and the package map value');
+  t.assert.eq(kinds('handleIndex').join(','), 'composite', 'Should find a function stored in a struct literal');
+  t.assert.eq(kinds('logError').join(','), 'field,field', 'Should find functions assigned to fields');
+  t.assert.eq(kinds('normalize').sort().join(','), 'assignment,return', 'Should find assignments and returns, not the shadowed local');
+  t.assert.eq(kinds('Server.Handle').join(','), 'argument', 'Should find method values');
+  t.assert.eq(functions.handleHealth.value_sites.find((s) => s.kind === 'argument').enclosing, 'handlers:NewServer', 'Should record the enclosing function');
+});
+
+await test('find_function_values separates called and unreferenced functions', async (t) => {
+  t.assert.eq(functions.byPath.used_as_value, false, 'Should not count a call returning a function');
+  t.assert.eq(functions.byPath.calls, 1, 'Should count the direct calls');
+  t.assert.eq(functions.lessRoute.used_as_value, false, 'Should not count direct calls as values');
+  t.assert.eq(functions.normalize.calls, 1, 'Should count the calls of a function also used as a value');
+  t.assert.eq(functions.unused.used_as_value || functions.unused.calls > 0, false, 'Should leave unreferenced functions');
+  const values = find_function_values(packages, { values_only: true });
+  t.assert.ok(values.every((fn) => fn.used_as_value), 'Should only list values with values_only');
+});
@@ -0,0 +1,261 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go documentation analysis functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file, group_go_packages } from '../../../lib/analysis/golang.mjs';
+import {
+  summarize_package_doc,
+  get_doc_synopsis,
+  find_source_undocumented,
+  find_source_doc_names,
+  check_doc_name,
+  find_source_doc_coverage,
+  compare_doc_coverage,
+  find_source_doc_links,
+  parse_doc_links,
+  glob_to_regexp
+} from '../../../lib/analysis/godoc.mjs';
+
+const package_doc_source = readFileSync('./tests/fixtures/package_doc.go', 'utf-8');
+const package_header_source = readFileSync('./tests/fixtures/package_doc_header.go', 'utf-8');
+
+// ============ get_doc_synopsis tests ============
+
+await test('get_doc_synopsis returns the first sentence', async (t) => {
+  t.assert.eq(
+    get_doc_synopsis('Package mux implements a\nrequest router. Routes match in order.'),
+    'Package mux implements a request router.',
+    'Should join lines and stop at the first period'
+  );
+  t.assert.eq(get_doc_synopsis('Package mux'), 'Package mux', 'Should return text without a period');
+});
+
+// ============ summarize_package_doc tests ============
+
+await test('summarize_package_doc reports the merged package doc', async (t) => {
+  const [pkg] = group_go_packages([
+    parse_go_file(package_header_source, 'mux/a.go'),
+    parse_go_file(package_doc_source, 'mux/doc.go')
+  ]);
+  const result = summarize_package_doc(pkg);
+  t.assert.ok(result.documented, 'Should be documented');
+  t.assert.eq(result.synopsis, 'Package mux implements a request router.', 'Should have synopsis');
+  t.assert.ok(result.doc.includes('\n\nRoutes are matched'), 'Should keep paragraphs');
+  t.assert.eq(result.name_mismatch, null, 'doc.go names the right package');
+  t.assert.eq(result.conflicts[0].synopsis, 'Package router implements the routing tree.', 'Should summarize conflicts');
+  t.assert.eq(
+    result.file_headers.map((h) => h.filename).join(', '),
+    'mux/a.go, mux/doc.go',
+    'Should list file headers'
+  );
+  t.assert.eq(result.file_headers[0].header, 'Router internals.', 'Should strip block comment markers');
+});
+
+await test('summarize_package_doc flags docs that name another package', async (t) => {
+  const [pkg] = group_go_packages([parse_go_file(package_header_source, 'mux/a.go')]);
+  const result = summarize_package_doc(pkg);
+  t.assert.eq(result.name_mismatch, 'router', 'Should report the named package');
+});
+
+await test('summarize_package_doc handles undocumented packages', async (t) => {
+  const [pkg] = group_go_packages([parse_go_file('package mux\n', 'mux/b.go')]);
+  const result = summarize_package_doc(pkg);
+  t.assert.ok(!result.documented, 'Should be undocumented');
+  t.assert.eq(result.synopsis, null, 'Should have no synopsis');
+  t.assert.eq(result.conflicts.length, 0, 'Should have no conflicts');
+});
+
+// ============ find_source_undocumented tests ============
+
+const classes_source = {
+  filename: 'shapes/classes_structs.go',
+  source: readFileSync('./tests/fixtures/classes_structs.go', 'utf-8')
+};
+
+await test('find_source_undocumented reports exported symbols without docs', async (t) => {
+  const result = find_source_undocumented([classes_source]);
+  const names = result.symbols.map((s) => `${s.kind}:${s.name}`);
+  t.assert.ok(!names.includes('type:Point') && !names.includes('type:User'), 'Documented types are fine');
+  t.assert.ok(names.includes('field:Point.X'), 'Fields without docs are reported');
+  t.assert.ok(names.includes('field:User.Email'), 'Tagged fields are reported');
+  t.assert.ok(names.includes('type:Reader'), 'Undocumented types are reported');
+  t.assert.ok(names.includes('method:Dog.Speak'), 'Methods of exported types are reported');
+  t.assert.ok(!names.some((n) => n.startsWith('field:Employee.User')), 'Embedded fields are skipped');
+  t.assert.eq(result.summary.undocumented, result.symbols.length, 'Should count them');
+  t.assert.ok(result.summary.coverage > 0 && result.summary.coverage < 100, 'Should compute coverage');
+});
+
+await test('find_source_undocumented only checks the requested kinds', async (t) => {
+  const result = find_source_undocumented([classes_source], { kinds: ['type'] });
+  t.assert.ok(result.symbols.every((s) => s.kind === 'type'), 'Only types');
+  t.assert.eq(result.kinds.join(','), 'type', 'Should report the kinds');
+
+  let error = null;
+  try {
+    find_source_undocumented([classes_source], { kinds: ['struct'] });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error && /Unknown kind 'struct'/.test(error.message), 'Should reject unknown kinds');
+});
+
+await test('find_source_undocumented skips excluded files', async (t) => {
+  const generated = { ...classes_source, filename: 'shapes/zz_generated.go' };
+  const result = find_source_undocumented([generated], { exclude: ['zz_*.go'] });
+  t.assert.eq(result.summary.checked, 0, 'Should skip generated files');
+});
+
+await test('glob_to_regexp matches filenames like .gitignore', async (t) => {
+  t.assert.ok(glob_to_regexp('*_gen.go').test('a/b/x_gen.go'), 'Basename globs match in any directory');
+  t.assert.ok(glob_to_regexp('internal/**').test('internal/a/b.go'), '** matches directories');
+  t.assert.ok(glob_to_regexp('**/mock_*.go').test('mock_a.go'), '**/ matches the top level');
+  t.assert.ok(!glob_to_regexp('a/*.go').test('a/b/c.go'), '* does not match /');
+});
+
+// ============ find_source_doc_names tests ============
+
+const doc_names_source = {
+  filename: 'fetch/fetch.go',
+  source: readFileSync('./tests/fixtures/doc_names.go', 'utf-8')
+};
+
+await test('find_source_doc_names reports docs not beginning with the name', async (t) => {
+  const result = find_source_doc_names([doc_names_source]);
+  const names = result.symbols.map((s) => `${s.name}:${s.first_word}`);
+  t.assert.eq(
+    names.join(' '),
+    'Retries:how Client.Count:Returns URLParser:UrlParser Open:Opens NewClient:This',
+    'Should report each misnamed doc with its first word'
+  );
+  t.assert.eq(result.symbols[1].expected, 'Count', 'Methods should be named without their type');
+  t.assert.ok(result.symbols[1].message.includes('"Count ..."'), 'Should describe the expected form');
+  t.assert.eq(result.summary.mismatched, 5, 'Should count them');
+});
+
+await test('find_source_doc_names accepts articles, type prefixes and group docs', async (t) => {
+  const result = find_source_doc_names([doc_names_source]);
+  const names = new Set(result.symbols.map((s) => s.name));
+  t.assert.ok(!names.has('Client'), 'A leading article should be accepted');
+  t.assert.ok(!names.has('Client.Close'), 'Methods may be named with their type');
+  t.assert.ok(!names.has('Red') && !names.has('Green'), 'Group docs should not be checked');
+  t.assert.ok(!names.has('MaxSize') && !names.has('ID'), 'Punctuation and possessives should be ignored');
+  t.assert.eq(result.by_kind.const.checked, 4, 'Only constants with their own docs are checked');
+});
+
+await test('find_source_doc_names handles acronyms and deprecation notices', async (t) => {
+  const result = find_source_doc_names([doc_names_source]);
+  const parser = result.symbols.find((s) => s.name === 'URLParser');
+  t.assert.eq(parser.reason, 'case', 'A differently cased name should be a case mismatch');
+  t.assert.eq(result.summary.by_case, 1, 'Should count case mismatches');
+
+  const names = new Set(result.symbols.map((s) => s.name));
+  t.assert.ok(!names.has('Get'), 'A lone deprecation notice should be accepted');
+  t.assert.ok(!names.has('Dial'), 'The paragraph after the notice should be checked');
+  t.assert.ok(result.symbols.find((s) => s.name === 'Open').deprecated, 'Should note deprecated symbols');
+  t.assert.eq(check_doc_name('// Deprecated: Foo is old.', ['Foo']), null, 'Should skip the notice');
+});
+
+const coverage_v1 = [{
+  filename: 'geo/geo.go',
+  source: 'package geo\n\n// Point is a point.\ntype Point struct{ X, Y int }\n\nfunc Origin() Point { return Point{} }\n\n// Distance is the distance.\nfunc Distance(a, b Point) int { return 0 }\n'
+}];
+const coverage_v2 = [{
+  filename: 'geo/geo.go',
+  source: 'package geo\n\n// Point is a point.\ntype Point struct{ X, Y int }\n\n// Origin is the origin.\nfunc Origin() Point { return Point{} }\n\nfunc Distance(a, b Point) int { return 0 }\n\nfunc Scale(p Point, k int) Point { return p }\n\nfunc Rotate(p Point) Point { return p }\n'
+}];
+
+await test('find_source_doc_coverage records documented symbols by id', async (t) => {
+  const coverage = find_source_doc_coverage(coverage_v1, { kinds: ['function', 'type'] });
+  t.assert.eq(coverage.version, 1, 'Should record the format version');
+  t.assert.eq(coverage.symbols, { 'geo:Point': true, 'geo:Origin': false, 'geo:Distance': true }, 'Should flag each symbol');
+  t.assert.eq(coverage.summary, { checked: 3, documented: 2, coverage: 66.7 }, 'Should summarize coverage');
+});
+
+await test('compare_doc_coverage reports the delta and new undocumented symbols', async (t) => {
+  const options = { kinds: ['function', 'type'] };
+  const baseline = find_source_doc_coverage(coverage_v1, options);
+  const current = find_source_doc_coverage(coverage_v2, options);
+  const comparison = compare_doc_coverage(current, baseline);
+  t.assert.eq(comparison.delta, -26.7, 'Should report the change of coverage');
+  t.assert.ok(comparison.regression, 'A lower coverage should be a regression');
+  t.assert.eq(comparison.new_undocumented, ['geo:Rotate', 'geo:Scale'], 'Should list new symbols without docs');
+  t.assert.eq(comparison.newly_documented, ['geo:Origin'], 'Should list symbols documented since');
+  t.assert.eq(comparison.lost_docs, ['geo:Distance'], 'Should list symbols whose docs were removed');
+  t.assert.ok(!compare_doc_coverage(baseline, baseline).regression, 'The same coverage should not regress');
+
+  let error = null;
+  try {
+    compare_doc_coverage(current, { version: 99 });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject other baseline versions');
+});
+
+// ============ doc link tests ============
+
+const doc_link_sources = [
+  ['doc_links.go', 'calc/calc.go'],
+  ['doc_links_store.go', 'store/store.go']
+].map(([fixture, filename]) => ({
+  filename,
+  source: readFileSync(`./tests/fixtures/${fixture}`, 'utf-8')
+}));
+
+await test('parse_doc_links extracts symbol links and URLs', async (t) => {
+  const links = parse_doc_links(
+    '// See [Reader], [io.Writer] and [the spec].\n// Not m[Key] or [lower].\n//\n//\tx := [Code]\n//\n// [the spec]: https://go.dev/ref/spec'
+  );
+  t.assert.eq(
+    links.symbols.map((l) => l.target).join(','),
+    'Reader,io.Writer',
+    'Should skip index expressions, unexported names and code blocks'
+  );
+  t.assert.eq(links.urls.length, 1, 'Should find the link definition');
+  t.assert.eq(links.urls[0].text, 'the spec', 'Should keep the link text');
+  t.assert.eq(links.urls[0].url, 'https://go.dev/ref/spec', 'Should keep the URL');
+  const bare = parse_doc_links('// Read https://example.com/docs.');
+  t.assert.eq(bare.urls[0].url, 'https://example.com/docs', 'Should trim trailing punctuation of bare URLs');
+});
+
+await test('find_source_doc_links resolves links to project symbols', async (t) => {
+  const result = find_source_doc_links(doc_link_sources);
+  const calculator = result.symbols.find((s) => s.name === 'Calculator');
+  const by_target = Object.fromEntries(
+    calculator.doc_links.map((l) => [l.target, l])
+  );
+  t.assert.eq(by_target['*store.Register'].status, 'resolved', 'Should resolve imported package symbols');
+  t.assert.eq(by_target['*store.Register'].resolved.directory, 'store', 'Should point at the package');
+  t.assert.eq(by_target['Calculator.Print'].resolved.kind, 'method', 'Should resolve methods');
+  t.assert.eq(by_target['Calculator.Total'].resolved.kind, 'field', 'Should resolve fields');
+  t.assert.eq(by_target.Precision.resolved.kind, 'const', 'Should resolve constants');
+  t.assert.eq(by_target['io.Writer'].status, 'external', 'Should treat other packages as external');
+  t.assert.eq(by_target['io.Writer'].import_path, 'io', 'Should give the import path');
+
+  const round = result.symbols.find((s) => s.name === 'Calculator.Round');
+  t.assert.eq(round.doc_links[0].status, 'resolved', 'Should resolve import path links');
+  t.assert.eq(round.urls[0].url, 'https://example.com/rounding', 'Should list bare URLs');
+
+  const pkg = result.symbols.find((s) => s.kind === 'package');
+  t.assert.eq(pkg.doc_links.length, 2, 'Should extract the links of package docs');
+  t.assert.eq(pkg.urls[0].text, 'Go spec', 'Should list defined URLs separately');
+});
+
+await test('find_source_doc_links flags unresolved links', async (t) => {
+  const result = find_source_doc_links(doc_link_sources);
+  t.assert.eq(
+    result.unresolved.map((u) => u.link).join(','),
+    '[Calculator.Sum],[Subtract],[store.Counter],[Calcualtor.Add],[Register.Reset]',
+    'Should flag links to missing symbols'
+  );
+  t.assert.eq(result.summary.resolved, 12, 'Should count resolved links');
+  t.assert.eq(result.summary.external, 2, 'Should count external links');
+  t.assert.eq(result.summary.unresolved, 5, 'Should count unresolved links');
+
+  const only = find_source_doc_links(doc_link_sources, { unresolved_only: true });
+  t.assert.eq(only.symbols.length, 2, 'Should only list symbols with unresolved links');
+});
@@ -0,0 +1,533 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for the lightweight Go declaration parser.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  mask_source,
+  split_top_level,
+  parse_parameters,
+  parse_results,
+  parse_receiver,
+  parse_field,
+  parse_go_file,
+  group_go_packages,
+  parse_go_sources,
+  is_build_ignored,
+  parse_filename_constraints,
+  convert_plus_build,
+  evaluate_build_constraint,
+  get_methods_by_type,
+  find_go_assignments,
+  summarize_go_body,
+  find_go_assertions,
+  find_go_conversions,
+  get_base_type,
+  evaluate_constant,
+  build_constant_resolver,
+  count_array_elements,
+  split_composite_literal,
+  get_array_length
+} from '../../../lib/analysis/golang.mjs';
+
+const classes_structs = readFileSync(
+  './tests/fixtures/classes_structs.go',
+  'utf-8'
+);
+
+// ============ Scanning helper tests ============
+
+await test('mask_source blanks comments and string contents', async (t) => {
+  const source = 'x := "a{b}" // c { d\ny := `e(f`';
+  const masked = mask_source(source);
+  t.assert.eq(masked.length, source.length, 'Should preserve length');
+  t.assert.ok(!masked.includes('{'), 'Should blank braces in strings and comments');
+  t.assert.ok(masked.includes('\n'), 'Should preserve newlines');
+  t.assert.ok(masked.startsWith('x := "'), 'Should keep code and delimiters');
+});
+
+await test('split_top_level ignores nested separators', async (t) => {
+  const result = split_top_level('a int, f func(x, y int) error, m map[string]int');
+  t.assert.eq(result.length, 3, 'Should have three parts');
+  t.assert.eq(result[1], 'f func(x, y int) error', 'Should keep nested commas');
+});
+
+// ============ Signature parsing tests ============
+
+await test('parse_parameters handles grouped names', async (t) => {
+  const result = parse_parameters('a, b int, s string');
+  t.assert.eq(
+    result.map((p) => `${p.name} ${p.type}`).join(', '),
+    'a int, b int, s string',
+    'Should share types across grouped names'
+  );
+});
+
+await test('parse_parameters handles unnamed parameters', async (t) => {
+  const result = parse_parameters('int, chan string, func(int) error');
+  t.assert.ok(result.every((p) => p.name === null), 'Should have no names');
+  t.assert.eq(result[1].type, 'chan string', 'Should keep chan type');
+});
+
+await test('parse_parameters handles variadics', async (t) => {
+  const result = parse_parameters('format string, args ...any');
+  t.assert.eq(result[1].variadic, true, 'Should mark variadic');
+  t.assert.eq(result[1].type, 'any', 'Should strip the ellipsis');
+});
+
+await test('parse_results handles single and grouped results', async (t) => {
+  t.assert.eq(parse_results('error')[0].type, 'error', 'Should parse single result');
+  t.assert.eq(parse_results('(n int, err error)').map((r) => r.name).join(', '), 'n, err', 'Should parse named results');
+  t.assert.eq(parse_results('').length, 0, 'Should handle no results');
+});
+
+await test('parse_results marks nilable results by type kind', async (t) => {
+  const divide = parse_results('(float64, error)');
+  t.assert.ok(!divide[0].nilable, 'float64 cannot be nil');
+  t.assert.ok(divide[1].nilable, 'error can be nil');
+  t.assert.ok(!parse_results('Shape')[0].nilable, 'Unresolved named types are not nilable');
+  t.assert.ok(parse_results('io.Reader')[0].nilable, 'Well known interfaces are nilable');
+});
+
+await test('group_go_packages resolves nilable results of package types', async (t) => {
+  const [pkg] = group_go_packages([
+    parse_go_file(readFileSync('./tests/fixtures/nilable.go', 'utf-8'), 'lookup/lookup.go')
+  ]);
+  const nilable_of = (name) =>
+    pkg.functions.find((f) => f.name === name).results.map((r) => r.nilable);
+
+  for (const name of ['Pointer', 'Slice', 'Map', 'Channel', 'Func', 'Interface', 'Any', 'Reader']) {
+    t.assert.eq(nilable_of(name).join(','), 'true', `${name} can return nil`);
+  }
+  t.assert.eq(nilable_of('Divide').join(','), 'false,true', 'Divide returns a value and an error');
+  t.assert.eq(nilable_of('Named').join(','), 'true,true,true', 'Should resolve package types');
+  t.assert.eq(nilable_of('Value').join(','), 'false,false,false,false', 'Values cannot be nil');
+  t.assert.eq(nilable_of('Array').join(','), 'false', 'Arrays cannot be nil');
+
+  const find = pkg.types.find((t) => t.name === 'Finder').methods[0];
+  t.assert.eq(find.results.map((r) => r.nilable).join(','), 'true,false', 'Should mark interface methods');
+});
+
+await test('parse_receiver handles pointer and generic receivers', async (t) => {
+  const result = parse_receiver('c *Container[T]');
+  t.assert.eq(result.name, 'c', 'Should have name');
+  t.assert.eq(result.type, 'Container', 'Should have base type');
+  t.assert.eq(result.pointer, true, 'Should be pointer');
+  t.assert.eq(result.type_args, 'T', 'Should have type args');
+});
+
+await test('parse_field handles tags and embedding', async (t) => {
+  const named = parse_field('ID int `json:"id"`');
+  t.assert.eq(named.names[0], 'ID', 'Should have field name');
+  t.assert.eq(named.tag, 'json:"id"', 'Should extract tag');
+
+  const embedded = parse_field('*sync.Mutex');
+  t.assert.eq(embedded.embedded, true, 'Should be embedded');
+  t.assert.eq(embedded.names[0], 'Mutex', 'Should use unqualified type name');
+});
+
+await test('get_base_type strips pointers, slices and type arguments', async (t) => {
+  t.assert.eq(get_base_type('*[]pkg.Item'), 'pkg.Item', 'Should strip pointer and slice');
+  t.assert.eq(get_base_type('List[T]'), 'List', 'Should strip type arguments');
+  t.assert.eq(get_base_type('...string'), 'string', 'Should strip ellipsis');
+});
+
+// ============ File parsing tests ============
+
+await test('parse_go_file extracts package and imports', async (t) => {
+  const result = parse_go_file(classes_structs, 'classes_structs.go');
+  t.assert.eq(result.package, 'main', 'Should have package name');
+  t.assert.eq(result.imports.map((i) => i.path).join(', '), 'fmt, math', 'Should have imports');
+});
+
+await test('parse_go_file extracts structs and fields', async (t) => {
+  const result = parse_go_file(classes_structs, 'classes_structs.go');
+  const user = result.types.find((type) => type.name === 'User');
+  t.assert.eq(user.kind, 'struct', 'User should be a struct');
+  t.assert.eq(user.fields.length, 4, 'User should have 4 fields');
+  t.assert.eq(user.fields[0].tag, 'json:"id" db:"user_id"', 'Should have field tag');
+
+  const employee = result.types.find((type) => type.name === 'Employee');
+  t.assert.eq(employee.fields[0].embedded, true, 'Employee should embed User');
+});
+
+await test('parse_go_file extracts interfaces', async (t) => {
+  const result = parse_go_file(classes_structs, 'classes_structs.go');
+  const reader = result.types.find((type) => type.name === 'Reader');
+  t.assert.eq(reader.kind, 'interface', 'Reader should be an interface');
+  t.assert.eq(reader.methods[0].name, 'Read', 'Should have Read method');
+  t.assert.eq(reader.methods[0].results.length, 2, 'Read should have 2 results');
+
+  const read_writer = result.types.find((type) => type.name === 'ReadWriter');
+  t.assert.eq(read_writer.embeds.map((e) => e.type).join(', '), 'Reader, Writer', 'Should have embedded interfaces');
+});
+
+await test('parse_go_file extracts methods with receivers', async (t) => {
+  const result = parse_go_file(classes_structs, 'classes_structs.go');
+  const increment = result.functions.find((fn) => fn.name === 'Increment');
+  t.assert.eq(increment.receiver.type, 'Counter', 'Should have receiver type');
+  t.assert.eq(increment.receiver.pointer, true, 'Should have pointer receiver');
+  t.assert.ok(increment.body.includes('c.value++'), 'Should capture body');
+
+  const container = result.types.find((type) => type.name === 'Container');
+  t.assert.eq(container.type_params, 'T any', 'Should capture type parameters');
+});
+
+await test('parse_go_file extracts consts with iota and build constraints', async (t) => {
+  const source = [
+    '//go:build linux',
+    '',
+    '// Package kinds has kinds.',
+    'package kinds',
+    '',
+    'const (',
+    '\tA Kind = iota',
+    '\tB',
+    '',
+    '\tC',
+    ')',
+    '',
+    'var x, y = 1, "a"'
+  ].join('\n');
+  const result = parse_go_file(source, 'kinds.go');
+  t.assert.eq(result.build_constraints.go_build, 'linux', 'Should have build constraint');
+  t.assert.eq(result.package_doc, '// Package kinds has kinds.', 'Should have package doc');
+  t.assert.eq(result.consts.map((c) => c.names[0]).join(', '), 'A, B, C', 'Should have consts');
+  t.assert.eq(result.consts[1].implicit, true, 'B should repeat the previous expression');
+  t.assert.eq(result.consts[2].group, 1, 'C should be in the second group');
+  t.assert.eq(result.vars[0].names.join(', '), 'x, y', 'Should have var names');
+});
+
+await test('parse_go_file groups struct fields with their section headers', async (t) => {
+  const result = parse_go_file(readFileSync('./tests/fixtures/field_groups.go', 'utf-8'), 'server/config.go');
+  const fields = result.types[0].fields;
+  const group_of = (name) => fields.find((f) => f.names.includes(name));
+  t.assert.eq(fields.map((f) => f.group).join(','), '0,0,1,1,2,3', 'Blank lines should separate groups');
+  t.assert.eq(group_of('Port').group_header, '// Network', 'A standalone comment should head the group');
+  t.assert.eq(group_of('Host').doc, '// Host is the host to listen on.', 'The field doc should be kept');
+  t.assert.eq(group_of('WriteTimeout').group_header, '// Timeouts for each connection.', 'A leading doc not naming the field should head the group');
+  t.assert.eq(group_of('Logger').group_header, null, 'A field doc should not head its own group');
+});
+
+await test('is_build_ignored detects files excluded from every build', async (t) => {
+  const check = (go_build, plus_build = []) => is_build_ignored({ go_build, plus_build });
+  t.assert.ok(check('ignore'), 'ignore excludes the file');
+  t.assert.ok(check('ignore && linux'), 'A conjunction with ignore excludes the file');
+  t.assert.ok(!check('ignore || linux'), 'A disjunction can still be satisfied');
+  t.assert.ok(!check('!ignore'), 'A negated tag does not exclude the file');
+  t.assert.ok(check(null, ['ignore']), 'Should support +build lines');
+  t.assert.ok(!check(null, ['ignore linux']), 'Spaces separate alternatives in +build lines');
+});
+
+await test('parse_filename_constraints reads GOOS and GOARCH suffixes', async (t) => {
+  const check = (filename) => {
+    const { goos, goarch, test: is_test } = parse_filename_constraints(filename);
+    return `${goos}/${goarch}/${is_test}`;
+  };
+  t.assert.eq(check('poll_linux.go'), 'linux/null/false', 'Should read a GOOS suffix');
+  t.assert.eq(check('asm_amd64.go'), 'null/amd64/false', 'Should read a GOARCH suffix');
+  t.assert.eq(check('net/poll_linux_amd64.go'), 'linux/amd64/false', 'Should read both suffixes');
+  t.assert.eq(check('poll_linux_test.go'), 'linux/null/true', 'Should look before _test');
+  t.assert.eq(check('linux.go'), 'null/null/false', 'A name without underscore is unconstrained');
+  t.assert.eq(check('poll_unix.go'), 'null/null/false', 'unix is not a GOOS');
+});
+
+await test('parse_go_file merges build lines with filename constraints', async (t) => {
+  const parse = (name) =>
+    parse_go_file(readFileSync(`./tests/fixtures/${name}`, 'utf-8'), name).effective_constraint;
+
+  t.assert.eq(parse('constraint_linux.go').expression, 'linux', 'Should use the filename alone');
+  t.assert.eq(
+    parse('constraint_linux_amd64.go').expression,
+    '!purego && linux && amd64',
+    'Should AND the build line with both suffixes'
+  );
+  t.assert.eq(
+    parse('constraint_windows.go').expression,
+    '(go1.21 || purego) && windows',
+    'Should group a disjunction'
+  );
+  t.assert.eq(
+    parse('constraint_arm64.go').expression,
+    '((linux && cgo) || darwin) && !purego && arm64',
+    'Should convert +build lines'
+  );
+  t.assert.eq(parse('constraint_unix.go').expression, 'unix', 'Should use the build line alone');
+  t.assert.eq(
+    parse('constraint_nocgo_linux.go').expression,
+    'linux && !cgo',
+    'Should not repeat a suffix the build line already requires'
+  );
+  t.assert.eq(
+    parse('constraint_unix_linux_amd64.go').expression,
+    '(linux || darwin) && amd64 && linux',
+    'Should keep a suffix the build line only allows'
+  );
+  const test_file = parse('constraint_linux_test.go');
+  t.assert.ok(test_file.test, 'Should flag test files');
+  t.assert.eq(test_file.expression, 'linux', 'Should not treat _test as a tag');
+  t.assert.eq(convert_plus_build([]), null, 'No +build lines means no constraint');
+});
+
+await test('evaluate_build_constraint evaluates expressions', async (t) => {
+  const tags = new Set(['linux', 'amd64', 'unix']);
+  t.assert.ok(evaluate_build_constraint(null, tags), 'Unconstrained files always match');
+  t.assert.ok(evaluate_build_constraint('!purego && linux && amd64', tags), 'Should evaluate conjunctions');
+  t.assert.ok(!evaluate_build_constraint('(go1.21 || purego) && windows', tags), 'Should evaluate groups');
+  t.assert.ok(evaluate_build_constraint('darwin || unix', tags), 'Should evaluate disjunctions');
+
+  let error = null;
+  try {
+    evaluate_build_constraint('linux &&', tags);
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject malformed expressions');
+});
+
+await test('parse_go_sources keeps scripts out of packages', async (t) => {
+  const sources = [
+    { filename: 'tables/tables.go', source: 'package tables\n\n//go:generate go run gen.go\n\nconst Size8 = 8\n' },
+    { filename: 'tables/gen.go', source: readFileSync('./tests/fixtures/gen_script.go', 'utf-8') }
+  ];
+
+  const result = parse_go_sources(sources);
+  t.assert.eq(result.packages.length, 1, 'Only the buildable package should be grouped');
+  t.assert.eq(result.packages[0].functions.length, 0, 'Script functions should not leak into the package');
+  t.assert.eq(result.scripts.length, 0, 'Scripts are only parsed when asked for');
+  t.assert.eq(result.ignored_files.join(','), 'tables/gen.go', 'Should list ignored files');
+
+  const [script] = parse_go_sources(sources, { include_scripts: true }).scripts;
+  t.assert.ok(script.script, 'Should mark scripts');
+  t.assert.eq(script.name, 'main', 'Should keep the script package name');
+  t.assert.eq(script.functions.map((f) => f.name).join(','), 'main,render', 'Should parse the script');
+  t.assert.eq(script.generated_by[0].filename, 'tables/tables.go', 'Should find the go:generate directive');
+  t.assert.eq(script.generated_by[0].line, 3, 'Should record the directive line');
+});
+
+await test('group_go_packages groups files by directory', async (t) => {
+  const files = [
+    parse_go_file('package a\n\ntype T struct{}\n', 'a/one.go'),
+    parse_go_file('package a\n\nfunc (t T) M() {}\n', 'a/two.go'),
+    parse_go_file('package b\n', 'b/one.go')
+  ];
+  const result = group_go_packages(files);
+  t.assert.eq(result.length, 2, 'Should have two packages');
+  t.assert.eq(result[0].types.length, 1, 'Package a should have one type');
+  t.assert.eq(result[0].methods.T.length, 1, 'Package a should index methods by receiver');
+});
+
+// ============ Method grouping tests ============
+
+const method_names = (methods) =>
+  methods.map((m) => (m.promoted_from ? `${m.promoted_from}.${m.name}` : m.name)).join(', ');
+
+await test('get_methods_by_type groups methods by receiver type', async (t) => {
+  const file = parse_go_file(readFileSync('./tests/fixtures/test.go', 'utf-8'), 'test.go');
+  const result = get_methods_by_type(file);
+  t.assert.eq(method_names(result.Calculator), 'Add, Multiply', 'Calculator methods');
+});
+
+await test('get_methods_by_type sorts methods and uses base names for generic receivers', async (t) => {
+  const file = parse_go_file(readFileSync('./tests/fixtures/classes_structs.go', 'utf-8'), 'classes_structs.go');
+  const result = get_methods_by_type(file);
+  t.assert.eq(method_names(result.Counter), 'Decrement, Increment, Value', 'Should sort by name');
+  t.assert.eq(method_names(result.Container), 'Add, Get', 'Should key Container[T] as Container');
+});
+
+await test('get_methods_by_type includes promoted methods', async (t) => {
+  const source = [
+    'package p',
+    'type Reader interface { Read() }',
+    'type A struct{}',
+    'func (A) Close() {}',
+    'func (A) Name() {}',
+    'type B struct{ *A }',
+    'func (B) Name() {}',
+    'func (B) Size() {}',
+    'type C struct{}',
+    'func (C) Size() {}',
+    'type D struct {',
+    '\tB',
+    '\tC',
+    '\tReader',
+    '}',
+    'func (D) Own() {}'
+  ].join('\n');
+  const file = parse_go_file(source, 'p/p.go');
+  const result = get_methods_by_type(file, { include_promoted: true });
+  t.assert.eq(method_names(result.B), 'A.Close, Name, Size', 'Own methods shadow promoted ones');
+  t.assert.eq(method_names(result.D), 'A.Close, B.Name, Own, Reader.Read', 'Ambiguous names are left out');
+  t.assert.eq(result.D.find((m) => m.name === 'Close').depth, 2, 'Should record the depth');
+  t.assert.eq(get_methods_by_type(file).D.length, 1, 'Should omit promoted methods by default');
+});
+
+// ============ Assignment tests ============
+
+const assignments_file = parse_go_file(
+  readFileSync('./tests/fixtures/assignments.go', 'utf-8'),
+  'metrics/assignments.go'
+);
+const get_function = (name) => assignments_file.functions.find((f) => f.name === name);
+
+await test('find_go_assignments classifies assignment operators', async (t) => {
+  const fn = get_function('Sum');
+  const result = find_go_assignments(fn.body, fn.body_line);
+  t.assert.eq(
+    result.map((a) => `${a.operator}:${a.kind}`).join(' '),
+    ':=:define ++:increment +=:compound =:define =:assign',
+    'Should find definitions, increments and compound assignments'
+  );
+  t.assert.eq(result[2].line, 40, 'Should record the line');
+  t.assert.eq(result[3].targets.map((t) => t.base).join(', '), 'scale, offset', 'Should skip the var type');
+  t.assert.ok(!result.some((a) => a.operator === '<'), 'Should ignore comparisons');
+});
+
+await test('summarize_go_body reports compound field writes as reads and writes', async (t) => {
+  const fn = get_function('Add');
+  const result = summarize_go_body(fn.body, fn.body_line);
+  t.assert.eq(result.compound_count, 4, 'Should count compound assignments');
+  t.assert.eq(result.by_operator['+='], 1, 'Should count by operator');
+  t.assert.eq(
+    result.field_writes.map((w) => `${w.base}.${w.field}`).join(' '),
+    'c.value c.total c.flags c.counts',
+    'Should find field writes'
+  );
+  t.assert.ok(result.field_writes.every((w) => w.read_write), 'Compound writes also read');
+  t.assert.ok(result.field_writes[3].indexed, 'Map element writes are indexed');
+});
+
+await test('summarize_go_body treats increments of fields as writes', async (t) => {
+  const increment = get_function('Increment');
+  const result = summarize_go_body(increment.body, increment.body_line);
+  t.assert.eq(result.field_writes[0].target, 'c.value', 'c.value++ writes c.value');
+  t.assert.eq(result.field_writes[0].line, 13, 'Should record the line');
+
+  const reset = get_function('Reset');
+  const writes = summarize_go_body(reset.body, reset.body_line).field_writes;
+  t.assert.eq(writes.map((w) => w.operator).join(' '), '= &^=', 'Should handle &^=');
+  t.assert.eq(writes[0].read_write, false, 'Plain assignment does not read');
+});
+
+// ============ Conversion and assertion tests ============
+
+await test('find_go_conversions records conversions to known types', async (t) => {
+  const fn = parse_go_file(classes_structs).functions.find(
+    (f) => f.name === 'ToFahrenheit'
+  );
+  const conversions = find_go_conversions(
+    fn.body,
+    fn.body_line,
+    new Set(['Celsius', 'Fahrenheit'])
+  );
+  t.assert.eq(conversions.length, 1, 'ToFahrenheit converts once');
+  t.assert.eq(conversions[0].type, 'Fahrenheit', 'Should name the type');
+  t.assert.eq(conversions[0].expression, 'c*9/5 + 32', 'Should keep the converted expression');
+  t.assert.eq(conversions[0].kind, 'named', 'Fahrenheit is a named type');
+  t.assert.eq(conversions[0].line, fn.body_line + 1, 'Should report the line');
+});
+
+await test('find_go_conversions tells conversions from calls', async (t) => {
+  const body = '{\n\tb := []byte(fmt.Sprint(int64(n)))\n\tp := (*Node)(ptr)\n\treturn Scale(b, p)\n}';
+  const found = find_go_conversions(body, 1, new Set(['Node']));
+  t.assert.eq(found.map((c) => c.type).join(','), '[]byte,int64,*Node', 'Should find composite and basic conversions');
+  t.assert.eq(found[0].kind, 'composite', '[]byte is a composite type');
+  t.assert.ok(!found.some((c) => c.type === 'byte'), 'Should not split []byte');
+  t.assert.ok(!found.some((c) => c.type === 'Scale'), 'Unknown names are calls');
+});
+
+await test('find_go_assertions separates checked and unchecked assertions', async (t) => {
+  const body = '{\n\tc := e.(Click)\n\tif p, ok := e.(*Click); ok {\n\t\treturn p\n\t}\n\tv, ok := m[k].(string)\n\tswitch x := e.(type) {\n\t}\n\treturn e.(Click).X\n}';
+  const found = find_go_assertions(body);
+  t.assert.eq(found.map((a) => a.form).join(','), 'single,comma_ok,comma_ok,switch,single', 'Should classify each assertion');
+  t.assert.eq(found[2].expression, 'm[k]', 'Should keep indexed operands');
+  t.assert.eq(found[4].line, 9, 'Should report the line');
+  t.assert.eq(found.filter((a) => !a.checked).length, 2, 'Two assertions can panic');
+});
+
+// ============ Package doc tests ============
+
+const package_doc_source = readFileSync('./tests/fixtures/package_doc.go', 'utf-8');
+const package_header_source = readFileSync('./tests/fixtures/package_doc_header.go', 'utf-8');
+
+await test('parse_go_file separates package docs from file headers', async (t) => {
+  const result = parse_go_file(package_doc_source, 'mux/doc.go');
+  t.assert.ok(result.package_doc.startsWith('// Package mux implements'), 'Should have package doc');
+  t.assert.eq(result.package_doc_line, 6, 'Should record the doc line');
+  t.assert.ok(result.file_header.includes('Copyright 2024'), 'License should be a file header');
+  t.assert.ok(!result.file_header.includes('go:build'), 'Should skip build constraints');
+});
+
+await test('parse_go_file treats comments without the Package prefix as headers', async (t) => {
+  const result = parse_go_file('// Helpers for tests.\npackage mux\n', 'mux/util.go');
+  t.assert.eq(result.package_doc, null, 'Should not have a package doc');
+  t.assert.eq(result.file_header, '// Helpers for tests.', 'Should be a file header');
+});
+
+await test('group_go_packages merges package docs and reports conflicts', async (t) => {
+  const files = [
+    parse_go_file(package_header_source, 'mux/a.go'),
+    parse_go_file(package_doc_source, 'mux/doc.go'),
+    parse_go_file('package mux\n', 'mux/b.go')
+  ];
+  const [pkg] = group_go_packages(files);
+  t.assert.eq(pkg.doc_file, 'mux/doc.go', 'doc.go should take precedence');
+  t.assert.eq(pkg.doc_line, 6, 'Should keep the doc line');
+  t.assert.eq(pkg.doc_conflicts.length, 1, 'Should report one conflict');
+  t.assert.eq(pkg.doc_conflicts[0].filename, 'mux/a.go', 'Should point at the other doc');
+  t.assert.eq(pkg.doc_conflicts[0].same_text, false, 'Docs should differ');
+});
+
+// ============ Constant evaluation tests ============
+
+await test('evaluate_constant evaluates arithmetic expressions', async (t) => {
+  t.assert.eq(evaluate_constant('1 << 10'), 1024, 'Should shift');
+  t.assert.eq(evaluate_constant('(2 + 3) * 4'), 20, 'Should respect parentheses');
+  t.assert.eq(evaluate_constant('int64(3) * 2'), 6, 'Should handle conversions');
+  t.assert.eq(evaluate_constant('"text"'), null, 'Should return null for strings');
+});
+
+await test('build_constant_resolver resolves iota and named constants', async (t) => {
+  const source = 'package p\n\nconst (\n\tA = iota * 10\n\tB\n)\n\nconst Size = B + 2\n';
+  const resolve = build_constant_resolver(parse_go_file(source).consts);
+  t.assert.eq(resolve('A'), 0, 'A should be 0');
+  t.assert.eq(resolve('B'), 10, 'B should repeat iota * 10');
+  t.assert.eq(resolve('Size'), 12, 'Size should use B');
+});
+
+await test('evaluate_constant resolves array lengths', async (t) => {
+  const length = (name) => (name === 'table' ? 4 : null);
+  t.assert.eq(evaluate_constant('len(table) * 2', { length }), 8, 'Should use the array length');
+  t.assert.eq(evaluate_constant('len(other)', { length }), null, 'Should return null for unknown arrays');
+  t.assert.eq(evaluate_constant('len(table)'), null, 'Should need a length resolver');
+});
+
+await test('count_array_elements follows keyed elements', async (t) => {
+  t.assert.eq(count_array_elements('1, 2, 3'), 3, 'Should count elements');
+  t.assert.eq(count_array_elements('2: "c", "d"'), 4, 'Should continue after keys');
+  t.assert.eq(count_array_elements('5: 1, 0: 2'), 6, 'Should use the highest index');
+  t.assert.eq(count_array_elements('"a,b", {1, 2}'), 2, 'Should not split strings or nested literals');
+  t.assert.eq(count_array_elements(''), 0, 'Should count empty literals');
+  t.assert.eq(count_array_elements('N: 1'), null, 'Should return null for unresolved keys');
+});
+
+await test('split_composite_literal separates the type from the body', async (t) => {
+  const literal = split_composite_literal('[...]struct{ X int }{{1}, {2}}');
+  t.assert.eq(literal.type, '[...]struct{ X int }', 'Should keep braces of the type');
+  t.assert.eq(literal.body, '{1}, {2}', 'Should return the body');
+  t.assert.eq(split_composite_literal('compute()'), null, 'Should return null for other expressions');
+});
+
+await test('get_array_length evaluates explicit and implicit lengths', async (t) => {
+  const resolve = (name) => (name === 'N' ? 16 : null);
+  t.assert.eq(get_array_length('[N]byte', { resolve }).length, 16, 'Should resolve constants');
+  t.assert.eq(get_array_length('[N * 2][4]byte', { resolve }).length, 32, 'Should take the outer length');
+  t.assert.eq(get_array_length('[...]int', { literal: '[...]int{1, 2, 3}' }).length, 3, 'Should count implicit lengths');
+  const unresolved = get_array_length('[M]byte', { resolve });
+  t.assert.eq(unresolved.length, null, 'Should return a null length when unresolved');
+  t.assert.eq(unresolved.expr, 'M', 'Should keep the length expression');
+  t.assert.eq(get_array_length('[]byte'), null, 'Should return null for slices');
+});
@@ -0,0 +1,114 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for the Go symbol graph functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file, group_go_packages } from '../../../lib/analysis/golang.mjs';
+import {
+  build_symbol_graph,
+  render_graph_html,
+  get_method_key,
+  get_value_method_set,
+  get_pointer_method_set
+} from '../../../lib/analysis/graph.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(readFileSync('./tests/fixtures/graph.go', 'utf-8'), 'shapes/shapes.go'),
+  parse_go_file(
+    'package geom\n\n// Mul multiplies.\nfunc Mul(a, b float64) float64 {\n\treturn a * b\n}\n',
+    'geom/geom.go'
+  )
+]);
+
+const edges_of = (graph, type) =>
+  graph.edges
+    .filter((e) => e.type === type)
+    .map((e) => `${e.source}>${e.target}`)
+    .join(' ');
+
+// ============ build_symbol_graph tests ============
+
+await test('build_symbol_graph creates nodes for functions, methods and types', async (t) => {
+  const graph = build_symbol_graph(packages);
+  const square = graph.nodes.find((n) => n.id === 'shapes:Square');
+  t.assert.eq(square.kind, 'struct', 'Square should be a struct');
+  t.assert.eq(graph.nodes.find((n) => n.id === 'shapes:Square.Area').kind, 'method', 'Should have methods');
+  t.assert.eq(graph.nodes.find((n) => n.id === 'shapes:format').exported, false, 'format is unexported');
+  t.assert.eq(graph.summary.total_packages, 2, 'Should have two packages');
+});
+
+await test('build_symbol_graph resolves calls within and across packages', async (t) => {
+  const graph = build_symbol_graph(packages);
+  t.assert.eq(
+    edges_of(graph, 'calls'),
+    'shapes:Square.Area>geom:Mul shapes:Describe>shapes:Square.Area shapes:Describe>shapes:format',
+    'Should resolve calls'
+  );
+  const format = graph.edges.find((e) => e.target === 'shapes:format');
+  t.assert.eq(format.count, 2, 'Repeated calls should be merged');
+});
+
+await test('build_symbol_graph finds embedding and implementations', async (t) => {
+  const graph = build_symbol_graph(packages);
+  t.assert.eq(edges_of(graph, 'embeds'), 'shapes:Named>shapes:Shape shapes:Square>shapes:Base', 'Should find embeds');
+  t.assert.eq(
+    edges_of(graph, 'implements'),
+    'shapes:Square>shapes:Shape shapes:Square>shapes:Named',
+    'Promoted methods should count towards implementations'
+  );
+});
+
+await test('value and pointer method sets differ for pointer receivers', async (t) => {
+  const [pkg] = group_go_packages([
+    parse_go_file(readFileSync('./tests/fixtures/methodsets.go', 'utf-8'), 'counter/counter.go')
+  ]);
+  t.assert.eq([...get_value_method_set('Counter', pkg)].join(' '), 'Value()(int)', 'Value set should only have value receivers');
+  t.assert.eq([...get_pointer_method_set('Counter', pkg)].sort().join(' '), 'Increment()() Value()(int)', 'Pointer set should have every method');
+  t.assert.ok(get_value_method_set('Tracked', pkg).has('Increment()()'), 'Embedded pointers should promote pointer methods');
+  t.assert.ok(!get_value_method_set('Wrapped', pkg).has('Increment()()'), 'Embedded values should not promote pointer methods');
+
+  const receivers = build_symbol_graph([pkg])
+    .edges.filter((e) => e.type === 'implements')
+    .map((e) => `${e.source}>${e.target}:${e.receiver}`)
+    .sort()
+    .join(' ');
+  t.assert.eq(
+    receivers,
+    'counter:Counter>counter:Incrementer:pointer counter:Counter>counter:Valuer:value ' +
+      'counter:Tracked>counter:Incrementer:value counter:Tracked>counter:Valuer:value ' +
+      'counter:Wrapped>counter:Incrementer:pointer counter:Wrapped>counter:Valuer:value',
+    'Implementations should say whether values or only pointers satisfy'
+  );
+});
+
+await test('build_symbol_graph filters edge types', async (t) => {
+  const graph = build_symbol_graph(packages, { edge_types: ['embeds'] });
+  t.assert.eq(graph.edges.length, 2, 'Should only have embeds');
+  t.assert.eq(Object.keys(graph.summary.edges_by_type).join(','), 'embeds', 'Should only count embeds');
+});
+
+// ============ get_method_key tests ============
+
+await test('get_method_key ignores parameter names', async (t) => {
+  const [a] = parse_go_file('package p\nfunc (x T) M(a ...string) (n int) {}\n').functions;
+  const [b] = parse_go_file('package p\nfunc (y *T) M(b ...string) int {}\n').functions;
+  t.assert.eq(get_method_key(a), get_method_key(b), 'Keys should match');
+  t.assert.eq(get_method_key(a), 'M(...string)(int)', 'Should include types');
+});
+
+// ============ render_graph_html tests ============
+
+await test('render_graph_html embeds the graph in a standalone page', async (t) => {
+  const graph = build_symbol_graph(packages);
+  graph.nodes[0].name = '</script>';
+  const html = render_graph_html(graph, { title: 'A & B' });
+  t.assert.ok(html.startsWith('<!DOCTYPE html>'), 'Should be an HTML document');
+  t.assert.ok(html.includes('<title>A &amp; B</title>'), 'Should escape the title');
+  t.assert.ok(!html.includes('"</script>"'), 'Should not close the data script');
+  t.assert.ok(!/<script[^>]+src=/.test(html), 'Should not load external scripts');
+  const data = html.match(/id="graph-data">([\s\S]*?)<\/script>/)[1];
+  t.assert.eq(JSON.parse(data).nodes.length, graph.nodes.length, 'Should embed the graph JSON');
+});
@@ -0,0 +1,107 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go interface method usage functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { find_source_unused_interface_methods } from '../../../lib/analysis/ifacemethods.mjs';
+
+const sources = [
+  {
+    filename: 'store/store.go',
+    source: readFileSync('./tests/fixtures/interface_methods.go', 'utf-8')
+  }
+];
+
+const { unused, interfaces, summary } =
+  find_source_unused_interface_methods(sources);
+const find_unused = (symbol) =>
+  unused.find((m) => `${m.interface}.${m.method}` === symbol);
+const find_interface = (name) => interfaces.find((i) => i.name === name);
+
+// ============ find_source_unused_interface_methods tests ============
+
+await test('find_source_unused_interface_methods finds methods of a partially used interface', async (t) => {
+  const store = find_interface('Store');
+  t.assert.eq(store.classification, 'partial', 'Should classify partially used interfaces');
+  t.assert.eq(store.used.join(','), 'Get,Set,Delete', 'Should track parameters, fields and ranged collections');
+  const flush = find_unused('Store.Flush');
+  t.assert.eq(flush.line, 8, 'Should report the line of the method');
+  t.assert.eq(flush.signature, 'Flush() error', 'Should include the signature');
+  t.assert.ok(flush.interface_called, 'Should tell the interface is called otherwise');
+});
+
+await test('find_source_unused_interface_methods follows embedded interfaces', async (t) => {
+  t.assert.eq(find_interface('Reader').used.join(','), 'Keys', 'Should count calls through embedding interfaces for the declaring one');
+  t.assert.ok(find_unused('Reader.Get'), 'Should not count calls through other interfaces');
+  t.assert.eq(find_interface('ReadCloser').classification, 'used', 'Should track locals assigned a function result');
+});
+
+await test('find_source_unused_interface_methods tracks assertions and type parameters', async (t) => {
+  const cache = find_interface('cache');
+  t.assert.eq(cache.used.join(','), 'lookup,reset', 'Should track assertions and constraints');
+  const evict = find_unused('cache.evict');
+  t.assert.eq(evict.confidence, 'high', 'Should be confident without uncertainty');
+  t.assert.eq(evict.message, 'cache.evict is never called through the interface; consider removing it from cache', 'Should describe the unused method');
+});
+
+await test('find_source_unused_interface_methods reports uncertainty', async (t) => {
+  t.assert.eq(find_unused('Store.Flush').uncertainty.join(','), 'exported', 'Should flag exported interfaces');
+  t.assert.eq(find_unused('cache.String').uncertainty.join(','), 'standard_method', 'Should flag standard method names');
+  const log = find_unused('logger.log');
+  t.assert.eq(log.uncertainty.join(','), 'struct_embedding', 'Should flag interfaces embedded in structs');
+  t.assert.eq(log.confidence, 'low', 'Should lower the confidence');
+  t.assert.eq(find_interface('logger').classification, 'unused', 'Should classify interfaces without calls');
+
+  const reflected = find_source_unused_interface_methods([
+    ...sources,
+    {
+      filename: 'store/call.go',
+      source: 'package store\n\nimport "reflect"\n\nfunc call(v any, name string) {\n\treflect.ValueOf(v).MethodByName(name).Call(nil)\n}\n'
+    }
+  ]);
+  const evict = reflected.unused.find((m) => m.method === 'evict');
+  t.assert.eq(evict.uncertainty.join(','), 'reflection', 'Should flag calls by reflection');
+});
+
+await test('find_source_unused_interface_methods leaves test calls out', async (t) => {
+  const report = find_source_unused_interface_methods([
+    ...sources,
+    {
+      filename: 'store/store_test.go',
+      source: 'package store\n\nfunc flush(st Store) {\n\tst.Flush()\n}\n'
+    }
+  ]);
+  const flush = report.unused.find((m) => m.method === 'Flush');
+  t.assert.eq(flush.test_calls, 1, 'Should count calls in tests apart');
+  t.assert.ok(flush.message.includes('only called in tests'), 'Should say the method is only called in tests');
+  t.assert.eq(report.summary.test_only, 1, 'Should count methods only called in tests');
+});
+
+await test('find_source_unused_interface_methods tracks qualified interfaces', async (t) => {
+  const report = find_source_unused_interface_methods([
+    ...sources,
+    {
+      filename: 'app/app.go',
+      source: 'package app\n\nimport "example.com/store"\n\nfunc flush(st store.Store) error {\n\treturn st.Flush()\n}\n'
+    }
+  ]);
+  t.assert.ok(!report.unused.find((m) => m.method === 'Flush'), 'Should track interfaces of imported packages');
+});
+
+await test('find_source_unused_interface_methods filters', async (t) => {
+  const unexported = find_source_unused_interface_methods(sources, { unexported_only: true });
+  t.assert.ok(unexported.unused.every((m) => !m.exported), 'Should only report unexported interfaces on request');
+  const high = find_source_unused_interface_methods(sources, { confidence: 'high' });
+  t.assert.eq(high.unused.map((m) => m.method).join(','), 'evict', 'Should filter by confidence');
+});
+
+await test('find_source_unused_interface_methods summarizes', async (t) => {
+  t.assert.eq(summary.interfaces, 5, 'Should count interfaces');
+  t.assert.eq(summary.partial_interfaces, 3, 'Should count partially used interfaces');
+  t.assert.eq(summary.methods, 13, 'Should count methods');
+  t.assert.eq(summary.unused, 6, 'Should count unused methods');
+  t.assert.eq(summary.by_confidence.high, 1, 'Should count unused methods by confidence');
+});
@@ -0,0 +1,80 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for diff impact functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  summarize_diff_impact,
+  parse_unified_diff,
+  reverse_apply_hunks
+} from '../../../lib/analysis/impact.mjs';
+
+const old_source = readFileSync('./tests/fixtures/impact_old.go', 'utf-8');
+const new_source = readFileSync('./tests/fixtures/impact_new.go', 'utf-8');
+const diff = readFileSync('./tests/fixtures/impact.diff', 'utf-8');
+
+const added_file = `diff --git a/store/doc.go b/store/doc.go
+new file mode 100644
+--- /dev/null
++++ b/store/doc.go
+@@ -0,0 +1,4 @@
++// Package store keeps items.
++package store
++
++func Open() {}
+`;
+
+const deleted_file = `--- a/store/old.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package store
+-
+-var legacy = true
+`;
+
+// ============ parse_unified_diff tests ============
+
+await test('parse_unified_diff records changed lines', async (t) => {
+  const [file] = parse_unified_diff(diff);
+  t.assert.eq(file.new_path, 'store/store.go', 'Should strip the b/ prefix');
+  t.assert.eq(file.status, 'modified', 'Should detect modified files');
+  t.assert.eq(file.hunks.length, 1, 'Should find the hunk');
+  t.assert.eq(file.hunks[0].removed.join(','), '8,9,10,13,14,15', 'Should number removed lines');
+  t.assert.eq(file.hunks[0].added[0], 8, 'Should number added lines');
+});
+
+await test('parse_unified_diff detects added and deleted files', async (t) => {
+  const files = parse_unified_diff(added_file + deleted_file);
+  t.assert.eq(files.length, 2, 'Should find both files');
+  t.assert.eq(files[0].status, 'added', 'Should detect added files');
+  t.assert.eq(files[0].old_path, null, 'Added files have no old path');
+  t.assert.eq(files[1].status, 'deleted', 'Should detect deleted files');
+  t.assert.eq(files[1].old_path, 'store/old.go', 'Should keep the old path');
+});
+
+await test('reverse_apply_hunks rebuilds the old version', async (t) => {
+  const [file] = parse_unified_diff(diff);
+  t.assert.eq(reverse_apply_hunks(new_source, file.hunks), old_source, 'Should rebuild the old file');
+  t.assert.eq(reverse_apply_hunks('package other\n', file.hunks), null, 'Should reject mismatching files');
+});
+
+// ============ summarize_diff_impact tests ============
+
+await test('summarize_diff_impact maps hunks onto symbols', async (t) => {
+  const result = await summarize_diff_impact(diff, async () => new_source);
+  const changes = result.files[0].symbols.map((s) => `${s.change}:${s.name}`);
+  t.assert.eq(changes.join(' '), 'added:Store.Set removed:Store.Reset modified:Store.Get', 'Should list symbol changes');
+  t.assert.ok(!changes.some((c) => c.includes('version')), 'Unchanged symbols are not listed');
+  t.assert.eq(result.summary.symbols_modified, 1, 'Should count modified symbols');
+});
+
+await test('summarize_diff_impact handles added and deleted files', async (t) => {
+  const sources = { 'store/doc.go': '// Package store keeps items.\npackage store\n\nfunc Open() {}\n' };
+  const result = await summarize_diff_impact(added_file + deleted_file, async (p) => sources[p] || null);
+  t.assert.eq(result.files[0].symbols.map((s) => `${s.change}:${s.name}`).join(' '), 'added:Open', 'Should list added symbols');
+  t.assert.eq(result.files[1].symbols.map((s) => `${s.change}:${s.name}`).join(' '), 'removed:legacy', 'Should list removed symbols');
+  t.assert.eq(result.summary.files_deleted, 1, 'Should count deleted files');
+});
@@ -0,0 +1,86 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go import usage functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_import_usage,
+  find_qualified_identifiers,
+  get_default_import_name
+} from '../../../lib/analysis/imports.mjs';
+
+const source = readFileSync('./tests/fixtures/unused_imports.go', 'utf-8');
+const [file] = find_import_usage([
+  { filename: 'report/report.go', source }
+]);
+const import_of = (path) => file.imports.find((imp) => imp.path === path);
+
+// ============ get_default_import_name tests ============
+
+await test('get_default_import_name strips major versions', async (t) => {
+  t.assert.eq(get_default_import_name('fmt'), 'fmt', 'Standard library');
+  t.assert.eq(get_default_import_name('net/http'), 'http', 'Last element');
+  t.assert.eq(get_default_import_name('gopkg.in/yaml.v3'), 'yaml', 'gopkg.in version');
+  t.assert.eq(get_default_import_name('github.com/a/units/v2'), 'units', 'Major version element');
+  t.assert.eq(get_default_import_name('github.com/mattn/go-sqlite3'), 'sqlite3', 'go- prefix');
+});
+
+// ============ find_qualified_identifiers tests ============
+
+await test('find_qualified_identifiers ignores comments, strings and selectors', async (t) => {
+  const uses = find_qualified_identifiers(
+    'package p\n\n// os.Exit\nfunc f() {\n\ts := "io.EOF"\n\ta.b.Run()\n\tfmt.Println(s)\n}\n'
+  );
+  t.assert.eq(uses.has('os'), false, 'Comments are ignored');
+  t.assert.eq(uses.has('io'), false, 'Strings are ignored');
+  t.assert.eq(uses.has('b'), false, 'Selectors are not qualifiers');
+  t.assert.eq(uses.get('a').count, 1, 'a is a qualifier');
+  t.assert.eq(uses.get('fmt').line, 7, 'Should have the first line');
+});
+
+// ============ find_import_usage tests ============
+
+await test('find_import_usage reports unused imports with their span', async (t) => {
+  t.assert.eq(file.unused.map((imp) => imp.path).join(', '), 'os, gopkg.in/yaml.v3', 'os and yaml are unused');
+
+  const os = file.unused[0];
+  t.assert.eq(os.line, 9, 'Should have the line');
+  t.assert.eq(os.column, 2, 'Should start at the opening quote');
+  t.assert.eq(os.end_column, 6, 'Should end after the closing quote');
+  t.assert.eq(os.message, '"os" imported and not used', 'Should have a message');
+
+  const yaml = file.unused[1];
+  t.assert.eq(yaml.column, 2, 'Span includes the import name');
+  t.assert.eq(yaml.message, '"gopkg.in/yaml.v3" imported as yaml and not used', 'Message names the alias');
+});
+
+await test('find_import_usage treats blank and dot imports as used', async (t) => {
+  t.assert.eq(import_of('embed').kind, 'blank', 'embed is a blank import');
+  t.assert.eq(import_of('embed').used, true, 'Blank imports are used');
+  t.assert.eq(import_of('math').kind, 'dot', 'math is a dot import');
+  t.assert.eq(import_of('math').used, true, 'Dot imports are used');
+});
+
+await test('find_import_usage resolves import names', async (t) => {
+  t.assert.eq(import_of('strconv').name, 'str', 'Explicit names are used');
+  t.assert.eq(import_of('strconv').used, true, 'str.Itoa uses strconv');
+  t.assert.eq(import_of('github.com/example/units/v2').used, true, 'Major versions are stripped');
+  t.assert.eq(import_of('github.com/example/units/v2').guessed, true, 'Names outside the standard library are guessed');
+  t.assert.eq(import_of('fmt').guessed, false, 'Standard library names are known');
+});
+
+await test('find_import_usage uses project package names', async (t) => {
+  const [main] = find_import_usage([
+    { filename: 'lib/go-units/units.go', source: 'package units\n' },
+    {
+      filename: 'main.go',
+      source: 'package main\n\nimport "example.com/lib/go-units"\n\nvar x units.Length\n'
+    }
+  ]);
+  t.assert.eq(main.imports[0].name, 'units', 'Should use the package clause');
+  t.assert.eq(main.imports[0].guessed, false, 'Project package names are known');
+  t.assert.eq(main.unused.length, 0, 'units is used');
+});
@@ -0,0 +1,55 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go package initialization order functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file, group_go_packages } from '../../../lib/analysis/golang.mjs';
+import {
+  analyze_package_init_order,
+  find_name_references
+} from '../../../lib/analysis/initorder.mjs';
+
+const load_package = (name) => {
+  const source = readFileSync(`./tests/fixtures/${name}.go`, 'utf-8');
+  return group_go_packages([parse_go_file(source, `${name}/${name}.go`)])[0];
+};
+
+const config = analyze_package_init_order(load_package('init_order'));
+const cycle = analyze_package_init_order(load_package('init_cycle'));
+const get_variable = (result, name) => result.variables.find((v) => v.name === name);
+
+// ============ find_name_references tests ============
+
+await test('find_name_references skips selectors and literal keys', async (t) => {
+  const names = new Set(['a', 'b', 'c', 'd']);
+  const references = find_name_references('T{a: b, c}.d + b', names, 4);
+  t.assert.eq(references.map((r) => r.name).join(','), 'b,c', 'Should only find references');
+  t.assert.eq(references[0].line, 4, 'Should record the line');
+});
+
+// ============ analyze_package_init_order tests ============
+
+await test('analyze_package_init_order follows dependencies through functions', async (t) => {
+  const dir = get_variable(config, 'Dir');
+  t.assert.eq(dir.depends_on.map((d) => `${d.kind}:${d.name}`).join(','), 'func:defaultDir', 'Should record the direct dependency');
+  t.assert.eq(dir.requires.join(','), 'home', 'Should require the variables of called functions');
+  t.assert.eq(get_variable(config, 'greeting').requires.length, 0, 'Parameters shadow package variables');
+});
+
+await test('analyze_package_init_order computes the Go initialization order', async (t) => {
+  t.assert.eq(config.order.slice(0, 5).join(','), 'home,Dir,Path,Loaded,Err', 'Should initialize dependencies first');
+  t.assert.ok(get_variable(config, 'Path').reordered, 'Path depends on a later variable');
+  t.assert.ok(!get_variable(config, 'settings').reordered, 'settings depends on an earlier variable');
+  t.assert.eq(config.cycles.length, 0, 'Should find no cycles');
+});
+
+await test('analyze_package_init_order reports initialization cycles', async (t) => {
+  t.assert.eq(cycle.cycles.length, 1, 'Should find one cycle');
+  t.assert.eq(cycle.cycles[0].path.join(' '), 'a b f a', 'Should report the chain of references');
+  t.assert.ok(cycle.cycles[0].message.includes('f refers to a'), 'Should describe the cycle');
+  t.assert.eq(cycle.uninitialized.join(','), 'a,b', 'Variables in the cycle cannot be initialized');
+  t.assert.eq(cycle.order.join(','), 'c', 'Recursive functions are not cycles');
+});
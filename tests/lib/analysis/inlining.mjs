@@ -0,0 +1,79 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go inlining hint functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_inline_hints,
+  get_inline_hint,
+  estimate_inline_cost
+} from '../../../lib/analysis/inlining.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/inlining.go', 'utf-8'),
+    'mathx/mathx.go'
+  )
+]);
+const hints = find_inline_hints(packages);
+const hint_of = (name) => hints.find((fn) => fn.function === name).inline_hint;
+
+// ============ estimate_inline_cost tests ============
+
+await test('estimate_inline_cost counts identifiers, literals and operators', async (t) => {
+  t.assert.eq(estimate_inline_cost(' return a + b '), 4, 'return a + b');
+  t.assert.eq(estimate_inline_cost(' return p.X + 1 '), 4, 'selectors');
+  t.assert.eq(estimate_inline_cost(' f(x, y) '), 3, 'delimiters');
+});
+
+// ============ get_inline_hint tests ============
+
+await test('get_inline_hint flags small functions as inlinable', async (t) => {
+  t.assert.ok(hint_of('Add').inlinable, 'Add');
+  t.assert.eq(hint_of('Add').reasons, [], 'no reasons');
+  t.assert.ok(hint_of('Point.Norm1').inlinable, 'small method');
+});
+
+await test('get_inline_hint gives the reasons of non-candidates', async (t) => {
+  t.assert.eq(
+    hint_of('RecursiveFactorial').reasons,
+    ['recursive', 'multiple_returns'],
+    'RecursiveFactorial'
+  );
+  t.assert.eq(hint_of('abs').reasons, ['multiple_returns'], 'abs');
+  t.assert.eq(hint_of('Sum').reasons, ['loop'], 'Sum');
+  t.assert.eq(hint_of('Locked').reasons, ['defer'], 'Locked');
+  t.assert.eq(hint_of('Adder').reasons, ['closure'], 'closure returns');
+  t.assert.eq(hint_of('Pinned').reasons, ['noinline'], 'directive');
+  t.assert.eq(hint_of('Polynomial').reasons, ['cost'], 'over budget');
+});
+
+await test('get_inline_hint uses the budget', async (t) => {
+  const [fn] = packages[0].functions.filter((f) => f.name === 'Polynomial');
+
+  t.assert.ok(get_inline_hint(fn, { budget: 200 }).inlinable, 'larger budget');
+  t.assert.eq(
+    get_inline_hint({ name: 'f', doc: null, body: null }).reasons,
+    ['no_body'],
+    'no body'
+  );
+});
+
+// ============ find_inline_hints tests ============
+
+await test('find_inline_hints lists candidates only', async (t) => {
+  const candidates = find_inline_hints(packages, { inlinable_only: true });
+
+  t.assert.eq(
+    candidates.map((fn) => fn.function),
+    ['Add', 'Point.Norm1'],
+    'candidates'
+  );
+});
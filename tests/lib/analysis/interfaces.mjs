@@ -0,0 +1,368 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go interface near-miss functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_near_miss,
+  find_near_misses,
+  find_method_sets,
+  find_interface_widths,
+  find_interface_uses,
+  find_unused_interfaces,
+  find_method_implementers,
+  parse_method_signature,
+  format_method_signature,
+  find_handlers_of_shape,
+  parse_handler_shape
+} from '../../../lib/analysis/interfaces.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/near_miss.go', 'utf-8'),
+    'geo/shapes.go'
+  ),
+  parse_go_file(
+    'package draw\n\ntype Canvas struct{}\n\n' +
+      'func (c Canvas) Area() float64 { return 0 }\n',
+    'draw/canvas.go'
+  )
+]);
+
+// ============ format_method_signature tests ============
+
+await test('format_method_signature formats results like Go', async (t) => {
+  t.assert.eq(
+    format_method_signature({ name: 'Perimeter', params: [], results: [{ name: null, type: 'float64' }] }),
+    'Perimeter() float64',
+    'Should format a single unnamed result without parentheses'
+  );
+  t.assert.eq(
+    format_method_signature({
+      name: 'Read',
+      params: [{ name: 'p', type: '[]byte' }],
+      results: [{ name: 'n', type: 'int' }, { name: 'err', type: 'error' }]
+    }),
+    'Read(p []byte) (n int, err error)',
+    'Should keep parameter and result names'
+  );
+});
+
+// ============ find_near_miss tests ============
+
+await test('find_near_miss reports the missing method of Triangle', async (t) => {
+  const report = find_near_miss(packages, 'Triangle', 'Shape');
+  t.assert.ok(!report.satisfies, 'Triangle should not satisfy Shape');
+  t.assert.eq(report.present.length, 1, 'Should have one present method');
+  t.assert.eq(report.present[0], 'Area() float64', 'Should list Area as present');
+  t.assert.eq(report.missing.length, 1, 'Should miss one method');
+  t.assert.eq(
+    report.missing[0].signature,
+    'Perimeter() float64',
+    'Should give the exact missing signature'
+  );
+  t.assert.ok(
+    report.message.includes('1 method away'),
+    'Should say the type is one method away'
+  );
+  t.assert.eq(report.pointer_methods[0], 'Area', 'Should note pointer receivers');
+});
+
+await test('find_near_miss reports mismatched signatures', async (t) => {
+  const report = find_near_miss(packages, 'Square', 'Shape');
+  t.assert.eq(report.missing.length, 0, 'Should not report Perimeter missing');
+  t.assert.eq(report.mismatched.length, 1, 'Should report one mismatch');
+  t.assert.eq(report.mismatched[0].expected, 'Perimeter() float64', 'Should give the wanted signature');
+  t.assert.eq(report.mismatched[0].actual, 'Perimeter() int', 'Should give the declared signature');
+});
+
+await test('find_near_miss resolves embedded interfaces and promoted methods', async (t) => {
+  const circle = find_near_miss(packages, 'Circle', 'Shape');
+  t.assert.ok(circle.satisfies, 'Circle should satisfy Shape');
+  t.assert.ok(circle.value_satisfies, 'Circle should satisfy Shape by value');
+  t.assert.ok(!circle.message, 'Should have no message when satisfied');
+
+  const solid = find_near_miss(packages, 'Circle', 'geo.Solid');
+  t.assert.eq(solid.missing[0].signature, 'Volume() float64', 'Should include embedded methods');
+
+  const cube = find_near_miss(packages, 'Cube', 'Solid');
+  t.assert.eq(cube.present.length, 2, 'Should use promoted methods');
+  t.assert.eq(cube.mismatched[0].name, 'Perimeter', 'Should report the promoted mismatch');
+});
+
+await test('find_near_miss qualifies types across packages and validates names', async (t) => {
+  const report = find_near_miss(packages, 'draw.Canvas', 'Shape');
+  t.assert.eq(report.missing[0].signature, 'Perimeter() float64', 'Should compare across packages');
+
+  let error = null;
+  try {
+    find_near_miss(packages, 'Hexagon', 'Shape');
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error && error.message.includes("'Hexagon' not found"), 'Should throw for unknown types');
+});
+
+// ============ find_near_misses tests ============
+
+await test('find_near_misses lists pairs within max_missing methods', async (t) => {
+  const reports = find_near_misses(packages);
+  const pairs = reports.map((r) => `${r.type}:${r.interface}`);
+  t.assert.ok(pairs.includes('Triangle:Shape'), 'Should report Triangle');
+  t.assert.ok(pairs.includes('Square:Shape'), 'Should report Square');
+  t.assert.ok(pairs.includes('Circle:Solid'), 'Should report Circle for Solid');
+  t.assert.ok(!pairs.includes('Circle:Shape'), 'Should not report satisfied pairs');
+  t.assert.ok(!pairs.includes('Label:Shape'), 'Should not report types sharing only a name');
+  t.assert.ok(!pairs.includes('Triangle:Solid'), 'Should not report pairs beyond max_missing');
+
+  const wider = find_near_misses(packages, { max_missing: 2 });
+  t.assert.ok(wider.length >= reports.length, 'Should widen with max_missing');
+  t.assert.ok(
+    !wider.some((r) => r.type === 'Triangle' && r.interface === 'Solid'),
+    'Should require as many present methods as lacking ones'
+  );
+});
+
+// ============ find_method_sets tests ============
+
+const counters = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/methodsets.go', 'utf-8'),
+    'counter/counter.go'
+  )
+]);
+
+await test('find_method_sets separates value and pointer method sets', async (t) => {
+  const sets = find_method_sets(counters, 'Counter');
+  t.assert.eq(sets.value_method_set.join(', '), 'Value() int', 'Value set should lack Increment');
+  t.assert.eq(sets.pointer_method_set.join(', '), 'Increment(), Value() int', 'Pointer set should have both');
+  t.assert.eq(sets.pointer_only.join(', '), 'Increment', 'Should list pointer only methods');
+
+  const satisfied = sets.interfaces.map((i) => `${i.interface}:${i.value}`).join(' ');
+  t.assert.eq(satisfied, 'Incrementer:false Valuer:true', 'Only *Counter should satisfy Incrementer');
+});
+
+await test('find_method_sets follows embedded pointers and values', async (t) => {
+  const tracked = find_method_sets(counters, 'Tracked');
+  t.assert.eq(tracked.pointer_only.length, 0, 'Embedded *Counter should promote Increment to values');
+  t.assert.ok(tracked.interfaces.every((i) => i.value), 'Tracked values should satisfy both');
+
+  const wrapped = find_method_sets(counters, 'counter.Wrapped');
+  t.assert.eq(wrapped.pointer_only.join(', '), 'Increment', 'Embedded Counter should keep Increment on the pointer');
+
+  const report = find_near_miss(counters, 'Tracked', 'Incrementer');
+  t.assert.ok(report.value_satisfies, 'Near misses should use the value method set');
+  t.assert.ok(!find_near_miss(counters, 'Wrapped', 'Incrementer').value_satisfies, 'Wrapped values should not satisfy');
+});
+
+// ============ find_interface_widths tests ============
+
+const widths = find_interface_widths(
+  group_go_packages([
+    parse_go_file(
+      readFileSync('./tests/fixtures/interface_width.go', 'utf-8'),
+      'store/store.go'
+    )
+  ])
+);
+const width_of = (name) => widths.find((i) => i.name === name);
+
+await test('find_interface_widths classifies narrow and wide interfaces', async (t) => {
+  t.assert.eq(width_of('Getter').classification, 'narrow', 'Single method interfaces are narrow');
+  t.assert.eq(width_of('Store').method_count, 12, 'Should count embedded methods');
+  t.assert.eq(width_of('Store').classification, 'wide', 'Interfaces over the threshold are wide');
+  t.assert.ok(width_of('Store').message.includes('12 methods'), 'Should explain wide interfaces');
+  t.assert.eq(widths[0].name, 'Store', 'Widest interfaces come first');
+
+  const shapes = find_interface_widths(packages);
+  const shape = shapes.find((i) => i.name === 'Shape');
+  t.assert.eq(shape.method_count, 2, 'Shape has two methods');
+  t.assert.eq(shape.classification, 'regular', 'Two methods are neither narrow nor wide');
+  t.assert.eq(
+    find_interface_widths(packages, { max_methods: 2 }).find((i) => i.name === 'Solid').classification,
+    'wide',
+    'The threshold should be configurable'
+  );
+});
+
+await test('find_interface_widths resolves standard embeds and type sets', async (t) => {
+  t.assert.eq(width_of('Blob').methods.join(','), 'Close,Read', 'Should resolve io.ReadCloser');
+  t.assert.eq(width_of('Blob').classification, 'regular', 'io.ReadCloser has two methods');
+  t.assert.ok(!width_of('Encoder').complete, 'Unknown embeds make the count incomplete');
+  t.assert.eq(width_of('Encoder').unresolved_embeds.join(','), 'codec.Encoder', 'Should list unresolved embeds');
+  t.assert.eq(width_of('Encoder').classification, 'regular', 'Incomplete interfaces are not narrow or empty');
+  t.assert.eq(width_of('Any').classification, 'empty', 'interface{} is empty');
+  t.assert.eq(width_of('Number').classification, 'constraint', 'Type sets are constraints');
+});
+
+// ============ find_unused_interfaces tests ============
+
+const unused_packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/unused_interfaces.go', 'utf-8'),
+    'shapes/shapes.go'
+  ),
+  parse_go_file(
+    readFileSync('./tests/fixtures/classes_structs.go', 'utf-8'),
+    'models/models.go'
+  ),
+  parse_go_file(
+    'package draw\n\nimport geo "example.com/app/shapes"\n\n' +
+      'type Canvas struct{}\n\n' +
+      'func (c Canvas) Sort(items []geo.Comparable[int]) {}\n',
+    'draw/canvas.go'
+  ),
+  parse_go_file(
+    'package shapes\n\ntype fake interface{ Fake() }\n\n' +
+      'func use(f fake) {}\n',
+    'shapes/shapes_test.go'
+  )
+]);
+
+await test('find_interface_uses counts uses by kind', async (t) => {
+  const uses = Object.fromEntries(
+    find_interface_uses(unused_packages).map((i) => [`${i.directory}:${i.name}`, i])
+  );
+  t.assert.eq(uses['shapes:Shape'].uses.parameter, 1, 'Should count parameter types');
+  t.assert.eq(uses['shapes:Shape'].uses.method, 1, 'Should count the method signatures of other interfaces');
+  t.assert.eq(uses['shapes:Sized'].uses.embed, 1, 'Should count the embeds of other interfaces');
+  t.assert.eq(uses['shapes:Measured'].uses.field, 1, 'Should count struct field types');
+  t.assert.eq(uses['shapes:Number'].uses.constraint, 1, 'Should count type parameter constraints');
+  t.assert.eq(uses['shapes:renderer'].uses.expression, 1, 'Should count type assertions in function bodies');
+  t.assert.eq(uses['shapes:Comparable'].uses.parameter, 1, 'Should count qualified uses from importing packages');
+  t.assert.eq(uses['shapes:Painter'].used, false, 'Should not count compile-time assertions as uses');
+  t.assert.eq(uses['shapes:Painter'].assertions, 1, 'Should count compile-time assertions separately');
+  t.assert.eq(uses['shapes:visitor'].used, false, 'Should not count an interface referring to itself');
+  t.assert.ok(!uses['shapes:fake'], 'Should leave out interfaces of test files');
+});
+
+await test('find_unused_interfaces reports unused interfaces by visibility', async (t) => {
+  const unused = find_unused_interfaces(unused_packages);
+  t.assert.eq(
+    unused.map((i) => `${i.directory}:${i.name}`).join(','),
+    'models:Animal,models:Shape,models:ReadWriter,models:Comparable,shapes:Painter,shapes:visitor',
+    'Should report the unused interfaces, including Comparable of classes_structs.go'
+  );
+  const by_name = Object.fromEntries(unused.map((i) => [i.name, i]));
+  t.assert.eq(by_name.Painter.severity, 'info', 'Should report exported interfaces as possible public API');
+  t.assert.ok(by_name.Painter.message.includes('public API'), 'Should mention the public API for exported interfaces');
+  t.assert.eq(by_name.visitor.severity, 'warning', 'Should warn about unexported interfaces');
+  t.assert.eq(
+    find_unused_interfaces(unused_packages, { unexported_only: true }).map((i) => i.name).join(','),
+    'visitor',
+    'Should only report unexported interfaces when asked'
+  );
+});
+
+// ============ find_method_implementers tests ============
+
+const implementer_packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/method_implementers.go', 'utf-8'),
+    'shapes/shapes.go'
+  ),
+  parse_go_file(
+    'package log\n\ntype Level int\n\n' +
+      'func (l Level) String() string { return "info" }\n\n' +
+      'func (l Level) Close() {}\n',
+    'log/level.go'
+  ),
+  parse_go_file(
+    'package shapes\n\ntype fakePoint struct{}\n\n' +
+      'func (f fakePoint) String() string { return "" }\n',
+    'shapes/shapes_test.go'
+  )
+]);
+
+await test('parse_method_signature parses signatures with or without a name', async (t) => {
+  const method = parse_method_signature('Read', '(p []byte) (n int, err error)');
+  t.assert.eq(method.params.map((p) => p.type).join(','), '[]byte', 'Should parse the parameters');
+  t.assert.eq(method.results.map((r) => r.type).join(','), 'int,error', 'Should parse the results');
+  t.assert.eq(
+    parse_method_signature('String', 'String() string').results[0].type,
+    'string',
+    'Should accept the method name in the signature'
+  );
+  let error = null;
+  try {
+    parse_method_signature('String', 'string');
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject a signature without parameter list');
+});
+
+await test('find_method_implementers finds types sharing a method shape', async (t) => {
+  const strings = find_method_implementers(implementer_packages, 'String', '() string');
+  t.assert.eq(strings.method, 'String() string', 'Should format the method signature');
+  t.assert.eq(
+    strings.implementers.map((i) => `${i.directory}:${i.type}`).join(','),
+    'log:Level,shapes:Point,shapes:Polygon,shapes:Color',
+    'Should find the types of all packages, ignoring result names and test files'
+  );
+  const by_type = Object.fromEntries(strings.implementers.map((i) => [i.type, i]));
+  t.assert.eq(by_type.Polygon.pointer_receiver, true, 'Should flag pointer receivers');
+  t.assert.eq(by_type.Point.pointer_receiver, false, 'Should flag value receivers');
+  t.assert.eq(
+    strings.mismatches.map((i) => `${i.type} ${i.signature}`).join(','),
+    'Grid String(indent int) string',
+    'Should report methods of the same name with another signature, stripping type arguments'
+  );
+
+  const closers = find_method_implementers(implementer_packages, 'Close', 'Close() error');
+  t.assert.eq(
+    closers.implementers.map((i) => i.type).join(','),
+    'Polygon,Grid',
+    'Should match the full signature'
+  );
+  t.assert.eq(closers.mismatches.length, 2, 'Should report the other Close methods as mismatches');
+});
+
+// ============ find_handlers_of_shape tests ============
+
+const handler_packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/handler_shapes.go', 'utf-8'),
+    'web/web.go'
+  )
+]);
+
+const handlers_of = (shape, options) =>
+  find_handlers_of_shape(handler_packages, shape, options)
+    .handlers.map((h) => h.symbol)
+    .join(',');
+
+await test('find_handlers_of_shape finds functions by signature', async (t) => {
+  t.assert.eq(
+    handlers_of('func(http.ResponseWriter, *http.Request)'),
+    'Index,Health,Server.ServeHTTP',
+    'Should match functions and methods whatever their parameter names'
+  );
+  t.assert.eq(handlers_of('func(context.Context) error'), 'Migrate,Seed', 'Should match results and follow renamed imports');
+  t.assert.eq(handlers_of('func(context.Context, *web.Request) error'), 'Handle', 'Should match package types qualified by the package name');
+  t.assert.eq(handlers_of('func(_) error'), 'Migrate,Seed', 'Should match any type with _');
+  t.assert.eq(handlers_of('func(string, ...any)'), 'Log', 'Should match variadic parameters and any');
+  t.assert.eq(handlers_of('func(http.ResponseWriter, *http.Request)', { functions_only: true }), 'Index,Health', 'Should leave out methods with functions_only');
+  const result = find_handlers_of_shape(handler_packages, '(w http.ResponseWriter, r *http.Request)');
+  t.assert.eq(result.shape, 'func(w http.ResponseWriter, r *http.Request)', 'Should accept shapes without func');
+  t.assert.eq(result.handlers[0].signature, 'func Index(w http.ResponseWriter, r *http.Request)', 'Should give the signature of each handler');
+});
+
+await test('parse_handler_shape rejects invalid shapes', async (t) => {
+  for (const shape of ['func', 'error', '(ctx context.Context, _) error']) {
+    let message = null;
+    try {
+      parse_handler_shape(shape);
+    } catch (error) {
+      message = error.message;
+    }
+    t.assert.eq(message, `Invalid handler shape '${shape}'`, `Should reject ${shape}`);
+  }
+});
@@ -0,0 +1,64 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go dependency inversion functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_dependency_inversions,
+  get_package_levels
+} from '../../../lib/analysis/inversion.mjs';
+
+const packages = group_go_packages(
+  ['store', 'service', 'api'].map((name) =>
+    parse_go_file(
+      readFileSync(`./tests/fixtures/inversion_${name}.go`, 'utf-8'),
+      `${name}/${name}.go`
+    )
+  )
+);
+
+const inversions = find_dependency_inversions(packages);
+
+// ============ get_package_levels tests ============
+
+await test('get_package_levels layers packages by their imports', async (t) => {
+  const levels = get_package_levels(packages);
+  const level_of = (name) => levels.get(packages.find((p) => p.name === name));
+  t.assert.eq(level_of('store'), 0, 'Should put packages without project imports at level 0');
+  t.assert.eq(level_of('service'), 1, 'Should put importers one level up');
+  t.assert.eq(level_of('api'), 2, 'Should follow the highest import');
+});
+
+// ============ find_dependency_inversions tests ============
+
+await test('find_dependency_inversions suggests interfaces for concrete dependencies', async (t) => {
+  const symbols = inversions.map((i) => `${i.kind}:${i.symbol}`);
+  t.assert.eq(
+    symbols.join(' '),
+    'parameter:Shutdown field:Service.users parameter:Find',
+    'Should report the parameters and fields only used through methods of an interface'
+  );
+  const find = inversions.find((i) => i.symbol === 'Find');
+  t.assert.eq(find.concrete, 'store.Store', 'Should give the concrete type');
+  t.assert.eq(find.methods_used.join(','), 'Get', 'Should give the methods used');
+  t.assert.eq(find.suggestion, 'service.Getter', 'Should suggest the interface');
+  t.assert.eq(find.level, 1, 'Should give the level of the package');
+  t.assert.eq(find.depends_on.level, 0, 'Should give the level of the dependency');
+  const shutdown = inversions.find((i) => i.symbol === 'Shutdown');
+  t.assert.eq(shutdown.suggestion, 'api.Closer', 'Should prefer the interfaces of the depending package');
+});
+
+await test('find_dependency_inversions leaves dependencies needing the concrete type', async (t) => {
+  const symbols = new Set(inversions.map((i) => i.symbol));
+  t.assert.ok(!symbols.has('Save'), 'Should leave methods no interface declares together');
+  t.assert.ok(!symbols.has('Where'), 'Should leave parameters whose fields are read');
+  t.assert.ok(!symbols.has('Service.raw'), 'Should leave fields whose fields are read');
+  t.assert.ok(!symbols.has('Service.cache'), 'Should leave types without a satisfied interface');
+});
@@ -0,0 +1,80 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go lifecycle state machine functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_source_state_machines,
+  format_state_machine_dot,
+  parse_state_condition
+} from '../../../lib/analysis/lifecycle.mjs';
+
+const sources = [
+  {
+    filename: 'server/server.go',
+    source: readFileSync('./tests/fixtures/lifecycle.go', 'utf-8')
+  }
+];
+
+const { machines, summary } = find_source_state_machines(sources);
+const find_machine = (type) => machines.find((m) => m.type === type);
+const server = find_machine('Server');
+const find_transition = (method) =>
+  server.transitions.find((t) => t.method === method);
+
+// ============ parse_state_condition tests ============
+
+await test('parse_state_condition parses state comparisons', async (t) => {
+  const condition = parse_state_condition('s.state == A || s.state == B', 's\\.state');
+  t.assert.eq(condition.operator, '==', 'Should find the operator');
+  t.assert.eq(condition.states.join(','), 'A,B', 'Should find every state');
+  t.assert.eq(parse_state_condition('Idle != s.state', 's\\.state').states[0], 'Idle', 'Should accept reversed comparisons');
+  t.assert.eq(parse_state_condition('s.name == x', 's\\.state'), null, 'Should ignore other fields');
+});
+
+// ============ find_source_state_machines tests ============
+
+await test('find_source_state_machines finds lifecycle types', async (t) => {
+  t.assert.eq(summary.machines, 2, 'Should find the two lifecycle types');
+  t.assert.ok(!find_machine('Counter'), 'Should ignore types without a state field');
+  t.assert.eq(server.field, 'state', 'Should find the state field');
+  t.assert.eq(server.state_type, 'State', 'Should find the state type');
+  t.assert.eq(server.states.map((s) => s.name).join(','), 'Idle,Running,Paused,Stopped,Failed', 'Should list the states');
+  t.assert.eq(server.initial, 'Idle', 'Should find the initial state');
+});
+
+await test('find_source_state_machines reads transition guards', async (t) => {
+  t.assert.eq(find_transition('Start').from.join(','), 'Idle', 'Should read early returns on !=');
+  t.assert.eq(find_transition('Pause').from.join(','), 'Running', 'Should read enclosing conditions');
+  t.assert.eq(find_transition('Resume').from.join(','), 'Paused', 'Should read switch cases');
+  t.assert.eq(find_transition('Stop').from.join(','), 'Idle,Running,Paused,Failed', 'Should read early returns on ==');
+  t.assert.eq(find_transition('Stop').line, 61, 'Should report the line of the transition');
+});
+
+await test('find_source_state_machines checks completeness', async (t) => {
+  t.assert.eq(server.complete, false, 'Should flag unreachable states');
+  t.assert.eq(server.unreachable.join(','), 'Failed', 'Should list unreachable states');
+  const stopped = server.states.find((s) => s.name === 'Stopped');
+  t.assert.eq(stopped.terminal, true, 'Should find terminal states');
+  t.assert.eq(server.lifecycle_methods.join(','), 'Start,Pause,Resume,Stop', 'Should list lifecycle methods');
+});
+
+await test('find_source_state_machines accepts untyped state fields', async (t) => {
+  const job = find_machine('Job');
+  t.assert.eq(job.state_type, null, 'Should not need an enum');
+  t.assert.eq(job.initial, 'pending', 'Should find the initial state of composite literals');
+  t.assert.eq(job.transitions[0].from[0], '*', 'Should allow any state without guards');
+  t.assert.eq(job.complete, true, 'Should find complete machines');
+});
+
+// ============ format_state_machine_dot tests ============
+
+await test('format_state_machine_dot formats a digraph', async (t) => {
+  const dot = format_state_machine_dot(server);
+  t.assert.ok(dot.startsWith('digraph Server {'), 'Should name the graph');
+  t.assert.ok(dot.includes('"Idle" -> "Running" [label="Start"];'), 'Should label transitions');
+  t.assert.ok(dot.includes('"Failed" [shape=circle, style=dashed];'), 'Should dash unreachable states');
+});
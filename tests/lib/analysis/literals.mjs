@@ -0,0 +1,163 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go literal extraction functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_go_literals,
+  unquote_go_literal,
+  get_number_value,
+  find_format_calls,
+  parse_format_verbs,
+  find_magic_numbers
+} from '../../../lib/analysis/literals.mjs';
+
+const source = readFileSync('./tests/fixtures/literals.go', 'utf-8');
+const literals = find_go_literals(source, 'fixtures/literals.go');
+
+const find_literal = (text) => literals.find((l) => l.text === text);
+
+// ============ Value tests ============
+
+await test('unquote_go_literal decodes interpreted strings', async (t) => {
+  t.assert.eq(unquote_go_literal('"a\\tb\\n"'), 'a\tb\n', 'Should decode escapes');
+  t.assert.eq(unquote_go_literal('"\\x41\\u00e9"'), 'Aé', 'Should decode hex and unicode escapes');
+  t.assert.eq(unquote_go_literal("'\\''"), "'", 'Should decode rune escapes');
+});
+
+await test('unquote_go_literal keeps raw strings as-is', async (t) => {
+  t.assert.eq(unquote_go_literal('`a\\tb`'), 'a\\tb', 'Should not decode raw strings');
+});
+
+await test('get_number_value parses Go number literals', async (t) => {
+  t.assert.eq(get_number_value('0xFF'), 255, 'Should parse hex');
+  t.assert.eq(get_number_value('0b101'), 5, 'Should parse binary');
+  t.assert.eq(get_number_value('1_000'), 1000, 'Should allow underscores');
+  t.assert.eq(get_number_value('3.25e2'), 325, 'Should parse floats');
+  t.assert.eq(get_number_value('2i'), null, 'Should not evaluate imaginary literals');
+});
+
+// ============ Extraction tests ============
+
+await test('find_go_literals distinguishes const and inline literals', async (t) => {
+  t.assert.eq(find_literal('"https://api.example.com/v1"').context, 'const', 'Named constant');
+  t.assert.eq(find_literal('"https://cdn.example.com/"').context, 'inline', 'Hardcoded URL');
+  t.assert.eq(find_literal('1.5').context, 'const', 'Local constant');
+  t.assert.ok(find_literal('1.5').is_const, 'Should set is_const');
+});
+
+await test('find_go_literals classifies imports, tags and variables', async (t) => {
+  t.assert.eq(find_literal('"net/http"').context, 'import', 'Import path');
+  t.assert.eq(find_literal('`json:"endpoint"`').context, 'struct_tag', 'Struct tag');
+  t.assert.eq(find_literal('"literals/1.0"').context, 'var', 'Package variable');
+});
+
+await test('find_go_literals reports spans and enclosing symbols', async (t) => {
+  const url = find_literal('"https://cdn.example.com/"');
+  t.assert.eq(url.line, 30, 'Should report the line');
+  t.assert.eq(url.column, 9, 'Should report the column');
+  t.assert.eq(url.end_column, 35, 'Should report the end column');
+  t.assert.eq(url.enclosing.name, 'Fetch', 'Should report the enclosing function');
+  t.assert.eq(find_literal("','").enclosing.name, 'Config.describe', 'Should include receivers');
+});
+
+await test('find_go_literals folds unary minus into numbers', async (t) => {
+  const negative = find_literal('-1');
+  t.assert.ok(negative, 'Should find -1');
+  t.assert.eq(negative.value, -1, 'Should negate the value');
+  const subtraction = literals.filter((l) => l.kind === 'number' && l.line === 45);
+  t.assert.eq(subtraction[0].text, '1', 'Should not treat subtraction as negation');
+});
+
+await test('find_go_literals filters by kind', async (t) => {
+  const numbers = find_go_literals(source, 'literals.go', { kind: 'number' });
+  t.assert.ok(numbers.length > 0, 'Should find numbers');
+  t.assert.ok(numbers.every((l) => l.kind === 'number'), 'Should only return numbers');
+  const runes = find_go_literals(source, 'literals.go', { kind: 'rune' });
+  t.assert.eq(runes.length, 1, 'Should find one rune');
+});
+
+await test('find_go_literals ignores literals in comments', async (t) => {
+  const result = find_go_literals('package x\n\n// 42 "no"\nvar y = 7\n', 'x.go');
+  t.assert.eq(result.map((l) => l.text).join(' '), '7', 'Should skip comments');
+});
+
+// ============ Format call tests ============
+
+await test('parse_format_verbs handles escapes, widths and indexes', async (t) => {
+  const simple = parse_format_verbs('%s: %d items (%.2f%%)');
+  t.assert.eq(simple.verbs.map((v) => v.text).join(' '), '%s %d %.2f', 'Should skip %% escapes');
+  t.assert.eq(simple.needed, 3, 'Should need one argument per verb');
+  t.assert.eq(parse_format_verbs('%-10s|%*d').needed, 3, 'Should count * widths as arguments');
+  t.assert.eq(parse_format_verbs('%6.*f').needed, 2, 'Should count * precisions as arguments');
+  const indexed = parse_format_verbs('%[2]d %[1]s');
+  t.assert.eq(indexed.needed, 2, 'Should follow argument indexes');
+  t.assert.ok(indexed.indexed, 'Should mark indexed formats');
+  t.assert.ok(parse_format_verbs('50%').no_verb, 'Should detect a trailing %');
+});
+
+await test('find_format_calls checks verbs against arguments', async (t) => {
+  const calls = find_format_calls(
+    readFileSync('./tests/fixtures/format_calls.go', 'utf-8'),
+    'fixtures/format_calls.go'
+  );
+  t.assert.eq(calls.length, 9, 'Should find the fmt and log format calls only');
+  const issues = calls
+    .filter((call) => call.issues.length > 0)
+    .map((call) => `${call.function}:${call.line}:${call.issues.map((i) => i.kind).join('+')}`);
+  t.assert.eq(
+    issues.join(' '),
+    'fmt.Sprintf:15:missing_args log.Printf:23:extra_args fmt.Errorf:25:no_verb fmt.Printf:32:extra_args',
+    'Should report the mismatched calls'
+  );
+  const sprintf = calls.find((call) => call.function === 'fmt.Sprintf');
+  t.assert.eq(sprintf.issues[0].message, 'fmt.Sprintf format "%s has %d items" needs 2 args but the call has 1 arg', 'Should explain the mismatch');
+  t.assert.eq(sprintf.enclosing.name, 'Summary', 'Should give the enclosing function');
+  const unchecked = calls.filter((call) => !call.checked);
+  t.assert.eq(unchecked.length, 2, 'Should not check non-literal formats and spread arguments');
+  t.assert.eq(
+    calls[calls.length - 1].issues[0].message,
+    'fmt.Printf call has arguments but no formatting directives',
+    'Should report arguments without directives'
+  );
+});
+
+// ============ Magic number tests ============
+
+await test('find_magic_numbers groups repeated inline numbers', async (t) => {
+  const sources = [{
+    filename: 'retry/retry.go',
+    source: readFileSync('./tests/fixtures/magic_numbers.go', 'utf-8')
+  }];
+  const numbers = find_magic_numbers(sources);
+  const by_value = Object.fromEntries(numbers.map((n) => [n.value, n]));
+  t.assert.eq(numbers.map((n) => n.value).join(','), '5,250,4096', 'Should find the numbers used 3 times or more, most used first');
+  t.assert.eq(by_value[4096].texts.join(','), '0x1000,4096', 'Should group literals by value');
+  t.assert.eq(by_value[5].occurrences, 3, 'Should not count the constant declaration');
+  t.assert.eq(by_value[5].suggestion, 'use the constant MaxAttempts', 'Should suggest an existing constant');
+  t.assert.eq(by_value[4096].suggestion, 'use the constant bufferSize', 'Should suggest unexported constants');
+  t.assert.eq(by_value[250].suggestion, 'extract a named constant', 'Should suggest extracting a constant');
+  t.assert.eq(by_value[250].occurrences, 3, 'Should not count package variable initializers');
+  t.assert.eq(by_value[5].declarations, 2, 'Should count the declarations using the number');
+  t.assert.eq(by_value[5].sites[0].enclosing.name, 'Retry', 'Should give the enclosing function of each site');
+});
+
+await test('find_magic_numbers supports options', async (t) => {
+  const sources = [{
+    filename: 'retry/retry.go',
+    source: readFileSync('./tests/fixtures/magic_numbers.go', 'utf-8')
+  }];
+  const values = (options) => find_magic_numbers(sources, options).map((n) => n.value).join(',');
+  t.assert.eq(values({ min_occurrences: 2 }), '5,250,4096,100', 'Should lower the minimum occurrences');
+  t.assert.eq(values({ ignore: [5], min_occurrences: 2 }), '250,4096,1,100', 'Should replace the common numbers');
+  let error = null;
+  try {
+    find_magic_numbers(sources, { min_occurrences: 1 });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject a minimum below 2');
+});
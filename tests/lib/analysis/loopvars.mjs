@@ -0,0 +1,85 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go loop variable capture functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_loop_captures,
+  find_variable_loops,
+  get_constraint_go_version,
+  has_per_iteration_loopvars
+} from '../../../lib/analysis/loopvars.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/loopvars.go', 'utf-8'),
+    'worker/worker.go'
+  ),
+  parse_go_file(
+    readFileSync('./tests/fixtures/loopvars_go122.go', 'utf-8'),
+    'worker/modern.go'
+  )
+]);
+const captures = find_loop_captures(packages);
+const captures_of = (name) => captures.filter((c) => c.function === name);
+
+// ============ Go version tests ============
+
+await test('has_per_iteration_loopvars checks for Go 1.22', async (t) => {
+  t.assert.eq(has_per_iteration_loopvars('1.21.5'), false, '1.21 shares loop variables');
+  t.assert.eq(has_per_iteration_loopvars('1.22'), true, '1.22 declares them per iteration');
+  t.assert.eq(has_per_iteration_loopvars('go1.23'), true, 'Accepts a go prefix');
+  t.assert.eq(has_per_iteration_loopvars(undefined), false, 'No version is the old semantics');
+  t.assert.eq(get_constraint_go_version({ go_build: 'linux && go1.22' }), '1.22', 'Reads go1.N constraints');
+  t.assert.eq(get_constraint_go_version({ go_build: 'go1.22 || windows' }), null, 'Ignores disjunctions');
+});
+
+// ============ find_variable_loops tests ============
+
+await test('find_variable_loops finds loops declaring variables', async (t) => {
+  const loops = find_variable_loops(
+    '{\n\tfor i := 0; i < n; i++ {}\n\tfor k, v := range m {}\n\tfor x = range m {}\n\tfor {}\n}'
+  );
+  t.assert.eq(loops.length, 2, 'Should skip loops declaring no variables');
+  t.assert.eq(loops[0].kind, 'for', 'Should find three-clause loops');
+  t.assert.eq(loops[1].variables.join(', '), 'k, v', 'Should have range variables');
+});
+
+// ============ find_loop_captures tests ============
+
+await test('find_loop_captures reports the capture site', async (t) => {
+  const [capture] = captures_of('RunAll');
+  t.assert.eq(capture.variable, 'job', 'RunAll captures job');
+  t.assert.eq(capture.line, 24, 'Should be the line using the variable');
+  t.assert.eq(capture.loop_line, 20, 'Should have the loop line');
+  t.assert.eq(capture.launch_line, 22, 'Should have the go statement line');
+  t.assert.ok(capture.message.includes('before Go 1.22'), 'Message should note the Go version');
+
+  t.assert.eq(captures_of('Count')[0].loop_kind, 'for', 'Count captures a three-clause loop variable');
+  t.assert.eq(captures_of('Fetch').map((c) => c.variable).join(', '), 'name, url', 'Fetch captures both range variables');
+  t.assert.eq(captures_of('Fetch')[0].launch, 'group', 'Fetch launches with errgroup');
+  t.assert.eq(captures_of('Outer').map((c) => c.variable).join(', '), 'g', 'Outer captures the outer loop variable');
+});
+
+await test('find_loop_captures skips passed, copied and direct variables', async (t) => {
+  t.assert.eq(captures_of('PassArgument').length, 0, 'Parameters shadow loop variables');
+  t.assert.eq(captures_of('CopyFirst').length, 0, 'Copies shadow loop variables');
+  t.assert.eq(captures_of('Direct').length, 0, 'Call arguments are evaluated immediately');
+});
+
+await test('find_loop_captures honors the Go version', async (t) => {
+  t.assert.eq(captures_of('Modern').length, 0, 'go1.22 build constraints fix captures');
+  t.assert.eq(find_loop_captures(packages, { go_version: '1.22' }).length, 0, 'Go 1.22 targets fix captures');
+
+  const fixed = find_loop_captures(packages, { go_version: '1.22', include_fixed: true });
+  t.assert.eq(fixed.length, captures.length + 1, 'Fixed captures can be included');
+  t.assert.eq(fixed[0].fixed_by, 'go_version', 'Should say what fixes the capture');
+  t.assert.eq(fixed[0].severity, 'info', 'Fixed captures are informational');
+});
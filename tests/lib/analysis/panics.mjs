@@ -0,0 +1,75 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go panic reachability functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file, group_go_packages } from '../../../lib/analysis/golang.mjs';
+import {
+  find_reachable_panics,
+  find_panic_sites,
+  has_deferred_recover
+} from '../../../lib/analysis/panics.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(readFileSync('./tests/fixtures/panics.go', 'utf-8'), 'parser/parser.go')
+]);
+
+const by_function = (result) => new Map(result.functions.map((f) => [f.function, f]));
+
+// ============ find_panic_sites tests ============
+
+await test('find_panic_sites finds panic and log.Panic calls', async (t) => {
+  const body = '{\n\tpanic(fmt.Sprintf("x: %d", n))\n\tlog.Panicf("failed")\n\tmypanic(1)\n}';
+  const sites = find_panic_sites(body, 10);
+  t.assert.eq(sites.length, 2, 'Should find two panic calls');
+  t.assert.eq(sites[0].line, 11, 'Should record the line');
+  t.assert.eq(sites[0].text, 'panic(fmt.Sprintf("x: %d", n))', 'Should include the whole call');
+  t.assert.eq(sites[1].text, 'log.Panicf("failed")', 'Should find log.Panicf');
+});
+
+await test('has_deferred_recover detects recovering functions', async (t) => {
+  const fns = new Map(packages[0].functions.map((f) => [f.name, f]));
+  t.assert.ok(has_deferred_recover(fns.get('SafeParse')), 'Should detect a deferred literal');
+  t.assert.ok(!has_deferred_recover(fns.get('Guarded')), 'Should need the helper set');
+  t.assert.ok(has_deferred_recover(fns.get('Guarded'), new Set(['catch'])), 'Should detect a recovering helper');
+});
+
+// ============ find_reachable_panics tests ============
+
+await test('find_reachable_panics reports shortest paths', async (t) => {
+  const result = find_reachable_panics(packages);
+  const functions = by_function(result);
+
+  t.assert.ok(functions.get('Fail').direct, 'Fail should panic directly');
+  const parse = functions.get('Parser.Parse');
+  t.assert.eq(parse.depth, 1, 'Parse should reach a panic in one call');
+  t.assert.eq(parse.path.map((p) => p.function).join(' '), 'Parser.Parse Parser.expr', 'Should report the path');
+  t.assert.eq(parse.panic.line, 33, 'Should report the panic site');
+  t.assert.eq(functions.get('MustParse').depth, 2, 'MustParse should reach the panic through Parse');
+  t.assert.ok(!functions.has('Length'), 'Length should be safe');
+});
+
+await test('find_reachable_panics follows interface calls', async (t) => {
+  const describe = by_function(find_reachable_panics(packages)).get('Describe');
+  t.assert.ok(describe, 'Describe should reach a panic');
+  t.assert.eq(describe.path[1].function, 'Leaf.Kind', 'Should dispatch to the implementation');
+  t.assert.eq(describe.path[1].via, 'Node', 'Should record the interface');
+});
+
+await test('find_reachable_panics stops at recovering functions', async (t) => {
+  const result = find_reachable_panics(packages);
+  const functions = by_function(result);
+  t.assert.ok(!functions.has('SafeParse'), 'SafeParse recovers');
+  t.assert.ok(!functions.has('Guarded'), 'Guarded recovers through a helper');
+  t.assert.eq(result.summary.recovering_functions, 2, 'Should count recovering functions');
+  t.assert.eq(result.unresolved.map((u) => u.function).join(' '), 'Apply', 'Should report function value calls');
+});
+
+await test('find_reachable_panics bounds the search depth', async (t) => {
+  const functions = by_function(find_reachable_panics(packages, { max_depth: 1 }));
+  t.assert.ok(functions.has('Parser.Parse'), 'Parse is within one call');
+  t.assert.ok(!functions.has('MustParse'), 'MustParse is two calls away');
+});
@@ -0,0 +1,84 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go parameter naming functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_source_param_names,
+  classify_param_name,
+  DEFAULT_ACCEPTABLE_NAMES
+} from '../../../lib/analysis/paramnames.mjs';
+
+const sources = [
+  {
+    filename: 'calc/calc.go',
+    source: readFileSync('./tests/fixtures/param_names.go', 'utf-8')
+  }
+];
+
+const { functions, summary } = find_source_param_names(sources);
+const find_function = (name) => functions.find((f) => f.function === name);
+const acceptable = new Set(DEFAULT_ACCEPTABLE_NAMES);
+
+// ============ classify_param_name tests ============
+
+await test('classify_param_name classifies weak names', async (t) => {
+  t.assert.eq(classify_param_name({ name: null, type: 'int' }, acceptable), 'unnamed', 'Should flag unnamed parameters');
+  t.assert.eq(classify_param_name({ name: '_', type: 'int' }, acceptable), 'blank', 'Should flag blank parameters');
+  t.assert.eq(classify_param_name({ name: 'arg0', type: 'int' }, acceptable), 'numbered', 'Should flag numbered placeholders');
+  t.assert.eq(classify_param_name({ name: 'a', type: 'int' }, acceptable), 'short', 'Should flag single letters');
+  t.assert.eq(classify_param_name({ name: 'i', type: 'int' }, acceptable), null, 'Should accept idiomatic names');
+  t.assert.eq(classify_param_name({ name: 'w', type: 'io.Writer' }, acceptable), null, 'Should accept idiomatic typed letters');
+  t.assert.eq(classify_param_name({ name: 'w', type: 'int' }, acceptable), 'short', 'Should check the type of idiomatic letters');
+  t.assert.eq(classify_param_name({ name: 'values', type: 'int' }, acceptable), null, 'Should accept informative names');
+});
+
+// ============ find_source_param_names tests ============
+
+await test('find_source_param_names flags exported functions', async (t) => {
+  const add = find_function('Add');
+  t.assert.eq(add.weak_params.map((p) => p.name).join(','), 'a,b', 'Should list the weak parameters');
+  t.assert.eq(add.weak_params[0].reason, 'short', 'Should give the reason');
+  t.assert.eq(add.signature, 'Add(a int, b int) int', 'Should include the signature');
+  t.assert.eq(find_function('Scale').weak_params[1].position, 1, 'Should give the position of unnamed parameters');
+  t.assert.eq(find_function('Scale').message, 'Scale has weak parameter names: #1 (unnamed), #2 (unnamed)', 'Should describe the weak parameters');
+});
+
+await test('find_source_param_names checks methods and interfaces', async (t) => {
+  const apply = find_function('Calculator.Apply');
+  t.assert.eq(apply.weak_params.length, 1, 'Should accept ctx and never flag receivers');
+  t.assert.eq(apply.weak_params[0].reason, 'blank', 'Should flag blank parameters');
+  t.assert.eq(find_function('Calculator.Merge').weak_params[0].reason, 'numbered', 'Should flag numbered names');
+  const method = find_function('Operator.Do');
+  t.assert.eq(method.kind, 'interface_method', 'Should check interface methods');
+  t.assert.eq(method.line, 53, 'Should report the line of the interface method');
+});
+
+await test('find_source_param_names skips idiomatic and unexported functions', async (t) => {
+  t.assert.ok(!find_function('Serve'), 'Should accept w and r for HTTP handlers');
+  t.assert.ok(!find_function('Hypot'), 'Should accept x and y for floats');
+  t.assert.ok(!find_function('Copy'), 'Should accept n');
+  t.assert.ok(!find_function('add'), 'Should skip unexported functions');
+  t.assert.ok(!find_function('hidden.Run'), 'Should skip methods of unexported types');
+  t.assert.eq(summary.functions, 10, 'Should count the functions checked');
+  t.assert.eq(summary.weak_params, 9, 'Should count the weak parameters');
+  t.assert.eq(summary.by_reason.short, 4, 'Should count by reason');
+});
+
+await test('find_source_param_names accepts configured names and reasons', async (t) => {
+  const configured = find_source_param_names(sources, { acceptable: ['a', 'b', 'x', 'y'] });
+  t.assert.ok(!configured.functions.some((f) => f.function === 'Add'), 'Should accept configured names');
+  t.assert.ok(configured.functions.some((f) => f.function === 'Copy'), 'Should replace the default list');
+  const unnamed = find_source_param_names(sources, { reasons: ['unnamed'] });
+  t.assert.eq(unnamed.functions.map((f) => f.function).join(','), 'Scale', 'Should filter by reason');
+  let error = null;
+  try {
+    find_source_param_names(sources, { reasons: ['long'] });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject unknown reasons');
+});
@@ -0,0 +1,86 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go predicate naming functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_source_predicate_names,
+  is_predicate_name,
+  suggest_predicate_name,
+  DEFAULT_PREDICATE_PREFIXES
+} from '../../../lib/analysis/predicates.mjs';
+
+const sources = [
+  {
+    filename: 'config/config.go',
+    source: readFileSync('./tests/fixtures/predicates.go', 'utf-8')
+  }
+];
+
+const { functions, summary } = find_source_predicate_names(sources);
+const find_function = (name) => functions.find((f) => f.function === name);
+
+// ============ is_predicate_name tests ============
+
+await test('is_predicate_name recognizes predicate prefixes', async (t) => {
+  t.assert.ok(is_predicate_name('IsEmpty', DEFAULT_PREDICATE_PREFIXES), 'Should accept Is');
+  t.assert.ok(is_predicate_name('HasKey', DEFAULT_PREDICATE_PREFIXES), 'Should accept Has');
+  t.assert.ok(is_predicate_name('Can', DEFAULT_PREDICATE_PREFIXES), 'Should accept a bare prefix');
+  t.assert.ok(!is_predicate_name('Issue', DEFAULT_PREDICATE_PREFIXES), 'Should require a word after the prefix');
+  t.assert.ok(!is_predicate_name('Empty', DEFAULT_PREDICATE_PREFIXES), 'Should reject other names');
+});
+
+// ============ suggest_predicate_name tests ============
+
+await test('suggest_predicate_name suggests renames', async (t) => {
+  t.assert.eq(suggest_predicate_name('Empty'), 'IsEmpty', 'Should prefix with Is');
+  t.assert.eq(suggest_predicate_name('AllowWrite'), 'CanWrite', 'Should turn Allow into Can');
+  t.assert.eq(suggest_predicate_name('NeedsFlush'), 'ShouldFlush', 'Should turn Needs into Should');
+  t.assert.eq(suggest_predicate_name('KeyExists'), 'HasKey', 'Should turn Exists into Has');
+  t.assert.eq(suggest_predicate_name('GetEnabled'), 'IsEnabled', 'Should turn Get into Is');
+});
+
+// ============ find_source_predicate_names tests ============
+
+await test('find_source_predicate_names flags bool functions', async (t) => {
+  const empty = find_function('Config.Empty');
+  t.assert.eq(empty.kind, 'method', 'Should check methods');
+  t.assert.eq(empty.line, 36, 'Should report the line');
+  t.assert.eq(empty.suggestion, 'IsEmpty', 'Should suggest a rename');
+  t.assert.eq(empty.message, 'Config.Empty returns a bool but does not read as a predicate; consider renaming it IsEmpty', 'Should describe the function');
+  t.assert.eq(find_function('Config.Valid').signature, 'Valid() (ok bool)', 'Should check named bool results');
+  t.assert.eq(find_function('Checker.Ready').kind, 'interface_method', 'Should check interface methods');
+  t.assert.eq(find_function('CheckSigned').suggestion, 'IsSigned', 'Should rewrite the name');
+  t.assert.ok(find_function('Issue'), 'Should flag names only starting like a prefix');
+});
+
+await test('find_source_predicate_names skips predicates and other signatures', async (t) => {
+  for (const name of ['Config.IsEmpty', 'Config.HasKey', 'CanWrite', 'ShouldRetry', 'Checker.IsStale']) {
+    t.assert.ok(!find_function(name), `Should accept ${name}`);
+  }
+  t.assert.ok(!find_function('Config.Lookup'), 'Should skip (bool, error)');
+  t.assert.ok(!find_function('Config.Get'), 'Should skip comma-ok results');
+  t.assert.ok(!find_function('Less'), 'Should accept idiomatic names');
+  t.assert.ok(!find_function('empty'), 'Should skip unexported functions');
+});
+
+await test('find_source_predicate_names takes prefixes and names', async (t) => {
+  const report = find_source_predicate_names(sources, {
+    prefixes: ['Is', 'Has', 'Can', 'Should', 'Allow'],
+    acceptable: ['Valid']
+  });
+  const names = report.functions.map((f) => f.function);
+  t.assert.ok(!names.includes('AllowWrite'), 'Should accept custom prefixes');
+  t.assert.ok(!names.includes('Config.Valid'), 'Should accept custom names');
+  t.assert.ok(names.includes('Less'), 'Should replace the default names');
+});
+
+await test('find_source_predicate_names summarizes', async (t) => {
+  t.assert.eq(summary.functions, 13, 'Should count the bool functions checked');
+  t.assert.eq(summary.flagged, 7, 'Should count the flagged functions');
+  t.assert.eq(summary.well_named, 6, 'Should count the well named functions');
+  t.assert.eq(summary.by_kind.method, 3, 'Should count flagged functions by kind');
+});
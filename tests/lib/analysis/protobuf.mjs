@@ -0,0 +1,129 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go to protobuf schema functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  generate_source_proto,
+  find_go_enums,
+  map_proto_type,
+  to_snake_case
+} from '../../../lib/analysis/protobuf.mjs';
+
+const source = readFileSync('./tests/fixtures/protobuf.go', 'utf-8');
+const [pkg] = group_go_packages([parse_go_file(source, 'shop/order.go')]);
+
+// ============ to_snake_case tests ============
+
+await test('to_snake_case keeps acronyms together', async (t) => {
+  t.assert.eq(to_snake_case('UserID'), 'user_id', 'UserID');
+  t.assert.eq(to_snake_case('HTTPServer'), 'http_server', 'HTTPServer');
+  t.assert.eq(to_snake_case('Name'), 'name', 'Name');
+});
+
+// ============ find_go_enums tests ============
+
+await test('find_go_enums finds integer types with typed constants', async (t) => {
+  const enums = find_go_enums(pkg);
+
+  t.assert.eq(
+    [...enums.keys()].sort(),
+    ['Priority', 'Status'],
+    'Money has no constants'
+  );
+  t.assert.eq(
+    enums.get('Status').values.map((v) => [v.name, v.value]),
+    [
+      ['StatusPending', 1],
+      ['StatusPaid', 2],
+      ['StatusShipped', 3]
+    ],
+    'implicit constants repeat the type of the group'
+  );
+});
+
+// ============ map_proto_type tests ============
+
+await test('map_proto_type maps Go types to proto types', async (t) => {
+  const context = {
+    types: new Map(pkg.types.map((type) => [type.name, type])),
+    enums: find_go_enums(pkg)
+  };
+
+  t.assert.eq(map_proto_type('int', context).type, 'int64', 'int');
+  t.assert.eq(map_proto_type('[]byte', context).type, 'bytes', '[]byte');
+  t.assert.eq(map_proto_type('Money', context).type, 'int64', 'underlying');
+  t.assert.ok(map_proto_type('*string', context).optional, 'pointers');
+  t.assert.ok(map_proto_type('[]Item', context).repeated, 'slices');
+  t.assert.eq(
+    map_proto_type('map[string][]int', context).unsupported,
+    'nested collection map[string][]int',
+    'maps of slices are unsupported'
+  );
+  t.assert.ok(map_proto_type('chan bool', context).unsupported, 'channels');
+});
+
+// ============ generate_source_proto tests ============
+
+await test('generate_source_proto generates serialized structs', async (t) => {
+  const { schemas, summary } = generate_source_proto(
+    [{ filename: 'shop/order.go', source }],
+    { module: 'example.com/app' }
+  );
+  const [schema] = schemas;
+
+  t.assert.eq(schema.filename, 'shop/shop.proto', 'one file per package');
+  t.assert.eq(schema.messages, ['Item', 'Order'], 'Clock has no tags');
+  t.assert.eq(schema.enums, ['Priority', 'Status'], 'referenced enums');
+  t.assert.eq(summary.warnings, 1, 'one skipped field');
+  t.assert.ok(
+    schema.text.includes('option go_package = "example.com/app/shop";'),
+    'go_package of the module'
+  );
+  t.assert.ok(
+    schema.text.includes('import "google/protobuf/timestamp.proto";'),
+    'imports well known types'
+  );
+  t.assert.ok(schema.text.includes('  int64 id = 1;'), 'tag field number');
+  t.assert.ok(schema.text.includes('  string customer = 3;'), 'tag number');
+  t.assert.ok(
+    schema.text.includes('  repeated Item items = 2;'),
+    'untagged fields take unused numbers'
+  );
+  t.assert.ok(
+    schema.text.includes('  optional Priority priority = 5;'),
+    'pointer to an enum'
+  );
+  t.assert.ok(
+    schema.text.includes('  google.protobuf.Timestamp created_at = 8;'),
+    'json tag name'
+  );
+  t.assert.ok(
+    schema.text.includes('  message Shipping {'),
+    'anonymous struct nested message'
+  );
+  t.assert.ok(!schema.text.includes('secret'), 'json "-" fields are skipped');
+  t.assert.ok(
+    schema.text.includes('  STATUS_UNSPECIFIED = 0;'),
+    'zero value added to enums without one'
+  );
+  t.assert.ok(schema.text.includes('  PRIORITY_LOW = 0;'), 'existing zero');
+});
+
+await test('generate_source_proto generates the requested types', async (t) => {
+  const { schemas } = generate_source_proto(
+    [{ filename: 'shop/order.go', source }],
+    { types: ['Item'] }
+  );
+
+  t.assert.eq(schemas[0].messages, ['Item'], 'only Item');
+  t.assert.eq(schemas[0].enums, [], 'no enums');
+  t.assert.ok(!schemas[0].text.includes('go_package'), 'no module');
+});
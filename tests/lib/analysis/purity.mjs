@@ -0,0 +1,83 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go function purity functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_concurrency_hints,
+  annotate_concurrency_hints,
+  find_side_effects
+} from '../../../lib/analysis/purity.mjs';
+
+const load_packages = () =>
+  group_go_packages([
+    parse_go_file(
+      readFileSync('./tests/fixtures/purity.go', 'utf-8'),
+      'counter/counter.go'
+    )
+  ]);
+
+const [pkg] = load_packages();
+const hints = find_concurrency_hints([pkg]);
+const hint_of = (name) => hints.find((f) => f.name === name).hint;
+const function_of = (name) => pkg.functions.find((f) => f.name === name);
+
+// ============ find_side_effects tests ============
+
+await test('find_side_effects finds package variable and argument writes', async (t) => {
+  const add = find_side_effects(function_of('Add'), pkg);
+  t.assert.eq(add.writes[0].name, 'total', 'Add writes total');
+  t.assert.eq(add.writes[0].line, 16, 'Should report the line of the write');
+
+  const fill = find_side_effects(function_of('Fill'), pkg);
+  t.assert.eq(fill.argument_writes[0].target, 'values[i]', 'Fill writes through its slice');
+
+  const shadow = find_side_effects(function_of('Shadow'), pkg);
+  t.assert.eq(shadow.reads.length + shadow.writes.length, 0, 'Locals shadow package variables');
+
+  const record = find_side_effects(function_of('Record'), pkg);
+  t.assert.ok(record.locked, 'Record takes a lock');
+});
+
+// ============ find_concurrency_hints tests ============
+
+await test('find_concurrency_hints separates safe and unsafe functions', async (t) => {
+  t.assert.eq(hint_of('Sum'), 'safe', 'Sum only uses local state');
+  t.assert.eq(hint_of('Clamp'), 'safe', 'Constants are not shared state');
+  t.assert.eq(hint_of('Add'), 'unsafe', 'Add mutates a package variable');
+  t.assert.eq(hint_of('Fill'), 'unsafe', 'Fill mutates a shared argument');
+  t.assert.eq(hint_of('Total'), 'unknown', 'Total reads a package variable');
+  t.assert.eq(hint_of('Record'), 'unknown', 'Record writes under a lock');
+});
+
+await test('find_concurrency_hints follows calls and receivers', async (t) => {
+  t.assert.eq(hint_of('AddTwice'), 'unsafe', 'AddTwice calls Add');
+  t.assert.eq(hint_of('Stats.Inc'), 'unsafe', 'Inc writes through its pointer receiver');
+  t.assert.eq(hint_of('Stats.Count'), 'safe', 'Count has a value receiver');
+  t.assert.eq(hint_of('Bump'), 'unsafe', 'Bump calls Inc on a shared pointer');
+  t.assert.eq(hint_of('Copy'), 'safe', 'Copy only changes its own copy');
+
+  const add_twice = hints.find((f) => f.name === 'AddTwice');
+  t.assert.eq(add_twice.reasons[0].kind, 'call', 'Should explain the propagated hint');
+  t.assert.ok(add_twice.doc_conflict, 'The doc claims AddTwice is safe');
+  t.assert.ok(!hints.find((f) => f.name === 'Sum').doc_conflict, 'Sum is documented correctly');
+
+  const unsafe = find_concurrency_hints([pkg], { hint: 'unsafe' });
+  t.assert.ok(unsafe.every((f) => f.hint === 'unsafe'), 'Should filter by hint');
+});
+
+await test('annotate_concurrency_hints is opt-in', async (t) => {
+  const [fresh] = load_packages();
+  const sum = fresh.functions.find((f) => f.name === 'Sum');
+  t.assert.eq(sum.concurrency_hint, undefined, 'Parsing does not compute hints');
+  annotate_concurrency_hints([fresh]);
+  t.assert.eq(sum.concurrency_hint, 'safe', 'Annotating sets the hint');
+  t.assert.ok(Array.isArray(sum.concurrency_reasons), 'Annotating sets the reasons');
+});
@@ -5,6 +5,7 @@
  */
 
 import { test } from 'st';
+import { readFileSync } from 'fs';
 import {
   calculate_readability_score,
   analyze_identifier_lengths,
@@ -14,9 +15,12 @@ import {
   analyze_line_lengths,
   detect_magic_numbers,
   analyze_boolean_complexity,
+  analyze_go_nesting,
+  score_nesting_quality,
   READABILITY_WEIGHTS,
   THRESHOLDS
 } from '../../../lib/analysis/readability.mjs';
+import { parse_go_file } from '../../../lib/analysis/golang.mjs';
 
 // ============ Constants tests ============
 
@@ -335,3 +339,65 @@ await test('calculate_readability_score handles different languages', async (t)
   t.assert.ok(jsResult.score >= 0, 'Should handle JavaScript');
   t.assert.ok(pyResult.score >= 0, 'Should handle Python');
 });
+
+// ============ Go guard clause and nesting tests ============
+
+const nesting_functions = parse_go_file(
+  readFileSync('./tests/fixtures/nesting.go', 'utf-8'),
+  'fixtures/nesting.go'
+).functions;
+
+const nesting_of = (name) => {
+  const fn = nesting_functions.find((f) => f.name === name);
+  return analyze_go_nesting(fn.body, fn.body_line);
+};
+
+await test('analyze_go_nesting detects a leading guard clause', async (t) => {
+  const result = nesting_of('Divide');
+  t.assert.eq(result.guard_clauses.length, 1, 'Should find one guard');
+  t.assert.eq(result.guard_clauses[0].condition, 'b == 0', 'Should record the condition');
+  t.assert.eq(result.guard_clauses[0].line, 10, 'Should record the line');
+  t.assert.ok(result.leading_guard, 'First statement is a guard');
+  t.assert.eq(result.max_depth, 1, 'Should have depth 1');
+});
+
+await test('analyze_go_nesting only counts top level guards', async (t) => {
+  const result = nesting_of('Validate');
+  t.assert.eq(result.guard_clauses.length, 2, 'Should ignore the return inside the loop');
+  t.assert.eq(result.max_depth, 2, 'Should count the loop and its if');
+});
+
+await test('analyze_go_nesting measures deeply nested conditionals', async (t) => {
+  const result = nesting_of('Process');
+  t.assert.eq(result.max_depth, 5, 'Should reach depth 5');
+  t.assert.eq(result.max_depth_line, 39, 'Should report the deepest line');
+  t.assert.ok(!result.uses_guard_clauses, 'Should not use guards');
+  t.assert.eq(result.wrapping_if.line, 35, 'Should find the wrapping if');
+});
+
+await test('analyze_go_nesting detects else after return', async (t) => {
+  const result = nesting_of('Classify');
+  t.assert.eq(result.else_after_return.length, 1, 'Should find one else');
+  t.assert.eq(result.else_after_return[0].line, 53, 'Should point at the else');
+  t.assert.eq(result.guard_clauses.length, 0, 'An if with else is not a guard');
+});
+
+await test('analyze_go_nesting counts function literal arguments', async (t) => {
+  t.assert.eq(nesting_of('TestSum').max_depth, 2, 'Should descend into t.Run callbacks');
+  t.assert.eq(nesting_of('Sum').max_depth, 1, 'Composite literals are not blocks');
+});
+
+await test('score_nesting_quality ranks guarded above nested code', async (t) => {
+  const guarded = score_nesting_quality(nesting_of('Divide'));
+  const nested = score_nesting_quality(nesting_of('Process'));
+  t.assert.eq(guarded.style, 'guarded', 'Divide is guarded');
+  t.assert.eq(nested.style, 'deeply_nested', 'Process is deeply nested');
+  t.assert.ok(guarded.score > nested.score, 'Guarded code should score higher');
+  t.assert.ok(nested.suggestions.length > 0, 'Should suggest improvements');
+});
+
+await test('score_nesting_quality penalizes else after return', async (t) => {
+  const result = score_nesting_quality(nesting_of('Classify'));
+  t.assert.ok(result.score < 100, 'Should lower the score');
+  t.assert.ok(result.suggestions[0].includes('else'), 'Should suggest dropping the else');
+});
@@ -0,0 +1,69 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go receiver naming functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  find_receiver_names,
+  summarize_receiver_names,
+  get_type_initials
+} from '../../../lib/analysis/receivers.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/receivers.go', 'utf-8'),
+    'calc/calc.go'
+  )
+]);
+
+// ============ get_type_initials tests ============
+
+await test('get_type_initials takes the first letters of the type words', async (t) => {
+  t.assert.eq(get_type_initials('Calculator'), 'c', 'Should take the first letter of one word');
+  t.assert.eq(get_type_initials('BufferedReader'), 'br', 'Should take the first letter of each word');
+  t.assert.eq(get_type_initials('HTTPServer'), 'hs', 'Should split acronyms from the next word');
+  t.assert.eq(get_type_initials('xmlNode'), 'xn', 'Should handle unexported types');
+  t.assert.eq(get_type_initials('GoObject'), 'g', 'Should not suggest a keyword');
+});
+
+// ============ find_receiver_names tests ============
+
+await test('find_receiver_names reports the receiver names by type', async (t) => {
+  const types = Object.fromEntries(
+    find_receiver_names(packages).map((type) => [type.type, type])
+  );
+  t.assert.eq(types.Calculator.consistent, true, 'Should find Calculator consistent');
+  t.assert.eq(types.Calculator.unnamed, 1, 'Should count unnamed receivers apart');
+  t.assert.eq(types.Calculator.issues.length, 0, 'Should not flag consistent types');
+  t.assert.eq(JSON.stringify(types.BufferedReader.names), '{"br":2,"reader":1,"b":1}', 'Should count each name');
+  t.assert.eq(types.BufferedReader.suggestion, 'br', 'Should suggest the most used short name');
+  t.assert.eq(types.HTTPServer.issues.join(','), 'generic', 'Should flag generic names');
+  t.assert.eq(types.HTTPServer.suggestion, 'hs', 'Should suggest initials when no name is idiomatic');
+  t.assert.eq(types.Stack.suggestion, 's', 'Should strip the type arguments of generic receivers');
+  t.assert.eq(types.Logged.methods, 1, 'Should leave promoted methods with their declaring type');
+  t.assert.eq(types.Logged.consistent, true, 'Should not flag the embedding type');
+});
+
+await test('find_receiver_names lists the renames making receivers consistent', async (t) => {
+  const types = find_receiver_names(packages, { inconsistent_only: true });
+  t.assert.eq(
+    types.map((type) => type.type).join(','),
+    'BufferedReader,HTTPServer,Stack',
+    'Should only list types with issues'
+  );
+  t.assert.eq(
+    types[0].renames.map((r) => `${r.method}:${r.from}->${r.to}:${r.conflict}`).join(','),
+    'Len:reader->br:false,Peek:b->br:true',
+    'Should list the renames, marking those clashing with a parameter'
+  );
+  const summary = summarize_receiver_names(types);
+  t.assert.eq(summary.renames, 4, 'Should count the renames');
+  t.assert.eq(summary.conflicts, 1, 'Should count the conflicts');
+});
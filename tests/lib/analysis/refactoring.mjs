@@ -0,0 +1,346 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for refactoring impact analysis functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file, group_go_packages } from '../../../lib/analysis/golang.mjs';
+import {
+  detect_rename_conflicts,
+  validate_new_name,
+  filter_definition_references,
+  build_rename_edits,
+  find_enclosing_entity,
+  get_package_for_file,
+  compare_symbol_sources,
+  find_duplicate_methods,
+  normalize_method,
+  count_body_statements
+} from '../../../lib/analysis/refactoring.mjs';
+
+const entities = [
+  { symbol: 'outer', type: 'function', filename: 'pkg/a.go', start_line: 1, end_line: 20 },
+  { symbol: 'inner', type: 'function', filename: 'pkg/a.go', start_line: 5, end_line: 10 },
+  { symbol: 'other', type: 'function', filename: 'pkg/b.go', start_line: 1, end_line: 10 }
+];
+
+// ============ get_package_for_file tests ============
+
+await test('get_package_for_file returns the directory of a file', async (t) => {
+  t.assert.eq(get_package_for_file('pkg/sub/file.go'), 'pkg/sub', 'Should return directory');
+  t.assert.eq(get_package_for_file('main.go'), '.', 'Should return . for top level files');
+});
+
+// ============ find_enclosing_entity tests ============
+
+await test('find_enclosing_entity returns innermost entity', async (t) => {
+  const result = find_enclosing_entity(entities, 'pkg/a.go', 7);
+  t.assert.eq(result.symbol, 'inner', 'Should pick the narrowest enclosing function');
+});
+
+await test('find_enclosing_entity returns null outside of functions', async (t) => {
+  t.assert.eq(find_enclosing_entity(entities, 'pkg/a.go', 30), null, 'Should return null');
+  t.assert.eq(find_enclosing_entity(entities, 'pkg/c.go', 5), null, 'Should respect filename');
+});
+
+// ============ validate_new_name tests ============
+
+await test('validate_new_name accepts valid identifiers', async (t) => {
+  const result = validate_new_name('oldName', 'newName', 'javascript');
+  t.assert.eq(result.length, 0, 'Should have no conflicts');
+});
+
+await test('validate_new_name rejects invalid identifiers', async (t) => {
+  const result = validate_new_name('oldName', '1bad-name', 'javascript');
+  t.assert.eq(result[0].type, 'invalid_name', 'Should flag invalid name');
+});
+
+await test('validate_new_name rejects $ in Go identifiers', async (t) => {
+  const result = validate_new_name('oldName', 'new$Name', 'go');
+  t.assert.eq(result[0].type, 'invalid_name', 'Should flag $ in a Go name');
+  t.assert.eq(validate_new_name('oldName', 'new$Name', 'javascript').length, 0, 'Should accept $ in JavaScript');
+});
+
+await test('validate_new_name flags unchanged names', async (t) => {
+  const result = validate_new_name('same', 'same', 'javascript');
+  t.assert.ok(result.some((c) => c.type === 'unchanged'), 'Should flag unchanged name');
+});
+
+await test('validate_new_name flags reserved words', async (t) => {
+  const result = validate_new_name('value', 'class', 'javascript');
+  t.assert.ok(result.some((c) => c.type === 'reserved_word'), 'Should flag reserved word');
+});
+
+// ============ detect_rename_conflicts tests ============
+
+await test('detect_rename_conflicts finds package level collisions', async (t) => {
+  const result = detect_rename_conflicts({
+    symbol: 'other',
+    new_name: 'outer',
+    references: [{ filename: 'pkg/b.go', line: 1, is_definition: true }],
+    new_name_references: [],
+    entities
+  });
+  t.assert.eq(result.length, 1, 'Should find one conflict');
+  t.assert.eq(result[0].type, 'collision', 'Should be a collision');
+  t.assert.eq(result[0].filename, 'pkg/a.go', 'Should point at the existing declaration');
+});
+
+await test('detect_rename_conflicts finds collisions in the defining scope', async (t) => {
+  const result = detect_rename_conflicts({
+    symbol: 'count',
+    new_name: 'total',
+    references: [{ filename: 'pkg/a.go', line: 6, is_definition: true }],
+    new_name_references: [{ filename: 'pkg/a.go', line: 8, is_definition: true }],
+    entities
+  });
+  t.assert.eq(result.length, 1, 'Should find one conflict');
+  t.assert.eq(result[0].type, 'collision', 'Should be a collision');
+});
+
+await test('detect_rename_conflicts finds locals that would shadow references', async (t) => {
+  const result = detect_rename_conflicts({
+    symbol: 'config',
+    new_name: 'cfg',
+    references: [
+      { filename: 'pkg/a.go', line: 30, is_definition: true },
+      { filename: 'pkg/b.go', line: 4, is_definition: false }
+    ],
+    new_name_references: [{ filename: 'pkg/b.go', line: 2, is_definition: true }],
+    entities
+  });
+  t.assert.eq(result.length, 1, 'Should find one conflict');
+  t.assert.eq(result[0].type, 'shadowing', 'Should be shadowing');
+  t.assert.eq(result[0].line, 2, 'Should point at the local declaration');
+});
+
+await test('detect_rename_conflicts finds local renames that shadow outer names', async (t) => {
+  const result = detect_rename_conflicts({
+    symbol: 'n',
+    new_name: 'limit',
+    references: [{ filename: 'pkg/b.go', line: 3, is_definition: true }],
+    new_name_references: [
+      { filename: 'pkg/a.go', line: 30, is_definition: true },
+      { filename: 'pkg/b.go', line: 6, is_definition: false }
+    ],
+    entities
+  });
+  t.assert.eq(result.length, 1, 'Should find one conflict');
+  t.assert.eq(result[0].type, 'shadowing', 'Should be shadowing');
+  t.assert.eq(result[0].line, 6, 'Should point at the outer usage');
+});
+
+await test('detect_rename_conflicts ignores unrelated scopes', async (t) => {
+  const result = detect_rename_conflicts({
+    symbol: 'x',
+    new_name: 'y',
+    references: [{ filename: 'pkg/a.go', line: 7, is_definition: true }],
+    new_name_references: [{ filename: 'pkg/b.go', line: 3, is_definition: true }],
+    entities
+  });
+  t.assert.eq(result.length, 0, 'Should find no conflicts');
+});
+
+// ============ filter_definition_references tests ============
+
+const rename_entities = [
+  { symbol: 'run', type: 'function', filename: 'pkg/a.go', start_line: 6, end_line: 10 },
+  { symbol: 'save', type: 'function', filename: 'pkg/a.go', start_line: 12, end_line: 15 },
+  { symbol: 'main', type: 'function', filename: 'pkg/b.go', start_line: 3, end_line: 8 },
+  { symbol: 'load', type: 'function', filename: 'pkg/a.go', start_line: 3, end_line: 4 }
+];
+
+const load_references = [
+  { filename: 'pkg/a.go', line: 3, symbol_type: 'function', is_definition: true, context: 'func load() error {' },
+  { filename: 'pkg/a.go', line: 7, symbol_type: 'variable', is_definition: true, context: '\tload := true' },
+  { filename: 'pkg/a.go', line: 8, symbol_type: 'variable', is_definition: false, context: '\tif load {' },
+  { filename: 'pkg/a.go', line: 12, symbol_type: 'parameter', is_definition: true, context: 'func save(load bool) {' },
+  { filename: 'pkg/a.go', line: 13, symbol_type: 'variable', is_definition: false, context: '\tfmt.Println(load)' },
+  { filename: 'pkg/b.go', line: 4, symbol_type: 'function', is_definition: false, context: '\tload()' },
+  { filename: 'pkg/b.go', line: 5, symbol_type: 'field', is_definition: false, context: '\tc.load = nil' }
+];
+
+await test('filter_definition_references keeps only the package level function', async (t) => {
+  const references = filter_definition_references({
+    symbol: 'load',
+    definition: load_references[0],
+    references: load_references,
+    entities: rename_entities
+  });
+  const edits = build_rename_edits(references);
+  t.assert.eq(
+    edits.map((e) => `${e.filename}:${e.line}`).join(' '),
+    'pkg/a.go:3 pkg/b.go:4',
+    'Should edit the declaration and call of load only'
+  );
+});
+
+await test('filter_definition_references keeps a local within its function', async (t) => {
+  const references = filter_definition_references({
+    symbol: 'load',
+    definition: load_references[1],
+    references: load_references,
+    entities: rename_entities
+  });
+  t.assert.eq(
+    references.map((r) => `${r.filename}:${r.line}`).join(' '),
+    'pkg/a.go:7 pkg/a.go:8',
+    'Should keep the local of run only'
+  );
+});
+
+await test('filter_definition_references matches linked entities', async (t) => {
+  const definition = { ...load_references[0], definition_entity_id: 1 };
+  const references = filter_definition_references({
+    symbol: 'load',
+    definition,
+    references: [
+      definition,
+      { filename: 'pkg/c.go', line: 9, symbol_type: 'field', is_definition: false, definition_entity_id: 1 },
+      { filename: 'pkg/c.go', line: 10, symbol_type: 'function', is_definition: false, definition_entity_id: 2 }
+    ],
+    entities: rename_entities
+  });
+  t.assert.eq(references.map((r) => r.line).join(' '), '3 9', 'Should keep the references linked to the definition');
+});
+
+// ============ build_rename_edits tests ============
+
+await test('build_rename_edits sorts edits by location', async (t) => {
+  const result = build_rename_edits([
+    { filename: 'pkg/b.go', line: 1, column_start: 4, is_definition: false },
+    { filename: 'pkg/a.go', line: 9, column_start: 8, is_definition: false },
+    { filename: 'pkg/a.go', line: 9, column_start: 2, is_definition: false },
+    { filename: 'pkg/a.go', line: 3, column_start: 5, is_definition: true }
+  ]);
+  t.assert.eq(
+    result.map((e) => `${e.filename}:${e.line}:${e.column_start}`),
+    ['pkg/a.go:3:5', 'pkg/a.go:9:2', 'pkg/a.go:9:8', 'pkg/b.go:1:4'],
+    'Should sort by filename, line and column'
+  );
+  t.assert.eq(result[0].is_definition, true, 'Should keep definition flag');
+});
+
+// ============ compare_symbol_sources tests ============
+
+const compare_a = {
+  filename: 'store/a.go',
+  source: readFileSync('./tests/fixtures/compare_a.go', 'utf-8')
+};
+const compare_b = {
+  filename: 'store/b.go',
+  source: readFileSync('./tests/fixtures/compare_b.go', 'utf-8')
+};
+
+const aspects = (result) =>
+  result.changes.map((c) => `${c.aspect}${c.name ? ':' + c.name : ''}`).join(' ');
+
+await test('compare_symbol_sources reports parameter renames as compatible', async (t) => {
+  const result = compare_symbol_sources('Open', compare_a, compare_b);
+  t.assert.eq(result.status, 'changed', 'Should detect changes');
+  t.assert.eq(aspects(result), 'param_names body', 'Should report renamed parameters');
+  t.assert.ok(result.summary.api_preserved, 'Renaming parameters preserves the API');
+});
+
+await test('compare_symbol_sources reports receiver and signature changes', async (t) => {
+  const result = compare_symbol_sources('Store.Load', compare_a, compare_b);
+  const receiver = result.changes.find((c) => c.aspect === 'receiver');
+  t.assert.eq(receiver.before, '*Store', 'Should report old receiver');
+  t.assert.eq(receiver.after, 'Store', 'Should report new receiver');
+  const params = result.changes.find((c) => c.aspect === 'params');
+  t.assert.eq(params.after, 'context.Context, string, ...string', 'Should report new parameters');
+  t.assert.ok(result.changes.some((c) => c.aspect === 'doc'), 'Should report doc changes');
+  t.assert.eq(result.summary.breaking_count, 2, 'Should count API changes');
+});
+
+await test('compare_symbol_sources accepts bare method names', async (t) => {
+  const result = compare_symbol_sources('Load', compare_a, compare_b);
+  t.assert.eq(result.a.kind, 'method', 'Should find the method');
+});
+
+await test('compare_symbol_sources compares struct fields', async (t) => {
+  const result = compare_symbol_sources('Store', compare_a, compare_b);
+  t.assert.eq(
+    aspects(result),
+    'field:Path field:Timeout field:cache field:Retries field:items doc',
+    'Should report field changes'
+  );
+  const removed = result.changes.find((c) => c.name === 'cache');
+  t.assert.eq(removed.breaking, false, 'Unexported fields are not API');
+  t.assert.eq(result.summary.breaking_count, 2, 'Tag and type changes are API changes');
+});
+
+await test('compare_symbol_sources flags methods added to interfaces', async (t) => {
+  const result = compare_symbol_sources('Backend', compare_a, compare_b);
+  t.assert.eq(result.changes.length, 1, 'Should find one change');
+  t.assert.eq(result.changes[0].change, 'added', 'Should be an added method');
+  t.assert.ok(result.changes[0].breaking, 'Should be an API change');
+});
+
+await test('compare_symbol_sources compares constant values', async (t) => {
+  const result = compare_symbol_sources('Version', compare_a, compare_b);
+  t.assert.eq(result.changes[0].before, '2', 'Should report old value');
+  t.assert.eq(result.changes[0].after, '3', 'Should report new value');
+});
+
+await test('compare_symbol_sources handles symbols missing on one side', async (t) => {
+  t.assert.eq(compare_symbol_sources('Close', compare_a, compare_b).status, 'missing_in_b', 'Removed');
+  t.assert.eq(compare_symbol_sources('Close', compare_b, compare_a).status, 'missing_in_a', 'Added');
+  t.assert.eq(compare_symbol_sources('Nope', compare_a, compare_b).status, 'missing', 'Missing');
+  t.assert.ok(!compare_symbol_sources('Close', compare_a, compare_b).summary.api_preserved, 'Not preserved');
+});
+
+await test('compare_symbol_sources reports identical declarations', async (t) => {
+  const result = compare_symbol_sources('Open', compare_a, compare_a);
+  t.assert.eq(result.status, 'identical', 'Should be identical');
+  t.assert.eq(result.changes.length, 0, 'Should have no changes');
+});
+
+// ============ find_duplicate_methods tests ============
+
+const duplicate_packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/duplicate_methods.go', 'utf-8'),
+    'store/store.go'
+  )
+]);
+
+await test('normalize_method ignores the receiver, locals and layout', async (t) => {
+  const [pkg] = duplicate_packages;
+  const [file_store, memory_store] = pkg.functions.filter((f) => f.name === 'Validate');
+  const a = normalize_method(file_store);
+  const b = normalize_method(memory_store);
+  t.assert.eq(a.tokens.join(' '), b.tokens.join(' '), 'Should normalize to the same tokens');
+  t.assert.ok(a.tokens.includes('$0'), 'Should replace parameters');
+  t.assert.ok(!a.tokens.includes('key'), 'Should not keep parameter names');
+  t.assert.ok(a.tokens.includes('ContainsAny'), 'Should keep selectors');
+});
+
+await test('count_body_statements skips blank lines, comments and closing braces', async (t) => {
+  t.assert.eq(count_body_statements('{\n\treturn 1\n}'), 1, 'One-liner');
+  t.assert.eq(count_body_statements('{\n\t// c\n\tif x {\n\t\ty()\n\t}\n\n\treturn\n}'), 3, 'Three statements');
+});
+
+await test('find_duplicate_methods groups identical methods across types', async (t) => {
+  const groups = find_duplicate_methods(duplicate_packages);
+  const names = groups.map((g) => g.names.join(','));
+  t.assert.eq(groups.length, 2, 'Should find two groups');
+  t.assert.ok(names.includes('Validate') && names.includes('HitRate'), 'Should group Validate and HitRate');
+  t.assert.ok(!names.includes('Reset'), 'Should not group methods with different logic');
+  t.assert.ok(!names.includes('Name'), 'Should exclude trivial one-liners');
+
+  const hit_rate = groups.find((g) => g.names[0] === 'HitRate');
+  t.assert.eq(hit_rate.types.join(','), 'FileStore,MemoryStore', 'Should report both types');
+  t.assert.eq(hit_rate.methods[0].filename, 'store/store.go', 'Should report locations');
+  t.assert.eq(hit_rate.receiver_fields.join(','), 'hits,misses', 'Should report receiver fields');
+});
+
+await test('find_duplicate_methods suggests how to share the logic', async (t) => {
+  const groups = find_duplicate_methods(duplicate_packages);
+  const kind = (name) => groups.find((g) => g.names[0] === name).suggestion.kind;
+  t.assert.eq(kind('Validate'), 'function', 'Receiver unused: package function');
+  t.assert.eq(kind('HitRate'), 'embed', 'Shared fields: embedded struct');
+  t.assert.eq(find_duplicate_methods(duplicate_packages, { min_statements: 1 }).length, 3, 'Should include one-liners when asked');
+});
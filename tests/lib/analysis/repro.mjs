@@ -0,0 +1,128 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go minimal reproduction functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_file } from '../../../lib/analysis/golang.mjs';
+import {
+  minimal_repro_from_sources,
+  find_references
+} from '../../../lib/analysis/repro.mjs';
+
+const sources = [
+  {
+    filename: 'shapes/shapes.go',
+    source: readFileSync('./tests/fixtures/repro.go', 'utf-8')
+  },
+  {
+    filename: 'units/units.go',
+    source: readFileSync('./tests/fixtures/repro_units.go', 'utf-8')
+  },
+  { filename: 'shapes/shapes_test.go', source: 'package shapes\n\nfunc Area() {}\n' }
+];
+
+// Parse a reproduction back, the way a reader of the snippet would
+const reparse = (repro) => {
+  const file = parse_go_file(repro.source, 'repro.go');
+  const names = [
+    ...file.functions.map((f) =>
+      f.receiver ? `${f.receiver.type}.${f.name}` : f.name
+    ),
+    ...file.types.map((t) => t.name),
+    ...file.consts.flatMap((c) => c.names)
+  ];
+  return { file, names };
+};
+
+// ============ find_references tests ============
+
+await test('find_references separates selectors and qualified names', async (t) => {
+  const references = find_references('fmt.Println(s.Name(), Circle{})');
+  const fmt = references.find((r) => r.name === 'fmt');
+  t.assert.eq(fmt.member, 'Println', 'Should carry the selected member');
+  t.assert.ok(references.find((r) => r.name === 'Name').selector, 'Name is a selector');
+  t.assert.ok(!references.find((r) => r.name === 'Circle').selector, 'Circle is not');
+});
+
+// ============ minimal_repro_from_sources tests ============
+
+await test('minimal_repro_from_sources copies a method with its receiver type', async (t) => {
+  const repro = minimal_repro_from_sources(sources, 'Circle.Area');
+  const { file, names } = reparse(repro);
+  t.assert.eq(file.package, 'shapes', 'Should keep the package name');
+  t.assert.ok(names.includes('Circle'), 'Should copy the receiver type');
+  t.assert.ok(!names.includes('Circle.Perimeter'), 'Should not copy uncalled methods');
+  t.assert.ok(!names.includes('Shape'), 'Should not copy unrelated types');
+  t.assert.ok(repro.source.includes('type Circle struct {'), 'Should lift grouped types');
+  t.assert.ok(repro.source.includes('// Area returns the area'), 'Should keep doc comments');
+  t.assert.eq(repro.imports.join(','), 'math', 'Should keep used standard imports only');
+});
+
+await test('minimal_repro_from_sources copies methods needed by interfaces', async (t) => {
+  const repro = minimal_repro_from_sources(sources, 'Unit');
+  const { names } = reparse(repro);
+  t.assert.ok(names.includes('Shape'), 'Should copy the result type');
+  t.assert.ok(names.includes('Circle.Name'), 'Circle must implement Shape');
+  t.assert.ok(!names.includes('Circle.Perimeter'), 'Perimeter is not part of Shape');
+});
+
+await test('minimal_repro_from_sources stubs project packages', async (t) => {
+  const repro = minimal_repro_from_sources(sources, 'Grow');
+  const { file, names } = reparse(repro);
+  t.assert.ok(names.includes('units_Length'), 'Should copy and rename project types');
+  const scale = file.functions.find((f) => f.name === 'units_Scale');
+  t.assert.ok(scale, 'Should stub project functions');
+  t.assert.eq(scale.results_text, 'units_Length', 'Should rename types in signatures');
+  t.assert.ok(scale.body.includes('panic("stub")'), 'Stubs panic');
+  t.assert.ok(repro.source.includes('units_Scale(c.Radius, factor)'), 'Should rename references');
+  t.assert.ok(!repro.source.includes('units.'), 'Should not reference the package');
+  t.assert.ok(
+    repro.declarations.find((d) => d.name === 'Scale').stubbed,
+    'Should flag stubbed declarations'
+  );
+});
+
+await test('minimal_repro_from_sources stubs unknown packages', async (t) => {
+  const repro = minimal_repro_from_sources(sources, 'Draw');
+  const { names } = reparse(repro);
+  t.assert.ok(names.includes('Describe'), 'Should copy called functions');
+  t.assert.ok(names.includes('units_Suffix'), 'Should copy project constants');
+  t.assert.ok(names.includes('render_Canvas'), 'Should stub types used in signatures');
+  t.assert.ok(names.includes('render_Text'), 'Should stub called functions');
+  t.assert.eq(
+    repro.stubs.map((s) => `${s.name}:${s.kind}`).join(','),
+    'render_Canvas:type,render_Text:func',
+    'Should report opaque stubs'
+  );
+  t.assert.ok(!repro.source.includes('github.com/acme/render'), 'Should drop the import');
+});
+
+await test('minimal_repro_from_sources copies whole constant groups', async (t) => {
+  const repro = minimal_repro_from_sources(sources, 'shapes.KindSquare');
+  const { names } = reparse(repro);
+  t.assert.ok(names.includes('KindCircle'), 'iota depends on the whole group');
+  t.assert.ok(names.includes('Kind'), 'Should copy the constant type');
+});
+
+await test('minimal_repro_from_sources rejects unknown and ambiguous symbols', async (t) => {
+  const error_of = (files, symbol) => {
+    try {
+      minimal_repro_from_sources(files, symbol);
+      return '';
+    } catch (error) {
+      return error.message;
+    }
+  };
+  const more = [
+    ...sources,
+    { filename: 'other/other.go', source: 'package other\n\nfunc Unit() {}\n' }
+  ];
+  t.assert.ok(error_of(sources, 'Missing').includes('not found'), 'Should reject unknown symbols');
+  t.assert.ok(error_of(sources, 'Area').includes('not found'), 'Test files are ignored');
+  t.assert.ok(error_of(more, 'Unit').includes('ambiguous'), 'Should reject ambiguous symbols');
+  const qualified = minimal_repro_from_sources(more, 'other.Unit');
+  t.assert.eq(qualified.directory, 'other', 'Qualified symbols are not ambiguous');
+});
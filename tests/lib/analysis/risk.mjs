@@ -0,0 +1,98 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go function risk functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_source_risk_scores,
+  parse_git_churn
+} from '../../../lib/analysis/risk.mjs';
+
+const sources = [
+  {
+    filename: 'billing/billing.go',
+    source: readFileSync('./tests/fixtures/risk.go', 'utf-8')
+  }
+];
+
+const report = find_source_risk_scores(sources);
+const find_function = (result, name) =>
+  result.functions.find((f) => f.name === name);
+const round = (value) => Math.round(value * 10) / 10;
+
+// ============ parse_git_churn tests ============
+
+await test('parse_git_churn counts commits by file', async (t) => {
+  const churn = parse_git_churn('\0\n\nbilling/billing.go\nREADME.md\n\0\n\nbilling/billing.go\nbilling/billing.go\n');
+  t.assert.eq(churn.get('billing/billing.go'), 2, 'Should count each commit once per file');
+  t.assert.eq(churn.get('README.md'), 1, 'Should count every file');
+  t.assert.eq(parse_git_churn('').size, 0, 'Should accept an empty log');
+});
+
+// ============ find_source_risk_scores tests ============
+
+await test('find_source_risk_scores collects the metrics', async (t) => {
+  const parse = find_function(report, 'Parse');
+  t.assert.eq(parse.complexity, 6, 'Should give the cyclomatic complexity');
+  t.assert.eq(parse.fan_in, 3, 'Should give the fan-in');
+  t.assert.eq(find_function(report, 'Report').fan_out, 4, 'Should give the fan-out');
+  t.assert.eq(parse.churn, null, 'Should leave churn out without a log');
+});
+
+await test('find_source_risk_scores ranks complex widely called functions first', async (t) => {
+  t.assert.eq(report.functions[0].name, 'Parse', 'Should rank the riskiest function first');
+  t.assert.eq(report.functions[report.functions.length - 1].name, 'Report', 'Should rank the least risky function last');
+  t.assert.eq(report.scores['billing:Parse'], report.functions[0].score, 'Should map ids to scores');
+});
+
+await test('find_source_risk_scores applies the formula', async (t) => {
+  // Invoice: 1 decision point of 5, fan-in 1 of 3, fan-out 1 of 4
+  const norm = (value, max) => Math.log1p(value) / Math.log1p(max);
+  const expected = (100 * (0.4 * norm(1, 5) + 0.3 * norm(1, 3) + 0.1 * norm(1, 4))) / 0.8;
+  const invoice = find_function(report, 'Invoice');
+  t.assert.eq(invoice.score, round(expected), 'Should compute the weighted mean of the normalized metrics');
+  t.assert.eq(invoice.factors.fan_in, round((100 * 0.3 * norm(1, 3)) / 0.8), 'Should give the contribution of each metric');
+  t.assert.eq(find_function(report, 'Tax').factors.complexity, 0, 'Should count complexity from its decision points');
+  t.assert.eq(report.functions[0].score, round((100 * (0.4 + 0.3)) / 0.8), 'Should score the highest metrics fully');
+});
+
+await test('find_source_risk_scores takes churn and weights', async (t) => {
+  const churn = parse_git_churn('\0\n\nbilling/billing.go\n');
+  const with_churn = find_source_risk_scores(sources, { churn });
+  t.assert.eq(find_function(with_churn, 'Parse').churn, 1, 'Should give the churn of the file');
+  t.assert.eq(find_function(with_churn, 'Parse').score, 90, 'Should weigh churn in');
+  t.assert.ok(with_churn.summary.churn, 'Should tell churn counts');
+
+  const weighted = find_source_risk_scores(sources, { weights: { complexity: 0, fan_in: 0, fan_out: 1 } });
+  t.assert.eq(weighted.functions[0].name, 'Report', 'Should apply custom weights');
+  t.assert.eq(weighted.functions[0].score, 100, 'Should normalize on the weights used');
+});
+
+await test('find_source_risk_scores checks weights', async (t) => {
+  let error = null;
+  try {
+    find_source_risk_scores(sources, { weights: { size: 1 } });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error && error.message.includes('Unknown metric'), 'Should reject unknown metrics');
+  error = null;
+  try {
+    find_source_risk_scores(sources, { weights: { complexity: 0, fan_in: 0, fan_out: 0 } });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject weights all 0');
+});
+
+await test('find_source_risk_scores summarizes', async (t) => {
+  const limited = find_source_risk_scores(sources, { limit: 2 });
+  t.assert.eq(limited.functions.length, 2, 'Should list the riskiest functions');
+  t.assert.eq(Object.keys(limited.scores).length, 6, 'Should score every function');
+  t.assert.eq(report.summary.functions, 6, 'Should count functions');
+  t.assert.eq(report.summary.max_score, 87.5, 'Should give the highest score');
+  t.assert.ok(!('churn' in report.summary.weights), 'Should leave the churn weight out without churn');
+});
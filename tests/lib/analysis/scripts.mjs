@@ -0,0 +1,25 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go script analysis functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { parse_go_sources } from '../../../lib/analysis/golang.mjs';
+import { summarize_go_script } from '../../../lib/analysis/scripts.mjs';
+
+// ============ summarize_go_script tests ============
+
+await test('summarize_go_script describes generator programs', async (t) => {
+  const sources = [
+    { filename: 'gen.go', source: readFileSync('./tests/fixtures/gen_script.go', 'utf-8') }
+  ];
+  const [script] = parse_go_sources(sources, { include_scripts: true }).scripts;
+  const summary = summarize_go_script(script);
+  t.assert.eq(summary.filename, 'gen.go', 'Should keep the filename');
+  t.assert.ok(summary.has_main, 'Should detect the main function');
+  t.assert.eq(summary.synopsis, 'This program generates tables.go.', 'Should use the file comment');
+  t.assert.eq(summary.imports.join(','), 'fmt,os', 'Should list imports');
+  t.assert.eq(summary.generated_by.length, 0, 'Nothing runs this script');
+});
@@ -0,0 +1,100 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go search index export functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  build_search_index,
+  format_search_index,
+  get_type_signature,
+  SEARCH_INDEX_FIELDS
+} from '../../../lib/analysis/searchindex.mjs';
+
+const source = readFileSync('./tests/fixtures/near_miss.go', 'utf-8');
+const sources = [{ filename: 'geo/shapes.go', source }];
+const documents = build_search_index(sources);
+const find = (name) => documents.find((d) => d.qualifiedName === name);
+
+// ============ build_search_index tests ============
+
+await test('build_search_index emits one flat document per symbol', async (t) => {
+  const area = find('geo.Triangle.Area');
+  t.assert.ok(area, 'Should index methods qualified by receiver');
+  t.assert.eq(area.kind, 'method', 'Should have the method kind');
+  t.assert.eq(area.signature, 'func (t *Triangle) Area() float64', 'Should have a one line signature');
+  t.assert.eq(area.doc, 'Area returns the area of the triangle.', 'Should strip comment markers');
+  t.assert.eq(area.file, 'geo/shapes.go', 'Should carry the file');
+  t.assert.eq(area.package, 'geo', 'Should carry the package');
+  t.assert.eq(area.id, 'geo:geo.Triangle.Area', 'Should have a stable id');
+
+  for (const document of documents) {
+    for (const field of SEARCH_INDEX_FIELDS) {
+      t.assert.ok(field.name in document, `Should have the ${field.name} field`);
+    }
+    t.assert.ok(
+      Object.values(document).every((v) => typeof v !== 'object'),
+      'Should be flat'
+    );
+  }
+});
+
+await test('build_search_index covers types, fields and interface methods', async (t) => {
+  t.assert.eq(find('geo.Shape').kind, 'interface', 'Should index interfaces');
+  t.assert.eq(find('geo.Shape.Perimeter').signature, 'Perimeter() float64', 'Should index interface methods');
+  t.assert.eq(find('geo.Triangle.B').kind, 'field', 'Should index each name of a field');
+  t.assert.eq(find('geo.Label').signature, 'type Label string', 'Should give the underlying type');
+  t.assert.ok(!find('geo.Cube.Square'), 'Should skip embedded fields');
+  t.assert.eq(
+    get_type_signature({ name: 'ID', kind: 'alias', underlying: 'string' }),
+    'type ID = string',
+    'Should format aliases'
+  );
+});
+
+await test('build_search_index fingerprints ignore docs and position', async (t) => {
+  const moved = source
+    .replace('// Area returns the area of the triangle.', '// Area computes Heron.')
+    .replace('package geo\n', 'package geo\n\n\n');
+  const changed = source.replace('s := (t.A + t.B + t.C) / 2', 's := (t.A + t.B + t.C) * 0.5');
+  const fingerprint = (src) =>
+    build_search_index([{ filename: 'geo/shapes.go', source: src }]).find(
+      (d) => d.qualifiedName === 'geo.Triangle.Area'
+    ).fingerprint;
+
+  t.assert.eq(fingerprint(moved), find('geo.Triangle.Area').fingerprint, 'Should not change with docs or lines');
+  t.assert.ok(fingerprint(changed) !== find('geo.Triangle.Area').fingerprint, 'Should change with the body');
+});
+
+await test('build_search_index filters and accepts custom fields', async (t) => {
+  const methods = build_search_index(sources, {
+    kinds: ['method'],
+    fields: (document, context) => ({
+      url: `https://example.com/${document.file}#L${document.line}`,
+      end_line: context.declaration.end_line
+    })
+  });
+  t.assert.ok(methods.every((d) => d.kind === 'method'), 'Should filter kinds');
+  t.assert.eq(methods[0].url, 'https://example.com/geo/shapes.go#L7', 'Should add custom fields');
+  t.assert.ok(methods.every((d) => d.end_line >= d.line), 'Should pass the declaration');
+
+  let error = null;
+  try {
+    build_search_index(sources, { fields: () => ({ kind: 'x' }) });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error && error.message.includes("'kind'"), 'Should refuse to override schema fields');
+});
+
+// ============ format_search_index tests ============
+
+await test('format_search_index writes one JSON document per line', async (t) => {
+  const text = format_search_index(documents);
+  const lines = text.split('\n');
+  t.assert.eq(lines.pop(), '', 'Should end with a newline');
+  t.assert.eq(lines.length, documents.length, 'Should have one line per document');
+  t.assert.eq(JSON.parse(lines[0]).id, documents[0].id, 'Should parse back');
+});
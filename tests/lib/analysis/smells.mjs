@@ -0,0 +1,85 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go code smell score functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  collect_smell_diagnostics,
+  score_smells,
+  calculate_go_complexity
+} from '../../../lib/analysis/smells.mjs';
+
+const packages = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/smells.go', 'utf-8'),
+    'sync/sync.go'
+  )
+]);
+const diagnostics = collect_smell_diagnostics(packages);
+const of = (symbol, code) =>
+  diagnostics.find((d) => d.symbol === symbol && d.code === code);
+
+// ============ collect_smell_diagnostics tests ============
+
+await test('collect_smell_diagnostics finds ignored errors', async (t) => {
+  const unchecked = of('Syncer.Run', 'unchecked_error');
+  t.assert.eq(unchecked.value, 2, 'Should find calls of error returning functions used as statements');
+  t.assert.eq(unchecked.lines.join(','), '34,42', 'Should record the call lines');
+  const discarded = of('Syncer.Run', 'discarded_error');
+  t.assert.eq(discarded.lines.join(','), '29,41', 'Should find errors assigned to _');
+  t.assert.ok(!of('Syncer.Run', 'discarded_error').lines.includes(30), 'Should not flag range loops');
+});
+
+await test('collect_smell_diagnostics collects metrics and conventions', async (t) => {
+  t.assert.eq(of('Syncer.Run', 'nesting').value, 4, 'Should measure nesting');
+  t.assert.eq(of('Syncer.Run', 'parameters').value, 5, 'Should count parameters');
+  t.assert.eq(of('Syncer.Run', 'panic').value, 1, 'Should count panics');
+  t.assert.ok(of('Syncer.Run', 'misplaced_context'), 'Should report the misplaced context');
+  t.assert.eq(of('Syncer', 'undocumented').value, 2, 'Should count the type and its fields');
+  t.assert.ok(!of('Count', 'undocumented'), 'Documented functions are fine');
+  t.assert.eq(calculate_go_complexity('{ if a && b { return } }'), 3, 'Should count decisions');
+});
+
+// ============ score_smells tests ============
+
+await test('score_smells ranks symbols and files', async (t) => {
+  const result = score_smells(diagnostics);
+  t.assert.eq(result.symbols[0].symbol, 'Syncer.Run', 'The worst symbol comes first');
+  t.assert.eq(result.scores['sync:Syncer.Run'], result.symbols[0].score, 'Should key scores by symbol');
+  t.assert.ok(!('sync:Count' in result.scores), 'Clean symbols have no score');
+  t.assert.eq(result.files[0].score, 9.5, 'A file scores the sum of its symbols');
+  t.assert.ok(
+    !result.symbols[0].diagnostics.some((d) => d.code === 'nesting'),
+    'Metrics within their threshold do not score'
+  );
+
+  const strict = score_smells(diagnostics, { thresholds: { nesting: 3 } });
+  const nesting = strict.symbols[0].diagnostics.find((d) => d.code === 'nesting');
+  t.assert.eq(nesting.points, 0.33, 'Metrics score their relative overshoot');
+});
+
+await test('score_smells uses configurable weights', async (t) => {
+  const result = score_smells(diagnostics, {
+    weights: { unchecked_error: 0, undocumented: 2 },
+    thresholds: { nesting: 2 }
+  });
+  const run = result.symbols.find((s) => s.symbol === 'Syncer.Run');
+  t.assert.ok(!run.diagnostics.some((d) => d.code === 'unchecked_error'), 'A weight of 0 disables a diagnostic');
+  t.assert.eq(run.diagnostics.find((d) => d.code === 'nesting').points, 1, 'Should use the threshold');
+  t.assert.eq(result.scores['sync:Syncer'], 4, 'Should apply weights');
+
+  let error = null;
+  try {
+    score_smells(diagnostics, { weights: { typo: 1 } });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error && error.message.includes("'typo'"), 'Should reject unknown codes');
+});
@@ -0,0 +1,67 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go package stability functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { find_source_package_metrics } from '../../../lib/analysis/stability.mjs';
+
+const sources = [
+  ['stability_model.go', 'model/model.go'],
+  ['stability_store.go', 'store/store.go'],
+  ['stability_store_test.go', 'store/store_test.go'],
+  ['stability_service.go', 'service/service.go'],
+  ['stability_api.go', 'api/api.go'],
+  ['stability_plugin.go', 'plugin/plugin.go']
+].map(([fixture, filename]) => ({
+  filename,
+  source: readFileSync(`./tests/fixtures/${fixture}`, 'utf-8')
+}));
+
+const { packages, summary } = find_source_package_metrics(sources);
+const find_package = (directory) =>
+  packages.find((p) => p.directory === directory);
+
+// ============ find_source_package_metrics tests ============
+
+await test('find_source_package_metrics counts package couplings', async (t) => {
+  const store = find_package('store');
+  t.assert.eq(store.afferent_coupling, 2, 'Should count dependent packages');
+  t.assert.eq(store.efferent_coupling, 1, 'Should count imported project packages');
+  t.assert.eq(store.dependents.join(','), 'api,service', 'Should not count test imports');
+  t.assert.eq(find_package('api').efferent_coupling, 3, 'Should not count external imports');
+});
+
+await test('find_source_package_metrics computes instability', async (t) => {
+  t.assert.eq(find_package('model').instability, 0, 'Should be stable without dependencies');
+  t.assert.eq(find_package('api').instability, 1, 'Should be unstable without dependents');
+  t.assert.eq(find_package('store').instability, 0.33, 'Should divide Ce by Ca + Ce');
+  t.assert.eq(find_package('service').instability, 0.67, 'Should round to two decimals');
+});
+
+await test('find_source_package_metrics computes abstractness', async (t) => {
+  const store = find_package('store');
+  t.assert.eq(store.types, 3, 'Should not count types of test files');
+  t.assert.eq(store.abstractness, 0.67, 'Should divide interfaces by types');
+  t.assert.eq(find_package('model').abstractness, 0, 'Should be concrete without interfaces');
+  t.assert.eq(find_package('plugin').abstractness, 1, 'Should be abstract with only interfaces');
+});
+
+await test('find_source_package_metrics computes the distance from the main sequence', async (t) => {
+  t.assert.eq(find_package('store').distance, 0, 'Should be on the main sequence');
+  t.assert.eq(find_package('service').zone, 'main_sequence', 'Should classify balanced packages');
+  t.assert.eq(find_package('model').distance, 1, 'Should compute |A + I - 1|');
+  t.assert.eq(find_package('model').zone, 'pain', 'Should find concrete stable packages');
+  t.assert.eq(find_package('plugin').zone, 'uselessness', 'Should find abstract unstable packages');
+  t.assert.eq(packages[0].directory, 'model', 'Should sort by distance');
+});
+
+await test('find_source_package_metrics summarizes the packages', async (t) => {
+  t.assert.eq(summary.packages, 5, 'Should count packages');
+  t.assert.eq(summary.by_zone.main_sequence, 3, 'Should count packages by zone');
+  t.assert.eq(summary.mean_distance, 0.4, 'Should average the distance');
+  const strict = find_source_package_metrics(sources, { threshold: 1 });
+  t.assert.eq(strict.summary.by_zone.pain, 0, 'Should apply the threshold');
+});
@@ -0,0 +1,567 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go struct analysis functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  compute_type_layout,
+  compute_struct_layouts,
+  build_layout_context,
+  find_large_value_params,
+  find_field_initializations,
+  find_struct_literals,
+  get_typical_init,
+  classify_structs,
+  classify_method_role,
+  find_method_shadowing,
+  find_embedders,
+  find_source_embedders,
+  validate_struct_tags,
+  parse_struct_tag,
+  find_type_complexity,
+  find_copy_locks,
+  find_field_types,
+  find_options_structs,
+  find_recursive_structs,
+  align_to
+} from '../../../lib/analysis/structs.mjs';
+
+const load_fixture = (filename) => {
+  const source = readFileSync(`./tests/fixtures/${filename}`, 'utf-8');
+  return group_go_packages([parse_go_file(source, `fixtures/${filename}`)])[0];
+};
+
+const layout_pkg = load_fixture('struct_layout.go');
+
+// ============ Layout tests ============
+
+await test('align_to rounds offsets up', async (t) => {
+  t.assert.eq(align_to(5, 4), 8, 'Should round up');
+  t.assert.eq(align_to(8, 8), 8, 'Should keep aligned offsets');
+  t.assert.eq(align_to(3, 1), 3, 'Should ignore byte alignment');
+});
+
+await test('compute_type_layout handles builtin and composite types', async (t) => {
+  const context = build_layout_context(layout_pkg);
+  t.assert.eq(compute_type_layout('string', context).size, 16, 'string is 16 bytes');
+  t.assert.eq(compute_type_layout('[]int', context).size, 24, 'slice is 24 bytes');
+  t.assert.eq(compute_type_layout('*Large', context).size, 8, 'pointer is 8 bytes');
+  t.assert.eq(compute_type_layout('[bufferSize]int32', context).size, 32, 'array uses constant length');
+  t.assert.eq(compute_type_layout('Reader', context).size, 16, 'interface is 16 bytes');
+});
+
+await test('compute_struct_layouts includes padding', async (t) => {
+  const layouts = compute_struct_layouts(layout_pkg);
+  const small = layouts.find((l) => l.name === 'Small');
+  t.assert.eq(small.size, 8, 'Small should be 8 bytes');
+  t.assert.eq(small.padding, 3, 'Small should have 3 bytes of tail padding');
+
+  const padded = layouts.find((l) => l.name === 'Padded');
+  t.assert.eq(padded.size, 24, 'Padded should be 24 bytes');
+  t.assert.eq(padded.padding, 14, 'Padded should have 14 bytes of padding');
+  t.assert.eq(padded.fields[1].offset, 8, 'B should be aligned to 8');
+});
+
+await test('compute_struct_layouts resolves nested and library types', async (t) => {
+  const layouts = compute_struct_layouts(layout_pkg);
+  const large = layouts.find((l) => l.name === 'Large');
+  t.assert.eq(large.size, 176, 'Large should be 176 bytes');
+  t.assert.eq(large.exact, true, 'Large layout should be exact');
+
+  const guarded = layouts.find((l) => l.name === 'Guarded');
+  t.assert.eq(guarded.size, 32, 'Guarded should be 32 bytes');
+});
+
+await test('compute_struct_layouts marks unknown types as inexact', async (t) => {
+  const pkg = group_go_packages([
+    parse_go_file('package p\n\ntype T struct {\n\tv other.Thing\n}\n', 'p/t.go')
+  ])[0];
+  const layouts = compute_struct_layouts(pkg);
+  t.assert.eq(layouts[0].exact, false, 'Should be inexact');
+});
+
+// ============ Large value parameter tests ============
+
+await test('find_large_value_params flags large by-value structs', async (t) => {
+  const findings = find_large_value_params(layout_pkg);
+  const names = findings.map((f) => `${f.function}:${f.parameter}`);
+  t.assert.ok(names.includes('Process:l'), 'Should flag Large parameter');
+  t.assert.ok(names.includes('Summary:l'), 'Should flag Large value receiver');
+  t.assert.eq(findings[0].size, 176, 'Should report computed size');
+});
+
+await test('find_large_value_params ignores pointers, interfaces and small structs', async (t) => {
+  const findings = find_large_value_params(layout_pkg);
+  const functions = findings.map((f) => f.function);
+  t.assert.ok(!functions.includes('ProcessPointer'), 'Should not flag pointer parameters');
+  t.assert.ok(!functions.includes('Rename'), 'Should not flag pointer receivers');
+  t.assert.ok(!functions.includes('Consume'), 'Should not flag interfaces or variadics');
+  t.assert.ok(!functions.includes('Describe'), 'Should not flag small structs');
+});
+
+await test('find_large_value_params respects the threshold', async (t) => {
+  const findings = find_large_value_params(layout_pkg, { threshold: 16 });
+  const names = findings.map((f) => `${f.function}:${f.parameter}`);
+  t.assert.ok(names.includes('Describe:p'), 'Should flag Padded with a lower threshold');
+  t.assert.ok(!names.includes('Describe:s'), 'Should still skip Small');
+});
+
+await test('find_large_value_params sizes generic instantiations', async (t) => {
+  const context = build_layout_context(layout_pkg);
+  const pair = compute_type_layout('Pair[Padded, [bufferSize]int64]', context);
+  t.assert.eq(pair.size, 88, 'Should size the type arguments');
+  t.assert.eq(pair.exact, true, 'Instantiated layout should be exact');
+  t.assert.eq(compute_type_layout('Pair', context).exact, false, 'Uninstantiated layout should be inexact');
+
+  const findings = find_large_value_params(layout_pkg);
+  const store = findings.find((f) => f.function === 'Store');
+  t.assert.ok(store, 'Should flag the large instantiation');
+  t.assert.eq(store.size, 88, 'Should report the instantiated size');
+  t.assert.ok(store.message.includes('Pair[Padded, [bufferSize]int64]'), 'Should name the instantiation');
+  t.assert.ok(!findings.some((f) => f.function === 'Flag'), 'Should not flag the small instantiation');
+});
+
+// ============ Field initialization tests ============
+
+const field_init_packages = group_go_packages([
+  parse_go_file(readFileSync('./tests/fixtures/field_init.go', 'utf-8'), 'client/client.go'),
+  parse_go_file(readFileSync('./tests/fixtures/field_init_caller.go', 'utf-8'), 'cmd/app/main.go')
+]);
+const field_init_types = new Map(
+  find_field_initializations(field_init_packages).map((t) => [t.name, t])
+);
+const get_field = (type, name) => field_init_types.get(type).fields.find((f) => f.name === name);
+
+await test('get_typical_init uses the majority', async (t) => {
+  t.assert.eq(get_typical_init(2, 1), 'constructor', 'Should prefer constructors');
+  t.assert.eq(get_typical_init(0, 3), 'caller', 'Should prefer callers');
+  t.assert.eq(get_typical_init(1, 1), 'mixed', 'Should report ties as mixed');
+  t.assert.eq(get_typical_init(0, 0), 'none', 'Should report unset fields');
+});
+
+await test('find_struct_literals finds keyed and positional literals', async (t) => {
+  const structs = new Map([['p:Point', { fields: [{ names: ['X', 'Y'] }] }]]);
+  const resolve = (name) => `p:${name}`;
+  const body = '{\n\ta := Point{X: 1}\n\tb := &Point{1, 2}\n\tif ok {\n\t}\n}';
+  const literals = find_struct_literals(body, 5, resolve, structs);
+  t.assert.eq(literals.length, 2, 'Should only find struct literals');
+  t.assert.eq(literals[0].fields.join(','), 'X', 'Should report keyed fields');
+  t.assert.eq(literals[1].fields.join(','), 'X,Y', 'Positional literals set every field');
+  t.assert.eq(literals[1].line, 7, 'Should record the line');
+});
+
+await test('find_field_initializations attributes fields to constructors', async (t) => {
+  t.assert.eq(field_init_types.get('Client').constructors.join(','), 'NewClient', 'Should find the constructor');
+  t.assert.eq(get_field('Client', 'Addr').typical_init, 'constructor', 'Addr is set by NewClient');
+  t.assert.eq(get_field('Client', 'Retries').by_caller, 0, 'Decrements in methods are not counted');
+  t.assert.ok(get_field('Client', 'Addr').suggestion.includes('getter'), 'Should suggest a getter');
+  t.assert.ok(!field_init_types.get('Client').fields.some((f) => f.name === 'conn'), 'Should skip unexported fields');
+});
+
+await test('find_field_initializations follows callers across packages', async (t) => {
+  const timeout = get_field('Client', 'Timeout');
+  t.assert.eq(timeout.typical_init, 'mixed', 'Timeout is set by NewClient and main');
+  t.assert.eq(get_field('Client', 'Debug').typical_init, 'caller', 'Method writes are not counted');
+  t.assert.ok(get_field('Client', 'Debug').suggestion.includes('setter'), 'Should suggest a setter');
+  t.assert.eq(get_field('Options', 'Verbose').literals, 1, 'Should count qualified literals');
+  t.assert.eq(get_field('Options', 'Limit').typical_init, 'none', 'Limit is never set');
+});
+
+// ============ classify_structs tests ============
+
+const struct_kinds = classify_structs([load_fixture('struct_kinds.go')]);
+const kind_of = (name) => struct_kinds.find((s) => s.name === name);
+
+await test('classify_method_role recognises accessors', async (t) => {
+  const user = kind_of('User');
+  const role = (name) => user.methods.find((m) => m.name === name).role;
+  t.assert.eq(role('Role'), 'getter', 'Returning a field is a getter');
+  t.assert.eq(role('SetRole'), 'setter', 'Assigning a parameter is a setter');
+  t.assert.eq(role('String'), 'formatting', 'String is formatting');
+  t.assert.eq(
+    classify_method_role({
+      name: 'Reset',
+      receiver: { name: 'c', type: 'Counter' },
+      params: [],
+      results: [],
+      body: '{\n\tc.count = 0\n}'
+    }),
+    'behavior',
+    'Assigning a constant is behavior'
+  );
+});
+
+await test('classify_structs separates data from behavior', async (t) => {
+  t.assert.eq(kind_of('Point').kind, 'data', 'No methods is data');
+  t.assert.eq(kind_of('User').kind, 'data', 'Only accessors is data');
+  t.assert.eq(kind_of('Counter').kind, 'service', 'Hidden state with behavior is a service');
+  t.assert.eq(kind_of('Calculator').kind, 'service', 'Behavior outweighing fields is a service');
+  t.assert.eq(kind_of('Config').kind, 'mixed', 'Mostly fields with some behavior is mixed');
+  t.assert.eq(kind_of('Counter').behavior_methods, 2, 'Should count behavior methods');
+  t.assert.eq(kind_of('Counter').accessor_methods, 1, 'Should count accessors');
+  t.assert.eq(kind_of('Config').exported_fields, 4, 'Should count exported fields');
+});
+
+// ============ find_method_shadowing tests ============
+
+const shadowing = find_method_shadowing([load_fixture('shadowing.go')]);
+const shadow_of = (type, name) =>
+  shadowing.find((s) => s.type === type && s.name === name);
+
+await test('find_method_shadowing reports overrides of promoted methods', async (t) => {
+  const name = shadow_of('Employee', 'Name');
+  t.assert.eq(name.kind, 'override', 'Same signature is an override');
+  t.assert.eq(name.embedded, 'User', 'Should name the embedded type');
+  t.assert.ok(name.delegates, 'Employee.Name calls e.User.Name()');
+  t.assert.ok(name.intentional, 'Overrides are intentional');
+  t.assert.ok(!shadow_of('Employee', 'Email').delegates, 'Email does not call User.Email');
+  t.assert.ok(!shadowing.some((s) => s.type === 'Manager'), 'Manager only inherits methods');
+});
+
+await test('find_method_shadowing separates accidental collisions', async (t) => {
+  const name = shadow_of('Contractor', 'Name');
+  t.assert.eq(name.kind, 'collision', 'Different signature is a collision');
+  t.assert.ok(!name.intentional, 'Undocumented collision is accidental');
+  t.assert.eq(name.hidden_interfaces.join(','), 'Named', 'Contractor no longer implements Named');
+  t.assert.ok(shadow_of('Contractor', 'Email').intentional, 'Doc comment naming User is intentional');
+  const id = shadow_of('Guest', 'ID');
+  t.assert.eq(id.member, 'field', 'A field can shadow a promoted method');
+  t.assert.ok(!id.intentional, 'Field collisions are accidental');
+  t.assert.eq(shadowing.length, 5, 'Should find every shadowing member');
+});
+
+// ============ find_embedders tests ============
+
+const embedding_packages = group_go_packages([
+  parse_go_file(readFileSync('./tests/fixtures/shadowing.go', 'utf-8'), 'staff/staff.go'),
+  parse_go_file(readFileSync('./tests/fixtures/embedders.go', 'utf-8'), 'directory/directory.go')
+]);
+
+await test('find_embedders lists the public types embedding a type', async (t) => {
+  const result = find_embedders(embedding_packages, 'User');
+  t.assert.eq(result.type.directory, 'staff', 'Should resolve the embedded type');
+  t.assert.eq(
+    result.embedders.map((e) => e.name).join(','),
+    'Contractor,Employee,Guest',
+    'Should list direct exported embedders'
+  );
+  const unexported = find_embedders(embedding_packages, 'User', { include_unexported: true });
+  const record = unexported.embedders.find((e) => e.name === 'record');
+  t.assert.eq(record.directory, 'directory', 'Should follow imports of project packages');
+});
+
+await test('find_embedders reports transitive embedding paths', async (t) => {
+  const result = find_embedders(embedding_packages, 'staff.User', { transitive: true });
+  const manager = result.embedders.find((e) => e.name === 'Manager');
+  t.assert.eq(manager.depth, 2, 'Manager embeds User through Employee');
+  t.assert.eq(manager.via, 'Employee', 'Should name the intermediate type');
+  t.assert.eq(manager.path.join(' > '), 'Manager > Employee > User', 'Should report the path');
+  const entry = result.embedders.find((e) => e.name === 'Entry');
+  t.assert.eq(entry.path[0], 'directory.Entry', 'Should qualify types of other packages');
+  t.assert.ok(!find_embedders(embedding_packages, 'User').embedders.some((e) => e.depth > 1), 'Direct by default');
+});
+
+await test('find_embedders handles interface embedding', async (t) => {
+  const result = find_embedders(embedding_packages, 'Named');
+  t.assert.eq(result.embedders.length, 1, 'Only Lister embeds Named');
+  t.assert.eq(result.embedders[0].kind, 'interface', 'Lister is an interface');
+
+  let message = '';
+  try {
+    find_embedders(embedding_packages, 'Missing');
+  } catch (error) {
+    message = error.message;
+  }
+  t.assert.ok(message.includes('not found'), 'Should reject unknown types');
+});
+
+await test('find_embedders names the candidates of an ambiguous type', async (t) => {
+  const packages = group_go_packages([
+    parse_go_file('package store\n\ntype Config struct{}\n', 'store.go'),
+    parse_go_file('package cache\n\n\ntype Config struct{}\n', 'cache.go')
+  ]);
+  let message = '';
+  try {
+    find_embedders(packages, 'Config');
+  } catch (error) {
+    message = error.message;
+  }
+  t.assert.ok(message.includes('store.Config (store.go:3)'), 'Should name the package and file of each candidate');
+  t.assert.ok(message.includes('cache.Config (cache.go:4)'), 'Should list every candidate');
+  t.assert.ok(message.includes("such as 'cache.Config'") || message.includes("such as 'store.Config'"), 'Should say how to qualify the name');
+  t.assert.eq(find_embedders(packages, 'store.Config').type.package, 'store', 'Should accept the qualified name');
+});
+
+await test('find_source_embedders summarizes the embedders of sources', async (t) => {
+  const result = find_source_embedders(
+    [
+      { filename: 'staff/staff.go', source: readFileSync('./tests/fixtures/shadowing.go', 'utf-8') },
+      { filename: 'directory/directory.go', source: readFileSync('./tests/fixtures/embedders.go', 'utf-8') }
+    ],
+    'User',
+    { transitive: true }
+  );
+  t.assert.eq(result.summary.total_embedders, result.embedders.length, 'Should count every embedder');
+  t.assert.eq(result.summary.direct, 3, 'Contractor, Employee and Guest embed User directly');
+  t.assert.ok(result.summary.transitive > 0, 'Should count transitive embedders');
+});
+
+// ============ Struct tag tests ============
+
+const tags_pkg = load_fixture('struct_tags.go');
+
+await test('parse_struct_tag reads key and value pairs', async (t) => {
+  const { tags, error } = parse_struct_tag('json:"id,omitempty" db:"user_id"');
+  t.assert.eq(error, null, 'Should parse well formed tags');
+  t.assert.eq(tags.get('json').name, 'id', 'Should split the name');
+  t.assert.eq(tags.get('json').options.join(','), 'omitempty', 'Should split the options');
+  t.assert.eq(tags.get('db').value, 'user_id', 'Should read every key');
+  t.assert.ok(parse_struct_tag('json: "id"').error, 'Should reject a space after the colon');
+});
+
+await test('validate_struct_tags reports fields breaking the default policy', async (t) => {
+  const diagnostics = validate_struct_tags([tags_pkg]);
+  t.assert.ok(!diagnostics.some((d) => d.type === 'User'), 'User complies');
+  t.assert.ok(!diagnostics.some((d) => d.type === 'Cache'), 'Untagged structs are skipped');
+  t.assert.eq(
+    diagnostics.map((d) => `${d.field}:${d.rule}`).join(' '),
+    'Currency:required CreatedAt:naming UpdatedAt:naming Notes:syntax',
+    'Should report missing, misnamed and malformed tags'
+  );
+  t.assert.ok(diagnostics[2].message.includes('field name UpdatedAt'), 'Unnamed tags use the field name');
+  t.assert.ok(!diagnostics.some((d) => d.field === 'Secret'), 'Ignored fields comply');
+});
+
+await test('validate_struct_tags applies custom policies', async (t) => {
+  const diagnostics = validate_struct_tags([tags_pkg], {
+    required: ['json', 'db'],
+    naming: { db: 'snake_case' },
+    include_embedded: true,
+    include_unexported: true
+  });
+  const fields = new Set(diagnostics.map((d) => d.field));
+  t.assert.ok(fields.has('Base') && fields.has('internal'), 'Should include embedded and unexported fields');
+  t.assert.ok(!diagnostics.some((d) => d.type === 'User'), 'User has every tag');
+  t.assert.ok(
+    diagnostics.some((d) => d.field === 'Total' && d.key === 'db' && d.rule === 'required'),
+    'Should require every key'
+  );
+
+  let message = '';
+  try {
+    validate_struct_tags([tags_pkg], { naming: { json: 'snake' } });
+  } catch (error) {
+    message = error.message;
+  }
+  t.assert.ok(message.includes("Unknown naming rule 'snake'"), 'Should reject unknown rules');
+});
+
+// ============ Type complexity tests ============
+
+const complexity_pkg = load_fixture('type_complexity.go');
+
+await test('find_type_complexity ranks types by total method complexity', async (t) => {
+  const types = find_type_complexity([complexity_pkg]);
+  t.assert.eq(
+    types.map((type) => `${type.name}:${type.total_complexity}`).join(' '),
+    'Engine:16 Base:7 Counter:1',
+    'Should rank the types, leaving out types without methods'
+  );
+  const [engine] = types;
+  t.assert.eq(engine.method_count, 4, 'Should count the declared methods');
+  t.assert.eq(engine.methods[0].name, 'Run', 'Should list the most complex method first');
+  t.assert.eq(engine.max_complexity, 6, 'Should give the maximum method complexity');
+  t.assert.eq(engine.average_complexity, 4, 'Should give the average method complexity');
+  t.assert.eq(engine.share, 66.7, 'Should give the share of the project complexity');
+});
+
+await test('find_type_complexity includes promoted methods when asked', async (t) => {
+  const engine = find_type_complexity([complexity_pkg], { include_promoted: true })[0];
+  t.assert.eq(engine.total_complexity, 19, 'Should add the promoted methods');
+  const configure = engine.methods.find((m) => m.name === 'Configure');
+  t.assert.eq(configure.promoted_from, 'Base', 'Should name the type declaring a promoted method');
+  const validate = engine.methods.filter((m) => m.name === 'Validate');
+  t.assert.eq(validate.length, 1, 'Should count a shadowed method once');
+  t.assert.eq(validate[0].promoted_from, null, 'Should keep the method shadowing a promoted one');
+});
+
+// ============ Copy lock tests ============
+
+const copy_locks = find_copy_locks([load_fixture('copy_locks.go')]);
+
+await test('find_copy_locks flags the types containing locks', async (t) => {
+  t.assert.eq(
+    copy_locks.no_copy_types.map((type) => `${type.name}:${type.path}:${type.lock}`).join(' '),
+    'noCopy::noCopy Cache:mu:sync.Mutex Pool:WaitGroup:sync.WaitGroup Stats:_:noCopy Shards:shards.mu:sync.Mutex',
+    'Should follow fields, embedded fields, markers and arrays'
+  );
+  t.assert.ok(!copy_locks.no_copy_types.some((type) => type.name === 'Ref'), 'Pointers and slices are safe to copy');
+});
+
+await test('find_copy_locks reports locks passed by value', async (t) => {
+  t.assert.eq(
+    copy_locks.findings.map((f) => `${f.function}:${f.kind}:${f.parameter}`).join(' '),
+    'Get:receiver:c Dump:parameter:c Dump:parameter:shards Wait:parameter:p',
+    'Should report value receivers and parameters, not pointers or variadics'
+  );
+  t.assert.eq(
+    copy_locks.findings[0].message,
+    "Receiver 'c' passes Cache by value, copying Cache.mu (sync.Mutex); pass a pointer instead",
+    'Should name the copied lock'
+  );
+
+  const aliased = find_copy_locks(group_go_packages([
+    parse_go_file(
+      'package a\n\nimport s "sync"\n\nfunc Run(m s.RWMutex) {}\n\nfunc Other(sync int) {}\n',
+      'a/a.go'
+    )
+  ]));
+  t.assert.eq(aliased.findings.map((f) => f.lock).join(','), 's.RWMutex', 'Should resolve aliased imports');
+});
+
+// ============ Field type tests ============
+
+const field_types_pkg = load_fixture('field_types.go');
+
+await test('find_field_types counts field types across structs', async (t) => {
+  const { histogram, types } = find_field_types([field_types_pkg]);
+  t.assert.eq(histogram.string, 4, 'Should count every field name, including struct literal fields');
+  t.assert.eq(histogram['time.Time'], 2, 'Should resolve aliases by default');
+  t.assert.eq(histogram.UserID, 2, 'Should keep defined types by default');
+  t.assert.eq(histogram['[]UserID'], 1, 'Should keep composite types');
+  t.assert.eq(histogram['struct{...}'], 1, 'Should count struct literals as one type');
+  t.assert.eq(histogram.int64, 3, 'Should count grouped field names');
+  t.assert.ok(!('Base' in histogram), 'Should leave out embedded fields');
+  t.assert.eq(types[0].type, 'string', 'Should sort by count');
+  t.assert.eq(types.find((type) => type.type === 'string').structs, 2, 'Should count the structs using a type');
+});
+
+await test('find_field_types resolves types and reports inconsistent fields', async (t) => {
+  const none = find_field_types([field_types_pkg], { resolution: 'none' }).histogram;
+  t.assert.eq(none.Timestamp, 1, 'Should keep aliases without resolution');
+
+  const underlying = find_field_types([field_types_pkg], { resolution: 'underlying' });
+  t.assert.eq(underlying.histogram.int64, 5, 'Should resolve defined types to their underlying type');
+  t.assert.eq(underlying.histogram['[]int64'], 1, 'Should resolve inside composite types');
+  t.assert.eq(underlying.histogram['*Order'], 1, 'Should keep struct types');
+
+  const [id] = find_field_types([field_types_pkg]).inconsistent_fields;
+  t.assert.eq(id.name, 'ID', 'Should report field names with different types');
+  t.assert.eq(JSON.stringify(id.types), '{"UserID":1,"int":1,"int64":1}', 'Should count the types of the field name');
+
+  const embedded = find_field_types([field_types_pkg], { include_embedded: true }).histogram;
+  t.assert.eq(embedded.Base, 1, 'Should count embedded fields when asked');
+
+  let message = '';
+  try {
+    find_field_types([field_types_pkg], { resolution: 'deep' });
+  } catch (error) {
+    message = error.message;
+  }
+  t.assert.ok(message.includes("Unknown type resolution 'deep'"), 'Should reject unknown resolutions');
+});
+
+// ============ Options struct tests ============
+
+await test('find_options_structs links functions to their options struct', async (t) => {
+  const result = find_options_structs([load_fixture('options_structs.go')]);
+  const functions = Object.fromEntries(result.functions.map((fn) => [fn.symbol, fn]));
+  t.assert.eq(
+    Object.keys(functions).sort().join(','),
+    'NewServer,Publish,Server.Dial',
+    'Should find the functions taking an options struct last'
+  );
+  t.assert.eq(functions.NewServer.options_param.struct, 'fixtures:Config', 'Should link the function to the struct');
+  t.assert.eq(functions.NewServer.options_param.position, 2, 'Should give the 1-based parameter position');
+  t.assert.eq(functions['Server.Dial'].options_param.pointer, true, 'Should accept pointers to options structs');
+  t.assert.eq(functions.Publish.options_param.reason, 'fields', 'Should detect plain data structs with many fields');
+  const config = result.structs.find((s) => s.name === 'Config');
+  t.assert.eq(config.options.map((o) => o.name).join(','), 'Addr,Timeout,MaxConns', 'Should list the options of the struct');
+  t.assert.eq(config.options[0].doc, 'Addr is the address to listen on.', 'Should give the doc of each option');
+  t.assert.eq(config.options[2].doc, 'zero means unlimited', 'Should fall back to the line comment');
+});
+
+await test('find_options_structs leaves other parameters', async (t) => {
+  const pkg = load_fixture('options_structs.go');
+  const symbols = find_options_structs([pkg]).functions.map((fn) => fn.symbol);
+  t.assert.ok(!symbols.includes('Config.Merge'), 'Should leave the methods of the options struct');
+  t.assert.ok(!symbols.includes('Move'), 'Should leave small structs');
+  t.assert.ok(!symbols.includes('Save'), 'Should leave structs with methods');
+  t.assert.ok(!symbols.includes('Start'), 'Should leave functional options');
+  t.assert.ok(!symbols.includes('Configure'), 'Should only check the last parameter');
+  const loose = find_options_structs([pkg], { min_fields: 2 }).functions.map((fn) => fn.symbol);
+  t.assert.ok(loose.includes('Move'), 'Should use the minimum number of fields');
+});
+
+// ============ Recursive struct tests ============
+
+await test('find_recursive_structs detects self-referential structs', async (t) => {
+  const structs = Object.fromEntries(
+    find_recursive_structs([load_fixture('recursive_structs.go')]).map((s) => [s.name, s])
+  );
+  t.assert.eq(structs.Node.recursion, 'direct', 'Should detect a linked list');
+  t.assert.eq(structs.Node.self_fields.map((f) => `${f.name}:${f.via}`).join(','), 'Next:pointer', 'Should give the referencing fields');
+  t.assert.eq(structs.Tree.self_fields.map((f) => `${f.name}:${f.via}`).join(','), 'Children:slice,Index:map', 'Should follow slices and maps of generic types');
+  t.assert.eq(structs.Graph.recursion, 'direct', 'Should detect directly recursive structs referencing other structs');
+  t.assert.eq(structs.Point.recursive, false, 'Should not flag plain structs');
+  t.assert.eq(structs.Shape.recursive, false, 'Should not flag structs referencing other structs');
+});
+
+await test('find_recursive_structs detects mutually recursive structs', async (t) => {
+  const structs = Object.fromEntries(
+    find_recursive_structs([load_fixture('recursive_structs.go')]).map((s) => [s.name, s])
+  );
+  t.assert.eq(structs.Dir.recursion, 'mutual', 'Should detect mutual recursion');
+  t.assert.eq(structs.Dir.group.join(','), 'fixtures:File,fixtures:User', 'Should give the other structs of the group');
+  t.assert.eq(
+    structs.Dir.group_fields.map((f) => `${f.name}>${f.targets.join('+')}`).join(','),
+    'Files>fixtures:File,Meta>fixtures:User',
+    'Should give the fields referencing the group, through struct literals too'
+  );
+  t.assert.eq(structs.File.recursion, 'mutual', 'Should flag every struct of the group');
+  t.assert.eq(structs.Node.group.length, 0, 'Should not group directly recursive structs alone');
+  const recursive = find_recursive_structs([load_fixture('recursive_structs.go')], { recursive_only: true });
+  t.assert.eq(recursive.map((s) => s.name).join(','), 'Dir,File,Graph,Node,Tree,User', 'Should only list recursive structs with recursive_only');
+});
+
+// ============ Array size tests ============
+
+const array_pkg = load_fixture('array_sizes.go');
+
+await test('compute_struct_layouts evaluates constant array lengths', async (t) => {
+  const keyed = compute_struct_layouts(array_pkg).find((l) => l.name === 'Keyed');
+  t.assert.eq(keyed.size, 96, 'Should size arrays of constant length');
+  t.assert.eq(keyed.exact, true, 'Should be exact');
+  t.assert.eq(keyed.fields[0].length, 32, 'Should expose the array length');
+  t.assert.eq(keyed.fields[1].length, 64, 'Should evaluate constant expressions');
+});
+
+await test('compute_struct_layouts counts implicit-length arrays', async (t) => {
+  const counted = compute_struct_layouts(array_pkg).find((l) => l.name === 'Counted');
+  const lengths = counted.fields.map((f) => f.length);
+  t.assert.eq(lengths.join(','), '5,6,3', 'Should count the elements of [...]T literals');
+  t.assert.eq(counted.fields[2].offset, 16, 'Should place fields after the counted arrays');
+  t.assert.eq(counted.size, 40, 'Should size the struct');
+  t.assert.eq(counted.exact, true, 'Should be exact');
+});
+
+await test('compute_struct_layouts keeps unresolved array sizes symbolic', async (t) => {
+  const remote = compute_struct_layouts(array_pkg).find((l) => l.name === 'Remote');
+  const buffer = remote.fields[1];
+  t.assert.eq(buffer.length, null, 'Should not resolve constants of other packages');
+  t.assert.eq(buffer.symbolic_size, 'limits.MaxSize*4', 'Should give a symbolic size');
+  t.assert.eq(remote.exact, false, 'Should be inexact');
+  const context = build_layout_context(array_pkg);
+  t.assert.eq(
+    compute_type_layout('[n + 1]int64', context).symbolic_size,
+    '(n + 1)*8',
+    'Should parenthesize expressions'
+  );
+});
@@ -0,0 +1,96 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go interface stub generation functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  parse_go_file,
+  group_go_packages
+} from '../../../lib/analysis/golang.mjs';
+import {
+  generate_interface_stub,
+  name_stub_params,
+  get_zero_value
+} from '../../../lib/analysis/stubs.mjs';
+
+const [classes_pkg] = group_go_packages([
+  parse_go_file(
+    readFileSync('./tests/fixtures/classes_structs.go', 'utf-8'),
+    'classes_structs.go'
+  )
+]);
+
+const [kv_pkg] = group_go_packages([
+  parse_go_file(readFileSync('./tests/fixtures/stubs.go', 'utf-8'), 'kv/kv.go')
+]);
+
+// ============ name_stub_params tests ============
+
+await test('name_stub_params keeps names and synthesizes missing ones', async (t) => {
+  const named = name_stub_params([{ name: 'p', type: '[]byte' }], 'arg', new Set());
+  t.assert.eq(named[0].name, 'p', 'Should keep the interface name');
+  t.assert.ok(!named[0].synthesized, 'Should not be synthesized');
+
+  const unnamed = name_stub_params(
+    [{ name: null, type: 'string' }, { name: null, type: 'int' }],
+    'arg',
+    new Set(['arg0'])
+  );
+  t.assert.eq(unnamed.map((p) => p.name).join(','), 'arg1,arg2', 'Should skip taken names');
+  t.assert.ok(unnamed[0].synthesized, 'Should be synthesized');
+});
+
+// ============ get_zero_value tests ============
+
+await test('get_zero_value resolves local and predeclared types', async (t) => {
+  const types = new Map(kv_pkg.types.map((type) => [type.name, type]));
+  t.assert.eq(get_zero_value('error', types), 'nil', 'error');
+  t.assert.eq(get_zero_value('float64', types), '0', 'Numbers');
+  t.assert.eq(get_zero_value('string', types), '""', 'Strings');
+  t.assert.eq(get_zero_value('[]byte', types), 'nil', 'Slices');
+  t.assert.eq(get_zero_value('Entry', types), 'Entry{}', 'Local structs');
+  t.assert.eq(get_zero_value('Count', types), 'Count(0)', 'Named numbers');
+  t.assert.eq(get_zero_value('Keys', types), 'Keys(nil)', 'Named slices');
+  t.assert.eq(get_zero_value('Getter', types), 'nil', 'Interfaces');
+  t.assert.eq(get_zero_value('time.Time', types), '*new(time.Time)', 'Unknown types');
+});
+
+// ============ generate_interface_stub tests ============
+
+await test('generate_interface_stub preserves parameter and result names', async (t) => {
+  const stub = generate_interface_stub(classes_pkg, 'Reader');
+  t.assert.eq(stub.type_name, 'StubReader', 'Should name the stub');
+  t.assert.ok(
+    stub.source.includes('func (s *StubReader) Read(p []byte) (n int, err error) {\n\treturn\n}'),
+    'Should render Read with p, n and err'
+  );
+});
+
+await test('generate_interface_stub follows embedded interfaces', async (t) => {
+  const stub = generate_interface_stub(classes_pkg, 'ReadWriter');
+  t.assert.eq(stub.methods.map((m) => m.name).join(','), 'Read,Write', 'Should include embedded methods');
+
+  const store = generate_interface_stub(kv_pkg, 'Store', { type_name: 'fakeStore' });
+  t.assert.ok(store.methods.some((m) => m.name === 'Get'), 'Should include local embeds');
+  t.assert.eq(store.unresolved_embeds.join(','), 'io.Closer', 'Should report unresolved embeds');
+  t.assert.ok(store.source.includes('TODO: implement the methods of io.Closer'), 'Should note them');
+});
+
+await test('generate_interface_stub synthesizes names only when omitted', async (t) => {
+  const stub = generate_interface_stub(kv_pkg, 'Store', { type_name: 'fakeStore' });
+  const source = stub.source;
+  t.assert.ok(source.includes('func (f *fakeStore) Get(arg0 string) ([]byte, error) {\n\treturn nil, nil\n}'), 'Get');
+  t.assert.ok(source.includes('Put(key string, value []byte) error {\n\treturn nil\n}'), 'Put keeps names');
+  t.assert.ok(source.includes('Watch(arg0 context.Context, arg1 func(string)) <-chan Entry'), 'Watch');
+  t.assert.ok(source.includes('List(s string, limit int) (Keys, error) {\n\treturn Keys(nil), nil'), 'List');
+  t.assert.ok(source.includes('First() (Entry, bool) {\n\treturn Entry{}, false'), 'First');
+  t.assert.eq(generate_interface_stub(kv_pkg, 'Missing'), null, 'Missing interface');
+});
+
+await test('generate_interface_stub avoids receiver clashes', async (t) => {
+  const stub = generate_interface_stub(kv_pkg, 'Store', { type_name: 'sqlStore' });
+  t.assert.eq(stub.receiver, 'stub', 'Should not shadow parameter s');
+});
@@ -0,0 +1,101 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go public surface area functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import { build_source_catalog } from '../../../lib/analysis/catalog.mjs';
+import {
+  find_source_surface_area,
+  summarize_surface_area,
+  compare_surface_area,
+  compute_surface_area,
+  get_entry_weight
+} from '../../../lib/analysis/surface.mjs';
+
+const sources = [
+  {
+    filename: 'geo/shapes.go',
+    source: readFileSync('./tests/fixtures/near_miss.go', 'utf-8')
+  },
+  {
+    filename: 'set/set.go',
+    source:
+      'package set\n\n' +
+      'type Set[T comparable] struct {\n\titems map[T]bool\n\tN     int\n}\n\n' +
+      'func Map[T, U any](xs []T, f func(T) U) []U { return nil }\n\n' +
+      'func (s *Set[T]) Add(v ...T) (ok bool, err error) { return }\n\n' +
+      'func (s *Set[T]) grow() {}\n\n' +
+      'const A, B = 1, 2\n'
+  }
+];
+
+// ============ get_entry_weight tests ============
+
+await test('get_entry_weight weighs signatures', async (t) => {
+  const weight = (kind, signature) => get_entry_weight({ kind, signature });
+  t.assert.eq(
+    weight('function', 'func Map[T, U any](xs []T, f func(T) U) []U'),
+    6,
+    'Should count parameters, results and type parameters'
+  );
+  t.assert.eq(
+    weight('method', 'func (s *Set[T]) Add(v ...T) (ok bool, err error)'),
+    4,
+    'Should not count the receiver'
+  );
+  t.assert.eq(weight('method', 'Area() float64'), 2, 'Should weigh interface methods');
+  t.assert.eq(weight('struct', 'type Set[T comparable] struct'), 2, 'Should count type parameters of types');
+  t.assert.eq(weight('type', 'type Alias = int'), 1, 'Should weigh aliases');
+  t.assert.eq(weight('const', 'const A = 1'), 1, 'Should weigh constants');
+});
+
+// ============ summarize_surface_area tests ============
+
+await test('summarize_surface_area sums the exported API by kind and package', async (t) => {
+  const surface = find_source_surface_area(sources);
+  t.assert.eq(surface.surface_area, 47, 'Should sum the weights of the exported symbols');
+  t.assert.eq(surface.packages.set, 15, 'Should leave out unexported fields and methods');
+  t.assert.eq(surface.packages.geo, 32, 'Should sum the weights by package');
+  t.assert.eq(surface.by_kind.method.count, 11, 'Should count the entries by kind');
+  t.assert.eq(
+    compute_surface_area(build_source_catalog(sources, { generated_at: 'now' })),
+    surface.surface_area,
+    'Should compute the same single number from the catalog'
+  );
+  t.assert.eq(
+    JSON.stringify(find_source_surface_area([...sources].reverse())),
+    JSON.stringify(surface),
+    'Should not depend on the order of the files'
+  );
+});
+
+// ============ compare_surface_area tests ============
+
+await test('compare_surface_area alerts on rapid growth', async (t) => {
+  const baseline = summarize_surface_area(build_source_catalog(sources.slice(0, 1), { generated_at: '' }));
+  const current = find_source_surface_area(sources);
+  const comparison = compare_surface_area(current, baseline);
+  t.assert.eq(comparison.delta, 15, 'Should give the change of surface area');
+  t.assert.eq(comparison.growth, 46.9, 'Should give the growth in percent');
+  t.assert.eq(comparison.alert, true, 'Should alert over the maximum growth');
+  t.assert.eq(
+    comparison.packages.map((p) => `${p.directory}:${p.delta}`).join(','),
+    'set:15',
+    'Should list the packages whose surface area changed'
+  );
+  t.assert.eq(
+    compare_surface_area(current, baseline, { max_growth: 50 }).alert,
+    false,
+    'Should not alert under the maximum growth'
+  );
+  let error = null;
+  try {
+    compare_surface_area(current, { version: 0 });
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error, 'Should reject baselines of another version');
+});
@@ -0,0 +1,97 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go symbol range functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  build_symbol_ranges,
+  parse_symbol_ranges,
+  SYMBOL_RANGE_FORMAT
+} from '../../../lib/analysis/symbolranges.mjs';
+
+const source = readFileSync('./tests/fixtures/symbol_ranges.go', 'utf-8');
+const bytes = Buffer.from(source, 'utf8');
+const document = build_symbol_ranges([{ filename: 'geo/geo.go', source }]);
+const [file] = document.files;
+const find_symbol = (kind, name) =>
+  file.symbols.find((s) => s.kind === kind && s.name === name);
+const slice = (start, end) => bytes.subarray(start, end).toString('utf8');
+
+// ============ build_symbol_ranges tests ============
+
+await test('build_symbol_ranges describes the document', async (t) => {
+  t.assert.eq(document.format, SYMBOL_RANGE_FORMAT, 'Should name the format');
+  t.assert.eq(document.version, 1, 'Should version the format');
+  t.assert.eq(file.path, 'geo/geo.go', 'Should name the file');
+  t.assert.eq(file.byte_length, bytes.length, 'Should count bytes, not characters');
+  t.assert.ok(file.byte_length > source.length, 'Should use a multibyte fixture');
+});
+
+await test('build_symbol_ranges uses UTF-8 byte offsets', async (t) => {
+  const label = find_symbol('field', 'Label');
+  t.assert.eq(slice(label.start_byte, label.end_byte), 'Label string', 'Should range after multibyte text');
+  const distance = find_symbol('function', 'Distance');
+  t.assert.ok(slice(distance.start_byte, distance.end_byte).startsWith('func Distance('), 'Should start at func');
+  t.assert.ok(slice(distance.start_byte, distance.end_byte).endsWith('dy*dy)\n}'), 'Should end after the body');
+  t.assert.eq(distance.start_point.row, 37, 'Should use zero-based rows');
+  t.assert.eq(label.start_point.column, 1, 'Should use byte columns');
+});
+
+await test('build_symbol_ranges maps kinds to tree-sitter nodes', async (t) => {
+  const point = find_symbol('struct', 'Point');
+  t.assert.eq(point.node_type, 'type_spec', 'Should use the node type');
+  t.assert.eq(point.capture, 'definition.class', 'Should use the tags capture');
+  t.assert.eq(find_symbol('alias', 'ID').node_type, 'type_alias', 'Should find aliases');
+  t.assert.eq(find_symbol('method', 'Scale').node_type, 'method_declaration', 'Should find methods');
+  t.assert.eq(find_symbol('method', 'Scale').receiver, 'Point', 'Should name the receiver');
+  t.assert.eq(find_symbol('interface_method', 'Area').node_type, 'method_elem', 'Should find method elements');
+  t.assert.eq(find_symbol('import', 'math').name_end_byte - find_symbol('import', 'math').name_start_byte, 6, 'Should range the quoted path');
+});
+
+await test('build_symbol_ranges nests members in their type', async (t) => {
+  const point = find_symbol('struct', 'Point');
+  t.assert.eq(find_symbol('field', 'X').parent, point.id, 'Should nest fields');
+  t.assert.eq(find_symbol('field', 'fmt.Stringer').parent, point.id, 'Should nest embedded fields');
+  t.assert.eq(find_symbol('interface_method', 'Perimeter').parent, find_symbol('interface', 'Shape').id, 'Should nest interface methods');
+  t.assert.eq(find_symbol('method', 'Scale').parent, null, 'Should keep methods top-level');
+  const w = find_symbol('field', 'W');
+  const h = find_symbol('field', 'H');
+  t.assert.eq(slice(w.start_byte, w.end_byte), 'W int', 'Should split fields on one line');
+  t.assert.eq(slice(h.start_byte, h.end_byte), 'H int', 'Should end fields before the brace');
+});
+
+// ============ parse_symbol_ranges tests ============
+
+await test('parse_symbol_ranges round-trips a document', async (t) => {
+  const parsed = parse_symbol_ranges(JSON.stringify(document));
+  t.assert.eq(JSON.stringify(parsed), JSON.stringify(document), 'Should read back the same document');
+  for (const symbol of parsed.files[0].symbols) {
+    const name = slice(symbol.name_start_byte, symbol.name_end_byte);
+    const expected = symbol.kind === 'import' ? `"${symbol.name}"` : symbol.name;
+    t.assert.eq(name, expected, `Should range the name of ${symbol.name}`);
+  }
+});
+
+await test('parse_symbol_ranges rejects invalid documents', async (t) => {
+  const rejects = (input) => {
+    try {
+      parse_symbol_ranges(input);
+      return false;
+    } catch (error) {
+      return true;
+    }
+  };
+  const copy = () => JSON.parse(JSON.stringify(document));
+  t.assert.ok(rejects({ format: 'other' }), 'Should reject other formats');
+  t.assert.ok(rejects({ ...copy(), version: 2 }), 'Should reject other versions');
+  const outside = copy();
+  outside.files[0].symbols[0].end_byte = outside.files[0].byte_length + 1;
+  t.assert.ok(rejects(outside), 'Should reject ranges out of the file');
+  const orphan = copy();
+  const field = orphan.files[0].symbols.find((s) => s.name === 'Label');
+  field.start_byte = 0;
+  t.assert.ok(rejects(orphan), 'Should reject children out of their parent');
+});
@@ -0,0 +1,65 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go workspace symbol functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  format_workspace_symbols,
+  score_fuzzy_match,
+  SYMBOL_KINDS
+} from '../../../lib/analysis/symbols.mjs';
+
+const sources = [
+  { filename: 'client/client.go', source: readFileSync('./tests/fixtures/field_init.go', 'utf-8') },
+  { filename: 'shapes/shapes.go', source: readFileSync('./tests/fixtures/graph.go', 'utf-8') }
+];
+
+// ============ score_fuzzy_match tests ============
+
+await test('score_fuzzy_match matches subsequences ignoring case', async (t) => {
+  t.assert.ok(score_fuzzy_match('nc', 'NewClient') !== null, 'Should match word starts');
+  t.assert.ok(score_fuzzy_match('BC', 'xbcB') !== null, 'Should fall back to the first occurrences');
+  t.assert.eq(score_fuzzy_match('nc', 'conn'), null, 'Should require the characters in order');
+  t.assert.eq(score_fuzzy_match('', 'Anything'), 0, 'Empty queries match everything');
+});
+
+await test('score_fuzzy_match ranks exact and prefix matches first', async (t) => {
+  const exact = score_fuzzy_match('client', 'Client');
+  const prefix = score_fuzzy_match('client', 'ClientOptions');
+  const scattered = score_fuzzy_match('client', 'NewConnectionList');
+  t.assert.ok(exact > prefix, 'Exact matches should beat prefixes');
+  t.assert.ok(prefix > scattered, 'Prefixes should beat scattered matches');
+});
+
+// ============ format_workspace_symbols tests ============
+
+await test('format_workspace_symbols uses the LSP shape', async (t) => {
+  const [symbol] = format_workspace_symbols(sources, { query: 'NewClient', root: '/src/app' });
+  t.assert.eq(symbol.name, 'NewClient', 'Should find the function');
+  t.assert.eq(symbol.kind, SYMBOL_KINDS.function, 'Should use the Function kind');
+  t.assert.eq(symbol.containerName, 'client', 'Should use the package as container');
+  t.assert.eq(symbol.location.uri, 'file:///src/app/client/client.go', 'Should build a file URI');
+  t.assert.eq(symbol.location.range.start.line, 22, 'Lines should be zero based');
+  t.assert.eq(symbol.location.range.start.character, 5, 'Should point at the name');
+  t.assert.eq(symbol.location.range.end.character, 14, 'Should span the name');
+});
+
+await test('format_workspace_symbols uses receivers as containers', async (t) => {
+  const symbols = format_workspace_symbols(sources, { query: 'Client.Do' });
+  t.assert.eq(symbols.length, 1, 'Qualified queries should match the container');
+  t.assert.eq(symbols[0].kind, SYMBOL_KINDS.method, 'Should use the Method kind');
+  t.assert.eq(symbols[0].containerName, 'Client', 'Should use the receiver type');
+  t.assert.eq(symbols[0].location.uri, 'client/client.go', 'Should keep relative paths without a root');
+});
+
+await test('format_workspace_symbols lists types and fields across packages', async (t) => {
+  const symbols = format_workspace_symbols(sources, { limit: 1000 });
+  const kinds = new Map(symbols.map((s) => [`${s.containerName}.${s.name}`, s.kind]));
+  t.assert.eq(kinds.get('client.Client'), SYMBOL_KINDS.struct, 'Should list structs');
+  t.assert.eq(kinds.get('Client.Timeout'), SYMBOL_KINDS.field, 'Should list fields');
+  t.assert.ok(symbols.some((s) => s.location.uri === 'shapes/shapes.go'), 'Should include every package');
+  t.assert.eq(format_workspace_symbols(sources, { limit: 3 }).length, 3, 'Should apply the limit');
+});
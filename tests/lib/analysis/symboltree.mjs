@@ -0,0 +1,87 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go symbol tree functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  build_symbol_tree,
+  format_symbol_tree
+} from '../../../lib/analysis/symboltree.mjs';
+
+const sources = [
+  {
+    filename: 'repo/container/container.go',
+    source: readFileSync('./tests/fixtures/state_aliasing.go', 'utf-8')
+  },
+  {
+    filename: 'repo/calc/calc.go',
+    source: readFileSync('./tests/fixtures/param_names.go', 'utf-8')
+  },
+  {
+    filename: 'repo/main.go',
+    source: `package main
+
+const Version = "1"
+
+var debug, _ = false, 1
+
+func (c *Other) Run() {}
+
+func main() {}
+`
+  }
+];
+
+const tree = build_symbol_tree(sources);
+const find_child = (node, name) => node.children.find((c) => c.name === name);
+
+// ============ build_symbol_tree tests ============
+
+await test('build_symbol_tree nests directories and files', async (t) => {
+  t.assert.eq(tree.name, 'repo', 'Should root the tree at the common directory');
+  t.assert.eq(tree.children.map((c) => c.name).join(','), 'calc,container,main.go', 'Should list directories before files');
+  t.assert.eq(tree.counts.files, 3, 'Should count files');
+  t.assert.eq(tree.counts.symbols, 32, 'Should count symbols');
+  t.assert.eq(find_child(tree, 'calc').path, 'repo/calc', 'Should give directory paths');
+});
+
+await test('build_symbol_tree lists the symbols of files', async (t) => {
+  const file = find_child(find_child(tree, 'container'), 'container.go');
+  t.assert.eq(file.counts.exported, 14, 'Should count exported symbols');
+  t.assert.eq(file.counts.unexported, 1, 'Should count unexported symbols');
+  const container = file.symbols.find((s) => s.name === 'Container');
+  t.assert.eq(container.kind, 'struct', 'Should give the kind of types');
+  t.assert.eq(container.children.length, 13, 'Should nest methods under their type');
+  const main = find_child(tree, 'main.go');
+  t.assert.eq(main.symbols.map((s) => s.name).join(','), 'Version,debug,Other.Run,main', 'Should order symbols by line and skip blank names');
+  t.assert.eq(main.symbols[2].kind, 'method', 'Should list methods away from their type');
+});
+
+// ============ format_symbol_tree tests ============
+
+await test('format_symbol_tree renders the tree', async (t) => {
+  const lines = format_symbol_tree(tree);
+  t.assert.eq(lines[0], 'repo/ (3 files, 32 symbols: 27 exported, 5 unexported)', 'Should describe the root');
+  t.assert.ok(lines.includes('│       ├── struct Calculator [10]'), 'Should connect symbols');
+  t.assert.ok(lines.includes('    └── func main [9]'), 'Should end branches');
+});
+
+await test('format_symbol_tree limits depth', async (t) => {
+  const lines = format_symbol_tree(tree, { depth: 2 });
+  t.assert.ok(lines.includes('│   └── calc.go (13 symbols: 11 exported, 2 unexported)'), 'Should show the levels within the depth');
+  t.assert.ok(!lines.some((l) => l.includes('Calculator')), 'Should collapse deeper levels');
+  const shallow = format_symbol_tree(tree, { depth: 3 });
+  t.assert.ok(shallow.includes('│       ├── struct Calculator [10] (2 methods)'), 'Should count collapsed methods');
+});
+
+await test('format_symbol_tree summarizes large trees', async (t) => {
+  const lines = format_symbol_tree(tree, { max_symbols: 5 });
+  t.assert.ok(!lines.some((l) => l.includes('func main')), 'Should leave symbols out');
+  t.assert.eq(lines[lines.length - 1], '32 symbols over 5, showing files only', 'Should say the tree is summarized');
+  t.assert.eq(format_symbol_tree(tree, { max_symbols: 0 }).length, 38, 'Should list every symbol without a limit');
+  const summary = format_symbol_tree(tree, { summary: true });
+  t.assert.eq(summary.length, lines.length - 2, 'Should summarize on request without a note');
+});
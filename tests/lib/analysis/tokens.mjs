@@ -0,0 +1,87 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for symbol token cost functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  summarize_token_costs,
+  attach_token_cost,
+  create_token_counter,
+  register_tokenizer,
+  list_tokenizers
+} from '../../../lib/analysis/tokens.mjs';
+
+const field_init_source = {
+  filename: 'client/client.go',
+  source: readFileSync('./tests/fixtures/field_init.go', 'utf-8')
+};
+
+// ============ create_token_counter tests ============
+
+await test('create_token_counter uses the named tokenizer', async (t) => {
+  t.assert.eq(create_token_counter('chars')('abcdefgh'), 2, 'Four characters per token');
+  t.assert.eq(create_token_counter('whitespace')('a b  c'), 3, 'One token per word');
+  t.assert.eq(create_token_counter('code')('NewClient(opts)'), 5, 'Words and punctuation');
+  t.assert.eq(create_token_counter().tokenizer, 'code', 'Should default to code');
+});
+
+await test('create_token_counter rejects unknown tokenizers', async (t) => {
+  let error = null;
+  try {
+    create_token_counter('nope');
+  } catch (e) {
+    error = e;
+  }
+  t.assert.ok(error && /Unknown tokenizer 'nope'/.test(error.message), 'Should throw');
+});
+
+await test('register_tokenizer adds a pluggable tokenizer', async (t) => {
+  register_tokenizer('lines', (text) => text.split('\n').length);
+  t.assert.ok(list_tokenizers().includes('lines'), 'Should list it');
+  t.assert.eq(create_token_counter('lines')('a\nb'), 2, 'Should use it');
+});
+
+// ============ attach_token_cost tests ============
+
+await test('attach_token_cost computes lazily and caches', async (t) => {
+  let calls = 0;
+  register_tokenizer('counting', (text) => {
+    calls++;
+    return text.length;
+  });
+  const symbol = attach_token_cost(
+    { signature_text: 'func F()', text: '// F.\nfunc F() {}' },
+    create_token_counter('counting')
+  );
+  t.assert.eq(calls, 0, 'Should not count before access');
+  t.assert.eq(symbol.token_cost.signature, 8, 'Signature tokens');
+  t.assert.eq(symbol.token_cost.total, 17, 'Total tokens');
+  t.assert.eq(symbol.token_cost.body, 9, 'Body tokens');
+  t.assert.eq(calls, 2, 'Should count once per text');
+});
+
+// ============ summarize_token_costs tests ============
+
+await test('summarize_token_costs annotates symbols and sums packages', async (t) => {
+  const result = summarize_token_costs([field_init_source], {
+    tokenizer: 'chars'
+  });
+  t.assert.eq(result.tokenizer, 'chars', 'Should report the tokenizer');
+  const [pkg] = result.packages;
+  const new_client = pkg.symbols.find((s) => s.name === 'NewClient');
+  t.assert.eq(new_client.kind, 'function', 'Should list functions');
+  t.assert.ok(new_client.token_cost.body > 0, 'Should count the body');
+  t.assert.ok(
+    new_client.token_cost.signature < new_client.token_cost.total,
+    'Signature should cost less than the declaration'
+  );
+  t.assert.ok(pkg.symbols.some((s) => s.name === 'Client.Do'), 'Should list methods');
+  t.assert.eq(
+    pkg.total_tokens,
+    pkg.symbols.reduce((sum, s) => sum + s.token_cost.total, 0),
+    'Should sum package totals'
+  );
+});
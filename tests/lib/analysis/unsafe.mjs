@@ -0,0 +1,94 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for Go unsafe usage functions.
+ */
+
+import { test } from 'st';
+import { readFileSync } from 'fs';
+import {
+  find_unsafe_usage,
+  find_file_unsafe_usage,
+  find_linkname_directives
+} from '../../../lib/analysis/unsafe.mjs';
+
+const source = readFileSync('./tests/fixtures/unsafe.go', 'utf-8');
+
+// ============ find_linkname_directives tests ============
+
+await test('find_linkname_directives finds directives with targets', async (t) => {
+  const directives = find_linkname_directives(
+    '//go:linkname nanotime runtime.nanotime\n//go:linkname exported\n'
+  );
+
+  t.assert.eq(directives.length, 2, 'two directives');
+  t.assert.eq(directives[0].target, 'runtime.nanotime', 'target');
+  t.assert.eq(directives[1].target, null, 'no target');
+});
+
+// ============ find_file_unsafe_usage tests ============
+
+await test('find_file_unsafe_usage flags files and functions', async (t) => {
+  const file = find_file_unsafe_usage(source, 'buffers/buffers.go');
+  const kinds_of = (name) => file.functions.find((fn) => fn.name === name);
+
+  t.assert.eq(
+    file.flags,
+    { unsafe: true, reflect: true, linkname: true, assembly: true },
+    'file flags'
+  );
+  t.assert.eq(kinds_of('BytesToString').kinds, ['unsafe'], 'aliased unsafe');
+  t.assert.eq(kinds_of('HeaderSize').kinds, ['unsafe', 'reflect'], 'both');
+  t.assert.eq(kinds_of('nanotime').kinds, ['linkname'], 'linkname, not assembly');
+  t.assert.eq(kinds_of('memclr').kinds, ['unsafe', 'assembly'], 'assembly');
+  t.assert.ok(!kinds_of('Options.Allowed'), 'unsafe variable and field');
+});
+
+await test('find_file_unsafe_usage reports sites with severity', async (t) => {
+  const file = find_file_unsafe_usage(source, 'buffers/buffers.go');
+  const site = (name) => file.sites.find((s) => s.name === name);
+
+  t.assert.eq(site('unsafe.Pointer').severity, 'high', 'Pointer');
+  t.assert.eq(site('unsafe.Pointer').line, 15, 'Pointer line');
+  t.assert.eq(site('unsafe.Sizeof').severity, 'low', 'layout function');
+  t.assert.eq(site('reflect.SliceHeader').severity, 'high', 'raw header');
+  t.assert.eq(site('reflect.ValueOf').severity, 'low', 'reflection');
+  t.assert.eq(site('UnsafeAddr').function, 'Address', 'unsafe method');
+  t.assert.eq(site('unsafe').site, 'import', 'import site');
+});
+
+await test('find_file_unsafe_usage ignores unsafe identifiers', async (t) => {
+  const file = find_file_unsafe_usage(
+    'package a\n\ntype T struct{ unsafe struct{ Pointer int } }\n\nfunc (t T) P() int {\n\treturn t.unsafe.Pointer\n}\n',
+    'a/a.go'
+  );
+
+  t.assert.eq(file.sites, [], 'no unsafe import');
+});
+
+// ============ find_unsafe_usage tests ============
+
+await test('find_unsafe_usage filters kinds', async (t) => {
+  const files = find_unsafe_usage(
+    [
+      { filename: 'buffers/buffers.go', source },
+      { filename: 'safe/safe.go', source: 'package safe\n\nfunc F() {}\n' }
+    ],
+    { kinds: ['linkname'] }
+  );
+
+  t.assert.eq(files.length, 1, 'files without usage are left out');
+  t.assert.eq(files[0].sites.map((s) => s.name), ['nanotime'], 'linkname');
+  t.assert.eq(files[0].functions.map((fn) => fn.name), ['nanotime'], 'function');
+});
+
+await test('find_unsafe_usage rejects unknown kinds', async (t) => {
+  let error = null;
+  try {
+    find_unsafe_usage([], { kinds: ['cgo'] });
+  } catch (e) {
+    error = e;
+  }
+
+  t.assert.ok(error && error.message.includes('cgo'), 'unknown kind');
+});
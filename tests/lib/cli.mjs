@@ -0,0 +1,99 @@
+'use strict';
+
+/**
+ * @fileoverview Tests for the exit status of the cb command line.
+ */
+
+import { test } from 'st';
+import { execFile } from 'child_process';
+import { writeFile, mkdir, rm } from 'fs/promises';
+import { join } from 'path';
+import { tmpdir } from 'os';
+
+// Write Go files to a fresh directory
+const write_go_directory = async (name, files) => {
+  const directory = join(tmpdir(), `codebuddy-cli-${name}-${Date.now()}`);
+  await mkdir(directory, { recursive: true });
+  for (const [filename, source] of Object.entries(files)) {
+    await writeFile(join(directory, filename), source);
+  }
+  return directory;
+};
+
+// Run bin/cb and resolve with its exit status
+const run_cb = (args) => {
+  return new Promise((resolve) => {
+    execFile('node', ['./bin/cb', ...args], (error) => {
+      resolve(error ? error.code : 0);
+    });
+  });
+};
+
+const undocumented_go = `package store
+
+func Open() error { return nil }
+`;
+
+// ============ --strict exit status tests ============
+
+await test('undocumented --strict exits non-zero on undocumented symbols', async (t) => {
+  const directory = await write_go_directory('undocumented', { 'store.go': undocumented_go });
+  t.assert.eq(await run_cb(['undocumented', directory, '--strict']), 1, 'Should fail with --strict');
+  t.assert.eq(await run_cb(['undocumented', directory]), 0, 'Should succeed without --strict');
+  await rm(directory, { recursive: true, force: true });
+});
+
+await test('doccov --strict exits non-zero when coverage regressed', async (t) => {
+  const directory = await write_go_directory('doccov', {
+    'store.go': '// Package store keeps values.\npackage store\n\n// Close closes the store.\nfunc Close() error { return nil }\n'
+  });
+  t.assert.eq(await run_cb(['doccov', directory, '--update']), 0, 'Should write the baseline');
+  await writeFile(join(directory, 'open.go'), undocumented_go);
+  t.assert.eq(await run_cb(['doccov', directory, '--strict']), 1, 'Should fail on a regression with --strict');
+  t.assert.eq(await run_cb(['doccov', directory]), 0, 'Should succeed without --strict');
+  await rm(directory, { recursive: true, force: true });
+});
+
+await test('surface --strict exits non-zero when the growth alerts', async (t) => {
+  const directory = await write_go_directory('surface', { 'store.go': undocumented_go });
+  t.assert.eq(await run_cb(['surface', directory, '--update']), 0, 'Should write the baseline');
+  await writeFile(
+    join(directory, 'more.go'),
+    'package store\n\nfunc Get(key string) string { return key }\n\nfunc Put(key, value string) {}\n'
+  );
+  t.assert.eq(await run_cb(['surface', directory, '--strict']), 1, 'Should fail on rapid growth with --strict');
+  t.assert.eq(await run_cb(['surface', directory]), 0, 'Should succeed without --strict');
+  t.assert.eq(await run_cb(['surface', directory, '--strict', '--max-growth=1000']), 0, 'Should succeed under the maximum');
+  await rm(directory, { recursive: true, force: true });
+});
+
+await test('doc-names --strict exits non-zero on misnamed doc comments', async (t) => {
+  const directory = await write_go_directory('doc-names', {
+    'store.go': 'package store\n\n// Opens the store.\nfunc Open() error { return nil }\n'
+  });
+  t.assert.eq(await run_cb(['doc-names', directory, '--strict']), 1, 'Should fail with --strict');
+  t.assert.eq(await run_cb(['doc-names', directory]), 0, 'Should succeed without --strict');
+  await rm(directory, { recursive: true, force: true });
+});
+
+await test('doubles --strict exits non-zero on interfaces without test doubles', async (t) => {
+  const directory = await write_go_directory('doubles', {
+    'store.go': 'package store\n\n// Store keeps values.\ntype Store interface {\n\tGet(key string) string\n}\n'
+  });
+  t.assert.eq(await run_cb(['doubles', directory, '--strict']), 1, 'Should fail with --strict');
+  await writeFile(
+    join(directory, 'store_test.go'),
+    'package store\n\ntype fakeStore struct{}\n\nfunc (fakeStore) Get(key string) string { return key }\n'
+  );
+  t.assert.eq(await run_cb(['doubles', directory, '--strict']), 0, 'Should succeed once a double exists');
+  await rm(directory, { recursive: true, force: true });
+});
+
+await test('doc-links --strict exits non-zero on unresolved doc links', async (t) => {
+  const directory = await write_go_directory('doc-links', {
+    'store.go': 'package store\n\n// Open opens a [Store].\nfunc Open() error { return nil }\n'
+  });
+  t.assert.eq(await run_cb(['doc-links', directory, '--strict']), 1, 'Should fail with --strict');
+  t.assert.eq(await run_cb(['doc-links', directory]), 0, 'Should succeed without --strict');
+  await rm(directory, { recursive: true, force: true });
+});
@@ -1,6 +1,9 @@
 'use strict';
 
-import { get_nodes_from_source } from '../../lib/functions.mjs';
+import {
+  get_nodes_from_source,
+  get_all_identifiers_from_source
+} from '../../lib/functions.mjs';
 import { import_file } from '../../lib/sourcecode.mjs';
 import { build_control_flow_from_source } from '../../lib/controlflow.mjs';
 
@@ -75,3 +78,18 @@ await test('Go: extracts control flow - type switch', async (t) => {
   const switchNodes = result.nodes.filter(n => n.type === 'switch');
   t.assert.ok(switchNodes.length > 0, 'Should find type switch nodes');
 });
+
+await test('Go: marks compound assignments and increments as writes', async (t) => {
+  const source = await import_file('./tests/fixtures/assignments.go');
+  const identifiers = get_all_identifiers_from_source(source, 'assignments.go');
+  const at = (symbol, line) => identifiers.find(i => i.symbol === symbol && i.line === line);
+
+  t.assert.ok(at('value', 13).is_write, 'c.value++ should write value');
+  t.assert.eq(at('value', 13).symbol_type, 'field', 'value should be a field');
+  t.assert.ok(!at('c', 13).is_write, 'c.value++ should not write c');
+  t.assert.ok(at('value', 18).is_write, 'c.value += n should write value');
+  t.assert.ok(!at('n', 18).is_write, 'The right-hand side should be a read');
+  t.assert.ok(at('counts', 21).is_write, 'c.counts["add"]++ should write counts');
+  t.assert.ok(at('copy', 32).is_definition, 'copy := c should define copy');
+  t.assert.ok(at('total', 40).is_write, 'total += values[i] should write total');
+});
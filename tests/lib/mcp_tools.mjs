@@ -49,6 +49,8 @@ await test('create_mcp_server creates server with all expected tools', async (t)
     'list_definitions',
     'symbol_reference_summary',
     'symbols_at_location',
+    'rename_impact',
+    'compare_symbol',
     // Class hierarchy tools
     'class_hierarchy',
     'interface_implementations',
@@ -62,6 +64,68 @@ await test('create_mcp_server creates server with all expected tools', async (t)
     // Pattern detection and test analysis tools
     'analysis_patterns',
     'analysis_tests',
+    // Go analysis tools
+    'analysis_struct_size',
+    'analysis_literals',
+    'analysis_channels',
+    'analysis_nesting',
+    'analysis_package_docs',
+    'analysis_symbol_graph',
+    'analysis_error_wrapping',
+    'analysis_panics',
+    'analysis_field_init',
+    'analysis_workspace_symbols',
+    'analysis_init_order',
+    'analysis_go_scripts',
+    'analysis_duplicate_methods',
+    'analysis_context_params',
+    'analysis_token_costs',
+    'analysis_stub',
+    'analysis_undocumented',
+    'analysis_doc_names',
+    'analysis_struct_kinds',
+    'analysis_near_misses',
+    'analysis_build_constraints',
+    'analysis_smell_score',
+    'analysis_method_shadowing',
+    'analysis_conversions',
+    'analysis_repro',
+    'analysis_concurrency_hints',
+    'analysis_embedders',
+    'analysis_escapes',
+    'analysis_imports',
+    'analysis_loop_captures',
+    'analysis_conversions_between',
+    'analysis_method_sets',
+    'analysis_interface_widths',
+    'analysis_struct_tags',
+    'analysis_coupling',
+    'analysis_unsafe_usage',
+    'analysis_call_chain',
+    'analysis_inline_hints',
+    'analysis_unused_interfaces',
+    'analysis_type_complexity',
+    'analysis_format_calls',
+    'analysis_copy_locks',
+    'analysis_field_types',
+    'analysis_method_implementers',
+    'analysis_deprecated_calls',
+    'analysis_interface_guideline',
+    'analysis_function_values',
+    'analysis_magic_numbers',
+    'analysis_error_results',
+    'analysis_options_structs',
+    'analysis_handlers_of_shape',
+    'analysis_recursive_structs',
+    'analysis_doc_links',
+    'analysis_builtin_shadowing',
+    'analysis_state_machines',
+    'analysis_package_metrics',
+    'analysis_param_names',
+    'analysis_state_aliasing',
+    'analysis_unused_interface_methods',
+    'analysis_predicate_names',
+    'analysis_risk_scores',
     // File analytics
     'file_analytics'
   ];